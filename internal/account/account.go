@@ -0,0 +1,236 @@
+// Package account selects Claude accounts for new sessions and tracks
+// per-account usage.
+//
+// Accounts are configured in mayor/accounts.json (see config.AccountsConfig).
+// A session's account is chosen by GT_ACCOUNT, then the --account flag, then
+// the rig's assignment, the role's assignment, and finally the default. With
+// rotation enabled, an assigned or default account that recently hit a rate
+// limit is skipped in favor of the next account in the rotation pool; an
+// account named explicitly (GT_ACCOUNT or --account) is never rotated away.
+//
+// Usage lives in <town>/.runtime/accounts/usage.json alongside other runtime
+// state: sessions started per account, the account each session name last
+// started on (session names are reused, so the map stays small), and
+// rate-limit cooldowns recorded by 'gt account check'.
+package account
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Selection sources, reported in Resolved.Source.
+const (
+	SourceEnv     = "GT_ACCOUNT"
+	SourceFlag    = "flag"
+	SourceRig     = "rig"
+	SourceRole    = "role"
+	SourceDefault = "default"
+)
+
+// APIKeyVar is the environment variable sessions read their API key from.
+const APIKeyVar = "ANTHROPIC_API_KEY"
+
+// Selection describes the session an account is being chosen for.
+type Selection struct {
+	Flag string // --account flag value, if any
+	Role string // agent role (e.g., "polecat")
+	Rig  string // rig name (empty for town-level agents)
+}
+
+// Resolved is the account chosen for a session.
+type Resolved struct {
+	Handle    string
+	ConfigDir string // expanded CLAUDE_CONFIG_DIR, if the account has one
+	APIKeyEnv string // variable or secret holding the API key, if any
+	Source    string // how the account was selected (SourceEnv, ...)
+
+	// RotatedFrom is the account that would have been used had it not been
+	// rate limited.
+	RotatedFrom string
+
+	// Limited reports that the chosen account is itself in a rate-limit
+	// cooldown (explicitly selected, or every pool account is limited).
+	Limited bool
+}
+
+// Env returns the startup environment for the account's API key. The key is
+// resolved by the session's shell when it starts (environment first, then
+// gt secret get), so it never appears in the command line. Returns nil if
+// the account has no API key.
+func (r *Resolved) Env() map[string]string {
+	if r == nil || r.APIKeyEnv == "" {
+		return nil
+	}
+	return map[string]string{
+		APIKeyVar: fmt.Sprintf(`"${%s:-$(gt secret get %s 2>/dev/null)}"`, r.APIKeyEnv, r.APIKeyEnv),
+	}
+}
+
+// ConfigDirOrEmpty returns the config dir, or "" for a nil Resolved.
+func (r *Resolved) ConfigDirOrEmpty() string {
+	if r == nil {
+		return ""
+	}
+	return r.ConfigDir
+}
+
+// Record counts a session started on the account and remembers which
+// account the session runs on, so rate limits seen in it can be attributed.
+// A nil Resolved records nothing.
+func (r *Resolved) Record(townRoot, sessionName string) error {
+	if r == nil {
+		return nil
+	}
+	return RecordSession(townRoot, r.Handle, sessionName)
+}
+
+// Resolve chooses the account for a session. Returns nil (and no error) if
+// no accounts are configured.
+func Resolve(townRoot string, sel Selection) (*Resolved, error) {
+	cfg, err := config.LoadAccountsConfig(constants.MayorAccountsPath(townRoot))
+	if err != nil || len(cfg.Accounts) == 0 {
+		// No accounts configured - sessions use the runtime's own login
+		return nil, nil
+	}
+	state, err := LoadState(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	return resolve(cfg, state, sel, os.Getenv("GT_ACCOUNT"), time.Now())
+}
+
+// ResolveResume chooses the account for restarting a session: handle if
+// given (e.g. recorded when a polecat hibernated), otherwise the account
+// sessionName last started on, otherwise as Resolve. A resumed conversation
+// is stored under its account's config dir, so it must restart there, on the
+// same credentials. An account last used by the session that has since been
+// removed from accounts.json falls back to Resolve; a given handle does not.
+func ResolveResume(townRoot string, sel Selection, sessionName, handle string) (*Resolved, error) {
+	if handle != "" {
+		sel.Flag = handle
+		return Resolve(townRoot, sel)
+	}
+	if state, err := LoadState(townRoot); err == nil && state.Sessions[sessionName] != "" {
+		last := sel
+		last.Flag = state.Sessions[sessionName]
+		if r, err := Resolve(townRoot, last); err == nil {
+			return r, nil
+		}
+	}
+	return Resolve(townRoot, sel)
+}
+
+// SessionAccount returns the account sessionName last started on, or "" if
+// none is recorded.
+func SessionAccount(townRoot, sessionName string) string {
+	state, err := LoadState(townRoot)
+	if err != nil {
+		return ""
+	}
+	return state.Sessions[sessionName]
+}
+
+// resolve implements Resolve against loaded config and usage state.
+func resolve(cfg *config.AccountsConfig, state *State, sel Selection, envAccount string, now time.Time) (*Resolved, error) {
+	handle, source := "", ""
+	switch {
+	case envAccount != "":
+		handle, source = envAccount, SourceEnv
+	case sel.Flag != "":
+		handle, source = sel.Flag, SourceFlag
+	case sel.Rig != "" && cfg.Rigs[sel.Rig] != "":
+		handle, source = cfg.Rigs[sel.Rig], SourceRig
+	case sel.Role != "" && cfg.Roles[sel.Role] != "":
+		handle, source = cfg.Roles[sel.Role], SourceRole
+	case cfg.Default != "":
+		handle, source = cfg.Default, SourceDefault
+	default:
+		return nil, nil
+	}
+	if cfg.GetAccount(handle) == nil {
+		if source == SourceEnv {
+			return nil, fmt.Errorf("GT_ACCOUNT '%s' not found in accounts config", handle)
+		}
+		return nil, fmt.Errorf("account '%s' not found in accounts config", handle)
+	}
+
+	chosen := handle
+	rotatedFrom := ""
+	explicit := source == SourceEnv || source == SourceFlag
+	if state.Limited(handle, now) && !explicit && cfg.Rotation != nil && cfg.Rotation.Enabled {
+		if next := nextAvailable(rotationPool(cfg), handle, state, now); next != "" {
+			chosen, rotatedFrom = next, handle
+		}
+	}
+
+	acct := cfg.GetAccount(chosen)
+	r := &Resolved{
+		Handle:      chosen,
+		APIKeyEnv:   acct.APIKeyEnv,
+		Source:      source,
+		RotatedFrom: rotatedFrom,
+		Limited:     state.Limited(chosen, now),
+	}
+	if acct.ConfigDir != "" {
+		r.ConfigDir = acct.ConfigDirPath()
+	}
+	return r, nil
+}
+
+// rotationPool returns the handles to rotate through.
+func rotationPool(cfg *config.AccountsConfig) []string {
+	if len(cfg.Rotation.Pool) > 0 {
+		return cfg.Rotation.Pool
+	}
+	pool := make([]string, 0, len(cfg.Accounts))
+	for h := range cfg.Accounts {
+		pool = append(pool, h)
+	}
+	sort.Strings(pool)
+	return pool
+}
+
+// nextAvailable returns the first account after current in pool order
+// (wrapping around) that is not rate limited, or "" if there is none.
+func nextAvailable(pool []string, current string, state *State, now time.Time) string {
+	start := 0
+	for i, h := range pool {
+		if h == current {
+			start = i + 1
+			break
+		}
+	}
+	for i := 0; i < len(pool); i++ {
+		h := pool[(start+i)%len(pool)]
+		if h != current && !state.Limited(h, now) {
+			return h
+		}
+	}
+	return ""
+}
+
+// PrepareSession resolves the account for a session, prepends its
+// environment (CLAUDE_CONFIG_DIR and API key) to the startup command, and
+// records the session against the account. The command is returned
+// unchanged if no accounts are configured.
+func PrepareSession(townRoot string, sel Selection, sessionName, command string) (string, error) {
+	r, err := Resolve(townRoot, sel)
+	if err != nil || r == nil {
+		return command, err
+	}
+	env := r.Env()
+	if r.ConfigDir != "" {
+		if env == nil {
+			env = make(map[string]string)
+		}
+		env["CLAUDE_CONFIG_DIR"] = r.ConfigDir
+	}
+	_ = r.Record(townRoot, sessionName) // usage tracking is best-effort
+	return config.PrependEnv(command, env), nil
+}
@@ -0,0 +1,237 @@
+package account
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+func testAccounts() *config.AccountsConfig {
+	return &config.AccountsConfig{
+		Version: config.CurrentAccountsVersion,
+		Accounts: map[string]config.Account{
+			"personal": {ConfigDir: "/accounts/personal"},
+			"work":     {ConfigDir: "/accounts/work"},
+			"team":     {APIKeyEnv: "TEAM_API_KEY"},
+		},
+		Default: "personal",
+		Roles:   map[string]string{constants.RolePolecat: "work"},
+		Rigs:    map[string]string{"docs": "team"},
+	}
+}
+
+func TestResolve_Precedence(t *testing.T) {
+	cfg := testAccounts()
+	state := &State{Accounts: map[string]*Usage{}, Sessions: map[string]string{}}
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		sel        Selection
+		env        string
+		wantHandle string
+		wantSource string
+	}{
+		{"default", Selection{Role: constants.RoleMayor}, "", "personal", SourceDefault},
+		{"role", Selection{Role: constants.RolePolecat, Rig: "gastown"}, "", "work", SourceRole},
+		{"rig over role", Selection{Role: constants.RolePolecat, Rig: "docs"}, "", "team", SourceRig},
+		{"flag over rig", Selection{Flag: "personal", Rig: "docs"}, "", "personal", SourceFlag},
+		{"env over flag", Selection{Flag: "personal"}, "work", "work", SourceEnv},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := resolve(cfg, state, tt.sel, tt.env, now)
+			if err != nil {
+				t.Fatalf("resolve: %v", err)
+			}
+			if r.Handle != tt.wantHandle || r.Source != tt.wantSource {
+				t.Errorf("resolve = %s (%s), want %s (%s)", r.Handle, r.Source, tt.wantHandle, tt.wantSource)
+			}
+		})
+	}
+
+	if _, err := resolve(cfg, state, Selection{Flag: "nope"}, "", now); err == nil {
+		t.Error("expected error for unknown --account")
+	}
+}
+
+func TestResolve_Rotation(t *testing.T) {
+	cfg := testAccounts()
+	cfg.Rotation = &config.AccountRotationConfig{Enabled: true, Pool: []string{"work", "team", "personal"}}
+	now := time.Now()
+	state := &State{
+		Accounts: map[string]*Usage{"work": {LimitedUntil: now.Add(time.Hour)}},
+		Sessions: map[string]string{},
+	}
+
+	r, err := resolve(cfg, state, Selection{Role: constants.RolePolecat}, "", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Handle != "team" || r.RotatedFrom != "work" || r.Limited {
+		t.Errorf("resolve = %+v, want rotation from work to team", r)
+	}
+
+	// Explicit selection is never rotated away
+	r, _ = resolve(cfg, state, Selection{Flag: "work"}, "", now)
+	if r.Handle != "work" || !r.Limited {
+		t.Errorf("explicit resolve = %+v, want limited work", r)
+	}
+
+	// Every pool account limited: keep the assigned one
+	state.Accounts["team"] = &Usage{LimitedUntil: now.Add(time.Hour)}
+	state.Accounts["personal"] = &Usage{LimitedUntil: now.Add(time.Hour)}
+	r, _ = resolve(cfg, state, Selection{Role: constants.RolePolecat}, "", now)
+	if r.Handle != "work" || r.RotatedFrom != "" || !r.Limited {
+		t.Errorf("all-limited resolve = %+v, want limited work", r)
+	}
+
+	// Cooldown over: back on the assigned account
+	r, _ = resolve(cfg, state, Selection{Role: constants.RolePolecat}, "", now.Add(2*time.Hour))
+	if r.Handle != "work" || r.Limited {
+		t.Errorf("post-cooldown resolve = %+v, want work", r)
+	}
+}
+
+func TestResolvedEnv(t *testing.T) {
+	r := &Resolved{Handle: "team", APIKeyEnv: "TEAM_API_KEY"}
+	v := r.Env()[APIKeyVar]
+	if !strings.Contains(v, "$TEAM_API_KEY") && !strings.Contains(v, "${TEAM_API_KEY") {
+		t.Errorf("Env = %q, want reference to TEAM_API_KEY", v)
+	}
+	if !strings.Contains(v, "gt secret get TEAM_API_KEY") {
+		t.Errorf("Env = %q, want keyring fallback", v)
+	}
+	if (&Resolved{Handle: "work"}).Env() != nil || (*Resolved)(nil).Env() != nil {
+		t.Error("Env without API key should be nil")
+	}
+}
+
+func TestUsageState(t *testing.T) {
+	townRoot := t.TempDir()
+
+	if err := RecordSession(townRoot, "work", "gt-gastown-p-nux"); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordSession(townRoot, "work", "gt-gastown-p-toast"); err != nil {
+		t.Fatal(err)
+	}
+
+	until := time.Now().Add(time.Hour)
+	if marked, err := MarkLimited(townRoot, "work", until); err != nil || !marked {
+		t.Fatalf("MarkLimited = %v, %v; want newly marked", marked, err)
+	}
+	if marked, _ := MarkLimited(townRoot, "work", until.Add(time.Hour)); marked {
+		t.Error("second MarkLimited during cooldown should not count")
+	}
+
+	s, err := LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := s.Accounts["work"]
+	if u.Sessions != 2 || u.RateLimits != 1 || !s.Limited("work", time.Now()) {
+		t.Errorf("usage = %+v, want 2 sessions, 1 rate limit, limited", u)
+	}
+	if s.Sessions["gt-gastown-p-nux"] != "work" {
+		t.Errorf("Sessions = %v, want nux mapped to work", s.Sessions)
+	}
+
+	if err := ClearLimit(townRoot, "work"); err != nil {
+		t.Fatal(err)
+	}
+	s, _ = LoadState(townRoot)
+	if s.Limited("work", time.Now()) {
+		t.Error("still limited after ClearLimit")
+	}
+}
+
+func TestPrepareSession(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Setenv("GT_ACCOUNT", "")
+
+	// No accounts: command unchanged
+	cmd, err := PrepareSession(townRoot, Selection{Role: constants.RoleMayor}, "hq-mayor", "claude")
+	if err != nil || cmd != "claude" {
+		t.Fatalf("PrepareSession without accounts = %q, %v", cmd, err)
+	}
+
+	cfg := testAccounts()
+	if err := config.SaveAccountsConfig(constants.MayorAccountsPath(townRoot), cfg); err != nil {
+		t.Fatal(err)
+	}
+	cmd, err = PrepareSession(townRoot, Selection{Role: constants.RoleMayor}, "hq-mayor", "claude")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd != "export CLAUDE_CONFIG_DIR=/accounts/personal && claude" {
+		t.Errorf("PrepareSession = %q", cmd)
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, ".runtime", "accounts", "usage.json")); err != nil {
+		t.Errorf("session not recorded: %v", err)
+	}
+}
+
+func TestResolveResume(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Setenv("GT_ACCOUNT", "")
+	if err := config.SaveAccountsConfig(constants.MayorAccountsPath(townRoot), testAccounts()); err != nil {
+		t.Fatal(err)
+	}
+	sel := Selection{Role: constants.RolePolecat, Rig: "gastown"}
+
+	// A new session gets the role's account
+	if r, err := ResolveResume(townRoot, sel, "gt-gastown-Toast", ""); err != nil || r.Handle != "work" {
+		t.Fatalf("ResolveResume without history = %+v, %v; want work", r, err)
+	}
+
+	// A session restarts on the account it last ran on
+	if err := RecordSession(townRoot, "personal", "gt-gastown-Toast"); err != nil {
+		t.Fatal(err)
+	}
+	if got := SessionAccount(townRoot, "gt-gastown-Toast"); got != "personal" {
+		t.Errorf("SessionAccount = %q, want personal", got)
+	}
+	if r, err := ResolveResume(townRoot, sel, "gt-gastown-Toast", ""); err != nil || r.Handle != "personal" || r.ConfigDir != "/accounts/personal" {
+		t.Errorf("ResolveResume = %+v, %v; want personal", r, err)
+	}
+
+	// A recorded handle wins, and must still exist
+	if r, err := ResolveResume(townRoot, sel, "gt-gastown-Toast", "team"); err != nil || r.Handle != "team" {
+		t.Errorf("ResolveResume(team) = %+v, %v", r, err)
+	}
+	if _, err := ResolveResume(townRoot, sel, "gt-gastown-Toast", "gone"); err == nil {
+		t.Error("ResolveResume(gone) = nil error, want account not found")
+	}
+
+	// A removed account last used by the session falls back
+	if err := RecordSession(townRoot, "gone", "gt-gastown-Nux"); err != nil {
+		t.Fatal(err)
+	}
+	if r, err := ResolveResume(townRoot, sel, "gt-gastown-Nux", ""); err != nil || r.Handle != "work" {
+		t.Errorf("ResolveResume after removal = %+v, %v; want work", r, err)
+	}
+}
+
+func TestDetectRateLimit(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"Claude AI usage limit reached|1760000000", true},
+		{"5-hour limit reached ∙ resets 3pm", true},
+		{"API Error: 429 {\"type\":\"error\",\"error\":{\"type\":\"rate_limit_error\"}}", true},
+		{"> implementing the rate limiter for the API", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := DetectRateLimit(tt.content); got != tt.want {
+			t.Errorf("DetectRateLimit(%q) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package account
+
+import "strings"
+
+// rateLimitMarkers are substrings (lowercased) that runtimes print when the
+// account has hit a usage or rate limit.
+var rateLimitMarkers = []string{
+	"usage limit reached",
+	"limit reached ∙ resets",
+	"limit reached · resets",
+	"rate_limit_error",
+	"api error: 429",
+	"rate limit exceeded",
+}
+
+// DetectRateLimit reports whether pane content shows a rate-limit message.
+// Callers should pass only the visible tail of the pane so a limit that has
+// since cleared is not detected again from scrollback.
+func DetectRateLimit(content string) bool {
+	lower := strings.ToLower(content)
+	for _, m := range rateLimitMarkers {
+		if strings.Contains(lower, m) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// stateLockTimeout bounds how long a usage update waits for another writer.
+const stateLockTimeout = 10 * time.Second
+
+// Usage is one account's tracked usage.
+type Usage struct {
+	Sessions      int       `json:"sessions"`                  // sessions started on the account
+	LastUsed      time.Time `json:"last_used,omitempty"`       // last session start
+	RateLimits    int       `json:"rate_limits"`               // rate limits detected
+	LastRateLimit time.Time `json:"last_rate_limit,omitempty"` // most recent rate limit
+	LimitedUntil  time.Time `json:"limited_until,omitempty"`   // end of the current cooldown
+}
+
+// Limited reports whether the account is in a rate-limit cooldown at now.
+func (u *Usage) Limited(now time.Time) bool {
+	return u != nil && now.Before(u.LimitedUntil)
+}
+
+// State is the town's account usage state.
+type State struct {
+	Accounts map[string]*Usage `json:"accounts"`
+	Sessions map[string]string `json:"sessions"` // tmux session -> account handle
+}
+
+// Limited reports whether handle is in a rate-limit cooldown at now.
+func (s *State) Limited(handle string, now time.Time) bool {
+	return s.Accounts[handle].Limited(now)
+}
+
+// usage returns handle's usage, creating it if needed.
+func (s *State) usage(handle string) *Usage {
+	u := s.Accounts[handle]
+	if u == nil {
+		u = &Usage{}
+		s.Accounts[handle] = u
+	}
+	return u
+}
+
+// StatePath returns the usage state file for a town.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "accounts", "usage.json")
+}
+
+// LoadState reads the town's usage state. A missing file is empty state.
+func LoadState(townRoot string) (*State, error) {
+	s := &State{Accounts: make(map[string]*Usage), Sessions: make(map[string]string)}
+	data, err := os.ReadFile(StatePath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parsing account usage: %w", err)
+	}
+	if s.Accounts == nil {
+		s.Accounts = make(map[string]*Usage)
+	}
+	if s.Sessions == nil {
+		s.Sessions = make(map[string]string)
+	}
+	return s, nil
+}
+
+// update applies fn to the usage state under a file lock, so concurrent
+// session starts and checks do not lose each other's updates.
+func update(townRoot string, fn func(*State)) error {
+	path := StatePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating account state dir: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	ctx, cancel := context.WithTimeout(context.Background(), stateLockTimeout)
+	defer cancel()
+	if locked, err := lock.TryLockContext(ctx, 50*time.Millisecond); err != nil || !locked {
+		return fmt.Errorf("locking account usage: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	s, err := LoadState(townRoot)
+	if err != nil {
+		return err
+	}
+	fn(s)
+	return util.AtomicWriteJSON(path, s)
+}
+
+// RecordSession counts a session start on handle and maps the session to it.
+func RecordSession(townRoot, handle, sessionName string) error {
+	return update(townRoot, func(s *State) {
+		u := s.usage(handle)
+		u.Sessions++
+		u.LastUsed = time.Now().UTC()
+		if sessionName != "" {
+			s.Sessions[sessionName] = handle
+		}
+	})
+}
+
+// MarkLimited puts handle in a rate-limit cooldown until until. A limit seen
+// while the account is already cooling down is not counted again. Returns
+// true if the account was newly limited.
+func MarkLimited(townRoot, handle string, until time.Time) (bool, error) {
+	marked := false
+	err := update(townRoot, func(s *State) {
+		now := time.Now().UTC()
+		u := s.usage(handle)
+		if u.Limited(now) {
+			return
+		}
+		u.RateLimits++
+		u.LastRateLimit = now
+		u.LimitedUntil = until.UTC()
+		marked = true
+	})
+	return marked, err
+}
+
+// ClearLimit ends handle's rate-limit cooldown.
+func ClearLimit(townRoot, handle string) error {
+	return update(townRoot, func(s *State) {
+		if u := s.Accounts[handle]; u != nil {
+			u.LimitedUntil = time.Time{}
+		}
+	})
+}
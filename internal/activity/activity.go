@@ -19,6 +19,15 @@ const (
 	ThresholdStale  = 5 * time.Minute  // Yellow threshold (beyond this is red)
 )
 
+// Thresholds are the cutoffs between active, stale, and stuck.
+type Thresholds struct {
+	Active time.Duration // Green below this
+	Stale  time.Duration // Yellow below this, red beyond
+}
+
+// DefaultThresholds are the thresholds used by Calculate.
+var DefaultThresholds = Thresholds{Active: ThresholdActive, Stale: ThresholdStale}
+
 // Info holds activity information for display.
 type Info struct {
 	LastActivity time.Time // Raw timestamp of last activity
@@ -34,6 +43,18 @@ type Info struct {
 //   - Red:     >5 minutes (stuck)
 //   - Unknown: zero time value
 func Calculate(lastActivity time.Time) Info {
+	return CalculateWith(lastActivity, DefaultThresholds)
+}
+
+// CalculateWith is like Calculate with custom thresholds (e.g., a rig's
+// [activity] settings in gastown.toml). Zero thresholds use the defaults.
+func CalculateWith(lastActivity time.Time, t Thresholds) Info {
+	if t.Active <= 0 {
+		t.Active = ThresholdActive
+	}
+	if t.Stale <= 0 {
+		t.Stale = ThresholdStale
+	}
 	info := Info{
 		LastActivity: lastActivity,
 	}
@@ -57,7 +78,7 @@ func Calculate(lastActivity time.Time) Info {
 	info.FormattedAge = formatAge(info.Duration)
 
 	// Determine color class
-	info.ColorClass = colorForDuration(info.Duration)
+	info.ColorClass = colorForDuration(info.Duration, t)
 
 	return info
 }
@@ -110,11 +131,11 @@ func formatInt(n int) string {
 }
 
 // colorForDuration returns the color class for a given duration.
-func colorForDuration(d time.Duration) string {
+func colorForDuration(d time.Duration, t Thresholds) string {
 	switch {
-	case d < ThresholdActive:
+	case d < t.Active:
 		return ColorGreen
-	case d < ThresholdStale:
+	case d < t.Stale:
 		return ColorYellow
 	default:
 		return ColorRed
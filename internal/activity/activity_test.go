@@ -176,3 +176,25 @@ func TestInfo_IsStuck(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateWith(t *testing.T) {
+	th := Thresholds{Active: 10 * time.Minute, Stale: 30 * time.Minute}
+	tests := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{5 * time.Minute, ColorGreen},
+		{20 * time.Minute, ColorYellow},
+		{45 * time.Minute, ColorRed},
+	}
+	for _, tt := range tests {
+		if got := CalculateWith(time.Now().Add(-tt.ago), th).ColorClass; got != tt.want {
+			t.Errorf("CalculateWith(%v ago) = %q, want %q", tt.ago, got, tt.want)
+		}
+	}
+
+	// Zero thresholds fall back to the defaults
+	if got := CalculateWith(time.Now().Add(-3*time.Minute), Thresholds{}).ColorClass; got != ColorYellow {
+		t.Errorf("CalculateWith with zero thresholds = %q, want %q", got, ColorYellow)
+	}
+}
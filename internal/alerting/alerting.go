@@ -0,0 +1,215 @@
+// Package alerting opens incidents in an on-call service (PagerDuty,
+// Opsgenie) while a critical condition holds in the town, and resolves
+// them when it clears.
+//
+// gt incidents sync evaluates the town's conditions on every daemon
+// heartbeat and passes those that hold to Reconcile, which triggers an
+// incident for each new one and resolves each that no longer holds. Every
+// incident has a deduplication key (see Key) naming its condition and
+// subject, so a condition pages once however long it lasts, and the
+// incidents open in each service are kept in .runtime/alerting/ to know
+// what to resolve.
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Incident is a condition that holds, as an incident.
+type Incident struct {
+	Key       string            `json:"key"`       // deduplication key (see Key)
+	Condition string            `json:"condition"` // see config.AlertConditions
+	Subject   string            `json:"subject,omitempty"`
+	Severity  string            `json:"severity"` // config.Severity*
+	Summary   string            `json:"summary"`
+	Details   map[string]string `json:"details,omitempty"`
+}
+
+// Key returns the deduplication key for a condition in a town, about
+// subject (a rig, a remote town; "" for the town itself).
+func Key(town, condition, subject string) string {
+	key := "gastown:" + town + ":" + condition
+	if subject != "" {
+		key += ":" + subject
+	}
+	return key
+}
+
+// Sink is an on-call service incidents are sent to.
+type Sink interface {
+	Name() string
+	Trigger(ctx context.Context, i Incident) error
+	Resolve(ctx context.Context, key string) error
+}
+
+// httpClient returns hc, or a client with a 30s timeout if it's nil.
+func httpClient(hc *http.Client) *http.Client {
+	if hc == nil {
+		return &http.Client{Timeout: 30 * time.Second}
+	}
+	return hc
+}
+
+// post sends body as JSON to endpoint with headers, and fails unless the
+// service accepts it.
+func post(ctx context.Context, hc *http.Client, endpoint string, headers map[string]string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := httpClient(hc).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// PagerDutyEventsURL is PagerDuty's Events API v2.
+const PagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDuty sends incidents to a PagerDuty service's Events API v2
+// integration. The deduplication key is PagerDuty's dedup_key.
+type PagerDuty struct {
+	RoutingKey string
+	Source     string // the town, shown as the incident's source
+	URL        string // PagerDutyEventsURL if empty
+	HTTP       *http.Client
+}
+
+// Name implements Sink.
+func (p *PagerDuty) Name() string { return "pagerduty" }
+
+// pagerDutySeverity maps a severity to PagerDuty's.
+func pagerDutySeverity(severity string) string {
+	switch severity {
+	case config.SeverityCritical:
+		return "critical"
+	case config.SeverityHigh:
+		return "error"
+	case config.SeverityMedium:
+		return "warning"
+	}
+	return "info"
+}
+
+// send posts an event to the Events API.
+func (p *PagerDuty) send(ctx context.Context, event map[string]interface{}) error {
+	endpoint := p.URL
+	if endpoint == "" {
+		endpoint = PagerDutyEventsURL
+	}
+	event["routing_key"] = p.RoutingKey
+	if err := post(ctx, p.HTTP, endpoint, nil, event); err != nil {
+		return fmt.Errorf("pagerduty %s: %w", event["event_action"], err)
+	}
+	return nil
+}
+
+// Trigger implements Sink.
+func (p *PagerDuty) Trigger(ctx context.Context, i Incident) error {
+	return p.send(ctx, map[string]interface{}{
+		"event_action": "trigger",
+		"dedup_key":    i.Key,
+		"payload": map[string]interface{}{
+			"summary":        i.Summary,
+			"source":         p.Source,
+			"severity":       pagerDutySeverity(i.Severity),
+			"component":      i.Subject,
+			"class":          i.Condition,
+			"custom_details": i.Details,
+		},
+	})
+}
+
+// Resolve implements Sink.
+func (p *PagerDuty) Resolve(ctx context.Context, key string) error {
+	return p.send(ctx, map[string]interface{}{"event_action": "resolve", "dedup_key": key})
+}
+
+// OpsgenieURL is Opsgenie's API (EU accounts use
+// https://api.eu.opsgenie.com).
+const OpsgenieURL = "https://api.opsgenie.com"
+
+// Opsgenie sends incidents to Opsgenie as alerts. The deduplication key is
+// the alert's alias.
+type Opsgenie struct {
+	APIKey string
+	Source string // the town, shown as the alert's source
+	URL    string // OpsgenieURL if empty
+	HTTP   *http.Client
+}
+
+// Name implements Sink.
+func (o *Opsgenie) Name() string { return "opsgenie" }
+
+// opsgeniePriority maps a severity to an Opsgenie priority.
+func opsgeniePriority(severity string) string {
+	switch severity {
+	case config.SeverityCritical:
+		return "P1"
+	case config.SeverityHigh:
+		return "P2"
+	case config.SeverityMedium:
+		return "P3"
+	}
+	return "P4"
+}
+
+// send posts to an alerts endpoint of the API.
+func (o *Opsgenie) send(ctx context.Context, path string, body interface{}) error {
+	base := o.URL
+	if base == "" {
+		base = OpsgenieURL
+	}
+	headers := map[string]string{"Authorization": "GenieKey " + o.APIKey}
+	if err := post(ctx, o.HTTP, strings.TrimSuffix(base, "/")+path, headers, body); err != nil {
+		return fmt.Errorf("opsgenie: %w", err)
+	}
+	return nil
+}
+
+// Trigger implements Sink.
+func (o *Opsgenie) Trigger(ctx context.Context, i Incident) error {
+	message := i.Summary
+	if r := []rune(message); len(r) > 130 { // Opsgenie's limit
+		message = string(r[:129]) + "…"
+	}
+	return o.send(ctx, "/v2/alerts", map[string]interface{}{
+		"message":     message,
+		"alias":       i.Key,
+		"description": i.Summary,
+		"priority":    opsgeniePriority(i.Severity),
+		"source":      o.Source,
+		"tags":        []string{"gastown", i.Condition},
+		"details":     i.Details,
+	})
+}
+
+// Resolve implements Sink.
+func (o *Opsgenie) Resolve(ctx context.Context, key string) error {
+	return o.send(ctx, "/v2/alerts/"+url.PathEscape(key)+"/close?identifierType=alias",
+		map[string]string{"source": o.Source})
+}
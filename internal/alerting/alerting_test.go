@@ -0,0 +1,174 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// fakeSink records what it's sent, and fails while err is set.
+type fakeSink struct {
+	name      string
+	err       error
+	triggered []string
+	resolved  []string
+}
+
+func (f *fakeSink) Name() string { return f.name }
+
+func (f *fakeSink) Trigger(_ context.Context, i Incident) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.triggered = append(f.triggered, i.Key)
+	return nil
+}
+
+func (f *fakeSink) Resolve(_ context.Context, key string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.resolved = append(f.resolved, key)
+	return nil
+}
+
+func TestReconcile(t *testing.T) {
+	townRoot := t.TempDir()
+	ctx := context.Background()
+	now := time.Now()
+	pd := &fakeSink{name: "pagerduty"}
+	og := &fakeSink{name: "opsgenie"}
+	routes := []Route{{Sink: pd}, {Sink: og, MinSeverity: config.SeverityCritical}}
+
+	deacon := Incident{Key: Key("hq", "deacon_down", ""), Condition: "deacon_down", Severity: config.SeverityCritical, Summary: "deacon down"}
+	wedged := Incident{Key: Key("hq", "merge_queue_wedged", "gastown"), Condition: "merge_queue_wedged", Subject: "gastown", Severity: config.SeverityHigh}
+
+	// Both fire: PagerDuty takes both, Opsgenie only the critical one
+	triggered, resolved, err := Reconcile(ctx, townRoot, []Incident{deacon, wedged}, routes, now)
+	if err != nil || triggered != 3 || resolved != 0 {
+		t.Fatalf("first Reconcile = %d, %d, %v; want 3, 0, nil", triggered, resolved, err)
+	}
+	if len(og.triggered) != 1 || og.triggered[0] != deacon.Key {
+		t.Errorf("opsgenie triggered %v, want only %s", og.triggered, deacon.Key)
+	}
+
+	// Still firing: nothing new is sent
+	triggered, _, _ = Reconcile(ctx, townRoot, []Incident{deacon, wedged}, routes, now)
+	if triggered != 0 {
+		t.Errorf("re-triggered %d incidents that were already open", triggered)
+	}
+
+	// The deacon recovers, but Opsgenie is down: PagerDuty resolves it, and
+	// Opsgenie's is retried
+	og.err = errors.New("503")
+	_, resolved, err = Reconcile(ctx, townRoot, []Incident{wedged}, routes, now)
+	if err == nil || resolved != 0 {
+		t.Errorf("Reconcile with a failing sink = %d resolved, %v; want 0 and an error", resolved, err)
+	}
+	og.err = nil
+	_, resolved, err = Reconcile(ctx, townRoot, []Incident{wedged}, routes, now)
+	if err != nil || resolved != 1 {
+		t.Errorf("retried Reconcile = %d resolved, %v; want 1, nil", resolved, err)
+	}
+	if len(og.resolved) != 1 || len(pd.resolved) != 1 {
+		t.Errorf("resolved pagerduty %v, opsgenie %v; want the deacon incident once in each", pd.resolved, og.resolved)
+	}
+
+	open, err := Load(townRoot)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(open) != 1 || open[wedged.Key] == nil {
+		t.Fatalf("open = %v, want only %s", open, wedged.Key)
+	}
+
+	// PagerDuty is unconfigured: its incident can't be resolved, so it's
+	// forgotten once it clears
+	if _, resolved, err = Reconcile(ctx, townRoot, nil, routes[1:], now); err != nil || resolved != 1 {
+		t.Errorf("Reconcile without the sink = %d resolved, %v; want 1, nil", resolved, err)
+	}
+	if open, _ := Load(townRoot); len(open) != 0 {
+		t.Errorf("open = %v, want none", open)
+	}
+}
+
+func TestPagerDuty(t *testing.T) {
+	var got []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		got = append(got, body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	p := &PagerDuty{RoutingKey: "R1", Source: "hq", URL: srv.URL}
+	inc := Incident{Key: "gastown:hq:deacon_down", Condition: "deacon_down", Severity: config.SeverityHigh, Summary: "deacon down"}
+	if err := p.Trigger(context.Background(), inc); err != nil {
+		t.Fatalf("Trigger: %v", err)
+	}
+	if err := p.Resolve(context.Background(), inc.Key); err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d events, want 2", len(got))
+	}
+	payload, _ := got[0]["payload"].(map[string]interface{})
+	if got[0]["event_action"] != "trigger" || got[0]["dedup_key"] != inc.Key || got[0]["routing_key"] != "R1" ||
+		payload["severity"] != "error" || payload["source"] != "hq" {
+		t.Errorf("trigger = %v", got[0])
+	}
+	if got[1]["event_action"] != "resolve" || got[1]["dedup_key"] != inc.Key {
+		t.Errorf("resolve = %v", got[1])
+	}
+}
+
+func TestOpsgenie(t *testing.T) {
+	var paths []string
+	var alert map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "GenieKey K1" {
+			t.Errorf("Authorization = %q", auth)
+		}
+		paths = append(paths, r.URL.RequestURI())
+		if alert == nil {
+			_ = json.NewDecoder(r.Body).Decode(&alert)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	o := &Opsgenie{APIKey: "K1", Source: "hq", URL: srv.URL}
+	inc := Incident{Key: "gastown:hq:budget_exceeded:gastown", Condition: "budget_exceeded", Severity: config.SeverityCritical, Summary: "over budget"}
+	if err := o.Trigger(context.Background(), inc); err != nil {
+		t.Fatalf("Trigger: %v", err)
+	}
+	if err := o.Resolve(context.Background(), inc.Key); err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if alert["alias"] != inc.Key || alert["priority"] != "P1" {
+		t.Errorf("alert = %v", alert)
+	}
+	want := []string{"/v2/alerts", "/v2/alerts/gastown:hq:budget_exceeded:gastown/close?identifierType=alias"}
+	if len(paths) != 2 || paths[0] != want[0] || paths[1] != want[1] {
+		t.Errorf("requests = %v, want %v", paths, want)
+	}
+}
+
+func TestSinkError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Invalid routing key"}`, http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	err := (&PagerDuty{URL: srv.URL}).Trigger(context.Background(), Incident{Key: "k"})
+	if err == nil {
+		t.Fatal("Trigger succeeded against a failing service")
+	}
+}
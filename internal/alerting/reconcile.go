@@ -0,0 +1,146 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Route sends incidents of MinSeverity or worse to a sink.
+type Route struct {
+	Sink        Sink
+	MinSeverity string // "" = every severity
+}
+
+// Open is an incident that's open in one or more sinks.
+type Open struct {
+	Incident
+	Sinks    []string  `json:"sinks"` // names of the sinks it's open in
+	OpenedAt time.Time `json:"opened_at"`
+}
+
+// statePath returns where a town's open incidents are kept.
+func statePath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "alerting", "open.json")
+}
+
+// Load returns the town's open incidents, by key.
+func Load(townRoot string) (map[string]*Open, error) {
+	open := make(map[string]*Open)
+	data, err := os.ReadFile(statePath(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return open, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &open); err != nil {
+		return nil, fmt.Errorf("parsing open incidents: %w", err)
+	}
+	return open, nil
+}
+
+// save stores the town's open incidents.
+func save(townRoot string, open map[string]*Open) error {
+	if err := os.MkdirAll(filepath.Dir(statePath(townRoot)), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(statePath(townRoot), open)
+}
+
+// Reconcile brings the sinks in line with the incidents that hold now
+// (firing): each is triggered in every route it's severe enough for and
+// isn't yet open in, and each open incident no longer firing is resolved.
+// It returns how many incidents it triggered and resolved. A trigger or
+// resolve that fails is retried by the next Reconcile. An incident open in
+// a sink that's no longer configured is forgotten, since it can't be
+// resolved from here.
+func Reconcile(ctx context.Context, townRoot string, firing []Incident, routes []Route, now time.Time) (triggered, resolved int, err error) {
+	open, err := Load(townRoot)
+	if err != nil {
+		return 0, 0, err
+	}
+	sinks := make(map[string]Sink)
+	for _, r := range routes {
+		sinks[r.Sink.Name()] = r.Sink
+	}
+	var errs []error
+
+	holding := make(map[string]bool)
+	for _, inc := range firing {
+		holding[inc.Key] = true
+		o := open[inc.Key]
+		if o == nil {
+			o = &Open{OpenedAt: now}
+		}
+		o.Incident = inc
+		for _, r := range routes {
+			name := r.Sink.Name()
+			if !config.SeverityAtLeast(inc.Severity, r.MinSeverity) || containsName(o.Sinks, name) {
+				continue
+			}
+			if err := r.Sink.Trigger(ctx, inc); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			o.Sinks = append(o.Sinks, name)
+			triggered++
+		}
+		if len(o.Sinks) > 0 {
+			open[inc.Key] = o
+		}
+	}
+
+	keys := make([]string, 0, len(open))
+	for key := range open {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if holding[key] {
+			continue
+		}
+		o := open[key]
+		var still []string
+		for _, name := range o.Sinks {
+			sink := sinks[name]
+			if sink == nil {
+				continue
+			}
+			if err := sink.Resolve(ctx, key); err != nil {
+				errs = append(errs, err)
+				still = append(still, name)
+			}
+		}
+		if len(still) > 0 {
+			o.Sinks = still
+			continue
+		}
+		delete(open, key)
+		resolved++
+	}
+
+	if err := save(townRoot, open); err != nil {
+		errs = append(errs, err)
+	}
+	return triggered, resolved, errors.Join(errs...)
+}
+
+// containsName reports whether names contains name.
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
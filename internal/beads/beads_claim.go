@@ -0,0 +1,90 @@
+// Package beads provides claim operations for assigning work.
+package beads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// ErrAlreadyClaimed indicates the bead is assigned to someone else.
+var ErrAlreadyClaimed = errors.New("bead already claimed")
+
+// ClaimLockFile is the lock file, inside the resolved .beads directory, that
+// serializes claims against one bead store.
+const ClaimLockFile = "claim.lock"
+
+// claimLockTimeout bounds how long a claim waits for a concurrent claim.
+const claimLockTimeout = 30 * time.Second
+
+// ClaimConflictError reports that a claim lost to an existing assignee.
+// It matches ErrAlreadyClaimed with errors.Is.
+type ClaimConflictError struct {
+	ID     string // bead being claimed
+	Holder string // current assignee
+	Status string // current status
+}
+
+func (e *ClaimConflictError) Error() string {
+	return fmt.Sprintf("bead %s is already claimed by %s (status %s)", e.ID, e.Holder, e.Status)
+}
+
+// Unwrap lets errors.Is match ErrAlreadyClaimed.
+func (e *ClaimConflictError) Unwrap() error {
+	return ErrAlreadyClaimed
+}
+
+// ClaimOptions configures a claim.
+type ClaimOptions struct {
+	// Status is the status to set on success. Default: "hooked".
+	Status string
+
+	// Force takes the bead even if someone else holds it.
+	Force bool
+}
+
+// Claim assigns the bead to assignee with compare-and-set semantics: it
+// succeeds only if the bead is unassigned, already held by assignee, or
+// closed. Claims made through Claim against the same bead store are
+// serialized by a file lock, so two gt dispatchers (e.g. two witnesses, or a
+// witness and a human running gt sling) racing for one bead cannot both win;
+// the loser gets a *ClaimConflictError. bd itself does not take the lock: an
+// assignment written directly with bd update can still race a claim.
+func (b *Beads) Claim(id, assignee string, opts ClaimOptions) error {
+	if opts.Status == "" {
+		opts.Status = StatusHooked
+	}
+
+	// Lock the store this wrapper resolves to. Without a local .beads
+	// directory bd finds its database elsewhere, so there is nothing to lock
+	// and the claim falls back to a plain compare-and-set.
+	beadsDir := b.beadsDir
+	if beadsDir == "" {
+		beadsDir = ResolveBeadsDir(b.workDir)
+	}
+	if info, err := os.Stat(beadsDir); err == nil && info.IsDir() {
+		lock := flock.New(filepath.Join(beadsDir, ClaimLockFile))
+		ctx, cancel := context.WithTimeout(context.Background(), claimLockTimeout)
+		defer cancel()
+		locked, err := lock.TryLockContext(ctx, 50*time.Millisecond)
+		if err != nil || !locked {
+			return fmt.Errorf("acquiring claim lock for %s: %w", id, err)
+		}
+		defer func() { _ = lock.Unlock() }()
+	}
+
+	issue, err := b.Show(id)
+	if err != nil {
+		return err
+	}
+	if !opts.Force && issue.Assignee != "" && issue.Assignee != assignee && issue.Status != "closed" {
+		return &ClaimConflictError{ID: id, Holder: issue.Assignee, Status: issue.Status}
+	}
+
+	return b.Update(id, UpdateOptions{Status: &opts.Status, Assignee: &assignee})
+}
@@ -0,0 +1,92 @@
+package beads
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// stubClaimBD installs a bd stub whose show output reports the given assignee
+// and which logs update calls. It returns the path of the update log.
+func stubClaimBD(t *testing.T, assignee, status string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell stub not supported on windows")
+	}
+	binDir := t.TempDir()
+	logPath := filepath.Join(binDir, "bd.log")
+	script := `#!/bin/sh
+while [ "${1#--}" != "$1" ]; do shift; done
+case "$1" in
+  show)
+    echo '[{"id":"gt-abc","title":"Work","status":"` + status + `","assignee":"` + assignee + `"}]'
+    ;;
+  update)
+    echo "$*" >> "` + logPath + `"
+    ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatalf("write bd stub: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return logPath
+}
+
+func TestClaim(t *testing.T) {
+	tests := []struct {
+		name         string
+		holder       string
+		status       string
+		force        bool
+		wantConflict bool
+	}{
+		{"unassigned", "", "open", false, false},
+		{"already mine", "gastown/polecats/nux", "hooked", false, false},
+		{"held by other", "gastown/polecats/toast", "hooked", false, true},
+		{"held by other forced", "gastown/polecats/toast", "hooked", true, false},
+		{"closed", "gastown/polecats/toast", "closed", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := stubClaimBD(t, tt.holder, tt.status)
+			workDir := t.TempDir()
+			beadsDir := filepath.Join(workDir, ".beads")
+			if err := os.MkdirAll(beadsDir, 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			err := New(workDir).Claim("gt-abc", "gastown/polecats/nux", ClaimOptions{Force: tt.force})
+			logged, _ := os.ReadFile(logPath)
+
+			if tt.wantConflict {
+				if !errors.Is(err, ErrAlreadyClaimed) {
+					t.Fatalf("Claim() = %v, want ErrAlreadyClaimed", err)
+				}
+				var conflict *ClaimConflictError
+				if !errors.As(err, &conflict) || conflict.Holder != tt.holder {
+					t.Errorf("Claim() conflict = %+v, want holder %s", conflict, tt.holder)
+				}
+				if len(logged) != 0 {
+					t.Errorf("conflicting claim ran update: %s", logged)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Claim() = %v", err)
+			}
+			if !strings.Contains(string(logged), "--status=hooked") ||
+				!strings.Contains(string(logged), "--assignee=gastown/polecats/nux") {
+				t.Errorf("update args = %q, want hooked status and assignee", logged)
+			}
+			if _, err := os.Stat(filepath.Join(beadsDir, ClaimLockFile)); err != nil {
+				t.Errorf("claim lock not created: %v", err)
+			}
+		})
+	}
+}
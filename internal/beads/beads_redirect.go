@@ -111,7 +111,7 @@ func cleanBeadsRuntimeFiles(beadsDir string) error {
 		// SQLite databases
 		"*.db", "*.db-*", "*.db?*",
 		// Daemon runtime
-		"daemon.lock", "daemon.log", "daemon.pid", "bd.sock",
+		"daemon.lock", "daemon.log", "daemon.pid", "bd.sock", "claim.lock",
 		// Sync state
 		"sync-state.json", "last-touched", "metadata.json",
 		// Version tracking
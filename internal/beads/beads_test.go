@@ -568,6 +568,10 @@ func TestMRFieldsRoundTrip(t *testing.T) {
 		Rig:         "gastown",
 		MergeCommit: "abc123def789",
 		CloseReason: "merged",
+		PRURL:       "https://github.com/example/gastown/pull/42",
+
+		ReviewVerdict: "approved",
+		Reviewer:      "gastown/reviewer",
 	}
 
 	// Format to string
@@ -178,6 +178,11 @@ type MRFields struct {
 	MergeCommit string // SHA of merge commit (set on close)
 	CloseReason string // Reason for closing: merged, rejected, conflict, superseded
 	AgentBead   string // Agent bead ID that created this MR (for traceability)
+	PRURL       string // Forge pull request URL (if one was opened for this branch)
+
+	// Code review fields (set by the Refinery's review stage)
+	ReviewVerdict string // approved or changes_requested
+	Reviewer      string // Reviewer identity (e.g., "gastown/reviewer")
 
 	// Conflict resolution fields (for priority scoring)
 	RetryCount      int    // Number of conflict-resolution cycles
@@ -244,6 +249,15 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "agent_bead", "agent-bead", "agentbead":
 			fields.AgentBead = value
 			hasFields = true
+		case "pr_url", "pr-url", "prurl":
+			fields.PRURL = value
+			hasFields = true
+		case "review_verdict", "review-verdict", "reviewverdict":
+			fields.ReviewVerdict = value
+			hasFields = true
+		case "reviewer":
+			fields.Reviewer = value
+			hasFields = true
 		case "retry_count", "retry-count", "retrycount":
 			if n, err := parseIntField(value); err == nil {
 				fields.RetryCount = n
@@ -310,6 +324,15 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.AgentBead != "" {
 		lines = append(lines, "agent_bead: "+fields.AgentBead)
 	}
+	if fields.PRURL != "" {
+		lines = append(lines, "pr_url: "+fields.PRURL)
+	}
+	if fields.ReviewVerdict != "" {
+		lines = append(lines, "review_verdict: "+fields.ReviewVerdict)
+	}
+	if fields.Reviewer != "" {
+		lines = append(lines, "reviewer: "+fields.Reviewer)
+	}
 	if fields.RetryCount > 0 {
 		lines = append(lines, fmt.Sprintf("retry_count: %d", fields.RetryCount))
 	}
@@ -355,6 +378,13 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"agent_bead":         true,
 		"agent-bead":         true,
 		"agentbead":          true,
+		"pr_url":             true,
+		"pr-url":             true,
+		"prurl":              true,
+		"review_verdict":     true,
+		"review-verdict":     true,
+		"reviewverdict":      true,
+		"reviewer":           true,
 		"retry_count":        true,
 		"retry-count":        true,
 		"retrycount":         true,
@@ -0,0 +1,191 @@
+// Package budget evaluates per-rig spend caps and tracks human overrides.
+//
+// Spend is rolled up per rig by the caller (from session cost records); this
+// package decides whether a rig is over budget and whether enforcement applies.
+// Override and alert state lives in the wisp layer, so it is local to the town
+// and disappears on wisp cleanup like other operational state (e.g., parking).
+package budget
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/wisp"
+)
+
+// Wisp config keys for budget state.
+const (
+	overrideUntilKey  = "budget_override_until"
+	overrideReasonKey = "budget_override_reason"
+	overrideByKey     = "budget_override_by"
+	alertedKey        = "budget_alerted"
+)
+
+// Budget windows. The token windows cap the same periods as the dollar ones.
+const (
+	WindowDaily        = "daily"
+	WindowWeekly       = "weekly"
+	WindowDailyTokens  = "daily_tokens"
+	WindowWeeklyTokens = "weekly_tokens"
+)
+
+// Spend is a rig's rolled-up spend in USD and tokens.
+type Spend struct {
+	Daily        float64 `json:"daily_usd"`
+	Weekly       float64 `json:"weekly_usd"`
+	DailyTokens  int     `json:"daily_tokens"`
+	WeeklyTokens int     `json:"weekly_tokens"`
+}
+
+// Override suspends budget enforcement for a rig until a deadline.
+type Override struct {
+	Until  time.Time `json:"until"`
+	Reason string    `json:"reason,omitempty"`
+	By     string    `json:"by,omitempty"`
+}
+
+// Active reports whether the override is in effect at now.
+func (o *Override) Active(now time.Time) bool {
+	return o != nil && now.Before(o.Until)
+}
+
+// Status is the budget evaluation for one rig.
+type Status struct {
+	Rig       string    `json:"rig"`
+	Spend     Spend     `json:"spend"`
+	DailyUSD  float64   `json:"daily_cap_usd,omitempty"`
+	WeeklyUSD float64   `json:"weekly_cap_usd,omitempty"`
+	Exceeded  []string  `json:"exceeded,omitempty"` // windows over their cap
+	Override  *Override `json:"override,omitempty"`
+	Enforced  bool      `json:"enforced"` // over budget and not overridden
+
+	DailyTokens  int `json:"daily_cap_tokens,omitempty"`
+	WeeklyTokens int `json:"weekly_cap_tokens,omitempty"`
+}
+
+// OverBudget reports whether any cap is exceeded, regardless of overrides.
+func (s *Status) OverBudget() bool {
+	return len(s.Exceeded) > 0
+}
+
+// Summary describes the exceeded caps (e.g., "daily $52.10 of $50.00" or
+// "weekly 2.1M of 2M tokens").
+func (s *Status) Summary() string {
+	var parts []string
+	for _, w := range s.Exceeded {
+		switch w {
+		case WindowDaily:
+			parts = append(parts, fmt.Sprintf("daily $%.2f of $%.2f", s.Spend.Daily, s.DailyUSD))
+		case WindowWeekly:
+			parts = append(parts, fmt.Sprintf("weekly $%.2f of $%.2f", s.Spend.Weekly, s.WeeklyUSD))
+		case WindowDailyTokens:
+			parts = append(parts, fmt.Sprintf("daily %s of %s tokens", FormatTokens(s.Spend.DailyTokens), FormatTokens(s.DailyTokens)))
+		case WindowWeeklyTokens:
+			parts = append(parts, fmt.Sprintf("weekly %s of %s tokens", FormatTokens(s.Spend.WeeklyTokens), FormatTokens(s.WeeklyTokens)))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Evaluate compares spend against the rig's caps. A cap is exceeded once spend
+// reaches it. The override, if any, is recorded and suppresses enforcement.
+func Evaluate(rig string, cfg *config.BudgetConfig, spend Spend, override *Override, now time.Time) *Status {
+	s := &Status{Rig: rig, Spend: spend}
+	if cfg == nil {
+		return s
+	}
+	s.DailyUSD = cfg.DailyUSD
+	s.WeeklyUSD = cfg.WeeklyUSD
+	s.DailyTokens = cfg.DailyTokens
+	s.WeeklyTokens = cfg.WeeklyTokens
+
+	if cfg.DailyUSD > 0 && spend.Daily >= cfg.DailyUSD {
+		s.Exceeded = append(s.Exceeded, WindowDaily)
+	}
+	if cfg.WeeklyUSD > 0 && spend.Weekly >= cfg.WeeklyUSD {
+		s.Exceeded = append(s.Exceeded, WindowWeekly)
+	}
+	if cfg.DailyTokens > 0 && spend.DailyTokens >= cfg.DailyTokens {
+		s.Exceeded = append(s.Exceeded, WindowDailyTokens)
+	}
+	if cfg.WeeklyTokens > 0 && spend.WeeklyTokens >= cfg.WeeklyTokens {
+		s.Exceeded = append(s.Exceeded, WindowWeeklyTokens)
+	}
+
+	if override.Active(now) {
+		s.Override = override
+	}
+	s.Enforced = s.OverBudget() && s.Override == nil
+	return s
+}
+
+// FormatTokens formats a token count compactly (e.g., "950", "12.5k", "2.1M").
+func FormatTokens(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", float64(n)/1_000_000), ".0") + "M"
+	case n >= 1_000:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", float64(n)/1_000), ".0") + "k"
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
+// GetOverride returns the rig's budget override, or nil if none is set.
+func GetOverride(townRoot, rig string) *Override {
+	cfg := wisp.NewConfig(townRoot, rig)
+	until, err := time.Parse(time.RFC3339, cfg.GetString(overrideUntilKey))
+	if err != nil {
+		return nil
+	}
+	return &Override{
+		Until:  until,
+		Reason: cfg.GetString(overrideReasonKey),
+		By:     cfg.GetString(overrideByKey),
+	}
+}
+
+// SetOverride suspends enforcement for the rig until o.Until.
+func SetOverride(townRoot, rig string, o Override) error {
+	cfg := wisp.NewConfig(townRoot, rig)
+	if err := cfg.Set(overrideUntilKey, o.Until.UTC().Format(time.RFC3339)); err != nil {
+		return err
+	}
+	if err := cfg.Set(overrideReasonKey, o.Reason); err != nil {
+		return err
+	}
+	return cfg.Set(overrideByKey, o.By)
+}
+
+// ClearOverride removes the rig's budget override.
+func ClearOverride(townRoot, rig string) error {
+	cfg := wisp.NewConfig(townRoot, rig)
+	for _, key := range []string{overrideUntilKey, overrideReasonKey, overrideByKey} {
+		if err := cfg.Unset(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// alertKey identifies the budget period an alert covers, so each exceeded
+// window alerts once per period rather than on every check.
+func alertKey(s *Status, now time.Time) string {
+	return strings.Join(s.Exceeded, "+") + ":" + now.Format("2006-01-02")
+}
+
+// ShouldAlert reports whether an alert for the status has not been sent yet
+// in the current period.
+func ShouldAlert(townRoot string, s *Status, now time.Time) bool {
+	if !s.Enforced {
+		return false
+	}
+	return wisp.NewConfig(townRoot, s.Rig).GetString(alertedKey) != alertKey(s, now)
+}
+
+// MarkAlerted records that an alert for the status was sent.
+func MarkAlerted(townRoot string, s *Status, now time.Time) error {
+	return wisp.NewConfig(townRoot, s.Rig).Set(alertedKey, alertKey(s, now))
+}
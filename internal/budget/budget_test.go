@@ -0,0 +1,121 @@
+package budget
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestEvaluate(t *testing.T) {
+	now := time.Now()
+	cfg := &config.BudgetConfig{DailyUSD: 50, WeeklyUSD: 200}
+
+	tests := []struct {
+		name     string
+		cfg      *config.BudgetConfig
+		spend    Spend
+		override *Override
+		exceeded []string
+		enforced bool
+	}{
+		{"no config", nil, Spend{Daily: 1000, Weekly: 1000}, nil, nil, false},
+		{"within budget", cfg, Spend{Daily: 10, Weekly: 100}, nil, nil, false},
+		{"daily exceeded", cfg, Spend{Daily: 50, Weekly: 100}, nil, []string{WindowDaily}, true},
+		{"both exceeded", cfg, Spend{Daily: 60, Weekly: 250}, nil, []string{WindowDaily, WindowWeekly}, true},
+		{"weekly only cap", &config.BudgetConfig{WeeklyUSD: 200}, Spend{Daily: 500, Weekly: 100}, nil, nil, false},
+		{"overridden", cfg, Spend{Daily: 60}, &Override{Until: now.Add(time.Hour)}, []string{WindowDaily}, false},
+		{"override expired", cfg, Spend{Daily: 60}, &Override{Until: now.Add(-time.Hour)}, []string{WindowDaily}, true},
+		{"daily tokens exceeded", &config.BudgetConfig{DailyTokens: 1000, WeeklyTokens: 5000},
+			Spend{DailyTokens: 1000, WeeklyTokens: 2000}, nil, []string{WindowDailyTokens}, true},
+		{"weekly tokens exceeded", &config.BudgetConfig{DailyUSD: 50, WeeklyTokens: 5000},
+			Spend{Daily: 10, DailyTokens: 900, WeeklyTokens: 6000}, nil, []string{WindowWeeklyTokens}, true},
+		{"tokens within budget", &config.BudgetConfig{DailyTokens: 1000},
+			Spend{Daily: 500, DailyTokens: 999}, nil, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Evaluate("gastown", tt.cfg, tt.spend, tt.override, now)
+			if len(s.Exceeded) != len(tt.exceeded) {
+				t.Fatalf("Exceeded = %v, want %v", s.Exceeded, tt.exceeded)
+			}
+			for i := range tt.exceeded {
+				if s.Exceeded[i] != tt.exceeded[i] {
+					t.Errorf("Exceeded = %v, want %v", s.Exceeded, tt.exceeded)
+				}
+			}
+			if s.Enforced != tt.enforced {
+				t.Errorf("Enforced = %v, want %v", s.Enforced, tt.enforced)
+			}
+		})
+	}
+}
+
+func TestStatusSummary(t *testing.T) {
+	s := Evaluate("gastown", &config.BudgetConfig{DailyUSD: 50, WeeklyUSD: 200},
+		Spend{Daily: 52.1, Weekly: 210}, nil, time.Now())
+	want := "daily $52.10 of $50.00, weekly $210.00 of $200.00"
+	if got := s.Summary(); got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+
+	s = Evaluate("gastown", &config.BudgetConfig{DailyTokens: 500_000, WeeklyTokens: 2_000_000},
+		Spend{DailyTokens: 512_300, WeeklyTokens: 2_100_000}, nil, time.Now())
+	want = "daily 512.3k of 500k tokens, weekly 2.1M of 2M tokens"
+	if got := s.Summary(); got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
+
+func TestOverrideRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+
+	if o := GetOverride(townRoot, "gastown"); o != nil {
+		t.Fatalf("GetOverride before set = %+v, want nil", o)
+	}
+
+	until := time.Now().Add(4 * time.Hour).Truncate(time.Second)
+	if err := SetOverride(townRoot, "gastown", Override{Until: until, Reason: "release", By: "overseer"}); err != nil {
+		t.Fatalf("SetOverride: %v", err)
+	}
+
+	o := GetOverride(townRoot, "gastown")
+	if o == nil || !o.Until.Equal(until) || o.Reason != "release" || o.By != "overseer" {
+		t.Fatalf("GetOverride = %+v, want round-tripped override", o)
+	}
+	if !o.Active(time.Now()) {
+		t.Error("override should be active")
+	}
+
+	if err := ClearOverride(townRoot, "gastown"); err != nil {
+		t.Fatalf("ClearOverride: %v", err)
+	}
+	if o := GetOverride(townRoot, "gastown"); o != nil {
+		t.Errorf("GetOverride after clear = %+v, want nil", o)
+	}
+}
+
+func TestShouldAlertOncePerPeriod(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+	s := Evaluate("gastown", &config.BudgetConfig{DailyUSD: 50}, Spend{Daily: 60}, nil, now)
+
+	if !ShouldAlert(townRoot, s, now) {
+		t.Fatal("first check should alert")
+	}
+	if err := MarkAlerted(townRoot, s, now); err != nil {
+		t.Fatalf("MarkAlerted: %v", err)
+	}
+	if ShouldAlert(townRoot, s, now) {
+		t.Error("second check in the same period should not alert")
+	}
+	if !ShouldAlert(townRoot, s, now.AddDate(0, 0, 1)) {
+		t.Error("next day should alert again")
+	}
+
+	within := Evaluate("gastown", &config.BudgetConfig{DailyUSD: 50}, Spend{Daily: 10}, nil, now)
+	if ShouldAlert(townRoot, within, now) {
+		t.Error("within budget should never alert")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
@@ -28,13 +29,27 @@ var accountCmd = &cobra.Command{
 	Long: `Manage multiple Claude Code accounts for Gas Town.
 
 This enables switching between accounts (e.g., personal vs work) with
-easy account selection per spawn or globally.
+easy account selection per spawn, per role or rig, or globally.
+
+A session's account is chosen by GT_ACCOUNT, then --account, then the rig's
+assignment, the role's assignment, and the default. With rotation enabled in
+mayor/accounts.json, new sessions skip accounts that recently hit a rate
+limit:
+
+  "rotation": { "enabled": true, "pool": ["work", "team"], "cooldown": "1h" }
+
+An account can use an API key instead of (or as well as) a config dir:
+"api_key_env" names the environment variable or 'gt secret' entry holding it.
 
 Commands:
   gt account list              List registered accounts
   gt account add <handle>      Add a new account
   gt account default <handle>  Set the default account
-  gt account status            Show current account info`,
+  gt account status            Show current account info
+  gt account assign <handle>   Assign an account to roles or rigs
+  gt account usage             Show per-account usage and rate limits
+  gt account check             Detect rate-limited accounts (run by daemon)
+  gt account cooldown <handle> Take an account out of rotation`,
 }
 
 var accountListCmd = &cobra.Command{
@@ -84,11 +99,13 @@ Examples:
 
 // AccountListItem represents an account in list output.
 type AccountListItem struct {
-	Handle      string `json:"handle"`
-	Email       string `json:"email"`
-	Description string `json:"description,omitempty"`
-	ConfigDir   string `json:"config_dir"`
-	IsDefault   bool   `json:"is_default"`
+	Handle      string   `json:"handle"`
+	Email       string   `json:"email"`
+	Description string   `json:"description,omitempty"`
+	ConfigDir   string   `json:"config_dir,omitempty"`
+	APIKeyEnv   string   `json:"api_key_env,omitempty"`
+	IsDefault   bool     `json:"is_default"`
+	AssignedTo  []string `json:"assigned_to,omitempty"`
 }
 
 func runAccountList(cmd *cobra.Command, args []string) error {
@@ -122,7 +139,9 @@ func runAccountList(cmd *cobra.Command, args []string) error {
 			Email:       acct.Email,
 			Description: acct.Description,
 			ConfigDir:   acct.ConfigDir,
+			APIKeyEnv:   acct.APIKeyEnv,
 			IsDefault:   handle == cfg.Default,
+			AssignedTo:  accountAssignments(cfg, handle),
 		})
 	}
 
@@ -157,11 +176,31 @@ func runAccountList(cmd *cobra.Command, args []string) error {
 		if item.Description != "" {
 			fmt.Printf("    %s\n", style.Dim.Render(item.Description))
 		}
+		if len(item.AssignedTo) > 0 {
+			fmt.Printf("    %s\n", style.Dim.Render("assigned: "+strings.Join(item.AssignedTo, ", ")))
+		}
 	}
 
 	return nil
 }
 
+// accountAssignments lists the roles and rigs assigned to handle, sorted.
+func accountAssignments(cfg *config.AccountsConfig, handle string) []string {
+	var out []string
+	for role, h := range cfg.Roles {
+		if h == handle {
+			out = append(out, "role "+role)
+		}
+	}
+	for rigName, h := range cfg.Rigs {
+		if h == handle {
+			out = append(out, "rig "+rigName)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
 func runAccountAdd(cmd *cobra.Command, args []string) error {
 	handle := args[0]
 
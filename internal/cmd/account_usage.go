@@ -0,0 +1,385 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/account"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Account assignment, usage, and rotation flags
+var (
+	accountAssignRoles []string
+	accountAssignRigs  []string
+	accountAssignClear bool
+	accountUsageJSON   bool
+	accountCheckDryRun bool
+	accountCooldownFor time.Duration
+	accountCooldownEnd bool
+)
+
+// rateLimitScanLines is how much of each pane's tail gt account check reads.
+const rateLimitScanLines = 30
+
+var accountAssignCmd = &cobra.Command{
+	Use:   "assign [handle]",
+	Short: "Assign an account to roles or rigs",
+	Long: `Assign an account to roles or rigs.
+
+New sessions for an assigned role or rig use that account instead of the
+default. A rig assignment wins over a role assignment; GT_ACCOUNT and
+--account still override both.
+
+Examples:
+  gt account assign work --role polecat --role refinery
+  gt account assign personal --rig docs
+  gt account assign --clear --role polecat`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAccountAssign,
+}
+
+var accountUsageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show per-account usage and rate-limit state",
+	Long: `Show usage tracked per account: sessions started, live sessions,
+today's cost, rate limits detected, and any active cooldown.
+
+Examples:
+  gt account usage
+  gt account usage --json`,
+	RunE: runAccountUsage,
+}
+
+var accountCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Detect rate-limited accounts from live sessions",
+	Long: `Scan live sessions for rate-limit messages and put their accounts in a
+cooldown.
+
+While an account cools down, sessions that would use it through a role, rig,
+or default assignment start on the next account in the rotation pool
+(when rotation is enabled in mayor/accounts.json). Sessions already running
+on the limited account are left alone. The daemon runs this on every
+heartbeat.`,
+	RunE: runAccountCheck,
+}
+
+var accountCooldownCmd = &cobra.Command{
+	Use:   "cooldown <handle>",
+	Short: "Take an account out of rotation for a while",
+	Long: `Manually put an account in a rate-limit cooldown, or end one early.
+
+Examples:
+  gt account cooldown work --for 5h     # Limit resets in 5 hours
+  gt account cooldown work --clear      # Limit lifted`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAccountCooldown,
+}
+
+func init() {
+	accountAssignCmd.Flags().StringSliceVar(&accountAssignRoles, "role", nil, "Role to assign (repeatable)")
+	accountAssignCmd.Flags().StringSliceVar(&accountAssignRigs, "rig", nil, "Rig to assign (repeatable)")
+	accountAssignCmd.Flags().BoolVar(&accountAssignClear, "clear", false, "Remove the assignments instead")
+	accountUsageCmd.Flags().BoolVar(&accountUsageJSON, "json", false, "Output as JSON")
+	accountCheckCmd.Flags().BoolVar(&accountCheckDryRun, "dry-run", false, "Report rate limits without recording them")
+	accountCooldownCmd.Flags().DurationVar(&accountCooldownFor, "for", 0, "Cooldown length (default: rotation cooldown)")
+	accountCooldownCmd.Flags().BoolVar(&accountCooldownEnd, "clear", false, "End the cooldown")
+
+	accountCmd.AddCommand(accountAssignCmd)
+	accountCmd.AddCommand(accountUsageCmd)
+	accountCmd.AddCommand(accountCheckCmd)
+	accountCmd.AddCommand(accountCooldownCmd)
+}
+
+// printResolvedAccount reports the account a new session will use.
+func printResolvedAccount(acct *account.Resolved) {
+	if acct == nil {
+		return
+	}
+	fmt.Printf("Using account: %s\n", acct.Handle)
+	if acct.RotatedFrom != "" {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("(rotated from %s, which is rate limited)", acct.RotatedFrom)))
+	} else if acct.Limited {
+		style.PrintWarning("account '%s' is rate limited; the session may stall until the limit resets", acct.Handle)
+	}
+}
+
+// loadTownAccounts finds the town and loads its accounts config.
+func loadTownAccounts() (string, *config.AccountsConfig, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := config.LoadAccountsConfig(constants.MayorAccountsPath(townRoot))
+	if err != nil {
+		return "", nil, fmt.Errorf("loading accounts config: %w", err)
+	}
+	return townRoot, cfg, nil
+}
+
+func runAccountAssign(cmd *cobra.Command, args []string) error {
+	if len(accountAssignRoles) == 0 && len(accountAssignRigs) == 0 {
+		return fmt.Errorf("specify at least one --role or --rig")
+	}
+	if accountAssignClear == (len(args) == 1) {
+		return fmt.Errorf("give an account handle to assign, or --clear without one")
+	}
+
+	townRoot, cfg, err := loadTownAccounts()
+	if err != nil {
+		return err
+	}
+	handle := ""
+	if len(args) == 1 {
+		handle = args[0]
+		if cfg.GetAccount(handle) == nil {
+			return fmt.Errorf("account '%s' not found", handle)
+		}
+	}
+
+	if cfg.Roles == nil {
+		cfg.Roles = make(map[string]string)
+	}
+	if cfg.Rigs == nil {
+		cfg.Rigs = make(map[string]string)
+	}
+	for _, role := range accountAssignRoles {
+		if !isKnownRole(role) {
+			return fmt.Errorf("unknown role '%s'", role)
+		}
+		setAssignment(cfg.Roles, role, handle)
+	}
+	for _, rigName := range accountAssignRigs {
+		setAssignment(cfg.Rigs, rigName, handle)
+	}
+
+	if err := config.SaveAccountsConfig(constants.MayorAccountsPath(townRoot), cfg); err != nil {
+		return fmt.Errorf("saving accounts config: %w", err)
+	}
+
+	targets := append(prefixAll("role ", accountAssignRoles), prefixAll("rig ", accountAssignRigs)...)
+	if handle == "" {
+		fmt.Printf("%s Cleared account assignment for %s\n", style.Bold.Render("✓"), strings.Join(targets, ", "))
+	} else {
+		fmt.Printf("%s Assigned '%s' to %s\n", style.Bold.Render("✓"), handle, strings.Join(targets, ", "))
+	}
+	return nil
+}
+
+// setAssignment sets or (for an empty handle) removes an assignment.
+func setAssignment(m map[string]string, key, handle string) {
+	if handle == "" {
+		delete(m, key)
+		return
+	}
+	m[key] = handle
+}
+
+// isKnownRole reports whether role is an agent role accounts can be assigned to.
+func isKnownRole(role string) bool {
+	switch role {
+	case constants.RoleMayor, constants.RoleDeacon, constants.RoleWitness,
+		constants.RoleRefinery, constants.RolePolecat, constants.RoleCrew:
+		return true
+	}
+	return false
+}
+
+func prefixAll(prefix string, items []string) []string {
+	out := make([]string, len(items))
+	for i, s := range items {
+		out[i] = prefix + s
+	}
+	return out
+}
+
+// AccountUsageItem is one account in gt account usage output.
+type AccountUsageItem struct {
+	Handle        string     `json:"handle"`
+	Sessions      int        `json:"sessions"`
+	LiveSessions  []string   `json:"live_sessions,omitempty"`
+	TodayUSD      float64    `json:"today_usd"`
+	RateLimits    int        `json:"rate_limits"`
+	LastUsed      *time.Time `json:"last_used,omitempty"`
+	LastRateLimit *time.Time `json:"last_rate_limit,omitempty"`
+	LimitedUntil  *time.Time `json:"limited_until,omitempty"`
+}
+
+func runAccountUsage(cmd *cobra.Command, args []string) error {
+	townRoot, cfg, err := loadTownAccounts()
+	if err != nil {
+		return err
+	}
+	state, err := account.LoadState(townRoot)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	items := make(map[string]*AccountUsageItem, len(cfg.Accounts))
+	for handle := range cfg.Accounts {
+		item := &AccountUsageItem{Handle: handle}
+		if u := state.Accounts[handle]; u != nil {
+			item.Sessions = u.Sessions
+			item.RateLimits = u.RateLimits
+			item.LastUsed = timePtr(u.LastUsed)
+			item.LastRateLimit = timePtr(u.LastRateLimit)
+			if u.Limited(now) {
+				item.LimitedUntil = timePtr(u.LimitedUntil)
+			}
+		}
+		items[handle] = item
+	}
+
+	// Today's cost: ended sessions from cost records plus live sessions'
+	// running cost, attributed through the session -> account map.
+	today, _ := querySessionCostWisps(now)
+	for _, e := range today {
+		if item := items[state.Sessions[e.SessionID]]; item != nil {
+			item.TodayUSD += e.CostUSD
+		}
+	}
+	t := tmux.NewTmux()
+	sessions, _ := t.ListSessions()
+	for _, sess := range sessions {
+		item := items[state.Sessions[sess]]
+		if item == nil {
+			continue
+		}
+		item.LiveSessions = append(item.LiveSessions, sess)
+		if content, err := t.CapturePaneAll(sess); err == nil {
+			item.TodayUSD += extractCost(content)
+		}
+	}
+
+	list := make([]*AccountUsageItem, 0, len(items))
+	for _, item := range items {
+		list = append(list, item)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Handle < list[j].Handle })
+
+	if accountUsageJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list)
+	}
+
+	if len(list) == 0 {
+		fmt.Println("No accounts configured.")
+		return nil
+	}
+	fmt.Printf("%s\n\n", style.Bold.Render("Account Usage"))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACCOUNT\tSESSIONS\tLIVE\tTODAY\tRATE LIMITS\tSTATUS")
+	for _, item := range list {
+		status := "ok"
+		if item.LimitedUntil != nil {
+			status = style.Warning.Render("limited until " + item.LimitedUntil.Local().Format("15:04"))
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t$%.2f\t%d\t%s\n",
+			item.Handle, item.Sessions, len(item.LiveSessions), item.TodayUSD, item.RateLimits, status)
+	}
+	_ = w.Flush()
+
+	if cfg.Rotation != nil && cfg.Rotation.Enabled {
+		fmt.Printf("\n%s\n", style.Dim.Render(fmt.Sprintf("Rotation enabled (cooldown %s)", cfg.Rotation.CooldownDuration())))
+	}
+	return nil
+}
+
+// timePtr returns nil for the zero time, so JSON output omits it.
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func runAccountCheck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := config.LoadAccountsConfig(constants.MayorAccountsPath(townRoot))
+	if err != nil || len(cfg.Accounts) == 0 {
+		return nil // no accounts, nothing to rotate
+	}
+	state, err := account.LoadState(townRoot)
+	if err != nil {
+		return err
+	}
+
+	t := tmux.NewTmux()
+	sessions, _ := t.ListSessions()
+	until := time.Now().Add(cfg.Rotation.CooldownDuration())
+	seen := make(map[string]bool)
+	for _, sess := range sessions {
+		handle := state.Sessions[sess]
+		if handle == "" || seen[handle] || cfg.GetAccount(handle) == nil {
+			continue
+		}
+		content, err := t.CapturePane(sess, rateLimitScanLines)
+		if err != nil || !account.DetectRateLimit(content) {
+			continue
+		}
+		seen[handle] = true
+
+		if accountCheckDryRun {
+			fmt.Printf("Would mark %s rate limited (seen in %s)\n", handle, sess)
+			continue
+		}
+		marked, err := account.MarkLimited(townRoot, handle, until)
+		if err != nil {
+			return fmt.Errorf("recording rate limit for %s: %w", handle, err)
+		}
+		if marked {
+			fmt.Printf("Account %s rate limited (seen in %s): cooling down until %s\n",
+				handle, sess, until.Format("15:04"))
+		}
+	}
+	return nil
+}
+
+func runAccountCooldown(cmd *cobra.Command, args []string) error {
+	handle := args[0]
+	townRoot, cfg, err := loadTownAccounts()
+	if err != nil {
+		return err
+	}
+	if cfg.GetAccount(handle) == nil {
+		return fmt.Errorf("account '%s' not found", handle)
+	}
+
+	if accountCooldownEnd {
+		if err := account.ClearLimit(townRoot, handle); err != nil {
+			return err
+		}
+		fmt.Printf("%s Cleared cooldown for '%s'\n", style.Bold.Render("✓"), handle)
+		return nil
+	}
+
+	d := accountCooldownFor
+	if d <= 0 {
+		d = cfg.Rotation.CooldownDuration()
+	}
+	until := time.Now().Add(d)
+	if err := account.ClearLimit(townRoot, handle); err != nil {
+		return err
+	}
+	if _, err := account.MarkLimited(townRoot, handle, until); err != nil {
+		return err
+	}
+	fmt.Printf("%s '%s' cooling down until %s\n", style.Bold.Render("✓"), handle, until.Format("Jan 2 15:04"))
+	return nil
+}
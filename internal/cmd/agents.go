@@ -12,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/lock"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -35,6 +36,7 @@ type AgentSession struct {
 	Type      AgentType
 	Rig       string // For rig-specific agents
 	AgentName string // e.g., crew name, polecat name
+	Town      string // Town whose naming scheme the session matched
 }
 
 // AgentTypeColors maps agent types to tmux color codes.
@@ -126,67 +128,41 @@ func init() {
 }
 
 // categorizeSession determines the agent type from a session name.
+// Names are parsed with the town's session naming scheme, so sessions
+// belonging to other towns on the same tmux server are ignored.
 func categorizeSession(name string) *AgentSession {
-	session := &AgentSession{Name: name}
-
-	// Town-level agents use hq- prefix: hq-mayor, hq-deacon
-	if strings.HasPrefix(name, "hq-") {
-		suffix := strings.TrimPrefix(name, "hq-")
-		if suffix == "mayor" {
-			session.Type = AgentMayor
-			return session
-		}
-		if suffix == "deacon" {
-			session.Type = AgentDeacon
-			return session
-		}
-		return nil // Unknown hq- session
-	}
-
-	// Rig-level agents use gt- prefix
-	if !strings.HasPrefix(name, "gt-") {
-		return nil
-	}
-
-	suffix := strings.TrimPrefix(name, "gt-")
+	scheme := session.CurrentScheme()
+	agent := &AgentSession{Name: name, Town: scheme.Town}
 
 	// Witness sessions: legacy format gt-witness-<rig> (fallback)
-	if strings.HasPrefix(suffix, "witness-") {
-		session.Type = AgentWitness
-		session.Rig = strings.TrimPrefix(suffix, "witness-")
-		return session
+	if rest := strings.TrimPrefix(name, scheme.Prefix+"witness-"); rest != name && rest != "" {
+		agent.Type = AgentWitness
+		agent.Rig = rest
+		return agent
 	}
 
-	// Rig-level agents: gt-<rig>-<type> or gt-<rig>-crew-<name>
-	parts := strings.SplitN(suffix, "-", 2)
-	if len(parts) < 2 {
-		return nil // Invalid format
-	}
-
-	session.Rig = parts[0]
-	remainder := parts[1]
-
-	// Check for crew: gt-<rig>-crew-<name>
-	if strings.HasPrefix(remainder, "crew-") {
-		session.Type = AgentCrew
-		session.AgentName = strings.TrimPrefix(remainder, "crew-")
-		return session
-	}
-
-	// Check for other agent types
-	switch remainder {
-	case "witness":
-		session.Type = AgentWitness
-		return session
-	case "refinery":
-		session.Type = AgentRefinery
-		return session
+	identity, err := scheme.Parse(name)
+	if err != nil {
+		return nil
 	}
-
-	// Everything else is a polecat
-	session.Type = AgentPolecat
-	session.AgentName = remainder
-	return session
+	agent.Rig = identity.Rig
+	agent.AgentName = identity.Name
+
+	switch identity.Role {
+	case session.RoleMayor:
+		agent.Type = AgentMayor
+	case session.RoleDeacon:
+		agent.Type = AgentDeacon
+	case session.RoleWitness:
+		agent.Type = AgentWitness
+	case session.RoleRefinery:
+		agent.Type = AgentRefinery
+	case session.RoleCrew:
+		agent.Type = AgentCrew
+	default:
+		agent.Type = AgentPolecat
+	}
+	return agent
 }
 
 // getAgentSessions returns all categorized Gas Town sessions.
@@ -502,7 +478,7 @@ func buildCollisionReport(townRoot string) (*CollisionReport, error) {
 	// Filter to gt- sessions
 	var gtSessions []string
 	for _, s := range sessions {
-		if strings.HasPrefix(s, "gt-") {
+		if session.HasRigPrefix(s) {
 			gtSessions = append(gtSessions, s)
 		}
 	}
@@ -574,9 +550,9 @@ func guessSessionFromWorkerDir(workerDir, townRoot string) string {
 
 	switch workerType {
 	case "crew":
-		return fmt.Sprintf("gt-%s-crew-%s", rig, workerName)
+		return session.CrewSessionName(rig, workerName)
 	case "polecats":
-		return fmt.Sprintf("gt-%s-%s", rig, workerName)
+		return session.PolecatSessionName(rig, workerName)
 	}
 
 	return ""
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/concurrency"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/townspec"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+const townSpecHelp = `The spec (town.yaml in the town root by default) declares the rigs the
+town has, the agents to run, each rig's polecat cap and budget, the
+scheduled jobs, and the integrations. Whatever it leaves out is left as it
+is: rigs it doesn't name are never removed, and a roles, polecats, budget,
+or schedules key that's omitted isn't managed.
+
+Example town.yaml:
+  roles: [deacon, mayor]           # town agents to run; others are stopped
+  rigs:
+    gastown:
+      git_url: https://github.com/steveyegge/gastown
+      roles: [witness, refinery]   # rig agents to run; others are stopped
+      polecats: 6                  # cap on the rig's worker sessions
+      budget: {daily_usd: 50, weekly_usd: 250}
+    legacy:
+      git_url: https://github.com/acme/legacy
+      parked: true
+  schedules:                       # replaces settings/schedules.json's jobs
+    - {name: standup, cron: "0 9 * * 1-5", action: mail, target: mayor/, subject: Standup}
+  integrations:                    # replaces these gastown.toml tables
+    jira:
+      url: https://acme.atlassian.net
+      token: secret://jira-token`
+
+var planCmd = &cobra.Command{
+	Use:     "plan [town.yaml]",
+	GroupID: GroupConfig,
+	Short:   "Show what gt apply would change to match the town spec",
+	Long: `Compare the town with its declarative spec and show the changes gt apply
+would make, without making them:
+  +  a rig added, an agent started, a setting added
+  -  a rig parked, an agent stopped, a scheduled job removed
+  ~  a setting changed
+  !  a difference apply can't make (e.g., a rig's git URL)
+
+` + townSpecHelp,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPlan,
+}
+
+var applyCmd = &cobra.Command{
+	Use:     "apply [town.yaml]",
+	GroupID: GroupConfig,
+	Short:   "Converge the town on its declarative spec",
+	Long: `Make the town match its declarative spec: add rigs (gt rig add), write
+polecat caps, budgets, schedules, and integrations to the town's settings,
+park and unpark rigs, and stop and start agents. The changes are printed
+first, as gt plan shows them.
+
+Applying stops at the first command that fails; fix it and apply again to
+make the rest. gastown.toml is rewritten when an integration changes, which
+drops its comments.
+
+` + townSpecHelp,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runApply,
+}
+
+func init() {
+	rootCmd.AddCommand(planCmd)
+	rootCmd.AddCommand(applyCmd)
+}
+
+// townPlan loads the town spec (args[0], or town.yaml in the town root)
+// and diffs the town against it.
+func townPlan(args []string) (string, *townspec.Spec, []townspec.Change, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	path := filepath.Join(townRoot, townspec.DefaultFile)
+	if len(args) > 0 {
+		path = args[0]
+	}
+	spec, err := townspec.Load(path)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	state, err := townState(townRoot)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return townRoot, spec, townspec.Diff(spec, state), nil
+}
+
+// townState returns the parts of the town a spec declares, as they are.
+func townState(townRoot string) (*townspec.State, error) {
+	state := &townspec.State{Running: make(map[string]bool), Rigs: make(map[string]*townspec.RigState)}
+	t := tmux.NewTmux()
+	running := func(name string) bool {
+		ok, _ := t.HasSession(name)
+		return ok
+	}
+	state.Running["mayor"] = running(getMayorSessionName())
+	state.Running["deacon"] = running(getDeaconSessionName())
+
+	rigs, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		return nil, err
+	}
+	limits, _, err := concurrency.Limits(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if rigs != nil {
+		for name, entry := range rigs.Rigs {
+			rs := &townspec.RigState{GitURL: entry.GitURL, Parked: IsRigParked(townRoot, name)}
+			if limits != nil {
+				rs.Polecats = limits.Rigs[name]
+			}
+			settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, name)))
+			if err != nil && !errors.Is(err, config.ErrNotFound) {
+				return nil, fmt.Errorf("rig %s: %w", name, err)
+			}
+			if settings != nil {
+				rs.Budget = settings.Budget
+			}
+			state.Rigs[name] = rs
+			state.Running[name+"/witness"] = running(session.WitnessSessionName(name))
+			state.Running[name+"/refinery"] = running(session.RefinerySessionName(name))
+		}
+	}
+
+	schedules, err := config.LoadOrCreateSchedulesConfig(config.SchedulesConfigPath(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	state.Schedules = schedules.Jobs
+	if state.Integrations, err = townspec.ReadGastownTOML(townRoot); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// printPlan prints the changes as gt plan shows them.
+func printPlan(changes []townspec.Change) {
+	for _, c := range changes {
+		switch c.Op {
+		case townspec.OpAdd:
+			fmt.Println(style.Success.Render(c.String()))
+		case townspec.OpRemove, townspec.OpConflict:
+			fmt.Println(style.Error.Render(c.String()))
+		default:
+			fmt.Println(c.String())
+		}
+	}
+}
+
+// conflicts counts the changes apply can't make.
+func conflicts(changes []townspec.Change) int {
+	n := 0
+	for _, c := range changes {
+		if c.Op == townspec.OpConflict {
+			n++
+		}
+	}
+	return n
+}
+
+func runPlan(cmd *cobra.Command, args []string) error {
+	_, _, changes, err := townPlan(args)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Println("The town matches its spec")
+		return nil
+	}
+	printPlan(changes)
+	fmt.Printf("\n%d change(s)", len(changes))
+	if n := conflicts(changes); n > 0 {
+		fmt.Printf(", %d to make by hand", n)
+	}
+	fmt.Println()
+	return nil
+}
+
+func runApply(cmd *cobra.Command, args []string) error {
+	townRoot, spec, changes, err := townPlan(args)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Println("The town matches its spec")
+		return nil
+	}
+	printPlan(changes)
+	fmt.Println()
+
+	gtPath, err := os.Executable()
+	if err != nil {
+		gtPath = "gt"
+	}
+	wrote := false
+	for _, c := range changes {
+		switch {
+		case c.Op == townspec.OpConflict:
+		case c.Settings():
+			if wrote {
+				continue
+			}
+			if err := townspec.Write(townRoot, spec); err != nil {
+				return fmt.Errorf("writing settings: %w", err)
+			}
+			wrote = true
+			fmt.Printf("%s Wrote settings\n", style.Success.Render("✓"))
+		default:
+			run := exec.Command(gtPath, c.Command...)
+			run.Dir = townRoot
+			run.Env = append(os.Environ(), telemetry.EnvList(cmd.Context())...)
+			run.Stdout = os.Stdout
+			run.Stderr = os.Stderr
+			if err := run.Run(); err != nil {
+				return fmt.Errorf("gt %s: %w", strings.Join(c.Command, " "), err)
+			}
+			fmt.Printf("%s %s\n", style.Success.Render("✓"), c)
+		}
+	}
+	if n := conflicts(changes); n > 0 {
+		return fmt.Errorf("%d difference(s) must be changed by hand (marked !)", n)
+	}
+	return nil
+}
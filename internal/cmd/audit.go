@@ -399,42 +399,21 @@ func formatTownlogSummary(e townlog.Event) string {
 	}
 }
 
-// collectFeedEvents queries the activity feed for events.
+// collectFeedEvents queries the town's event log.
 func collectFeedEvents(townRoot, actor string, since time.Time) ([]AuditEntry, error) {
-	var entries []AuditEntry
-
-	eventsPath := filepath.Join(townRoot, events.EventsFile)
-	file, err := os.Open(eventsPath)
+	found, err := events.Query(townRoot, events.Filter{Since: since})
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil // No events file yet
-		}
 		return nil, err
 	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		var e events.Event
-		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
-			continue // Skip malformed lines
-		}
 
+	var entries []AuditEntry
+	for _, e := range found {
 		// Apply actor filter
 		if actor != "" && !matchesActor(e.Actor, actor) {
 			continue
 		}
-
-		// Parse timestamp
-		ts, _ := time.Parse(time.RFC3339, e.Timestamp)
-
-		// Apply since filter
-		if !since.IsZero() && ts.Before(since) {
-			continue
-		}
-
 		entries = append(entries, AuditEntry{
-			Timestamp: ts,
+			Timestamp: e.Time(),
 			Source:    "events",
 			Type:      e.Type,
 			Actor:     e.Actor,
@@ -475,6 +454,50 @@ func formatFeedSummary(e events.Event) string {
 			return fmt.Sprintf("Sent mail to %s", to)
 		}
 		return "Sent mail"
+	case events.TypeToolExec:
+		if tool, ok := e.Payload["tool"].(string); ok {
+			return fmt.Sprintf("Ran %s", tool)
+		}
+		return "Ran a tool"
+	case events.TypePolicyDenied:
+		if tool, ok := e.Payload["tool"].(string); ok {
+			return fmt.Sprintf("Blocked %s by policy", tool)
+		}
+		return "Blocked a tool by policy"
+	case events.TypeLoopDetected:
+		if session, ok := e.Payload["session"].(string); ok {
+			return fmt.Sprintf("Paused %s as a runaway loop", session)
+		}
+		return "Paused a runaway loop"
+	case events.TypeLoopResumed:
+		if session, ok := e.Payload["session"].(string); ok {
+			return fmt.Sprintf("Resumed %s", session)
+		}
+		return "Resumed a paused session"
+	case events.TypeCostAnomaly:
+		if rig, ok := e.Payload["rig"].(string); ok {
+			return fmt.Sprintf("Alerted %s's hourly spend", rig)
+		}
+		return "Alerted an hourly spend anomaly"
+	case events.TypePromptSent:
+		if session, ok := e.Payload["session"].(string); ok {
+			return fmt.Sprintf("Prompted %s", session)
+		}
+		return "Sent a prompt"
+	case events.TypeAgentStarted, events.TypeAgentStopped:
+		verb := "Started"
+		if e.Type == events.TypeAgentStopped {
+			verb = "Stopped"
+		}
+		if session, ok := e.Payload["session"].(string); ok {
+			return fmt.Sprintf("%s %s", verb, session)
+		}
+		return verb + " session"
+	case events.TypeConfigChange:
+		if key, ok := e.Payload["key"].(string); ok {
+			return fmt.Sprintf("Changed %s", key)
+		}
+		return "Changed config"
 	default:
 		return e.Type
 	}
@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/outcome"
+	"github.com/steveyegge/gastown/internal/sla"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beadListJSON     bool
+	beadListRig      string
+	beadListStatus   string
+	beadListPriority int
+	beadListAging    bool
+)
+
+var beadCmd = &cobra.Command{
+	Use:     "bead",
+	GroupID: GroupWork,
+	Short:   "Work with beads across town",
+	RunE:    requireSubcommand,
+}
+
+var beadListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List work beads with age and SLA indicators",
+	Long: `List work beads from town and rig beads, oldest first within each priority.
+
+The SLA column shows how each bead stands against its priority's SLA
+(configured under "sla" in settings/config.json):
+  ✓ due 5h        within its deadlines
+  ⚠ start in 10m  over 75% of a deadline elapsed
+  ✗ start +20m    not started in time
+  ✗ overdue +2h   not finished in time
+
+Infrastructure beads (agents, merge requests, escalations, mail) are omitted.
+
+Examples:
+  gt bead list                    # Open work in town and all rigs
+  gt bead list --rig gastown -p 1
+  gt bead list --aging            # Only beads near or past their SLA
+  gt bead list --status all --json`,
+	RunE: runBeadList,
+}
+
+func init() {
+	beadListCmd.Flags().BoolVar(&beadListJSON, "json", false, "Output as JSON")
+	beadListCmd.Flags().StringVar(&beadListRig, "rig", "", "Only beads in this rig")
+	beadListCmd.Flags().StringVar(&beadListStatus, "status", "", "Status filter passed to bd (default: not closed)")
+	beadListCmd.Flags().IntVarP(&beadListPriority, "priority", "p", -1, "Only beads of this priority (0-4)")
+	beadListCmd.Flags().BoolVar(&beadListAging, "aging", false, "Only beads near or past their SLA")
+	beadCmd.AddCommand(beadListCmd)
+
+	rootCmd.AddCommand(beadCmd)
+}
+
+// slaBead is a work bead with its SLA evaluation.
+type slaBead struct {
+	*beads.Issue
+	Rig string      `json:"rig,omitempty"`
+	SLA *sla.Status `json:"sla"`
+}
+
+func runBeadList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	list, err := collectSLABeads(townRoot, beadListRig, beads.ListOptions{
+		Status:   beadListStatus,
+		Priority: beadListPriority,
+	}, time.Now())
+	if err != nil {
+		return err
+	}
+	if beadListAging {
+		aging := make([]*slaBead, 0, len(list))
+		for _, b := range list {
+			if b.SLA.State == sla.StateWarning || b.SLA.Breached() {
+				aging = append(aging, b)
+			}
+		}
+		list = aging
+	}
+
+	if beadListJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list)
+	}
+
+	if len(list) == 0 {
+		fmt.Printf("%s No beads found\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tP\tSTATUS\tAGE\tSLA\tTITLE")
+	for _, b := range list {
+		age := "-"
+		if b.SLA.Age > 0 {
+			age = formatWorkerAge(b.SLA.Age)
+		}
+		_, _ = fmt.Fprintf(w, "%s\tP%d\t%s\t%s\t%s\t%s\n",
+			b.ID, b.Priority, b.Status, age, renderSLAIndicator(b.SLA, now), b.Title)
+	}
+	return w.Flush()
+}
+
+// collectSLABeads lists work beads from town beads and each rig's beads and
+// evaluates them against the town's SLA config. rigFilter limits the listing
+// to one rig. Results are sorted by priority, then oldest first.
+func collectSLABeads(townRoot, rigFilter string, opts beads.ListOptions, now time.Time) ([]*slaBead, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	starts := assignmentStarts(townRoot)
+
+	type source struct {
+		rig       string
+		beadsPath string
+	}
+	var sources []source
+	if rigFilter == "" {
+		sources = append(sources, source{"", beads.GetTownBeadsPath(townRoot)})
+	}
+	rigNames := discoverRigs(townRoot)
+	sort.Strings(rigNames)
+	found := false
+	for _, name := range rigNames {
+		if rigFilter != "" && name != rigFilter {
+			continue
+		}
+		found = true
+		sources = append(sources, source{name, constants.RigMayorPath(filepath.Join(townRoot, name))})
+	}
+	if rigFilter != "" && !found {
+		return nil, fmt.Errorf("rig not found: %s", rigFilter)
+	}
+
+	result := make([]*slaBead, 0)
+	for _, src := range sources {
+		issues, err := beads.New(src.beadsPath).List(opts)
+		if err != nil {
+			name := src.rig
+			if name == "" {
+				name = "town"
+			}
+			style.PrintWarning("listing %s beads: %v", name, err)
+			continue
+		}
+		for _, issue := range issues {
+			if !isWorkBead(issue) {
+				continue
+			}
+			created, _ := time.Parse(time.RFC3339, issue.CreatedAt)
+			result = append(result, &slaBead{
+				Issue: issue,
+				Rig:   src.rig,
+				SLA: sla.Evaluate(settings.SLA, sla.Bead{
+					ID:       issue.ID,
+					Rig:      src.rig,
+					Priority: issue.Priority,
+					Status:   issue.Status,
+					Created:  created,
+					Started:  starts[issue.ID],
+				}, now),
+			})
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Priority != result[j].Priority {
+			return result[i].Priority < result[j].Priority
+		}
+		return result[i].SLA.Age > result[j].SLA.Age
+	})
+	return result, nil
+}
+
+// assignmentStarts maps bead IDs to when they were first slung, from the
+// assignment outcome log. Best effort: a missing log yields an empty map.
+func assignmentStarts(townRoot string) map[string]time.Time {
+	starts := make(map[string]time.Time)
+	outcomes, _ := outcome.Load(townRoot)
+	for _, o := range outcomes {
+		if o.AssignedAt.IsZero() {
+			continue
+		}
+		if t, ok := starts[o.Bead]; !ok || o.AssignedAt.Before(t) {
+			starts[o.Bead] = o.AssignedAt
+		}
+	}
+	return starts
+}
+
+// isWorkBead reports whether an issue is work rather than Gas Town
+// infrastructure (agents, merge requests, escalations, mail, molecules).
+func isWorkBead(issue *beads.Issue) bool {
+	switch issue.Type {
+	case "agent", "molecule", "message", "convoy", "event":
+		return false
+	}
+	for _, label := range issue.Labels {
+		if strings.HasPrefix(label, "gt:") {
+			return false
+		}
+	}
+	return true
+}
+
+// renderSLAIndicator renders a bead's SLA state for the SLA column.
+func renderSLAIndicator(s *sla.Status, now time.Time) string {
+	switch s.State {
+	case sla.StateOK, sla.StateWarning:
+		var text string
+		switch {
+		case s.StartDue != nil:
+			text = "start in " + formatWorkerAge(s.StartDue.Sub(now))
+		case s.FinishDue != nil:
+			text = "due " + formatWorkerAge(s.FinishDue.Sub(now))
+		}
+		if s.State == sla.StateWarning {
+			return style.Warning.Render("⚠ " + text)
+		}
+		return style.Dim.Render("✓ " + text)
+	case sla.StateStartBreached:
+		return style.Error.Render("✗ start +" + formatWorkerAge(now.Sub(*s.StartDue)))
+	case sla.StateFinishBreached:
+		return style.Error.Render("✗ overdue +" + formatWorkerAge(now.Sub(*s.FinishDue)))
+	}
+	return style.Dim.Render("-")
+}
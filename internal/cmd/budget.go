@@ -0,0 +1,551 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/budget"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/outcome"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	budgetJSON        bool
+	budgetCheckDryRun bool
+	budgetOverrideFor time.Duration
+	budgetOverrideWhy string
+)
+
+var budgetCmd = &cobra.Command{
+	Use:     "budget",
+	GroupID: GroupServices,
+	Short:   "Show and enforce per-rig spend budgets",
+	Long: `Show and enforce per-rig spend budgets.
+
+Budgets are configured per rig in <rig>/settings/config.json:
+
+  "budget": {
+    "daily_usd": 50,
+    "weekly_usd": 250,
+    "daily_tokens": 2000000,
+    "weekly_tokens": 10000000,
+    "actions": ["block_spawn", "pause_sessions", "alert"]
+  }
+
+Spend is rolled up per rig from the same sources as 'gt costs': today's
+session cost records, daily cost digests, and live cost from running sessions.
+Tokens come from the sessions' recorded cost snapshots (the outcome log)
+and the token counts running sessions display.
+
+When a rig is over budget:
+  block_spawn     gt sling refuses to spawn new polecats in the rig
+  pause_sessions  polecat sessions with no assigned work are stopped
+  alert           the mayor is mailed once per exceeded window per day
+
+Humans can suspend enforcement with 'gt budget override'.
+
+Independently of budgets, every rig's hourly spend is compared with an
+absolute cap and with the rig's own baseline, and anomalies are posted to
+webhooks or Slack (see 'gt budget hourly').
+
+Examples:
+  gt budget                              # Budget status for all rigs
+  gt budget status gastown --json        # One rig, as JSON
+  gt budget check                        # Enforce budgets (run by the daemon)
+  gt budget hourly                       # Last hour's spend vs. baseline
+  gt budget override gastown --for 4h -r "release crunch"
+  gt budget clear-override gastown`,
+	RunE: runBudgetStatus,
+}
+
+var budgetStatusCmd = &cobra.Command{
+	Use:   "status [rig]",
+	Short: "Show budget status for rigs",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runBudgetStatus,
+}
+
+var budgetCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Enforce budgets: pause sessions and alert for over-budget rigs",
+	Long: `Evaluate every rig's budget and apply enforcement actions.
+
+Spawn blocking is enforced by gt sling itself; this command handles the
+pause_sessions and alert actions. It also records every rig's hourly spend
+and alerts on anomalies (see gt budget hourly). The daemon runs it on every
+heartbeat.`,
+	RunE: runBudgetCheck,
+}
+
+var budgetOverrideCmd = &cobra.Command{
+	Use:   "override <rig>",
+	Short: "Suspend budget enforcement for a rig",
+	Long: `Suspend budget enforcement for a rig for a limited time.
+
+While the override is active, the rig can spawn polecats and its sessions
+are not paused, even if spend exceeds the configured caps.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBudgetOverride,
+}
+
+var budgetClearOverrideCmd = &cobra.Command{
+	Use:   "clear-override <rig>",
+	Short: "Remove a budget override and resume enforcement",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runBudgetClearOverride,
+}
+
+func init() {
+	rootCmd.AddCommand(budgetCmd)
+	budgetCmd.AddCommand(budgetStatusCmd)
+	budgetCmd.AddCommand(budgetCheckCmd)
+	budgetCmd.AddCommand(budgetOverrideCmd)
+	budgetCmd.AddCommand(budgetClearOverrideCmd)
+
+	budgetCmd.PersistentFlags().BoolVar(&budgetJSON, "json", false, "Output as JSON")
+	budgetCheckCmd.Flags().BoolVar(&budgetCheckDryRun, "dry-run", false, "Report what would be done without making changes")
+	budgetOverrideCmd.Flags().DurationVar(&budgetOverrideFor, "for", 24*time.Hour, "How long the override lasts")
+	budgetOverrideCmd.Flags().StringVarP(&budgetOverrideWhy, "reason", "r", "", "Reason for the override")
+}
+
+// rigBudget pairs a rig with its budget config.
+type rigBudget struct {
+	rig    *rig.Rig
+	config *config.BudgetConfig
+}
+
+// loadRigBudgets returns the rigs in the town that have a budget configured.
+// If only is non-empty, just that rig is returned (even without a budget).
+func loadRigBudgets(townRoot, only string) ([]rigBudget, error) {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+	rigMgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+
+	var names []string
+	if only != "" {
+		names = []string{only}
+	} else {
+		for name := range rigsConfig.Rigs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	var result []rigBudget
+	for _, name := range names {
+		r, err := rigMgr.GetRig(name)
+		if err != nil {
+			if only != "" {
+				return nil, fmt.Errorf("rig '%s' not found", name)
+			}
+			continue
+		}
+		rb := rigBudget{rig: r, config: loadBudgetConfig(r.Path)}
+		if rb.config == nil && only == "" {
+			continue
+		}
+		result = append(result, rb)
+	}
+	return result, nil
+}
+
+// loadBudgetConfig returns the rig's budget config, falling back to the
+// budget in the rig's or town's gastown.toml, or nil if none is set.
+func loadBudgetConfig(rigPath string) *config.BudgetConfig {
+	if settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath)); err == nil && settings.Budget != nil {
+		return settings.Budget
+	}
+	if gtConfig, err := config.LoadRigGastownConfig(filepath.Dir(rigPath), rigPath); err == nil {
+		return gtConfig.Budget
+	}
+	return nil
+}
+
+// rigSpendCache memoizes the spend rollup for the lifetime of one command,
+// so batch slings do not re-query the ledger for every spawn.
+var rigSpendCache map[string]budget.Spend
+
+// rollupRigSpend totals spend per rig for today and the last 7 days.
+// Dollar sources match gt costs: today's session cost wisps (ended
+// sessions), daily digest beads (previous days), and live cost from running
+// sessions. Tokens come from the outcome log's cost snapshots (ended
+// sessions) and the running sessions' panes.
+func rollupRigSpend() map[string]budget.Spend {
+	if rigSpendCache != nil {
+		return rigSpendCache
+	}
+
+	spend := make(map[string]budget.Spend)
+	add := func(rigName string, daily, weekly float64) {
+		if rigName == "" {
+			return
+		}
+		s := spend[rigName]
+		s.Daily += daily
+		s.Weekly += weekly
+		spend[rigName] = s
+	}
+	addTokens := func(rigName string, daily, weekly int) {
+		if rigName == "" {
+			return
+		}
+		s := spend[rigName]
+		s.DailyTokens += daily
+		s.WeeklyTokens += weekly
+		spend[rigName] = s
+	}
+
+	now := time.Now()
+	today, _ := querySessionCostWisps(now)
+	for _, e := range today {
+		add(e.Rig, e.CostUSD, e.CostUSD)
+	}
+	digests, _ := queryDigestBeads(6) // previous 6 days; today is not digested yet
+	for _, e := range digests {
+		add(e.Rig, 0, e.CostUSD)
+	}
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		if updates, err := outcome.ReadUpdates(townRoot); err == nil {
+			for rigName, s := range recordedTokens(updates, now) {
+				addTokens(rigName, s.DailyTokens, s.WeeklyTokens)
+			}
+		}
+	}
+
+	t := tmux.NewTmux()
+	sessions, _ := t.ListSessions()
+	for _, sess := range sessions {
+		if !session.HasRigPrefix(sess) {
+			continue
+		}
+		_, rigName, _ := parseSessionName(sess)
+		if rigName == "" {
+			continue
+		}
+		content, err := t.CapturePaneAll(sess)
+		if err != nil {
+			continue
+		}
+		cost := extractCost(content)
+		add(rigName, cost, cost)
+		tokens := extractTokens(content)
+		addTokens(rigName, tokens, tokens)
+	}
+
+	rigSpendCache = spend
+	return spend
+}
+
+// recordedTokens totals the tokens of recorded sessions per rig, for today
+// and the last 7 days (today and the 6 before, as for dollars). Each
+// session counts once, at its latest cost snapshot in the outcome log.
+func recordedTokens(updates []outcome.Update, now time.Time) map[string]budget.Spend {
+	latest := make(map[string]outcome.Update)
+	for _, u := range updates {
+		if u.Kind != outcome.KindCost || u.Session == "" || u.Tokens == 0 {
+			continue
+		}
+		if prev, ok := latest[u.Session]; !ok || !u.Time.Before(prev.Time) {
+			latest[u.Session] = u
+		}
+	}
+
+	today := now.Format("2006-01-02")
+	weekStart := now.AddDate(0, 0, -6).Format("2006-01-02")
+	spend := make(map[string]budget.Spend)
+	for _, u := range latest {
+		rigName := u.Rig
+		if rigName == "" {
+			// Agent addresses are rig/role/name; town agents have no rig
+			if r, rest, ok := strings.Cut(u.Agent, "/"); ok && rest != "" {
+				rigName = r
+			}
+		}
+		day := u.Time.Local().Format("2006-01-02")
+		if rigName == "" || day < weekStart || day > today {
+			continue
+		}
+		s := spend[rigName]
+		s.WeeklyTokens += u.Tokens
+		if day == today {
+			s.DailyTokens += u.Tokens
+		}
+		spend[rigName] = s
+	}
+	return spend
+}
+
+// evaluateRigBudget evaluates one rig's budget against current spend.
+func evaluateRigBudget(townRoot string, rb rigBudget) *budget.Status {
+	spend := rollupRigSpend()[rb.rig.Name]
+	return budget.Evaluate(rb.rig.Name, rb.config, spend, budget.GetOverride(townRoot, rb.rig.Name), time.Now())
+}
+
+// checkSpawnBudget returns an error if the rig's budget blocks new polecats.
+// Called by gt sling before spawning.
+func checkSpawnBudget(townRoot string, r *rig.Rig) error {
+	cfg := loadBudgetConfig(r.Path)
+	if cfg == nil || !cfg.HasAction(config.BudgetActionBlockSpawn) {
+		return nil
+	}
+	status := evaluateRigBudget(townRoot, rigBudget{rig: r, config: cfg})
+	if !status.Enforced {
+		return nil
+	}
+	return fmt.Errorf("rig '%s' is over budget (%s): not spawning new polecats\nOverride with: gt budget override %s --for 4h",
+		r.Name, status.Summary(), r.Name)
+}
+
+func runBudgetStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	only := ""
+	if len(args) > 0 {
+		only = args[0]
+	}
+	rigs, err := loadRigBudgets(townRoot, only)
+	if err != nil {
+		return err
+	}
+
+	statuses := make([]*budget.Status, 0, len(rigs))
+	for _, rb := range rigs {
+		statuses = append(statuses, evaluateRigBudget(townRoot, rb))
+	}
+
+	if budgetJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(statuses)
+	}
+
+	if len(statuses) == 0 {
+		fmt.Printf("%s No rig budgets configured\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	for _, s := range statuses {
+		icon := style.Success.Render("✓")
+		state := "within budget"
+		switch {
+		case s.Enforced:
+			icon = style.Error.Render("✗")
+			state = "OVER BUDGET: " + s.Summary()
+		case s.OverBudget():
+			icon = style.Warning.Render("!")
+			state = "over budget (overridden): " + s.Summary()
+		}
+		fmt.Printf("%s %s  %s\n", icon, style.Bold.Render(s.Rig), state)
+		fmt.Printf("    today: $%.2f%s  week: $%.2f%s\n",
+			s.Spend.Daily, formatCap(s.DailyUSD), s.Spend.Weekly, formatCap(s.WeeklyUSD))
+		if s.DailyTokens > 0 || s.WeeklyTokens > 0 {
+			fmt.Printf("    tokens today: %s%s  week: %s%s\n",
+				budget.FormatTokens(s.Spend.DailyTokens), formatTokenCap(s.DailyTokens),
+				budget.FormatTokens(s.Spend.WeeklyTokens), formatTokenCap(s.WeeklyTokens))
+		}
+		if s.Override != nil {
+			fmt.Printf("    override until %s", s.Override.Until.Local().Format("2006-01-02 15:04"))
+			if s.Override.Reason != "" {
+				fmt.Printf(" (%s)", s.Override.Reason)
+			}
+			fmt.Println()
+		}
+	}
+	return nil
+}
+
+// formatCap formats a budget cap for display (" / $50.00"), or "" if unset.
+func formatCap(usd float64) string {
+	if usd <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" / $%.2f", usd)
+}
+
+// formatTokenCap formats a token cap for display (" / 500k"), or "" if unset.
+func formatTokenCap(tokens int) string {
+	if tokens <= 0 {
+		return ""
+	}
+	return " / " + budget.FormatTokens(tokens)
+}
+
+func runBudgetCheck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	rigs, err := loadRigBudgets(townRoot, "")
+	if err != nil {
+		return err
+	}
+
+	checkCostAnomalies(townRoot, budgetCheckDryRun)
+
+	now := time.Now()
+	for _, rb := range rigs {
+		status := evaluateRigBudget(townRoot, rb)
+		if !status.Enforced {
+			continue
+		}
+		fmt.Printf("%s %s over budget: %s\n", style.Error.Render("✗"), rb.rig.Name, status.Summary())
+
+		if rb.config.HasAction(config.BudgetActionPauseSessions) {
+			pauseNonessentialSessions(rb.rig, budgetCheckDryRun)
+		}
+
+		if rb.config.HasAction(config.BudgetActionAlert) && budget.ShouldAlert(townRoot, status, now) {
+			if budgetCheckDryRun {
+				fmt.Printf("  Would alert mayor\n")
+				continue
+			}
+			if err := sendBudgetAlert(townRoot, status, rb.config); err != nil {
+				style.PrintWarning("could not alert mayor: %v", err)
+				continue
+			}
+			_ = budget.MarkAlerted(townRoot, status, now)
+			_ = events.LogFeed(events.TypeBudgetExceeded, "daemon",
+				events.BudgetExceededPayload(rb.rig.Name, status.Summary(), budgetActions(rb.config)))
+			fmt.Printf("  %s Alerted mayor\n", style.Bold.Render("✓"))
+		}
+	}
+	return nil
+}
+
+// budgetActions returns the enforcement actions a budget applies.
+func budgetActions(cfg *config.BudgetConfig) []string {
+	var actions []string
+	for _, action := range []string{config.BudgetActionBlockSpawn, config.BudgetActionPauseSessions, config.BudgetActionAlert} {
+		if cfg.HasAction(action) {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// pauseNonessentialSessions stops polecat sessions in the rig that have no
+// assigned work. Working polecats, crew, the witness, and the refinery are
+// left running so in-flight work can land.
+func pauseNonessentialSessions(r *rig.Rig, dryRun bool) {
+	t := tmux.NewTmux()
+	polecatMgr := polecat.NewManager(r, git.NewGit(r.Path), t)
+	sessMgr := polecat.NewSessionManager(t, r)
+
+	polecats, err := polecatMgr.List()
+	if err != nil {
+		style.PrintWarning("listing polecats in %s: %v", r.Name, err)
+		return
+	}
+	for _, p := range polecats {
+		if p.Issue != "" {
+			continue
+		}
+		running, _ := sessMgr.IsRunning(p.Name)
+		if !running {
+			continue
+		}
+		if dryRun {
+			fmt.Printf("  Would stop %s (no assigned work)\n", sessMgr.SessionName(p.Name))
+			continue
+		}
+		if err := sessMgr.Stop(p.Name, false); err != nil {
+			style.PrintWarning("stopping %s: %v", sessMgr.SessionName(p.Name), err)
+			continue
+		}
+		fmt.Printf("  %s Stopped %s (no assigned work)\n", style.Bold.Render("✓"), sessMgr.SessionName(p.Name))
+	}
+}
+
+// sendBudgetAlert mails the mayor that a rig exceeded its budget.
+func sendBudgetAlert(townRoot string, status *budget.Status, cfg *config.BudgetConfig) error {
+	var enforced []string
+	for _, action := range []string{config.BudgetActionBlockSpawn, config.BudgetActionPauseSessions} {
+		if cfg.HasAction(action) {
+			enforced = append(enforced, action)
+		}
+	}
+
+	body := fmt.Sprintf(`Rig %s exceeded its budget: %s
+
+Today: $%.2f%s, %s tokens%s
+Week:  $%.2f%s, %s tokens%s
+Enforcement: %s
+
+To let work continue, a human can run:
+  gt budget override %s --for 4h -r "<reason>"`,
+		status.Rig, status.Summary(),
+		status.Spend.Daily, formatCap(status.DailyUSD),
+		budget.FormatTokens(status.Spend.DailyTokens), formatTokenCap(status.DailyTokens),
+		status.Spend.Weekly, formatCap(status.WeeklyUSD),
+		budget.FormatTokens(status.Spend.WeeklyTokens), formatTokenCap(status.WeeklyTokens),
+		strings.Join(enforced, ", "), status.Rig)
+
+	router := mail.NewRouter(townRoot)
+	return router.Send(&mail.Message{
+		From:     "deacon/",
+		To:       "mayor/",
+		Subject:  fmt.Sprintf("BUDGET_EXCEEDED %s", status.Rig),
+		Body:     body,
+		Priority: mail.PriorityHigh,
+	})
+}
+
+func runBudgetOverride(cmd *cobra.Command, args []string) error {
+	townRoot, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	if budgetOverrideFor <= 0 {
+		return fmt.Errorf("--for must be positive")
+	}
+
+	o := budget.Override{
+		Until:  time.Now().Add(budgetOverrideFor),
+		Reason: budgetOverrideWhy,
+		By:     detectSender(),
+	}
+	if err := budget.SetOverride(townRoot, r.Name, o); err != nil {
+		return fmt.Errorf("setting override: %w", err)
+	}
+
+	fmt.Printf("%s Budget enforcement suspended for %s until %s\n",
+		style.Success.Render("✓"), r.Name, o.Until.Local().Format("2006-01-02 15:04"))
+	if loadBudgetConfig(r.Path) == nil {
+		fmt.Printf("  %s\n", style.Dim.Render("Note: this rig has no budget configured"))
+	}
+	return nil
+}
+
+func runBudgetClearOverride(cmd *cobra.Command, args []string) error {
+	townRoot, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	if err := budget.ClearOverride(townRoot, r.Name); err != nil {
+		return fmt.Errorf("clearing override: %w", err)
+	}
+	fmt.Printf("%s Budget enforcement resumed for %s\n", style.Success.Render("✓"), r.Name)
+	return nil
+}
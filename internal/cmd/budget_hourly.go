@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/costwatch"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var budgetHourlyCmd = &cobra.Command{
+	Use:   "hourly",
+	Short: "Show each rig's spend over the last hour against its baseline",
+	Long: `Show each rig's spend over the last hour against its baseline: the
+mean of its past hours.
+
+gt budget check records each rig's spend on every daemon heartbeat and
+alerts when the last hour crosses a threshold:
+  hourly_cap  the rig spent more than cost_alerts.hourly_usd in an hour
+  spike       the rig spent over spike_factor times its baseline (and at
+              least min_spike_usd)
+
+Alerts are posted to the webhooks and recorded as cost_anomaly events (they
+show in gt feed), once per rig and kind per clock hour. With no webhook
+configured, the mayor is mailed instead.
+
+Thresholds are set in gastown.toml (town file only):
+  [cost_alerts]
+  hourly_usd = 20          # 0 = no absolute cap
+  spike_factor = 3         # 0 = no spike alerts
+  min_spike_usd = 1
+  baseline_hours = 24
+  webhook_url = "secret://cost-webhook"   # JSON POST
+  slack_webhook = "${SLACK_WEBHOOK}"      # default: contacts.slack_webhook in settings/escalation.json`,
+	RunE: runBudgetHourly,
+}
+
+func init() {
+	budgetCmd.AddCommand(budgetHourlyCmd)
+}
+
+// rigHourly is one rig's row in gt budget hourly.
+type rigHourly struct {
+	Rig         string  `json:"rig"`
+	HourUSD     float64 `json:"hour_usd"`
+	BaselineUSD float64 `json:"baseline_usd,omitempty"`
+	HasBaseline bool    `json:"has_baseline"`
+}
+
+// costAlertsConfig returns the town's cost alert settings.
+func costAlertsConfig(townRoot string) config.CostAlertsConfig {
+	cfg, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return config.CostAlertsConfig{}
+	}
+	return cfg.CostAlerts
+}
+
+// townRigNames returns the names of the town's rigs, sorted.
+func townRigNames(townRoot string) []string {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func runBudgetHourly(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	_, _, _, baselineHours := costAlertsConfig(townRoot).Thresholds()
+
+	now := time.Now()
+	rows := []rigHourly{}
+	for _, name := range townRigNames(townRoot) {
+		s, err := costwatch.Load(townRoot, name)
+		if err != nil {
+			continue
+		}
+		row := rigHourly{Rig: name, HourUSD: s.LastHour()}
+		row.BaselineUSD, row.HasBaseline = s.Baseline(now, baselineHours)
+		rows = append(rows, row)
+	}
+
+	if budgetJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+	if len(rows) == 0 {
+		fmt.Printf("%s No rigs\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, r := range rows {
+		baseline := style.Dim.Render("no baseline yet")
+		if r.HasBaseline {
+			baseline = fmt.Sprintf("baseline $%.2f/hour", r.BaselineUSD)
+		}
+		fmt.Printf("%-20s $%7.2f last hour  %s\n", r.Rig, r.HourUSD, baseline)
+	}
+	return nil
+}
+
+// checkCostAnomalies records each rig's spend today and alerts on hourly
+// spend anomalies. Called by gt budget check.
+func checkCostAnomalies(townRoot string, dryRun bool) {
+	cfg := costAlertsConfig(townRoot)
+	spend := rollupRigSpend()
+	now := time.Now()
+	for _, name := range townRigNames(townRoot) {
+		s, err := costwatch.Load(townRoot, name)
+		if err != nil {
+			style.PrintWarning("loading cost history of %s: %v", name, err)
+			continue
+		}
+		s.Record(now, spend[name].Daily)
+		for _, a := range s.Check(now, cfg) {
+			if !s.ShouldAlert(a) {
+				continue
+			}
+			fmt.Printf("%s %s cost anomaly: %s\n", style.Error.Render("✗"), name, a.Summary())
+			if dryRun {
+				fmt.Printf("  Would alert\n")
+				continue
+			}
+			if err := sendCostAlert(townRoot, cfg, a); err != nil {
+				style.PrintWarning("could not deliver cost alert: %v", err)
+				continue
+			}
+			s.MarkAlerted(a)
+			fmt.Printf("  %s Alerted\n", style.Bold.Render("✓"))
+		}
+		if dryRun {
+			continue
+		}
+		if err := s.Save(townRoot); err != nil {
+			style.PrintWarning("saving cost history of %s: %v", name, err)
+		}
+	}
+}
+
+// sendCostAlert records a cost anomaly in the feed and posts it to the
+// configured webhooks, or mails the mayor if there are none. It fails only
+// if every delivery does.
+func sendCostAlert(townRoot string, cfg config.CostAlertsConfig, a *costwatch.Anomaly) error {
+	_ = events.LogFeed(events.TypeCostAnomaly, "daemon",
+		events.CostAnomalyPayload(a.Rig, a.Kind, a.HourUSD, a.Summary()))
+
+	slackField, slackURL := "cost_alerts.slack_webhook", cfg.SlackWebhook
+	if slackURL == "" {
+		esc, err := config.LoadOrCreateEscalationConfig(config.EscalationConfigPath(townRoot))
+		if err != nil {
+			style.PrintWarning("loading escalation config: %v", err)
+		} else {
+			slackField, slackURL = "contacts.slack_webhook", esc.Contacts.SlackWebhook
+		}
+	}
+	targets := []struct {
+		name, url string
+		post      func(context.Context, string, *costwatch.Anomaly) error
+	}{
+		{"cost_alerts.webhook_url", cfg.WebhookURL, costwatch.PostWebhook},
+		{slackField, slackURL, costwatch.PostSlack},
+	}
+
+	var delivered, attempted int
+	var lastErr error
+	for _, t := range targets {
+		if t.url == "" {
+			continue
+		}
+		attempted++
+		url, err := config.ResolveRef(t.name, t.url)
+		if err == nil {
+			if err = t.post(context.Background(), url, a); err != nil {
+				err = fmt.Errorf("%s: %w", t.name, err)
+			}
+		}
+		if err != nil {
+			lastErr = err
+			style.PrintWarning("%v", lastErr)
+			continue
+		}
+		delivered++
+	}
+	if attempted == 0 {
+		return mail.NewRouter(townRoot).Send(&mail.Message{
+			From:     "deacon/",
+			To:       "mayor/",
+			Subject:  fmt.Sprintf("COST_ANOMALY %s", a.Rig),
+			Body:     a.Text() + "\n\nFind the cause with 'gt costs --by-rig' and 'gt loop status'.",
+			Priority: mail.PriorityHigh,
+		})
+	}
+	if delivered == 0 {
+		return lastErr
+	}
+	return nil
+}
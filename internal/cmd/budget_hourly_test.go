@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/costwatch"
+)
+
+func TestSendCostAlert_SlackWebhookRef(t *testing.T) {
+	posted := make(chan struct{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted <- struct{}{}
+	}))
+	defer srv.Close()
+
+	townRoot := t.TempDir()
+	t.Chdir(townRoot) // keep the cost_anomaly event out of the source tree
+	escPath := config.EscalationConfigPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(escPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	esc := `{"type":"escalation","version":1,"contacts":{"slack_webhook":"${GT_TEST_SLACK_WEBHOOK}"}}`
+	if err := os.WriteFile(escPath, []byte(esc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	a := &costwatch.Anomaly{Rig: "gastown", Kind: "spike", HourUSD: 12}
+
+	t.Setenv("GT_TEST_SLACK_WEBHOOK", srv.URL)
+	if err := sendCostAlert(townRoot, config.CostAlertsConfig{}, a); err != nil {
+		t.Fatalf("sendCostAlert: %v", err)
+	}
+	select {
+	case <-posted:
+	default:
+		t.Fatal("contacts.slack_webhook reference was not posted to")
+	}
+
+	os.Unsetenv("GT_TEST_SLACK_WEBHOOK")
+	err := sendCostAlert(townRoot, config.CostAlertsConfig{}, a)
+	if !errors.Is(err, config.ErrUnresolvedRef) {
+		t.Errorf("sendCostAlert with unset webhook ref = %v, want ErrUnresolvedRef", err)
+	}
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/outcome"
+)
+
+func TestRecordedTokens(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.Local)
+	cost := func(at time.Time, agent, session string, tokens int) outcome.Update {
+		return outcome.Update{Time: at, Kind: outcome.KindCost, Agent: agent, Session: session, Tokens: tokens}
+	}
+	updates := []outcome.Update{
+		// Snapshots are cumulative: only the latest per session counts
+		cost(now.Add(-2*time.Hour), "gastown/polecats/nux", "gt-gastown-nux", 1000),
+		cost(now.Add(-time.Hour), "gastown/polecats/nux", "gt-gastown-nux", 3000),
+		cost(now.AddDate(0, 0, -3), "gastown/crew/max", "gt-gastown-crew-max", 5000),
+		cost(now.AddDate(0, 0, -8), "gastown/polecats/old", "gt-gastown-old", 7000),
+		cost(now.Add(-time.Hour), "mayor/", "hq-mayor", 9000),
+		{Time: now, Kind: outcome.KindCost, Rig: "beads", Session: "gt-beads-toast", Tokens: 200},
+	}
+
+	got := recordedTokens(updates, now)
+	if s := got["gastown"]; s.DailyTokens != 3000 || s.WeeklyTokens != 8000 {
+		t.Errorf("gastown tokens = %d/%d, want 3000 today, 8000 this week", s.DailyTokens, s.WeeklyTokens)
+	}
+	if s := got["beads"]; s.DailyTokens != 200 || s.WeeklyTokens != 200 {
+		t.Errorf("beads tokens = %d/%d, want 200/200", s.DailyTokens, s.WeeklyTokens)
+	}
+	if len(got) != 2 {
+		t.Errorf("rigs = %v, want only gastown and beads (town agents have no rig)", got)
+	}
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/review"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// chatCommand is a /gt command run from a chat integration (gt slack, gt
+// discord).
+type chatCommand struct {
+	Via      string // the integration: slack, discord
+	Verb     string // status, prompt, approve, stop
+	Target   string // the agent or MR acted on
+	Text     string // the prompt, or the approval's summary
+	UserID   string
+	UserName string
+}
+
+// chatReply is the reply to a chatCommand. Replies not InChannel are shown
+// only to the user who ran it.
+type chatReply struct {
+	Text      string
+	InChannel bool
+}
+
+// chatHelp lists the /gt command's verbs.
+const chatHelp = "Gas Town commands:\n" +
+	"`/gt status` - the town's health\n" +
+	"`/gt prompt <agent> <text>` - nudge an agent (operators)\n" +
+	"`/gt approve <mr> [message]` - approve an MR under review (operators)\n" +
+	"`/gt stop <agent>` - stop an agent's session (operators)"
+
+// runChatCommand runs a verified /gt command for the town. Control verbs
+// are only for users isOperator accepts under the town's gastown.toml.
+func runChatCommand(townRoot string, c chatCommand, isOperator func(cfg *config.GastownConfig, userID string) bool) chatReply {
+	switch c.Verb {
+	case "status":
+		town, err := collectTownHealth(townRoot)
+		if err != nil {
+			return chatReply{Text: "Couldn't check the town's health: " + err.Error()}
+		}
+		lines := []string{fmt.Sprintf("Town: %s", town.State)}
+		for _, reason := range town.Reasons {
+			lines = append(lines, "• "+reason)
+		}
+		for _, r := range town.Rigs {
+			lines = append(lines, fmt.Sprintf("%s: %s (%d sessions)", r.Name, r.State, len(r.Sessions)))
+		}
+		return chatReply{Text: strings.Join(lines, "\n")}
+	case "prompt", "approve", "stop":
+	default:
+		return chatReply{Text: chatHelp}
+	}
+
+	// Control commands
+	cfg, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return chatReply{Text: "Couldn't load gastown.toml: " + err.Error()}
+	}
+	if !isOperator(cfg, c.UserID) {
+		return chatReply{Text: fmt.Sprintf("Only operators may run `/gt %s`: add your %s user ID (%s) to %s.operators in gastown.toml.", c.Verb, c.Via, c.UserID, c.Via)}
+	}
+	if c.Target == "" || (c.Verb == "prompt" && c.Text == "") {
+		return chatReply{Text: chatHelp}
+	}
+	actor := c.Via + ":" + c.UserName
+	audit := func(target string) {
+		_ = events.LogAudit(events.TypeChatCommand, actor,
+			events.ChatCommandPayload(c.Via, fmt.Sprintf("%s (%s)", c.UserName, c.UserID), c.Verb, target))
+	}
+
+	if c.Verb == "approve" {
+		if err := review.SetVerdict(townRoot, c.Target, actor, review.VerdictApproved, c.Text); err != nil {
+			return chatReply{Text: fmt.Sprintf("Couldn't approve %s: %v", c.Target, err)}
+		}
+		audit(c.Target)
+		return chatReply{Text: fmt.Sprintf("<@%s> approved %s", c.UserID, c.Target), InChannel: true}
+	}
+
+	sessionName := c.Target
+	if strings.Contains(c.Target, "/") || c.Target == "mayor" || c.Target == "deacon" {
+		if _, sessionName, err = agentAddressToIDs(c.Target); err != nil {
+			return chatReply{Text: err.Error()}
+		}
+	}
+	t := tmux.NewTmux()
+	if exists, _ := t.HasSession(sessionName); !exists {
+		return chatReply{Text: fmt.Sprintf("No session `%s` is running.", sessionName)}
+	}
+	if c.Verb == "stop" {
+		if err := t.KillSessionWithProcesses(sessionName); err != nil {
+			return chatReply{Text: fmt.Sprintf("Couldn't stop `%s`: %v", sessionName, err)}
+		}
+		audit(sessionName)
+		return chatReply{Text: fmt.Sprintf("<@%s> stopped `%s`", c.UserID, sessionName), InChannel: true}
+	}
+	if err := t.NudgeSession(sessionName, fmt.Sprintf("[from %s] %s", actor, c.Text)); err != nil {
+		return chatReply{Text: fmt.Sprintf("Couldn't prompt `%s`: %v", sessionName, err)}
+	}
+	audit(sessionName)
+	return chatReply{Text: fmt.Sprintf("<@%s> prompted `%s`: %s", c.UserID, sessionName, c.Text), InChannel: true}
+}
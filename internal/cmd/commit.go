@@ -1,12 +1,17 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -35,7 +40,19 @@ Identity mapping:
   Agent: gastown/crew/jack  →  Name: gastown/crew/jack
                                 Email: gastown.crew.jack@gastown.local
 
-When run without GT_ROLE (human), passes through to git commit with no changes.`,
+When run without GT_ROLE (human), passes through to git commit with no changes.
+
+Conventional commits from a bead:
+  gt commit --bead gt-abc [-m subject] [--type fix] [--scope mail] [--breaking] [--push [--allow-protected]]
+
+With --bead, all changes (including untracked files) are staged and committed
+as "type(scope): subject (gt-abc)". The type comes from the bead's type
+(bug → fix, feature/epic → feat, otherwise chore), the scope from a
+"scope:<name>" label, and the subject defaults to the bead title. The agent
+is recorded as author, committer, and in a Gt-Actor trailer. --push pushes
+the current branch to origin. Pushes to the rig's protected branches are
+refused unless --allow-protected is given (crew working directly on main);
+the override is recorded in the audit log.`,
 	RunE:               runCommit,
 	DisableFlagParsing: true, // We'll parse flags ourselves to pass them to git
 }
@@ -49,24 +66,18 @@ func runCommit(cmd *cobra.Command, args []string) error {
 	// Detect agent identity
 	identity := detectSender()
 
+	if hasBeadFlag(args) {
+		return runBeadCommit(args, identity)
+	}
+
 	// If overseer (human), just pass through to git commit
 	if identity == "overseer" {
 		return runGitCommit(args, "", "")
 	}
 
-	// Load agent email domain from town settings
-	domain := DefaultAgentEmailDomain
-	townRoot, err := workspace.FindFromCwd()
-	if err == nil && townRoot != "" {
-		settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
-		if err == nil && settings.AgentEmailDomain != "" {
-			domain = settings.AgentEmailDomain
-		}
-	}
-
 	// Convert identity to git-friendly email
 	// "gastown/crew/jack" → "gastown.crew.jack@domain"
-	email := identityToEmail(identity, domain)
+	email := identityToEmail(identity, agentEmailDomain())
 
 	// Use identity as the author name (human-readable)
 	name := identity
@@ -74,6 +85,19 @@ func runCommit(cmd *cobra.Command, args []string) error {
 	return runGitCommit(args, name, email)
 }
 
+// agentEmailDomain returns the town's agent_email_domain setting, or
+// DefaultAgentEmailDomain.
+func agentEmailDomain() string {
+	townRoot, err := workspace.FindFromCwd()
+	if err == nil && townRoot != "" {
+		settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+		if err == nil && settings.AgentEmailDomain != "" {
+			return settings.AgentEmailDomain
+		}
+	}
+	return DefaultAgentEmailDomain
+}
+
 // identityToEmail converts a Gas Town identity to a git email address.
 // "gastown/crew/jack" → "gastown.crew.jack@domain"
 // "mayor/" → "mayor@domain"
@@ -116,3 +140,164 @@ func runGitCommit(args []string, name, email string) error {
 	}
 	return nil
 }
+
+// beadCommitArgs are the gt commit --bead options.
+type beadCommitArgs struct {
+	bead, subject, commitType, scope string
+	breaking, push, allowProtected   bool
+}
+
+// hasBeadFlag reports whether args select the conventional-commit mode.
+func hasBeadFlag(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			return false
+		}
+		if arg == "--bead" || strings.HasPrefix(arg, "--bead=") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseBeadCommitArgs parses gt commit --bead flags. Flag parsing is
+// disabled on commitCmd so plain commits can pass anything to git.
+func parseBeadCommitArgs(args []string) (*beadCommitArgs, error) {
+	opts := &beadCommitArgs{}
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		var target *string
+		switch name {
+		case "--bead":
+			target = &opts.bead
+		case "-m", "--message":
+			target = &opts.subject
+		case "--type":
+			target = &opts.commitType
+		case "--scope":
+			target = &opts.scope
+		case "--breaking":
+			opts.breaking = true
+			continue
+		case "--push":
+			opts.push = true
+			continue
+		case "--allow-protected":
+			opts.allowProtected = true
+			continue
+		default:
+			return nil, fmt.Errorf("%s is not supported with --bead", args[i])
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("%s requires a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		*target = value
+	}
+	if opts.bead == "" {
+		return nil, fmt.Errorf("--bead requires a bead ID")
+	}
+	if opts.allowProtected && !opts.push {
+		return nil, fmt.Errorf("--allow-protected requires --push")
+	}
+	return opts, nil
+}
+
+// commitMessageForBead builds a conventional-commit message for work on
+// issue. Explicit values in opts override those derived from the bead.
+func commitMessageForBead(issue *beads.Issue, opts *beadCommitArgs) git.CommitMessage {
+	msg := git.CommitMessage{
+		Type:     opts.commitType,
+		Scope:    opts.scope,
+		Subject:  opts.subject,
+		Issue:    issue.ID,
+		Breaking: opts.breaking,
+	}
+	if msg.Type == "" {
+		switch issue.Type {
+		case "bug":
+			msg.Type = "fix"
+		case "feature", "epic":
+			msg.Type = "feat"
+		case "docs":
+			msg.Type = "docs"
+		default:
+			msg.Type = "chore"
+		}
+	}
+	if msg.Scope == "" {
+		for _, label := range issue.Labels {
+			if scope, ok := strings.CutPrefix(label, "scope:"); ok {
+				msg.Scope = scope
+				break
+			}
+		}
+	}
+	if msg.Subject == "" {
+		msg.Subject = issue.Title
+	}
+	return msg
+}
+
+// runBeadCommit commits all changes with a conventional message built from
+// a bead, attributed to the current agent, and optionally pushes.
+func runBeadCommit(args []string, identity string) error {
+	opts, err := parseBeadCommitArgs(args)
+	if err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+
+	bd := beads.New(beads.ResolveBeadsDir(cwd))
+	issue, err := bd.Show(opts.bead)
+	if err != nil {
+		return fmt.Errorf("loading bead %s: %w", opts.bead, err)
+	}
+	msg := commitMessageForBead(issue, opts)
+
+	var author git.CommitAuthor
+	if identity != "overseer" {
+		author = git.CommitAuthor{
+			Name:  identity,
+			Email: identityToEmail(identity, agentEmailDomain()),
+			Actor: strings.TrimSuffix(identity, "/"),
+		}
+	}
+
+	g := git.NewGit(cwd)
+	sha, err := g.CommitAll(msg, author)
+	if err != nil {
+		return fmt.Errorf("committing: %w", err)
+	}
+	fmt.Printf("%s Committed %s %s\n", style.SuccessPrefix, sha[:8], strings.SplitN(msg.String(), "\n", 2)[0])
+
+	if !opts.push {
+		return nil
+	}
+	branch, err := g.CurrentBranch()
+	if err != nil || branch == "HEAD" {
+		return fmt.Errorf("cannot push: not on a branch")
+	}
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		if _, r, err := findCurrentRig(townRoot); err == nil {
+			g.SetProtectedBranches(r.ProtectedBranches())
+		}
+	}
+	if err := g.PushWithOptions("origin", branch, git.PushOptions{AllowProtected: opts.allowProtected}); err != nil {
+		if errors.Is(err, git.ErrPushRejected) {
+			return fmt.Errorf("%w (fetch and rebase onto origin/%s, then push again)", err, branch)
+		}
+		if errors.Is(err, git.ErrPushGuarded) && !opts.allowProtected && g.ProtectedBranchPattern(branch) != "" {
+			return fmt.Errorf("%w (pass --allow-protected to push directly to %s)", err, branch)
+		}
+		return fmt.Errorf("pushing %s: %w", branch, err)
+	}
+	fmt.Printf("%s Pushed %s\n", style.SuccessPrefix, branch)
+	return nil
+}
@@ -1,6 +1,10 @@
 package cmd
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
 
 func TestIdentityToEmail(t *testing.T) {
 	tests := []struct {
@@ -69,3 +73,55 @@ func TestIdentityToEmail(t *testing.T) {
 		})
 	}
 }
+
+func TestParseBeadCommitArgs(t *testing.T) {
+	opts, err := parseBeadCommitArgs([]string{"--bead", "gt-abc", "-m", "fix the thing", "--scope=mail", "--push"})
+	if err != nil {
+		t.Fatalf("parseBeadCommitArgs: %v", err)
+	}
+	if opts.bead != "gt-abc" || opts.subject != "fix the thing" || opts.scope != "mail" || !opts.push || opts.breaking {
+		t.Errorf("parseBeadCommitArgs = %+v", opts)
+	}
+	if opts, err := parseBeadCommitArgs([]string{"--bead", "gt-abc", "--push", "--allow-protected"}); err != nil || !opts.allowProtected {
+		t.Errorf("parseBeadCommitArgs(--allow-protected) = %+v, %v", opts, err)
+	}
+
+	for _, args := range [][]string{
+		{"--bead"},
+		{"--bead", "gt-abc", "--amend"},
+		{"-m", "no bead"},
+		{"--bead", "gt-abc", "--allow-protected"},
+	} {
+		if _, err := parseBeadCommitArgs(args); err == nil {
+			t.Errorf("parseBeadCommitArgs(%q) = nil error, want error", args)
+		}
+	}
+}
+
+func TestCommitMessageForBead(t *testing.T) {
+	bug := &beads.Issue{ID: "gt-1", Title: "Crash on empty inbox", Type: "bug", Labels: []string{"gt:task", "scope:mail"}}
+	tests := []struct {
+		issue *beads.Issue
+		opts  beadCommitArgs
+		want  string
+	}{
+		{bug, beadCommitArgs{}, "fix(mail): Crash on empty inbox (gt-1)"},
+		{bug, beadCommitArgs{commitType: "test", scope: "inbox", subject: "cover empty inbox"}, "test(inbox): cover empty inbox (gt-1)"},
+		{&beads.Issue{ID: "gt-2", Title: "Merge trains", Type: "feature"}, beadCommitArgs{breaking: true}, "feat!: Merge trains (gt-2)"},
+		{&beads.Issue{ID: "gt-3", Title: "Bump deps", Type: "task"}, beadCommitArgs{}, "chore: Bump deps (gt-3)"},
+	}
+	for _, tt := range tests {
+		if got := commitMessageForBead(tt.issue, &tt.opts).String(); got != tt.want {
+			t.Errorf("commitMessageForBead(%s) = %q, want %q", tt.issue.ID, got, tt.want)
+		}
+	}
+}
+
+func TestHasBeadFlag(t *testing.T) {
+	if !hasBeadFlag([]string{"-m", "x", "--bead=gt-1"}) {
+		t.Error("hasBeadFlag should detect --bead=")
+	}
+	if hasBeadFlag([]string{"-m", "x", "--", "--bead"}) {
+		t.Error("hasBeadFlag should ignore args after --")
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -29,7 +31,9 @@ Commands:
   gt config agent get <name>         Show agent configuration
   gt config agent set <name> <cmd>   Set custom agent command
   gt config agent remove <name>      Remove custom agent
-  gt config default-agent [name]     Get or set default agent`,
+  gt config default-agent [name]     Get or set default agent
+  gt config show [--effective]       Show settings from gastown.toml and env
+  gt config validate                 Check config files, paths, and binaries`,
 }
 
 // Agent subcommands
@@ -119,6 +123,43 @@ Examples:
 	RunE: runConfigDefaultAgent,
 }
 
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show effective operator settings",
+	Long: `Show the effective operator settings for the town.
+
+Settings come from ~/.config/gastown/config.toml, then $TOWN/gastown.toml,
+then GT_* environment variables, each overriding the last:
+
+  runtime, model, max_tokens   GT_RUNTIME, GT_MODEL, GT_MAX_TOKENS
+  [concurrency] max_sessions   GT_MAX_SESSIONS
+  [server] bind, port          GT_SERVER_BIND, GT_SERVER_PORT
+  [budget] daily_usd, weekly_usd
+                               GT_BUDGET_DAILY_USD, GT_BUDGET_WEEKLY_USD
+
+A rig's own gastown.toml (<town>/<rig>/gastown.toml) overrides the town
+files for that rig's sessions; use --rig to see a rig's effective settings.
+
+Command-line flags such as --agent and --port override these in turn.
+
+With --effective, every resolved setting is listed with the file or
+environment variable it came from, along with the agent, model, and
+account each role starts with - e.g., to see why polecats run a given
+model.
+
+Examples:
+  gt config show
+  gt config show --effective
+  gt config show --effective --rig gastown --json`,
+	RunE: runConfigShow,
+}
+
+var (
+	configShowRig       string
+	configShowEffective bool
+	configShowJSON      bool
+)
+
 var configAgentEmailDomainCmd = &cobra.Command{
 	Use:   "agent-email-domain [domain]",
 	Short: "Get or set agent email domain",
@@ -347,6 +388,7 @@ func runConfigAgentSet(cmd *cobra.Command, args []string) error {
 	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
 		return fmt.Errorf("saving town settings: %w", err)
 	}
+	logConfigChange(townRoot, settingsPath, "agents."+name, commandLine)
 
 	fmt.Printf("Agent '%s' set to: %s\n", style.Bold.Render(name), commandLine)
 
@@ -397,6 +439,7 @@ func runConfigAgentRemove(cmd *cobra.Command, args []string) error {
 	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
 		return fmt.Errorf("saving town settings: %w", err)
 	}
+	logConfigChange(townRoot, settingsPath, "agents."+name, "")
 
 	fmt.Printf("Removed custom agent '%s'\n", style.Bold.Render(name))
 	return nil
@@ -428,6 +471,10 @@ func runConfigDefaultAgent(cmd *cobra.Command, args []string) error {
 			defaultAgent = "claude"
 		}
 		fmt.Printf("Default agent: %s\n", style.Bold.Render(defaultAgent))
+		if gtConfig, err := config.LoadGastownConfig(townRoot); err == nil && gtConfig.Runtime != "" && gtConfig.Runtime != defaultAgent {
+			fmt.Printf("  %s overridden by runtime %q (gastown.toml or %s)\n",
+				style.Dim.Render("⚠"), gtConfig.Runtime, config.EnvRuntime)
+		}
 		return nil
 	}
 
@@ -460,11 +507,106 @@ func runConfigDefaultAgent(cmd *cobra.Command, args []string) error {
 	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
 		return fmt.Errorf("saving town settings: %w", err)
 	}
+	logConfigChange(townRoot, settingsPath, "default_agent", name)
 
 	fmt.Printf("Default agent set to '%s'\n", style.Bold.Render(name))
 	return nil
 }
 
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return fmt.Errorf("finding town root: %w", err)
+	}
+
+	rigPath := ""
+	if configShowRig != "" {
+		rigPath = filepath.Join(townRoot, configShowRig)
+		if _, err := os.Stat(rigPath); err != nil {
+			return fmt.Errorf("rig not found: %s", configShowRig)
+		}
+	}
+	if configShowEffective || configShowJSON {
+		return runConfigShowEffective(townRoot, rigPath)
+	}
+	gtConfig, err := config.LoadRigGastownConfig(townRoot, rigPath)
+	if err != nil {
+		return err
+	}
+
+	orDefault := func(v, def string) string {
+		if v == "" {
+			return style.Dim.Render(def)
+		}
+		return v
+	}
+	fmt.Printf("%s\n", style.Bold.Render("Operator settings"))
+	if len(gtConfig.Files) == 0 {
+		fmt.Printf("  files:        %s\n", style.Dim.Render("(none)"))
+	}
+	for _, f := range gtConfig.Files {
+		fmt.Printf("  file:         %s\n", f)
+	}
+	fmt.Printf("  runtime:      %s\n", orDefault(gtConfig.Runtime, "(default_agent from settings)"))
+	fmt.Printf("  model:        %s\n", orDefault(gtConfig.Model, "(agent default)"))
+	maxTokens := ""
+	if gtConfig.MaxTokens > 0 {
+		maxTokens = fmt.Sprintf("%d", gtConfig.MaxTokens)
+	}
+	fmt.Printf("  max_tokens:   %s\n", orDefault(maxTokens, "(agent default)"))
+	maxSessions := ""
+	if gtConfig.Concurrency != nil && gtConfig.Concurrency.MaxSessions > 0 {
+		maxSessions = fmt.Sprintf("%d", gtConfig.Concurrency.MaxSessions)
+	}
+	fmt.Printf("  max_sessions: %s\n", orDefault(maxSessions, "(from settings)"))
+	fmt.Printf("  server:       %s\n", gtConfig.Server.Addr())
+	budget := ""
+	if b := gtConfig.Budget; b != nil {
+		budget = fmt.Sprintf("$%.2f/day, $%.2f/week", b.DailyUSD, b.WeeklyUSD)
+		if b.DailyTokens > 0 || b.WeeklyTokens > 0 {
+			budget += fmt.Sprintf(", %d tokens/day, %d tokens/week", b.DailyTokens, b.WeeklyTokens)
+		}
+	}
+	fmt.Printf("  budget:       %s\n", orDefault(budget, "(per rig)"))
+	fmt.Printf("  activity:     active < %s, stale < %s\n",
+		orDefault(gtConfig.Activity.Active, "2m"), orDefault(gtConfig.Activity.Stale, "5m"))
+	if gtConfig.MergeQueue != nil {
+		names := make([]string, 0, len(gtConfig.MergeQueue.Checks))
+		for _, c := range gtConfig.MergeQueue.Checks {
+			names = append(names, c.Name)
+		}
+		fmt.Printf("  merge checks: %s\n", orDefault(strings.Join(names, ", "), "(none)"))
+	}
+	roles := make([]string, 0, len(gtConfig.Prompts))
+	for role := range gtConfig.Prompts {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	for _, role := range roles {
+		fmt.Printf("  prompt[%s]: %s\n", role, style.Dim.Render(firstLine(gtConfig.Prompts[role])))
+	}
+	return nil
+}
+
+// logConfigChange records a change to the settings file at path in the
+// town's event log, attributed to whoever ran the command.
+func logConfigChange(townRoot, path, key, value string) {
+	file := path
+	if rel, err := filepath.Rel(townRoot, path); err == nil {
+		file = rel
+	}
+	_ = events.LogAudit(events.TypeConfigChange, detectSender(), events.ConfigChangePayload(file, key, value))
+}
+
+// firstLine returns the first line of s, marking any truncation.
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i] + " …"
+	}
+	return s
+}
+
 func runConfigAgentEmailDomain(cmd *cobra.Command, args []string) error {
 	townRoot, err := workspace.FindFromCwd()
 	if err != nil {
@@ -507,6 +649,7 @@ func runConfigAgentEmailDomain(cmd *cobra.Command, args []string) error {
 	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
 		return fmt.Errorf("saving town settings: %w", err)
 	}
+	logConfigChange(townRoot, settingsPath, "agent_email_domain", domain)
 
 	fmt.Printf("Agent email domain set to '%s'\n", style.Bold.Render(domain))
 	fmt.Printf("\nExample: gastown/crew/jack → gastown.crew.jack@%s\n", domain)
@@ -532,6 +675,10 @@ func init() {
 	configCmd.AddCommand(configAgentCmd)
 	configCmd.AddCommand(configDefaultAgentCmd)
 	configCmd.AddCommand(configAgentEmailDomainCmd)
+	configShowCmd.Flags().StringVar(&configShowRig, "rig", "", "Show settings for a rig, including its gastown.toml")
+	configShowCmd.Flags().BoolVar(&configShowEffective, "effective", false, "Show every resolved setting with its source, including each role's agent and model")
+	configShowCmd.Flags().BoolVar(&configShowJSON, "json", false, "Output effective settings as JSON")
+	configCmd.AddCommand(configShowCmd)
 
 	// Register with root
 	rootCmd.AddCommand(configCmd)
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/account"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the town's configuration files",
+	Long: `Check every configuration file in the town.
+
+Each file is loaded with its usual validation: types and ranges (budgets,
+concurrency caps, ports), durations (timeouts, cooldowns, SLAs), addresses
+(server.bind, forge and federation URLs), and ${VAR}/secret:// references.
+Then referenced paths and binaries are checked: registered rig directories,
+account config dirs, and the command of every agent the town starts.
+
+Exits non-zero if any errors are found. Warnings do not fail validation.
+
+Examples:
+  gt config validate
+  gt config validate --json`,
+	RunE: runConfigValidate,
+}
+
+var configValidateJSON bool
+
+func init() {
+	configValidateCmd.Flags().BoolVar(&configValidateJSON, "json", false, "Output as JSON")
+	configCmd.AddCommand(configValidateCmd)
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return fmt.Errorf("finding town root: %w", err)
+	}
+
+	issues := config.ValidateTown(townRoot)
+	errCount := 0
+	for _, issue := range issues {
+		if !issue.Warning {
+			errCount++
+		}
+	}
+
+	if configValidateJSON {
+		if issues == nil {
+			issues = []config.ValidationIssue{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(issues); err != nil {
+			return err
+		}
+	} else {
+		for _, issue := range issues {
+			prefix := style.ErrorPrefix
+			if issue.Warning {
+				prefix = style.WarningPrefix
+			}
+			fmt.Printf("%s %s\n    %s\n", prefix, relToTown(townRoot, issue.File), issue.Message)
+		}
+		switch {
+		case len(issues) == 0:
+			fmt.Printf("%s Configuration is valid\n", style.SuccessPrefix)
+		case errCount == 0:
+			fmt.Printf("\n%s Configuration is valid (%d warning(s))\n", style.SuccessPrefix, len(issues))
+		default:
+			fmt.Println()
+		}
+	}
+
+	if errCount > 0 {
+		return fmt.Errorf("config validation found %d error(s)", errCount)
+	}
+	return nil
+}
+
+// runConfigShowEffective prints every resolved setting with its source.
+func runConfigShowEffective(townRoot, rigPath string) error {
+	values, err := config.EffectiveConfig(townRoot, rigPath)
+	if err != nil {
+		return err
+	}
+	values = append(values, effectiveAccounts(townRoot, rigPath)...)
+
+	if configShowJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(values)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+	for _, v := range values {
+		value := v.Value
+		if value == "" {
+			value = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", v.Key, value, style.Dim.Render(v.Source))
+	}
+	return w.Flush()
+}
+
+// effectiveAccounts returns the account each role's sessions would start on.
+// Nothing is returned if no accounts are configured.
+func effectiveAccounts(townRoot, rigPath string) []config.EffectiveValue {
+	roles := []string{constants.RoleMayor, constants.RoleDeacon}
+	rig := ""
+	if rigPath != "" {
+		roles = []string{constants.RoleWitness, constants.RoleRefinery, constants.RolePolecat, constants.RoleCrew}
+		rig = filepath.Base(rigPath)
+	}
+	var values []config.EffectiveValue
+	for _, role := range roles {
+		r, err := account.Resolve(townRoot, account.Selection{Role: role, Rig: rig})
+		if err != nil {
+			values = append(values, config.EffectiveValue{Key: "account." + role, Source: err.Error()})
+			continue
+		}
+		if r == nil {
+			return nil
+		}
+		var source string
+		switch r.Source {
+		case account.SourceEnv:
+			source = "$GT_ACCOUNT"
+		case account.SourceRig:
+			source = "mayor/accounts.json rigs." + rig
+		case account.SourceRole:
+			source = "mayor/accounts.json roles." + role
+		default:
+			source = "mayor/accounts.json default"
+		}
+		if r.RotatedFrom != "" {
+			source += fmt.Sprintf(" (rotated from %s)", r.RotatedFrom)
+		}
+		values = append(values, config.EffectiveValue{Key: "account." + role, Value: r.Handle, Source: source})
+	}
+	return values
+}
+
+// relToTown returns path relative to the town root when it is inside it.
+func relToTown(townRoot, path string) string {
+	if rel, err := filepath.Rel(townRoot, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
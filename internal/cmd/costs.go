@@ -15,6 +15,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/outcome"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -183,6 +185,9 @@ type CostsOutput struct {
 // costRegex matches cost patterns like "$1.23" or "$12.34"
 var costRegex = regexp.MustCompile(`\$(\d+\.\d{2})`)
 
+// tokensRegex matches token counts like "12345 tokens" or "12.3k tokens"
+var tokensRegex = regexp.MustCompile(`(\d+(?:\.\d+)?)([kKmM]?) tokens\b`)
+
 func runCosts(cmd *cobra.Command, args []string) error {
 	// If querying ledger, use ledger functions
 	if costsToday || costsWeek || costsByRole || costsByRig {
@@ -205,17 +210,17 @@ func runLiveCosts() error {
 	var costs []SessionCost
 	var total float64
 
-	for _, session := range sessions {
-		// Only process Gas Town sessions (start with "gt-")
-		if !strings.HasPrefix(session, constants.SessionPrefix) {
+	for _, sess := range sessions {
+		// Only process Gas Town rig sessions (carry the rig prefix)
+		if !session.HasRigPrefix(sess) {
 			continue
 		}
 
-		// Parse session name to get role/rig/worker
-		role, rig, worker := parseSessionName(session)
+		// Parse sess name to get role/rig/worker
+		role, rig, worker := parseSessionName(sess)
 
 		// Capture pane content
-		content, err := t.CapturePaneAll(session)
+		content, err := t.CapturePaneAll(sess)
 		if err != nil {
 			continue // Skip sessions we can't capture
 		}
@@ -224,10 +229,10 @@ func runLiveCosts() error {
 		cost := extractCost(content)
 
 		// Check if an agent appears to be running
-		running := t.IsAgentRunning(session)
+		running := t.IsAgentRunning(sess)
 
 		costs = append(costs, SessionCost{
-			Session: session,
+			Session: sess,
 			Role:    role,
 			Rig:     rig,
 			Worker:  worker,
@@ -578,9 +583,9 @@ func queryDigestBeads(days int) ([]CostEntry, error) {
 //   - gt-gastown-witness -> role=witness, rig=gastown, worker=""
 //   - gt-gastown-refinery -> role=refinery, rig=gastown, worker=""
 //   - gt-gastown-crew-joe -> role=crew, rig=gastown, worker=joe
-func parseSessionName(session string) (role, rig, worker string) {
+func parseSessionName(sessionName string) (role, rig, worker string) {
 	// Remove gt- prefix
-	name := strings.TrimPrefix(session, constants.SessionPrefix)
+	name := session.TrimRigPrefix(sessionName)
 
 	// Check for global agents
 	switch name {
@@ -635,6 +640,26 @@ func extractCost(content string) float64 {
 	return cost
 }
 
+// extractTokens finds the most recent token count in pane content.
+// Returns 0 if the agent does not display one.
+func extractTokens(content string) int {
+	matches := tokensRegex.FindAllStringSubmatch(content, -1)
+	if len(matches) == 0 {
+		return 0
+	}
+
+	lastMatch := matches[len(matches)-1]
+	var n float64
+	_, _ = fmt.Sscanf(lastMatch[1], "%f", &n)
+	switch strings.ToLower(lastMatch[2]) {
+	case "k":
+		n *= 1_000
+	case "m":
+		n *= 1_000_000
+	}
+	return int(n)
+}
+
 func outputCostsJSON(output CostsOutput) error {
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
@@ -824,6 +849,18 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		fmt.Fprintf(os.Stderr, "warning: could not auto-close session cost wisp %s: %v\n", wispID, closeErr)
 	}
 
+	// Attribute the session's cost to its assignment for gt stats
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		_ = outcome.Record(townRoot, outcome.Update{
+			Kind:    outcome.KindCost,
+			Bead:    recordWorkItem,
+			Agent:   agentPath,
+			Session: session,
+			CostUSD: cost,
+			Tokens:  extractTokens(content),
+		})
+	}
+
 	// Output confirmation (silent if cost is zero and no work item)
 	if cost > 0 || recordWorkItem != "" {
 		fmt.Printf("%s Recorded $%.2f for %s (wisp: %s)", style.Success.Render("✓"), cost, session, wispID)
@@ -851,12 +888,12 @@ func deriveSessionName() string {
 
 	// Polecat: gt-{rig}-{polecat}
 	if polecat != "" && rig != "" {
-		return fmt.Sprintf("gt-%s-%s", rig, polecat)
+		return session.PolecatSessionName(rig, polecat)
 	}
 
 	// Crew: gt-{rig}-crew-{crew}
 	if crew != "" && rig != "" {
-		return fmt.Sprintf("gt-%s-crew-%s", rig, crew)
+		return session.CrewSessionName(rig, crew)
 	}
 
 	// Town-level roles (mayor, deacon): gt-{town}-{role} or gt-{role}
@@ -870,7 +907,7 @@ func deriveSessionName() string {
 
 	// Rig-based roles (witness, refinery): gt-{rig}-{role}
 	if role != "" && rig != "" {
-		return fmt.Sprintf("gt-%s-%s", rig, role)
+		return fmt.Sprintf("%s%s-%s", session.CurrentScheme().Prefix, rig, role)
 	}
 
 	return ""
@@ -887,11 +924,11 @@ func detectCurrentTmuxSession() string {
 		return ""
 	}
 
-	session := strings.TrimSpace(string(output))
+	sess := strings.TrimSpace(string(output))
 	// Only return if it looks like a Gas Town session
 	// Accept both gt- (rig sessions) and hq- (town-level sessions like hq-mayor)
-	if strings.HasPrefix(session, constants.SessionPrefix) || strings.HasPrefix(session, constants.HQSessionPrefix) {
-		return session
+	if session.HasRigPrefix(sess) || session.HasHQPrefix(sess) {
+		return sess
 	}
 	return ""
 }
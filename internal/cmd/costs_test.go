@@ -112,3 +112,18 @@ func TestDeriveSessionName(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractTokens(t *testing.T) {
+	tests := map[string]int{
+		"":                                   0,
+		"no usage here":                      0,
+		"✻ Working… (↑ 850 tokens)":          850,
+		"↓ 1.2k tokens\n...\n↑ 12.5k tokens": 12500,
+		"3M tokens used":                     3000000,
+	}
+	for content, want := range tests {
+		if got := extractTokens(content); got != want {
+			t.Errorf("extractTokens(%q) = %d, want %d", content, got, want)
+		}
+	}
+}
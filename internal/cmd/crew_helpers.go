@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -68,7 +69,7 @@ func getCrewManager(rigName string) (*crew.Manager, *rig.Rig, error) {
 
 // crewSessionName generates the tmux session name for a crew worker.
 func crewSessionName(rigName, crewName string) string {
-	return fmt.Sprintf("gt-%s-crew-%s", rigName, crewName)
+	return session.CrewSessionName(rigName, crewName)
 }
 
 // parseRigSlashName parses "rig/name" format into separate rig and name parts.
@@ -279,16 +280,16 @@ func ensureDefaultBranch(dir, roleName, rigPath string) bool { //nolint:unparam
 }
 
 // parseCrewSessionName extracts rig and crew name from a tmux session name.
-// Format: gt-<rig>-crew-<name>
+// Format: <prefix><rig>-crew-<name>, with the town's rig-level prefix
+// (default "gt-").
 // Returns empty strings and false if the format doesn't match.
 func parseCrewSessionName(sessionName string) (rigName, crewName string, ok bool) {
-	// Must start with "gt-" and contain "-crew-"
-	if !strings.HasPrefix(sessionName, "gt-") {
+	// Must carry the rig prefix and contain "-crew-"
+	if !session.HasRigPrefix(sessionName) {
 		return "", "", false
 	}
 
-	// Remove "gt-" prefix
-	rest := sessionName[3:]
+	rest := session.TrimRigPrefix(sessionName)
 
 	// Find "-crew-" separator
 	idx := strings.Index(rest, "-crew-")
@@ -316,7 +317,7 @@ func findRigCrewSessions(rigName string) ([]string, error) { //nolint:unparam //
 		return nil, nil
 	}
 
-	prefix := fmt.Sprintf("gt-%s-crew-", rigName)
+	prefix := session.CrewSessionName(rigName, "")
 	var sessions []string
 
 	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
@@ -11,8 +11,8 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/account"
 	"github.com/steveyegge/gastown/internal/beads"
-	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/mail"
@@ -308,13 +308,13 @@ func runCrewStart(cmd *cobra.Command, args []string) error {
 	if townRoot == "" {
 		townRoot = filepath.Dir(r.Path)
 	}
-	accountsPath := constants.MayorAccountsPath(townRoot)
-	claudeConfigDir, _, _ := config.ResolveAccountConfigDir(accountsPath, crewAccount)
+	acct, _ := account.Resolve(townRoot, account.Selection{Flag: crewAccount, Role: constants.RoleCrew, Rig: r.Name})
 
 	// Build start options (shared across all crew members)
 	opts := crew.StartOptions{
 		Account:         crewAccount,
-		ClaudeConfigDir: claudeConfigDir,
+		ClaudeConfigDir: acct.ConfigDirOrEmpty(),
+		AccountEnv:      acct.Env(),
 		AgentOverride:   crewAgentOverride,
 	}
 
@@ -361,6 +361,7 @@ func runCrewStart(cmd *cobra.Command, args []string) error {
 			skippedCount++
 		} else {
 			fmt.Printf("  %s %s/%s: started\n", style.SuccessPrefix, rigName, res.name)
+			_ = acct.Record(townRoot, crewMgr.SessionName(res.name))
 			startedCount++
 		}
 	}
@@ -26,6 +26,10 @@ type CrewStatusItem struct {
 	GitClean     bool     `json:"git_clean"`
 	GitModified  []string `json:"git_modified,omitempty"`
 	GitUntracked []string `json:"git_untracked,omitempty"`
+	GitAhead     int      `json:"git_ahead,omitempty"`
+	GitBehind    int      `json:"git_behind,omitempty"`
+	GitMerging   bool     `json:"git_merging,omitempty"`
+	GitRebasing  bool     `json:"git_rebasing,omitempty"`
 	MailTotal    int      `json:"mail_total"`
 	MailUnread   int      `json:"mail_unread"`
 }
@@ -89,16 +93,13 @@ func runCrewStatus(cmd *cobra.Command, args []string) error {
 
 		// Git status
 		crewGit := git.NewGit(w.ClonePath)
-		gitStatus, _ := crewGit.Status()
-		branch, _ := crewGit.CurrentBranch()
-
-		gitClean := true
-		var modified, untracked []string
-		if gitStatus != nil {
-			gitClean = gitStatus.Clean
-			modified = append(gitStatus.Modified, gitStatus.Added...)
-			modified = append(modified, gitStatus.Deleted...)
-			untracked = gitStatus.Untracked
+		gitStatus, err := crewGit.Status()
+		if err != nil {
+			gitStatus = &git.GitStatus{Clean: true}
+		}
+		branch := gitStatus.Branch
+		if gitStatus.Detached {
+			branch = "HEAD"
 		}
 
 		// Mail status (non-fatal: display defaults to 0 if count fails)
@@ -115,9 +116,13 @@ func runCrewStatus(cmd *cobra.Command, args []string) error {
 			Path:         w.ClonePath,
 			Branch:       branch,
 			HasSession:   hasSession,
-			GitClean:     gitClean,
-			GitModified:  modified,
-			GitUntracked: untracked,
+			GitClean:     gitStatus.Clean,
+			GitModified:  gitStatus.ChangedFiles(),
+			GitUntracked: gitStatus.Untracked,
+			GitAhead:     gitStatus.Ahead,
+			GitBehind:    gitStatus.Behind,
+			GitMerging:   gitStatus.Merging,
+			GitRebasing:  gitStatus.Rebasing,
 			MailTotal:    mailTotal,
 			MailUnread:   mailUnread,
 		}
@@ -147,7 +152,11 @@ func runCrewStatus(cmd *cobra.Command, args []string) error {
 
 		fmt.Printf("%s %s/%s\n", sessionStatus, item.Rig, item.Name)
 		fmt.Printf("  Path:   %s\n", item.Path)
-		fmt.Printf("  Branch: %s\n", item.Branch)
+		branchInfo := item.Branch
+		if item.GitAhead > 0 || item.GitBehind > 0 {
+			branchInfo += style.Dim.Render(fmt.Sprintf(" (ahead %d, behind %d)", item.GitAhead, item.GitBehind))
+		}
+		fmt.Printf("  Branch: %s\n", branchInfo)
 
 		if item.GitClean {
 			fmt.Printf("  Git:    %s\n", style.Dim.Render("clean"))
@@ -160,6 +169,12 @@ func runCrewStatus(cmd *cobra.Command, args []string) error {
 				fmt.Printf("          Untracked: %s\n", strings.Join(item.GitUntracked, ", "))
 			}
 		}
+		if item.GitMerging {
+			fmt.Printf("          %s\n", style.Warning.Render("merge in progress"))
+		}
+		if item.GitRebasing {
+			fmt.Printf("          %s\n", style.Warning.Render("rebase in progress"))
+		}
 
 		if item.MailUnread > 0 {
 			fmt.Printf("  Mail:   %d unread / %d total\n", item.MailUnread, item.MailTotal)
@@ -1,12 +1,12 @@
 package cmd
 
 import (
-	"fmt"
 	"os/exec"
 	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/session"
 )
 
 // cycleSession is the --session flag for cycle next/prev commands.
@@ -67,10 +67,10 @@ and cycles within the appropriate group.`,
 // direction: 1 for next, -1 for previous
 // sessionOverride: if non-empty, use this instead of detecting current session
 func cycleToSession(direction int, sessionOverride string) error {
-	session := sessionOverride
-	if session == "" {
+	sess := sessionOverride
+	if sess == "" {
 		var err error
-		session, err = getCurrentTmuxSession()
+		sess, err = getCurrentTmuxSession()
 		if err != nil {
 			return nil // Not in tmux, nothing to do
 		}
@@ -80,25 +80,25 @@ func cycleToSession(direction int, sessionOverride string) error {
 	townLevelSessions := getTownLevelSessions()
 	if townLevelSessions != nil {
 		for _, townSession := range townLevelSessions {
-			if session == townSession {
-				return cycleTownSession(direction, session)
+			if sess == townSession {
+				return cycleTownSession(direction, sess)
 			}
 		}
 	}
 
 	// Check if it's a crew session (format: gt-<rig>-crew-<name>)
-	if strings.HasPrefix(session, "gt-") && strings.Contains(session, "-crew-") {
-		return cycleCrewSession(direction, session)
+	if session.HasRigPrefix(sess) && strings.Contains(sess, "-crew-") {
+		return cycleCrewSession(direction, sess)
 	}
 
 	// Check if it's a rig infra session (witness or refinery)
-	if rig := parseRigInfraSession(session); rig != "" {
-		return cycleRigInfraSession(direction, session, rig)
+	if rig := parseRigInfraSession(sess); rig != "" {
+		return cycleRigInfraSession(direction, sess, rig)
 	}
 
 	// Check if it's a polecat session (gt-<rig>-<name>, not crew/witness/refinery)
-	if rig, _, ok := parsePolecatSessionName(session); ok && rig != "" {
-		return cyclePolecatSession(direction, session)
+	if rig, _, ok := parsePolecatSessionName(sess); ok && rig != "" {
+		return cyclePolecatSession(direction, sess)
 	}
 
 	// Unknown session type - do nothing
@@ -108,11 +108,11 @@ func cycleToSession(direction int, sessionOverride string) error {
 // parseRigInfraSession extracts rig name if this is a witness or refinery session.
 // Returns empty string if not a rig infra session.
 // Format: gt-<rig>-witness or gt-<rig>-refinery
-func parseRigInfraSession(session string) string {
-	if !strings.HasPrefix(session, "gt-") {
+func parseRigInfraSession(sess string) string {
+	if !session.HasRigPrefix(sess) {
 		return ""
 	}
-	rest := session[3:] // Remove "gt-" prefix
+	rest := session.TrimRigPrefix(sess)
 
 	// Check for -witness or -refinery suffix
 	if strings.HasSuffix(rest, "-witness") {
@@ -127,8 +127,8 @@ func parseRigInfraSession(session string) string {
 // cycleRigInfraSession cycles between witness and refinery sessions for a rig.
 func cycleRigInfraSession(direction int, currentSession, rig string) error {
 	// Find running infra sessions for this rig
-	witnessSession := fmt.Sprintf("gt-%s-witness", rig)
-	refinerySession := fmt.Sprintf("gt-%s-refinery", rig)
+	witnessSession := session.WitnessSessionName(rig)
+	refinerySession := session.RefinerySessionName(rig)
 
 	var sessions []string
 	allSessions, err := listTmuxSessions()
@@ -24,6 +24,7 @@ The daemon is a simple Go process that:
 - Pokes agents periodically (heartbeat)
 - Processes lifecycle requests (cycle, restart, shutdown)
 - Restarts sessions when agents request cycling
+- Reconciles running sessions with the town's desired state
 
 The daemon is a "dumb scheduler" - all intelligence is in agents.`,
 }
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	daemonReconcileDryRun bool
+	daemonReconcileJSON   bool
+)
+
+var daemonReconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Converge running sessions with the town's desired state",
+	Long: `Compare the town's desired state with the sessions running in tmux and
+converge them. The daemon does this on every heartbeat; run it by hand to
+see or apply the plan without waiting.
+
+Desired state comes from the rig registry (mayor/rigs.json) and the
+workspaces on disk:
+  - start:  an operational rig's witness or refinery is missing or its
+            agent has died (parked and docked rigs are skipped)
+  - stop:   a session started by gt whose rig is no longer registered, or
+            whose polecat worktree or crew workspace is gone
+  - adopt:  an agent session started outside gt (no GT_ROLE) for a known
+            rig and workspace gets the standard env vars and theme
+
+Sessions that belong to another town (GT_ROOT differs) and unmanaged
+sessions that match no known agent are never touched.
+
+Examples:
+  gt daemon reconcile --dry-run
+  gt daemon reconcile
+  gt daemon reconcile --dry-run --json`,
+	Args: cobra.NoArgs,
+	RunE: runDaemonReconcile,
+}
+
+func init() {
+	daemonReconcileCmd.Flags().BoolVar(&daemonReconcileDryRun, "dry-run", false, "Show the plan without changing anything")
+	daemonReconcileCmd.Flags().BoolVar(&daemonReconcileJSON, "json", false, "Output the plan as JSON")
+	daemonCmd.AddCommand(daemonReconcileCmd)
+}
+
+func runDaemonReconcile(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	// Progress goes to stderr so --json output stays parseable
+	actions, err := daemon.Reconcile(townRoot, daemonReconcileDryRun, log.New(os.Stderr, "", 0))
+	if err != nil {
+		return err
+	}
+
+	if daemonReconcileJSON {
+		if actions == nil {
+			actions = []daemon.ReconcileAction{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(actions)
+	}
+
+	if len(actions) == 0 {
+		fmt.Printf("%s Town is converged\n", style.Success.Render("✓"))
+		return nil
+	}
+	for _, a := range actions {
+		verb := string(a.Kind)
+		if daemonReconcileDryRun {
+			verb = "would " + verb
+		}
+		fmt.Printf("  %-14s %s  %s\n", verb, style.Bold.Render(a.Session), style.Dim.Render(a.Reason))
+	}
+	return nil
+}
@@ -1,19 +1,41 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
 	"os/exec"
+	"os/signal"
+	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/discord"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/federation"
+	"github.com/steveyegge/gastown/internal/forge"
+	"github.com/steveyegge/gastown/internal/health"
+	"github.com/steveyegge/gastown/internal/linear"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/outcome"
+	"github.com/steveyegge/gastown/internal/secret"
+	"github.com/steveyegge/gastown/internal/slack"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/web"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var (
 	dashboardPort int
+	dashboardBind string
 	dashboardOpen bool
 )
 
@@ -29,25 +51,73 @@ The dashboard shows real-time convoy status with:
 - Last activity indicator (green/yellow/red)
 - Auto-refresh every 30 seconds via htmx
 
+The server also receives forge webhooks (GitHub, GitLab, Gitea) at
+POST /webhooks/<rig>. Deliveries are verified against the secret named by
+the rig's forge.webhook_secret_env setting (the environment variable, or the
+keyring secret stored with gt secret set), and each event is mailed to the
+rig's Refinery.
+
+The federation API is served under /api/federation/ so a central town can
+aggregate this town's status and dispatch work to it (see gt federation).
+When remote towns are configured, the dashboard lists them too.
+
+Assignment outcome stats (see gt stats) are served as JSON at /api/stats,
+and the event/audit log (see gt events) at /api/events. The town's rolled-up
+health (see gt health) is served at /health/town, the Slack app's /gt
+slash command (see gt slack) at /slack/commands, the Discord
+application's interactions endpoint (see gt discord) at
+/discord/interactions, and the Linear workspace's webhook (see gt linear)
+at /linear/webhook.
+
+Requests authenticate with a bearer token from [[server.api_keys]] in
+gastown.toml. A "read" key opens the dashboard, /api/stats and
+/health/town; an "operator" key also opens /api/events, whose tool
+arguments and prompts need it even when no keys are configured (the
+federation token counts as an operator key). With no keys configured, the
+read routes are public. Webhook and chat endpoints check their own
+signatures, and /api/federation/ the federation token.
+
+The listen address defaults to [server] bind and port in gastown.toml
+(or GT_SERVER_BIND / GT_SERVER_PORT); flags override both. With [server]
+tls_cert and tls_key (PEM files, relative to the town root) the server
+speaks HTTPS. On SIGINT or SIGTERM it stops accepting connections and
+lets in-flight requests finish (up to 10s) before exiting.
+
 Example:
-  gt dashboard              # Start on default port 8080
+  gt dashboard              # Start on the configured port (default 8080)
   gt dashboard --port 3000  # Start on port 3000
+  gt dashboard --bind 127.0.0.1  # Listen on localhost only
   gt dashboard --open       # Start and open browser`,
 	RunE: runDashboard,
 }
 
 func init() {
-	dashboardCmd.Flags().IntVar(&dashboardPort, "port", 8080, "HTTP port to listen on")
+	dashboardCmd.Flags().IntVar(&dashboardPort, "port", config.DefaultServerPort, "HTTP port to listen on (default from gastown.toml)")
+	dashboardCmd.Flags().StringVar(&dashboardBind, "bind", "", "Address to listen on (default from gastown.toml, else all interfaces)")
 	dashboardCmd.Flags().BoolVar(&dashboardOpen, "open", false, "Open browser automatically")
 	rootCmd.AddCommand(dashboardCmd)
 }
 
 func runDashboard(cmd *cobra.Command, args []string) error {
 	// Verify we're in a workspace
-	if _, err := workspace.FindFromCwdOrError(); err != nil {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	// Resolve the listen address: flags > env > gastown.toml > defaults
+	gtConfig, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	server := gtConfig.Server
+	if cmd.Flags().Changed("port") {
+		server.Port = dashboardPort
+	}
+	if cmd.Flags().Changed("bind") {
+		server.Bind = dashboardBind
+	}
+
 	// Create the live convoy fetcher
 	fetcher, err := web.NewLiveConvoyFetcher()
 	if err != nil {
@@ -60,27 +130,196 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("creating convoy handler: %w", err)
 	}
 
+	auth, err := dashboardAuth(townRoot, server)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/webhooks/", web.NewWebhookHandler(resolveWebhookRig, func(rigName string, ev *forge.Event) error {
+		return notifyForgeEvent(townRoot, rigName, ev)
+	}))
+	mux.Handle("/api/federation/", federation.NewHandler(
+		func() (*federation.TownStatus, error) { return localFederationStatus(townRoot) },
+		func(ctx context.Context, req federation.DispatchRequest) (*federation.DispatchResult, error) {
+			return localFederationDispatch(ctx, townRoot, req)
+		},
+		federationToken(townRoot),
+	))
+	mux.Handle("/api/stats", auth.Require(config.APIScopeRead, web.NewStatsHandler(func() ([]*outcome.Outcome, error) {
+		return outcome.Load(townRoot)
+	})))
+	mux.Handle("/api/events", auth.Require(config.APIScopeOperator, web.NewEventsHandler(func(f events.Filter) ([]events.Event, error) {
+		return events.Query(townRoot, f)
+	})))
+	mux.Handle("/health/town", auth.Require(config.APIScopeRead, web.NewHealthHandler(func() (*health.Town, error) {
+		return collectTownHealth(townRoot)
+	})))
+	mux.Handle("/slack/commands", web.NewSlackCommandHandler(
+		func() (string, error) { return slackSigningSecret(townRoot) },
+		func(c *slack.Command) slack.Reply { return runSlackCommand(townRoot, c) },
+	))
+	mux.Handle("/discord/interactions", web.NewDiscordInteractionHandler(
+		func() (string, error) { return discordPublicKey(townRoot) },
+		func(i *discord.Interaction) discord.Response { return runDiscordCommand(townRoot, i) },
+	))
+	mux.Handle("/linear/webhook", web.NewLinearWebhookHandler(
+		func() (string, error) { return linearWebhookSecret(townRoot) },
+		func(w *linear.Webhook) error { return linearWebhook(townRoot, w) },
+	))
+	if clients, err := federationClients(townRoot); err == nil && len(clients) > 0 {
+		handler.SetTownFetcher(func() ([]web.TownRow, error) { return federationTownRows(townRoot) })
+	}
+	mux.Handle("/", auth.Require(config.APIScopeRead, handler))
+
 	// Build the URL
-	url := fmt.Sprintf("http://localhost:%d", dashboardPort)
+	host := server.Bind
+	if host == "" || host == "0.0.0.0" {
+		host = "localhost"
+	}
+	scheme := "http"
+	if server.TLS() {
+		scheme = "https"
+	}
+	url := scheme + "://" + net.JoinHostPort(host, strconv.Itoa(server.Port))
 
 	// Open browser if requested
 	if dashboardOpen {
 		go openBrowser(url)
 	}
 
+	ln, err := net.Listen("tcp", server.Addr())
+	if err != nil {
+		return err
+	}
+
 	// Start the server with timeouts
 	fmt.Printf("🚚 Gas Town Dashboard starting at %s\n", url)
 	fmt.Printf("   Press Ctrl+C to stop\n")
 
-	server := &http.Server{
-		Addr:              fmt.Sprintf(":%d", dashboardPort),
-		Handler:           handler,
+	httpServer := &http.Server{
+		Addr:              server.Addr(),
+		Handler:           telemetry.Handler(web.WithRequestLogging(mux)),
 		ReadHeaderTimeout: 10 * time.Second,
 		ReadTimeout:       30 * time.Second,
 		WriteTimeout:      60 * time.Second,
 		IdleTimeout:       120 * time.Second,
 	}
-	return server.ListenAndServe()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return serveDashboard(ctx, httpServer, ln, townPath(townRoot, server.TLSCert), townPath(townRoot, server.TLSKey))
+}
+
+// dashboardShutdownTimeout bounds how long in-flight requests may run once
+// the dashboard is asked to stop.
+const dashboardShutdownTimeout = 10 * time.Second
+
+// serveDashboard serves srv on ln, over TLS when certFile and keyFile are
+// set, until ctx is done (SIGINT/SIGTERM). It then stops accepting
+// connections and lets in-flight requests finish before returning.
+func serveDashboard(ctx context.Context, srv *http.Server, ln net.Listener, certFile, keyFile string) error {
+	errc := make(chan error, 1)
+	go func() {
+		if certFile != "" {
+			errc <- srv.ServeTLS(ln, certFile, keyFile)
+		} else {
+			errc <- srv.Serve(ln)
+		}
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	fmt.Printf("\nShutting down dashboard...\n")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), dashboardShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutting down dashboard: %w", err)
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// townPath resolves a configured path relative to the town root.
+func townPath(townRoot, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(townRoot, path)
+}
+
+// dashboardAuth builds the server's API key check from [server] api_keys,
+// with the federation token (if set) as an operator key. Webhook and chat
+// endpoints verify their own signatures, and /api/federation/ its token, so
+// they are not wrapped.
+func dashboardAuth(townRoot string, server config.ServerConfig) (*web.Auth, error) {
+	var keys []web.APIKey
+	for _, k := range server.APIKeys {
+		token, err := config.ResolveRef("server.api_keys."+k.Name+".key", k.Key)
+		if err != nil {
+			return nil, err
+		}
+		if token == "" {
+			return nil, fmt.Errorf("server.api_keys.%s.key is empty", k.Name)
+		}
+		keys = append(keys, web.APIKey{Name: k.Name, Token: token, Scope: k.Scope})
+	}
+	if token := federationToken(townRoot); token != "" {
+		keys = append(keys, web.APIKey{Name: "federation", Token: token, Scope: config.APIScopeOperator})
+	}
+	return web.NewAuth(keys, len(server.APIKeys) == 0), nil
+}
+
+// resolveWebhookRig returns the forge and webhook secret for a rig.
+func resolveWebhookRig(rigName string) (forge.Forge, string, error) {
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return nil, "", err
+	}
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+	if err != nil {
+		settings = nil
+	}
+	f, err := forge.Resolve(settings, r.GitURL)
+	if err != nil {
+		return nil, "", err
+	}
+	var webhookSecret string
+	if settings != nil && settings.Forge != nil && settings.Forge.WebhookSecretEnv != "" {
+		webhookSecret = secret.Lookup(settings.Forge.WebhookSecretEnv)
+	}
+	return f, webhookSecret, nil
+}
+
+// notifyForgeEvent mails a verified forge event to the rig's Refinery.
+func notifyForgeEvent(townRoot, rigName string, ev *forge.Event) error {
+	lines := []string{
+		fmt.Sprintf("Forge: %s", ev.Forge),
+		fmt.Sprintf("Repo: %s", ev.Repo),
+		fmt.Sprintf("Branch: %s", ev.Branch),
+	}
+	if ev.Action != "" {
+		lines = append(lines, fmt.Sprintf("Action: %s", ev.Action))
+	}
+	if ev.State != "" {
+		lines = append(lines, fmt.Sprintf("State: %s", ev.State))
+	}
+	if ev.URL != "" {
+		lines = append(lines, fmt.Sprintf("URL: %s", ev.URL))
+	}
+
+	msg := &mail.Message{
+		To:      fmt.Sprintf("%s/refinery", rigName),
+		From:    "overseer",
+		Subject: fmt.Sprintf("FORGE_%s %s", strings.ToUpper(ev.Type), ev.Branch),
+		Body:    strings.Join(lines, "\n"),
+	}
+	return mail.NewRouter(townRoot).Send(msg)
 }
 
 // openBrowser opens the specified URL in the default browser.
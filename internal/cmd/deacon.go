@@ -12,10 +12,12 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/account"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/claude"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/crashdump"
 	"github.com/steveyegge/gastown/internal/deacon"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/runtime"
@@ -162,9 +164,9 @@ This command is used by the Deacon when an agent fails consecutive health checks
 It performs the force-kill protocol:
 
 1. Log the intervention (send mail to agent)
-2. Kill the tmux session
+2. Capture a crash dump of the session, then kill it
 3. Update agent bead state to "killed"
-4. Notify mayor (optional, for visibility)
+4. Notify mayor (optional, for visibility), naming the crash dump
 
 After force-kill, the agent is 'asleep'. Normal wake mechanisms apply:
 - gt rig boot restarts it
@@ -207,6 +209,25 @@ Examples:
 	RunE: runDeaconStaleHooks,
 }
 
+var deaconExpireLeasesCmd = &cobra.Command{
+	Use:   "expire-leases",
+	Short: "Return work with expired leases to the ready queue",
+	Long: `Process expired work leases and return abandoned beads to the ready queue.
+
+When a worker hooks a bead it gets a time-boxed lease (default: 30 minutes).
+The lease is renewed by activity heartbeats: every gt command the worker runs,
+and any activity in the worker's tmux session. When a lease expires without
+activity, the bead is set back to 'open' with its assignee cleared so another
+worker can pick it up.
+
+Leases whose bead was re-hooked or closed are discarded.
+
+Examples:
+  gt deacon expire-leases             # Release beads with expired leases
+  gt deacon expire-leases --dry-run   # Preview what would be released`,
+	RunE: runDeaconExpireLeases,
+}
+
 var deaconPauseCmd = &cobra.Command{
 	Use:   "pause",
 	Short: "Pause the Deacon to prevent patrol actions",
@@ -252,6 +273,9 @@ var (
 	staleHooksMaxAge time.Duration
 	staleHooksDryRun bool
 
+	// Expire leases flags
+	expireLeasesDryRun bool
+
 	// Pause flags
 	pauseReason string
 )
@@ -268,6 +292,7 @@ func init() {
 	deaconCmd.AddCommand(deaconForceKillCmd)
 	deaconCmd.AddCommand(deaconHealthStateCmd)
 	deaconCmd.AddCommand(deaconStaleHooksCmd)
+	deaconCmd.AddCommand(deaconExpireLeasesCmd)
 	deaconCmd.AddCommand(deaconPauseCmd)
 	deaconCmd.AddCommand(deaconResumeCmd)
 
@@ -295,6 +320,10 @@ func init() {
 	deaconStaleHooksCmd.Flags().BoolVar(&staleHooksDryRun, "dry-run", false,
 		"Preview what would be unhooked without making changes")
 
+	// Flags for expire-leases
+	deaconExpireLeasesCmd.Flags().BoolVar(&expireLeasesDryRun, "dry-run", false,
+		"Preview what would be released without making changes")
+
 	// Flags for pause
 	deaconPauseCmd.Flags().StringVar(&pauseReason, "reason", "",
 		"Reason for pausing the Deacon")
@@ -358,6 +387,10 @@ func startDeaconSession(t *tmux.Tmux, sessionName, agentOverride string) error {
 	if err != nil {
 		return fmt.Errorf("building startup command: %w", err)
 	}
+	startupCmd, err = account.PrepareSession(townRoot, account.Selection{Role: constants.RoleDeacon}, sessionName, startupCmd)
+	if err != nil {
+		return fmt.Errorf("resolving account: %w", err)
+	}
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
@@ -815,6 +848,20 @@ func runDeaconForceKill(cmd *cobra.Command, args []string) error {
 	mailBody := fmt.Sprintf("Deacon detected %s as unresponsive.\nReason: %s\nAction: force-killing session", agent, reason)
 	sendMail(townRoot, agent, "FORCE_KILL: unresponsive", mailBody)
 
+	// Capture a post-mortem bundle while the pane is still there
+	dumpRig, dumpActor := forceKillDumpIDs(agent)
+	dump, err := crashdump.Capture(t, crashdump.Options{
+		TownRoot: townRoot,
+		Rig:      dumpRig,
+		Agent:    dumpActor,
+		Session:  sessionName,
+		ExitCode: -1,
+		Reason:   "force-killed: " + reason,
+	})
+	if err != nil {
+		style.PrintWarning("capturing crash dump: %v", err)
+	}
+
 	// Step 2: Kill the tmux session
 	fmt.Printf("%s Killing tmux session %s...\n", style.Dim.Render("2."), sessionName)
 	if err := t.KillSession(sessionName); err != nil {
@@ -829,6 +876,9 @@ func runDeaconForceKill(cmd *cobra.Command, args []string) error {
 	if !forceKillSkipNotify {
 		fmt.Printf("%s Notifying mayor...\n", style.Dim.Render("4."))
 		notifyBody := fmt.Sprintf("Agent %s was force-killed by Deacon.\nReason: %s", agent, reason)
+		if dump != "" {
+			notifyBody += "\nCrash dump: " + dump
+		}
 		sendMail(townRoot, "mayor/", "Agent killed: "+agent, notifyBody)
 	}
 
@@ -840,6 +890,9 @@ func runDeaconForceKill(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("%s Force-killed agent %s (total kills: %d)\n",
 		style.Bold.Render("✓"), agent, agentState.ForceKillCount)
+	if dump != "" {
+		fmt.Printf("  Crash dump: %s\n", dump)
+	}
 	fmt.Printf("  %s\n", style.Dim.Render("Agent is now 'asleep'. Use 'gt rig boot' to restart."))
 
 	return nil
@@ -910,9 +963,9 @@ func agentAddressToIDs(address string) (beadID, sessionName string, err error) {
 		rig, role := parts[0], parts[1]
 		switch role {
 		case "witness":
-			return fmt.Sprintf("gt-%s-witness", rig), fmt.Sprintf("gt-%s-witness", rig), nil
+			return beads.WitnessBeadID(rig), session.WitnessSessionName(rig), nil
 		case "refinery":
-			return fmt.Sprintf("gt-%s-refinery", rig), fmt.Sprintf("gt-%s-refinery", rig), nil
+			return beads.RefineryBeadID(rig), session.RefinerySessionName(rig), nil
 		default:
 			return "", "", fmt.Errorf("unknown role: %s", role)
 		}
@@ -921,9 +974,9 @@ func agentAddressToIDs(address string) (beadID, sessionName string, err error) {
 		rig, agentType, name := parts[0], parts[1], parts[2]
 		switch agentType {
 		case "polecats":
-			return fmt.Sprintf("gt-%s-polecat-%s", rig, name), fmt.Sprintf("gt-%s-%s", rig, name), nil
+			return beads.PolecatBeadID(rig, name), session.PolecatSessionName(rig, name), nil
 		case "crew":
-			return fmt.Sprintf("gt-%s-crew-%s", rig, name), fmt.Sprintf("gt-%s-crew-%s", rig, name), nil
+			return beads.CrewBeadID(rig, name), session.CrewSessionName(rig, name), nil
 		default:
 			return "", "", fmt.Errorf("unknown agent type: %s", agentType)
 		}
@@ -932,6 +985,21 @@ func agentAddressToIDs(address string) (beadID, sessionName string, err error) {
 	}
 }
 
+// forceKillDumpIDs returns the rig ("" for town-level agents) and event log
+// actor of an agent address, for its crash dump.
+func forceKillDumpIDs(address string) (rigName, actor string) {
+	parts := strings.Split(address, "/")
+	switch {
+	case len(parts) == 1:
+		return "", address + "/"
+	case len(parts) == 3 && parts[1] == "polecats":
+		// Polecats log events as rig/name
+		return parts[0], parts[0] + "/" + parts[2]
+	default:
+		return parts[0], address
+	}
+}
+
 // getAgentBeadUpdateTime gets the update time from an agent bead.
 func getAgentBeadUpdateTime(townRoot, beadID string) (time.Time, error) {
 	cmd := exec.Command("bd", "show", beadID, "--json")
@@ -1041,6 +1109,56 @@ func runDeaconStaleHooks(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runDeaconExpireLeases returns beads with expired work leases to the ready queue.
+func runDeaconExpireLeases(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	result, err := deacon.ExpireLeases(townRoot, &deacon.LeaseExpiryConfig{DryRun: expireLeasesDryRun})
+	if err != nil {
+		return fmt.Errorf("expiring leases: %w", err)
+	}
+
+	if result.TotalLeases == 0 {
+		fmt.Printf("%s No work leases held\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	fmt.Printf("%s Found %d lease(s), %d expired\n",
+		style.Bold.Render("●"), result.TotalLeases, result.ExpiredCount)
+
+	for _, r := range result.Results {
+		status := style.Dim.Render("○")
+		action := r.Action
+		switch r.Action {
+		case "released":
+			status = style.Bold.Render("✓")
+			action = "returned to ready queue"
+		case "would-release":
+			status = style.Bold.Render("?")
+			action = "would return to ready queue"
+		case "error":
+			status = style.Dim.Render("✗")
+			action = fmt.Sprintf("error: %s", r.Error)
+		}
+
+		fmt.Printf("  %s %s: %s (%s, expired %s ago, holder: %s)\n",
+			status, r.BeadID, action, r.Reason, r.Expired, r.Holder)
+	}
+
+	if expireLeasesDryRun {
+		fmt.Printf("\n%s Dry run - no changes made. Run without --dry-run to release.\n",
+			style.Dim.Render("ℹ"))
+	} else if result.Released > 0 {
+		fmt.Printf("\n%s Returned %d bead(s) to the ready queue\n",
+			style.Bold.Render("✓"), result.Released)
+	}
+
+	return nil
+}
+
 // runDeaconPause pauses the Deacon to prevent patrol actions.
 func runDeaconPause(cmd *cobra.Command, args []string) error {
 	townRoot, err := workspace.FindFromCwdOrError()
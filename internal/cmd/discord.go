@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/discord"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var discordCmd = &cobra.Command{
+	Use:     "discord",
+	GroupID: GroupComm,
+	Short:   "Post town events to Discord and take commands from it",
+	Long: `Connect the town to a Discord application, so teams that coordinate on
+Discord can supervise it from chat (gt slack does the same for Slack).
+
+Notifications: the daemon posts session lifecycle (agents starting,
+stopping, dying, paused as runaway loops), escalation, and merge events to
+Discord on every heartbeat (gt discord sync). With the application's bot
+token, each rig's events go to its own channel (rigs), town-level events to
+a channel per kind of event (channels) or the default channel; with a
+channel webhook, everything goes to its channel.
+
+Control: gt dashboard serves the application's interactions endpoint at
+POST /discord/interactions (set it as the Interactions Endpoint URL), and gt
+discord register registers the /gt command. Requests are verified with the
+application's public key.
+  /gt status                    the town's health (gt health)
+  /gt prompt <agent> <text>     nudge an agent (mayor, deacon,
+                                gastown/witness, gastown/polecats/toast, or a
+                                tmux session name)
+  /gt approve <mr> [message]    approve an MR under review (gt review)
+  /gt stop <agent>              stop an agent's session
+prompt, approve, and stop are only for the Discord users listed in
+operators, and are posted in the channel and recorded in the event log
+(gt events --type chat_command).
+
+Example gastown.toml:
+  [discord]
+  bot_token = "secret://discord-bot-token"
+  application_id = "1234567890123456789"
+  public_key = "<the application's public key>"
+  channel = "1122334455667788990"
+  operators = ["80351110224678912"]
+
+  [discord.rigs]
+  gastown = "1122334455667788991"
+
+Examples:
+  gt discord register           # Register the /gt command
+  gt discord test               # Post a test message
+  gt discord sync               # Post new events now`,
+	RunE: requireSubcommand,
+}
+
+var discordSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Post events logged since the last sync (run by the daemon)",
+	RunE:  runDiscordSync,
+}
+
+var discordTestCmd = &cobra.Command{
+	Use:   "test [message]",
+	Short: "Post a test message to the default channel",
+	RunE:  runDiscordTest,
+}
+
+var discordRegisterCmd = &cobra.Command{
+	Use:   "register",
+	Short: "Register the /gt command with Discord",
+	Long: `Register the /gt command for the application in gastown.toml, replacing
+the application's other commands. Needs bot_token and application_id.
+Discord can take up to an hour to show it in every server.`,
+	RunE: runDiscordRegister,
+}
+
+func init() {
+	discordCmd.AddCommand(discordSyncCmd)
+	discordCmd.AddCommand(discordTestCmd)
+	discordCmd.AddCommand(discordRegisterCmd)
+	rootCmd.AddCommand(discordCmd)
+}
+
+// discordPoster returns the town's Discord poster, or nil if it doesn't
+// post to Discord.
+func discordPoster(cfg config.DiscordConfig) (discord.Poster, error) {
+	switch {
+	case cfg.BotToken != "":
+		return discordClient(cfg)
+	case cfg.WebhookURL != "":
+		url, err := config.ExpandRefs(cfg.WebhookURL)
+		if err != nil {
+			return nil, fmt.Errorf("resolving discord.webhook_url: %w", err)
+		}
+		return discord.NewWebhook(url), nil
+	}
+	return nil, nil
+}
+
+// discordClient returns a client for the town's bot.
+func discordClient(cfg config.DiscordConfig) (*discord.Client, error) {
+	if cfg.BotToken == "" {
+		return nil, fmt.Errorf("discord not configured: set bot_token in gastown.toml's [discord]")
+	}
+	token, err := config.ExpandRefs(cfg.BotToken)
+	if err != nil {
+		return nil, fmt.Errorf("resolving discord.bot_token: %w", err)
+	}
+	return discord.NewClient(token), nil
+}
+
+// discordPublicKey returns the town's Discord public key ("" = slash
+// commands aren't configured).
+func discordPublicKey(townRoot string) (string, error) {
+	cfg, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return "", err
+	}
+	return cfg.Discord.PublicKey, nil
+}
+
+func runDiscordSync(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	poster, err := discordPoster(cfg.Discord)
+	if err != nil || poster == nil {
+		return err
+	}
+	posted, err := discord.Sync(context.Background(), townRoot, cfg.Discord, poster)
+	if posted > 0 {
+		fmt.Printf("Posted %d event(s) to Discord\n", posted)
+	}
+	return err
+}
+
+func runDiscordTest(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	poster, err := discordPoster(cfg.Discord)
+	if err != nil {
+		return err
+	}
+	if poster == nil {
+		return fmt.Errorf("discord not configured: set bot_token or webhook_url in gastown.toml's [discord]")
+	}
+	text := strings.Join(args, " ")
+	if text == "" {
+		name, _ := workspace.GetTownName(townRoot)
+		text = strings.TrimSpace(fmt.Sprintf("👋 Gas Town %s is connected", name))
+	}
+	if err := poster.Post(context.Background(), cfg.Discord.Channel, text); err != nil {
+		return err
+	}
+	fmt.Printf("%s Posted to Discord\n", style.Success.Render("✓"))
+	return nil
+}
+
+func runDiscordRegister(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	client, err := discordClient(cfg.Discord)
+	if err != nil {
+		return err
+	}
+	if err := client.RegisterCommands(context.Background(), cfg.Discord.ApplicationID); err != nil {
+		return err
+	}
+	fmt.Printf("%s Registered /gt for application %s\n", style.Success.Render("✓"), cfg.Discord.ApplicationID)
+	return nil
+}
+
+// runDiscordCommand runs a verified /gt slash command for the town.
+func runDiscordCommand(townRoot string, i *discord.Interaction) discord.Response {
+	verb, opts := i.Subcommand()
+	user := i.Invoker()
+	target := opts["agent"]
+	if verb == "approve" {
+		target = opts["mr"]
+	}
+	cc := chatCommand{Via: "discord", Verb: verb, Target: target, Text: opts["text"] + opts["message"], UserID: user.ID, UserName: user.Username}
+	reply := runChatCommand(townRoot, cc, func(cfg *config.GastownConfig, id string) bool { return cfg.Discord.IsOperator(id) })
+	return discord.Response{Text: reply.Text, InChannel: reply.InChannel}
+}
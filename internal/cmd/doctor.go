@@ -31,6 +31,7 @@ Workspace checks:
   - rigs-registry-exists     Check mayor/rigs.json exists (fixable)
   - rigs-registry-valid      Check registered rigs exist (fixable)
   - mayor-exists             Check mayor/ directory structure
+  - state-versions           Check state files are in the current formats (fixable)
 
 Town root protection:
   - town-git                 Verify town root is under version control
@@ -163,6 +164,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewRuntimeGitignoreCheck())
 	d.Register(doctor.NewLegacyGastownCheck())
 	d.Register(doctor.NewClaudeSettingsCheck())
+	d.Register(doctor.NewStateVersionCheck())
 
 	// Priming subsystem check
 	d.Register(doctor.NewPrimingCheck())
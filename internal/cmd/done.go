@@ -6,14 +6,19 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/forge"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/pr"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -115,7 +120,7 @@ func runDone(cmd *cobra.Command, args []string) error {
 	}
 
 	// Find current rig
-	rigName, _, err := findCurrentRig(townRoot)
+	rigName, r, err := findCurrentRig(townRoot)
 	if err != nil {
 		return err
 	}
@@ -129,6 +134,7 @@ func runDone(cmd *cobra.Command, args []string) error {
 		mayorClone := filepath.Join(townRoot, rigName, "mayor", "rig")
 		g = git.NewGit(mayorClone)
 	}
+	g.SetProtectedBranches(r.ProtectedBranches())
 
 	// Get current branch - try env var first if cwd is gone
 	var branch string
@@ -222,7 +228,7 @@ func runDone(cmd *cobra.Command, args []string) error {
 	}
 
 	// For COMPLETED, we need an issue ID and branch must not be the default branch
-	var mrID string
+	var mrID, prURL string
 	if exitType == ExitCompleted {
 		if branch == defaultBranch || branch == "master" {
 			return fmt.Errorf("cannot submit %s/master branch to merge queue", defaultBranch)
@@ -355,6 +361,16 @@ func runDone(cmd *cobra.Command, args []string) error {
 			fmt.Printf("%s Work submitted to merge queue\n", style.Bold.Render("✓"))
 			fmt.Printf("  MR ID: %s\n", style.Bold.Render(mrID))
 		}
+		// Open a forge pull request if the rig requires review upstream
+		prURL = openPullRequest(townRoot, rigName, g, bd, pullRequestWork{
+			IssueID: issueID,
+			MRID:    mrID,
+			Branch:  branch,
+			Target:  target,
+			Worker:  worker,
+			Sender:  sender,
+		})
+
 		fmt.Printf("  Source: %s\n", branch)
 		fmt.Printf("  Target: %s\n", target)
 		fmt.Printf("  Issue: %s\n", issueID)
@@ -362,6 +378,9 @@ func runDone(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  Worker: %s\n", worker)
 		}
 		fmt.Printf("  Priority: P%d\n", priority)
+		if prURL != "" {
+			fmt.Printf("  PR: %s\n", prURL)
+		}
 		fmt.Println()
 		fmt.Printf("%s\n", style.Dim.Render("The Refinery will process your merge request."))
 	} else if exitType == ExitPhaseComplete {
@@ -405,6 +424,9 @@ func runDone(cmd *cobra.Command, args []string) error {
 	if mrID != "" {
 		bodyLines = append(bodyLines, fmt.Sprintf("MR: %s", mrID))
 	}
+	if prURL != "" {
+		bodyLines = append(bodyLines, fmt.Sprintf("PR: %s", prURL))
+	}
 	if doneGate != "" {
 		bodyLines = append(bodyLines, fmt.Sprintf("Gate: %s", doneGate))
 	}
@@ -443,7 +465,17 @@ func runDone(cmd *cobra.Command, args []string) error {
 
 	// Log done event (townlog and activity feed)
 	_ = LogDone(townRoot, sender, issueID)
-	_ = events.LogFeed(events.TypeDone, sender, events.DonePayload(issueID, branch))
+	releaseWorkLease(townRoot, issueID)
+	donePayload := events.DonePayload(issueID, branch)
+	if prURL != "" {
+		donePayload["pr_url"] = prURL
+	}
+	_ = events.LogFeed(events.TypeDone, sender, donePayload)
+	var outcomeBase string
+	if exitType == ExitCompleted && cwdAvailable {
+		outcomeBase = "origin/" + defaultBranch
+	}
+	recordDone(townRoot, issueID, sender, exitType, prURL, g, outcomeBase)
 
 	// Update agent bead state (ZFC: self-report completion)
 	updateAgentStateOnDone(cwd, townRoot, exitType, issueID)
@@ -577,6 +609,7 @@ func updateAgentStateOnDone(cwd, townRoot, exitType, _ string) { // issueID unus
 				fmt.Fprintf(os.Stderr, "Warning: couldn't close hooked bead %s: %v\n", hookedBeadID, err)
 			}
 		}
+		releaseWorkLease(townRoot, hookedBeadID)
 	}
 
 	// Clear the hook (work is done) - gt-zecmc
@@ -705,7 +738,7 @@ func selfKillSession(townRoot string, roleInfo RoleInfo) error {
 		return fmt.Errorf("cannot determine session: rig=%q, polecat=%q", rigName, polecatName)
 	}
 
-	sessionName := fmt.Sprintf("gt-%s-%s", rigName, polecatName)
+	sessionName := session.PolecatSessionName(rigName, polecatName)
 	agentID := fmt.Sprintf("%s/polecats/%s", rigName, polecatName)
 
 	// Log to townlog (human-readable audit log)
@@ -728,3 +761,140 @@ func selfKillSession(townRoot string, roleInfo RoleInfo) error {
 
 	return nil
 }
+
+// sessionSummary summarizes the tool calls made by actor since beadID was
+// slung to it, up to until, or returns "" if the sling can't be found.
+func sessionSummary(townRoot, beadID, actor string, until time.Time) string {
+	slings, err := events.Query(townRoot, events.Filter{Types: []string{events.TypeSling}, Until: until})
+	if err != nil {
+		return ""
+	}
+	var since events.Event
+	for _, s := range slings {
+		if bead, _ := s.Payload["bead"].(string); bead == beadID {
+			since = s
+		}
+	}
+	if since.Timestamp == "" {
+		return ""
+	}
+	calls, err := events.Query(townRoot, events.Filter{
+		Types: []string{events.TypeToolExec},
+		Actor: actor,
+		Since: since.Time(),
+		Until: until,
+	})
+	if err != nil {
+		return ""
+	}
+	// Filter's Actor is a prefix, which would take in gastown/polecats/toast2
+	// for gastown/polecats/toast.
+	var own []events.Event
+	for _, c := range calls {
+		if c.Actor == actor {
+			own = append(own, c)
+		}
+	}
+	return events.Summarize(own)
+}
+
+// pullRequestWork identifies the submitted work a pull request is opened for.
+type pullRequestWork struct {
+	IssueID string
+	MRID    string
+	Branch  string
+	Target  string
+	Worker  string
+	Sender  string
+}
+
+// openPullRequest opens a forge pull request for a pushed polecat branch when
+// the rig's settings enable pull_requests. The PR URL is recorded on the MR
+// bead and the Refinery is notified. Failures are warnings: the MR bead is
+// already in the merge queue, so gt done must not fail over forge problems.
+// Returns the PR URL, or "" if no PR was opened.
+func openPullRequest(townRoot, rigName string, g *git.Git, bd *beads.Beads, work pullRequestWork) string {
+	rigPath := filepath.Join(townRoot, rigName)
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil || settings.PullRequests == nil || !settings.PullRequests.Enabled {
+		return ""
+	}
+	prCfg := settings.PullRequests
+
+	remoteURL, _ := g.RemoteURL("origin")
+	f, err := forge.Resolve(settings, remoteURL)
+	if err != nil {
+		style.PrintWarning("%v (set forge.type in %s)", err, config.RigSettingsPath(rigPath))
+		return ""
+	}
+
+	body := pr.BodyInput{
+		IssueID: work.IssueID,
+		MRID:    work.MRID,
+		Rig:     rigName,
+		Worker:  work.Worker,
+	}
+	if issue, err := bd.Show(work.IssueID); err == nil {
+		body.IssueTitle = issue.Title
+		body.IssueDescription = issue.Description
+	}
+	if commits, err := g.CommitSubjects("origin/"+work.Target, work.Branch); err == nil {
+		body.Commits = commits
+	}
+	body.Session = sessionSummary(townRoot, work.IssueID, work.Sender, time.Now())
+
+	fmt.Printf("Opening pull request...\n")
+	pull, err := f.CreatePR(g.WorkDir(), forge.PRRequest{
+		Branch:    work.Branch,
+		Base:      work.Target,
+		Title:     pr.Title(body),
+		Body:      pr.BuildBody(body),
+		Draft:     prCfg.Draft,
+		Labels:    prCfg.Labels,
+		Reviewers: prCfg.Reviewers,
+	})
+	if err != nil {
+		style.PrintWarning("could not open pull request: %v", err)
+		return ""
+	}
+	if pull.Existing {
+		fmt.Printf("%s Pull request already open (idempotent)\n", style.Bold.Render("✓"))
+	} else {
+		fmt.Printf("%s Pull request opened\n", style.Bold.Render("✓"))
+	}
+
+	// Link the PR back to the MR bead
+	if mr, err := bd.Show(work.MRID); err == nil {
+		fields := beads.ParseMRFields(mr)
+		if fields == nil {
+			fields = &beads.MRFields{}
+		}
+		if fields.PRURL != pull.URL {
+			fields.PRURL = pull.URL
+			desc := beads.SetMRFields(mr, fields)
+			if err := bd.Update(work.MRID, beads.UpdateOptions{Description: &desc}); err != nil {
+				style.PrintWarning("could not link pull request to MR bead: %v", err)
+			}
+		}
+	}
+
+	// Notify the Refinery so it can track the review alongside the MR
+	if !pull.Existing {
+		notification := &mail.Message{
+			To:      fmt.Sprintf("%s/refinery", rigName),
+			From:    work.Sender,
+			Subject: fmt.Sprintf("PR_OPENED %s", work.IssueID),
+			Body: strings.Join([]string{
+				fmt.Sprintf("Issue: %s", work.IssueID),
+				fmt.Sprintf("MR: %s", work.MRID),
+				fmt.Sprintf("PR: %s", pull.URL),
+				fmt.Sprintf("Branch: %s", work.Branch),
+			}, "\n"),
+		}
+		if err := mail.NewRouter(townRoot).Send(notification); err != nil {
+			style.PrintWarning("could not notify refinery of pull request: %v", err)
+		}
+	}
+
+	return pull.URL
+}
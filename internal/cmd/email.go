@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/email"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Email command flags
+var emailSyncFlush bool
+
+var emailCmd = &cobra.Command{
+	Use:     "email",
+	GroupID: GroupComm,
+	Short:   "Email high-severity events to operators",
+	Long: `Email the town's high-severity events, for operators who don't run a chat
+integration (gt slack, gt discord).
+
+The daemon emails these events on every heartbeat (gt email sync):
+  budget      a rig went over its daily or weekly budget (gt budget)
+  crash       a polecat crashed 3 times within an hour, or several sessions
+              died at once
+  escalation  a high or critical escalation (gt escalate)
+
+gastown.toml's [email] section sets the SMTP server, and who gets the
+town's events. A rig can send its own events to its own recipients with
+"email" in <rig>/settings/config.json:
+  "email": {"to": ["ops@example.com"], "events": ["budget", "crash"], "digest": "daily"}
+
+With a digest ("hourly" or "daily"), events are collected into one email an
+hour or a day instead of sent as they happen.
+
+Example gastown.toml:
+  [email]
+  smtp_host = "smtp.example.com"
+  username = "gastown@example.com"
+  password = "secret://smtp-password"
+  from = "Gas Town <gastown@example.com>"
+  to = ["oncall@example.com"]
+  digest = "hourly"
+
+Examples:
+  gt email test                 # Send a test email to the town's recipients
+  gt email sync --flush         # Send new events and every pending digest now`,
+	RunE: requireSubcommand,
+}
+
+var emailSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Email events logged since the last sync (run by the daemon)",
+	RunE:  runEmailSync,
+}
+
+var emailTestCmd = &cobra.Command{
+	Use:   "test [message]",
+	Short: "Send a test email to the town's recipients",
+	RunE:  runEmailTest,
+}
+
+func init() {
+	emailSyncCmd.Flags().BoolVar(&emailSyncFlush, "flush", false, "Send pending digests now, even if they aren't due")
+
+	emailCmd.AddCommand(emailSyncCmd)
+	emailCmd.AddCommand(emailTestCmd)
+	rootCmd.AddCommand(emailCmd)
+}
+
+// emailNotifier returns the town's notifier, or nil if it doesn't send
+// email.
+func emailNotifier(townRoot string) (*email.Notifier, error) {
+	cfg, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.Email.Sending() {
+		return nil, nil
+	}
+	password := ""
+	if cfg.Email.Password != "" {
+		if password, err = config.ExpandRefs(cfg.Email.Password); err != nil {
+			return nil, fmt.Errorf("resolving email.password: %w", err)
+		}
+	}
+
+	n := &email.Notifier{
+		Town: cfg.Email,
+		Rigs: make(map[string]config.EmailRouting),
+		Mailer: &email.SMTP{
+			Host:     cfg.Email.SMTPHost,
+			Port:     cfg.Email.Port(),
+			Username: cfg.Email.Username,
+			Password: password,
+			From:     cfg.Email.From,
+		},
+		Prefix: "[gt]",
+	}
+	if name, _ := workspace.GetTownName(townRoot); name != "" {
+		n.Prefix = fmt.Sprintf("[gt %s]", name)
+	}
+	if rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot)); err == nil {
+		for name := range rigsConfig.Rigs {
+			settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, name)))
+			if err == nil && settings.Email != nil {
+				n.Rigs[name] = *settings.Email
+			}
+		}
+	}
+	return n, nil
+}
+
+func runEmailSync(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	n, err := emailNotifier(townRoot)
+	if err != nil || n == nil {
+		return err
+	}
+	sent, err := n.Sync(townRoot, emailSyncFlush)
+	if sent > 0 {
+		fmt.Printf("Sent %d email(s)\n", sent)
+	}
+	return err
+}
+
+func runEmailTest(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	n, err := emailNotifier(townRoot)
+	if err != nil {
+		return err
+	}
+	if n == nil {
+		return fmt.Errorf("email not configured: set smtp_host and from in gastown.toml's [email]")
+	}
+	to := n.Town.To
+	if len(to) == 0 {
+		// Any rig's recipients will do
+		var rigs []string
+		for name, r := range n.Rigs {
+			if len(r.To) > 0 {
+				rigs = append(rigs, name)
+			}
+		}
+		sort.Strings(rigs)
+		if len(rigs) == 0 {
+			return fmt.Errorf("no recipients: set to in gastown.toml's [email] or a rig's settings")
+		}
+		to = n.Rigs[rigs[0]].To
+	}
+	text := strings.Join(args, " ")
+	if text == "" {
+		text = "Gas Town can reach you by email."
+	}
+	if err := n.Mailer.Send(to, n.Prefix+" Test", text+"\n"); err != nil {
+		return err
+	}
+	fmt.Printf("%s Sent to %s\n", style.Success.Render("✓"), strings.Join(to, ", "))
+	return nil
+}
@@ -16,6 +16,8 @@ var (
 	escalateStaleJSON   bool
 	escalateDryRun      bool
 	escalateCloseReason string
+	escalateHandoff     bool
+	escalateSummary     string
 )
 
 var escalateCmd = &cobra.Command{
@@ -41,6 +43,12 @@ WORKFLOW:
   4. Recipient acknowledges with: gt escalate ack <id>
   5. After resolution: gt escalate close <id> --reason "fixed"
 
+HANDOFF TO CREW:
+  A blocked polecat can hand its work to a human crew member with --handoff.
+  The branch is pushed, and the branch, summary, and bead are packaged as a
+  crew assignment. The bead is parked (status blocked) and the overseer is
+  mailed. A crew member picks it up with 'gt handoff accept <bead>'.
+
 CONFIGURATION:
   Routing is configured in ~/gt/settings/escalation.json:
   - routes: Map severity to action lists (bead, mail:mayor, email:human, sms:human)
@@ -52,6 +60,7 @@ Examples:
   gt escalate "Build failing" --severity critical --reason "CI blocked"
   gt escalate "Need API credentials" --severity high --source "plugin:rebuild-gt"
   gt escalate "Code review requested" --reason "PR #123 ready"
+  gt escalate "Need OAuth credentials" --handoff --summary "Callback done; token exchange untested"
   gt escalate list                          # Show open escalations
   gt escalate ack hq-abc123                 # Acknowledge
   gt escalate close hq-abc123 --reason "Fixed in commit abc"
@@ -143,6 +152,8 @@ func init() {
 	escalateCmd.Flags().StringVar(&escalateRelatedBead, "related", "", "Related bead ID (task, bug, etc.)")
 	escalateCmd.Flags().BoolVar(&escalateJSON, "json", false, "Output as JSON")
 	escalateCmd.Flags().BoolVarP(&escalateDryRun, "dry-run", "n", false, "Show what would be done without executing")
+	escalateCmd.Flags().BoolVar(&escalateHandoff, "handoff", false, "Hand this polecat's work off to crew (polecats only)")
+	escalateCmd.Flags().StringVar(&escalateSummary, "summary", "", "Summary of what was tried, for the crew member (default: --reason)")
 
 	// List subcommand flags
 	escalateListCmd.Flags().BoolVar(&escalateListJSON, "json", false, "Output as JSON")
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/handoff"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// handoffStatusBlocked parks handed-off work: it keeps the bead out of the
+// ready queue and stops gt done from closing it as the polecat exits.
+const handoffStatusBlocked = "blocked"
+
+// prepareHandoff gathers a polecat's branch, bead, and summary into a handoff
+// package. The branch is pushed so a crew clone can fetch it.
+func prepareHandoff(townRoot, description string) (*handoff.Package, error) {
+	roleInfo, err := GetRole()
+	if err != nil {
+		return nil, fmt.Errorf("detecting role: %w", err)
+	}
+	if roleInfo.Role != RolePolecat || roleInfo.Rig == "" || roleInfo.Polecat == "" {
+		return nil, fmt.Errorf("--handoff is for polecats (current role: %s)", roleInfo.Role)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("getting current directory: %w", err)
+	}
+	g := git.NewGit(cwd)
+	g.SetProtectedBranches((&rig.Rig{Name: roleInfo.Rig, Path: filepath.Join(townRoot, roleInfo.Rig)}).ProtectedBranches())
+	branch, err := g.CurrentBranch()
+	if err != nil || branch == "" || branch == "HEAD" {
+		return nil, fmt.Errorf("cannot hand off: not on a branch")
+	}
+	if dirty, err := g.HasUncommittedChanges(); err == nil && dirty {
+		return nil, fmt.Errorf("cannot hand off: commit your work first so crew can pick it up")
+	}
+
+	bd := beads.New(filepath.Join(townRoot, roleInfo.Rig))
+	beadID := escalateRelatedBead
+	if beadID == "" {
+		agentID := fmt.Sprintf("%s/polecats/%s", roleInfo.Rig, roleInfo.Polecat)
+		hooked, err := bd.List(beads.ListOptions{Status: beads.StatusHooked, Assignee: agentID, Priority: -1})
+		if err != nil || len(hooked) == 0 {
+			return nil, fmt.Errorf("cannot hand off: no hooked bead (use --related <bead>)")
+		}
+		beadID = hooked[0].ID
+	}
+
+	pkg := &handoff.Package{
+		Bead:      beadID,
+		Rig:       roleInfo.Rig,
+		Polecat:   roleInfo.Polecat,
+		Branch:    branch,
+		Blocker:   description,
+		Summary:   escalateSummary,
+		SessionID: runtime.SessionIDFromEnv(),
+	}
+	if pkg.Summary == "" {
+		pkg.Summary = escalateReason
+	}
+	if pkg.SessionID == "" {
+		pkg.SessionID = ReadPersistedSessionID()
+	}
+	if issue, err := bd.Show(beadID); err == nil {
+		pkg.Title = issue.Title
+	}
+	if base := g.RemoteDefaultBranch(); base != "" {
+		if commits, err := g.CommitSubjects("origin/"+base, "HEAD"); err == nil {
+			pkg.Commits = commits
+		}
+	}
+
+	if escalateDryRun {
+		return pkg, nil
+	}
+	if err := g.Push("origin", branch, false); err != nil {
+		return nil, fmt.Errorf("pushing %s for handoff: %w", branch, err)
+	}
+	return pkg, nil
+}
+
+// finishHandoff records the package, parks the bead, and notifies the overseer.
+func finishHandoff(townRoot string, pkg *handoff.Package, escalationID string) error {
+	pkg.Escalation = escalationID
+	if err := handoff.Save(townRoot, pkg); err != nil {
+		return fmt.Errorf("saving handoff: %w", err)
+	}
+
+	bd := beads.New(filepath.Join(townRoot, pkg.Rig))
+	status := handoffStatusBlocked
+	if err := bd.Update(pkg.Bead, beads.UpdateOptions{Status: &status}); err != nil {
+		style.PrintWarning("could not park %s: %v", pkg.Bead, err)
+	}
+
+	router := mail.NewRouter(townRoot)
+	msg := &mail.Message{
+		From:     pkg.From(),
+		To:       "overseer",
+		Subject:  fmt.Sprintf("HANDOFF %s: %s", pkg.Bead, pkg.Blocker),
+		Body:     handoff.FormatAssignment(pkg),
+		Type:     mail.TypeTask,
+		Priority: mail.PriorityHigh,
+	}
+	if err := router.Send(msg); err != nil {
+		style.PrintWarning("failed to notify overseer: %v", err)
+	}
+
+	fmt.Printf("%s Handoff packaged: %s (branch %s)\n", style.Bold.Render("🤝"), pkg.Bead, pkg.Branch)
+	fmt.Printf("  Crew picks it up with: gt handoff accept %s\n", pkg.Bead)
+	fmt.Printf("  Now exit with: gt done --status %s\n", ExitEscalated)
+	return nil
+}
+
+// printHandoffDryRun shows what a handoff would package.
+func printHandoffDryRun(pkg *handoff.Package) {
+	fmt.Printf("Would package handoff:\n")
+	fmt.Printf("  Bead: %s\n", pkg.Bead)
+	fmt.Printf("  Branch: %s (pushed to origin)\n", pkg.Branch)
+	if len(pkg.Commits) > 0 {
+		fmt.Printf("  Commits: %s\n", strings.Join(pkg.Commits, "; "))
+	}
+	fmt.Printf("  Notify: overseer\n")
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/handoff"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -48,6 +50,16 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		agentID = "unknown"
 	}
 
+	// Package a polecat's work for crew before escalating
+	var handoffPkg *handoff.Package
+	if escalateHandoff {
+		handoffPkg, err = prepareHandoff(townRoot, description)
+		if err != nil {
+			return err
+		}
+		escalateRelatedBead = handoffPkg.Bead
+	}
+
 	// Dry run mode
 	if escalateDryRun {
 		actions := escalationConfig.GetRouteForSeverity(severity)
@@ -63,73 +75,34 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		}
 		fmt.Printf("  Actions: %s\n", strings.Join(actions, ", "))
 		fmt.Printf("  Mail targets: %s\n", strings.Join(targets, ", "))
+		if handoffPkg != nil {
+			printHandoffDryRun(handoffPkg)
+		}
 		return nil
 	}
 
-	// Create escalation bead
-	bd := beads.New(beads.ResolveBeadsDir(townRoot))
-	fields := &beads.EscalationFields{
+	issueID, actions, targets, err := sendEscalation(townRoot, escalationConfig, escalationRequest{
+		Description: description,
 		Severity:    severity,
 		Reason:      escalateReason,
 		Source:      escalateSource,
-		EscalatedBy: agentID,
-		EscalatedAt: time.Now().Format(time.RFC3339),
+		From:        agentID,
 		RelatedBead: escalateRelatedBead,
-	}
-
-	issue, err := bd.CreateEscalationBead(description, fields)
+	})
 	if err != nil {
-		return fmt.Errorf("creating escalation bead: %w", err)
+		return err
 	}
 
-	// Get routing actions for this severity
-	actions := escalationConfig.GetRouteForSeverity(severity)
-	targets := extractMailTargetsFromActions(actions)
-
-	// Send mail to each target (actions with "mail:" prefix)
-	router := mail.NewRouter(townRoot)
-	for _, target := range targets {
-		msg := &mail.Message{
-			From:    agentID,
-			To:      target,
-			Subject: fmt.Sprintf("[%s] %s", strings.ToUpper(severity), description),
-			Body:    formatEscalationMailBody(issue.ID, severity, escalateReason, agentID, escalateRelatedBead),
-			Type:    mail.TypeTask,
-		}
-
-		// Set priority based on severity
-		switch severity {
-		case config.SeverityCritical:
-			msg.Priority = mail.PriorityUrgent
-		case config.SeverityHigh:
-			msg.Priority = mail.PriorityHigh
-		case config.SeverityMedium:
-			msg.Priority = mail.PriorityNormal
-		default:
-			msg.Priority = mail.PriorityLow
-		}
-
-		if err := router.Send(msg); err != nil {
-			style.PrintWarning("failed to send to %s: %v", target, err)
+	if handoffPkg != nil {
+		if err := finishHandoff(townRoot, handoffPkg, issueID); err != nil {
+			return err
 		}
 	}
 
-	// Process external notification actions (email:, sms:, slack)
-	executeExternalActions(actions, escalationConfig, issue.ID, severity, description)
-
-	// Log to activity feed
-	payload := events.EscalationPayload(issue.ID, agentID, strings.Join(targets, ","), description)
-	payload["severity"] = severity
-	payload["actions"] = strings.Join(actions, ",")
-	if escalateSource != "" {
-		payload["source"] = escalateSource
-	}
-	_ = events.LogFeed(events.TypeEscalationSent, agentID, payload)
-
 	// Output
 	if escalateJSON {
 		result := map[string]interface{}{
-			"id":       issue.ID,
+			"id":       issueID,
 			"severity": severity,
 			"actions":  actions,
 			"targets":  targets,
@@ -141,7 +114,7 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		fmt.Println(string(out))
 	} else {
 		emoji := severityEmoji(severity)
-		fmt.Printf("%s Escalation created: %s\n", emoji, issue.ID)
+		fmt.Printf("%s Escalation created: %s\n", emoji, issueID)
 		fmt.Printf("  Severity: %s\n", severity)
 		if escalateSource != "" {
 			fmt.Printf("  Source: %s\n", escalateSource)
@@ -538,29 +511,118 @@ func extractMailTargetsFromActions(actions []string) []string {
 	return targets
 }
 
+// escalationRequest is an escalation to create and route.
+type escalationRequest struct {
+	Description string
+	Severity    string
+	Reason      string
+	Source      string
+	From        string
+	RelatedBead string
+}
+
+// sendEscalation creates the escalation bead, mails the targets routed for its
+// severity, runs external notification actions, and logs it to the feed.
+func sendEscalation(townRoot string, escalationConfig *config.EscalationConfig, req escalationRequest) (issueID string, actions, targets []string, err error) {
+	// Create escalation bead
+	bd := beads.New(beads.ResolveBeadsDir(townRoot))
+	fields := &beads.EscalationFields{
+		Severity:    req.Severity,
+		Reason:      req.Reason,
+		Source:      req.Source,
+		EscalatedBy: req.From,
+		EscalatedAt: time.Now().Format(time.RFC3339),
+		RelatedBead: req.RelatedBead,
+	}
+
+	issue, err := bd.CreateEscalationBead(req.Description, fields)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("creating escalation bead: %w", err)
+	}
+
+	// Get routing actions for this severity
+	actions = escalationConfig.GetRouteForSeverity(req.Severity)
+	targets = extractMailTargetsFromActions(actions)
+
+	// Send mail to each target (actions with "mail:" prefix)
+	router := mail.NewRouter(townRoot)
+	for _, target := range targets {
+		msg := &mail.Message{
+			From:    req.From,
+			To:      target,
+			Subject: fmt.Sprintf("[%s] %s", strings.ToUpper(req.Severity), req.Description),
+			Body:    formatEscalationMailBody(issue.ID, req.Severity, req.Reason, req.From, req.RelatedBead),
+			Type:    mail.TypeTask,
+		}
+
+		// Set priority based on severity
+		switch req.Severity {
+		case config.SeverityCritical:
+			msg.Priority = mail.PriorityUrgent
+		case config.SeverityHigh:
+			msg.Priority = mail.PriorityHigh
+		case config.SeverityMedium:
+			msg.Priority = mail.PriorityNormal
+		default:
+			msg.Priority = mail.PriorityLow
+		}
+
+		if err := router.Send(msg); err != nil {
+			style.PrintWarning("failed to send to %s: %v", target, err)
+		}
+	}
+
+	// Process external notification actions (email:, sms:, slack)
+	if err := executeExternalActions(actions, escalationConfig, issue.ID, req.Severity, req.Description); err != nil {
+		style.PrintWarning("external notification skipped: %v", err)
+	}
+
+	// Log to activity feed
+	payload := events.EscalationPayload(issue.ID, req.From, strings.Join(targets, ","), req.Description)
+	payload["severity"] = req.Severity
+	payload["actions"] = strings.Join(actions, ",")
+	if req.Source != "" {
+		payload["source"] = req.Source
+	}
+	_ = events.LogFeed(events.TypeEscalationSent, req.From, payload)
+
+	return issue.ID, actions, targets, nil
+}
+
 // executeExternalActions processes external notification actions (email:, sms:, slack).
 // For now, this logs warnings if contacts aren't configured - actual sending is future work.
-func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _, _ string) {
+// Returns the contacts whose references could not be resolved.
+func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _, _ string) error {
+	var errs []error
 	for _, action := range actions {
 		switch {
 		case strings.HasPrefix(action, "email:"):
-			if cfg.Contacts.HumanEmail == "" {
+			email, err := config.ResolveRef("contacts.human_email", cfg.Contacts.HumanEmail)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("email action '%s': %w", action, err))
+			} else if email == "" {
 				style.PrintWarning("email action '%s' skipped: contacts.human_email not configured in settings/escalation.json", action)
 			} else {
 				// TODO: Implement actual email sending
-				fmt.Printf("  📧 Would send email to %s (not yet implemented)\n", cfg.Contacts.HumanEmail)
+				fmt.Printf("  📧 Would send email to %s (not yet implemented)\n", email)
 			}
 
 		case strings.HasPrefix(action, "sms:"):
-			if cfg.Contacts.HumanSMS == "" {
+			sms, err := config.ResolveRef("contacts.human_sms", cfg.Contacts.HumanSMS)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("sms action '%s': %w", action, err))
+			} else if sms == "" {
 				style.PrintWarning("sms action '%s' skipped: contacts.human_sms not configured in settings/escalation.json", action)
 			} else {
 				// TODO: Implement actual SMS sending
-				fmt.Printf("  📱 Would send SMS to %s (not yet implemented)\n", cfg.Contacts.HumanSMS)
+				fmt.Printf("  📱 Would send SMS to %s (not yet implemented)\n", sms)
 			}
 
 		case action == "slack":
-			if cfg.Contacts.SlackWebhook == "" {
+			webhook, err := config.ResolveRef("contacts.slack_webhook", cfg.Contacts.SlackWebhook)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("slack action: %w", err))
+			} else if webhook == "" {
 				style.PrintWarning("slack action skipped: contacts.slack_webhook not configured in settings/escalation.json")
 			} else {
 				// TODO: Implement actual Slack webhook posting
@@ -573,6 +635,7 @@ func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _
 			fmt.Printf("  📝 Logged to escalation log\n")
 		}
 	}
+	return errors.Join(errs...)
 }
 
 func formatEscalationMailBody(beadID, severity, reason, from, related string) string {
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestExecuteExternalActions_ResolvesRefs(t *testing.T) {
+	cfg := config.NewEscalationConfig()
+	cfg.Contacts.SlackWebhook = "${GT_TEST_ESCALATION_SLACK}"
+
+	t.Setenv("GT_TEST_ESCALATION_SLACK", "https://hooks.slack.example/T0/B0")
+	if err := executeExternalActions([]string{"slack"}, cfg, "", "", ""); err != nil {
+		t.Errorf("executeExternalActions with set webhook ref: %v", err)
+	}
+
+	t.Setenv("GT_TEST_ESCALATION_SLACK", "")
+	cfg.Contacts.SlackWebhook = "${GT_TEST_ESCALATION_SLACK_UNSET}"
+	err := executeExternalActions([]string{"slack"}, cfg, "", "", "")
+	if !errors.Is(err, config.ErrUnresolvedRef) {
+		t.Errorf("executeExternalActions with unset webhook ref = %v, want ErrUnresolvedRef", err)
+	}
+}
@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Events command flags
+var (
+	eventsType  string
+	eventsActor string
+	eventsSince string
+	eventsUntil string
+	eventsLimit int
+	eventsJSON  bool
+
+	eventsPruneOlderThan string
+)
+
+var eventsCmd = &cobra.Command{
+	Use:     "events",
+	GroupID: GroupDiag,
+	Short:   "Query the town's event/audit log",
+	Long: `Query the town's append-only event log (.events.jsonl).
+
+Besides activity feed events (sling, done, merged, ...), the log records
+for audit:
+  agent_started / agent_stopped - agent tmux sessions starting and stopping
+  prompt_sent                   - prompts nudged into sessions (SHA-256 and
+                                  length; text too with [events] record_prompts)
+  tool_exec                     - tools agents ran, with their arguments
+  merged / merge_failed         - merges the refinery performed or refused
+  config_change                 - settings changed through gt
+  policy_denied                 - tool calls the tool policy blocked
+  prompt_filtered               - prompts the outbound filter changed or
+                                  refused (gt outbound)
+  loop_detected / loop_resumed  - sessions paused as runaway loops (gt loop)
+  cost_anomaly                  - rigs whose hourly spend spiked (gt budget)
+  budget_exceeded               - rigs over their budget (gt budget)
+  crash_loop                    - polecats that keep crashing
+  chat_command                  - prompts, approvals, and stops sent from
+                                  Slack or Discord
+  events_pruned                 - old events removed from a chained log
+
+With [events] chain, each event records the hash of the one before it, and
+with [events] signing_key it is also signed; gt events verify checks both.
+
+The dashboard serves the same query at GET /api/events.
+
+Examples:
+  gt events                                  # Last 50 events
+  gt events --type tool_exec --actor gastown/polecats/toast
+  gt events --type prompt_sent,tool_exec --since 2h
+  gt events --since 7d --until 1d --json     # A window, as JSON lines`,
+	RunE: runEvents,
+}
+
+var eventsPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove events older than the retention period",
+	Long: `Remove events older than the town's retention period from the log.
+
+The retention period is [events] retention_days in gastown.toml (0 keeps
+everything); --older-than overrides it. The daemon runs this on each
+heartbeat.
+
+Examples:
+  gt events prune                  # Apply the configured retention
+  gt events prune --older-than 30d`,
+	RunE: runEventsPrune,
+}
+
+var eventsVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check the event log's hash chain and signatures",
+	Long: `Check that the event log hasn't been tampered with.
+
+With [events] chain = true in gastown.toml, each event records the SHA-256 of
+the line before it, so editing, inserting, or removing an entry breaks the
+chain at the next one. A chain alone can be rebuilt by anyone who can write
+the log; with [events] signing_key (a ${VAR} or secret:// reference), each
+event is also signed with HMAC-SHA256 and can't be forged without the key.
+
+Events written before chaining was turned on aren't checked. Pruning is
+recorded in the log, so a pruned chain still verifies. Removing the newest
+events can't be seen from the log alone: record the printed head hash
+somewhere else and compare it later.
+
+Exits non-zero if any problem is found.
+
+Examples:
+  gt events verify
+  gt events verify --json`,
+	RunE: runEventsVerify,
+}
+
+var eventsRecordToolCmd = &cobra.Command{
+	Use:   "record-tool",
+	Short: "Record a tool execution (called by the PostToolUse hook)",
+	Long: `Record a tool execution in the event log.
+
+Reads the runtime's PostToolUse hook JSON (tool_name, tool_input,
+session_id) from stdin, and feeds the call to runaway-loop detection (gt
+loop). It's not typically run manually, and never fails: a hook error must
+not interrupt the agent.`,
+	RunE: runEventsRecordTool,
+}
+
+func init() {
+	eventsCmd.Flags().StringVarP(&eventsType, "type", "t", "", "Only these event types (comma-separated)")
+	eventsCmd.Flags().StringVarP(&eventsActor, "actor", "a", "", "Only actors with this address prefix (e.g., gastown/, gastown/crew/max)")
+	eventsCmd.Flags().StringVar(&eventsSince, "since", "", "Only events newer than this duration (e.g., 1h, 7d)")
+	eventsCmd.Flags().StringVar(&eventsUntil, "until", "", "Only events older than this duration (e.g., 1d)")
+	eventsCmd.Flags().IntVarP(&eventsLimit, "limit", "n", 50, "Maximum number of events to show (0 = all)")
+	eventsCmd.Flags().BoolVar(&eventsJSON, "json", false, "Output raw events as JSON lines")
+
+	eventsPruneCmd.Flags().StringVar(&eventsPruneOlderThan, "older-than", "", "Remove events older than this (e.g., 30d); default from [events] retention_days")
+
+	eventsVerifyCmd.Flags().BoolVar(&eventsJSON, "json", false, "Output as JSON")
+
+	eventsCmd.AddCommand(eventsPruneCmd)
+	eventsCmd.AddCommand(eventsVerifyCmd)
+	eventsCmd.AddCommand(eventsRecordToolCmd)
+	rootCmd.AddCommand(eventsCmd)
+}
+
+func runEvents(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	filter := events.Filter{Actor: eventsActor, Limit: eventsLimit}
+	if eventsType != "" {
+		filter.Types = strings.Split(eventsType, ",")
+	}
+	if eventsSince != "" {
+		d, err := parseDuration(eventsSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since duration: %w", err)
+		}
+		filter.Since = time.Now().Add(-d)
+	}
+	if eventsUntil != "" {
+		d, err := parseDuration(eventsUntil)
+		if err != nil {
+			return fmt.Errorf("invalid --until duration: %w", err)
+		}
+		filter.Until = time.Now().Add(-d)
+	}
+
+	found, err := events.Query(townRoot, filter)
+	if err != nil {
+		return err
+	}
+	if eventsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		for _, e := range found {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if len(found) == 0 {
+		fmt.Printf("%s No events found\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, e := range found {
+		payload, _ := json.Marshal(e.Payload)
+		fmt.Printf("%s %-15s %-28s %s\n",
+			style.Dim.Render(e.Time().Local().Format("2006-01-02 15:04:05")),
+			e.Type, e.Actor, style.Dim.Render(string(payload)))
+	}
+	return nil
+}
+
+func runEventsPrune(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var maxAge time.Duration
+	if eventsPruneOlderThan != "" {
+		maxAge, err = parseDuration(eventsPruneOlderThan)
+		if err != nil || maxAge <= 0 {
+			return fmt.Errorf("invalid --older-than duration %q", eventsPruneOlderThan)
+		}
+	} else {
+		gtConfig, err := config.LoadGastownConfig(townRoot)
+		if err != nil {
+			return err
+		}
+		maxAge = gtConfig.Events.Retention()
+	}
+	if maxAge == 0 {
+		return nil // keep everything
+	}
+
+	removed, err := events.Prune(townRoot, maxAge)
+	if err != nil {
+		return err
+	}
+	if removed > 0 {
+		fmt.Printf("Pruned %d events older than %s\n", removed, maxAge)
+	}
+	return nil
+}
+
+func runEventsVerify(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	gtConfig, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	var key []byte
+	if gtConfig.Events.SigningKey != "" {
+		resolved, err := config.ExpandRefs(gtConfig.Events.SigningKey)
+		if err != nil {
+			return fmt.Errorf("resolving events.signing_key: %w", err)
+		}
+		key = []byte(resolved)
+	}
+
+	v, err := events.Verify(townRoot, key)
+	if err != nil {
+		return err
+	}
+	if eventsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	} else {
+		if !gtConfig.Events.Chained() && v.Chained == 0 {
+			fmt.Printf("%s Event log is not chained (set [events] chain = true)\n", style.Dim.Render("○"))
+			return nil
+		}
+		for _, p := range v.Problems {
+			fmt.Printf("%s line %d: %s\n", style.Error.Render("✗"), p.Line, p.Problem)
+		}
+		signed := "signatures not checked"
+		if key != nil {
+			signed = fmt.Sprintf("%d signed", v.Signed)
+		}
+		icon := style.Success.Render("✓")
+		if !v.OK() {
+			icon = style.Error.Render("✗")
+		}
+		fmt.Printf("%s %d events, %d chained, %s\n", icon, v.Entries, v.Chained, signed)
+		if v.Head != "" {
+			fmt.Printf("  head %s\n", style.Dim.Render(v.Head))
+		}
+	}
+	if !v.OK() {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// toolHookInput is the PostToolUse hook JSON the runtime sends on stdin.
+type toolHookInput struct {
+	SessionID string                 `json:"session_id"`
+	ToolName  string                 `json:"tool_name"`
+	ToolInput map[string]interface{} `json:"tool_input"`
+}
+
+func runEventsRecordTool(cmd *cobra.Command, args []string) error {
+	var input toolHookInput
+	if err := json.NewDecoder(os.Stdin).Decode(&input); err != nil || input.ToolName == "" {
+		return nil // nothing usable to record
+	}
+	_ = events.LogAudit(events.TypeToolExec, detectSender(), events.ToolPayload(input.ToolName, input.ToolInput, input.SessionID))
+	return recordLoopCall(input.ToolName, input.ToolInput)
+}
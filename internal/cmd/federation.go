@@ -0,0 +1,447 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/concurrency"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/federation"
+	"github.com/steveyegge/gastown/internal/secret"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/web"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	federationStatusJSON    bool
+	federationDispatchTitle string
+	federationDispatchDesc  string
+	federationDispatchPrio  int
+	federationDispatchTown  string
+	federationDispatchRig   string
+	federationDispatchDry   bool
+)
+
+var federationCmd = &cobra.Command{
+	Use:     "federation",
+	GroupID: GroupServices,
+	Short:   "Aggregate and route work across federated towns",
+	RunE:    requireSubcommand,
+	Long: `Connect this town to remote towns.
+
+Each town's dashboard (gt dashboard) serves a federation API. Remote towns
+are listed in settings/config.json:
+
+  "federation": {
+    "token_env": "GT_FEDERATION_TOKEN",
+    "towns": {
+      "west": { "url": "http://west-box:8080", "token_env": "GT_WEST_TOKEN" }
+    }
+  }
+
+token_env secures this town's API: remote towns must present the token,
+and dispatch is refused when no token is set. Each token_env names an
+environment variable or a keyring secret stored with 'gt secret set'.`,
+}
+
+var federationStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show sessions, health, and cost across federated towns",
+	Long: `Show this town and every federated town: running sessions, worker
+load, patrol health, and today's cost. Unreachable towns are reported.
+
+Examples:
+  gt federation status
+  gt federation status --json`,
+	Args: cobra.NoArgs,
+	RunE: runFederationStatus,
+}
+
+var federationDispatchCmd = &cobra.Command{
+	Use:   "dispatch",
+	Short: "Create a task in the least-loaded town and sling it",
+	Long: `Create a task and sling it to a rig in the least-loaded federated town.
+
+Healthy towns (Deacon, witnesses, and refineries running) are preferred;
+among them the town using the smallest share of its worker pool wins, and
+within it the rig with the fewest workers. This town is a candidate too.
+Use --town and --rig to choose explicitly.
+
+Examples:
+  gt federation dispatch --title "Fix flaky login test"
+  gt federation dispatch --title "Bump deps" --town west --rig gastown
+  gt federation dispatch --title "Triage crash" --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runFederationDispatch,
+}
+
+func init() {
+	federationStatusCmd.Flags().BoolVar(&federationStatusJSON, "json", false, "Output as JSON")
+
+	federationDispatchCmd.Flags().StringVar(&federationDispatchTitle, "title", "", "Task title (required)")
+	federationDispatchCmd.Flags().StringVarP(&federationDispatchDesc, "description", "d", "", "Task description")
+	federationDispatchCmd.Flags().IntVarP(&federationDispatchPrio, "priority", "p", 2, "Task priority (0-4)")
+	federationDispatchCmd.Flags().StringVar(&federationDispatchTown, "town", "", "Dispatch to this town instead of the least-loaded")
+	federationDispatchCmd.Flags().StringVar(&federationDispatchRig, "rig", "", "Dispatch to this rig instead of the least-loaded")
+	federationDispatchCmd.Flags().BoolVar(&federationDispatchDry, "dry-run", false, "Show where the task would go")
+	_ = federationDispatchCmd.MarkFlagRequired("title")
+
+	federationCmd.AddCommand(federationStatusCmd)
+	federationCmd.AddCommand(federationDispatchCmd)
+	rootCmd.AddCommand(federationCmd)
+}
+
+// federationStatusMu serializes local status collection; the spend rollup
+// it uses is a package-level cache.
+var federationStatusMu sync.Mutex
+
+// localFederationStatus collects this town's federation status.
+func localFederationStatus(townRoot string) (*federation.TownStatus, error) {
+	federationStatusMu.Lock()
+	defer federationStatusMu.Unlock()
+
+	townName, err := workspace.GetTownName(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	status := &federation.TownStatus{Town: townName, CollectedAt: time.Now().UTC()}
+
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+	rigs := make(map[string]*federation.RigStatus)
+	for name := range rigsConfig.Rigs {
+		rigs[name] = &federation.RigStatus{Name: name}
+	}
+
+	names, err := tmux.NewTmux().ListSessions()
+	if err != nil {
+		names = nil // no tmux server: nothing running
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		id, err := session.ParseSessionName(name)
+		if err != nil {
+			continue
+		}
+		status.Sessions = append(status.Sessions, federation.Session{Name: name, Role: string(id.Role), Rig: id.Rig, Agent: id.Name})
+		if (concurrency.Session{Role: string(id.Role)}).IsWorker() {
+			status.Workers++
+		}
+		r := rigs[id.Rig]
+		switch {
+		case id.Role == session.RoleDeacon:
+			status.DeaconRunning = true
+		case r == nil:
+		case id.Role == session.RoleWitness:
+			r.WitnessRunning = true
+		case id.Role == session.RoleRefinery:
+			r.RefineryRunning = true
+		case id.Role == session.RolePolecat || id.Role == session.RoleCrew:
+			r.Workers++
+		}
+	}
+
+	rigSpendCache = nil // always report current spend
+	for rigName, spend := range rollupRigSpend() {
+		status.CostTodayUSD += spend.Daily
+		if r := rigs[rigName]; r != nil {
+			r.CostTodayUSD = spend.Daily
+		}
+	}
+
+	if cfg, _, err := concurrency.Limits(townRoot); err == nil && cfg != nil {
+		status.MaxWorkers = cfg.MaxSessions
+	}
+
+	for _, r := range rigs {
+		status.Rigs = append(status.Rigs, *r)
+	}
+	sort.Slice(status.Rigs, func(i, j int) bool { return status.Rigs[i].Name < status.Rigs[j].Name })
+	return status, nil
+}
+
+// localFederationDispatch creates a task in a local rig and slings it there,
+// continuing ctx's trace in the sling.
+func localFederationDispatch(ctx context.Context, townRoot string, req federation.DispatchRequest) (*federation.DispatchResult, error) {
+	_, r, err := getRig(req.Rig)
+	if err != nil {
+		return nil, err
+	}
+	description := req.Description
+	if req.From != "" {
+		description = strings.TrimSpace(description + "\n\nDispatched from town " + req.From + ".")
+	}
+	issue, err := beads.New(beads.ResolveBeadsDir(r.Path)).Create(beads.CreateOptions{
+		Title:       req.Title,
+		Type:        "task",
+		Priority:    req.Priority,
+		Description: description,
+		Actor:       "federation",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating task: %w", err)
+	}
+
+	gtPath, err := os.Executable()
+	if err != nil {
+		gtPath = "gt"
+	}
+	slingCmd := exec.Command(gtPath, "sling", issue.ID, r.Name)
+	slingCmd.Dir = townRoot
+	slingCmd.Env = append(os.Environ(), telemetry.EnvList(ctx)...)
+	if out, err := slingCmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("slinging %s: %w: %s", issue.ID, err, strings.TrimSpace(string(out)))
+	}
+
+	townName, _ := workspace.GetTownName(townRoot)
+	return &federation.DispatchResult{Town: townName, Rig: r.Name, BeadID: issue.ID}, nil
+}
+
+// federationClients returns clients for the remote towns in the town's
+// federation config, sorted by name.
+func federationClients(townRoot string) ([]*federation.Client, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	if settings.Federation == nil {
+		return nil, nil
+	}
+	var clients []*federation.Client
+	for name, town := range settings.Federation.Towns {
+		url, err := config.ResolveRef("federation.towns."+name+".url", town.URL)
+		if err != nil {
+			return nil, err
+		}
+		var token string
+		if town.TokenEnv != "" {
+			token = secret.Lookup(town.TokenEnv)
+		}
+		clients = append(clients, federation.NewClient(name, url, token))
+	}
+	sort.Slice(clients, func(i, j int) bool { return clients[i].Name < clients[j].Name })
+	return clients, nil
+}
+
+// federationToken returns this town's federation API token, if configured.
+func federationToken(townRoot string) string {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Federation == nil || settings.Federation.TokenEnv == "" {
+		return ""
+	}
+	return secret.Lookup(settings.Federation.TokenEnv)
+}
+
+// gatherFederation returns this town's status followed by every remote town's.
+func gatherFederation(townRoot string) ([]*federation.TownStatus, []*federation.Client, error) {
+	clients, err := federationClients(townRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+	local, err := localFederationStatus(townRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), federation.DefaultTimeout)
+	defer cancel()
+	return append([]*federation.TownStatus{local}, federation.Gather(ctx, clients)...), clients, nil
+}
+
+// federationTownRows returns dashboard rows for this town and its remotes.
+func federationTownRows(townRoot string) ([]web.TownRow, error) {
+	statuses, _, err := gatherFederation(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]web.TownRow, 0, len(statuses))
+	for _, s := range statuses {
+		row := web.TownRow{
+			Name:       s.Town,
+			URL:        s.URL,
+			Workers:    s.Workers,
+			MaxWorkers: s.MaxWorkers,
+			Sessions:   len(s.Sessions),
+			CostToday:  s.CostTodayUSD,
+			Error:      s.Error,
+		}
+		switch {
+		case s.Error != "":
+			row.ColorClass = "mq-red"
+		case s.Healthy():
+			row.ColorClass = "mq-green"
+		default:
+			row.ColorClass = "mq-yellow"
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// federatedSessions returns the polecat sessions running in remote towns,
+// honoring the session list rig filter. Unreachable towns are warned about
+// and skipped.
+func federatedSessions(townRoot string) ([]SessionListItem, error) {
+	clients, err := federationClients(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), federation.DefaultTimeout)
+	defer cancel()
+
+	var items []SessionListItem
+	for _, s := range federation.Gather(ctx, clients) {
+		if s.Error != "" {
+			fmt.Fprintf(os.Stderr, "%s %s\n", style.Warning.Render("⚠"), s.Error)
+			continue
+		}
+		for _, sess := range s.Sessions {
+			if sess.Role != string(session.RolePolecat) {
+				continue
+			}
+			if sessionRigFilter != "" && sess.Rig != sessionRigFilter {
+				continue
+			}
+			items = append(items, SessionListItem{
+				Town:      s.Town,
+				Rig:       sess.Rig,
+				Polecat:   sess.Agent,
+				SessionID: sess.Name,
+				Running:   true,
+			})
+		}
+	}
+	return items, nil
+}
+
+func runFederationStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	statuses, _, err := gatherFederation(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if federationStatusJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(statuses)
+	}
+
+	for i, s := range statuses {
+		label := s.Town
+		if i == 0 {
+			label += " (this town)"
+		}
+		if s.Error != "" {
+			fmt.Printf("%s %s  %s\n", style.Error.Render("✗"), style.Bold.Render(label), style.Dim.Render(s.Error))
+			continue
+		}
+		icon := style.Success.Render("●")
+		if !s.Healthy() {
+			icon = style.Warning.Render("●")
+		}
+		pool := "unlimited"
+		if s.MaxWorkers > 0 {
+			pool = fmt.Sprintf("%d", s.MaxWorkers)
+		}
+		fmt.Printf("%s %s  workers %d/%s  sessions %d  today $%.2f\n",
+			icon, style.Bold.Render(label), s.Workers, pool, len(s.Sessions), s.CostTodayUSD)
+		for _, r := range s.Rigs {
+			fmt.Printf("    %-16s workers %-3d witness %-3s refinery %-3s $%.2f\n",
+				r.Name, r.Workers, onOff(r.WitnessRunning), onOff(r.RefineryRunning), r.CostTodayUSD)
+		}
+	}
+	return nil
+}
+
+func onOff(b bool) string {
+	if b {
+		return "up"
+	}
+	return "down"
+}
+
+func runFederationDispatch(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	statuses, clients, err := gatherFederation(townRoot)
+	if err != nil {
+		return err
+	}
+
+	candidates := statuses
+	if federationDispatchTown != "" {
+		candidates = nil
+		for _, s := range statuses {
+			if s.Town == federationDispatchTown {
+				candidates = append(candidates, s)
+			}
+		}
+		if len(candidates) == 0 {
+			return fmt.Errorf("town %q is not this town or a federated town", federationDispatchTown)
+		}
+	}
+	town, rigStatus := federation.LeastLoaded(candidates)
+	if town == nil {
+		return fmt.Errorf("no reachable town with rigs to dispatch to")
+	}
+	rigName := rigStatus.Name
+	if federationDispatchRig != "" {
+		rigName = federationDispatchRig
+	}
+
+	if federationDispatchDry {
+		fmt.Printf("Would dispatch %q to %s/%s (load %.2f)\n", federationDispatchTitle, town.Town, rigName, town.Load())
+		return nil
+	}
+
+	req := federation.DispatchRequest{
+		Rig:         rigName,
+		Title:       federationDispatchTitle,
+		Description: federationDispatchDesc,
+		Priority:    federationDispatchPrio,
+	}
+	var res *federation.DispatchResult
+	if town == statuses[0] {
+		res, err = localFederationDispatch(telemetry.ProcessContext(), townRoot, req)
+	} else {
+		req.From = statuses[0].Town
+		ctx, cancel := context.WithTimeout(telemetry.ProcessContext(), 2*time.Minute)
+		defer cancel()
+		for _, c := range clients {
+			if c.Name == town.Town {
+				res, err = c.Dispatch(ctx, req)
+				break
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("dispatching to %s: %w", town.Town, err)
+	}
+	if res == nil {
+		return fmt.Errorf("no client for town %s", town.Town)
+	}
+
+	fmt.Printf("%s Dispatched %s to %s/%s\n", style.Bold.Render("✓"), res.BeadID, res.Town, res.Rig)
+	return nil
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/handoff"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	handoffAcceptCrew string
+	handoffListAll    bool
+	handoffListJSON   bool
+)
+
+var handoffAcceptCmd = &cobra.Command{
+	Use:   "accept <bead>",
+	Short: "Pick up work a blocked polecat handed off to crew",
+	Long: `Pick up work a blocked polecat handed off with 'gt escalate --handoff'.
+
+The polecat's branch is fetched and checked out in your crew workspace, the
+bead is hooked to you, and the escalation is acknowledged. The polecat's
+summary of what it tried is printed as your briefing.
+
+Run from your crew workspace, or name the crew member with --crew.
+
+Examples:
+  gt handoff accept gt-abc
+  gt handoff accept gt-abc --crew max`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHandoffAccept,
+}
+
+var handoffListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List work handed off by blocked polecats",
+	Args:  cobra.NoArgs,
+	RunE:  runHandoffList,
+}
+
+func init() {
+	handoffCmd.AddCommand(handoffAcceptCmd)
+	handoffCmd.AddCommand(handoffListCmd)
+
+	handoffAcceptCmd.Flags().StringVar(&handoffAcceptCrew, "crew", "", "Crew member to assign (default: current crew workspace)")
+	handoffListCmd.Flags().BoolVar(&handoffListAll, "all", false, "Include accepted handoffs")
+	handoffListCmd.Flags().BoolVar(&handoffListJSON, "json", false, "Output as JSON")
+}
+
+// resolveHandoffCrew returns the crew member accepting a handoff in rigName.
+func resolveHandoffCrew(rigName string) (string, error) {
+	if handoffAcceptCrew != "" {
+		return handoffAcceptCrew, nil
+	}
+	roleInfo, err := GetRole()
+	if err == nil && roleInfo.Role == RoleCrew && roleInfo.Rig == rigName && roleInfo.Polecat != "" {
+		return roleInfo.Polecat, nil
+	}
+	return "", fmt.Errorf("run from a crew workspace in %s or pass --crew <name>", rigName)
+}
+
+func runHandoffAccept(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	pkg, err := handoff.Load(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	if pkg.Accepted() {
+		return fmt.Errorf("%w by %s", handoff.ErrAlreadyAccepted, pkg.AcceptedBy)
+	}
+
+	crewName, err := resolveHandoffCrew(pkg.Rig)
+	if err != nil {
+		return err
+	}
+
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+	r, err := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot)).GetRig(pkg.Rig)
+	if err != nil {
+		return fmt.Errorf("rig '%s' not found", pkg.Rig)
+	}
+	worker, err := crew.NewManager(r, git.NewGit(r.Path)).Get(crewName)
+	if err != nil {
+		if errors.Is(err, crew.ErrCrewNotFound) {
+			return fmt.Errorf("crew workspace '%s' not found in %s (create it with: gt crew add %s --rig %s)", crewName, pkg.Rig, crewName, pkg.Rig)
+		}
+		return fmt.Errorf("getting crew workspace: %w", err)
+	}
+	crewAddr := fmt.Sprintf("%s/crew/%s", pkg.Rig, crewName)
+
+	// Check the polecat's branch out in the crew clone
+	g := git.NewGit(worker.ClonePath)
+	if dirty, err := g.HasUncommittedChanges(); err == nil && dirty {
+		return fmt.Errorf("crew workspace %s has uncommitted changes; commit or stash them first", worker.ClonePath)
+	}
+	if err := g.FetchBranch("origin", pkg.Branch); err != nil {
+		return fmt.Errorf("fetching %s: %w", pkg.Branch, err)
+	}
+	exists, err := g.BranchExists(pkg.Branch)
+	if err != nil {
+		return fmt.Errorf("checking branch %s: %w", pkg.Branch, err)
+	}
+	if !exists {
+		if err := g.CreateBranchFrom(pkg.Branch, "origin/"+pkg.Branch); err != nil {
+			return fmt.Errorf("creating branch %s: %w", pkg.Branch, err)
+		}
+	}
+	if err := g.Checkout(pkg.Branch); err != nil {
+		return fmt.Errorf("checking out %s: %w", pkg.Branch, err)
+	}
+
+	// Hook the bead to the crew member
+	bd := beads.New(filepath.Join(townRoot, pkg.Rig))
+	status := beads.StatusHooked
+	if err := bd.Update(pkg.Bead, beads.UpdateOptions{Status: &status, Assignee: &crewAddr}); err != nil {
+		return fmt.Errorf("hooking %s to %s: %w", pkg.Bead, crewAddr, err)
+	}
+
+	if pkg.Escalation != "" {
+		townBeads := beads.New(beads.ResolveBeadsDir(townRoot))
+		if err := townBeads.AckEscalation(pkg.Escalation, crewAddr); err != nil {
+			style.PrintWarning("could not acknowledge escalation %s: %v", pkg.Escalation, err)
+		}
+	}
+
+	if _, err := handoff.Accept(townRoot, pkg.Bead, crewAddr); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Accepted %s for %s\n", style.Bold.Render("✓"), pkg.Bead, crewAddr)
+	fmt.Printf("  Workspace: %s\n", worker.ClonePath)
+	fmt.Printf("  Branch: %s\n\n", pkg.Branch)
+	fmt.Print(handoff.FormatAssignment(pkg))
+	return nil
+}
+
+func runHandoffList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	pkgs, err := handoff.List(townRoot)
+	if err != nil {
+		return fmt.Errorf("listing handoffs: %w", err)
+	}
+	var shown []*handoff.Package
+	for _, p := range pkgs {
+		if handoffListAll || !p.Accepted() {
+			shown = append(shown, p)
+		}
+	}
+
+	if handoffListJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(shown)
+	}
+
+	if len(shown) == 0 {
+		fmt.Printf("%s No pending handoffs\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, p := range shown {
+		state := "pending"
+		if p.Accepted() {
+			state = "accepted by " + p.AcceptedBy
+		}
+		fmt.Printf("%s %s  %s  (%s)\n", style.Bold.Render(p.Bead), p.From(), p.Blocker, state)
+		fmt.Printf("    branch %s, %s\n", p.Branch, p.CreatedAt.Local().Format("2006-01-02 15:04"))
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/crashdump"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/health"
+	"github.com/steveyegge/gastown/internal/loopguard"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// healthCrashWindow is how long a crash dump keeps its session degraded.
+const healthCrashWindow = time.Hour
+
+// Health command flags
+var (
+	healthJSON         bool
+	healthHistoryLimit int
+)
+
+var healthCmd = &cobra.Command{
+	Use:     "health",
+	GroupID: GroupDiag,
+	Short:   "Show the town's rolled-up health",
+	Long: `Show whether the town is OK in one call: every session's health rolled
+up into rig-level and town-level states, with the reasons.
+
+A session is:
+  down      it should be running (mayor, deacon, a rig's witness and
+            refinery) but isn't
+  degraded  it is paused as a runaway loop, crashed within the last hour,
+            or (the deacon) its patrol heartbeat is stale
+
+A rig is down when none of its patrol agents are running (parked rigs need
+none), and degraded when any of its sessions isn't ok. The town is down when
+the deacon is down or every rig is, and degraded when anything isn't ok.
+
+gt health exits non-zero unless the town is ok. The daemon records the
+town's health on every heartbeat (gt health check), and gt health history
+shows when it changed. gt dashboard serves the same rollup at GET
+/health/town (503 while the town is down), and gt status shows it in its
+header.
+
+Examples:
+  gt health                 # Town, rig, and session health
+  gt health --json          # As JSON
+  gt health history         # When the town's health changed`,
+	RunE: runHealth,
+}
+
+var healthCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Record the town's health and report changes (run by the daemon)",
+	RunE:  runHealthCheck,
+}
+
+var healthHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show when the town's health changed",
+	RunE:  runHealthHistory,
+}
+
+func init() {
+	healthCmd.PersistentFlags().BoolVar(&healthJSON, "json", false, "Output as JSON")
+	healthHistoryCmd.Flags().IntVarP(&healthHistoryLimit, "limit", "n", 20, "How many changes to show (0 = all)")
+
+	healthCmd.AddCommand(healthCheckCmd)
+	healthCmd.AddCommand(healthHistoryCmd)
+	rootCmd.AddCommand(healthCmd)
+}
+
+// collectTownHealth observes every agent session the town should have or
+// has running, and rolls their health up. It reads only tmux and local
+// state files, so it's cheap enough for every gt status and probe.
+func collectTownHealth(townRoot string) (*health.Town, error) {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+	rigs, err := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot)).DiscoverRigs()
+	if err != nil {
+		return nil, fmt.Errorf("discovering rigs: %w", err)
+	}
+
+	running := make(map[string]bool)
+	names, _ := tmux.NewTmux().ListSessions() // no tmux server: nothing running
+	for _, name := range names {
+		running[name] = true
+	}
+	paused := make(map[string]string)
+	if states, err := loopguard.List(townRoot); err == nil {
+		for _, s := range states {
+			if s.Paused() {
+				paused[s.Session] = s.Pause.Detail
+			}
+		}
+	}
+
+	now := time.Now()
+	observe := func(rigName, agent, sess, role string, required bool) health.Observation {
+		o := health.Observation{
+			Agent:    agent,
+			Session:  sess,
+			Role:     role,
+			Required: required,
+			Running:  running[sess],
+			Paused:   paused[sess],
+		}
+		if dir := crashdump.Latest(townRoot, rigName, sess, healthCrashWindow); dir != "" {
+			if info, err := crashdump.Load(dir); err == nil {
+				o.CrashedAt = info.CapturedAt
+			}
+		}
+		return o
+	}
+
+	deaconObs := observe("", "deacon/", session.DeaconSessionName(), "deacon", true)
+	if hb := deacon.ReadHeartbeat(townRoot); hb != nil && hb.IsVeryStale() {
+		deaconObs.HeartbeatAge = hb.Age()
+	}
+	agents := []health.Observation{
+		observe("", "mayor/", session.MayorSessionName(), "mayor", true),
+		deaconObs,
+	}
+
+	var rigObs []health.RigObservation
+	for _, r := range rigs {
+		ro := health.RigObservation{Name: r.Name, Parked: IsRigParked(townRoot, r.Name)}
+		if r.HasWitness {
+			ro.Sessions = append(ro.Sessions, observe(r.Name, r.Name+"/witness", session.WitnessSessionName(r.Name), "witness", true))
+		}
+		if r.HasRefinery {
+			ro.Sessions = append(ro.Sessions, observe(r.Name, r.Name+"/refinery", session.RefinerySessionName(r.Name), "refinery", true))
+		}
+		// Workers count only while running; idle polecats and crew are fine
+		for _, name := range names {
+			id, err := session.ParseSessionName(name)
+			if err != nil || id.Rig != r.Name {
+				continue
+			}
+			switch id.Role {
+			case session.RolePolecat:
+				ro.Sessions = append(ro.Sessions, observe(r.Name, r.Name+"/"+id.Name, name, "polecat", false))
+			case session.RoleCrew:
+				ro.Sessions = append(ro.Sessions, observe(r.Name, r.Name+"/crew/"+id.Name, name, "crew", false))
+			}
+		}
+		rigObs = append(rigObs, ro)
+	}
+	return health.Aggregate(agents, rigObs, now), nil
+}
+
+// healthIcon returns the icon for a health state.
+func healthIcon(s health.State) string {
+	switch s {
+	case health.StateDown:
+		return style.Error.Render("✗")
+	case health.StateDegraded:
+		return style.Warning.Render("!")
+	default:
+		return style.Success.Render("✓")
+	}
+}
+
+func runHealth(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	town, err := collectTownHealth(townRoot)
+	if err != nil {
+		return err
+	}
+	if healthJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(town)
+	}
+
+	fmt.Printf("%s %s %s\n", healthIcon(town.State), style.Bold.Render("Town:"), town.State)
+	for _, s := range town.Agents {
+		printSessionHealth(s)
+	}
+	for _, r := range town.Rigs {
+		label := string(r.State)
+		if r.Parked {
+			label += style.Dim.Render(" (parked)")
+		}
+		fmt.Printf("%s %s %s\n", healthIcon(r.State), style.Bold.Render(r.Name+":"), label)
+		for _, s := range r.Sessions {
+			printSessionHealth(s)
+		}
+	}
+	if !town.OK() {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// printSessionHealth prints one session's health line.
+func printSessionHealth(s health.Session) {
+	line := fmt.Sprintf("  %s %-10s %s", healthIcon(s.State), s.Role, s.Session)
+	if len(s.Reasons) > 0 {
+		line += "  " + style.Dim.Render(strings.Join(s.Reasons, "; "))
+	}
+	fmt.Println(line)
+}
+
+func runHealthCheck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	town, err := collectTownHealth(townRoot)
+	if err != nil {
+		return err
+	}
+	changed, err := health.Save(townRoot, town)
+	if err != nil {
+		return fmt.Errorf("recording health: %w", err)
+	}
+	if changed {
+		fmt.Printf("Town health %s\n", town.Summary())
+	}
+	return nil
+}
+
+func runHealthHistory(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	records, err := health.History(townRoot, healthHistoryLimit)
+	if err != nil {
+		return err
+	}
+	if healthJSON {
+		if records == nil {
+			records = []health.Record{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	}
+	if len(records) == 0 {
+		fmt.Println(style.Dim.Render("No health history yet (the daemon records it)"))
+		return nil
+	}
+	for _, r := range records {
+		fmt.Printf("%s %s %-8s", r.At.Local().Format("2006-01-02 15:04"), healthIcon(r.State), r.State)
+		if len(r.Reasons) > 0 {
+			fmt.Printf("  %s", style.Dim.Render(strings.Join(r.Reasons, "; ")))
+		}
+		fmt.Println()
+	}
+	return nil
+}
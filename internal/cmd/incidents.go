@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/alerting"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/health"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var incidentsCmd = &cobra.Command{
+	Use:     "incidents",
+	GroupID: GroupDiag,
+	Short:   "Open PagerDuty and Opsgenie incidents for critical conditions",
+	Long: `Page the on-call through PagerDuty or Opsgenie while a critical condition
+holds in the town, and resolve the incident when it clears.
+
+The daemon checks these conditions on every heartbeat (gt incidents sync):
+  town_unreachable    a federated remote town (gt federation) doesn't answer
+                      (critical)
+  deacon_down         the deacon's session isn't running (critical)
+  merge_queue_wedged  a rig's MRs have waited wedged_after (default 2h) with
+                      nothing merged (high)
+  budget_exceeded     a rig is over its budget and not overridden (high)
+
+Each incident has a deduplication key naming the town, the condition, and
+its rig or remote town, so a condition opens one incident however long it
+lasts. Each service takes the conditions of its min_severity or worse, and
+severities overrides a condition's severity.
+
+Example gastown.toml:
+  [alerting]
+  severities = { merge_queue_wedged = "critical" }
+
+  [alerting.pagerduty]
+  routing_key = "secret://pagerduty-routing-key"
+
+  [alerting.opsgenie]
+  api_key = "secret://opsgenie-api-key"
+  min_severity = "critical"
+
+Examples:
+  gt incidents                  # Show the open incidents
+  gt incidents test             # Open and resolve a test incident
+  gt incidents sync             # Check conditions and update incidents now`,
+	RunE: runIncidents,
+}
+
+var incidentsSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Open and resolve incidents for the conditions that hold now (run by the daemon)",
+	RunE:  runIncidentsSync,
+}
+
+var incidentsTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Open and then resolve a test incident in each service",
+	RunE:  runIncidentsTest,
+}
+
+func init() {
+	incidentsCmd.AddCommand(incidentsSyncCmd)
+	incidentsCmd.AddCommand(incidentsTestCmd)
+	rootCmd.AddCommand(incidentsCmd)
+}
+
+// incidentRoutes returns the services the town's incidents go to, or nil
+// if alerting isn't configured.
+func incidentRoutes(townRoot string, cfg config.AlertingConfig) ([]alerting.Route, error) {
+	source, _ := workspace.GetTownName(townRoot)
+	if source == "" {
+		source = "gastown"
+	}
+	var routes []alerting.Route
+	if cfg.PagerDuty.RoutingKey != "" {
+		key, err := config.ExpandRefs(cfg.PagerDuty.RoutingKey)
+		if err != nil {
+			return nil, fmt.Errorf("resolving alerting.pagerduty.routing_key: %w", err)
+		}
+		routes = append(routes, alerting.Route{
+			Sink:        &alerting.PagerDuty{RoutingKey: key, Source: source},
+			MinSeverity: cfg.PagerDuty.MinSeverity,
+		})
+	}
+	if cfg.Opsgenie.APIKey != "" {
+		key, err := config.ExpandRefs(cfg.Opsgenie.APIKey)
+		if err != nil {
+			return nil, fmt.Errorf("resolving alerting.opsgenie.api_key: %w", err)
+		}
+		routes = append(routes, alerting.Route{
+			Sink:        &alerting.Opsgenie{APIKey: key, Source: source, URL: cfg.Opsgenie.URL},
+			MinSeverity: cfg.Opsgenie.MinSeverity,
+		})
+	}
+	return routes, nil
+}
+
+func runIncidents(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	open, err := alerting.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(open) == 0 {
+		fmt.Println("No open incidents")
+		return nil
+	}
+	keys := make([]string, 0, len(open))
+	for key := range open {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		o := open[key]
+		fmt.Printf("%s [%s] %s\n", style.Error.Render("✗"), o.Severity, o.Summary)
+		fmt.Printf("  %s, open in %s since %s\n", style.Dim.Render(key), strings.Join(o.Sinks, ", "), o.OpenedAt.Local().Format("2006-01-02 15:04"))
+	}
+	return nil
+}
+
+func runIncidentsSync(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	if !cfg.Alerting.Enabled() {
+		return nil
+	}
+	routes, err := incidentRoutes(townRoot, cfg.Alerting)
+	if err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+	firing := townIncidents(ctx, townRoot, cfg.Alerting, time.Now())
+	triggered, resolved, err := alerting.Reconcile(ctx, townRoot, firing, routes, time.Now())
+	if triggered > 0 || resolved > 0 {
+		fmt.Printf("Opened %d, resolved %d incident(s)\n", triggered, resolved)
+	}
+	return err
+}
+
+func runIncidentsTest(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	routes, err := incidentRoutes(townRoot, cfg.Alerting)
+	if err != nil {
+		return err
+	}
+	if len(routes) == 0 {
+		return fmt.Errorf("alerting not configured: set [alerting.pagerduty] or [alerting.opsgenie] in gastown.toml")
+	}
+	town, _ := workspace.GetTownName(townRoot)
+	inc := alerting.Incident{
+		Key:       alerting.Key(town, "test", ""),
+		Condition: "test",
+		Severity:  config.SeverityLow,
+		Summary:   "Gas Town test incident (resolves at once)",
+	}
+	ctx := cmd.Context()
+	for _, r := range routes {
+		if err := r.Sink.Trigger(ctx, inc); err != nil {
+			return err
+		}
+		if err := r.Sink.Resolve(ctx, inc.Key); err != nil {
+			return err
+		}
+		fmt.Printf("%s Opened and resolved a test incident in %s\n", style.Success.Render("✓"), r.Sink.Name())
+	}
+	return nil
+}
+
+// townIncidents returns the incidents for the conditions (see
+// config.AlertConditions) that hold in the town now. A condition that
+// can't be checked is skipped with a warning, rather than paged or
+// resolved on a guess.
+func townIncidents(ctx context.Context, townRoot string, cfg config.AlertingConfig, now time.Time) []alerting.Incident {
+	town, _ := workspace.GetTownName(townRoot)
+	var out []alerting.Incident
+	add := func(condition, subject, summary string, details map[string]string) {
+		out = append(out, alerting.Incident{
+			Key:       alerting.Key(town, condition, subject),
+			Condition: condition,
+			Subject:   subject,
+			Severity:  cfg.Severity(condition),
+			Summary:   summary,
+			Details:   details,
+		})
+	}
+
+	if clients, err := federationClients(townRoot); err == nil {
+		for _, c := range clients {
+			if _, err := c.Status(ctx); err != nil {
+				add("town_unreachable", c.Name, fmt.Sprintf("Town %s is unreachable", c.Name),
+					map[string]string{"url": c.BaseURL, "error": err.Error()})
+			}
+		}
+	}
+
+	t, err := collectTownHealth(townRoot)
+	if err != nil {
+		style.PrintWarning("could not check the town's health: %v", err)
+	} else {
+		for _, a := range t.Agents {
+			if a.Role == "deacon" && a.State == health.StateDown {
+				add("deacon_down", "", fmt.Sprintf("The deacon of town %s is down", town),
+					map[string]string{"session": a.Session, "reasons": strings.Join(a.Reasons, "; ")})
+			}
+		}
+		wedgedAfter := cfg.WedgedAfterDuration()
+		for _, r := range t.Rigs {
+			if r.Parked {
+				continue
+			}
+			waiting, oldest, err := mergeQueueWedged(townRoot, r.Name, wedgedAfter, now)
+			if err != nil {
+				style.PrintWarning("could not check %s's merge queue: %v", r.Name, err)
+				continue
+			}
+			if waiting > 0 {
+				add("merge_queue_wedged", r.Name,
+					fmt.Sprintf("Merge queue for %s is wedged: %d MR(s) waiting, nothing merged in %s", r.Name, waiting, wedgedAfter),
+					map[string]string{"oldest_mr_age": now.Sub(oldest).Round(time.Minute).String()})
+			}
+		}
+	}
+
+	if rigs, err := loadRigBudgets(townRoot, ""); err != nil {
+		style.PrintWarning("could not check budgets: %v", err)
+	} else {
+		for _, rb := range rigs {
+			if status := evaluateRigBudget(townRoot, rb); status.Enforced {
+				add("budget_exceeded", rb.rig.Name, fmt.Sprintf("Rig %s is over budget: %s", rb.rig.Name, status.Summary()), nil)
+			}
+		}
+	}
+	return out
+}
+
+// mergeQueueWedged returns how many MRs are waiting in a rig's merge queue
+// and when the oldest was submitted, if it's wedged: its oldest MR has
+// waited longer than wedgedAfter and nothing has merged in that time.
+// It returns 0 if the queue isn't wedged.
+func mergeQueueWedged(townRoot, rigName string, wedgedAfter time.Duration, now time.Time) (int, time.Time, error) {
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	mrs, err := beads.New(r.BeadsPath()).List(beads.ListOptions{Status: "open", Label: "gt:merge-request", Priority: -1})
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	var oldest time.Time
+	for _, mr := range mrs {
+		if t, err := time.Parse(time.RFC3339, mr.CreatedAt); err == nil && (oldest.IsZero() || t.Before(oldest)) {
+			oldest = t
+		}
+	}
+	if oldest.IsZero() || now.Sub(oldest) < wedgedAfter {
+		return 0, time.Time{}, nil
+	}
+	merged, err := events.Query(townRoot, events.Filter{
+		Types: []string{events.TypeMerged},
+		Actor: rigName + "/",
+		Since: now.Add(-wedgedAfter),
+		Limit: 1,
+	})
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	if len(merged) > 0 {
+		return 0, time.Time{}, nil
+	}
+	return len(mrs), oldest, nil
+}
@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/jira"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// jiraImportMax bounds how many issues one import takes per rig; the rest
+// are imported by later syncs.
+const jiraImportMax = 50
+
+// jiraMaxPerSync bounds how many events one sync reports to Jira.
+const jiraMaxPerSync = 20
+
+// Jira command flags
+var jiraImportDryRun bool
+
+var jiraCmd = &cobra.Command{
+	Use:     "jira",
+	GroupID: GroupWork,
+	Short:   "Drive polecats from a Jira backlog",
+	Long: `Connect the town to a Jira site, so teams can work their existing Jira
+backlog with polecats.
+
+Import: issues matching a rig's JQL become beads in that rig, labeled with
+their issue key (e.g., jira:PROJ-123), with the issue's summary, type,
+priority, and description. Sling them like any other bead. An issue is
+imported once; changes made in Jira afterwards aren't copied.
+
+Sync: as polecats work on imported beads, their issues are moved through
+the site's workflow at each stage:
+  assigned  the bead was slung to an agent (gt sling)
+  pr        the polecat finished and submitted its work (gt done)
+  merged    the refinery merged the work
+transitions names the transition (or the status it moves to) for each
+stage; stages not listed leave the issue alone. With comments, each stage
+also comments on the issue. The pr comment summarizes the polecat's session
+from its tool calls (gt audit): how long it ran, what tools it used, and the
+files it edited.
+
+The daemon runs gt jira sync, which imports and then syncs, on every
+heartbeat.
+
+Example gastown.toml:
+  [jira]
+  url = "https://example.atlassian.net"
+  user = "gastown@example.com"    # omit for a Server/Data Center PAT
+  token = "secret://jira-token"
+  comments = true
+
+  [jira.rigs]
+  gastown = "project = GT AND labels = polecat AND status = \"To Do\""
+
+  [jira.transitions]
+  assigned = "In Progress"
+  pr = "In Review"
+  merged = "Done"
+
+Examples:
+  gt jira import --dry-run      # Show the issues an import would take
+  gt jira import gastown        # Import gastown's issues now
+  gt jira sync                  # Import, then report new work to Jira`,
+	RunE: requireSubcommand,
+}
+
+var jiraImportCmd = &cobra.Command{
+	Use:   "import [rig]",
+	Short: "Import issues matching each rig's JQL as beads",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runJiraImport,
+}
+
+var jiraSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Import issues, then report work logged since the last sync (run by the daemon)",
+	RunE:  runJiraSync,
+}
+
+func init() {
+	jiraImportCmd.Flags().BoolVar(&jiraImportDryRun, "dry-run", false, "Show what would be imported without creating beads")
+
+	jiraCmd.AddCommand(jiraImportCmd)
+	jiraCmd.AddCommand(jiraSyncCmd)
+	rootCmd.AddCommand(jiraCmd)
+}
+
+// jiraClient returns the town's Jira config and client, or a nil client if
+// the town isn't connected to Jira.
+func jiraClient(townRoot string) (*config.GastownConfig, *jira.Client, error) {
+	cfg, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !cfg.Jira.Enabled() {
+		return cfg, nil, nil
+	}
+	baseURL, err := config.ExpandRefs(cfg.Jira.URL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving jira.url: %w", err)
+	}
+	token := ""
+	if cfg.Jira.Token != "" {
+		if token, err = config.ExpandRefs(cfg.Jira.Token); err != nil {
+			return nil, nil, fmt.Errorf("resolving jira.token: %w", err)
+		}
+	}
+	return cfg, &jira.Client{BaseURL: baseURL, User: cfg.Jira.User, Token: token}, nil
+}
+
+func runJiraImport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, client, err := jiraClient(townRoot)
+	if err != nil {
+		return err
+	}
+	if client == nil {
+		return fmt.Errorf("jira not configured: set url in gastown.toml's [jira]")
+	}
+	rigs := jiraRigs(cfg)
+	if len(args) > 0 {
+		if _, ok := cfg.Jira.Rigs[args[0]]; !ok {
+			return fmt.Errorf("rig %s has no JQL in gastown.toml's [jira.rigs]", args[0])
+		}
+		rigs = args[:1]
+	}
+	for _, rigName := range rigs {
+		n, err := jiraImport(cmd.Context(), client, rigName, cfg.Jira.Rigs[rigName], jiraImportDryRun)
+		if err != nil {
+			return fmt.Errorf("importing %s: %w", rigName, err)
+		}
+		if n == 0 {
+			fmt.Printf("%s: nothing new\n", rigName)
+		}
+	}
+	return nil
+}
+
+func runJiraSync(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, client, err := jiraClient(townRoot)
+	if err != nil || client == nil {
+		return err
+	}
+	ctx := cmd.Context()
+
+	var errs []error
+	for _, rigName := range jiraRigs(cfg) {
+		if _, err := jiraImport(ctx, client, rigName, cfg.Jira.Rigs[rigName], false); err != nil {
+			errs = append(errs, fmt.Errorf("importing %s: %w", rigName, err))
+		}
+	}
+	sent, err := jiraForward(ctx, townRoot, cfg.Jira, client)
+	if sent > 0 {
+		fmt.Printf("Updated %d issue(s)\n", sent)
+	}
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// jiraRigs returns the rigs with JQL, sorted.
+func jiraRigs(cfg *config.GastownConfig) []string {
+	rigs := make([]string, 0, len(cfg.Jira.Rigs))
+	for name := range cfg.Jira.Rigs {
+		rigs = append(rigs, name)
+	}
+	sort.Strings(rigs)
+	return rigs
+}
+
+// jiraImport creates a bead in the rig for each issue matching jql that
+// the rig doesn't already have, and returns how many it created (or would
+// create, with dryRun).
+func jiraImport(ctx context.Context, client *jira.Client, rigName, jql string, dryRun bool) (int, error) {
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return 0, err
+	}
+	issues, err := client.Search(ctx, jql, jiraImportMax)
+	if err != nil {
+		return 0, err
+	}
+	if len(issues) == 0 {
+		return 0, nil
+	}
+
+	b := beads.New(r.BeadsPath())
+	imported, err := importedKeys(b, jira.KeyOf)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, issue := range issues {
+		if imported[issue.Key] != "" {
+			continue
+		}
+		if dryRun {
+			fmt.Printf("Would import %s into %s: %s\n", issue.Key, rigName, issue.Summary)
+			created++
+			continue
+		}
+		bead, err := createTrackedBead(b, beads.CreateOptions{
+			Title:       issue.Summary,
+			Type:        jira.BeadType(issue.Type),
+			Priority:    jira.BeadPriority(issue.Priority),
+			Description: jira.Description(issue, client.BrowseURL(issue.Key)),
+			Actor:       "jira",
+		}, jira.Label(issue.Key))
+		if err != nil {
+			return created, fmt.Errorf("importing %s: %w", issue.Key, err)
+		}
+		fmt.Printf("%s Imported %s into %s as %s: %s\n", style.Success.Render("✓"), issue.Key, rigName, bead.ID, issue.Summary)
+		created++
+	}
+	return created, nil
+}
+
+// jiraForward transitions and comments on the issues of the beads worked
+// on since the last sync, up to jiraMaxPerSync, and returns how many
+// events it reported. An event that fails to reach Jira is retried on the
+// next sync.
+func jiraForward(ctx context.Context, townRoot string, cfg config.JiraConfig, client *jira.Client) (int, error) {
+	townBeads := beads.New(townRoot)
+	cursor := filepath.Join(townRoot, constants.DirRuntime, "jira", "cursor.json")
+	return events.Forward(townRoot, cursor, jiraMaxPerSync,
+		func(e events.Event) bool {
+			return trackerStage(e) != ""
+		},
+		func(e events.Event) error {
+			bead, err := trackedBead(townBeads, e)
+			if err != nil || bead == nil {
+				return err
+			}
+			key := jira.KeyOf(bead.Labels)
+			if key == "" {
+				return nil
+			}
+
+			stage := trackerStage(e)
+			if name := cfg.Transitions[stage]; name != "" {
+				if err := client.Transition(ctx, key, name); err != nil {
+					if !errors.Is(err, jira.ErrNoTransition) {
+						return err
+					}
+					style.PrintWarning("%v", err)
+				}
+			}
+			if cfg.Comments {
+				return client.Comment(ctx, key, jiraComment(townRoot, stage, bead.ID, e))
+			}
+			return nil
+		})
+}
+
+// jiraComment returns the comment for a bead's issue at a stage.
+func jiraComment(townRoot, stage, beadID string, e events.Event) string {
+	str := func(key string) string {
+		s, _ := e.Payload[key].(string)
+		return s
+	}
+	switch stage {
+	case "assigned":
+		return fmt.Sprintf("Gas Town assigned %s to %s.", beadID, str("target"))
+	case "pr":
+		lines := []string{fmt.Sprintf("%s finished %s and submitted branch %s for merge.", e.Actor, beadID, str("branch"))}
+		if pr := str("pr_url"); pr != "" {
+			lines = append(lines, "Pull request: "+pr)
+		}
+		if summary := sessionSummary(townRoot, beadID, e.Actor, e.Time()); summary != "" {
+			lines = append(lines, "", summary)
+		}
+		return strings.Join(lines, "\n")
+	default:
+		text := fmt.Sprintf("The refinery merged %s (branch %s, from %s)", beadID, str("branch"), str("worker"))
+		if commit := str("commit"); commit != "" {
+			text += " as " + commit
+		}
+		return text + "."
+	}
+}
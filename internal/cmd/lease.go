@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/lease"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// isLeasedWorker reports whether work hooked to agentID is leased.
+// Only workers (polecats and crew) hold leases; town and rig infrastructure
+// agents keep their hooks until they release them explicitly.
+func isLeasedWorker(agentID string) bool {
+	return strings.Contains(agentID, "/polecats/") || strings.Contains(agentID, "/crew/")
+}
+
+// acquireWorkLease grants agentID a lease on a freshly hooked bead.
+// Non-fatal: a missing lease only means the Deacon cannot auto-expire the hook.
+func acquireWorkLease(townRoot, beadID, agentID string) {
+	if townRoot == "" || !isLeasedWorker(agentID) {
+		return
+	}
+	l, err := lease.Acquire(townRoot, beadID, agentID, lease.DefaultTTL)
+	if err != nil {
+		fmt.Printf("%s Could not record work lease: %v\n", style.Dim.Render("Warning:"), err)
+		return
+	}
+	fmt.Printf("%s Lease held by %s until %s (renewed by activity)\n",
+		style.Dim.Render("○"), agentID, l.ExpiresAt.Local().Format("15:04"))
+}
+
+// releaseWorkLease removes the lease on a bead whose work is finished or released.
+func releaseWorkLease(townRoot, beadID string) {
+	if townRoot == "" || beadID == "" {
+		return
+	}
+	_ = lease.Release(townRoot, beadID)
+}
+
+// renewWorkLeases is the activity heartbeat: every gt command run from a
+// worker session extends the leases that worker holds. Only polecat and crew
+// sessions hold leases, so anything else is skipped on GT_ROLE alone, before
+// looking for the town or resolving identity. Failures are warnings: a
+// missed renewal only brings the lease's expiry closer.
+func renewWorkLeases() {
+	envRole := os.Getenv(EnvGTRole)
+	if envRole == "" {
+		return
+	}
+	if role, _, _ := parseRoleString(envRole); role != RolePolecat && role != RoleCrew {
+		return
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		warnLeaseRenewal(err)
+		return
+	}
+	if townRoot == "" {
+		return
+	}
+	agentID, _, _, err := resolveSelfTarget()
+	if err != nil {
+		warnLeaseRenewal(err)
+		return
+	}
+	if !isLeasedWorker(agentID) {
+		return
+	}
+	if _, err := lease.RenewHolder(townRoot, agentID); err != nil {
+		warnLeaseRenewal(err)
+	}
+}
+
+func warnLeaseRenewal(err error) {
+	fmt.Fprintf(os.Stderr, "%s could not renew work leases: %v\n", style.Warning.Render("⚠"), err)
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/lease"
+)
+
+func TestRenewWorkLeases(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lease.Acquire(townRoot, "gt-a", "gastown/polecats/toast", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+
+	expiry := func() time.Time {
+		t.Helper()
+		l, err := lease.Get(townRoot, "gt-a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return l.ExpiresAt
+	}
+
+	// Sessions that can't hold leases are skipped
+	before := expiry()
+	time.Sleep(10 * time.Millisecond)
+	t.Setenv("GT_ROLE", "gastown/witness")
+	renewWorkLeases()
+	if got := expiry(); !got.Equal(before) {
+		t.Errorf("witness session renewed the lease: expiry %v, want %v", got, before)
+	}
+
+	t.Setenv("GT_ROLE", "gastown/polecats/toast")
+	renewWorkLeases()
+	if got := expiry(); !got.After(before) {
+		t.Errorf("polecat session did not renew its lease: expiry %v, want after %v", got, before)
+	}
+}
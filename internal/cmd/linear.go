@@ -0,0 +1,351 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/linear"
+	"github.com/steveyegge/gastown/internal/logging"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// linearImportMax bounds how many issues one import takes per rig; the
+// rest are imported by later syncs.
+const linearImportMax = 50
+
+// linearMaxPerSync bounds how many events one sync reports to Linear.
+const linearMaxPerSync = 20
+
+// Linear command flags
+var linearImportDryRun bool
+
+var linearCmd = &cobra.Command{
+	Use:     "linear",
+	GroupID: GroupWork,
+	Short:   "Drive polecats from Linear issues",
+	Long: `Connect the town to a Linear workspace, so teams can work their Linear
+issues with polecats.
+
+Import: issues in a rig's team that haven't been started (backlog or todo),
+limited to a project and labels if the rig sets them, become beads in that
+rig, labeled with their identifier (e.g., linear:ENG-123). Sling them like
+any other bead. An issue is imported once; changes made in Linear
+afterwards aren't copied.
+
+Sync: as polecats work on imported beads, their issues are moved to the
+workflow state states names for each stage:
+  assigned  the bead was slung to an agent (gt sling)
+  pr        the polecat finished and submitted its work (gt done); its
+            pull request is also attached to the issue
+  merged    the refinery merged the work
+Stages not listed leave the issue's state alone.
+
+The daemon runs gt linear sync, which imports and then syncs, on every
+heartbeat. For work to start as soon as an issue is triaged, add a webhook
+for Issue events in Linear's API settings pointing at gt dashboard's
+/linear/webhook, set webhook_secret to its signing secret, and set spawn on
+the rig: each issue triaged into the rig's team (and project and labels) is
+imported and slung to the rig at once.
+
+Example gastown.toml:
+  [linear]
+  api_key = "secret://linear-api-key"
+  webhook_secret = "secret://linear-webhook-secret"
+
+  [linear.rigs.gastown]
+  team = "ENG"
+  labels = ["polecat"]
+  spawn = true
+
+  [linear.states]
+  assigned = "In Progress"
+  pr = "In Review"
+  merged = "Done"
+
+Examples:
+  gt linear import --dry-run    # Show the issues an import would take
+  gt linear import gastown      # Import gastown's issues now
+  gt linear sync                # Import, then report new work to Linear`,
+	RunE: requireSubcommand,
+}
+
+var linearImportCmd = &cobra.Command{
+	Use:   "import [rig]",
+	Short: "Import each rig's unstarted Linear issues as beads",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runLinearImport,
+}
+
+var linearSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Import issues, then report work logged since the last sync (run by the daemon)",
+	RunE:  runLinearSync,
+}
+
+func init() {
+	linearImportCmd.Flags().BoolVar(&linearImportDryRun, "dry-run", false, "Show what would be imported without creating beads")
+
+	linearCmd.AddCommand(linearImportCmd)
+	linearCmd.AddCommand(linearSyncCmd)
+	rootCmd.AddCommand(linearCmd)
+}
+
+// linearClient returns the town's config and Linear client, or a nil
+// client if the town isn't connected to Linear.
+func linearClient(townRoot string) (*config.GastownConfig, *linear.Client, error) {
+	cfg, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !cfg.Linear.Enabled() {
+		return cfg, nil, nil
+	}
+	key, err := config.ExpandRefs(cfg.Linear.APIKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving linear.api_key: %w", err)
+	}
+	return cfg, &linear.Client{APIKey: key}, nil
+}
+
+// linearWebhookSecret returns the Linear webhook's signing secret, or ""
+// if webhooks aren't configured.
+func linearWebhookSecret(townRoot string) (string, error) {
+	cfg, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return "", err
+	}
+	if cfg.Linear.WebhookSecret == "" {
+		return "", nil
+	}
+	return config.ExpandRefs(cfg.Linear.WebhookSecret)
+}
+
+// linearRigs returns the rigs Linear issues are imported into, sorted.
+func linearRigs(cfg *config.GastownConfig) []string {
+	rigs := make([]string, 0, len(cfg.Linear.Rigs))
+	for name := range cfg.Linear.Rigs {
+		rigs = append(rigs, name)
+	}
+	sort.Strings(rigs)
+	return rigs
+}
+
+func runLinearImport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, client, err := linearClient(townRoot)
+	if err != nil {
+		return err
+	}
+	if client == nil {
+		return fmt.Errorf("linear not configured: set api_key in gastown.toml's [linear]")
+	}
+	rigs := linearRigs(cfg)
+	if len(args) > 0 {
+		if _, ok := cfg.Linear.Rigs[args[0]]; !ok {
+			return fmt.Errorf("rig %s isn't in gastown.toml's [linear.rigs]", args[0])
+		}
+		rigs = args[:1]
+	}
+	for _, rigName := range rigs {
+		n, err := linearImport(cmd.Context(), client, rigName, cfg.Linear.Rigs[rigName], linearImportDryRun)
+		if err != nil {
+			return fmt.Errorf("importing %s: %w", rigName, err)
+		}
+		if n == 0 {
+			fmt.Printf("%s: nothing new\n", rigName)
+		}
+	}
+	return nil
+}
+
+func runLinearSync(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, client, err := linearClient(townRoot)
+	if err != nil || client == nil {
+		return err
+	}
+	ctx := cmd.Context()
+
+	var errs []error
+	for _, rigName := range linearRigs(cfg) {
+		if _, err := linearImport(ctx, client, rigName, cfg.Linear.Rigs[rigName], false); err != nil {
+			errs = append(errs, fmt.Errorf("importing %s: %w", rigName, err))
+		}
+	}
+	sent, err := linearForward(ctx, townRoot, cfg.Linear, client)
+	if sent > 0 {
+		fmt.Printf("Updated %d issue(s)\n", sent)
+	}
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// linearImport creates a bead in the rig for each of sel's issues the rig
+// doesn't already have, and returns how many it created (or would create,
+// with dryRun).
+func linearImport(ctx context.Context, client *linear.Client, rigName string, sel config.LinearRig, dryRun bool) (int, error) {
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return 0, err
+	}
+	issues, err := client.Issues(ctx, sel.Team, sel.Project, sel.Labels, linearImportMax)
+	if err != nil {
+		return 0, err
+	}
+	if len(issues) == 0 {
+		return 0, nil
+	}
+
+	b := beads.New(r.BeadsPath())
+	imported, err := importedKeys(b, linear.KeyOf)
+	if err != nil {
+		return 0, err
+	}
+	created := 0
+	for _, issue := range issues {
+		if imported[issue.Identifier] != "" {
+			continue
+		}
+		if dryRun {
+			fmt.Printf("Would import %s into %s: %s\n", issue.Identifier, rigName, issue.Title)
+			created++
+			continue
+		}
+		bead, err := createLinearBead(b, issue)
+		if err != nil {
+			return created, err
+		}
+		fmt.Printf("%s Imported %s into %s as %s: %s\n", style.Success.Render("✓"), issue.Identifier, rigName, bead.ID, issue.Title)
+		created++
+	}
+	return created, nil
+}
+
+// createLinearBead creates the bead for a Linear issue.
+func createLinearBead(b *beads.Beads, issue linear.Issue) (*beads.Issue, error) {
+	bead, err := createTrackedBead(b, beads.CreateOptions{
+		Title:       issue.Title,
+		Type:        linear.BeadType(issue.Labels),
+		Priority:    linear.BeadPriority(issue.Priority),
+		Description: linear.Description(issue),
+		Actor:       "linear",
+	}, linear.Label(issue.Identifier))
+	if err != nil {
+		return nil, fmt.Errorf("importing %s: %w", issue.Identifier, err)
+	}
+	return bead, nil
+}
+
+// linearForward moves the issues of the beads worked on since the last
+// sync to their stage's state, and attaches their PRs, up to
+// linearMaxPerSync, and returns how many events it reported. An event that
+// fails to reach Linear is retried on the next sync.
+func linearForward(ctx context.Context, townRoot string, cfg config.LinearConfig, client *linear.Client) (int, error) {
+	townBeads := beads.New(townRoot)
+	cursor := filepath.Join(townRoot, constants.DirRuntime, "linear", "cursor.json")
+	return events.Forward(townRoot, cursor, linearMaxPerSync,
+		func(e events.Event) bool {
+			return trackerStage(e) != ""
+		},
+		func(e events.Event) error {
+			bead, err := trackedBead(townBeads, e)
+			if err != nil || bead == nil {
+				return err
+			}
+			id := linear.KeyOf(bead.Labels)
+			if id == "" {
+				return nil
+			}
+
+			stage := trackerStage(e)
+			if state := cfg.States[stage]; state != "" {
+				if err := client.SetState(ctx, id, state); err != nil {
+					if !errors.Is(err, linear.ErrNoState) {
+						return err
+					}
+					style.PrintWarning("%v", err)
+				}
+			}
+			if pr, _ := e.Payload["pr_url"].(string); stage == "pr" && pr != "" {
+				branch, _ := e.Payload["branch"].(string)
+				return client.AttachURL(ctx, id, pr, "Pull request: "+branch)
+			}
+			return nil
+		})
+}
+
+// linearWebhook handles a Linear webhook delivery: an issue just triaged
+// into a rig with spawn set is imported, then slung to the rig in the
+// background (Linear expects a reply within seconds).
+func linearWebhook(townRoot string, w *linear.Webhook) error {
+	if !w.Triaged() {
+		return nil
+	}
+	cfg, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	issue := w.Issue
+	rigName := ""
+	for _, name := range linearRigs(cfg) {
+		if sel := cfg.Linear.Rigs[name]; sel.Spawn && sel.Matches(issue.Team, issue.Project, issue.Labels) {
+			rigName = name
+			break
+		}
+	}
+	if rigName == "" {
+		return nil
+	}
+
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+	b := beads.New(r.BeadsPath())
+	imported, err := importedKeys(b, linear.KeyOf)
+	if err != nil {
+		return err
+	}
+	if imported[issue.Identifier] != "" {
+		return nil // already imported, and slung or left for a person to sling
+	}
+	bead, err := createLinearBead(b, *issue)
+	if err != nil {
+		return err
+	}
+
+	gtPath, err := os.Executable()
+	if err != nil {
+		gtPath = "gt"
+	}
+	go func() {
+		slingCmd := exec.Command(gtPath, "sling", bead.ID, rigName)
+		slingCmd.Dir = townRoot
+		if out, err := slingCmd.CombinedOutput(); err != nil {
+			logging.For(logging.ComponentAPI).Error("slinging triaged Linear issue",
+				"issue", issue.Identifier, "bead", bead.ID, "rig", rigName,
+				logging.KeyError, err, "output", strings.TrimSpace(string(out)))
+		}
+	}()
+	return nil
+}
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/crashdump"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -64,6 +67,10 @@ The exit code determines if this was a crash or expected exit:
   - Exit code 0: Expected exit (logged as 'done' if no other done was recorded)
   - Exit code non-zero: Crash (logged as 'crash')
 
+A crash also captures a post-mortem bundle (the pane's last lines, recent
+events, and the workspace's git status) under the rig's .runtime/crashes/;
+the logged event names it.
+
 Examples:
   gt log crash --agent greenplace/Toast --session gt-greenplace-Toast --exit-code 1`,
 	RunE: runLogCrash,
@@ -340,6 +347,22 @@ func runLogCrash(cmd *cobra.Command, args []string) error {
 		if crashSession != "" {
 			context += fmt.Sprintf(" (session: %s)", crashSession)
 		}
+
+		// The pane is still there (the pane-died hook runs before it
+		// closes), so capture the post-mortem bundle now
+		dir, err := crashdump.Capture(tmux.NewTmux(), crashdump.Options{
+			TownRoot: townRoot,
+			Rig:      crashRig(townRoot, crashAgent),
+			Agent:    crashAgent,
+			Session:  crashSession,
+			ExitCode: crashExitCode,
+			Reason:   fmt.Sprintf("exit code %d", crashExitCode),
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gt log crash: %v\n", err)
+		} else {
+			context += fmt.Sprintf(" [dump: %s]", dir)
+		}
 	}
 
 	// Log the event
@@ -351,6 +374,19 @@ func runLogCrash(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// crashRig returns the rig an agent address belongs to, or "" for
+// town-level agents.
+func crashRig(townRoot, agent string) string {
+	rigName, _, found := strings.Cut(agent, "/")
+	if !found || rigName == "" || rigName == "mayor" || rigName == "deacon" {
+		return ""
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, rigName)); err != nil {
+		return ""
+	}
+	return rigName
+}
+
 // LogEvent is a helper that logs an event from anywhere in the codebase.
 // It finds the town root and logs the event.
 func LogEvent(eventType townlog.EventType, agent, context string) error {
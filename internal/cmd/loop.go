@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/loopguard"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Loop command flags
+var (
+	loopStatusJSON  bool
+	loopCheckDryRun bool
+)
+
+var loopCmd = &cobra.Command{
+	Use:     "loop",
+	GroupID: GroupAgents,
+	Short:   "Detect and pause runaway agent loops",
+	Long: `Detect agent sessions stuck in runaway loops and pause them until a
+human resumes them, so an infinite loop can't burn through the budget.
+
+A session is paused when:
+  repeat_calls    it makes the same tool call (same tool, same input), or
+                  alternates the same two calls, 10 times in a row
+  repeat_prompts  it receives the same prompt, or alternates the same two
+                  prompts, 5 times in a row
+  burn_rate       its token rate is over 5x the baseline: the median rate of
+                  the town's running sessions
+
+A paused session is interrupted, a loop_detected event is recorded (it shows
+in gt feed), and the runtime's hooks refuse its tool calls and prompts until
+gt loop resume.
+
+Thresholds are set in gastown.toml (0 turns a check off):
+  [loops]
+  repeat_calls = 10
+  repeat_prompts = 5
+  burn_factor = 5
+  baseline_tokens_per_minute = 0   # 0 = the median of running sessions`,
+	RunE: requireSubcommand,
+}
+
+var loopStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show paused sessions and token rates",
+	RunE:  runLoopStatus,
+}
+
+var loopResumeCmd = &cobra.Command{
+	Use:   "resume <session|agent>",
+	Short: "Resume a session paused as a runaway loop",
+	Long: `Resume a session paused as a runaway loop, clearing the history that
+paused it. Give its tmux session name or its agent address.
+
+Examples:
+  gt loop resume gt-gastown-Toast
+  gt loop resume gastown/polecats/Toast`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLoopResume,
+}
+
+var loopCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Sample token rates and pause sessions burning far above baseline (run by the daemon)",
+	RunE:  runLoopCheck,
+}
+
+var loopCheckPromptCmd = &cobra.Command{
+	Use:   "check-prompt",
+	Short: "Check a prompt for a loop (called by the UserPromptSubmit hook)",
+	Long: `Record a prompt the session received and refuse it if the session is
+paused or the prompt completes a loop.
+
+Reads the runtime's UserPromptSubmit hook JSON from stdin. It's not
+typically run manually.`,
+	RunE: runLoopCheckPrompt,
+}
+
+func init() {
+	loopStatusCmd.Flags().BoolVar(&loopStatusJSON, "json", false, "Output as JSON")
+	loopCheckCmd.Flags().BoolVar(&loopCheckDryRun, "dry-run", false, "Show what would be paused without pausing")
+
+	loopCmd.AddCommand(loopStatusCmd)
+	loopCmd.AddCommand(loopResumeCmd)
+	loopCmd.AddCommand(loopCheckCmd)
+	loopCmd.AddCommand(loopCheckPromptCmd)
+	rootCmd.AddCommand(loopCmd)
+}
+
+// loopHookBlock is the decision a PostToolUse or UserPromptSubmit hook
+// writes to stdout to stop the model.
+type loopHookBlock struct {
+	Decision string `json:"decision"`
+	Reason   string `json:"reason"`
+}
+
+// currentLoopState returns the town root and loop state of the calling
+// agent's session, or false outside an agent session.
+func currentLoopState() (string, *loopguard.State, bool) {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return "", nil, false
+	}
+	sess := deriveSessionName()
+	if sess == "" {
+		sess = detectCurrentTmuxSession()
+	}
+	if sess == "" {
+		return "", nil, false
+	}
+	s, err := loopguard.Load(townRoot, sess)
+	if err != nil {
+		return "", nil, false
+	}
+	return townRoot, s, true
+}
+
+// loopThresholds returns the town's loop thresholds.
+func loopThresholds(townRoot string) config.LoopsConfig {
+	cfg, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return config.LoopsConfig{}
+	}
+	return cfg.Loops
+}
+
+// pausedLoopReason tells the model why its session is paused.
+func pausedLoopReason(s *loopguard.State) string {
+	return fmt.Sprintf("Session paused as a runaway loop (%s): %s. Stop and wait; a human must run 'gt loop resume %s'.",
+		s.Pause.Pattern, s.Pause.Detail, s.Session)
+}
+
+// pauseLoop records a new pause of s: it interrupts the session and emits
+// the alert event. The caller saves s.
+func pauseLoop(s *loopguard.State, actor string) {
+	_ = events.LogFeed(events.TypeLoopDetected, actor,
+		events.LoopPayload(s.Session, s.Pause.Pattern, s.Pause.Detail))
+	_ = tmux.NewTmux().SendKeysRaw(s.Session, "Escape")
+}
+
+// recordLoopCall feeds a tool call from the PostToolUse hook to the loop
+// detector, and stops the model if it completes a loop.
+func recordLoopCall(tool string, input map[string]interface{}) error {
+	townRoot, s, ok := currentLoopState()
+	if !ok {
+		return nil
+	}
+	repeatCalls, _, _ := loopThresholds(townRoot).Thresholds()
+	p := s.RecordCall(tool, input, repeatCalls)
+	if err := s.Save(townRoot); err != nil || p == nil {
+		return nil
+	}
+	pauseLoop(s, detectSender())
+	return json.NewEncoder(os.Stdout).Encode(loopHookBlock{Decision: "block", Reason: pausedLoopReason(s)})
+}
+
+// pausedLoopDenial returns why the calling session's tool calls are
+// refused, or "" if it isn't paused.
+func pausedLoopDenial() string {
+	if _, s, ok := currentLoopState(); ok && s.Paused() {
+		return pausedLoopReason(s)
+	}
+	return ""
+}
+
+// loopPromptInput is the UserPromptSubmit hook JSON the runtime sends on
+// stdin.
+type loopPromptInput struct {
+	Prompt string `json:"prompt"`
+}
+
+func runLoopCheckPrompt(cmd *cobra.Command, args []string) error {
+	var input loopPromptInput
+	if err := json.NewDecoder(os.Stdin).Decode(&input); err != nil {
+		return nil // nothing to check
+	}
+	townRoot, s, ok := currentLoopState()
+	if !ok {
+		return nil
+	}
+	if !s.Paused() {
+		_, repeatPrompts, _ := loopThresholds(townRoot).Thresholds()
+		p := s.RecordPrompt(input.Prompt, repeatPrompts)
+		if err := s.Save(townRoot); err != nil || p == nil {
+			return nil
+		}
+		pauseLoop(s, detectSender())
+	}
+	return json.NewEncoder(os.Stdout).Encode(loopHookBlock{Decision: "block", Reason: pausedLoopReason(s)})
+}
+
+func runLoopCheck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg := loopThresholds(townRoot)
+	_, _, burnFactor := cfg.Thresholds()
+
+	t := tmux.NewTmux()
+	sessions, err := t.ListSessions()
+	if err != nil {
+		return nil // no tmux server, no sessions
+	}
+	live := make(map[string]bool)
+	var states []*loopguard.State
+	var rates []float64
+	now := time.Now()
+	for _, sess := range sessions {
+		if !session.HasRigPrefix(sess) && !session.HasHQPrefix(sess) {
+			continue
+		}
+		live[sess] = true
+		content, err := t.CapturePaneAll(sess)
+		if err != nil {
+			continue
+		}
+		tokens := extractTokens(content)
+		if tokens == 0 {
+			continue // the agent doesn't show a token count
+		}
+		s, err := loopguard.Load(townRoot, sess)
+		if err != nil {
+			continue
+		}
+		s.RecordTokens(now, tokens)
+		states = append(states, s)
+		if rate, ok := s.Rate(); ok && !s.Paused() {
+			rates = append(rates, rate)
+		}
+	}
+
+	baseline := float64(cfg.BaselineTokensPerMinute)
+	if baseline == 0 {
+		baseline, _ = loopguard.Baseline(rates)
+	}
+	for _, s := range states {
+		p := s.CheckBurn(baseline, burnFactor)
+		if loopCheckDryRun {
+			if p != nil {
+				fmt.Printf("  Would pause %s: %s\n", s.Session, p.Detail)
+			}
+			continue
+		}
+		if p != nil {
+			pauseLoop(s, "daemon")
+			fmt.Printf("%s Paused %s: %s\n", style.Error.Render("✗"), s.Session, p.Detail)
+		}
+		_ = s.Save(townRoot)
+	}
+
+	// Forget sessions that are gone, unless a human still has to resume them
+	if all, err := loopguard.List(townRoot); err == nil && !loopCheckDryRun {
+		for _, s := range all {
+			if !live[s.Session] && !s.Paused() {
+				_ = os.Remove(loopguard.Path(townRoot, s.Session))
+			}
+		}
+	}
+	return nil
+}
+
+func runLoopStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	states, err := loopguard.List(townRoot)
+	if err != nil {
+		return err
+	}
+	if loopStatusJSON {
+		if states == nil {
+			states = []*loopguard.State{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(states)
+	}
+	if len(states) == 0 {
+		fmt.Println(style.Dim.Render("No sessions tracked"))
+		return nil
+	}
+	for _, s := range states {
+		rate := style.Dim.Render("rate unknown")
+		if r, ok := s.Rate(); ok {
+			rate = fmt.Sprintf("%.0f tokens/min", r)
+		}
+		if s.Paused() {
+			fmt.Printf("%s %-28s %s (%s, %s ago)\n", style.Error.Render("⏸"), s.Session, s.Pause.Detail,
+				s.Pause.Pattern, time.Since(s.Pause.PausedAt).Round(time.Second))
+			continue
+		}
+		fmt.Printf("%s %-28s %s\n", style.Success.Render("●"), s.Session, rate)
+	}
+	return nil
+}
+
+func runLoopResume(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	sess := args[0]
+	if _, statErr := os.Stat(loopguard.Path(townRoot, sess)); statErr != nil {
+		if _, name, err := agentAddressToIDs(sess); err == nil {
+			sess = name
+		}
+	}
+	s, err := loopguard.Load(townRoot, sess)
+	if err != nil {
+		return err
+	}
+	if !s.Paused() {
+		return fmt.Errorf("session %s is not paused", sess)
+	}
+	pattern := s.Pause.Pattern
+	s.Resume()
+	if err := s.Save(townRoot); err != nil {
+		return fmt.Errorf("saving loop state: %w", err)
+	}
+	_ = events.LogAudit(events.TypeLoopResumed, detectSender(), events.LoopPayload(sess, pattern, ""))
+	fmt.Printf("%s Resumed %s\n", style.Bold.Render("✓"), sess)
+	return nil
+}
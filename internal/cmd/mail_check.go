@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/outbound"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -70,11 +72,20 @@ func runMailCheck(cmd *cobra.Command, args []string) error {
 				subjects = append(subjects, fmt.Sprintf("- %s from %s: %s", msg.ID, msg.From, msg.Subject))
 			}
 
-			fmt.Println("<system-reminder>")
-			fmt.Printf("You have %d unread message(s) in your inbox.\n\n", unread)
+			var b strings.Builder
 			for _, s := range subjects {
-				fmt.Println(s)
+				b.WriteString(s + "\n")
+			}
+			// Subjects leave for the model's API too; withhold any the
+			// outbound filter blocks
+			list, err := outbound.Prompt(detectCurrentTmuxSession(), b.String())
+			if err != nil {
+				list = "(subjects withheld by the outbound filter)\n"
 			}
+
+			fmt.Println("<system-reminder>")
+			fmt.Printf("You have %d unread message(s) in your inbox.\n\n", unread)
+			fmt.Print(list)
 			fmt.Println()
 			fmt.Println("Run 'gt mail inbox' to see your messages, or 'gt mail read <id>' for a specific message.")
 			fmt.Println("</system-reminder>")
@@ -476,7 +476,8 @@ func runMqIntegrationLand(cmd *cobra.Command, args []string) error {
 
 	// 6. Push to origin
 	fmt.Printf("Pushing main to origin...\n")
-	if err := g.Push("origin", "main", false); err != nil {
+	// Landing is the sanctioned way onto main, so it overrides the guard.
+	if err := g.PushWithOptions("origin", "main", git.PushOptions{AllowProtected: true}); err != nil {
 		// Reset on push failure
 		resetErr := resetHard(g, "HEAD~1")
 		if resetErr != nil {
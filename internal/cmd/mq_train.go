@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ train command flags
+var (
+	mqTrainBatchSize int
+	mqTrainDryRun    bool
+)
+
+var mqTrainCmd = &cobra.Command{
+	Use:   "train <rig>",
+	Short: "Merge ready MRs in batches (merge trains)",
+	Long: `Merge the ready queue as merge trains instead of one MR at a time.
+
+Ready MRs are ordered by priority score (see gt mq next) and grouped into
+trains of up to batch_size MRs with the same target branch. Each train:
+
+  1. Runs every MR's pre-merge checks and review, and merges it onto the
+     train. MRs that conflict with the target or earlier MRs are ejected.
+  2. Runs the test command once on the assembled train.
+  3. On failure, bisects the train to find the MRs that broke the tests,
+     ejects them, and keeps the rest.
+  4. Pushes the passing MRs together.
+
+Merged MRs are closed; failed MRs are reported to the Witness and stay in
+the queue, exactly as with serial merging.
+
+The batch size comes from merge_queue.batch_size in the rig's config.json;
+--batch-size overrides it.
+
+Examples:
+  gt mq train gastown                   # Merge the queue in trains
+  gt mq train gastown --batch-size 8    # Up to 8 MRs per train
+  gt mq train gastown --dry-run         # Show the planned trains`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQTrain,
+}
+
+func init() {
+	mqTrainCmd.Flags().IntVar(&mqTrainBatchSize, "batch-size", 0, "Maximum MRs per train (default: merge_queue.batch_size)")
+	mqTrainCmd.Flags().BoolVar(&mqTrainDryRun, "dry-run", false, "Show the planned trains without merging")
+
+	mqCmd.AddCommand(mqTrainCmd)
+}
+
+func runMQTrain(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	_, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+	cfg := eng.Config()
+
+	batchSize := cfg.BatchSize
+	if mqTrainBatchSize > 0 {
+		batchSize = mqTrainBatchSize
+	}
+
+	ready, err := eng.ListReadyMRs()
+	if err != nil {
+		return fmt.Errorf("listing ready MRs: %w", err)
+	}
+	if len(ready) == 0 {
+		fmt.Printf("%s No ready merge requests in queue\n", style.Dim.Render("ℹ"))
+		return nil
+	}
+	for _, mr := range ready {
+		if mr.Target == "" {
+			mr.Target = cfg.TargetBranch
+		}
+	}
+
+	trains := refinery.PlanTrains(ready, batchSize, time.Now())
+
+	if mqTrainDryRun {
+		fmt.Printf("%s %d merge trains for '%s' (batch size %d):\n", style.Bold.Render("🚂"), len(trains), rigName, batchSize)
+		for i, train := range trains {
+			fmt.Printf("\n  Train %d → %s\n", i+1, train[0].Target)
+			for _, mr := range train {
+				fmt.Printf("    [P%d] %s  %s\n", mr.Priority, mr.ID, mr.Branch)
+			}
+		}
+		return nil
+	}
+
+	workerID := getWorkerID()
+	ctx := context.Background()
+	var merged, failed int
+	for i, train := range trains {
+		var claimed []*refinery.MRInfo
+		for _, mr := range train {
+			if err := eng.ClaimMR(mr.ID, workerID); err != nil {
+				fmt.Printf("%s Skipping %s: %v\n", style.Warning.Render("⚠"), mr.ID, err)
+				continue
+			}
+			claimed = append(claimed, mr)
+		}
+		if len(claimed) == 0 {
+			continue
+		}
+
+		fmt.Printf("%s Train %d/%d: %d MRs → %s\n", style.Bold.Render("🚂"), i+1, len(trains), len(claimed), claimed[0].Target)
+		for _, res := range eng.ProcessTrain(ctx, claimed) {
+			if res.Result.Success {
+				eng.HandleMRInfoSuccess(res.MR, res.Result)
+				merged++
+				continue
+			}
+			eng.HandleMRInfoFailure(res.MR, res.Result)
+			if err := eng.ReleaseMR(res.MR.ID); err != nil {
+				fmt.Printf("%s Failed to release %s: %v\n", style.Warning.Render("⚠"), res.MR.ID, err)
+			}
+			failed++
+		}
+	}
+
+	fmt.Printf("\n%s Merged %d, failed %d\n", style.Bold.Render("✓"), merged, failed)
+	return nil
+}
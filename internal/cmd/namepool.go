@@ -298,6 +298,7 @@ func saveRigNamepoolConfig(rigPath, theme string, customNames []string) error {
 	if err := config.SaveRigSettings(settingsPath, settings); err != nil {
 		return fmt.Errorf("saving settings: %w", err)
 	}
+	logConfigChange(filepath.Dir(rigPath), settingsPath, "namepool", theme)
 
 	return nil
 }
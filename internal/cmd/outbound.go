@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/outbound"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Outbound command flags
+var outboundTestJSON bool
+
+var outboundCmd = &cobra.Command{
+	Use:     "outbound",
+	GroupID: GroupConfig,
+	Short:   "Filter what prompts send to the model's API",
+	Long: `Filter prompts before they leave for the model's API, for teams with
+rules about what may be sent to third-party models.
+
+gastown.toml's [outbound] section sets what is filtered:
+  pii       built-in detectors: email addresses, phone numbers, US social
+            security numbers, and payment card numbers
+  patterns  regular expressions for other content (e.g., customer IDs)
+  deny      terms to filter wherever they appear, ignoring case (e.g.,
+            project codenames)
+and what happens to a match (mode):
+  redact        replaced with [FILTERED] (the default)
+  pseudonymize  replaced with a token such as [email:3f9a2c1b], the same for
+                the same value every time, so the model can still tell
+                values apart; hash_key (a ${VAR} or secret:// reference)
+                keys the tokens so they can't be reversed by guessing
+  block         the prompt isn't sent
+
+The filter applies to every prompt gt nudges into a session (gt nudge, gt
+sling, patrol and mail notifications), to the mail summaries injected into
+sessions, and, through the runtime's UserPromptSubmit hook (gt outbound
+check-prompt), to prompts typed or pasted into a session. The hook can't
+rewrite a prompt, so there any match refuses it. Runtimes without that hook
+only get the filter on what gt sends. Each filtered prompt is recorded in the
+event log (gt events --type prompt_filtered) without the matched content.
+
+The filter only sees prompts: files an agent reads with its tools are sent
+as the runtime sees fit. Use the tool policy (gt policy) to keep agents out
+of data they shouldn't send.
+
+Example gastown.toml:
+  [outbound]
+  pii = true
+  patterns = ["ACME-[0-9]{6}"]
+  deny = ["Project Falcon", "Globex"]
+  mode = "pseudonymize"
+  hash_key = "secret://outbound-key"
+
+Examples:
+  gt outbound test "mail jane@example.com about Project Falcon"
+  git log -5 | gt outbound test         # Filter stdin`,
+	RunE: requireSubcommand,
+}
+
+var outboundTestCmd = &cobra.Command{
+	Use:   "test [text]",
+	Short: "Show what the filter does to a text",
+	Long: `Show what the town's outbound filter does to a text: the text as it would
+be sent, and the rules that matched. Reads stdin when no text is given.
+Exits non-zero if the filter would block it.`,
+	RunE: runOutboundTest,
+}
+
+var outboundCheckPromptCmd = &cobra.Command{
+	Use:   "check-prompt",
+	Short: "Check a prompt against the filter (called by the UserPromptSubmit hook)",
+	Long: `Check a prompt against the town's outbound filter.
+
+Reads the runtime's UserPromptSubmit hook JSON (prompt) from stdin. A prompt
+the filter matches is refused with a block decision naming the rules (not the
+content) and logged to the event log. It's not typically run manually.`,
+	RunE: runOutboundCheckPrompt,
+}
+
+func init() {
+	outboundTestCmd.Flags().BoolVar(&outboundTestJSON, "json", false, "Output as JSON")
+
+	outboundCmd.AddCommand(outboundTestCmd)
+	outboundCmd.AddCommand(outboundCheckPromptCmd)
+	rootCmd.AddCommand(outboundCmd)
+}
+
+func runOutboundTest(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	f, err := outbound.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	text := strings.Join(args, " ")
+	if len(args) == 0 {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("reading stdin: %w", err)
+		}
+		text = string(data)
+	}
+
+	res := f.Apply(text)
+	if outboundTestJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(res); err != nil {
+			return err
+		}
+	} else {
+		if f == nil {
+			fmt.Printf("%s No outbound filter (set [outbound] in gastown.toml)\n", style.Dim.Render("○"))
+			return nil
+		}
+		for _, m := range res.Matches {
+			fmt.Printf("%s %s: %d\n", style.Warning.Render("!"), m.Rule, m.Count)
+		}
+		if res.Blocked {
+			fmt.Printf("%s Blocked: this text would not be sent\n", style.Error.Render("✗"))
+		} else {
+			if !res.Filtered() {
+				fmt.Printf("%s Nothing filtered\n", style.Success.Render("✓"))
+			}
+			fmt.Println(strings.TrimRight(res.Text, "\n"))
+		}
+	}
+	if res.Blocked {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func runOutboundCheckPrompt(cmd *cobra.Command, args []string) error {
+	var input loopPromptInput
+	if err := json.NewDecoder(os.Stdin).Decode(&input); err != nil || input.Prompt == "" {
+		return nil // nothing to check
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil
+	}
+	f, err := outbound.Load(townRoot)
+	if err != nil {
+		return json.NewEncoder(os.Stdout).Encode(loopHookBlock{Decision: "block",
+			Reason: fmt.Sprintf("Gas Town's outbound filter can't be applied (%v), so the prompt wasn't sent. Fix gastown.toml and resend.", err)})
+	}
+	res := f.Apply(input.Prompt)
+	if !res.Filtered() {
+		return nil
+	}
+	_ = events.LogAudit(events.TypePromptFiltered, detectSender(),
+		events.PromptFilteredPayload(detectCurrentTmuxSession(), "block", res.Rules(), res.Total()))
+	return json.NewEncoder(os.Stdout).Encode(loopHookBlock{Decision: "block",
+		Reason: fmt.Sprintf("Blocked by Gas Town's outbound filter: the prompt matches %s. Remove that content and resend.", strings.Join(res.Rules(), ", "))})
+}
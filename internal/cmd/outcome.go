@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/outcome"
+)
+
+// agentRole splits an agent address into rig, role, and worker name.
+// For example "gastown/polecats/nux" is (gastown, polecat, nux) and
+// "gastown/witness" is (gastown, witness, "").
+func agentRole(agentID string) (rigName, role, worker string) {
+	parts := strings.Split(agentID, "/")
+	switch {
+	case len(parts) == 3 && parts[1] == "polecats":
+		return parts[0], constants.RolePolecat, parts[2]
+	case len(parts) == 3 && parts[1] == "crew":
+		return parts[0], constants.RoleCrew, parts[2]
+	case len(parts) == 2:
+		return parts[0], parts[1], ""
+	default:
+		return "", agentID, ""
+	}
+}
+
+// recordAssignment starts an outcome record when a bead is hooked to an
+// agent. agentOverride is the --agent used for the spawn, if any. Best effort.
+func recordAssignment(townRoot, beadID, agentID, agentOverride string) {
+	if townRoot == "" || beadID == "" {
+		return
+	}
+	rigName, role, worker := agentRole(agentID)
+	model := agentOverride
+	if model == "" {
+		var rigPath string
+		if rigName != "" {
+			rigPath = filepath.Join(townRoot, rigName)
+		}
+		model, _ = config.ResolveRoleAgentName(role, townRoot, rigPath)
+	}
+	_ = outcome.Record(townRoot, outcome.Update{
+		Kind:   outcome.KindAssigned,
+		Bead:   beadID,
+		Agent:  agentID,
+		Rig:    rigName,
+		Role:   role,
+		Worker: worker,
+		Model:  model,
+	})
+}
+
+// recordDone records gt done for an assignment: the exit type, the PR, and
+// the branch's commits and test files relative to base. Best effort.
+func recordDone(townRoot, beadID, agentID, exitType, prURL string, g *git.Git, base string) {
+	if townRoot == "" || beadID == "" {
+		return
+	}
+	u := outcome.Update{
+		Kind:  outcome.KindDone,
+		Bead:  beadID,
+		Agent: agentID,
+		Exit:  exitType,
+		PRURL: prURL,
+	}
+	if g != nil && base != "" {
+		u.Commits, _ = g.CommitHashes(base, "HEAD")
+		files, _ := g.ChangedFiles(base, "HEAD")
+		for _, f := range files {
+			if outcome.IsTestFile(f) {
+				u.TestsAdded++
+			}
+		}
+	}
+	_ = outcome.Record(townRoot, u)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -320,6 +321,7 @@ type PolecatListItem struct {
 	State          polecat.State `json:"state"`
 	Issue          string        `json:"issue,omitempty"`
 	SessionRunning bool          `json:"session_running"`
+	Hibernated     bool          `json:"hibernated,omitempty"`
 }
 
 // getPolecatManager creates a polecat manager for the given rig.
@@ -381,6 +383,7 @@ func runPolecatList(cmd *cobra.Command, args []string) error {
 				State:          p.State,
 				Issue:          p.Issue,
 				SessionRunning: running,
+				Hibernated:     !running && polecatMgr.IsHibernated(p.Name),
 			})
 		}
 	}
@@ -421,6 +424,10 @@ func runPolecatList(cmd *cobra.Command, args []string) error {
 			stateStr = style.Dim.Render(stateStr)
 		}
 
+		if p.Hibernated {
+			stateStr += " " + style.Dim.Render("(hibernated)")
+		}
+
 		fmt.Printf("  %s %s/%s  %s\n", sessionStatus, p.Rig, p.Name, stateStr)
 		if p.Issue != "" {
 			fmt.Printf("    %s\n", style.Dim.Render(p.Issue))
@@ -612,6 +619,7 @@ type PolecatStatus struct {
 	Issue          string        `json:"issue,omitempty"`
 	ClonePath      string        `json:"clone_path"`
 	Branch         string        `json:"branch"`
+	Diff           *git.DiffStat `json:"diff,omitempty"` // vs the rig's default branch
 	SessionRunning bool          `json:"session_running"`
 	SessionID      string        `json:"session_id,omitempty"`
 	Attached       bool          `json:"attached,omitempty"`
@@ -649,6 +657,9 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// How much the polecat has produced (best-effort)
+	diff, _ := git.NewGit(p.ClonePath).DiffStat("origin/" + r.DefaultBranch())
+
 	// JSON output
 	if polecatStatusJSON {
 		status := PolecatStatus{
@@ -658,6 +669,7 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 			Issue:          p.Issue,
 			ClonePath:      p.ClonePath,
 			Branch:         p.Branch,
+			Diff:           diff,
 			SessionRunning: sessInfo.Running,
 			SessionID:      sessInfo.SessionID,
 			Attached:       sessInfo.Attached,
@@ -701,6 +713,9 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 	// Clone path and branch
 	fmt.Printf("  Clone:         %s\n", style.Dim.Render(p.ClonePath))
 	fmt.Printf("  Branch:        %s\n", style.Dim.Render(p.Branch))
+	if diff != nil {
+		fmt.Printf("  Changes:       %s\n", diff)
+	}
 
 	// Session info
 	fmt.Println()
@@ -1155,7 +1170,7 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 	for _, p := range targets {
 		if polecatNukeDryRun {
 			fmt.Printf("Would nuke %s/%s:\n", p.rigName, p.polecatName)
-			fmt.Printf("  - Kill session: gt-%s-%s\n", p.rigName, p.polecatName)
+			fmt.Printf("  - Kill session: %s\n", session.PolecatSessionName(p.rigName, p.polecatName))
 			fmt.Printf("  - Delete worktree: %s/polecats/%s\n", p.r.Path, p.polecatName)
 			fmt.Printf("  - Delete branch (if exists)\n")
 			fmt.Printf("  - Close agent bead: %s\n", beads.PolecatBeadID(p.rigName, p.polecatName))
@@ -5,6 +5,8 @@ import (
 	"os/exec"
 	"sort"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/session"
 )
 
 // cyclePolecatSession switches to the next or previous polecat session in the same rig.
@@ -83,8 +85,8 @@ func cyclePolecatSession(direction int, sessionOverride string) error {
 // Format: gt-<rig>-<name> where name is NOT crew-*, witness, or refinery.
 // Returns empty strings and false if the format doesn't match.
 func parsePolecatSessionName(sessionName string) (rigName, polecatName string, ok bool) { //nolint:unparam // polecatName kept for API consistency
-	// Must start with "gt-"
-	if !strings.HasPrefix(sessionName, "gt-") {
+	// Must carry the rig session prefix
+	if !session.HasRigPrefix(sessionName) {
 		return "", "", false
 	}
 
@@ -101,8 +103,7 @@ func parsePolecatSessionName(sessionName string) (rigName, polecatName string, o
 		return "", "", false
 	}
 
-	// Remove "gt-" prefix
-	rest := sessionName[3:]
+	rest := session.TrimRigPrefix(sessionName)
 
 	// Must have at least one hyphen (rig-name)
 	idx := strings.Index(rest, "-")
@@ -141,7 +142,7 @@ func findRigPolecatSessions(rigName string) ([]string, error) { //nolint:unparam
 		return nil, nil
 	}
 
-	prefix := fmt.Sprintf("gt-%s-", rigName)
+	prefix := session.PolecatSessionName(rigName, "")
 	var sessions []string
 
 	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
@@ -1,6 +1,10 @@
 package cmd
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
 
 func TestParsePolecatSessionName(t *testing.T) {
 	tests := []struct {
@@ -116,3 +120,21 @@ func TestParsePolecatSessionName(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSessionNamesScheme(t *testing.T) {
+	defer session.SetScheme(session.DefaultScheme())
+	session.SetScheme(session.NewScheme("acme", "gt-{town}-", "hq-{town}-"))
+
+	rig, crew, ok := parseCrewSessionName("gt-acme-gastown-crew-max")
+	if !ok || rig != "gastown" || crew != "max" {
+		t.Errorf("parseCrewSessionName() = %q, %q, %v; want gastown, max", rig, crew, ok)
+	}
+	rig, polecat, ok := parsePolecatSessionName("gt-acme-gastown-Toast")
+	if !ok || rig != "gastown" || polecat != "Toast" {
+		t.Errorf("parsePolecatSessionName() = %q, %q, %v; want gastown, Toast", rig, polecat, ok)
+	}
+	// Another town's default-named sessions aren't this town's
+	if _, _, ok := parseCrewSessionName("gt-gastown-crew-max"); ok {
+		t.Error("parseCrewSessionName() accepted another town's session")
+	}
+}
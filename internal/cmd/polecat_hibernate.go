@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	polecatHibernateIdle   bool
+	polecatHibernateDryRun bool
+)
+
+var polecatHibernateCmd = &cobra.Command{
+	Use:   "hibernate <rig>/<polecat>... | <rig> --idle",
+	Short: "Stop idle polecat sessions, keeping their conversation for later",
+	Long: `Hibernate polecats: stop their sessions while keeping the worktree and
+the agent's conversation so they can resume later.
+
+A hibernated polecat holds no process and no concurrency slot. Slinging
+work to it (gt sling <bead> <rig>/<polecat>) or 'gt polecat wake' resumes
+the previous conversation in a new session, which then checks its hook.
+
+With --idle, hibernates every polecat in the rig that has no assigned work
+and whose session has been inactive longer than hibernation.idle_after
+(default 15m). --idle does nothing unless hibernation.enabled is set in
+settings/config.json. The Witness runs this during patrol.
+
+Requires an agent that supports session resume (e.g., claude, codex).
+
+Examples:
+  gt polecat hibernate greenplace/Toast
+  gt polecat hibernate greenplace --idle
+  gt polecat hibernate greenplace --idle --dry-run`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runPolecatHibernate,
+}
+
+var polecatWakeCmd = &cobra.Command{
+	Use:   "wake <rig>/<polecat>...",
+	Short: "Resume hibernated polecats",
+	Long: `Resume hibernated polecats in new sessions.
+
+The polecat's previous conversation is resumed and it is nudged to check
+its hook. 'gt sling' wakes hibernated polecats automatically.
+
+Examples:
+  gt polecat wake greenplace/Toast`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runPolecatWake,
+}
+
+func init() {
+	polecatHibernateCmd.Flags().BoolVar(&polecatHibernateIdle, "idle", false, "Hibernate all idle polecats in the rig")
+	polecatHibernateCmd.Flags().BoolVar(&polecatHibernateDryRun, "dry-run", false, "Show what would be hibernated without doing it")
+
+	polecatCmd.AddCommand(polecatHibernateCmd)
+	polecatCmd.AddCommand(polecatWakeCmd)
+}
+
+func runPolecatHibernate(cmd *cobra.Command, args []string) error {
+	if polecatHibernateIdle {
+		if len(args) != 1 {
+			return fmt.Errorf("with --idle, provide just the rig name")
+		}
+		return hibernateIdlePolecats(args[0])
+	}
+
+	targets, err := resolvePolecatTargets(args, false)
+	if err != nil {
+		return err
+	}
+
+	t := tmux.NewTmux()
+	var lastErr error
+	for _, target := range targets {
+		if polecatHibernateDryRun {
+			fmt.Printf("Would hibernate %s/%s\n", target.rigName, target.polecatName)
+			continue
+		}
+		if err := hibernatePolecat(polecat.NewSessionManager(t, target.r), target.rigName, target.polecatName); err != nil {
+			fmt.Printf("%s %s/%s: %v\n", style.Error.Render("✗"), target.rigName, target.polecatName, err)
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// hibernateIdlePolecats hibernates polecats in a rig that have no assigned
+// work and have been inactive longer than the configured threshold.
+func hibernateIdlePolecats(rigName string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	if settings.Hibernation == nil || !settings.Hibernation.Enabled {
+		fmt.Printf("%s Hibernation is disabled (set hibernation.enabled in settings/config.json)\n", style.Dim.Render("○"))
+		return nil
+	}
+	idleAfter := settings.Hibernation.GetIdleAfter()
+
+	mgr, r, err := getPolecatManager(rigName)
+	if err != nil {
+		return err
+	}
+	polecats, err := mgr.List()
+	if err != nil {
+		return fmt.Errorf("listing polecats: %w", err)
+	}
+
+	sessMgr := polecat.NewSessionManager(tmux.NewTmux(), r)
+	count := 0
+	for _, p := range polecats {
+		if !isIdlePolecat(sessMgr, p, idleAfter) {
+			continue
+		}
+		if polecatHibernateDryRun {
+			fmt.Printf("Would hibernate %s/%s\n", rigName, p.Name)
+			count++
+			continue
+		}
+		if err := hibernatePolecat(sessMgr, rigName, p.Name); err != nil {
+			fmt.Printf("%s %s/%s: %v\n", style.Warning.Render("!"), rigName, p.Name, err)
+			continue
+		}
+		count++
+	}
+	if count == 0 {
+		fmt.Printf("%s No idle polecats in %s\n", style.Dim.Render("○"), rigName)
+	}
+	return nil
+}
+
+// isIdlePolecat reports whether a polecat has a running session but no
+// assigned work, and its session has been inactive for at least idleAfter.
+func isIdlePolecat(sessMgr *polecat.SessionManager, p *polecat.Polecat, idleAfter time.Duration) bool {
+	if p.Issue != "" || p.State == polecat.StateStuck {
+		return false
+	}
+	info, err := sessMgr.Status(p.Name)
+	if err != nil || !info.Running || info.Attached || info.LastActivity.IsZero() {
+		return false
+	}
+	return time.Since(info.LastActivity) >= idleAfter
+}
+
+func hibernatePolecat(sessMgr *polecat.SessionManager, rigName, polecatName string) error {
+	h, err := sessMgr.Hibernate(polecatName)
+	if err != nil {
+		if errors.Is(err, polecat.ErrSessionNotFound) {
+			return fmt.Errorf("no running session")
+		}
+		return err
+	}
+	fmt.Printf("%s Hibernated %s/%s %s\n", style.Bold.Render("✓"), rigName, polecatName,
+		style.Dim.Render("(session "+h.SessionID+")"))
+	return nil
+}
+
+func runPolecatWake(cmd *cobra.Command, args []string) error {
+	targets, err := resolvePolecatTargets(args, false)
+	if err != nil {
+		return err
+	}
+
+	t := tmux.NewTmux()
+	var lastErr error
+	for _, target := range targets {
+		if err := wakePolecat(polecat.NewSessionManager(t, target.r), target.rigName, target.polecatName); err != nil {
+			fmt.Printf("%s %s/%s: %v\n", style.Error.Render("✗"), target.rigName, target.polecatName, err)
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+func wakePolecat(sessMgr *polecat.SessionManager, rigName, polecatName string) error {
+	if _, err := sessMgr.Wake(polecatName); err != nil {
+		return err
+	}
+	fmt.Printf("%s Woke %s/%s\n", style.Bold.Render("✓"), rigName, polecatName)
+	return nil
+}
+
+// wakeHibernatedPolecat resumes a hibernated polecat so work slung to it is
+// picked up by its previous conversation. Returns false if the polecat is
+// not hibernated.
+func wakeHibernatedPolecat(rigName, polecatName string) (bool, error) {
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return false, err
+	}
+	sessMgr := polecat.NewSessionManager(tmux.NewTmux(), r)
+	if !sessMgr.IsHibernated(polecatName) {
+		return false, nil
+	}
+	fmt.Printf("Waking hibernated polecat %s/%s...\n", rigName, polecatName)
+	if _, err := sessMgr.Wake(polecatName); err != nil {
+		return true, err
+	}
+	return true, nil
+}
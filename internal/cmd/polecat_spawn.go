@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/account"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/events"
@@ -64,6 +65,11 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		return nil, fmt.Errorf("rig '%s' not found", rigName)
 	}
 
+	// Refuse to spawn when the rig is over budget (unless overridden)
+	if err := checkSpawnBudget(townRoot, r); err != nil {
+		return nil, err
+	}
+
 	// Get polecat manager (with tmux for session-aware allocation)
 	polecatGit := git.NewGit(r.Path)
 	t := tmux.NewTmux()
@@ -115,15 +121,12 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		return nil, fmt.Errorf("getting polecat after creation: %w", err)
 	}
 
-	// Resolve account for runtime config
-	accountsPath := constants.MayorAccountsPath(townRoot)
-	claudeConfigDir, accountHandle, err := config.ResolveAccountConfigDir(accountsPath, opts.Account)
+	// Resolve account for runtime config (flag, rig/role assignment, rotation)
+	acct, err := account.Resolve(townRoot, account.Selection{Flag: opts.Account, Role: constants.RolePolecat, Rig: rigName})
 	if err != nil {
 		return nil, fmt.Errorf("resolving account: %w", err)
 	}
-	if accountHandle != "" {
-		fmt.Printf("Using account: %s\n", accountHandle)
-	}
+	printResolvedAccount(acct)
 
 	// Start session (reuse tmux from manager)
 	polecatSessMgr := polecat.NewSessionManager(t, r)
@@ -133,18 +136,32 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	if !running {
 		fmt.Printf("Starting session for %s/%s...\n", rigName, polecatName)
 		startOpts := polecat.SessionStartOptions{
-			RuntimeConfigDir: claudeConfigDir,
+			RuntimeConfigDir: acct.ConfigDirOrEmpty(),
+			AccountEnv:       acct.Env(),
 		}
-		if opts.Agent != "" {
+		// Tool grants from the rig's work prompt for the hooked bead
+		var grantFlags string
+		if opts.HookBead != "" {
+			agentName := opts.Agent
+			if agentName == "" {
+				agentName, _ = config.ResolveRoleAgentName("polecat", townRoot, r.Path)
+			}
+			grantFlags = polecatToolGrantFlags(townRoot, rigName, polecatName, opts.HookBead, agentName)
+		}
+		if opts.Agent != "" || grantFlags != "" {
 			cmd, err := config.BuildPolecatStartupCommandWithAgentOverride(rigName, polecatName, r.Path, "", opts.Agent)
 			if err != nil {
 				return nil, err
 			}
+			if grantFlags != "" {
+				cmd += " " + grantFlags
+			}
 			startOpts.Command = cmd
 		}
 		if err := polecatSessMgr.Start(polecatName, startOpts); err != nil {
 			return nil, fmt.Errorf("starting session: %w", err)
 		}
+		_ = acct.Record(townRoot, polecatSessMgr.SessionName(polecatName))
 	}
 
 	// Get session name and pane
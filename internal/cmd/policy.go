@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/policy"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Policy command flags
+var (
+	policyShowRole string
+	policyShowRig  string
+)
+
+var policyCmd = &cobra.Command{
+	Use:     "policy",
+	GroupID: GroupConfig,
+	Short:   "Show and enforce the tool sandboxing policy",
+	Long: `Show and enforce the policy that restricts agents' tools.
+
+Each role has a permission profile:
+  observer   read the repo and spawn polecats; no edits or pushes (witness)
+  developer  edit code and run tests; no pushes to protected branches (polecat)
+  merger     merge and push, including to protected branches (refinery)
+Other roles have none. The profile is enforced here and by gt's git push
+guard; "profile" in [policy] or [policy.roles.<role>] picks another one, or
+"none".
+
+gastown.toml's [policy] section also limits, for every role:
+  paths           directories file tools may read and write, relative to the
+                  session's working directory ("." = its worktree) or absolute
+  allow_commands  the only commands shell tools may run
+  deny_commands   commands or command prefixes shell tools must not run
+  allow_hosts     the only hosts tools may reach ("*.example.com" matches
+                  subdomains)
+  deny_hosts      hosts tools must not reach
+
+[policy.roles.<role>] replaces any of these lists for one role. Empty lists
+impose no restriction. The runtime's PreToolUse hook runs gt policy check
+before every tool call; a denied call is not run, the model is told why, and
+the denial is recorded in the event log (gt events --type policy_denied).
+
+Example gastown.toml (or a rig's gastown.toml, for that rig):
+  [policy]
+  paths = [".", "/tmp"]
+  deny_commands = ["sudo", "git push --force"]
+
+  [policy.roles.crew]
+  profile = "developer"
+
+  [policy.roles.polecat]
+  allow_hosts = ["github.com", "*.githubusercontent.com", "proxy.golang.org"]`,
+	RunE: requireSubcommand,
+}
+
+var policyShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the policy that applies to a role",
+	Long: `Show the tool policy that applies to a role: the town-wide lists with the
+role's overrides applied.
+
+Examples:
+  gt policy show                          # The current role's policy
+  gt policy show --role polecat --rig gastown`,
+	RunE: runPolicyShow,
+}
+
+var policyCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check a tool call against the policy (called by the PreToolUse hook)",
+	Long: `Check a tool call against the calling role's policy.
+
+Reads the runtime's PreToolUse hook JSON (tool_name, tool_input, cwd,
+session_id) from stdin. A denied call is answered with a deny decision and
+its reason on stdout and logged to the event log. It's not typically run
+manually. An unreadable call or configuration is allowed, so a broken
+setting can't lock an agent out of fixing it.`,
+	RunE: runPolicyCheck,
+}
+
+func init() {
+	policyShowCmd.Flags().StringVar(&policyShowRole, "role", "", "Role to show (default: the current role)")
+	policyShowCmd.Flags().StringVar(&policyShowRig, "rig", "", "Rig whose gastown.toml applies (default: the current rig)")
+
+	policyCmd.AddCommand(policyShowCmd)
+	policyCmd.AddCommand(policyCheckCmd)
+	rootCmd.AddCommand(policyCmd)
+}
+
+// loadToolPolicy returns the policy for role, from the town's gastown.toml
+// and, if rigName is set, the rig's.
+func loadToolPolicy(townRoot, rigName, role string) (config.ToolPolicy, error) {
+	rigPath := ""
+	if rigName != "" {
+		rigPath = filepath.Join(townRoot, rigName)
+	}
+	cfg, err := config.LoadRigGastownConfig(townRoot, rigPath)
+	if err != nil {
+		return config.ToolPolicy{}, err
+	}
+	return cfg.Policy.ForRole(role), nil
+}
+
+func runPolicyShow(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	role, rigName := policyShowRole, policyShowRig
+	if role == "" || rigName == "" {
+		if info, err := GetRole(); err == nil {
+			if role == "" {
+				role = string(info.Role)
+			}
+			if rigName == "" {
+				rigName = info.Rig
+			}
+		}
+	}
+
+	p, err := loadToolPolicy(townRoot, rigName, role)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", style.Bold.Render(fmt.Sprintf("Tool policy for %s", roleOrAll(role))))
+	if prof := p.PermissionProfile(); prof != nil {
+		fmt.Printf("  %-15s %s %s\n", "profile", prof.Name, style.Dim.Render("("+prof.Description+")"))
+	} else {
+		fmt.Printf("  %-15s %s\n", "profile", style.Dim.Render("(none)"))
+	}
+	for _, row := range []struct {
+		name  string
+		value []string
+	}{
+		{"paths", p.Paths},
+		{"allow_commands", p.AllowCommands},
+		{"deny_commands", p.DenyCommands},
+		{"allow_hosts", p.AllowHosts},
+		{"deny_hosts", p.DenyHosts},
+	} {
+		value := style.Dim.Render("(unrestricted)")
+		if len(row.value) > 0 {
+			value = strings.Join(row.value, ", ")
+		}
+		fmt.Printf("  %-15s %s\n", row.name, value)
+	}
+	return nil
+}
+
+// rigProtectedBranches returns the protected branch patterns of rig, or nil
+// (the defaults) outside a rig.
+func rigProtectedBranches(townRoot, rigName string) []string {
+	if rigName == "" {
+		return nil
+	}
+	return (&rig.Rig{Name: rigName, Path: filepath.Join(townRoot, rigName)}).ProtectedBranches()
+}
+
+// roleOrAll names role for display.
+func roleOrAll(role string) string {
+	if role == "" {
+		return "all roles"
+	}
+	return role
+}
+
+// policyHookInput is the PreToolUse hook JSON the runtime sends on stdin.
+type policyHookInput struct {
+	SessionID string                 `json:"session_id"`
+	Cwd       string                 `json:"cwd"`
+	ToolName  string                 `json:"tool_name"`
+	ToolInput map[string]interface{} `json:"tool_input"`
+}
+
+// policyHookOutput is the PreToolUse hook decision written to stdout.
+type policyHookOutput struct {
+	HookSpecificOutput struct {
+		HookEventName            string `json:"hookEventName"`
+		PermissionDecision       string `json:"permissionDecision"`
+		PermissionDecisionReason string `json:"permissionDecisionReason"`
+	} `json:"hookSpecificOutput"`
+}
+
+func runPolicyCheck(cmd *cobra.Command, args []string) error {
+	var input policyHookInput
+	if err := json.NewDecoder(os.Stdin).Decode(&input); err != nil || input.ToolName == "" {
+		return nil // nothing to check
+	}
+	if reason := pausedLoopDenial(); reason != "" {
+		return denyToolCall(reason)
+	}
+	info, err := GetRole()
+	if err != nil {
+		return nil // not an agent in a town
+	}
+	p, err := loadToolPolicy(info.TownRoot, info.Rig, string(info.Role))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gt policy: %v; allowing %s\n", err, input.ToolName)
+		return nil
+	}
+
+	// The session's working directory: the project the runtime was started
+	// in, not wherever the agent has since cd'd to
+	workDir := os.Getenv("CLAUDE_PROJECT_DIR")
+	if workDir == "" {
+		workDir = input.Cwd
+	}
+	if workDir == "" {
+		workDir = info.WorkDir
+	}
+
+	d := policy.Evaluate(p, policy.Call{
+		Tool:              input.ToolName,
+		Input:             input.ToolInput,
+		WorkDir:           workDir,
+		ProtectedBranches: rigProtectedBranches(info.TownRoot, info.Rig),
+	})
+	if d.Allowed {
+		return nil
+	}
+	_ = events.LogAudit(events.TypePolicyDenied, detectSender(),
+		events.PolicyPayload(input.ToolName, input.ToolInput, d.Rule, d.Reason, input.SessionID))
+	return denyToolCall(fmt.Sprintf("Blocked by Gas Town tool policy (%s rule): %s", d.Rule, d.Reason))
+}
+
+// denyToolCall writes a PreToolUse deny decision with reason to stdout.
+func denyToolCall(reason string) error {
+	var out policyHookOutput
+	out.HookSpecificOutput.HookEventName = "PreToolUse"
+	out.HookSpecificOutput.PermissionDecision = "deny"
+	out.HookSpecificOutput.PermissionDecisionReason = reason
+	return json.NewEncoder(os.Stdout).Encode(out)
+}
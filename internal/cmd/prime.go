@@ -35,6 +35,7 @@ const (
 	RoleRefinery Role = "refinery"
 	RolePolecat  Role = "polecat"
 	RoleCrew     Role = "crew"
+	RoleReviewer Role = "reviewer"
 	RoleUnknown  Role = "unknown"
 )
 
@@ -209,6 +210,9 @@ func runPrime(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Output role instructions from gastown.toml (rig file overrides town)
+	outputRolePrompt(ctx)
+
 	// Output handoff content if present
 	outputHandoffContent(ctx)
 
@@ -553,6 +557,8 @@ func getAgentIdentity(ctx RoleContext) string {
 		return fmt.Sprintf("%s/witness", ctx.Rig)
 	case RoleRefinery:
 		return fmt.Sprintf("%s/refinery", ctx.Rig)
+	case RoleReviewer:
+		return fmt.Sprintf("%s/reviewer", ctx.Rig)
 	default:
 		return ""
 	}
@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/deacon"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
@@ -80,6 +82,30 @@ func outputPrimeContext(ctx RoleContext) error {
 	return nil
 }
 
+// outputRolePrompt outputs the instructions configured for the role under
+// [prompts] in gastown.toml. A rig's gastown.toml overrides the town's, so
+// each repo can give its agents its own policies.
+func outputRolePrompt(ctx RoleContext) {
+	if ctx.TownRoot == "" {
+		return
+	}
+	rigPath := ""
+	if ctx.Rig != "" {
+		rigPath = filepath.Join(ctx.TownRoot, ctx.Rig)
+	}
+	cfg, err := config.LoadRigGastownConfig(ctx.TownRoot, rigPath)
+	if err != nil {
+		style.PrintWarning("ignoring gastown.toml prompts: %v", err)
+		return
+	}
+	prompt := strings.TrimSpace(cfg.Prompts[string(ctx.Role)])
+	explain(prompt != "", "Role prompt: [prompts] entry in gastown.toml")
+	if prompt == "" {
+		return
+	}
+	fmt.Printf("\n%s\n\n%s\n", style.Bold.Render("## Local Instructions"), prompt)
+}
+
 func outputPrimeContextFallback(ctx RoleContext) error {
 	switch ctx.Role {
 	case RoleMayor:
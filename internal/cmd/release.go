@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var releaseReason string
@@ -45,6 +46,7 @@ func runRelease(cmd *cobra.Command, args []string) error {
 	}
 
 	bd := beads.New(cwd)
+	townRoot, _ := workspace.FindFromCwd()
 
 	// Release each issue
 	var released, failed int
@@ -60,6 +62,7 @@ func runRelease(cmd *cobra.Command, args []string) error {
 			fmt.Printf("%s Failed to release %s: %v\n", style.Dim.Render("✗"), id, err)
 			failed++
 		} else {
+			releaseWorkLease(townRoot, id)
 			fmt.Printf("%s Released %s → open\n", style.Bold.Render("✓"), id)
 			released++
 		}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/review"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	reviewMR       string
+	reviewDiffStat bool
+	reviewBlocker  bool
+	reviewMessage  string
+	reviewShowJSON bool
+)
+
+var reviewCmd = &cobra.Command{
+	Use:     "review",
+	GroupID: GroupWork,
+	Short:   "Reviewer tools for the refinery review stage",
+	Long: `Reviewer tools for the refinery review stage.
+
+When a rig sets "review": true in its merge_queue config, the Refinery runs a
+reviewer agent for each MR before merging. The reviewer reads the diff,
+records findings, and delivers exactly one verdict:
+
+  approve          the MR merges (suggestions are kept on record)
+  request-changes  the MR bounces back to the polecat with the findings
+
+The verdict is recorded on the MR bead (review_verdict, reviewer).
+
+The MR under review comes from GT_REVIEW_MR, which the Refinery sets for the
+reviewer session. Use --mr to act on a different MR.
+
+Examples:
+  gt review diff --stat
+  gt review annotate --blocker internal/cmd/done.go:42 "error is dropped"
+  gt review annotate README.md "typo in the install section"
+  gt review request-changes -m "Handle the push error before returning"
+  gt review approve -m "Change is small and covered by tests"`,
+	RunE: requireSubcommand,
+}
+
+var reviewDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show the MR's diff against its target branch",
+	Args:  cobra.NoArgs,
+	RunE:  runReviewDiff,
+}
+
+var reviewAnnotateCmd = &cobra.Command{
+	Use:   "annotate <file[:line]> <message...>",
+	Short: "Record a review finding",
+	Long: `Record a review finding anchored to a file and optional line.
+
+Findings are suggestions unless --blocker is set. Blockers must be fixed
+before the MR can merge; an MR with blockers cannot be approved.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runReviewAnnotate,
+}
+
+var reviewApproveCmd = &cobra.Command{
+	Use:   "approve",
+	Short: "Approve the MR for merge",
+	Args:  cobra.NoArgs,
+	RunE:  runReviewApprove,
+}
+
+var reviewRequestChangesCmd = &cobra.Command{
+	Use:   "request-changes",
+	Short: "Send the findings back to the polecat",
+	Args:  cobra.NoArgs,
+	RunE:  runReviewRequestChanges,
+}
+
+var reviewShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the in-progress review",
+	Args:  cobra.NoArgs,
+	RunE:  runReviewShow,
+}
+
+func init() {
+	rootCmd.AddCommand(reviewCmd)
+	reviewCmd.AddCommand(reviewDiffCmd)
+	reviewCmd.AddCommand(reviewAnnotateCmd)
+	reviewCmd.AddCommand(reviewApproveCmd)
+	reviewCmd.AddCommand(reviewRequestChangesCmd)
+	reviewCmd.AddCommand(reviewShowCmd)
+
+	reviewCmd.PersistentFlags().StringVar(&reviewMR, "mr", "", "MR under review (default: $GT_REVIEW_MR)")
+	reviewDiffCmd.Flags().BoolVar(&reviewDiffStat, "stat", false, "Show only a summary of changed files")
+	reviewAnnotateCmd.Flags().BoolVar(&reviewBlocker, "blocker", false, "Finding must be fixed before merge")
+	reviewApproveCmd.Flags().StringVarP(&reviewMessage, "message", "m", "", "Review summary")
+	reviewRequestChangesCmd.Flags().StringVarP(&reviewMessage, "message", "m", "", "What must change (required)")
+	_ = reviewRequestChangesCmd.MarkFlagRequired("message")
+	reviewShowCmd.Flags().BoolVar(&reviewShowJSON, "json", false, "Output as JSON")
+}
+
+// loadCurrentReview resolves the town and the MR under review and loads it.
+func loadCurrentReview() (string, *review.Review, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	mrID := reviewMR
+	if mrID == "" {
+		mrID = os.Getenv("GT_REVIEW_MR")
+	}
+	if mrID == "" {
+		return "", nil, fmt.Errorf("no MR under review (set --mr or GT_REVIEW_MR)")
+	}
+	r, err := review.Load(townRoot, mrID)
+	if err != nil {
+		return "", nil, err
+	}
+	return townRoot, r, nil
+}
+
+// reviewerIdentity returns the address recorded as the reviewer.
+func reviewerIdentity(r *review.Review) string {
+	if rig := os.Getenv("GT_RIG"); rig != "" {
+		return rig + "/" + string(RoleReviewer)
+	}
+	if r.Rig != "" {
+		return r.Rig + "/" + string(RoleReviewer)
+	}
+	return detectSender()
+}
+
+func runReviewDiff(cmd *cobra.Command, args []string) error {
+	_, r, err := loadCurrentReview()
+	if err != nil {
+		return err
+	}
+	gitArgs := []string{"diff"}
+	if reviewDiffStat {
+		gitArgs = append(gitArgs, "--stat")
+	}
+	// Three-dot: only what the branch adds since it forked from the target
+	gitArgs = append(gitArgs, r.Target+"..."+r.Branch)
+
+	c := exec.Command("git", gitArgs...)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("git diff %s...%s: %w", r.Target, r.Branch, err)
+	}
+	return nil
+}
+
+func runReviewAnnotate(cmd *cobra.Command, args []string) error {
+	townRoot, r, err := loadCurrentReview()
+	if err != nil {
+		return err
+	}
+	file, line, err := review.ParseLocation(args[0])
+	if err != nil {
+		return err
+	}
+	f := review.Finding{
+		File:     file,
+		Line:     line,
+		Severity: review.SeveritySuggestion,
+		Message:  strings.Join(args[1:], " "),
+	}
+	if reviewBlocker {
+		f.Severity = review.SeverityBlocker
+	}
+	if err := review.Annotate(townRoot, r.MRID, reviewerIdentity(r), f); err != nil {
+		return err
+	}
+	fmt.Printf("%s Recorded %s at %s\n", style.Bold.Render("✓"), f.Severity, f.Location())
+	return nil
+}
+
+func runReviewApprove(cmd *cobra.Command, args []string) error {
+	return setReviewVerdict(review.VerdictApproved)
+}
+
+func runReviewRequestChanges(cmd *cobra.Command, args []string) error {
+	return setReviewVerdict(review.VerdictChangesRequested)
+}
+
+// setReviewVerdict records the verdict on the current review.
+func setReviewVerdict(verdict string) error {
+	townRoot, r, err := loadCurrentReview()
+	if err != nil {
+		return err
+	}
+	if err := review.SetVerdict(townRoot, r.MRID, reviewerIdentity(r), verdict, reviewMessage); err != nil {
+		return err
+	}
+	fmt.Printf("%s Verdict for %s: %s\n", style.Bold.Render("✓"), r.MRID, verdict)
+	return nil
+}
+
+func runReviewShow(cmd *cobra.Command, args []string) error {
+	_, r, err := loadCurrentReview()
+	if err != nil {
+		return err
+	}
+	if reviewShowJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+
+	fmt.Printf("%s %s (%s → %s)\n", style.Bold.Render("Review:"), r.MRID, r.Branch, r.Target)
+	verdict := r.Verdict
+	if verdict == "" {
+		verdict = "pending"
+	}
+	fmt.Printf("  Verdict: %s\n", verdict)
+	if r.Summary != "" {
+		fmt.Printf("  Summary: %s\n", r.Summary)
+	}
+	if len(r.Findings) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("No findings"))
+		return nil
+	}
+	fmt.Println()
+	for _, f := range r.Findings {
+		fmt.Printf("  [%s] %s: %s\n", f.Severity, f.Location(), f.Message)
+	}
+	return nil
+}
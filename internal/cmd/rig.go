@@ -17,6 +17,7 @@ import (
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/wisp"
@@ -60,9 +61,17 @@ The command also:
   - Creates ~/gt/plugins/ (town-level) if it doesn't exist
   - Creates <rig>/plugins/ (rig-level)
 
+For very large repositories, --depth, --single-branch, and --filter limit
+what is cloned. They are saved in config.json and also apply to crew clones.
+Run 'git fetch --unshallow' in a workspace that needs the full history.
+
+Submodules are initialized and updated recursively in every clone and
+worktree, and again on pulls. Use --no-submodules to skip them.
+
 Example:
   gt rig add gastown https://github.com/steveyegge/gastown
-  gt rig add my-project git@github.com:user/repo.git --prefix mp`,
+  gt rig add my-project git@github.com:user/repo.git --prefix mp
+  gt rig add monorepo https://example.com/big.git --depth 1 --filter blob:none`,
 	Args: cobra.ExactArgs(2),
 	RunE: runRigAdd,
 }
@@ -256,6 +265,11 @@ var (
 	rigAddPrefix       string
 	rigAddLocalRepo    string
 	rigAddBranch       string
+	rigAddDepth        int
+	rigAddSingleBranch bool
+	rigAddFilter       string
+	rigAddNoSubmodules bool
+	rigAddSkipLFS      bool
 	rigResetHandoff    bool
 	rigResetMail       bool
 	rigResetStale      bool
@@ -286,6 +300,11 @@ func init() {
 	rigAddCmd.Flags().StringVar(&rigAddPrefix, "prefix", "", "Beads issue prefix (default: derived from name)")
 	rigAddCmd.Flags().StringVar(&rigAddLocalRepo, "local-repo", "", "Local repo path to share git objects (optional)")
 	rigAddCmd.Flags().StringVar(&rigAddBranch, "branch", "", "Default branch name (default: auto-detected from remote)")
+	rigAddCmd.Flags().IntVar(&rigAddDepth, "depth", 0, "Shallow clone with this many commits of history")
+	rigAddCmd.Flags().BoolVar(&rigAddSingleBranch, "single-branch", false, "Clone only the default branch")
+	rigAddCmd.Flags().StringVar(&rigAddFilter, "filter", "", "Partial clone object filter (e.g., blob:none)")
+	rigAddCmd.Flags().BoolVar(&rigAddNoSubmodules, "no-submodules", false, "Don't check out submodules in clones and worktrees")
+	rigAddCmd.Flags().BoolVar(&rigAddSkipLFS, "skip-lfs", false, "Leave Git LFS files as pointer files to save bandwidth")
 
 	rigResetCmd.Flags().BoolVar(&rigResetHandoff, "handoff", false, "Clear handoff content")
 	rigResetCmd.Flags().BoolVar(&rigResetMail, "mail", false, "Clear stale mail messages")
@@ -350,6 +369,13 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		BeadsPrefix:   rigAddPrefix,
 		LocalRepo:     rigAddLocalRepo,
 		DefaultBranch: rigAddBranch,
+		Clone: git.CloneOptions{
+			Depth:        rigAddDepth,
+			SingleBranch: rigAddSingleBranch,
+			Filter:       rigAddFilter,
+			NoSubmodules: rigAddNoSubmodules,
+			SkipLFS:      rigAddSkipLFS,
+		},
 	})
 	if err != nil {
 		return fmt.Errorf("adding rig: %w", err)
@@ -701,11 +727,11 @@ func assigneeToSessionName(assignee string) (sessionName string, isPersistent bo
 	switch len(parts) {
 	case 2:
 		// rig/polecatName -> gt-rig-polecatName
-		return fmt.Sprintf("gt-%s-%s", parts[0], parts[1]), false
+		return session.PolecatSessionName(parts[0], parts[1]), false
 	case 3:
 		// rig/crew/name -> gt-rig-crew-name
 		if parts[1] == "crew" {
-			return fmt.Sprintf("gt-%s-crew-%s", parts[0], parts[2]), true
+			return session.CrewSessionName(parts[0], parts[2]), true
 		}
 		// Other 3-part formats not recognized
 		return "", false
@@ -752,7 +778,7 @@ func runRigBoot(cmd *cobra.Command, args []string) error {
 
 	// 1. Start the witness
 	// Check actual tmux session, not state file (may be stale)
-	witnessSession := fmt.Sprintf("gt-%s-witness", rigName)
+	witnessSession := session.WitnessSessionName(rigName)
 	witnessRunning, _ := t.HasSession(witnessSession)
 	if witnessRunning {
 		skipped = append(skipped, "witness (already running)")
@@ -772,7 +798,7 @@ func runRigBoot(cmd *cobra.Command, args []string) error {
 
 	// 2. Start the refinery
 	// Check actual tmux session, not state file (may be stale)
-	refinerySession := fmt.Sprintf("gt-%s-refinery", rigName)
+	refinerySession := session.RefinerySessionName(rigName)
 	refineryRunning, _ := t.HasSession(refinerySession)
 	if refineryRunning {
 		skipped = append(skipped, "refinery (already running)")
@@ -832,7 +858,7 @@ func runRigStart(cmd *cobra.Command, args []string) error {
 		hasError := false
 
 		// 1. Start the witness
-		witnessSession := fmt.Sprintf("gt-%s-witness", rigName)
+		witnessSession := session.WitnessSessionName(rigName)
 		witnessRunning, _ := t.HasSession(witnessSession)
 		if witnessRunning {
 			skipped = append(skipped, "witness")
@@ -852,7 +878,7 @@ func runRigStart(cmd *cobra.Command, args []string) error {
 		}
 
 		// 2. Start the refinery
-		refinerySession := fmt.Sprintf("gt-%s-refinery", rigName)
+		refinerySession := session.RefinerySessionName(rigName)
 		refineryRunning, _ := t.HasSession(refinerySession)
 		if refineryRunning {
 			skipped = append(skipped, "refinery")
@@ -1066,7 +1092,7 @@ func runRigStatus(cmd *cobra.Command, args []string) error {
 
 	// Witness status
 	fmt.Printf("%s\n", style.Bold.Render("Witness"))
-	witnessSession := fmt.Sprintf("gt-%s-witness", rigName)
+	witnessSession := session.WitnessSessionName(rigName)
 	witnessRunning, _ := t.HasSession(witnessSession)
 	witMgr := witness.NewManager(r)
 	witStatus, _ := witMgr.Status()
@@ -1083,7 +1109,7 @@ func runRigStatus(cmd *cobra.Command, args []string) error {
 
 	// Refinery status
 	fmt.Printf("%s\n", style.Bold.Render("Refinery"))
-	refinerySession := fmt.Sprintf("gt-%s-refinery", rigName)
+	refinerySession := session.RefinerySessionName(rigName)
 	refineryRunning, _ := t.HasSession(refinerySession)
 	refMgr := refinery.NewManager(r)
 	refStatus, _ := refMgr.Status()
@@ -1113,7 +1139,7 @@ func runRigStatus(cmd *cobra.Command, args []string) error {
 	} else {
 		fmt.Printf(" (%d)\n", len(polecats))
 		for _, p := range polecats {
-			sessionName := fmt.Sprintf("gt-%s-%s", rigName, p.Name)
+			sessionName := session.PolecatSessionName(rigName, p.Name)
 			hasSession, _ := t.HasSession(sessionName)
 
 			sessionIcon := style.Dim.Render("○")
@@ -1154,8 +1180,10 @@ func runRigStatus(cmd *cobra.Command, args []string) error {
 			gitStatus, _ := crewGit.Status()
 
 			gitInfo := ""
-			if gitStatus != nil && !gitStatus.Clean {
-				gitInfo = style.Warning.Render(" (dirty)")
+			if gitStatus != nil {
+				if summary := gitStatus.Summary(); summary != "clean" {
+					gitInfo = style.Warning.Render(" (" + summary + ")")
+				}
 			}
 
 			fmt.Printf("  %s %s: %s%s\n", sessionIcon, w.Name, branch, gitInfo)
@@ -1412,7 +1440,7 @@ func runRigRestart(cmd *cobra.Command, args []string) error {
 		var skipped []string
 
 		// 1. Start the witness
-		witnessSession := fmt.Sprintf("gt-%s-witness", rigName)
+		witnessSession := session.WitnessSessionName(rigName)
 		witnessRunning, _ := t.HasSession(witnessSession)
 		if witnessRunning {
 			skipped = append(skipped, "witness")
@@ -1431,7 +1459,7 @@ func runRigRestart(cmd *cobra.Command, args []string) error {
 		}
 
 		// 2. Start the refinery
-		refinerySession := fmt.Sprintf("gt-%s-refinery", rigName)
+		refinerySession := session.RefinerySessionName(rigName)
 		refineryRunning, _ := t.HasSession(refinerySession)
 		if refineryRunning {
 			skipped = append(skipped, "refinery")
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var rigDiscoverDryRun bool
+
+var rigDiscoverCmd = &cobra.Command{
+	Use:   "discover",
+	Short: "Register rigs found in the town directory",
+	Long: `Scan the town directory for rigs that are not registered and register them.
+
+Use this after adding a rig by hand or syncing the town directory from
+another machine. A directory is recognized as a rig if it has a rig
+config.json, or a .beads/ directory plus a rig workspace (crew/, polecats/,
+witness/, refinery/rig, or mayor/rig).
+
+The git URL and beads prefix are read from the rig config, or from the
+rig's clones and .beads/config.yaml. A beads route is added for the rig's
+prefix unless another rig already routes it.
+
+Discovery also runs automatically on 'gt up' and 'gt start'.
+
+Examples:
+  gt rig discover
+  gt rig discover --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runRigDiscover,
+}
+
+func init() {
+	rigCmd.AddCommand(rigDiscoverCmd)
+	rigDiscoverCmd.Flags().BoolVar(&rigDiscoverDryRun, "dry-run", false, "Show rigs that would be registered")
+}
+
+func runRigDiscover(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	found, err := registerDiscoveredRigs(townRoot, rigDiscoverDryRun)
+	if err != nil {
+		return err
+	}
+	if len(found) == 0 {
+		fmt.Printf("%s No unregistered rigs found\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	verb := "Registered"
+	if rigDiscoverDryRun {
+		verb = "Would register"
+	}
+	for _, d := range found {
+		fmt.Printf("%s %s %s  %s\n", style.Bold.Render("✓"), verb, style.Bold.Render(d.Name), style.Dim.Render(strings.Join(d.Markers, " ")))
+		if d.Entry.GitURL == "" {
+			fmt.Printf("    %s no git URL found; set git_url for %s in mayor/rigs.json before spawning polecats\n",
+				style.Warning.Render("!"), d.Name)
+		}
+	}
+	return nil
+}
+
+// registerDiscoveredRigs registers unregistered rigs found in the town
+// directory and adds their beads routes. With dryRun nothing is written.
+func registerDiscoveredRigs(townRoot string, dryRun bool) ([]rig.Discovered, error) {
+	rigsPath := constants.MayorRigsPath(townRoot)
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Version: config.CurrentRigsVersion, Rigs: make(map[string]config.RigEntry)}
+	}
+
+	mgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+	found, err := mgr.Discover()
+	if err != nil {
+		return nil, fmt.Errorf("discovering rigs: %w", err)
+	}
+	if dryRun || len(found) == 0 {
+		return found, nil
+	}
+
+	for _, d := range found {
+		if err := mgr.Register(d); err != nil {
+			return nil, fmt.Errorf("registering rig %s: %w", d.Name, err)
+		}
+	}
+	if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+		return nil, fmt.Errorf("saving rigs config: %w", err)
+	}
+
+	routes, _ := beads.LoadRoutes(filepath.Join(townRoot, ".beads"))
+	for _, d := range found {
+		if d.Entry.BeadsConfig == nil || d.Entry.BeadsConfig.Prefix == "" {
+			continue
+		}
+		prefix := d.Entry.BeadsConfig.Prefix + "-"
+		if routeExists(routes, prefix) {
+			continue
+		}
+		routePath := d.Name
+		if _, err := os.Stat(filepath.Join(d.Path, "mayor", "rig", ".beads")); err == nil {
+			routePath = d.Name + "/mayor/rig"
+		}
+		if err := beads.AppendRoute(townRoot, beads.Route{Prefix: prefix, Path: routePath}); err != nil {
+			fmt.Printf("  %s Could not update routes.jsonl: %v\n", style.Warning.Render("!"), err)
+		}
+	}
+	return found, nil
+}
+
+func routeExists(routes []beads.Route, prefix string) bool {
+	for _, r := range routes {
+		if r.Prefix == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// autoDiscoverRigs registers rigs added to the town directory since the last
+// run. Used at startup; errors are reported but never block startup.
+func autoDiscoverRigs(townRoot string) {
+	found, err := registerDiscoveredRigs(townRoot, false)
+	if err != nil {
+		fmt.Printf("%s Rig discovery failed: %v\n", style.Warning.Render("!"), err)
+		return
+	}
+	for _, d := range found {
+		fmt.Printf("%s Discovered rig %s\n", style.Bold.Render("✓"), d.Name)
+	}
+}
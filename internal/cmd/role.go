@@ -179,7 +179,7 @@ func GetRoleWithContext(cwd, townRoot string) (RoleInfo, error) {
 
 		// If env is incomplete (missing rig/polecat for roles that need them),
 		// fill gaps from cwd detection and mark as incomplete
-		needsRig := parsedRole == RoleWitness || parsedRole == RoleRefinery || parsedRole == RolePolecat || parsedRole == RoleCrew
+		needsRig := parsedRole == RoleWitness || parsedRole == RoleRefinery || parsedRole == RoleReviewer || parsedRole == RolePolecat || parsedRole == RoleCrew
 		needsPolecat := parsedRole == RolePolecat || parsedRole == RoleCrew
 
 		if needsRig && info.Rig == "" && cwdCtx.Rig != "" {
@@ -235,6 +235,8 @@ func parseRoleString(s string) (Role, string, string) {
 		return RoleWitness, rig, ""
 	case "refinery":
 		return RoleRefinery, rig, ""
+	case "reviewer":
+		return RoleReviewer, rig, ""
 	case "polecats":
 		if len(parts) >= 3 {
 			return RolePolecat, rig, parts[2]
@@ -254,7 +256,7 @@ func parseRoleString(s string) (Role, string, string) {
 // ActorString returns the actor identity string for beads attribution.
 // Format matches beads created_by convention:
 //   - Simple roles: "mayor", "deacon"
-//   - Rig-specific: "gastown/witness", "gastown/refinery"
+//   - Rig-specific: "gastown/witness", "gastown/refinery", "gastown/reviewer"
 //   - Workers: "gastown/crew/max", "gastown/polecats/Toast"
 func (info RoleInfo) ActorString() string {
 	switch info.Role {
@@ -272,6 +274,11 @@ func (info RoleInfo) ActorString() string {
 			return fmt.Sprintf("%s/refinery", info.Rig)
 		}
 		return "refinery"
+	case RoleReviewer:
+		if info.Rig != "" {
+			return fmt.Sprintf("%s/reviewer", info.Rig)
+		}
+		return "reviewer"
 	case RolePolecat:
 		if info.Rig != "" && info.Polecat != "" {
 			return fmt.Sprintf("%s/polecats/%s", info.Rig, info.Polecat)
@@ -458,6 +465,7 @@ func runRoleList(cmd *cobra.Command, args []string) error {
 		{RoleDeacon, "Background supervisor daemon"},
 		{RoleWitness, "Per-rig polecat lifecycle manager"},
 		{RoleRefinery, "Per-rig merge queue processor"},
+		{RoleReviewer, "Per-MR code reviewer run by the refinery"},
 		{RolePolecat, "Ephemeral worker with own worktree"},
 		{RoleCrew, "Persistent worker with own worktree"},
 	}
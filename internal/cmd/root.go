@@ -2,13 +2,23 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/logging"
+	"github.com/steveyegge/gastown/internal/secret"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/version"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -30,6 +40,10 @@ var beadsExemptCommands = map[string]bool{
 	"version":    true,
 	"help":       true,
 	"completion": true,
+	"secret":     true, // Run by git credential helpers
+	"policy":     true, // Run by the PreToolUse hook before every tool call
+	"tool":       true, // Run by agents as their shell and file tools
+	"loop":       true, // Run by the UserPromptSubmit hook on every prompt
 }
 
 // Commands exempt from the town root branch warning.
@@ -43,18 +57,60 @@ var branchCheckExemptCommands = map[string]bool{
 	"git-init":   true, // Git setup
 }
 
+// Commands that may run when the town's session naming scheme can't be
+// loaded. They don't manage tmux sessions, help fix the settings, or are
+// run by hooks on every agent turn.
+var sessionNamingExemptCommands = map[string]bool{
+	"version":    true,
+	"help":       true,
+	"completion": true,
+	"doctor":     true, // Used to fix the problem
+	"config":     true, // Used to fix the problem
+	"install":    true, // Initial setup
+	"git-init":   true, // Git setup
+	"secret":     true, // Run by git credential helpers
+	"policy":     true, // Run by the PreToolUse hook before every tool call
+	"tool":       true, // Run by agents as their shell and file tools
+	"loop":       true, // Run by the UserPromptSubmit hook on every prompt
+}
+
 // persistentPreRun runs before every command.
 func persistentPreRun(cmd *cobra.Command, args []string) error {
 	// Get the root command name being run
 	cmdName := cmd.Name()
 
+	// Structured logs at the level and format gastown.toml [log] says
+	applyRedaction()
+	applyLogging()
+
+	// Trace this command (an agent's tool call, when an agent runs it)
+	startCommandSpan(cmd)
+
 	// Check town root branch (warning only, non-blocking)
 	if !branchCheckExemptCommands[cmdName] {
 		warnIfTownRootOffMain()
 	}
 
-	// Skip beads check for exempt commands
-	if beadsExemptCommands[cmdName] {
+	// Name tmux sessions with the town's naming scheme. Without it, session
+	// commands would act on the default-named sessions of another town.
+	if err := applySessionNaming(); err != nil {
+		if !sessionNamingExemptCommands[topLevelName(cmd)] {
+			return fmt.Errorf("%w\nFix it (see gt doctor) before running commands that manage tmux sessions", err)
+		}
+		fmt.Fprintf(os.Stderr, "%s %v\n", style.Warning.Render("⚠"), err)
+	}
+
+	// Retry flaky git network operations as gastown.toml [git] says
+	applyGitRetry()
+
+	// Limit an agent's pushes to what its role's permission profile allows
+	applyPushPermissions()
+
+	// Activity heartbeat: renew work leases held by the calling worker
+	renewWorkLeases()
+
+	// Skip beads check for exempt commands and their subcommands
+	if beadsExemptCommands[cmdName] || (cmd.HasParent() && beadsExemptCommands[cmd.Parent().Name()]) {
 		return nil
 	}
 
@@ -62,6 +118,165 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 	return CheckBeadsVersion()
 }
 
+// topLevelName returns the name of the gt subcommand cmd belongs to, e.g.
+// "config" for gt config agent list.
+func topLevelName(cmd *cobra.Command) string {
+	for cmd.HasParent() && cmd.Parent().HasParent() {
+		cmd = cmd.Parent()
+	}
+	return cmd.Name()
+}
+
+// applySessionNaming sets the tmux session naming scheme from the town's
+// settings, so towns sharing a tmux server keep their sessions apart.
+// Outside a town the default "gt-"/"hq-" scheme is kept. It returns an error,
+// leaving the default scheme in place, if the town's scheme can't be
+// determined.
+func applySessionNaming() error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil
+	}
+	settingsPath := config.TownSettingsPath(townRoot)
+	settings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("can't load the session naming scheme from %s: %w", settingsPath, err)
+	}
+	var prefix, hqPrefix string
+	if settings.Sessions != nil {
+		prefix, hqPrefix = settings.Sessions.Prefix, settings.Sessions.HQPrefix
+	}
+	townName, err := workspace.GetTownName(townRoot)
+	if err != nil && strings.Contains(prefix+hqPrefix, session.TownPlaceholder) {
+		return fmt.Errorf("can't name tmux sessions: the session prefixes use %s but the town name is unknown: %w", session.TownPlaceholder, err)
+	}
+	session.SetScheme(session.NewScheme(townName, prefix, hqPrefix))
+	return nil
+}
+
+// applyGitRetry sets the retry policy for git network operations from the
+// town's gastown.toml [git] section. Without a town or config, git keeps its
+// defaults.
+func applyGitRetry() {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+	gtConfig, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return
+	}
+	policy := git.DefaultRetryPolicy
+	if gtConfig.Git.Retries != nil {
+		policy.Retries = *gtConfig.Git.Retries
+	}
+	if backoff, err := time.ParseDuration(gtConfig.Git.RetryBackoff); err == nil {
+		policy.Backoff = backoff
+	}
+	git.SetDefaultRetryPolicy(policy)
+}
+
+// applyPushPermissions limits the git push guard to the calling agent's
+// permission profile (see gt policy). Commands run by people, without
+// GT_ROLE, are not limited.
+func applyPushPermissions() {
+	if os.Getenv(EnvGTRole) == "" {
+		return
+	}
+	info, err := GetRole()
+	if err != nil {
+		return
+	}
+	p, err := loadToolPolicy(info.TownRoot, info.Rig, string(info.Role))
+	if err != nil {
+		return
+	}
+	if prof := p.PermissionProfile(); prof != nil {
+		git.SetPushPermissions(git.PushPermissions{
+			NoPush:      prof.ReadOnly,
+			NoProtected: !prof.PushProtected,
+			Profile:     prof.Name,
+		})
+	}
+}
+
+// applyRedaction adds the town's gastown.toml [redact] patterns to the
+// built-in ones that scrub secrets from logs, captures, and events.
+func applyRedaction() {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+	gtConfig, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return
+	}
+	_ = secret.SetPatterns(gtConfig.Redact.Patterns) // validated on load
+}
+
+// applyLogging configures structured logging from the town's gastown.toml
+// [log] section, or only the GT_LOG_* overrides outside a town. An invalid
+// setting is reported and the defaults are used.
+func applyLogging() {
+	cfg := logging.Config{Level: os.Getenv(config.EnvLogLevel), Format: os.Getenv(config.EnvLogFormat)}
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		if gtConfig, err := config.LoadGastownConfig(townRoot); err == nil {
+			cfg = logging.Config{Level: gtConfig.Log.Level, Format: gtConfig.Log.Format}
+		}
+	}
+	if err := logging.Configure(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v; using default logging\n", style.Warning.Render("⚠"), err)
+		_ = logging.Configure(logging.Config{})
+	}
+}
+
+// Tracing state for the command being run, set by startCommandSpan.
+var (
+	commandSpan       trace.Span
+	telemetryShutdown func(context.Context) error
+)
+
+// startCommandSpan enables tracing as the town's gastown.toml [tracing]
+// section or the OTEL_* variables say, and starts the command's span,
+// continuing the trace of the process that ran gt (TRACEPARENT).
+func startCommandSpan(cmd *cobra.Command) {
+	cfg := telemetry.Config{Version: Version}
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		if gtConfig, err := config.LoadGastownConfig(townRoot); err == nil {
+			cfg.Endpoint = gtConfig.Tracing.Endpoint
+		}
+	}
+	shutdown, err := telemetry.Setup(context.Background(), cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s tracing disabled: %v\n", style.Warning.Render("⚠"), err)
+	}
+	telemetryShutdown = shutdown
+
+	ctx, span := telemetry.Tracer().Start(telemetry.ContextFromEnv(context.Background()), cmd.CommandPath(),
+		trace.WithAttributes(
+			telemetry.AttrCommand.String(cmd.CommandPath()),
+			telemetry.AttrActor.String(os.Getenv("BD_ACTOR")),
+			telemetry.AttrRole.String(os.Getenv("GT_ROLE")),
+			telemetry.AttrRig.String(os.Getenv("GT_RIG")),
+		))
+	telemetry.SetProcessContext(ctx)
+	commandSpan = span
+}
+
+// endCommandSpan ends the command's span and flushes spans to the collector.
+func endCommandSpan(err error) {
+	if commandSpan == nil {
+		return
+	}
+	if _, silent := IsSilentExit(err); silent {
+		err = nil
+	}
+	telemetry.End(commandSpan, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = telemetryShutdown(ctx) // best-effort: never fail a command over tracing
+}
+
 // warnIfTownRootOffMain prints a warning if the town root is not on main branch.
 // This is a non-blocking warning to help catch accidental branch switches.
 func warnIfTownRootOffMain() {
@@ -158,7 +373,9 @@ func checkStaleBinaryWarning() {
 // Execute runs the root command and returns an exit code.
 // The caller (main) should call os.Exit with this code.
 func Execute() int {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	endCommandSpan(err)
+	if err != nil {
 		// Check for silent exit (scripting commands that signal status via exit code)
 		if code, ok := IsSilentExit(err); ok {
 			return code
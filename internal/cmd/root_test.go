@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+func TestApplySessionNaming(t *testing.T) {
+	defer session.SetScheme(session.DefaultScheme())
+	townRoot := t.TempDir()
+	for rel, content := range map[string]string{
+		"mayor/town.json":      `{"type":"town","version":2,"name":"acme"}`,
+		"settings/config.json": `{"type":"town-settings","version":1,"sessions":{"prefix":"gt-{town}-","hq_prefix":"hq-{town}-"}}`,
+	} {
+		path := filepath.Join(townRoot, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	if err := os.Chdir(townRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := applySessionNaming(); err != nil {
+		t.Fatalf("applySessionNaming: %v", err)
+	}
+	if got := session.MayorSessionName(); got != "hq-acme-mayor" {
+		t.Errorf("mayor session = %q, want hq-acme-mayor", got)
+	}
+
+	// Unreadable settings must be reported, not silently fall back to
+	// another town's default names
+	session.SetScheme(session.DefaultScheme())
+	if err := os.WriteFile(filepath.Join(townRoot, "settings", "config.json"), []byte(`{"sessions":`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := applySessionNaming()
+	if err == nil || !strings.Contains(err.Error(), "session naming scheme") {
+		t.Errorf("applySessionNaming with bad settings = %v, want an error", err)
+	}
+}
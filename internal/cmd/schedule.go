@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/scheduler"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Schedule command flags
+var (
+	scheduleJSON         bool
+	scheduleHistoryLimit int
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:     "schedule",
+	GroupID: GroupServices,
+	Short:   "Manage recurring scheduled jobs",
+	RunE:    requireSubcommand,
+	Long: `Manage recurring jobs run by the daemon on cron schedules.
+
+Jobs are defined in settings/schedules.json and evaluated by the daemon
+every minute. Each job nudges an agent, sends mail, slings work to a rig
+(spawning a polecat), or runs a shell command from the town root.
+A job never overlaps with itself: if a previous run is still in progress,
+the next run is skipped and recorded as such.
+
+Example settings/schedules.json:
+  {
+    "type": "schedules",
+    "version": 1,
+    "jobs": [
+      {"name": "deps-update", "cron": "0 2 * * *", "action": "sling",
+       "bead": "mol-deps-update", "target": "gastown"},
+      {"name": "triage", "cron": "@hourly", "action": "nudge",
+       "target": "gastown/witness", "message": "Triage new beads"},
+      {"name": "cleanup", "cron": "0 4 * * 0", "action": "command",
+       "command": "gt doctor --fix", "timeout": "30m"}
+    ]
+  }
+
+Commands:
+  gt schedule list             List jobs with last and next run
+  gt schedule run <name>       Run a job now
+  gt schedule history [name]   Show run history`,
+}
+
+var scheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List scheduled jobs",
+	Long: `List all scheduled jobs with their cron expression, last run, and next run.
+
+Examples:
+  gt schedule list
+  gt schedule list --json`,
+	RunE: runScheduleList,
+}
+
+var scheduleRunCmd = &cobra.Command{
+	Use:   "run <name>",
+	Short: "Run a scheduled job now",
+	Long: `Run a scheduled job immediately, outside its cron schedule.
+
+The run is recorded in history and respects overlap protection: if the
+daemon is currently running the same job, this command fails.
+
+Examples:
+  gt schedule run triage`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScheduleRun,
+}
+
+var scheduleHistoryCmd = &cobra.Command{
+	Use:   "history [name]",
+	Short: "Show scheduled job run history",
+	Long: `Show recent runs of scheduled jobs, optionally filtered to one job.
+
+Examples:
+  gt schedule history
+  gt schedule history triage -n 5
+  gt schedule history --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runScheduleHistory,
+}
+
+func init() {
+	scheduleListCmd.Flags().BoolVar(&scheduleJSON, "json", false, "Output as JSON")
+	scheduleHistoryCmd.Flags().BoolVar(&scheduleJSON, "json", false, "Output as JSON")
+	scheduleHistoryCmd.Flags().IntVarP(&scheduleHistoryLimit, "limit", "n", 20, "Maximum number of runs to show")
+
+	scheduleCmd.AddCommand(scheduleListCmd)
+	scheduleCmd.AddCommand(scheduleRunCmd)
+	scheduleCmd.AddCommand(scheduleHistoryCmd)
+	rootCmd.AddCommand(scheduleCmd)
+}
+
+// ScheduleListItem represents a job in list output.
+type ScheduleListItem struct {
+	Name       string     `json:"name"`
+	Cron       string     `json:"cron"`
+	Action     string     `json:"action"`
+	Disabled   bool       `json:"disabled,omitempty"`
+	NextRun    *time.Time `json:"next_run,omitempty"`
+	LastRun    *time.Time `json:"last_run,omitempty"`
+	LastStatus string     `json:"last_status,omitempty"`
+}
+
+// loadSchedulesConfig loads the town's schedules config.
+func loadSchedulesConfig() (string, *config.SchedulesConfig, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := config.LoadOrCreateSchedulesConfig(config.SchedulesConfigPath(townRoot))
+	if err != nil {
+		return "", nil, fmt.Errorf("loading schedules config: %w", err)
+	}
+	return townRoot, cfg, nil
+}
+
+func runScheduleList(cmd *cobra.Command, args []string) error {
+	townRoot, cfg, err := loadSchedulesConfig()
+	if err != nil {
+		return err
+	}
+
+	lastRuns, err := scheduler.LastRuns(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading history: %w", err)
+	}
+
+	now := time.Now()
+	items := make([]ScheduleListItem, 0, len(cfg.Jobs))
+	for _, job := range cfg.Jobs {
+		item := ScheduleListItem{
+			Name:     job.Name,
+			Cron:     job.Cron,
+			Action:   job.Action,
+			Disabled: job.Disabled,
+		}
+		if sched, err := scheduler.ParseCron(job.Cron); err == nil && !job.Disabled {
+			if next := sched.Next(now); !next.IsZero() {
+				item.NextRun = &next
+			}
+		}
+		if last, ok := lastRuns[job.Name]; ok {
+			started := last.StartedAt
+			item.LastRun = &started
+			item.LastStatus = last.Status
+		}
+		items = append(items, item)
+	}
+
+	if scheduleJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	}
+
+	if len(items) == 0 {
+		fmt.Println("No scheduled jobs configured.")
+		fmt.Printf("\nDefine jobs in %s\n", style.Dim.Render(config.SchedulesConfigPath(townRoot)))
+		return nil
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render("Scheduled Jobs"))
+	for _, item := range items {
+		fmt.Printf("  %s  %s  %s", style.Bold.Render(item.Name), item.Cron, style.Dim.Render(item.Action))
+		if item.Disabled {
+			fmt.Printf("  %s", style.Dim.Render("(disabled)"))
+		}
+		fmt.Println()
+
+		if item.NextRun != nil {
+			fmt.Printf("    next: %s\n", item.NextRun.Format("2006-01-02 15:04"))
+		}
+		if item.LastRun != nil {
+			fmt.Printf("    last: %s %s\n", item.LastRun.Format("2006-01-02 15:04"), formatRunStatus(item.LastStatus))
+		}
+	}
+
+	return nil
+}
+
+func runScheduleRun(cmd *cobra.Command, args []string) error {
+	townRoot, cfg, err := loadSchedulesConfig()
+	if err != nil {
+		return err
+	}
+
+	s, err := scheduler.New(townRoot, cfg, nil)
+	if err != nil {
+		return fmt.Errorf("invalid schedules config: %w", err)
+	}
+
+	fmt.Printf("%s Running %s...\n", style.ArrowPrefix, args[0])
+	rec, err := s.RunNow(context.Background(), args[0])
+	if errors.Is(err, scheduler.ErrJobRunning) {
+		return fmt.Errorf("job %s is already running", args[0])
+	}
+	if rec != nil && rec.Output != "" {
+		fmt.Println(rec.Output)
+	}
+	if err != nil {
+		return fmt.Errorf("job %s failed: %w", args[0], err)
+	}
+
+	fmt.Printf("%s %s completed in %v\n", style.SuccessPrefix, args[0], rec.Duration().Round(time.Millisecond))
+	return nil
+}
+
+func runScheduleHistory(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	job := ""
+	if len(args) > 0 {
+		job = args[0]
+	}
+
+	records, err := scheduler.LoadHistory(townRoot, job, scheduleHistoryLimit)
+	if err != nil {
+		return fmt.Errorf("loading history: %w", err)
+	}
+
+	if scheduleJSON {
+		if records == nil {
+			records = []scheduler.RunRecord{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	}
+
+	if len(records) == 0 {
+		fmt.Println("No scheduled runs recorded.")
+		return nil
+	}
+
+	for _, rec := range records {
+		trigger := ""
+		if rec.Manual {
+			trigger = style.Dim.Render(" (manual)")
+		}
+		fmt.Printf("%s  %-20s %s %s%s\n",
+			rec.StartedAt.Format("2006-01-02 15:04:05"),
+			rec.Job,
+			formatRunStatus(rec.Status),
+			style.Dim.Render(rec.Duration().Round(time.Second).String()),
+			trigger)
+		if rec.Error != "" {
+			fmt.Printf("    %s\n", style.Dim.Render(rec.Error))
+		}
+	}
+
+	return nil
+}
+
+// formatRunStatus renders a run status with color.
+func formatRunStatus(status string) string {
+	switch status {
+	case scheduler.StatusSuccess:
+		return style.Success.Render(status)
+	case scheduler.StatusFailed:
+		return style.Error.Render(status)
+	default:
+		return style.Warning.Render(status)
+	}
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/secret"
+	"github.com/steveyegge/gastown/internal/style"
+	"golang.org/x/term"
+)
+
+var secretCmd = &cobra.Command{
+	Use:     "secret",
+	GroupID: GroupConfig,
+	Short:   "Store API keys and tokens in the OS keyring",
+	Long: `Store API keys, forge tokens, and webhook secrets in the OS keyring.
+
+Secrets live in the macOS Keychain, the Secret Service (GNOME Keyring or
+KWallet, via secret-tool) on Linux, or the Windows Credential Manager, under
+the service name "gastown". Nothing is written to disk in plaintext.
+
+Name secrets after the environment variables that would otherwise hold
+them. Settings that name a variable (forge.token_env,
+forge.webhook_secret_env, federation token_env) check the environment
+first, then the keyring, so an exported variable still wins.
+
+The value is read from the terminal without echo, or from stdin when piped,
+so it never appears in shell history or process listings.
+
+Examples:
+  gt secret set GITHUB_TOKEN               # Prompt for the token
+  pass show gitea | gt secret set GITEA_TOKEN
+  gt secret get GITEA_TOKEN                # Print for scripts
+  gt secret rm GT_WEBHOOK_SECRET`,
+	RunE: requireSubcommand,
+}
+
+var secretSetCmd = &cobra.Command{
+	Use:   "set <name>",
+	Short: "Store a secret in the keyring",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSecretSet,
+}
+
+var secretGetCmd = &cobra.Command{
+	Use:   "get <name>",
+	Short: "Print a secret from the keyring",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSecretGet,
+}
+
+var secretRmCmd = &cobra.Command{
+	Use:     "rm <name>",
+	Aliases: []string{"remove", "delete"},
+	Short:   "Remove a secret from the keyring",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runSecretRm,
+}
+
+func init() {
+	rootCmd.AddCommand(secretCmd)
+	secretCmd.AddCommand(secretSetCmd)
+	secretCmd.AddCommand(secretGetCmd)
+	secretCmd.AddCommand(secretRmCmd)
+}
+
+func runSecretSet(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := secret.ValidateName(name); err != nil {
+		return err
+	}
+
+	value, err := readSecretValue(name)
+	if err != nil {
+		return err
+	}
+	if err := secret.Set(name, value); err != nil {
+		return fmt.Errorf("storing %s: %w", name, err)
+	}
+
+	fmt.Printf("%s Stored %s in %s\n", style.Bold.Render("✓"), name, secret.Backend())
+	if os.Getenv(name) != "" {
+		style.PrintWarning("$%s is set in this environment and takes precedence over the keyring", name)
+	}
+	return nil
+}
+
+func runSecretGet(cmd *cobra.Command, args []string) error {
+	value, err := secret.Get(args[0])
+	if errors.Is(err, secret.ErrNotFound) {
+		return fmt.Errorf("no secret named %s in %s", args[0], secret.Backend())
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println(value)
+	return nil
+}
+
+func runSecretRm(cmd *cobra.Command, args []string) error {
+	err := secret.Delete(args[0])
+	if errors.Is(err, secret.ErrNotFound) {
+		return fmt.Errorf("no secret named %s in %s", args[0], secret.Backend())
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed %s\n", style.Bold.Render("✓"), args[0])
+	return nil
+}
+
+// readSecretValue prompts for a secret without echo on a terminal, or reads
+// the first line of stdin when piped.
+func readSecretValue(name string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		fmt.Fprintf(os.Stderr, "Value for %s: ", name)
+		data, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("reading value: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("reading value: %w", err)
+	}
+	value := strings.TrimRight(line, "\r\n")
+	if value == "" {
+		return "", errors.New("no value on stdin")
+	}
+	return value, nil
+}
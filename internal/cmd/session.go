@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/suggest"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -30,6 +31,7 @@ var (
 	sessionFile      string
 	sessionRigFilter string
 	sessionListJSON  bool
+	sessionListFed   bool
 )
 
 var sessionCmd = &cobra.Command{
@@ -89,7 +91,8 @@ var sessionListCmd = &cobra.Command{
 	Short: "List all sessions",
 	Long: `List all running polecat sessions.
 
-Shows session status, rig, and polecat name. Use --rig to filter by rig.`,
+Shows session status, rig, and polecat name. Use --rig to filter by rig.
+Use --federated to include polecat sessions from federated towns.`,
 	RunE: runSessionList,
 }
 
@@ -176,6 +179,7 @@ func init() {
 	// List flags
 	sessionListCmd.Flags().StringVar(&sessionRigFilter, "rig", "", "Filter by rig name")
 	sessionListCmd.Flags().BoolVar(&sessionListJSON, "json", false, "Output as JSON")
+	sessionListCmd.Flags().BoolVar(&sessionListFed, "federated", false, "Include polecat sessions from federated towns")
 
 	// Capture flags
 	sessionCaptureCmd.Flags().IntVarP(&sessionLines, "lines", "n", 100, "Number of lines to capture")
@@ -262,13 +266,21 @@ func runSessionStart(cmd *cobra.Command, args []string) error {
 	}
 
 	opts := polecat.SessionStartOptions{
-		Issue: sessionIssue,
+		Issue:  sessionIssue,
+		Resume: true,
 	}
+	// Keep the account the polecat last ran on, where its conversation is
+	acct, err := polecatMgr.ResumeAccount(polecatName, "", &opts)
+	if err != nil {
+		return fmt.Errorf("resolving account: %w", err)
+	}
+	printResolvedAccount(acct)
 
 	fmt.Printf("Starting session for %s/%s...\n", rigName, polecatName)
 	if err := polecatMgr.Start(polecatName, opts); err != nil {
 		return fmt.Errorf("starting session: %w", err)
 	}
+	_ = acct.Record(filepath.Dir(r.Path), polecatMgr.SessionName(polecatName))
 
 	fmt.Printf("%s Session started. Attach with: %s\n",
 		style.Bold.Render("✓"),
@@ -337,6 +349,7 @@ func runSessionAttach(cmd *cobra.Command, args []string) error {
 
 // SessionListItem represents a session in list output.
 type SessionListItem struct {
+	Town      string `json:"town,omitempty"` // set for federated towns
 	Rig       string `json:"rig"`
 	Polecat   string `json:"polecat"`
 	SessionID string `json:"session_id"`
@@ -397,6 +410,14 @@ func runSessionList(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if sessionListFed {
+		remote, err := federatedSessions(townRoot)
+		if err != nil {
+			return err
+		}
+		allSessions = append(allSessions, remote...)
+	}
+
 	// Output
 	if sessionListJSON {
 		enc := json.NewEncoder(os.Stdout)
@@ -415,7 +436,11 @@ func runSessionList(cmd *cobra.Command, args []string) error {
 		if !s.Running {
 			status = style.Dim.Render("○")
 		}
-		fmt.Printf("  %s %s/%s\n", status, s.Rig, s.Polecat)
+		if s.Town != "" {
+			fmt.Printf("  %s %s:%s/%s\n", status, s.Town, s.Rig, s.Polecat)
+		} else {
+			fmt.Printf("  %s %s/%s\n", status, s.Rig, s.Polecat)
+		}
 		fmt.Printf("    %s\n", style.Dim.Render(s.SessionID))
 	}
 
@@ -495,7 +520,7 @@ func runSessionRestart(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	polecatMgr, _, err := getSessionManager(rigName)
+	polecatMgr, r, err := getSessionManager(rigName)
 	if err != nil {
 		return err
 	}
@@ -518,12 +543,18 @@ func runSessionRestart(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Start fresh session
+	// Start fresh session, on the account the polecat last ran on
+	opts := polecat.SessionStartOptions{Resume: true}
+	acct, err := polecatMgr.ResumeAccount(polecatName, "", &opts)
+	if err != nil {
+		return fmt.Errorf("resolving account: %w", err)
+	}
+	printResolvedAccount(acct)
 	fmt.Printf("Starting session for %s/%s...\n", rigName, polecatName)
-	opts := polecat.SessionStartOptions{}
 	if err := polecatMgr.Start(polecatName, opts); err != nil {
 		return fmt.Errorf("starting session: %w", err)
 	}
+	_ = acct.Record(filepath.Dir(r.Path), polecatMgr.SessionName(polecatName))
 
 	fmt.Printf("%s Session restarted. Attach with: %s\n",
 		style.Bold.Render("✓"),
@@ -653,7 +684,7 @@ func runSessionCheck(cmd *cobra.Command, args []string) error {
 				continue
 			}
 			polecatName := entry.Name()
-			sessionName := fmt.Sprintf("gt-%s-%s", r.Name, polecatName)
+			sessionName := session.PolecatSessionName(r.Name, polecatName)
 			totalChecked++
 
 			// Check if session exists
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/sla"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var slaCheckDryRun bool
+
+var slaCmd = &cobra.Command{
+	Use:     "sla",
+	GroupID: GroupWork,
+	Short:   "Check beads against per-priority SLAs",
+	Long: `Check beads against per-priority SLAs.
+
+SLAs are configured per priority in settings/config.json, measured from
+bead creation:
+
+  "sla": {
+    "priorities": {
+      "P0": {"start_within": "15m", "finish_within": "4h"},
+      "P1": {"start_within": "1h", "finish_within": "8h"}
+    }
+  }
+
+A bead is started once it is slung (or hooked / in progress) and finished
+once it is closed. 'gt bead list' shows how close each bead is to its SLA.
+
+Examples:
+  gt sla check                # Escalate new breaches (run by the daemon)
+  gt sla check --dry-run      # Show breaches without escalating`,
+	RunE: requireSubcommand,
+}
+
+var slaCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Escalate beads that breached their SLA",
+	Long: `Evaluate open beads against their SLA and escalate each new breach.
+
+Each breach escalates once: a bead that misses its start deadline escalates
+at medium severity, and again at high severity if it later misses its finish
+deadline. Breaches on P0 beads are critical. Escalations are routed like
+'gt escalate', so the mayor is notified. The daemon runs this on every
+heartbeat.`,
+	RunE: runSLACheck,
+}
+
+func init() {
+	slaCheckCmd.Flags().BoolVarP(&slaCheckDryRun, "dry-run", "n", false, "Show breaches without escalating")
+	slaCmd.AddCommand(slaCheckCmd)
+
+	rootCmd.AddCommand(slaCmd)
+}
+
+func runSLACheck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	if settings.SLA == nil || len(settings.SLA.Priorities) == 0 {
+		return nil
+	}
+
+	list, err := collectSLABeads(townRoot, "", beads.ListOptions{Priority: -1}, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var escalationConfig *config.EscalationConfig
+	for _, b := range list {
+		if !b.SLA.Breached() {
+			continue
+		}
+		fmt.Printf("%s %s %s: %s\n", style.Error.Render("✗"), b.ID, b.Title, b.SLA.Summary())
+		if !sla.ShouldAlert(townRoot, b.SLA) {
+			continue
+		}
+
+		severity := slaBreachSeverity(b.SLA)
+		if slaCheckDryRun {
+			fmt.Printf("  Would escalate (%s)\n", severity)
+			continue
+		}
+		if escalationConfig == nil {
+			escalationConfig, err = config.LoadOrCreateEscalationConfig(config.EscalationConfigPath(townRoot))
+			if err != nil {
+				return fmt.Errorf("loading escalation config: %w", err)
+			}
+		}
+		issueID, _, _, err := sendEscalation(townRoot, escalationConfig, escalationRequest{
+			Description: fmt.Sprintf("SLA breach: %s %s", b.ID, b.Title),
+			Severity:    severity,
+			Reason:      b.SLA.Summary(),
+			Source:      "sla:" + b.SLA.State,
+			From:        "deacon/",
+			RelatedBead: b.ID,
+		})
+		if err != nil {
+			style.PrintWarning("could not escalate %s: %v", b.ID, err)
+			continue
+		}
+		_ = sla.MarkAlerted(townRoot, b.SLA)
+		fmt.Printf("  %s Escalated as %s (%s)\n", style.Bold.Render("✓"), issueID, severity)
+	}
+	return nil
+}
+
+// slaBreachSeverity maps a breach to an escalation severity: P0 breaches are
+// critical, missed finish deadlines high, and missed start deadlines medium.
+func slaBreachSeverity(s *sla.Status) string {
+	switch {
+	case s.Priority == 0:
+		return config.SeverityCritical
+	case s.State == sla.StateFinishBreached:
+		return config.SeverityHigh
+	default:
+		return config.SeverityMedium
+	}
+}
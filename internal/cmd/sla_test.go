@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/sla"
+)
+
+func TestSLABreachSeverity(t *testing.T) {
+	tests := []struct {
+		priority int
+		state    string
+		want     string
+	}{
+		{0, sla.StateStartBreached, config.SeverityCritical},
+		{1, sla.StateFinishBreached, config.SeverityHigh},
+		{2, sla.StateStartBreached, config.SeverityMedium},
+	}
+	for _, tt := range tests {
+		if got := slaBreachSeverity(&sla.Status{Priority: tt.priority, State: tt.state}); got != tt.want {
+			t.Errorf("P%d %s: severity = %q, want %q", tt.priority, tt.state, got, tt.want)
+		}
+	}
+}
+
+func TestIsWorkBead(t *testing.T) {
+	tests := []struct {
+		issue *beads.Issue
+		want  bool
+	}{
+		{&beads.Issue{Type: "bug"}, true},
+		{&beads.Issue{Type: "task", Labels: []string{"frontend"}}, true},
+		{&beads.Issue{Type: "agent"}, false},
+		{&beads.Issue{Type: "task", Labels: []string{"gt:merge-request"}}, false},
+	}
+	for _, tt := range tests {
+		if got := isWorkBead(tt.issue); got != tt.want {
+			t.Errorf("isWorkBead(%+v) = %v, want %v", tt.issue, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/slack"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var slackCmd = &cobra.Command{
+	Use:     "slack",
+	GroupID: GroupComm,
+	Short:   "Post town events to Slack and take commands from it",
+	Long: `Connect the town to a Slack app, so on-call humans can supervise it from
+chat.
+
+Notifications: the daemon posts session lifecycle (agents starting,
+stopping, dying, paused as runaway loops), escalation, and merge events to
+Slack on every heartbeat (gt slack sync). Post with the app's bot token to
+a channel per kind of event, or with an incoming webhook to its channel.
+
+Control: gt dashboard serves the app's /gt slash command at POST
+/slack/commands (set it as the command's Request URL). Requests are verified
+with the app's signing secret.
+  /gt status                    the town's health (gt health)
+  /gt prompt <agent> <text>     nudge an agent (mayor, deacon,
+                                gastown/witness, gastown/polecats/toast, or a
+                                tmux session name)
+  /gt approve <mr> [message]    approve an MR under review (gt review)
+  /gt stop <agent>              stop an agent's session
+prompt, approve, and stop are only for the Slack users listed in operators,
+and are posted in the channel and recorded in the event log
+(gt events --type chat_command).
+
+Example gastown.toml:
+  [slack]
+  bot_token = "secret://slack-bot-token"
+  signing_secret = "secret://slack-signing-secret"
+  channel = "#gastown"
+  operators = ["U012AB3CD"]
+
+  [slack.channels]
+  escalation = "#oncall"
+
+Examples:
+  gt slack test                 # Post a test message
+  gt slack sync                 # Post new events now`,
+	RunE: requireSubcommand,
+}
+
+var slackSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Post events logged since the last sync (run by the daemon)",
+	RunE:  runSlackSync,
+}
+
+var slackTestCmd = &cobra.Command{
+	Use:   "test [message]",
+	Short: "Post a test message to the default channel",
+	RunE:  runSlackTest,
+}
+
+func init() {
+	slackCmd.AddCommand(slackSyncCmd)
+	slackCmd.AddCommand(slackTestCmd)
+	rootCmd.AddCommand(slackCmd)
+}
+
+// slackPoster returns the town's Slack poster, or nil if it doesn't post
+// to Slack.
+func slackPoster(cfg config.SlackConfig) (slack.Poster, error) {
+	switch {
+	case cfg.BotToken != "":
+		token, err := config.ExpandRefs(cfg.BotToken)
+		if err != nil {
+			return nil, fmt.Errorf("resolving slack.bot_token: %w", err)
+		}
+		return slack.NewClient(token), nil
+	case cfg.WebhookURL != "":
+		url, err := config.ExpandRefs(cfg.WebhookURL)
+		if err != nil {
+			return nil, fmt.Errorf("resolving slack.webhook_url: %w", err)
+		}
+		return slack.NewWebhook(url), nil
+	}
+	return nil, nil
+}
+
+// slackSigningSecret returns the town's Slack signing secret ("" = slash
+// commands aren't configured).
+func slackSigningSecret(townRoot string) (string, error) {
+	cfg, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return "", err
+	}
+	if cfg.Slack.SigningSecret == "" {
+		return "", nil
+	}
+	return config.ExpandRefs(cfg.Slack.SigningSecret)
+}
+
+func runSlackSync(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	poster, err := slackPoster(cfg.Slack)
+	if err != nil || poster == nil {
+		return err
+	}
+	posted, err := slack.Sync(context.Background(), townRoot, cfg.Slack, poster)
+	if posted > 0 {
+		fmt.Printf("Posted %d event(s) to Slack\n", posted)
+	}
+	return err
+}
+
+func runSlackTest(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	poster, err := slackPoster(cfg.Slack)
+	if err != nil {
+		return err
+	}
+	if poster == nil {
+		return fmt.Errorf("slack not configured: set bot_token or webhook_url in gastown.toml's [slack]")
+	}
+	text := strings.Join(args, " ")
+	if text == "" {
+		name, _ := workspace.GetTownName(townRoot)
+		text = strings.TrimSpace(fmt.Sprintf(":wave: Gas Town %s is connected", name))
+	}
+	if err := poster.Post(context.Background(), cfg.Slack.Channel, text); err != nil {
+		return err
+	}
+	fmt.Printf("%s Posted to Slack\n", style.Success.Render("✓"))
+	return nil
+}
+
+// runSlackCommand runs a verified /gt slash command for the town.
+func runSlackCommand(townRoot string, c *slack.Command) slack.Reply {
+	verb, rest := c.Verb()
+	target, text, _ := strings.Cut(rest, " ")
+	cc := chatCommand{Via: "slack", Verb: verb, Target: target, Text: strings.TrimSpace(text), UserID: c.UserID, UserName: c.UserName}
+	reply := runChatCommand(townRoot, cc, func(cfg *config.GastownConfig, id string) bool { return cfg.Slack.IsOperator(id) })
+	return slack.Reply{Text: reply.Text, InChannel: reply.InChannel}
+}
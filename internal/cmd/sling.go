@@ -223,6 +223,9 @@ func runSling(cmd *cobra.Command, args []string) error {
 			}
 		} else if rigName, isRig := IsRigName(target); isRig {
 			// Check if target is a rig name (auto-spawn polecat)
+			if err := checkSpawnClaim(beadID, slingForce); err != nil {
+				return err
+			}
 			if slingDryRun {
 				// Dry run - just indicate what would happen
 				fmt.Printf("Would spawn fresh polecat in rig '%s'\n", rigName)
@@ -261,24 +264,38 @@ func runSling(cmd *cobra.Command, args []string) error {
 					parts := strings.Split(target, "/")
 					if len(parts) >= 3 && parts[1] == "polecats" {
 						rigName := parts[0]
-						fmt.Printf("Target polecat has no active session, spawning fresh polecat in rig '%s'...\n", rigName)
-						spawnOpts := SlingSpawnOptions{
-							Force:    slingForce,
-							Account:  slingAccount,
-							Create:   slingCreate,
-							HookBead: beadID,
-							Agent:    slingAgent,
-						}
-						spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
-						if spawnErr != nil {
-							return fmt.Errorf("spawning polecat to replace dead polecat: %w", spawnErr)
+						// A hibernated polecat resumes its previous conversation
+						if woke, wakeErr := wakeHibernatedPolecat(rigName, parts[2]); woke {
+							if wakeErr != nil {
+								return fmt.Errorf("waking hibernated polecat: %w", wakeErr)
+							}
+							targetAgent, targetPane, targetWorkDir, err = resolveTargetAgent(target)
+							if err != nil {
+								return fmt.Errorf("resolving woken polecat: %w", err)
+							}
+						} else {
+							if err := checkSpawnClaim(beadID, slingForce); err != nil {
+								return err
+							}
+							fmt.Printf("Target polecat has no active session, spawning fresh polecat in rig '%s'...\n", rigName)
+							spawnOpts := SlingSpawnOptions{
+								Force:    slingForce,
+								Account:  slingAccount,
+								Create:   slingCreate,
+								HookBead: beadID,
+								Agent:    slingAgent,
+							}
+							spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
+							if spawnErr != nil {
+								return fmt.Errorf("spawning polecat to replace dead polecat: %w", spawnErr)
+							}
+							targetAgent = spawnInfo.AgentID()
+							targetPane = spawnInfo.Pane
+							hookWorkDir = spawnInfo.ClonePath
+
+							// Wake witness and refinery to monitor the new polecat
+							wakeRigAgents(rigName)
 						}
-						targetAgent = spawnInfo.AgentID()
-						targetPane = spawnInfo.Pane
-						hookWorkDir = spawnInfo.ClonePath
-
-						// Wake witness and refinery to monitor the new polecat
-						wakeRigAgents(rigName)
 					} else {
 						return fmt.Errorf("resolving target: %w", err)
 					}
@@ -367,6 +384,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  args (in nudge): %s\n", slingArgs)
 		}
 		fmt.Printf("Would inject start prompt to pane: %s\n", targetPane)
+		fmt.Printf("  prompt: %s\n", slingStartPrompt(townRoot, targetAgent, beadID, info))
 		return nil
 	}
 
@@ -440,14 +458,15 @@ func runSling(cmd *cobra.Command, args []string) error {
 
 	// Hook the bead using bd update.
 	// See: https://github.com/steveyegge/gastown/issues/148
-	hookCmd := exec.Command("bd", "--no-daemon", "update", beadID, "--status=hooked", "--assignee="+targetAgent)
-	hookCmd.Dir = beads.ResolveHookDir(townRoot, beadID, hookWorkDir)
-	hookCmd.Stderr = os.Stderr
-	if err := hookCmd.Run(); err != nil {
+	// The claim is a compare-and-set on the assignee, so a bead another
+	// agent took meanwhile is reported as a conflict, not assigned twice.
+	if err := hookBead(townRoot, beadID, targetAgent, hookWorkDir, slingForce); err != nil {
 		return fmt.Errorf("hooking bead: %w", err)
 	}
 
 	fmt.Printf("%s Work attached to hook (status=hooked)\n", style.Bold.Render("✓"))
+	acquireWorkLease(townRoot, beadID, targetAgent)
+	recordAssignment(townRoot, beadID, targetAgent, slingAgent)
 
 	// Log sling event to activity feed
 	actor := detectActor()
@@ -495,7 +514,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 			}
 		}
 
-		if err := injectStartPrompt(targetPane, beadID, slingSubject, slingArgs); err != nil {
+		if err := injectStartPrompt(targetPane, slingStartPrompt(townRoot, targetAgent, beadID, info)); err != nil {
 			// Graceful fallback for no-tmux mode
 			fmt.Printf("%s Could not nudge (no tmux?): %v\n", style.Dim.Render("○"), err)
 			fmt.Printf("  Agent will discover work via gt prime / bd show\n")
@@ -1,9 +1,8 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"os"
-	"os/exec"
 	"path/filepath"
 
 	"github.com/steveyegge/gastown/internal/beads"
@@ -57,6 +56,11 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			fmt.Printf("  %s Already pinned (use --force to re-sling)\n", style.Dim.Render("✗"))
 			continue
 		}
+		if holder := claimedByOther(info, ""); holder != "" && !slingForce {
+			results = append(results, slingResult{beadID: beadID, success: false, errMsg: "claimed by " + holder})
+			fmt.Printf("  %s Already claimed by %s (use --force to re-sling)\n", style.Dim.Render("✗"), holder)
+			continue
+		}
 
 		// Spawn a fresh polecat
 		spawnOpts := SlingSpawnOptions{
@@ -93,16 +97,19 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 
 		// Hook the bead. See: https://github.com/steveyegge/gastown/issues/148
 		townRoot := filepath.Dir(townBeadsDir)
-		hookCmd := exec.Command("bd", "--no-daemon", "update", beadID, "--status=hooked", "--assignee="+targetAgent)
-		hookCmd.Dir = beads.ResolveHookDir(townRoot, beadID, hookWorkDir)
-		hookCmd.Stderr = os.Stderr
-		if err := hookCmd.Run(); err != nil {
-			results = append(results, slingResult{beadID: beadID, polecat: spawnInfo.PolecatName, success: false, errMsg: "hook failed"})
+		if err := hookBead(townRoot, beadID, targetAgent, hookWorkDir, slingForce); err != nil {
+			errMsg := "hook failed"
+			if errors.Is(err, beads.ErrAlreadyClaimed) {
+				errMsg = "claimed by another agent"
+			}
+			results = append(results, slingResult{beadID: beadID, polecat: spawnInfo.PolecatName, success: false, errMsg: errMsg})
 			fmt.Printf("  %s Failed to hook bead: %v\n", style.Dim.Render("✗"), err)
 			continue
 		}
 
 		fmt.Printf("  %s Work attached to %s\n", style.Bold.Render("✓"), spawnInfo.PolecatName)
+		acquireWorkLease(townRoot, beadID, targetAgent)
+		recordAssignment(townRoot, beadID, targetAgent, slingAgent)
 
 		// Log sling event
 		actor := detectActor()
@@ -125,7 +132,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 
 		// Nudge the polecat
 		if spawnInfo.Pane != "" {
-			if err := injectStartPrompt(spawnInfo.Pane, beadID, slingSubject, slingArgs); err != nil {
+			if err := injectStartPrompt(spawnInfo.Pane, slingStartPrompt(townRoot, targetAgent, beadID, info)); err != nil {
 				fmt.Printf("  %s Could not nudge (agent will discover via gt prime)\n", style.Dim.Render("○"))
 			} else {
 				fmt.Printf("  %s Start prompt sent\n", style.Bold.Render("▶"))
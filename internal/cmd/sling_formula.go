@@ -219,6 +219,7 @@ func runSlingFormula(args []string) error {
 		return fmt.Errorf("hooking wisp bead: %w", err)
 	}
 	fmt.Printf("%s Attached to hook (status=hooked)\n", style.Bold.Render("✓"))
+	acquireWorkLease(townRoot, wispRootID, targetAgent)
 
 	// Log sling event to activity feed (formula slinging)
 	actor := detectActor()
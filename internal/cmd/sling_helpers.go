@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -11,6 +12,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -18,9 +20,13 @@ import (
 
 // beadInfo holds status and assignee for a bead.
 type beadInfo struct {
-	Title    string `json:"title"`
-	Status   string `json:"status"`
-	Assignee string `json:"assignee"`
+	Title       string   `json:"title"`
+	Status      string   `json:"status"`
+	Assignee    string   `json:"assignee"`
+	Description string   `json:"description"`
+	Type        string   `json:"issue_type"`
+	Priority    int      `json:"priority"`
+	Labels      []string `json:"labels"`
 }
 
 // verifyBeadExists checks that the bead exists using bd show.
@@ -170,9 +176,25 @@ func storeDispatcherInBead(beadID, dispatcher string) error {
 	return nil
 }
 
+// formatStartPrompt builds the default "start now" prompt for slung work.
+// Rigs can replace it per bead type with a prompt template (see slingWorkPrompt).
+func formatStartPrompt(beadID, subject, args string) string {
+	if args != "" {
+		// Args provided - include them prominently in the prompt
+		if subject != "" {
+			return fmt.Sprintf("Work slung: %s (%s). Args: %s. Start working now - use these args to guide your execution.", beadID, subject, args)
+		}
+		return fmt.Sprintf("Work slung: %s. Args: %s. Start working now - use these args to guide your execution.", beadID, args)
+	}
+	if subject != "" {
+		return fmt.Sprintf("Work slung: %s (%s). Start working on it now - no questions, just begin.", beadID, subject)
+	}
+	return fmt.Sprintf("Work slung: %s. Start working on it now - run `gt hook` to see the hook, then begin.", beadID)
+}
+
 // injectStartPrompt sends a prompt to the target pane to start working.
 // Uses the reliable nudge pattern: literal mode + 500ms debounce + separate Enter.
-func injectStartPrompt(pane, beadID, subject, args string) error {
+func injectStartPrompt(pane, prompt string) error {
 	if pane == "" {
 		return fmt.Errorf("no target pane")
 	}
@@ -182,21 +204,6 @@ func injectStartPrompt(pane, beadID, subject, args string) error {
 		return nil
 	}
 
-	// Build the prompt to inject
-	var prompt string
-	if args != "" {
-		// Args provided - include them prominently in the prompt
-		if subject != "" {
-			prompt = fmt.Sprintf("Work slung: %s (%s). Args: %s. Start working now - use these args to guide your execution.", beadID, subject, args)
-		} else {
-			prompt = fmt.Sprintf("Work slung: %s. Args: %s. Start working now - use these args to guide your execution.", beadID, args)
-		}
-	} else if subject != "" {
-		prompt = fmt.Sprintf("Work slung: %s (%s). Start working on it now - no questions, just begin.", beadID, subject)
-	} else {
-		prompt = fmt.Sprintf("Work slung: %s. Start working on it now - run `gt hook` to see the hook, then begin.", beadID)
-	}
-
 	// Use the reliable nudge pattern (same as gt nudge / tmux.NudgeSession)
 	t := tmux.NewTmux()
 	return t.NudgePane(pane, prompt)
@@ -368,8 +375,8 @@ func wakeRigAgents(rigName string) {
 
 	// Nudge witness and refinery to clear any backoff
 	t := tmux.NewTmux()
-	witnessSession := fmt.Sprintf("gt-%s-witness", rigName)
-	refinerySession := fmt.Sprintf("gt-%s-refinery", rigName)
+	witnessSession := session.WitnessSessionName(rigName)
+	refinerySession := session.RefinerySessionName(rigName)
 
 	// Silent nudges - sessions might not exist yet
 	_ = t.NudgeSession(witnessSession, "Polecat dispatched - check for work")
@@ -439,3 +446,45 @@ func attachPolecatWorkMolecule(targetAgent, hookWorkDir, townRoot string) error
 	fmt.Printf("%s Attached %s to %s\n", style.Bold.Render("✓"), moleculeID, agentBeadID)
 	return nil
 }
+
+// claimedByOther returns the agent holding the bead if it is assigned to
+// someone other than agentID and not closed, or "" if it is free to take.
+// Pass agentID "" when the taker is not known yet (a polecat about to spawn).
+func claimedByOther(info *beadInfo, agentID string) string {
+	if info == nil || info.Assignee == "" || info.Assignee == agentID || info.Status == "closed" {
+		return ""
+	}
+	return info.Assignee
+}
+
+// hookBead claims the bead for agentID and marks it hooked. The claim is a
+// compare-and-set in the bead store, so a bead that another agent (or a
+// human) took in the meantime fails with a conflict instead of being
+// assigned twice. force takes the bead regardless.
+func hookBead(townRoot, beadID, agentID, hookWorkDir string, force bool) error {
+	b := beads.New(beads.ResolveHookDir(townRoot, beadID, hookWorkDir))
+	err := b.Claim(beadID, agentID, beads.ClaimOptions{Force: force})
+	var conflict *beads.ClaimConflictError
+	if errors.As(err, &conflict) {
+		return fmt.Errorf("%w\nUse --force to re-sling", err)
+	}
+	return err
+}
+
+// checkSpawnClaim fails before a polecat is spawned for a bead that another
+// agent already holds. The claim at hook time is authoritative; this check
+// only avoids spawning a polecat that could not take the work.
+func checkSpawnClaim(beadID string, force bool) error {
+	if force {
+		return nil
+	}
+	info, err := getBeadInfo(beadID)
+	if err != nil {
+		return nil // verified earlier; let the hook report real failures
+	}
+	if holder := claimedByOther(info, ""); holder != "" {
+		return fmt.Errorf("%w: bead %s is held by %s (status %s)\nUse --force to re-sling",
+			beads.ErrAlreadyClaimed, beadID, holder, info.Status)
+	}
+	return nil
+}
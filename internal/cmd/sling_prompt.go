@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workprompt"
+)
+
+// slingWorkPrompt renders the rig's prompt template for a bead slung to a
+// polecat. Returns nil if the target is not a polecat or its rig has no
+// prompt for the bead's type. Template errors are reported as warnings and
+// the default start prompt is used instead.
+func slingWorkPrompt(townRoot, targetAgent, beadID string, info *beadInfo) *workprompt.Prompt {
+	parts := strings.Split(targetAgent, "/")
+	if len(parts) != 3 || parts[1] != "polecats" || info == nil {
+		return nil
+	}
+	rigName, polecatName := parts[0], parts[2]
+
+	settingsPath := config.RigSettingsPath(filepath.Join(townRoot, rigName))
+	settings, err := config.LoadRigSettings(settingsPath)
+	if err != nil {
+		if !errors.Is(err, config.ErrNotFound) {
+			fmt.Printf("%s Could not load rig settings for work prompt: %v\n", style.Dim.Render("Warning:"), err)
+		}
+		return nil
+	}
+
+	prompt, err := workprompt.Build(settings, filepath.Dir(settingsPath), workprompt.Work{
+		ID:          beadID,
+		Title:       info.Title,
+		Description: info.Description,
+		Type:        info.Type,
+		Priority:    info.Priority,
+		Labels:      info.Labels,
+		Files:       workprompt.LinkedFiles(info.Description, info.Labels),
+		Rig:         rigName,
+		Polecat:     polecatName,
+		Subject:     slingSubject,
+		Args:        slingArgs,
+	})
+	if err != nil {
+		fmt.Printf("%s Could not render work prompt for %s (using default): %v\n", style.Dim.Render("Warning:"), beadID, err)
+		return nil
+	}
+	return prompt
+}
+
+// slingStartPrompt returns the start prompt to nudge a target with: the
+// rig's rendered work prompt if it has one, otherwise the default.
+func slingStartPrompt(townRoot, targetAgent, beadID string, info *beadInfo) string {
+	if p := slingWorkPrompt(townRoot, targetAgent, beadID, info); p != nil && p.Text != "" {
+		return p.Text
+	}
+	return formatStartPrompt(beadID, slingSubject, slingArgs)
+}
+
+// polecatToolGrantFlags returns the agent flags for the tool grants in the
+// rig's work prompt for beadID, or "" if there are none. agentName is the
+// agent the polecat runs; grants are skipped with a warning if it does not
+// support them.
+func polecatToolGrantFlags(townRoot, rigName, polecatName, beadID, agentName string) string {
+	info, err := getBeadInfo(beadID)
+	if err != nil {
+		return ""
+	}
+	prompt := slingWorkPrompt(townRoot, rigName+"/polecats/"+polecatName, beadID, info)
+	if prompt == nil || (len(prompt.AllowedTools) == 0 && len(prompt.DisallowedTools) == 0) {
+		return ""
+	}
+	flags := config.BuildToolGrantFlags(agentName, prompt.AllowedTools, prompt.DisallowedTools)
+	if flags == "" {
+		fmt.Printf("%s Agent %s does not support tool grants; ignoring work prompt tools\n", style.Dim.Render("Warning:"), agentName)
+	}
+	return flags
+}
@@ -227,6 +227,9 @@ echo "$(pwd)|$*" >> "${BD_LOG}"
 if [ "$1" = "--no-daemon" ]; then
   shift
 fi
+if [ "$1" = "--allow-stale" ]; then
+  shift
+fi
 cmd="$1"
 shift || true
 case "$cmd" in
@@ -389,6 +392,9 @@ echo "ARGS:$*" >> "${BD_LOG}"
 if [ "$1" = "--no-daemon" ]; then
   shift
 fi
+if [ "$1" = "--allow-stale" ]; then
+  shift
+fi
 cmd="$1"
 shift || true
 case "$cmd" in
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/account"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/crew"
@@ -170,6 +171,9 @@ func runStart(cmd *cobra.Command, args []string) error {
 	fmt.Println("Starting all agents in parallel...")
 	fmt.Println()
 
+	// Register rigs added to the town directory by hand or by a sync
+	autoDiscoverRigs(townRoot)
+
 	// Discover rigs once upfront to avoid redundant calls from parallel goroutines
 	rigs, rigsErr := discoverAllRigs(townRoot)
 	if rigsErr != nil {
@@ -480,7 +484,7 @@ func runShutdown(cmd *cobra.Command, args []string) error {
 func categorizeSessions(sessions []string, mayorSession, deaconSession string) (toStop, preserved []string) {
 	for _, sess := range sessions {
 		// Gas Town sessions use gt- (rig-level) or hq- (town-level) prefix
-		if !strings.HasPrefix(sess, "gt-") && !strings.HasPrefix(sess, "hq-") {
+		if !session.HasRigPrefix(sess) && !session.HasHQPrefix(sess) {
 			continue // Not a Gas Town session
 		}
 
@@ -813,19 +817,17 @@ func runStartCrew(cmd *cobra.Command, args []string) error {
 	crewMgr := crew.NewManager(r, crewGit)
 
 	// Resolve account for Claude config
-	accountsPath := constants.MayorAccountsPath(townRoot)
-	claudeConfigDir, accountHandle, err := config.ResolveAccountConfigDir(accountsPath, startCrewAccount)
+	acct, err := account.Resolve(townRoot, account.Selection{Flag: startCrewAccount, Role: constants.RoleCrew, Rig: rigName})
 	if err != nil {
 		return fmt.Errorf("resolving account: %w", err)
 	}
-	if accountHandle != "" {
-		fmt.Printf("Using account: %s\n", accountHandle)
-	}
+	printResolvedAccount(acct)
 
 	// Use manager's Start() method - handles workspace creation, settings, and session
 	err = crewMgr.Start(name, crew.StartOptions{
 		Account:         startCrewAccount,
-		ClaudeConfigDir: claudeConfigDir,
+		ClaudeConfigDir: acct.ConfigDirOrEmpty(),
+		AccountEnv:      acct.Env(),
 		AgentOverride:   startCrewAgentOverride,
 	})
 	if err != nil {
@@ -835,6 +837,7 @@ func runStartCrew(cmd *cobra.Command, args []string) error {
 			return err
 		}
 	} else {
+		_ = acct.Record(townRoot, crewMgr.SessionName(name))
 		fmt.Printf("%s Started crew workspace: %s/%s\n",
 			style.Bold.Render("✓"), rigName, name)
 	}
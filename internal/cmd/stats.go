@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/outcome"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	statsJSON  bool
+	statsBy    string
+	statsRig   string
+	statsSince string
+
+	// Record subcommand flags
+	statsRecordCommit string
+	statsRecordReason string
+)
+
+var statsCmd = &cobra.Command{
+	Use:     "stats",
+	GroupID: GroupDiag,
+	Short:   "Show assignment outcomes and success rates",
+	Long: `Show what happened to assigned work, aggregated per role, worker, rig, or agent.
+
+Every bead slung to an agent is an assignment. Its outcome is recorded as it
+progresses:
+  gt sling          assignment starts (agent, rig, role, model)
+  gt done           exit type, PR opened, test files added, wall time
+  Refinery          merged, rejected (merge failures), reverted later
+  gt costs record   session cost and tokens
+
+Outcomes are kept in <town>/.runtime/outcomes.jsonl. The same stats are
+served as JSON by gt dashboard at /api/stats (?by=, ?rig=, ?since=).
+
+Columns:
+  SUCCESS   merged and not reverted, as a share of assignments
+  TESTS     assignments that added or changed test files
+  AVG TIME  mean time from sling to gt done
+
+Examples:
+  gt stats                       # Totals for all assignments
+  gt stats --by model            # Compare agents/models
+  gt stats --by worker --rig gastown --since 7d
+  gt stats --by role --json`,
+	RunE: runStats,
+}
+
+var statsRecordCmd = &cobra.Command{
+	Use:   "record <merged|merge-failed|reverted> <bead>",
+	Short: "Record a Refinery outcome for an assignment",
+	Long: `Record a merge, merge failure, or revert for the assignment of a bead.
+
+The Refinery records outcomes automatically when it merges with gt mq train.
+When it merges by hand it runs this after pushing, alongside the MERGED mail.
+
+Examples:
+  gt stats record merged gt-abc123 --commit $(git rev-parse HEAD)
+  gt stats record merge-failed gt-abc123 --reason tests
+  gt stats record reverted gt-abc123`,
+	Args: cobra.ExactArgs(2),
+	RunE: runStatsRecord,
+}
+
+func init() {
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "Output as JSON")
+	statsCmd.Flags().StringVar(&statsBy, "by", "", "Group by role, worker, rig, or model")
+	statsCmd.Flags().StringVar(&statsRig, "rig", "", "Only assignments in this rig")
+	statsCmd.Flags().StringVar(&statsSince, "since", "", "Only assignments started within this window (e.g., 24h, 7d)")
+
+	statsRecordCmd.Flags().StringVar(&statsRecordCommit, "commit", "", "Merge commit (merged)")
+	statsRecordCmd.Flags().StringVar(&statsRecordReason, "reason", "", "Failure type, e.g. conflict or tests (merge-failed)")
+	statsCmd.AddCommand(statsRecordCmd)
+
+	rootCmd.AddCommand(statsCmd)
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	switch statsBy {
+	case "", outcome.ByRole, outcome.ByWorker, outcome.ByRig, outcome.ByModel:
+	default:
+		return fmt.Errorf("invalid --by %q: want role, worker, rig, or model", statsBy)
+	}
+
+	outcomes, err := outcome.Load(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading outcomes: %w", err)
+	}
+	if statsRig != "" {
+		var filtered []*outcome.Outcome
+		for _, o := range outcomes {
+			if o.Rig == statsRig {
+				filtered = append(filtered, o)
+			}
+		}
+		outcomes = filtered
+	}
+	if statsSince != "" {
+		window, err := parseDuration(statsSince)
+		if err != nil || window <= 0 {
+			return fmt.Errorf("invalid --since %q: want a duration like 24h or 7d", statsSince)
+		}
+		outcomes = outcome.Since(outcomes, time.Now().Add(-window))
+	}
+
+	stats := outcome.Aggregate(outcomes, statsBy)
+
+	if statsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+
+	if len(stats) == 0 {
+		fmt.Printf("%s No assignment outcomes recorded yet\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	title := "Assignment outcomes"
+	if statsBy != "" {
+		title += " by " + statsBy
+	}
+	if statsRig != "" {
+		title += " in " + statsRig
+	}
+	if statsSince != "" {
+		title += " (last " + statsSince + ")"
+	}
+	fmt.Printf("%s %s\n\n", style.Bold.Render("📊"), title)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "KEY\tASSIGNED\tDONE\tPRS\tMERGED\tREVERTED\tFAILURES\tTESTS\tSUCCESS\tAVG TIME\tCOST\t$/MERGE\tTOKENS")
+	for _, s := range stats {
+		avg := "-"
+		if d := s.AvgWallTime(); d > 0 {
+			avg = formatDuration(d)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%.0f%%\t%s\t$%.2f\t$%.2f\t%d\n",
+			s.Key, s.Assignments, s.Done, s.PRsOpened, s.Merged, s.Reverted, s.MergeFailures,
+			s.WithTests, 100*s.SuccessRate(), avg, s.CostUSD, s.CostPerMerge(), s.Tokens)
+	}
+	return w.Flush()
+}
+
+func runStatsRecord(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	u := outcome.Update{Bead: args[1]}
+	switch args[0] {
+	case "merged":
+		u.Kind = outcome.KindMerged
+		u.Commit = statsRecordCommit
+	case "merge-failed":
+		u.Kind = outcome.KindMergeFailed
+		u.Reason = statsRecordReason
+	case "reverted":
+		u.Kind = outcome.KindReverted
+	default:
+		return fmt.Errorf("unknown outcome %q: want merged, merge-failed, or reverted", args[0])
+	}
+
+	if err := outcome.Record(townRoot, u); err != nil {
+		return fmt.Errorf("recording outcome: %w", err)
+	}
+	fmt.Printf("%s Recorded %s for %s\n", style.Bold.Render("✓"), args[0], u.Bead)
+	return nil
+}
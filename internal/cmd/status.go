@@ -17,8 +17,10 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/health"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -38,7 +40,8 @@ var statusCmd = &cobra.Command{
 	Short:   "Show overall town status",
 	Long: `Display the current status of the Gas Town workspace.
 
-Shows town name, registered rigs, active polecats, and witness status.
+Shows town name, rolled-up health (see gt health), registered rigs, active
+polecats, and witness status.
 
 Use --fast to skip mail lookups for faster execution.
 Use --watch to continuously refresh status at regular intervals.`,
@@ -62,6 +65,7 @@ type TownStatus struct {
 	Agents   []AgentRuntime `json:"agents"`             // Global agents (Mayor, Deacon)
 	Rigs     []RigStatus    `json:"rigs"`
 	Summary  StatusSum      `json:"summary"`
+	Health   *health.Town   `json:"health,omitempty"` // Rolled-up health (see gt health)
 }
 
 // OverseerInfo represents the human operator's identity and status.
@@ -328,6 +332,13 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 
 	var wg sync.WaitGroup
 
+	// Roll up health in parallel with everything else
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		status.Health, _ = collectTownHealth(townRoot)
+	}()
+
 	// Fetch global agents in parallel with rig discovery
 	wg.Add(1)
 	go func() {
@@ -422,7 +433,14 @@ func outputStatusJSON(status TownStatus) error {
 func outputStatusText(status TownStatus) error {
 	// Header
 	fmt.Printf("%s %s\n", style.Bold.Render("Town:"), status.Name)
-	fmt.Printf("%s\n\n", style.Dim.Render(status.Location))
+	fmt.Printf("%s\n", style.Dim.Render(status.Location))
+	if status.Health != nil {
+		fmt.Printf("%s %s %s\n", healthIcon(status.Health.State), style.Bold.Render("Health:"), status.Health.State)
+		for _, reason := range status.Health.Reasons {
+			fmt.Printf("    %s\n", style.Dim.Render(reason))
+		}
+	}
+	fmt.Println()
 
 	// Overseer info
 	if status.Overseer != nil {
@@ -1044,7 +1062,7 @@ func discoverRigAgents(allSessions map[string]bool, r *rig.Rig, crews []string,
 		defs = append(defs, agentDef{
 			name:    "refinery",
 			address: r.Name + "/refinery",
-			session: fmt.Sprintf("gt-%s-refinery", r.Name),
+			session: session.RefinerySessionName(r.Name),
 			role:    "refinery",
 			beadID:  beads.RefineryBeadIDWithPrefix(prefix, r.Name),
 		})
@@ -1055,7 +1073,7 @@ func discoverRigAgents(allSessions map[string]bool, r *rig.Rig, crews []string,
 		defs = append(defs, agentDef{
 			name:    name,
 			address: r.Name + "/" + name,
-			session: fmt.Sprintf("gt-%s-%s", r.Name, name),
+			session: session.PolecatSessionName(r.Name, name),
 			role:    "polecat",
 			beadID:  beads.PolecatBeadIDWithPrefix(prefix, r.Name, name),
 		})
@@ -1,6 +1,10 @@
 package cmd
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
 
 func TestCategorizeSessionRig(t *testing.T) {
 	tests := []struct {
@@ -84,3 +88,22 @@ func TestCategorizeSessionType(t *testing.T) {
 		})
 	}
 }
+
+func TestCategorizeSessionScheme(t *testing.T) {
+	defer session.SetScheme(session.DefaultScheme())
+	session.SetScheme(session.NewScheme("acme", "gt-{town}-", "hq-{town}-"))
+
+	agent := categorizeSession("gt-acme-gastown-crew-max")
+	if agent == nil || agent.Type != AgentCrew || agent.Rig != "gastown" || agent.Town != "acme" {
+		t.Fatalf("categorizeSession() = %+v", agent)
+	}
+	if agent := categorizeSession("hq-acme-mayor"); agent == nil || agent.Type != AgentMayor {
+		t.Errorf("categorizeSession(hq-acme-mayor) = %+v", agent)
+	}
+	// Another town's sessions on the same tmux server are ignored
+	for _, other := range []string{"hq-mayor", "gt-gastown-witness"} {
+		if agent := categorizeSession(other); agent != nil {
+			t.Errorf("categorizeSession(%q) = %+v, want nil", other, agent)
+		}
+	}
+}
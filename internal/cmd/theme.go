@@ -124,7 +124,7 @@ func runThemeApply(cmd *cobra.Command, args []string) error {
 	// Apply to matching sessions
 	applied := 0
 	for _, sess := range sessions {
-		if !strings.HasPrefix(sess, "gt-") {
+		if !session.HasRigPrefix(sess) {
 			continue
 		}
 
@@ -140,9 +140,9 @@ func runThemeApply(cmd *cobra.Command, args []string) error {
 			theme = tmux.DeaconTheme()
 			worker = "Deacon"
 			role = "health-check"
-		} else if strings.HasSuffix(sess, "-witness") && strings.HasPrefix(sess, "gt-") {
+		} else if strings.HasSuffix(sess, "-witness") && session.HasRigPrefix(sess) {
 			// Witness sessions: gt-<rig>-witness
-			rig = strings.TrimPrefix(strings.TrimSuffix(sess, "-witness"), "gt-")
+			rig = strings.TrimSuffix(session.TrimRigPrefix(sess), "-witness")
 			theme = getThemeForRole(rig, "witness")
 			worker = "witness"
 			role = "witness"
@@ -359,6 +359,7 @@ func saveRigTheme(rigName, themeName string) error {
 	if err := config.SaveRigSettings(settingsPath, settings); err != nil {
 		return fmt.Errorf("saving settings: %w", err)
 	}
+	logConfigChange(townRoot, settingsPath, "theme", themeName)
 
 	return nil
 }
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/tools"
+)
+
+// Tool command flags
+var (
+	toolTimeout    time.Duration
+	toolMaxOutput  int
+	toolReadOffset int
+	toolReadLimit  int
+	toolGrepMax    int
+	toolJSON       bool
+)
+
+var toolCmd = &cobra.Command{
+	Use:     "tool",
+	GroupID: GroupAgents,
+	Short:   "Shell and file tools confined to the agent's workspace",
+	Long: `Run shell and file tools confined to the calling agent's workspace: its
+worktree or clone, or the current directory outside an agent session.
+Relative paths are relative to the workspace root.
+
+Every call is checked against the town's tool policy (gt policy show); file
+paths must be inside the workspace unless [policy] paths allows more. Grant
+the "sandbox" toolset to have a role's sessions use these instead of their
+runtime's own shell and file tools.
+
+Examples:
+  gt tool bash -- go test ./...
+  gt tool read internal/cmd/root.go --offset 100 --limit 50
+  echo 'package main' | gt tool write cmd/new/main.go
+  gt tool ls internal
+  gt tool grep 'func Test' internal/policy`,
+	RunE: requireSubcommand,
+}
+
+var toolBashCmd = &cobra.Command{
+	Use:   "bash -- <command>",
+	Short: "Run a shell command in the workspace",
+	Long: `Run a command with bash in the workspace root.
+
+The command is killed after --timeout; only the last --max-output bytes of
+its output are kept. gt exits with the command's exit code (124 on timeout).`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runToolBash,
+}
+
+var toolReadCmd = &cobra.Command{
+	Use:   "read <path>",
+	Short: "Print a file in the workspace",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runToolRead,
+}
+
+var toolWriteCmd = &cobra.Command{
+	Use:   "write <path>",
+	Short: "Write stdin to a file in the workspace",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runToolWrite,
+}
+
+var toolLsCmd = &cobra.Command{
+	Use:   "ls [path]",
+	Short: "List a directory in the workspace",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runToolLs,
+}
+
+var toolGrepCmd = &cobra.Command{
+	Use:   "grep <pattern> [path]",
+	Short: "Search files in the workspace for a regular expression",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE:  runToolGrep,
+}
+
+func init() {
+	toolCmd.PersistentFlags().IntVar(&toolMaxOutput, "max-output", tools.DefaultMaxOutput, "Maximum bytes of output")
+	toolBashCmd.Flags().DurationVar(&toolTimeout, "timeout", tools.DefaultTimeout, "Kill the command after this long")
+	toolReadCmd.Flags().IntVar(&toolReadOffset, "offset", 0, "First line to print (1-based)")
+	toolReadCmd.Flags().IntVar(&toolReadLimit, "limit", 0, "Maximum lines to print (0 = all)")
+	toolGrepCmd.Flags().IntVarP(&toolGrepMax, "max", "n", tools.DefaultMaxMatches, "Maximum matches to print")
+	toolLsCmd.Flags().BoolVar(&toolJSON, "json", false, "Output as JSON")
+	toolGrepCmd.Flags().BoolVar(&toolJSON, "json", false, "Output as JSON")
+
+	toolCmd.AddCommand(toolBashCmd)
+	toolCmd.AddCommand(toolReadCmd)
+	toolCmd.AddCommand(toolWriteCmd)
+	toolCmd.AddCommand(toolLsCmd)
+	toolCmd.AddCommand(toolGrepCmd)
+	rootCmd.AddCommand(toolCmd)
+}
+
+// toolWorkspace returns the calling agent's workspace: its role home and
+// policy inside a town, else the current directory with no policy.
+func toolWorkspace() (*tools.Workspace, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("getting current directory: %w", err)
+	}
+	ws := &tools.Workspace{Root: cwd, Timeout: toolTimeout, MaxOutput: toolMaxOutput}
+
+	info, err := GetRole()
+	if err != nil {
+		return ws, nil
+	}
+	if info.Home != "" {
+		ws.Root = info.Home
+	}
+	p, err := loadToolPolicy(info.TownRoot, info.Rig, string(info.Role))
+	if err != nil {
+		return nil, err
+	}
+	ws.Policy = p
+	ws.ProtectedBranches = rigProtectedBranches(info.TownRoot, info.Rig)
+	return ws, nil
+}
+
+func runToolBash(cmd *cobra.Command, args []string) error {
+	ws, err := toolWorkspace()
+	if err != nil {
+		return err
+	}
+	res, err := ws.Bash(context.Background(), strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	fmt.Print(res.Output)
+	if res.TimedOut {
+		fmt.Fprintf(os.Stderr, "gt tool: command timed out after %s\n", ws.Timeout)
+		return NewSilentExit(124)
+	}
+	if res.ExitCode != 0 {
+		return NewSilentExit(res.ExitCode)
+	}
+	return nil
+}
+
+func runToolRead(cmd *cobra.Command, args []string) error {
+	ws, err := toolWorkspace()
+	if err != nil {
+		return err
+	}
+	text, err := ws.ReadFile(args[0], toolReadOffset, toolReadLimit)
+	if err != nil {
+		return err
+	}
+	fmt.Print(text)
+	return nil
+}
+
+func runToolWrite(cmd *cobra.Command, args []string) error {
+	ws, err := toolWorkspace()
+	if err != nil {
+		return err
+	}
+	content, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("reading stdin: %w", err)
+	}
+	if err := ws.WriteFile(args[0], string(content)); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d bytes to %s\n", len(content), args[0])
+	return nil
+}
+
+func runToolLs(cmd *cobra.Command, args []string) error {
+	ws, err := toolWorkspace()
+	if err != nil {
+		return err
+	}
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	entries, err := ws.ListDir(path)
+	if err != nil {
+		return err
+	}
+	if toolJSON {
+		return json.NewEncoder(os.Stdout).Encode(entries)
+	}
+	for _, e := range entries {
+		if e.IsDir {
+			fmt.Printf("%s/\n", e.Name)
+		} else {
+			fmt.Printf("%-40s %d\n", e.Name, e.Size)
+		}
+	}
+	return nil
+}
+
+func runToolGrep(cmd *cobra.Command, args []string) error {
+	ws, err := toolWorkspace()
+	if err != nil {
+		return err
+	}
+	path := "."
+	if len(args) > 1 {
+		path = args[1]
+	}
+	matches, err := ws.Grep(args[0], path, toolGrepMax)
+	if err != nil {
+		return err
+	}
+	if toolJSON {
+		return json.NewEncoder(os.Stdout).Encode(matches)
+	}
+	for _, m := range matches {
+		fmt.Printf("%s:%d:%s\n", m.Path, m.Line, m.Text)
+	}
+	if len(matches) == 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/migrate"
+	"github.com/steveyegge/gastown/internal/secret"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
+	"github.com/steveyegge/gastown/internal/townbundle"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	townImportForce  bool
+	townImportDryRun bool
+	townMigrateDry   bool
+)
+
+var townExportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "Export the town's configuration as a portable bundle",
+	Long: `Write the town's configuration to a bundle (JSON) that gt town import
+can load into another town: its identity, rig registrations, account
+profiles, settings, schedules, escalation routes, gastown.toml (prompts,
+policies, integrations), town.yaml, and each rig's settings and
+gastown.toml.
+
+Secrets aren't exported. Config refers to them as secret://name, which is
+kept, and the bundle lists the names to set on the new machine. Credentials
+written into a file are replaced with [REDACTED], and the files are named
+so they can be moved to gt secret.
+
+The bundle is written to stdout if no file is given.
+
+Examples:
+  gt town export town-bundle.json
+  gt town export | ssh west 'cd ~/gt && gt town import -'`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTownExport,
+}
+
+var townImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import a bundle from gt town export into this town",
+	Long: `Load a bundle made by gt town export into this town.
+
+Rigs the bundle registers that this town lacks are added with gt rig add
+(which clones them), and then the bundle's files are written. A file that
+already exists with different contents is skipped unless --force is given.
+Files from an older gt are upgraded to the current formats first (see gt
+town migrate).
+
+The secrets the bundle refers to are listed if they aren't set here; store
+them with gt secret set.
+
+Examples:
+  gt town import town-bundle.json --dry-run
+  gt town import town-bundle.json
+  gt town import - < town-bundle.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTownImport,
+}
+
+var townMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the town's state files to the current formats",
+	Long: `Upgrade the town's JSON state files that are in an older format, after
+upgrading gt. Each file changed is backed up beside it as
+<file>.v<old version>.bak. gt doctor reports files that need it.
+
+Examples:
+  gt town migrate --dry-run   # Show what would be upgraded
+  gt town migrate`,
+	RunE: runTownMigrate,
+}
+
+func init() {
+	townImportCmd.Flags().BoolVar(&townImportForce, "force", false, "Overwrite existing files that differ from the bundle's")
+	townImportCmd.Flags().BoolVar(&townImportDryRun, "dry-run", false, "Show what would be imported without changing anything")
+	townMigrateCmd.Flags().BoolVar(&townMigrateDry, "dry-run", false, "Show what would be upgraded without changing anything")
+
+	townCmd.AddCommand(townExportCmd)
+	townCmd.AddCommand(townImportCmd)
+	townCmd.AddCommand(townMigrateCmd)
+}
+
+func runTownExport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	b, redacted, err := townbundle.Export(townRoot)
+	if err != nil {
+		return err
+	}
+	b.GTVersion = Version
+
+	out := os.Stdout
+	if len(args) > 0 && args[0] != "-" {
+		f, err := os.OpenFile(args[0], os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := b.Write(out); err != nil {
+		return err
+	}
+
+	// Report on stderr, so the bundle can be piped
+	if len(redacted) > 0 {
+		fmt.Fprintf(os.Stderr, "%s Redacted credentials in %s; move them to gt secret and refer to them as secret://name\n",
+			style.Warning.Render("⚠"), strings.Join(redacted, ", "))
+	}
+	if len(args) > 0 && args[0] != "-" {
+		fmt.Fprintf(os.Stderr, "%s Exported %d file(s) from town %s to %s\n", style.Success.Render("✓"), len(b.Files), b.Town, args[0])
+	}
+	if len(b.Secrets) > 0 {
+		fmt.Fprintf(os.Stderr, "  Secrets to set where it's imported: %s\n", strings.Join(b.Secrets, ", "))
+	}
+	return nil
+}
+
+func runTownImport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	in := os.Stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	b, err := townbundle.Read(in)
+	if err != nil {
+		return err
+	}
+	steps, err := b.Migrate()
+	if err != nil {
+		return err
+	}
+	for _, s := range steps {
+		fmt.Printf("  Upgraded %s\n", s)
+	}
+
+	newRigs, err := importRigs(cmd, townRoot, b)
+	if err != nil {
+		return err
+	}
+	written, skipped, err := b.Install(townRoot, townbundle.InstallOptions{
+		Force:   townImportForce,
+		DryRun:  townImportDryRun,
+		NewRigs: newRigs,
+	})
+	verb := "Wrote"
+	if townImportDryRun {
+		verb = "Would write"
+	}
+	for _, rel := range written {
+		fmt.Printf("%s %s %s\n", style.Success.Render("✓"), verb, rel)
+		if strings.Contains(b.Files[rel], secret.Redacted) {
+			fmt.Printf("  %s it has redacted credentials to fill in\n", style.Warning.Render("⚠"))
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if len(skipped) > 0 {
+		fmt.Printf("%s Skipped %d file(s) that differ here (use --force to overwrite): %s\n",
+			style.Warning.Render("⚠"), len(skipped), strings.Join(skipped, ", "))
+	}
+
+	var missing []string
+	for _, name := range b.Secrets {
+		if secret.Lookup(name) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		fmt.Printf("\nSecrets the config refers to that aren't set here:\n")
+		for _, name := range missing {
+			fmt.Printf("  gt secret set %s\n", name)
+		}
+	}
+	return nil
+}
+
+// importRigs adds the rigs the bundle registers that the town lacks, and
+// returns them.
+func importRigs(cmd *cobra.Command, townRoot string, b *townbundle.Bundle) (map[string]bool, error) {
+	rigs, err := b.Rigs()
+	if err != nil {
+		return nil, err
+	}
+	registered := make(map[string]config.RigEntry)
+	if current, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot)); err == nil {
+		registered = current.Rigs
+	} else if !errors.Is(err, config.ErrNotFound) {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(rigs))
+	for name := range rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	gtPath, err := os.Executable()
+	if err != nil {
+		gtPath = "gt"
+	}
+	added := make(map[string]bool)
+	for _, name := range names {
+		entry := rigs[name]
+		if have, ok := registered[name]; ok {
+			if have.GitURL != entry.GitURL {
+				style.PrintWarning("rig %s is registered here from %s, not %s; keeping it", name, have.GitURL, entry.GitURL)
+			}
+			continue
+		}
+		args := []string{"rig", "add", name, entry.GitURL}
+		if entry.BeadsConfig != nil && entry.BeadsConfig.Prefix != "" {
+			args = append(args, "--prefix", entry.BeadsConfig.Prefix)
+		}
+		added[name] = true
+		if townImportDryRun {
+			fmt.Printf("%s Would run gt %s\n", style.Success.Render("✓"), strings.Join(args, " "))
+			continue
+		}
+		run := exec.Command(gtPath, args...)
+		run.Dir = townRoot
+		run.Env = append(os.Environ(), telemetry.EnvList(cmd.Context())...)
+		run.Stdout = os.Stdout
+		run.Stderr = os.Stderr
+		if err := run.Run(); err != nil {
+			return nil, fmt.Errorf("adding rig %s: %w", name, err)
+		}
+	}
+	return added, nil
+}
+
+func runTownMigrate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	steps, err := migrate.Town(townRoot, townMigrateDry)
+	for _, s := range steps {
+		if townMigrateDry {
+			fmt.Printf("  Would upgrade %s\n", s)
+		} else {
+			fmt.Printf("%s Upgraded %s\n", style.Success.Render("✓"), s)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if len(steps) == 0 {
+		fmt.Println("State files are current")
+	}
+	return nil
+}
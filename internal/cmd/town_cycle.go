@@ -43,7 +43,8 @@ func init() {
 var townCmd = &cobra.Command{
 	Use:   "town",
 	Short: "Town-level operations",
-	Long:  `Commands for town-level operations including session cycling.`,
+	Long: `Commands for town-level operations: session cycling, exporting and
+importing the town's configuration, and upgrading its state files.`,
 }
 
 var townNextCmd = &cobra.Command{
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Helpers shared by the issue tracker integrations (gt jira, gt linear),
+// which import a tracker's issues as labeled beads and report the work done
+// on them back to the tracker.
+
+// trackerStage returns the stage (see config.TrackerStages) an event
+// marks, or "" if it doesn't mark one.
+func trackerStage(e events.Event) string {
+	switch e.Type {
+	case events.TypeSling:
+		return "assigned"
+	case events.TypeDone:
+		return "pr"
+	case events.TypeMerged:
+		return "merged"
+	}
+	return ""
+}
+
+// trackedBead returns the bead a stage event is about: the bead slung or
+// finished, or the merged MR's source issue. It returns nil if there's no
+// such bead, or it no longer exists, so the event is skipped rather than
+// retried forever.
+func trackedBead(townBeads *beads.Beads, e events.Event) (*beads.Issue, error) {
+	beadID, _ := e.Payload["bead"].(string)
+	if e.Type == events.TypeMerged {
+		mrID, _ := e.Payload["mr"].(string)
+		if mrID == "" {
+			return nil, nil
+		}
+		mr, err := townBeads.Show(mrID)
+		if err != nil {
+			return nil, lookupErr(err)
+		}
+		if fields := beads.ParseMRFields(mr); fields != nil {
+			beadID = fields.SourceIssue
+		}
+	}
+	if beadID == "" {
+		return nil, nil
+	}
+	bead, err := townBeads.Show(beadID)
+	if err != nil {
+		return nil, lookupErr(err)
+	}
+	return bead, nil
+}
+
+// lookupErr returns nil for a bead that no longer exists.
+func lookupErr(err error) error {
+	if errors.Is(err, beads.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// importedKeys returns the beads already imported from a tracker, by
+// issue key, where keyOf reads the key from a bead's labels.
+func importedKeys(b *beads.Beads, keyOf func(labels []string) string) (map[string]string, error) {
+	existing, err := b.List(beads.ListOptions{Status: "all", Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing beads: %w", err)
+	}
+	imported := make(map[string]string)
+	for _, issue := range existing {
+		if key := keyOf(issue.Labels); key != "" {
+			imported[key] = issue.ID
+		}
+	}
+	return imported, nil
+}
+
+// createTrackedBead creates a bead for an imported issue and labels it
+// with the issue's key (see importedKeys).
+func createTrackedBead(b *beads.Beads, opts beads.CreateOptions, label string) (*beads.Issue, error) {
+	bead, err := b.Create(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.Update(bead.ID, beads.UpdateOptions{AddLabels: []string{label}}); err != nil {
+		return nil, fmt.Errorf("labeling %s: %w", bead.ID, err)
+	}
+	return bead, nil
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/witness"
@@ -82,6 +83,9 @@ func runUp(cmd *cobra.Command, args []string) error {
 
 	allOK := true
 
+	// Register rigs added to the town directory by hand or by a sync
+	autoDiscoverRigs(townRoot)
+
 	// Discover rigs early so we can prefetch while daemon/deacon/mayor start
 	rigs := discoverRigs(townRoot)
 
@@ -188,7 +192,7 @@ func runUp(cmd *cobra.Command, args []string) error {
 		for _, rigName := range rigs {
 			crewStarted, crewErrors := startCrewFromSettings(townRoot, rigName)
 			for _, name := range crewStarted {
-				printStatus(fmt.Sprintf("Crew (%s/%s)", rigName, name), true, fmt.Sprintf("gt-%s-crew-%s", rigName, name))
+				printStatus(fmt.Sprintf("Crew (%s/%s)", rigName, name), true, session.CrewSessionName(rigName, name))
 			}
 			for name, err := range crewErrors {
 				printStatus(fmt.Sprintf("Crew (%s/%s)", rigName, name), false, err.Error())
@@ -200,7 +204,7 @@ func runUp(cmd *cobra.Command, args []string) error {
 		for _, rigName := range rigs {
 			polecatsStarted, polecatErrors := startPolecatsWithWork(townRoot, rigName)
 			for _, name := range polecatsStarted {
-				printStatus(fmt.Sprintf("Polecat (%s/%s)", rigName, name), true, fmt.Sprintf("gt-%s-polecat-%s", rigName, name))
+				printStatus(fmt.Sprintf("Polecat (%s/%s)", rigName, name), true, session.PolecatSessionName(rigName, name))
 			}
 			for name, err := range polecatErrors {
 				printStatus(fmt.Sprintf("Polecat (%s/%s)", rigName, name), false, err.Error())
@@ -678,14 +682,22 @@ func startPolecatsWithWork(townRoot, rigName string) ([]string, map[string]error
 			continue
 		}
 
-		// This polecat has work - start it using SessionManager
-		if err := polecatMgr.Start(polecatName, polecat.SessionStartOptions{}); err != nil {
+		// This polecat has work - start it using SessionManager, on the
+		// account it last ran on
+		opts := polecat.SessionStartOptions{Resume: true}
+		acct, err := polecatMgr.ResumeAccount(polecatName, "", &opts)
+		if err != nil {
+			errors[polecatName] = fmt.Errorf("resolving account: %w", err)
+			continue
+		}
+		if err := polecatMgr.Start(polecatName, opts); err != nil {
 			if err == polecat.ErrSessionRunning {
 				started = append(started, polecatName)
 			} else {
 				errors[polecatName] = err
 			}
 		} else {
+			_ = acct.Record(townRoot, polecatMgr.SessionName(polecatName))
 			started = append(started, polecatName)
 		}
 	}
@@ -151,9 +151,9 @@ func runWorktree(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create the worktree on main branch
-	// Use WorktreeAddExistingForce because main may already be checked out
-	// in other worktrees (e.g., mayor/rig). This is safe for cross-rig work.
-	if err := g.WorktreeAddExistingForce(worktreePath, "main"); err != nil {
+	// Force because main may already be checked out in other worktrees
+	// (e.g., mayor/rig). This is safe for cross-rig work.
+	if err := g.AddWorktree(worktreePath, git.AddWorktreeOptions{Branch: "main", Force: true}); err != nil {
 		return fmt.Errorf("creating worktree: %w", err)
 	}
 
@@ -330,7 +330,7 @@ func runWorktreeRemove(cmd *cobra.Command, args []string) error {
 	g := git.NewGit(targetMayorRig)
 
 	// Remove the worktree
-	if err := g.WorktreeRemove(worktreePath, worktreeRemoveForce); err != nil {
+	if err := g.RemoveWorktree(worktreePath, worktreeRemoveForce); err != nil {
 		return fmt.Errorf("removing worktree: %w", err)
 	}
 
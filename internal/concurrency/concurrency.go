@@ -0,0 +1,185 @@
+// Package concurrency enforces the town's limits on concurrent agent sessions.
+//
+// Limits are configured in the town settings (settings/config.json):
+//
+//	"concurrency": {
+//	  "max_sessions": 20,
+//	  "roles": {"polecat": 12, "crew": 6},
+//	  "rigs": {"gastown": 8}
+//	}
+//
+// Every session start asks for admission against the sessions that are
+// running right now, so the limits hold regardless of which command started
+// the session. See config.ConcurrencyConfig for the fairness rules.
+package concurrency
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// ErrLimitReached indicates a session start was refused by a concurrency limit.
+var ErrLimitReached = errors.New("concurrency limit reached")
+
+// Session is a running (or requested) agent session.
+type Session struct {
+	Role string
+	Rig  string
+}
+
+// IsWorker reports whether the session belongs to the shared worker pool.
+func (s Session) IsWorker() bool {
+	return s.Role == constants.RolePolecat || s.Role == constants.RoleCrew
+}
+
+// Usage summarizes the running sessions for display.
+type Usage struct {
+	Workers int            `json:"workers"`
+	ByRole  map[string]int `json:"by_role"`
+	ByRig   map[string]int `json:"by_rig"` // worker sessions per rig
+}
+
+// Count tallies running sessions.
+func Count(running []Session) Usage {
+	u := Usage{ByRole: make(map[string]int), ByRig: make(map[string]int)}
+	for _, s := range running {
+		u.ByRole[s.Role]++
+		if s.IsWorker() {
+			u.Workers++
+			u.ByRig[s.Rig]++
+		}
+	}
+	return u
+}
+
+// FairShare returns each rig's fair share of the worker pool.
+// Every rig is entitled to at least one worker.
+func FairShare(maxSessions int, rigs []string) int {
+	if len(rigs) == 0 {
+		return maxSessions
+	}
+	share := maxSessions / len(rigs)
+	if share < 1 {
+		share = 1
+	}
+	return share
+}
+
+// Admit decides whether a new session may start given the running sessions.
+// rigs lists every rig in the town; it determines each rig's fair share.
+// A nil config admits everything.
+func Admit(cfg *config.ConcurrencyConfig, rigs []string, running []Session, want Session) error {
+	if cfg == nil {
+		return nil
+	}
+	u := Count(running)
+
+	if limit := cfg.Roles[want.Role]; limit > 0 && u.ByRole[want.Role] >= limit {
+		return fmt.Errorf("%w: %d/%d %s sessions running", ErrLimitReached, u.ByRole[want.Role], limit, want.Role)
+	}
+
+	if !want.IsWorker() {
+		return nil
+	}
+
+	if limit := cfg.Rigs[want.Rig]; limit > 0 && u.ByRig[want.Rig] >= limit {
+		return fmt.Errorf("%w: %d/%d worker sessions running in rig %s", ErrLimitReached, u.ByRig[want.Rig], limit, want.Rig)
+	}
+
+	if cfg.MaxSessions <= 0 {
+		return nil
+	}
+	free := cfg.MaxSessions - u.Workers
+	if free <= 0 {
+		return fmt.Errorf("%w: %d/%d worker sessions running town-wide", ErrLimitReached, u.Workers, cfg.MaxSessions)
+	}
+
+	// Fair share: a rig already at its share may not take the slots that
+	// would let a rig below its share start a worker.
+	share := FairShare(cfg.MaxSessions, rigs)
+	if u.ByRig[want.Rig] < share {
+		return nil
+	}
+	reserved := 0
+	for _, rig := range rigs {
+		if rig != want.Rig && u.ByRig[rig] < share && !rigFull(cfg, u, rig) {
+			reserved++
+		}
+	}
+	if free <= reserved {
+		return fmt.Errorf("%w: rig %s is at its fair share (%d workers); %d remaining slots are reserved for other rigs",
+			ErrLimitReached, want.Rig, share, free)
+	}
+	return nil
+}
+
+// rigFull reports whether a rig is at its own cap and so needs no reserved slot.
+func rigFull(cfg *config.ConcurrencyConfig, u Usage, rig string) bool {
+	limit := cfg.Rigs[rig]
+	return limit > 0 && u.ByRig[rig] >= limit
+}
+
+// Running lists the agent sessions running in tmux.
+// Sessions whose names are not Gas Town agent sessions are ignored.
+func Running(t *tmux.Tmux) ([]Session, error) {
+	names, err := t.ListSessions()
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+	var running []Session
+	for _, name := range names {
+		id, err := session.ParseSessionName(name)
+		if err != nil {
+			continue
+		}
+		running = append(running, Session{Role: string(id.Role), Rig: id.Rig})
+	}
+	return running, nil
+}
+
+// Limits loads the town's concurrency config and rig names. Limits from
+// gastown.toml and GT_MAX_SESSIONS are layered over settings/config.json.
+// Returns a nil config when no limits are configured.
+func Limits(townRoot string) (*config.ConcurrencyConfig, []string, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	gtConfig, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+	limits := gtConfig.Concurrency.MergedOver(settings.Concurrency)
+	if limits == nil {
+		return nil, nil, nil
+	}
+	var rigs []string
+	if rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot)); err == nil {
+		for name := range rigsConfig.Rigs {
+			rigs = append(rigs, name)
+		}
+		sort.Strings(rigs)
+	}
+	return limits, rigs, nil
+}
+
+// Check admits a new session for role in rig against the town's limits and
+// the sessions currently running in tmux. It is a no-op when no limits are
+// configured.
+func Check(townRoot string, t *tmux.Tmux, role, rig string) error {
+	cfg, rigs, err := Limits(townRoot)
+	if err != nil || cfg == nil {
+		return err
+	}
+	running, err := Running(t)
+	if err != nil {
+		return err
+	}
+	return Admit(cfg, rigs, running, Session{Role: role, Rig: rig})
+}
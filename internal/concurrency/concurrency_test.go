@@ -0,0 +1,101 @@
+package concurrency
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func sessions(role, rig string, n int) []Session {
+	out := make([]Session, n)
+	for i := range out {
+		out[i] = Session{Role: role, Rig: rig}
+	}
+	return out
+}
+
+func TestAdmit(t *testing.T) {
+	rigs := []string{"alpha", "beta", "gamma"}
+	var storm []Session
+	storm = append(storm, sessions("polecat", "alpha", 7)...)
+	storm = append(storm, sessions("witness", "alpha", 1)...)
+	storm = append(storm, sessions("witness", "beta", 1)...)
+	withBeta := append(append([]Session{}, storm...), Session{"crew", "beta"})
+
+	tests := []struct {
+		name    string
+		cfg     *config.ConcurrencyConfig
+		running []Session
+		want    Session
+		admit   bool
+	}{
+		{"no config", nil, storm, Session{"polecat", "alpha"}, true},
+		{"role cap", &config.ConcurrencyConfig{Roles: map[string]int{"polecat": 7}}, storm, Session{"polecat", "beta"}, false},
+		{"role cap other role", &config.ConcurrencyConfig{Roles: map[string]int{"polecat": 7}}, storm, Session{"crew", "beta"}, true},
+		{"rig cap", &config.ConcurrencyConfig{Rigs: map[string]int{"alpha": 7}}, storm, Session{"polecat", "alpha"}, false},
+		{"rig cap other rig", &config.ConcurrencyConfig{Rigs: map[string]int{"alpha": 7}}, storm, Session{"polecat", "beta"}, true},
+		{"pool full", &config.ConcurrencyConfig{MaxSessions: 7}, storm, Session{"polecat", "beta"}, false},
+		// share = 10/3 = 3; alpha holds 7, so 2 of the 3 free slots are held for beta and gamma
+		{"over share with spare slot", &config.ConcurrencyConfig{MaxSessions: 10}, storm, Session{"polecat", "alpha"}, true},
+		{"over share into reserve", &config.ConcurrencyConfig{MaxSessions: 9}, storm, Session{"polecat", "alpha"}, false},
+		{"under share uses reserve", &config.ConcurrencyConfig{MaxSessions: 9}, storm, Session{"crew", "beta"}, true},
+		{"reserve held for beta", &config.ConcurrencyConfig{MaxSessions: 10}, withBeta, Session{"polecat", "alpha"}, false},
+		{"capped rig needs no reserve", &config.ConcurrencyConfig{MaxSessions: 10, Rigs: map[string]int{"beta": 1}}, withBeta, Session{"polecat", "alpha"}, true},
+		// Infrastructure never competes with the worker pool
+		{"witness exempt from pool", &config.ConcurrencyConfig{MaxSessions: 7, Rigs: map[string]int{"gamma": 1}}, storm, Session{"witness", "gamma"}, true},
+		{"witness role cap", &config.ConcurrencyConfig{Roles: map[string]int{"witness": 2}}, storm, Session{"witness", "gamma"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Admit(tt.cfg, rigs, tt.running, tt.want)
+			if tt.admit && err != nil {
+				t.Errorf("Admit() = %v, want admitted", err)
+			}
+			if !tt.admit && !errors.Is(err, ErrLimitReached) {
+				t.Errorf("Admit() = %v, want ErrLimitReached", err)
+			}
+		})
+	}
+}
+
+func TestFairShare(t *testing.T) {
+	if got := FairShare(10, []string{"a", "b", "c"}); got != 3 {
+		t.Errorf("FairShare(10, 3 rigs) = %d, want 3", got)
+	}
+	if got := FairShare(2, []string{"a", "b", "c"}); got != 1 {
+		t.Errorf("FairShare(2, 3 rigs) = %d, want 1", got)
+	}
+	if got := FairShare(5, nil); got != 5 {
+		t.Errorf("FairShare(5, no rigs) = %d, want 5", got)
+	}
+}
+
+// An unresolvable federation URL must only affect federation, not the
+// limits and agent choice that other commands read from the same file.
+func TestLimits_UnresolvedFederationRef(t *testing.T) {
+	townRoot := t.TempDir()
+	settings := `{"type":"town-settings","version":1,"default_agent":"codex",
+		"concurrency":{"max_sessions":4},
+		"federation":{"towns":{"lab":{"url":"${GT_TEST_UNSET_LAB_URL}"}}}}`
+	path := config.TownSettingsPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	limits, _, err := Limits(townRoot)
+	if err != nil {
+		t.Fatalf("Limits: %v", err)
+	}
+	if limits == nil || limits.MaxSessions != 4 {
+		t.Errorf("limits = %+v, want max_sessions 4", limits)
+	}
+	if rc := config.ResolveAgentConfig(townRoot, filepath.Join(townRoot, "rig")); rc.Command != "codex" {
+		t.Errorf("agent command = %q, want the town's default_agent codex", rc.Command)
+	}
+}
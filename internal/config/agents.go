@@ -69,6 +69,20 @@ type AgentPresetInfo struct {
 
 	// NonInteractive contains settings for non-interactive mode.
 	NonInteractive *NonInteractiveConfig `json:"non_interactive,omitempty"`
+
+	// AllowedToolsFlag and DisallowedToolsFlag are the flags that grant or
+	// deny tools at startup (e.g., "--allowedTools" for claude). Empty if
+	// the agent has no tool flags.
+	AllowedToolsFlag    string `json:"allowed_tools_flag,omitempty"`
+	DisallowedToolsFlag string `json:"disallowed_tools_flag,omitempty"`
+
+	// ModelFlag is the flag that selects the model (e.g., "--model"). Empty
+	// if the agent cannot choose a model at startup.
+	ModelFlag string `json:"model_flag,omitempty"`
+
+	// MaxTokensEnv is the environment variable that caps output tokens per
+	// response (e.g., "CLAUDE_CODE_MAX_OUTPUT_TOKENS" for claude).
+	MaxTokensEnv string `json:"max_tokens_env,omitempty"`
 }
 
 // NonInteractiveConfig contains settings for running agents non-interactively.
@@ -109,6 +123,10 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		SupportsHooks:       true,
 		SupportsForkSession: true,
 		NonInteractive:      nil, // Claude is native non-interactive
+		AllowedToolsFlag:    "--allowedTools",
+		DisallowedToolsFlag: "--disallowedTools",
+		ModelFlag:           "--model",
+		MaxTokensEnv:        "CLAUDE_CODE_MAX_OUTPUT_TOKENS",
 	},
 	AgentGemini: {
 		Name:                AgentGemini,
@@ -120,6 +138,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		ResumeStyle:         "flag",
 		SupportsHooks:       true,
 		SupportsForkSession: false,
+		ModelFlag:           "--model",
 		NonInteractive: &NonInteractiveConfig{
 			PromptFlag: "-p",
 			OutputFlag: "--output-format json",
@@ -135,6 +154,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		ResumeStyle:         "subcommand",
 		SupportsHooks:       false, // Use env/files instead
 		SupportsForkSession: false,
+		ModelFlag:           "--model",
 		NonInteractive: &NonInteractiveConfig{
 			Subcommand: "exec",
 			OutputFlag: "--json",
@@ -150,6 +170,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		ResumeStyle:         "flag",
 		SupportsHooks:       false, // TODO: verify hooks support
 		SupportsForkSession: false,
+		ModelFlag:           "--model",
 		NonInteractive: &NonInteractiveConfig{
 			PromptFlag: "-p",
 			OutputFlag: "--output-format json",
@@ -363,6 +384,59 @@ func BuildResumeCommand(agentName, sessionID string) string {
 	}
 }
 
+// BuildNonInteractiveArgs builds the argv to run an agent headless with a single prompt.
+// The process runs to completion and exits (e.g., "claude --dangerously-skip-permissions -p <prompt>").
+// Returns nil if the agent is unknown or has no non-interactive mode.
+func BuildNonInteractiveArgs(agentName, prompt string) []string {
+	info := GetAgentPresetByName(agentName)
+	if info == nil {
+		return nil
+	}
+
+	ni := info.NonInteractive
+	if ni == nil {
+		// Claude is native non-interactive via -p
+		if info.Name != AgentClaude {
+			return nil
+		}
+		ni = &NonInteractiveConfig{PromptFlag: "-p"}
+	}
+
+	argv := []string{info.Command}
+	if ni.Subcommand != "" {
+		argv = append(argv, ni.Subcommand)
+	}
+	argv = append(argv, info.Args...)
+	if ni.PromptFlag != "" {
+		argv = append(argv, ni.PromptFlag)
+	}
+	return append(argv, prompt)
+}
+
+// BuildToolGrantFlags returns the shell-quoted startup flags that grant the
+// allowed tools and deny the disallowed ones (e.g.,
+// `--disallowedTools "WebFetch"`). Returns "" if there is nothing to grant or
+// the agent has no flag for it.
+func BuildToolGrantFlags(agentName string, allowed, disallowed []string) string {
+	info := GetAgentPresetByName(agentName)
+	if info == nil {
+		return ""
+	}
+	var parts []string
+	add := func(flag string, tools []string) {
+		if flag == "" || len(tools) == 0 {
+			return
+		}
+		parts = append(parts, flag)
+		for _, tool := range tools {
+			parts = append(parts, quoteForShell(tool))
+		}
+	}
+	add(info.AllowedToolsFlag, allowed)
+	add(info.DisallowedToolsFlag, disallowed)
+	return strings.Join(parts, " ")
+}
+
 // SupportsSessionResume checks if an agent supports session resumption.
 func SupportsSessionResume(agentName string) bool {
 	info := GetAgentPresetByName(agentName)
@@ -629,3 +629,26 @@ func TestLoadRigAgentRegistry(t *testing.T) {
 		}
 	})
 }
+
+func TestBuildNonInteractiveArgs(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		agentName string
+		want      []string
+	}{
+		{"claude", []string{"claude", "--dangerously-skip-permissions", "-p", "fix it"}},
+		{"gemini", []string{"gemini", "--approval-mode", "yolo", "-p", "fix it"}},
+		{"codex", []string{"codex", "exec", "--yolo", "fix it"}},
+		{"auggie", nil},
+		{"unknown-agent", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.agentName, func(t *testing.T) {
+			got := BuildNonInteractiveArgs(tt.agentName, "fix it")
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") || (got == nil) != (tt.want == nil) {
+				t.Errorf("BuildNonInteractiveArgs(%q) = %v, want %v", tt.agentName, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// EffectiveValue is one resolved setting and where its value came from.
+type EffectiveValue struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// townRoles are the roles that run at town level; rigRoles run in a rig.
+var (
+	townRoles = []string{constants.RoleMayor, constants.RoleDeacon}
+	rigRoles  = []string{constants.RoleWitness, constants.RoleRefinery, constants.RolePolecat, constants.RoleCrew}
+)
+
+// EffectiveConfig returns the resolved operator settings for a town, or for
+// a rig's sessions if rigPath is set, with the source of each value: the
+// gastown.toml keys, then the agent and model each role starts with. File
+// sources are shown relative to townRoot.
+func EffectiveConfig(townRoot, rigPath string) ([]EffectiveValue, error) {
+	cfg, err := LoadRigGastownConfig(townRoot, rigPath)
+	if err != nil {
+		return nil, err
+	}
+	rel := func(src string) string {
+		if r, err := filepath.Rel(townRoot, src); err == nil && filepath.IsAbs(src) && !strings.HasPrefix(r, "..") {
+			return r
+		}
+		return src
+	}
+	var out []EffectiveValue
+	add := func(key, value, source string) {
+		out = append(out, EffectiveValue{Key: key, Value: value, Source: rel(source)})
+	}
+	fromToml := func(key, value string) {
+		add(key, value, cfg.Source(key))
+	}
+
+	fromToml("runtime", cfg.Runtime)
+	fromToml("model", cfg.Model)
+	fromToml("max_tokens", intOrEmpty(cfg.MaxTokens))
+	if rigPath == "" {
+		maxSessions := ""
+		if cfg.Concurrency != nil {
+			maxSessions = intOrEmpty(cfg.Concurrency.MaxSessions)
+		}
+		fromToml("concurrency.max_sessions", maxSessions)
+		fromToml("server.bind", cfg.Server.Bind)
+		fromToml("server.port", strconv.Itoa(cfg.Server.Port))
+		retries := ""
+		if cfg.Git.Retries != nil {
+			retries = strconv.Itoa(*cfg.Git.Retries)
+		}
+		fromToml("git.retries", retries)
+		fromToml("git.retry_backoff", cfg.Git.RetryBackoff)
+		fromToml("log.level", cfg.Log.Level)
+		fromToml("log.format", cfg.Log.Format)
+		fromToml("tracing.endpoint", cfg.Tracing.Endpoint)
+		fromToml("events.retention_days", intOrEmpty(cfg.Events.RetentionDays))
+		fromToml("events.record_prompts", strconv.FormatBool(cfg.Events.RecordPrompts))
+		fromToml("events.chain", strconv.FormatBool(cfg.Events.Chained()))
+		fromToml("events.signing_key", cfg.Events.SigningKey)
+		fromToml("redact.patterns", strings.Join(cfg.Redact.Patterns, ", "))
+		fromToml("policy.profile", cfg.Policy.Profile)
+		fromToml("policy.paths", strings.Join(cfg.Policy.Paths, ", "))
+		fromToml("policy.allow_commands", strings.Join(cfg.Policy.AllowCommands, ", "))
+		fromToml("policy.deny_commands", strings.Join(cfg.Policy.DenyCommands, ", "))
+		fromToml("policy.allow_hosts", strings.Join(cfg.Policy.AllowHosts, ", "))
+		fromToml("policy.deny_hosts", strings.Join(cfg.Policy.DenyHosts, ", "))
+		repeatCalls, repeatPrompts, burnFactor := cfg.Loops.Thresholds()
+		fromToml("loops.repeat_calls", strconv.Itoa(repeatCalls))
+		fromToml("loops.repeat_prompts", strconv.Itoa(repeatPrompts))
+		fromToml("loops.burn_factor", strconv.Itoa(burnFactor))
+		fromToml("loops.baseline_tokens_per_minute", intOrEmpty(cfg.Loops.BaselineTokensPerMinute))
+		hourlyUSD, spikeFactor, minSpikeUSD, baselineHours := cfg.CostAlerts.Thresholds()
+		fromToml("cost_alerts.hourly_usd", fmt.Sprintf("%.2f", hourlyUSD))
+		fromToml("cost_alerts.spike_factor", strconv.FormatFloat(spikeFactor, 'g', -1, 64))
+		fromToml("cost_alerts.min_spike_usd", fmt.Sprintf("%.2f", minSpikeUSD))
+		fromToml("cost_alerts.baseline_hours", strconv.Itoa(baselineHours))
+		fromToml("cost_alerts.webhook_url", maskWebhook(cfg.CostAlerts.WebhookURL))
+		fromToml("cost_alerts.slack_webhook", maskWebhook(cfg.CostAlerts.SlackWebhook))
+		fromToml("outbound.pii", strconv.FormatBool(cfg.Outbound.PII))
+		fromToml("outbound.patterns", strings.Join(cfg.Outbound.Patterns, ", "))
+		denied := ""
+		if n := len(cfg.Outbound.Deny); n > 0 {
+			denied = fmt.Sprintf("(%d terms)", n) // the terms themselves are sensitive
+		}
+		fromToml("outbound.deny", denied)
+		fromToml("outbound.mode", cfg.Outbound.Action())
+		fromToml("outbound.hash_key", cfg.Outbound.HashKey)
+		fromToml("slack.bot_token", cfg.Slack.BotToken)
+		fromToml("slack.webhook_url", maskWebhook(cfg.Slack.WebhookURL))
+		fromToml("slack.signing_secret", cfg.Slack.SigningSecret)
+		fromToml("slack.channel", cfg.Slack.Channel)
+		fromToml("slack.events", strings.Join(cfg.Slack.Events, ", "))
+		fromToml("slack.operators", strings.Join(cfg.Slack.Operators, ", "))
+		fromToml("discord.bot_token", cfg.Discord.BotToken)
+		fromToml("discord.webhook_url", maskWebhook(cfg.Discord.WebhookURL))
+		fromToml("discord.application_id", cfg.Discord.ApplicationID)
+		fromToml("discord.public_key", cfg.Discord.PublicKey)
+		fromToml("discord.channel", cfg.Discord.Channel)
+		fromToml("discord.events", strings.Join(cfg.Discord.Events, ", "))
+		fromToml("discord.operators", strings.Join(cfg.Discord.Operators, ", "))
+		fromToml("email.smtp_host", cfg.Email.SMTPHost)
+		fromToml("email.smtp_port", strconv.Itoa(cfg.Email.Port()))
+		fromToml("email.username", cfg.Email.Username)
+		fromToml("email.password", cfg.Email.Password)
+		fromToml("email.from", cfg.Email.From)
+		fromToml("email.to", strings.Join(cfg.Email.To, ", "))
+		fromToml("email.events", strings.Join(cfg.Email.Events, ", "))
+		fromToml("email.digest", cfg.Email.Digest)
+		fromToml("jira.url", cfg.Jira.URL)
+		fromToml("jira.user", cfg.Jira.User)
+		fromToml("jira.token", cfg.Jira.Token)
+		fromToml("jira.comments", strconv.FormatBool(cfg.Jira.Comments))
+		fromToml("linear.api_key", cfg.Linear.APIKey)
+		fromToml("linear.webhook_secret", cfg.Linear.WebhookSecret)
+		fromToml("alerting.pagerduty.routing_key", cfg.Alerting.PagerDuty.RoutingKey)
+		fromToml("alerting.pagerduty.min_severity", cfg.Alerting.PagerDuty.MinSeverity)
+		fromToml("alerting.opsgenie.api_key", cfg.Alerting.Opsgenie.APIKey)
+		fromToml("alerting.opsgenie.url", cfg.Alerting.Opsgenie.URL)
+		fromToml("alerting.opsgenie.min_severity", cfg.Alerting.Opsgenie.MinSeverity)
+		fromToml("alerting.wedged_after", cfg.Alerting.WedgedAfterDuration().String())
+	}
+	daily, weekly, dailyTokens, weeklyTokens := "", "", "", ""
+	if cfg.Budget != nil {
+		daily = fmt.Sprintf("%.2f", cfg.Budget.DailyUSD)
+		weekly = fmt.Sprintf("%.2f", cfg.Budget.WeeklyUSD)
+		dailyTokens = strconv.Itoa(cfg.Budget.DailyTokens)
+		weeklyTokens = strconv.Itoa(cfg.Budget.WeeklyTokens)
+	}
+	fromToml("budget.daily_usd", daily)
+	fromToml("budget.weekly_usd", weekly)
+	fromToml("budget.daily_tokens", dailyTokens)
+	fromToml("budget.weekly_tokens", weeklyTokens)
+	fromToml("activity.active", cfg.Activity.Active)
+	fromToml("activity.stale", cfg.Activity.Stale)
+	if cfg.MergeQueue != nil {
+		names := make([]string, 0, len(cfg.MergeQueue.Checks))
+		for _, c := range cfg.MergeQueue.Checks {
+			names = append(names, c.Name)
+		}
+		fromToml("merge_queue.checks", strings.Join(names, ", "))
+	}
+	prompts := make([]string, 0, len(cfg.Prompts))
+	for role := range cfg.Prompts {
+		prompts = append(prompts, role)
+	}
+	sort.Strings(prompts)
+	for _, role := range prompts {
+		fromToml("prompts."+role, fmt.Sprintf("%d chars", len(cfg.Prompts[role])))
+	}
+
+	roles := townRoles
+	if rigPath != "" {
+		roles = rigRoles
+	}
+	for _, role := range roles {
+		agent, source := explainRoleAgent(role, townRoot, rigPath, cfg)
+		add("agent."+role, agent, source)
+		model, source := explainModel(role, agent, townRoot, rigPath, cfg)
+		add("model."+role, model, source)
+	}
+	return out, nil
+}
+
+// RoleAgentModel returns the agent a role's sessions start with, its model
+// ("" if left to the agent), and the output token cap (0 for the agent's
+// default), as EffectiveConfig reports them.
+func RoleAgentModel(role, townRoot, rigPath string) (agent, model string, maxTokens int) {
+	cfg, err := LoadRigGastownConfig(townRoot, rigPath)
+	if err != nil {
+		cfg = &GastownConfig{}
+	}
+	agent, _ = explainRoleAgent(role, townRoot, rigPath, cfg)
+	model, _ = explainModel(role, agent, townRoot, rigPath, cfg)
+	return agent, model, cfg.MaxTokens
+}
+
+// explainRoleAgent returns the agent a role starts with and the setting that
+// chose it, following ResolveRoleAgentConfig's precedence.
+func explainRoleAgent(role, townRoot, rigPath string, cfg *GastownConfig) (agent, source string) {
+	var rigSettings *RigSettings
+	rigSettingsPath := ""
+	if rigPath != "" {
+		rigSettingsPath = RigSettingsPath(rigPath)
+		rigSettings, _ = LoadRigSettings(rigSettingsPath)
+	}
+	townSettingsPath := TownSettingsPath(townRoot)
+	townSettings, err := LoadOrCreateTownSettings(townSettingsPath)
+	if err != nil {
+		townSettings = NewTownSettings()
+	}
+
+	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
+	if rigPath != "" {
+		_ = LoadRigAgentRegistry(RigAgentRegistryPath(rigPath))
+	}
+
+	// Role agents that fail validation fall back, as at session start
+	if rigSettings != nil && rigSettings.RoleAgents[role] != "" {
+		if name := rigSettings.RoleAgents[role]; ValidateAgentConfig(name, townSettings, rigSettings) == nil {
+			return name, rigSettingsPath + " role_agents." + role
+		}
+	}
+	if name := townSettings.RoleAgents[role]; name != "" && ValidateAgentConfig(name, townSettings, rigSettings) == nil {
+		return name, townSettingsPath + " role_agents." + role
+	}
+	switch {
+	case rigSettings != nil && rigSettings.Runtime != nil:
+		return filepath.Base(rigSettings.Runtime.Command), rigSettingsPath + " runtime (deprecated)"
+	case rigSettings != nil && rigSettings.Agent != "":
+		return rigSettings.Agent, rigSettingsPath + " agent"
+	case cfg.Runtime != "":
+		return cfg.Runtime, cfg.Source("runtime")
+	case townSettings.DefaultAgent != "":
+		if _, err := os.Stat(townSettingsPath); err != nil {
+			return townSettings.DefaultAgent, "default"
+		}
+		return townSettings.DefaultAgent, townSettingsPath + " default_agent"
+	}
+	return "claude", "default"
+}
+
+// explainModel returns the model a role's agent starts with and where it was
+// chosen: the agent's own args, the operator model, or the agent default
+// (shown as an empty value).
+func explainModel(role, agent, townRoot, rigPath string, cfg *GastownConfig) (model, source string) {
+	rc := ResolveRoleAgentConfig(role, townRoot, rigPath)
+	info := GetAgentPresetByName(agent)
+	if info == nil && rc != nil {
+		info = GetAgentPresetByName(filepath.Base(rc.Command))
+	}
+	if info == nil || info.ModelFlag == "" {
+		return "", "agent " + agent + " has no model flag"
+	}
+	if rc != nil {
+		if v, ok := argValue(rc.Args, info.ModelFlag); ok {
+			return v, "agent " + agent + " args"
+		}
+	}
+	if cfg.Model != "" {
+		return cfg.Model, cfg.Source("model")
+	}
+	return "", "agent default"
+}
+
+// argValue returns the value of flag in args, given as "flag value" or
+// "flag=value".
+func argValue(args []string, flag string) (string, bool) {
+	for i, a := range args {
+		if a == flag && i+1 < len(args) {
+			return args[i+1], true
+		}
+		if strings.HasPrefix(a, flag+"=") {
+			return strings.TrimPrefix(a, flag+"="), true
+		}
+	}
+	return "", false
+}
+
+// intOrEmpty formats n, or "" for zero.
+func intOrEmpty(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// maskWebhook hides a webhook URL, which carries its own credential, unless
+// it is a ${VAR} or secret:// reference.
+func maskWebhook(url string) string {
+	if url == "" || HasRefs(url) {
+		return url
+	}
+	return "(set)"
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+func effectiveByKey(t *testing.T, townRoot, rigPath string) map[string]EffectiveValue {
+	t.Helper()
+	values, err := EffectiveConfig(townRoot, rigPath)
+	if err != nil {
+		t.Fatalf("EffectiveConfig: %v", err)
+	}
+	m := make(map[string]EffectiveValue, len(values))
+	for _, v := range values {
+		m[v.Key] = v
+	}
+	return m
+}
+
+func TestEffectiveConfig_Sources(t *testing.T) {
+	townRoot := setupGastownConfig(t, "max_tokens = 8000\n", `
+model = "town-model"
+
+[budget]
+daily_usd = 20
+`)
+	t.Setenv(EnvServerPort, "9090")
+	stubAgentBinary(t, "claude")
+
+	got := effectiveByKey(t, townRoot, "")
+	tests := []struct {
+		key, value, source string
+	}{
+		{"model", "town-model", GastownConfigFile},
+		{"max_tokens", "8000", UserGastownConfigPath()},
+		{"server.port", "9090", "$" + EnvServerPort},
+		{"budget.daily_usd", "20.00", GastownConfigFile},
+		{"budget.weekly_usd", "0.00", GastownConfigFile},
+		{"runtime", "", "default"},
+		{"agent.mayor", "claude", "default"},
+		{"model.mayor", "town-model", GastownConfigFile},
+	}
+	for _, tt := range tests {
+		v := got[tt.key]
+		if v.Value != tt.value || v.Source != tt.source {
+			t.Errorf("%s = %q from %q, want %q from %q", tt.key, v.Value, v.Source, tt.value, tt.source)
+		}
+	}
+}
+
+func TestEffectiveConfig_RigAgentAndModel(t *testing.T) {
+	townRoot := setupGastownConfig(t, "", "model = \"town-model\"\n")
+	stubAgentBinary(t, "claude")
+	rigPath := filepath.Join(townRoot, "gastown")
+	writeTestFile(t, RigSettingsPath(rigPath), `{"type":"rig-settings","version":1,
+		"role_agents":{"polecat":"pinned"},
+		"agents":{"pinned":{"command":"claude","args":["--model","haiku"]}}}`)
+	writeTestFile(t, RigGastownConfigPath(rigPath), "runtime = \"claude\"\n")
+
+	got := effectiveByKey(t, townRoot, rigPath)
+	settingsSrc := filepath.Join("gastown", "settings", "config.json")
+	tests := []struct {
+		key, value, source string
+	}{
+		{"agent." + constants.RolePolecat, "pinned", settingsSrc + " role_agents.polecat"},
+		{"model." + constants.RolePolecat, "haiku", "agent pinned args"},
+		{"agent." + constants.RoleWitness, "claude", filepath.Join("gastown", GastownConfigFile)},
+		{"model." + constants.RoleWitness, "town-model", GastownConfigFile},
+	}
+	for _, tt := range tests {
+		v := got[tt.key]
+		if v.Value != tt.value || v.Source != tt.source {
+			t.Errorf("%s = %q from %q, want %q from %q", tt.key, v.Value, v.Source, tt.value, tt.source)
+		}
+	}
+	if _, ok := got["server.port"]; ok {
+		t.Error("rig view should not include town-wide server settings")
+	}
+}
@@ -0,0 +1,1721 @@
+package config
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/logging"
+	"github.com/steveyegge/gastown/internal/secret"
+)
+
+// GastownConfigFile is the town's operator config file, at the town root.
+const GastownConfigFile = "gastown.toml"
+
+// Default server settings for 'gt dashboard'.
+const (
+	DefaultServerBind = ""
+	DefaultServerPort = 8080
+)
+
+// Environment variables that override the config files.
+const (
+	EnvRuntime         = "GT_RUNTIME"
+	EnvModel           = "GT_MODEL"
+	EnvMaxTokens       = "GT_MAX_TOKENS"
+	EnvMaxSessions     = "GT_MAX_SESSIONS"
+	EnvServerBind      = "GT_SERVER_BIND"
+	EnvServerPort      = "GT_SERVER_PORT"
+	EnvBudgetDailyUSD  = "GT_BUDGET_DAILY_USD"
+	EnvBudgetWeeklyUSD = "GT_BUDGET_WEEKLY_USD"
+	EnvLogLevel        = "GT_LOG_LEVEL"
+	EnvLogFormat       = "GT_LOG_FORMAT"
+)
+
+// GastownConfig is the operator-level configuration read from TOML files:
+//
+//	runtime    = "claude"
+//	model      = "sonnet"
+//	max_tokens = 32000
+//
+//	[concurrency]
+//	max_sessions = 12
+//
+//	[server]
+//	bind = "127.0.0.1"
+//	port = 8080
+//
+//	[budget]
+//	daily_usd = 50
+//
+// Settings are resolved with precedence flags > env > file > defaults. The
+// user file (~/.config/gastown/config.toml) applies to every town, the town
+// file ($TOWN/gastown.toml) overrides it, and a rig file
+// ($TOWN/<rig>/gastown.toml) overrides both for that rig's sessions.
+// Command-line flags are applied by the commands that have them (e.g.
+// --agent, --port).
+type GastownConfig struct {
+	// Runtime is the default agent for all roles (e.g., "claude", "codex").
+	// It overrides default_agent in settings/config.json; rig and role
+	// agent settings still take precedence. Empty means no override.
+	Runtime string `toml:"runtime"`
+
+	// Model is passed to agents that can select a model at startup.
+	// Empty means the agent's own default.
+	Model string `toml:"model"`
+
+	// MaxTokens caps output tokens per response for agents that support it
+	// (0 = agent default).
+	MaxTokens int `toml:"max_tokens"`
+
+	// Concurrency overrides the limits in settings/config.json. A non-zero
+	// max_sessions replaces the town-wide cap; role and rig caps are merged.
+	Concurrency *ConcurrencyConfig `toml:"concurrency"`
+
+	// Server configures the HTTP server run by 'gt dashboard'.
+	Server ServerConfig `toml:"server"`
+
+	// Budget is the default budget for rigs that don't set their own.
+	Budget *BudgetConfig `toml:"budget"`
+
+	// Prompts adds instructions to a role's context, keyed by role name
+	// ("witness", "polecat", ...). 'gt prime' prints them after the role's
+	// own context at session start.
+	Prompts map[string]string `toml:"prompts"`
+
+	// Activity sets the thresholds the dashboard uses to color agent activity.
+	Activity ActivityConfig `toml:"activity"`
+
+	// MergeQueue overrides the Refinery's pre-merge checks.
+	MergeQueue *GastownMergeQueueConfig `toml:"merge_queue"`
+
+	// Git configures retries of git network operations.
+	Git GitConfig `toml:"git"`
+
+	// Log configures gt's structured logs (stderr, and the daemon's log).
+	Log LogConfig `toml:"log"`
+
+	// Tracing configures OpenTelemetry trace export.
+	Tracing TracingConfig `toml:"tracing"`
+
+	// Events configures the town's event/audit log (.events.jsonl).
+	Events EventsConfig `toml:"events"`
+
+	// Redact adds patterns for secrets to scrub from logs and captures.
+	Redact RedactConfig `toml:"redact"`
+
+	// Policy restricts the tools agents may run.
+	Policy PolicyConfig `toml:"policy"`
+
+	// Loops sets when a session is paused as a runaway loop.
+	Loops LoopsConfig `toml:"loops"`
+
+	// CostAlerts sets when a rig's hourly spend is alerted as an anomaly.
+	CostAlerts CostAlertsConfig `toml:"cost_alerts"`
+
+	// Outbound filters what gt and agents send to the model's API.
+	Outbound OutboundConfig `toml:"outbound"`
+
+	// Slack connects the town to a Slack app for notifications and control.
+	Slack SlackConfig `toml:"slack"`
+
+	// Discord connects the town to a Discord application for notifications
+	// and control.
+	Discord DiscordConfig `toml:"discord"`
+
+	// Email sends high-severity events through an SMTP server.
+	Email EmailConfig `toml:"email"`
+
+	// Jira imports issues from Jira as beads and reports work on them back.
+	Jira JiraConfig `toml:"jira"`
+
+	// Linear imports issues from Linear as beads and reports work on them
+	// back.
+	Linear LinearConfig `toml:"linear"`
+
+	// Alerting opens PagerDuty and Opsgenie incidents for critical
+	// conditions.
+	Alerting AlertingConfig `toml:"alerting"`
+
+	// Files lists the config files that were loaded, lowest precedence first.
+	Files []string `toml:"-"`
+
+	// Sources maps each key set by a file or the environment (e.g.,
+	// "server.port") to where its value came from: a file path or an
+	// environment variable such as "$GT_MODEL". Keys left at their
+	// defaults are absent.
+	Sources map[string]string `toml:"-"`
+}
+
+// Source returns where key's effective value came from, or "default".
+func (c *GastownConfig) Source(key string) string {
+	if src, ok := c.Sources[key]; ok {
+		return src
+	}
+	return "default"
+}
+
+// setSource records src as the source of key.
+func (c *GastownConfig) setSource(key, src string) {
+	if c.Sources == nil {
+		c.Sources = make(map[string]string)
+	}
+	c.Sources[key] = src
+}
+
+// ActivityConfig sets activity thresholds. Activity newer than Active is
+// shown as active, older than Stale as stuck, and in between as stale.
+type ActivityConfig struct {
+	// Active is the threshold for active work (e.g., "2m").
+	Active string `toml:"active"`
+
+	// Stale is the threshold past which an agent looks stuck (e.g., "5m").
+	Stale string `toml:"stale"`
+}
+
+// Durations returns the thresholds, zero for any that are unset.
+func (c ActivityConfig) Durations() (active, stale time.Duration) {
+	active, _ = time.ParseDuration(c.Active)
+	stale, _ = time.ParseDuration(c.Stale)
+	return active, stale
+}
+
+// GitConfig controls how clone, fetch, pull, and push retry transient
+// network failures (authentication failures are never retried).
+type GitConfig struct {
+	// Retries is the number of retries after the first attempt. Nil means
+	// the default (3); 0 disables retries.
+	Retries *int `toml:"retries"`
+
+	// RetryBackoff is the wait before the first retry, doubling after each
+	// (e.g., "2s"). Empty means the default.
+	RetryBackoff string `toml:"retry_backoff"`
+}
+
+// LogConfig sets the level and format of gt's structured logs.
+type LogConfig struct {
+	// Level is the minimum level logged: "debug", "info", "warn", or
+	// "error". Empty means "info".
+	Level string `toml:"level"`
+
+	// Format is "text" (key=value) or "json". Empty means text.
+	Format string `toml:"format"`
+}
+
+// TracingConfig configures OpenTelemetry tracing of gt commands, agent
+// sessions, prompts, and API requests.
+type TracingConfig struct {
+	// Endpoint is the OTLP/HTTP collector URL (e.g., "http://localhost:4318").
+	// Empty leaves tracing to the standard OTEL_EXPORTER_OTLP_* variables,
+	// and off without them.
+	Endpoint string `toml:"endpoint"`
+}
+
+// EventsConfig sets what the event log records and how long it is kept.
+type EventsConfig struct {
+	// RetentionDays is how many days of events the daemon keeps (0 = keep
+	// everything).
+	RetentionDays int `toml:"retention_days"`
+
+	// RecordPrompts records the text of prompts sent to agents, not just
+	// their hash and length.
+	RecordPrompts bool `toml:"record_prompts"`
+
+	// Chain makes the log tamper-evident: each event records the SHA-256
+	// of the one before it, and gt events verify checks the chain.
+	Chain bool `toml:"chain"`
+
+	// SigningKey also signs each event with HMAC-SHA256, so the chain
+	// can't be rebuilt without the key. Must be a ${VAR} or secret://
+	// reference. Setting it implies chain.
+	SigningKey string `toml:"signing_key"`
+}
+
+// Chained reports whether events are hash-chained.
+func (c EventsConfig) Chained() bool {
+	return c.Chain || c.SigningKey != ""
+}
+
+// Retention returns RetentionDays as a duration (0 = keep everything).
+func (c EventsConfig) Retention() time.Duration {
+	return time.Duration(c.RetentionDays) * 24 * time.Hour
+}
+
+// RedactConfig adds to the built-in patterns (API keys, forge and cloud
+// tokens, private keys) that redact credentials from pane captures, logs,
+// webhook events, and the event log.
+type RedactConfig struct {
+	// Patterns are regular expressions whose matches are replaced with
+	// "[REDACTED]" (e.g., "acme_[0-9a-f]{32}").
+	Patterns []string `toml:"patterns"`
+}
+
+// Default runaway-loop thresholds.
+const (
+	DefaultLoopRepeatCalls   = 10
+	DefaultLoopRepeatPrompts = 5
+	DefaultLoopBurnFactor    = 5
+)
+
+// LoopsConfig sets when a session is paused as a runaway loop: repeating
+// the same tool calls or prompts, or burning tokens far faster than the
+// town's other sessions. Nil thresholds mean the defaults; 0 turns a check
+// off.
+type LoopsConfig struct {
+	// RepeatCalls is how many times in a row the same tool call (same
+	// tool, same input), or the same pair of calls alternating, pauses a
+	// session. Nil means the default (10).
+	RepeatCalls *int `toml:"repeat_calls"`
+
+	// RepeatPrompts is how many times in a row the same prompt, or the
+	// same pair of prompts alternating, pauses a session. Nil means the
+	// default (5).
+	RepeatPrompts *int `toml:"repeat_prompts"`
+
+	// BurnFactor pauses a session whose token rate exceeds this multiple
+	// of the baseline. Nil means the default (5).
+	BurnFactor *int `toml:"burn_factor"`
+
+	// BaselineTokensPerMinute is the normal token rate of a session. 0
+	// means the median rate of the town's running sessions.
+	BaselineTokensPerMinute int `toml:"baseline_tokens_per_minute"`
+}
+
+// Thresholds returns the effective thresholds, with defaults applied.
+func (c LoopsConfig) Thresholds() (repeatCalls, repeatPrompts, burnFactor int) {
+	orDefault := func(v *int, def int) int {
+		if v != nil {
+			return *v
+		}
+		return def
+	}
+	return orDefault(c.RepeatCalls, DefaultLoopRepeatCalls),
+		orDefault(c.RepeatPrompts, DefaultLoopRepeatPrompts),
+		orDefault(c.BurnFactor, DefaultLoopBurnFactor)
+}
+
+// Default cost anomaly thresholds.
+const (
+	DefaultCostSpikeFactor   = 3.0
+	DefaultCostMinSpikeUSD   = 1.0
+	DefaultCostBaselineHours = 24
+)
+
+// CostAlertsConfig sets when a rig's spend over the last hour is alerted:
+// above an absolute cap, or a spike above the rig's own baseline. Alerts
+// are posted to the webhooks and recorded in the feed.
+type CostAlertsConfig struct {
+	// HourlyUSD alerts when a rig spends more than this in an hour
+	// (0 = no absolute cap).
+	HourlyUSD float64 `toml:"hourly_usd"`
+
+	// SpikeFactor alerts when a rig's last hour exceeds this multiple of
+	// its baseline. Nil means the default (3); 0 turns spike alerts off.
+	SpikeFactor *float64 `toml:"spike_factor"`
+
+	// MinSpikeUSD is the least an hour must cost to count as a spike, so
+	// an idle rig's first few cents don't alert. Nil means the default (1).
+	MinSpikeUSD *float64 `toml:"min_spike_usd"`
+
+	// BaselineHours is how many past hours are averaged for a rig's
+	// baseline. 0 means the default (24).
+	BaselineHours int `toml:"baseline_hours"`
+
+	// WebhookURL receives each alert as a JSON POST. May be a ${VAR} or
+	// secret:// reference.
+	WebhookURL string `toml:"webhook_url"`
+
+	// SlackWebhook is a Slack incoming webhook URL. Empty means
+	// contacts.slack_webhook in settings/escalation.json, if set.
+	SlackWebhook string `toml:"slack_webhook"`
+}
+
+// Thresholds returns the effective thresholds, with defaults applied.
+func (c CostAlertsConfig) Thresholds() (hourlyUSD, spikeFactor, minSpikeUSD float64, baselineHours int) {
+	spikeFactor, minSpikeUSD, baselineHours = DefaultCostSpikeFactor, DefaultCostMinSpikeUSD, DefaultCostBaselineHours
+	if c.SpikeFactor != nil {
+		spikeFactor = *c.SpikeFactor
+	}
+	if c.MinSpikeUSD != nil {
+		minSpikeUSD = *c.MinSpikeUSD
+	}
+	if c.BaselineHours != 0 {
+		baselineHours = c.BaselineHours
+	}
+	return c.HourlyUSD, spikeFactor, minSpikeUSD, baselineHours
+}
+
+// Outbound filter modes: what happens to content the filter matches.
+const (
+	OutboundRedact       = "redact"       // replaced with [FILTERED]
+	OutboundPseudonymize = "pseudonymize" // replaced with a stable hash token
+	OutboundBlock        = "block"        // the prompt isn't sent
+)
+
+// OutboundConfig filters prompts before they leave for the model's API,
+// for teams with rules about what may be sent to a third party. It applies
+// to prompts gt sends into sessions and, through the runtime's
+// UserPromptSubmit hook, to prompts typed or pasted into them.
+type OutboundConfig struct {
+	// PII turns on the built-in detectors: email addresses, phone numbers,
+	// US social security numbers, and payment card numbers.
+	PII bool `toml:"pii"`
+
+	// Patterns are regular expressions for other content to filter (e.g.,
+	// "ACME-[0-9]{6}" for customer IDs).
+	Patterns []string `toml:"patterns"`
+
+	// Deny lists terms to filter wherever they appear, ignoring case
+	// (e.g., project codenames, customer names).
+	Deny []string `toml:"deny"`
+
+	// Mode is redact (the default), pseudonymize, or block.
+	Mode string `toml:"mode"`
+
+	// HashKey keys the pseudonyms, so they can't be reversed by hashing
+	// guesses. Must be a ${VAR} or secret:// reference.
+	HashKey string `toml:"hash_key"`
+}
+
+// Enabled reports whether the filter has anything to match.
+func (c OutboundConfig) Enabled() bool {
+	return c.PII || len(c.Patterns) > 0 || len(c.Deny) > 0
+}
+
+// Action returns the filter's mode, with the default applied.
+func (c OutboundConfig) Action() string {
+	if c.Mode == "" {
+		return OutboundRedact
+	}
+	return c.Mode
+}
+
+// ChatEventKinds are the kinds of events the chat integrations (Slack,
+// Discord) post.
+var ChatEventKinds = []string{"session", "escalation", "merge"}
+
+// SlackConfig connects the town to a Slack app: events posted to channels
+// by the daemon (gt slack sync), and /gt slash commands served by gt
+// dashboard at POST /slack/commands.
+type SlackConfig struct {
+	// BotToken is the app's bot token (xoxb-...), which posts to any
+	// channel the app is in. Must be a ${VAR} or secret:// reference.
+	BotToken string `toml:"bot_token"`
+
+	// WebhookURL is an incoming webhook, used instead of BotToken to post
+	// everything to the webhook's channel. May be a reference.
+	WebhookURL string `toml:"webhook_url"`
+
+	// SigningSecret verifies slash command requests. Must be a reference.
+	SigningSecret string `toml:"signing_secret"`
+
+	// Channel receives the events Channels doesn't route elsewhere.
+	Channel string `toml:"channel"`
+
+	// Channels routes a kind of event (session, escalation, merge) to its
+	// own channel.
+	Channels map[string]string `toml:"channels"`
+
+	// Events are the kinds to post. Empty means all of ChatEventKinds.
+	Events []string `toml:"events"`
+
+	// Operators are the Slack user IDs (e.g., "U012AB3CD") allowed to run
+	// /gt prompt and /gt approve. Empty means nobody; /gt status is open
+	// to anyone who can run the command.
+	Operators []string `toml:"operators"`
+}
+
+// Posting reports whether events are posted to Slack.
+func (c SlackConfig) Posting() bool {
+	return c.BotToken != "" || c.WebhookURL != ""
+}
+
+// Posts reports whether events of kind are posted.
+func (c SlackConfig) Posts(kind string) bool {
+	return c.Posting() && postsKind(c.Events, kind)
+}
+
+// ChannelFor returns the channel events of kind are posted to ("" with a
+// webhook, which has its own).
+func (c SlackConfig) ChannelFor(kind string) string {
+	if ch := c.Channels[kind]; ch != "" {
+		return ch
+	}
+	return c.Channel
+}
+
+// IsOperator reports whether the Slack user may run control commands.
+func (c SlackConfig) IsOperator(userID string) bool {
+	return containsString(c.Operators, userID)
+}
+
+// DiscordConfig connects the town to a Discord application: events posted
+// to channels by the daemon (gt discord sync), and /gt slash commands
+// served by gt dashboard at POST /discord/interactions.
+type DiscordConfig struct {
+	// BotToken is the application's bot token, which posts to any channel
+	// the bot can see and registers the /gt command. Must be a ${VAR} or
+	// secret:// reference.
+	BotToken string `toml:"bot_token"`
+
+	// WebhookURL is a channel webhook, used instead of BotToken to post
+	// everything to the webhook's channel. May be a reference.
+	WebhookURL string `toml:"webhook_url"`
+
+	// ApplicationID is the application's ID, which gt discord register
+	// registers the /gt command for.
+	ApplicationID string `toml:"application_id"`
+
+	// PublicKey is the application's public key (hex), which verifies
+	// interaction requests. It isn't a secret.
+	PublicKey string `toml:"public_key"`
+
+	// Channel is the ID of the channel receiving the events Rigs and
+	// Channels don't route elsewhere.
+	Channel string `toml:"channel"`
+
+	// Rigs routes a rig's events to its own channel (rig name -> channel
+	// ID).
+	Rigs map[string]string `toml:"rigs"`
+
+	// Channels routes a kind of event (session, escalation, merge) from
+	// a rig without its own channel, or from no rig, to its own channel.
+	Channels map[string]string `toml:"channels"`
+
+	// Events are the kinds to post. Empty means all of ChatEventKinds.
+	Events []string `toml:"events"`
+
+	// Operators are the Discord user IDs allowed to run /gt prompt, /gt
+	// approve, and /gt stop. Empty means nobody; /gt status is open to
+	// anyone who can run the command.
+	Operators []string `toml:"operators"`
+}
+
+// Posting reports whether events are posted to Discord.
+func (c DiscordConfig) Posting() bool {
+	return c.BotToken != "" || c.WebhookURL != ""
+}
+
+// Posts reports whether events of kind are posted.
+func (c DiscordConfig) Posts(kind string) bool {
+	return c.Posting() && postsKind(c.Events, kind)
+}
+
+// ChannelFor returns the channel ID an event of kind from rig ("" for
+// town-level events) is posted to ("" with a webhook, which has its own).
+func (c DiscordConfig) ChannelFor(kind, rig string) string {
+	if ch := c.Rigs[rig]; rig != "" && ch != "" {
+		return ch
+	}
+	if ch := c.Channels[kind]; ch != "" {
+		return ch
+	}
+	return c.Channel
+}
+
+// IsOperator reports whether the Discord user may run control commands.
+func (c DiscordConfig) IsOperator(userID string) bool {
+	return containsString(c.Operators, userID)
+}
+
+// EmailConfig sends high-severity events by email (gt email), for
+// operators who don't run a chat integration. Rigs route their own events
+// to their own recipients with email in settings/config.json; the rest go
+// to To.
+type EmailConfig struct {
+	// SMTPHost is the SMTP server. Mail is sent with STARTTLS when the
+	// server offers it, and logging in requires it.
+	SMTPHost string `toml:"smtp_host"`
+
+	// SMTPPort is the server's submission port. Zero means 587.
+	SMTPPort int `toml:"smtp_port"`
+
+	// Username logs in to the server. Empty sends without logging in.
+	Username string `toml:"username"`
+
+	// Password logs in to the server. Must be a ${VAR} or secret://
+	// reference.
+	Password string `toml:"password"`
+
+	// From is the sender's address.
+	From string `toml:"from"`
+
+	// To, Events, and Digest route town-level events, and rigs' events
+	// when the rig doesn't route its own (see EmailRouting).
+	To     []string `toml:"to"`
+	Events []string `toml:"events"`
+	Digest string   `toml:"digest"`
+}
+
+// Sending reports whether events are emailed.
+func (c EmailConfig) Sending() bool {
+	return c.SMTPHost != ""
+}
+
+// Port returns the SMTP port, with the default applied.
+func (c EmailConfig) Port() int {
+	if c.SMTPPort == 0 {
+		return 587
+	}
+	return c.SMTPPort
+}
+
+// Routing returns the town's own routing.
+func (c EmailConfig) Routing() EmailRouting {
+	return EmailRouting{To: c.To, Events: c.Events, Digest: c.Digest}
+}
+
+// TrackerStages are the points in a bead's life an issue tracker
+// integration (gt jira, gt linear) moves its issue through: slung to a
+// polecat, submitted as a PR by gt done, and merged by the refinery.
+var TrackerStages = []string{"assigned", "pr", "merged"}
+
+// JiraConfig connects the town to a Jira site (gt jira): issues matching a
+// rig's JQL are imported as beads, and as polecats work on them their
+// issues are transitioned and commented on.
+type JiraConfig struct {
+	// URL is the site's base URL (e.g., "https://example.atlassian.net").
+	URL string `toml:"url"`
+
+	// User is the account the token belongs to, for Jira Cloud's basic
+	// auth. Empty sends the token as a bearer personal access token, for
+	// Jira Server and Data Center.
+	User string `toml:"user"`
+
+	// Token is the API token. Must be a ${VAR} or secret:// reference.
+	Token string `toml:"token"`
+
+	// Rigs maps a rig to the JQL whose issues are imported as its beads.
+	Rigs map[string]string `toml:"rigs"`
+
+	// Transitions maps a stage (see TrackerStages) to the transition, or the
+	// status it moves to, applied to the issue then (e.g., "In Progress").
+	// Stages not listed leave the issue's status alone.
+	Transitions map[string]string `toml:"transitions"`
+
+	// Comments adds a comment to the issue at each stage, summarizing the
+	// polecat's session.
+	Comments bool `toml:"comments"`
+}
+
+// Enabled reports whether the town is connected to Jira.
+func (c JiraConfig) Enabled() bool {
+	return c.URL != ""
+}
+
+// LinearConfig connects the town to a Linear workspace (gt linear): issues
+// in each rig's team, project, and labels are imported as beads, and as
+// polecats work on them their issues change state and get their PR linked.
+type LinearConfig struct {
+	// APIKey is a Linear personal API key. Must be a ${VAR} or secret://
+	// reference.
+	APIKey string `toml:"api_key"`
+
+	// WebhookSecret verifies the deliveries of the workspace's webhook,
+	// served at /linear/webhook by gt dashboard. Must be a ${VAR} or
+	// secret:// reference. Empty refuses webhooks.
+	WebhookSecret string `toml:"webhook_secret"`
+
+	// Rigs maps a rig to the issues imported as its beads.
+	Rigs map[string]LinearRig `toml:"rigs"`
+
+	// States maps a stage (see TrackerStages) to the workflow state the
+	// issue is moved to then (e.g., "In Progress"). Stages not listed leave
+	// the issue's state alone.
+	States map[string]string `toml:"states"`
+}
+
+// LinearRig selects the Linear issues imported as a rig's beads.
+type LinearRig struct {
+	// Team is the key of the issues' team (e.g., "ENG").
+	Team string `toml:"team"`
+
+	// Project, if set, limits the import to the project with this name.
+	Project string `toml:"project"`
+
+	// Labels, if set, limits the import to issues with any of these labels.
+	Labels []string `toml:"labels"`
+
+	// Spawn slings issues to the rig as soon as they're triaged (moved into
+	// the team's backlog or todo), as reported by the webhook, instead of
+	// waiting for gt linear sync to import them.
+	Spawn bool `toml:"spawn"`
+}
+
+// Matches reports whether an issue in team and project with labels is
+// imported into the rig.
+func (r LinearRig) Matches(team, project string, labels []string) bool {
+	if !strings.EqualFold(team, r.Team) {
+		return false
+	}
+	if r.Project != "" && !strings.EqualFold(project, r.Project) {
+		return false
+	}
+	if len(r.Labels) == 0 {
+		return true
+	}
+	for _, l := range labels {
+		for _, want := range r.Labels {
+			if strings.EqualFold(l, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Enabled reports whether the town is connected to Linear.
+func (c LinearConfig) Enabled() bool {
+	return c.APIKey != ""
+}
+
+// AlertConditions are the conditions gt incidents opens incidents for,
+// with their default severities.
+var AlertConditions = map[string]string{
+	"town_unreachable":   SeverityCritical, // a federated remote town doesn't answer
+	"deacon_down":        SeverityCritical,
+	"merge_queue_wedged": SeverityHigh, // MRs waiting, none merged, for wedged_after
+	"budget_exceeded":    SeverityHigh,
+}
+
+// AlertingConfig opens an incident in PagerDuty or Opsgenie while a
+// critical condition (see AlertConditions) holds, and resolves it when the
+// condition clears (gt incidents).
+type AlertingConfig struct {
+	PagerDuty PagerDutyConfig `toml:"pagerduty"`
+	Opsgenie  OpsgenieConfig  `toml:"opsgenie"`
+
+	// Severities overrides conditions' default severities (e.g.,
+	// {merge_queue_wedged = "critical"}).
+	Severities map[string]string `toml:"severities"`
+
+	// WedgedAfter is how long MRs may wait with nothing merged before the
+	// merge queue counts as wedged (default "2h").
+	WedgedAfter string `toml:"wedged_after"`
+}
+
+// PagerDutyConfig sends incidents to a PagerDuty service through its
+// Events API v2 integration.
+type PagerDutyConfig struct {
+	// RoutingKey is the integration's key. Must be a ${VAR} or secret://
+	// reference.
+	RoutingKey string `toml:"routing_key"`
+
+	// MinSeverity is the least severe condition paged (default: all).
+	MinSeverity string `toml:"min_severity"`
+}
+
+// OpsgenieConfig sends incidents to Opsgenie as alerts.
+type OpsgenieConfig struct {
+	// APIKey is an API integration's key. Must be a ${VAR} or secret://
+	// reference.
+	APIKey string `toml:"api_key"`
+
+	// URL is the API's base URL (default "https://api.opsgenie.com"; EU
+	// accounts use "https://api.eu.opsgenie.com").
+	URL string `toml:"url"`
+
+	// MinSeverity is the least severe condition alerted (default: all).
+	MinSeverity string `toml:"min_severity"`
+}
+
+// Enabled reports whether incidents are opened anywhere.
+func (c AlertingConfig) Enabled() bool {
+	return c.PagerDuty.RoutingKey != "" || c.Opsgenie.APIKey != ""
+}
+
+// Severity returns a condition's severity, with overrides applied.
+func (c AlertingConfig) Severity(condition string) string {
+	if s := c.Severities[condition]; s != "" {
+		return s
+	}
+	return AlertConditions[condition]
+}
+
+// WedgedAfterDuration returns WedgedAfter, with the default applied.
+func (c AlertingConfig) WedgedAfterDuration() time.Duration {
+	if d, err := time.ParseDuration(c.WedgedAfter); err == nil && d > 0 {
+		return d
+	}
+	return 2 * time.Hour
+}
+
+// postsKind reports whether a chat integration posting kinds (empty = all)
+// posts events of kind.
+func postsKind(kinds []string, kind string) bool {
+	return len(kinds) == 0 || containsString(kinds, kind)
+}
+
+// isChatEventKind reports whether kind is one of ChatEventKinds.
+func isChatEventKind(kind string) bool {
+	return containsString(ChatEventKinds, kind)
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ToolPolicy restricts what an agent's tools may touch. Empty lists impose
+// no restriction.
+type ToolPolicy struct {
+	// Profile names the permission profile (e.g., "observer", "developer",
+	// "merger"); "none" turns the role's default profile off.
+	Profile string `toml:"profile"`
+
+	// Paths are the directories file tools may read and write, relative to
+	// the session's working directory (e.g., "." for the worktree only) or
+	// absolute.
+	Paths []string `toml:"paths"`
+
+	// AllowCommands, if set, are the only commands shell tools may run
+	// (e.g., "git", "go", "make").
+	AllowCommands []string `toml:"allow_commands"`
+
+	// DenyCommands are commands or command prefixes shell tools must not
+	// run (e.g., "sudo", "git push --force").
+	DenyCommands []string `toml:"deny_commands"`
+
+	// AllowHosts, if set, are the only hosts tools may reach over the
+	// network. "*.example.com" matches subdomains.
+	AllowHosts []string `toml:"allow_hosts"`
+
+	// DenyHosts are hosts tools must not reach, even if allowed.
+	DenyHosts []string `toml:"deny_hosts"`
+}
+
+// PolicyConfig is the tool policy for every role, with per-role overrides.
+type PolicyConfig struct {
+	ToolPolicy
+
+	// Roles override the town-wide lists for a role (e.g., "polecat"); a
+	// list a role sets replaces the town-wide one.
+	Roles map[string]ToolPolicy `toml:"roles"`
+}
+
+// ForRole returns the policy that applies to role. Unless the policy names
+// a profile, the role's default profile applies.
+func (c PolicyConfig) ForRole(role string) ToolPolicy {
+	p := c.ToolPolicy
+	p.merge(c.Roles[role])
+	if p.Profile == "" {
+		p.Profile = defaultRoleProfiles[role]
+	}
+	return p
+}
+
+// PermissionProfile returns the profile p names, or nil for none.
+func (p ToolPolicy) PermissionProfile() *PermissionProfile {
+	return GetPermissionProfile(p.Profile)
+}
+
+// validate checks the profile p names.
+func (p ToolPolicy) validate() error {
+	if p.Profile != "" && p.Profile != ProfileNone && GetPermissionProfile(p.Profile) == nil {
+		return fmt.Errorf("unknown profile %q: want one of %v or %q", p.Profile, ListPermissionProfiles(), ProfileNone)
+	}
+	return nil
+}
+
+// merge overlays the settings other sets onto p.
+func (p *ToolPolicy) merge(other ToolPolicy) {
+	if other.Profile != "" {
+		p.Profile = other.Profile
+	}
+	if len(other.Paths) > 0 {
+		p.Paths = other.Paths
+	}
+	if len(other.AllowCommands) > 0 {
+		p.AllowCommands = other.AllowCommands
+	}
+	if len(other.DenyCommands) > 0 {
+		p.DenyCommands = other.DenyCommands
+	}
+	if len(other.AllowHosts) > 0 {
+		p.AllowHosts = other.AllowHosts
+	}
+	if len(other.DenyHosts) > 0 {
+		p.DenyHosts = other.DenyHosts
+	}
+}
+
+// GastownMergeQueueConfig holds the merge queue settings gastown.toml can
+// override.
+type GastownMergeQueueConfig struct {
+	// Checks replace the rig's pre-merge checks from config.json.
+	Checks []MergeCheckConfig `toml:"checks"`
+}
+
+// ServerConfig configures the dashboard and API server.
+type ServerConfig struct {
+	// Bind is the address to listen on (default: all interfaces).
+	Bind string `toml:"bind"`
+
+	// Port is the TCP port to listen on (default: 8080).
+	Port int `toml:"port"`
+
+	// TLSCert and TLSKey are PEM certificate and private key files. When
+	// both are set the server speaks HTTPS only.
+	TLSCert string `toml:"tls_cert"`
+	TLSKey  string `toml:"tls_key"`
+
+	// APIKeys are the bearer tokens the server accepts. With none, the
+	// dashboard and its read-only APIs are public; operator routes accept
+	// only the federation token.
+	APIKeys []APIKeyConfig `toml:"api_keys"`
+}
+
+// API key scopes. An operator key may do everything a read key may.
+const (
+	APIScopeRead     = "read"
+	APIScopeOperator = "operator"
+)
+
+// APIKeyConfig is one bearer token accepted by the server.
+type APIKeyConfig struct {
+	// Name identifies the key in errors and logs.
+	Name string `toml:"name"`
+
+	// Key is the token. Must be a ${VAR} or secret:// reference.
+	Key string `toml:"key"`
+
+	// Scope is "read" (dashboard, stats, health) or "operator" (also the
+	// event/audit log).
+	Scope string `toml:"scope"`
+}
+
+// TLS reports whether the server is configured for HTTPS.
+func (c ServerConfig) TLS() bool {
+	return c.TLSCert != "" && c.TLSKey != ""
+}
+
+// Addr returns the listen address for the server.
+func (c ServerConfig) Addr() string {
+	return net.JoinHostPort(c.Bind, strconv.Itoa(c.Port))
+}
+
+// NewGastownConfig returns a GastownConfig with defaults.
+func NewGastownConfig() *GastownConfig {
+	return &GastownConfig{
+		Server: ServerConfig{Bind: DefaultServerBind, Port: DefaultServerPort},
+	}
+}
+
+// GastownConfigPath returns the town-level config file path.
+func GastownConfigPath(townRoot string) string {
+	return filepath.Join(townRoot, GastownConfigFile)
+}
+
+// UserGastownConfigPath returns the user-level config file path,
+// $XDG_CONFIG_HOME/gastown/config.toml or ~/.config/gastown/config.toml.
+// Returns "" if the home directory cannot be determined.
+func UserGastownConfigPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "gastown", "config.toml")
+}
+
+// RigGastownConfigPath returns the rig-level config file path.
+func RigGastownConfigPath(rigPath string) string {
+	return filepath.Join(rigPath, GastownConfigFile)
+}
+
+// LoadGastownConfig resolves the operator config for a town from defaults,
+// the user file, the town file, and the environment, in that order. Missing
+// files are skipped. townRoot may be empty to skip the town file.
+func LoadGastownConfig(townRoot string) (*GastownConfig, error) {
+	return LoadRigGastownConfig(townRoot, "")
+}
+
+// LoadRigGastownConfig is like LoadGastownConfig, with the rig's gastown.toml
+// layered over the town file. Concurrency and server settings are town-wide
+// and are ignored in a rig file. rigPath may be empty for town-level sessions.
+func LoadRigGastownConfig(townRoot, rigPath string) (*GastownConfig, error) {
+	cfg := NewGastownConfig()
+
+	paths := []string{UserGastownConfigPath()}
+	if townRoot != "" {
+		paths = append(paths, GastownConfigPath(townRoot))
+	}
+	if rigPath != "" {
+		paths = append(paths, RigGastownConfigPath(rigPath))
+	}
+	for i, path := range paths {
+		if path == "" {
+			continue
+		}
+		var file GastownConfig
+		md, err := toml.DecodeFile(path, &file)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("loading %s: %w", path, err)
+		}
+		if err := ValidateGastownConfig(&file); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		rigFile := rigPath != "" && i == len(paths)-1
+		if rigFile {
+			file.Concurrency = nil
+			file.Server = ServerConfig{}
+		}
+		cfg.merge(&file)
+		for _, key := range md.Keys() {
+			k := key.String()
+			if rigFile && (key[0] == "concurrency" || key[0] == "server") {
+				continue
+			}
+			if k == "budget" {
+				// A budget table replaces the whole budget
+				cfg.setSource("budget.daily_usd", path)
+				cfg.setSource("budget.weekly_usd", path)
+				cfg.setSource("budget.daily_tokens", path)
+				cfg.setSource("budget.weekly_tokens", path)
+			}
+			cfg.setSource(k, path)
+		}
+		cfg.Files = append(cfg.Files, path)
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	if err := ValidateGastownConfig(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// merge overlays the settings present in other onto c.
+func (c *GastownConfig) merge(other *GastownConfig) {
+	if other.Runtime != "" {
+		c.Runtime = other.Runtime
+	}
+	if other.Model != "" {
+		c.Model = other.Model
+	}
+	if other.MaxTokens != 0 {
+		c.MaxTokens = other.MaxTokens
+	}
+	if other.Concurrency != nil {
+		c.Concurrency = other.Concurrency.MergedOver(c.Concurrency)
+	}
+	if other.Server.Bind != "" {
+		c.Server.Bind = other.Server.Bind
+	}
+	if other.Server.Port != 0 {
+		c.Server.Port = other.Server.Port
+	}
+	if other.Server.TLSCert != "" {
+		c.Server.TLSCert = other.Server.TLSCert
+	}
+	if other.Server.TLSKey != "" {
+		c.Server.TLSKey = other.Server.TLSKey
+	}
+	if other.Server.APIKeys != nil {
+		c.Server.APIKeys = other.Server.APIKeys
+	}
+	if other.Budget != nil {
+		c.Budget = other.Budget
+	}
+	for role, prompt := range other.Prompts {
+		if c.Prompts == nil {
+			c.Prompts = make(map[string]string)
+		}
+		c.Prompts[role] = prompt
+	}
+	if other.Activity.Active != "" {
+		c.Activity.Active = other.Activity.Active
+	}
+	if other.Activity.Stale != "" {
+		c.Activity.Stale = other.Activity.Stale
+	}
+	if other.MergeQueue != nil && other.MergeQueue.Checks != nil {
+		c.MergeQueue = other.MergeQueue
+	}
+	if other.Git.Retries != nil {
+		c.Git.Retries = other.Git.Retries
+	}
+	if other.Git.RetryBackoff != "" {
+		c.Git.RetryBackoff = other.Git.RetryBackoff
+	}
+	if other.Log.Level != "" {
+		c.Log.Level = other.Log.Level
+	}
+	if other.Log.Format != "" {
+		c.Log.Format = other.Log.Format
+	}
+	if other.Tracing.Endpoint != "" {
+		c.Tracing.Endpoint = other.Tracing.Endpoint
+	}
+	if other.Events.RetentionDays != 0 {
+		c.Events.RetentionDays = other.Events.RetentionDays
+	}
+	if other.Events.RecordPrompts {
+		c.Events.RecordPrompts = true
+	}
+	if other.Events.Chain {
+		c.Events.Chain = true
+	}
+	if other.Events.SigningKey != "" {
+		c.Events.SigningKey = other.Events.SigningKey
+	}
+	if len(other.Redact.Patterns) > 0 {
+		c.Redact.Patterns = other.Redact.Patterns
+	}
+	if other.Loops.RepeatCalls != nil {
+		c.Loops.RepeatCalls = other.Loops.RepeatCalls
+	}
+	if other.Loops.RepeatPrompts != nil {
+		c.Loops.RepeatPrompts = other.Loops.RepeatPrompts
+	}
+	if other.Loops.BurnFactor != nil {
+		c.Loops.BurnFactor = other.Loops.BurnFactor
+	}
+	if other.Loops.BaselineTokensPerMinute != 0 {
+		c.Loops.BaselineTokensPerMinute = other.Loops.BaselineTokensPerMinute
+	}
+	if other.CostAlerts.HourlyUSD != 0 {
+		c.CostAlerts.HourlyUSD = other.CostAlerts.HourlyUSD
+	}
+	if other.CostAlerts.SpikeFactor != nil {
+		c.CostAlerts.SpikeFactor = other.CostAlerts.SpikeFactor
+	}
+	if other.CostAlerts.MinSpikeUSD != nil {
+		c.CostAlerts.MinSpikeUSD = other.CostAlerts.MinSpikeUSD
+	}
+	if other.CostAlerts.BaselineHours != 0 {
+		c.CostAlerts.BaselineHours = other.CostAlerts.BaselineHours
+	}
+	if other.CostAlerts.WebhookURL != "" {
+		c.CostAlerts.WebhookURL = other.CostAlerts.WebhookURL
+	}
+	if other.CostAlerts.SlackWebhook != "" {
+		c.CostAlerts.SlackWebhook = other.CostAlerts.SlackWebhook
+	}
+	if other.Outbound.PII {
+		c.Outbound.PII = true
+	}
+	if len(other.Outbound.Patterns) > 0 {
+		c.Outbound.Patterns = other.Outbound.Patterns
+	}
+	if len(other.Outbound.Deny) > 0 {
+		c.Outbound.Deny = other.Outbound.Deny
+	}
+	if other.Outbound.Mode != "" {
+		c.Outbound.Mode = other.Outbound.Mode
+	}
+	if other.Outbound.HashKey != "" {
+		c.Outbound.HashKey = other.Outbound.HashKey
+	}
+	if other.Slack.BotToken != "" {
+		c.Slack.BotToken = other.Slack.BotToken
+	}
+	if other.Slack.WebhookURL != "" {
+		c.Slack.WebhookURL = other.Slack.WebhookURL
+	}
+	if other.Slack.SigningSecret != "" {
+		c.Slack.SigningSecret = other.Slack.SigningSecret
+	}
+	if other.Slack.Channel != "" {
+		c.Slack.Channel = other.Slack.Channel
+	}
+	for kind, ch := range other.Slack.Channels {
+		if c.Slack.Channels == nil {
+			c.Slack.Channels = make(map[string]string)
+		}
+		c.Slack.Channels[kind] = ch
+	}
+	if len(other.Slack.Events) > 0 {
+		c.Slack.Events = other.Slack.Events
+	}
+	if len(other.Slack.Operators) > 0 {
+		c.Slack.Operators = other.Slack.Operators
+	}
+	if other.Discord.BotToken != "" {
+		c.Discord.BotToken = other.Discord.BotToken
+	}
+	if other.Discord.WebhookURL != "" {
+		c.Discord.WebhookURL = other.Discord.WebhookURL
+	}
+	if other.Discord.ApplicationID != "" {
+		c.Discord.ApplicationID = other.Discord.ApplicationID
+	}
+	if other.Discord.PublicKey != "" {
+		c.Discord.PublicKey = other.Discord.PublicKey
+	}
+	if other.Discord.Channel != "" {
+		c.Discord.Channel = other.Discord.Channel
+	}
+	for rig, ch := range other.Discord.Rigs {
+		if c.Discord.Rigs == nil {
+			c.Discord.Rigs = make(map[string]string)
+		}
+		c.Discord.Rigs[rig] = ch
+	}
+	for kind, ch := range other.Discord.Channels {
+		if c.Discord.Channels == nil {
+			c.Discord.Channels = make(map[string]string)
+		}
+		c.Discord.Channels[kind] = ch
+	}
+	if len(other.Discord.Events) > 0 {
+		c.Discord.Events = other.Discord.Events
+	}
+	if len(other.Discord.Operators) > 0 {
+		c.Discord.Operators = other.Discord.Operators
+	}
+	if other.Email.SMTPHost != "" {
+		c.Email.SMTPHost = other.Email.SMTPHost
+	}
+	if other.Email.SMTPPort != 0 {
+		c.Email.SMTPPort = other.Email.SMTPPort
+	}
+	if other.Email.Username != "" {
+		c.Email.Username = other.Email.Username
+	}
+	if other.Email.Password != "" {
+		c.Email.Password = other.Email.Password
+	}
+	if other.Email.From != "" {
+		c.Email.From = other.Email.From
+	}
+	if len(other.Email.To) > 0 {
+		c.Email.To = other.Email.To
+	}
+	if len(other.Email.Events) > 0 {
+		c.Email.Events = other.Email.Events
+	}
+	if other.Email.Digest != "" {
+		c.Email.Digest = other.Email.Digest
+	}
+	if other.Jira.URL != "" {
+		c.Jira.URL = other.Jira.URL
+	}
+	if other.Jira.User != "" {
+		c.Jira.User = other.Jira.User
+	}
+	if other.Jira.Token != "" {
+		c.Jira.Token = other.Jira.Token
+	}
+	for rig, jql := range other.Jira.Rigs {
+		if c.Jira.Rigs == nil {
+			c.Jira.Rigs = make(map[string]string)
+		}
+		c.Jira.Rigs[rig] = jql
+	}
+	for stage, name := range other.Jira.Transitions {
+		if c.Jira.Transitions == nil {
+			c.Jira.Transitions = make(map[string]string)
+		}
+		c.Jira.Transitions[stage] = name
+	}
+	if other.Jira.Comments {
+		c.Jira.Comments = true
+	}
+	if other.Linear.APIKey != "" {
+		c.Linear.APIKey = other.Linear.APIKey
+	}
+	if other.Linear.WebhookSecret != "" {
+		c.Linear.WebhookSecret = other.Linear.WebhookSecret
+	}
+	for rig, r := range other.Linear.Rigs {
+		if c.Linear.Rigs == nil {
+			c.Linear.Rigs = make(map[string]LinearRig)
+		}
+		c.Linear.Rigs[rig] = r
+	}
+	for stage, name := range other.Linear.States {
+		if c.Linear.States == nil {
+			c.Linear.States = make(map[string]string)
+		}
+		c.Linear.States[stage] = name
+	}
+	if other.Alerting.PagerDuty.RoutingKey != "" {
+		c.Alerting.PagerDuty.RoutingKey = other.Alerting.PagerDuty.RoutingKey
+	}
+	if other.Alerting.PagerDuty.MinSeverity != "" {
+		c.Alerting.PagerDuty.MinSeverity = other.Alerting.PagerDuty.MinSeverity
+	}
+	if other.Alerting.Opsgenie.APIKey != "" {
+		c.Alerting.Opsgenie.APIKey = other.Alerting.Opsgenie.APIKey
+	}
+	if other.Alerting.Opsgenie.URL != "" {
+		c.Alerting.Opsgenie.URL = other.Alerting.Opsgenie.URL
+	}
+	if other.Alerting.Opsgenie.MinSeverity != "" {
+		c.Alerting.Opsgenie.MinSeverity = other.Alerting.Opsgenie.MinSeverity
+	}
+	for condition, severity := range other.Alerting.Severities {
+		if c.Alerting.Severities == nil {
+			c.Alerting.Severities = make(map[string]string)
+		}
+		c.Alerting.Severities[condition] = severity
+	}
+	if other.Alerting.WedgedAfter != "" {
+		c.Alerting.WedgedAfter = other.Alerting.WedgedAfter
+	}
+	c.Policy.merge(other.Policy.ToolPolicy)
+	for role, p := range other.Policy.Roles {
+		if c.Policy.Roles == nil {
+			c.Policy.Roles = make(map[string]ToolPolicy)
+		}
+		c.Policy.Roles[role] = p
+	}
+}
+
+// applyEnv overlays the GT_* environment overrides onto c.
+func (c *GastownConfig) applyEnv() error {
+	strs := []struct {
+		env, key string
+		set      func(string)
+	}{
+		{EnvRuntime, "runtime", func(v string) { c.Runtime = v }},
+		{EnvModel, "model", func(v string) { c.Model = v }},
+		{EnvServerBind, "server.bind", func(v string) { c.Server.Bind = v }},
+		{EnvLogLevel, "log.level", func(v string) { c.Log.Level = v }},
+		{EnvLogFormat, "log.format", func(v string) { c.Log.Format = v }},
+	}
+	for _, s := range strs {
+		if v := os.Getenv(s.env); v != "" {
+			s.set(v)
+			c.setSource(s.key, "$"+s.env)
+		}
+	}
+
+	ints := []struct {
+		env, key string
+		set      func(int)
+	}{
+		{EnvMaxTokens, "max_tokens", func(n int) { c.MaxTokens = n }},
+		{EnvServerPort, "server.port", func(n int) { c.Server.Port = n }},
+		{EnvMaxSessions, "concurrency.max_sessions", func(n int) {
+			c.Concurrency = (&ConcurrencyConfig{MaxSessions: n}).MergedOver(c.Concurrency)
+		}},
+	}
+	for _, i := range ints {
+		v := os.Getenv(i.env)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %q is not an integer", i.env, v)
+		}
+		i.set(n)
+		c.setSource(i.key, "$"+i.env)
+	}
+
+	floats := []struct {
+		env, key string
+		set      func(*BudgetConfig, float64)
+	}{
+		{EnvBudgetDailyUSD, "budget.daily_usd", func(b *BudgetConfig, f float64) { b.DailyUSD = f }},
+		{EnvBudgetWeeklyUSD, "budget.weekly_usd", func(b *BudgetConfig, f float64) { b.WeeklyUSD = f }},
+	}
+	for _, f := range floats {
+		v := os.Getenv(f.env)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %q is not a number", f.env, v)
+		}
+		budget := &BudgetConfig{}
+		if c.Budget != nil {
+			*budget = *c.Budget
+		}
+		f.set(budget, n)
+		c.Budget = budget
+		c.setSource(f.key, "$"+f.env)
+	}
+	return nil
+}
+
+// validateAPIKeys validates the server's API keys.
+func validateAPIKeys(keys []APIKeyConfig) error {
+	seen := make(map[string]bool)
+	for i, k := range keys {
+		if k.Name == "" {
+			return fmt.Errorf("invalid server.api_keys[%d]: name is required", i)
+		}
+		if seen[k.Name] {
+			return fmt.Errorf("invalid server.api_keys: duplicate name %q", k.Name)
+		}
+		seen[k.Name] = true
+		if !HasRefs(k.Key) {
+			return fmt.Errorf("invalid server.api_keys.%s.key: must be a ${VAR} or secret:// reference, not the key itself", k.Name)
+		}
+		if k.Scope != APIScopeRead && k.Scope != APIScopeOperator {
+			return fmt.Errorf("invalid server.api_keys.%s.scope %q: want %q or %q", k.Name, k.Scope, APIScopeRead, APIScopeOperator)
+		}
+	}
+	return nil
+}
+
+// ValidateGastownConfig validates a GastownConfig.
+func ValidateGastownConfig(c *GastownConfig) error {
+	if c.MaxTokens < 0 {
+		return fmt.Errorf("invalid max_tokens: must not be negative")
+	}
+	if c.Server.Port < 0 || c.Server.Port > 65535 {
+		return fmt.Errorf("invalid server.port: %d is out of range", c.Server.Port)
+	}
+	if err := validateBindAddress(c.Server.Bind); err != nil {
+		return err
+	}
+	if (c.Server.TLSCert == "") != (c.Server.TLSKey == "") {
+		return fmt.Errorf("invalid server: tls_cert and tls_key must be set together")
+	}
+	if err := validateAPIKeys(c.Server.APIKeys); err != nil {
+		return err
+	}
+	if strings.ContainsAny(c.Runtime, " \t") {
+		return fmt.Errorf("invalid runtime %q: must be an agent name", c.Runtime)
+	}
+	if c.Concurrency != nil {
+		if err := validateConcurrencyConfig(c.Concurrency); err != nil {
+			return err
+		}
+	}
+	if c.Budget != nil {
+		if err := ValidateBudgetConfig(c.Budget); err != nil {
+			return err
+		}
+	}
+	for name, d := range map[string]string{"activity.active": c.Activity.Active, "activity.stale": c.Activity.Stale} {
+		if d == "" {
+			continue
+		}
+		if v, err := time.ParseDuration(d); err != nil || v <= 0 {
+			return fmt.Errorf("invalid %s %q: want a positive duration", name, d)
+		}
+	}
+	if active, stale := c.Activity.Durations(); active > 0 && stale > 0 && stale <= active {
+		return fmt.Errorf("invalid activity: stale (%s) must be longer than active (%s)", c.Activity.Stale, c.Activity.Active)
+	}
+	if c.Git.Retries != nil && *c.Git.Retries < 0 {
+		return fmt.Errorf("invalid git.retries: must not be negative")
+	}
+	if d := c.Git.RetryBackoff; d != "" {
+		if v, err := time.ParseDuration(d); err != nil || v <= 0 {
+			return fmt.Errorf("invalid git.retry_backoff %q: want a positive duration", d)
+		}
+	}
+	if err := (logging.Config{Level: c.Log.Level, Format: c.Log.Format}).Validate(); err != nil {
+		return fmt.Errorf("invalid log: %w", err)
+	}
+	if e := c.Tracing.Endpoint; e != "" {
+		if u, err := url.Parse(e); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid tracing.endpoint %q: must be an http(s) URL", e)
+		}
+	}
+	if c.Events.RetentionDays < 0 {
+		return fmt.Errorf("invalid events.retention_days: must not be negative")
+	}
+	if c.Events.SigningKey != "" && !HasRefs(c.Events.SigningKey) {
+		return fmt.Errorf("invalid events.signing_key: must be a ${VAR} or secret:// reference, not the key itself")
+	}
+	if err := secret.ValidatePatterns(c.Redact.Patterns); err != nil {
+		return fmt.Errorf("invalid redact.patterns: %w", err)
+	}
+	if err := c.Policy.validate(); err != nil {
+		return fmt.Errorf("invalid policy: %w", err)
+	}
+	for role, p := range c.Policy.Roles {
+		if !isRole(role) {
+			return fmt.Errorf("invalid policy.roles.%s: unknown role", role)
+		}
+		if err := p.validate(); err != nil {
+			return fmt.Errorf("invalid policy.roles.%s: %w", role, err)
+		}
+	}
+	for key, v := range map[string]*int{
+		"repeat_calls":   c.Loops.RepeatCalls,
+		"repeat_prompts": c.Loops.RepeatPrompts,
+		"burn_factor":    c.Loops.BurnFactor,
+	} {
+		if v != nil && *v < 0 {
+			return fmt.Errorf("invalid loops.%s: must not be negative", key)
+		}
+	}
+	if c.Loops.BaselineTokensPerMinute < 0 {
+		return fmt.Errorf("invalid loops.baseline_tokens_per_minute: must not be negative")
+	}
+	for key, v := range map[string]*float64{
+		"hourly_usd":    &c.CostAlerts.HourlyUSD,
+		"spike_factor":  c.CostAlerts.SpikeFactor,
+		"min_spike_usd": c.CostAlerts.MinSpikeUSD,
+	} {
+		if v != nil && *v < 0 {
+			return fmt.Errorf("invalid cost_alerts.%s: must not be negative", key)
+		}
+	}
+	if c.CostAlerts.BaselineHours < 0 {
+		return fmt.Errorf("invalid cost_alerts.baseline_hours: must not be negative")
+	}
+	switch c.Outbound.Mode {
+	case "", OutboundRedact, OutboundPseudonymize, OutboundBlock:
+	default:
+		return fmt.Errorf("invalid outbound.mode %q: want %s, %s, or %s", c.Outbound.Mode, OutboundRedact, OutboundPseudonymize, OutboundBlock)
+	}
+	for _, p := range c.Outbound.Patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("invalid outbound.patterns: %q: %w", p, err)
+		}
+	}
+	for _, term := range c.Outbound.Deny {
+		if strings.TrimSpace(term) == "" {
+			return fmt.Errorf("invalid outbound.deny: empty term")
+		}
+	}
+	if c.Outbound.HashKey != "" && !HasRefs(c.Outbound.HashKey) {
+		return fmt.Errorf("invalid outbound.hash_key: must be a ${VAR} or secret:// reference, not the key itself")
+	}
+	for key, v := range map[string]string{
+		"bot_token":      c.Slack.BotToken,
+		"signing_secret": c.Slack.SigningSecret,
+	} {
+		if v != "" && !HasRefs(v) {
+			return fmt.Errorf("invalid slack.%s: must be a ${VAR} or secret:// reference, not the secret itself", key)
+		}
+	}
+	for _, kind := range c.Slack.Events {
+		if !isChatEventKind(kind) {
+			return fmt.Errorf("invalid slack.events: unknown kind %q: want one of %v", kind, ChatEventKinds)
+		}
+	}
+	for kind := range c.Slack.Channels {
+		if !isChatEventKind(kind) {
+			return fmt.Errorf("invalid slack.channels.%s: unknown kind: want one of %v", kind, ChatEventKinds)
+		}
+	}
+	if c.Discord.BotToken != "" && !HasRefs(c.Discord.BotToken) {
+		return fmt.Errorf("invalid discord.bot_token: must be a ${VAR} or secret:// reference, not the token itself")
+	}
+	if k := c.Discord.PublicKey; k != "" {
+		if b, err := hex.DecodeString(k); err != nil || len(b) != 32 {
+			return fmt.Errorf("invalid discord.public_key: want the application's 64-character hex public key")
+		}
+	}
+	for _, kind := range c.Discord.Events {
+		if !isChatEventKind(kind) {
+			return fmt.Errorf("invalid discord.events: unknown kind %q: want one of %v", kind, ChatEventKinds)
+		}
+	}
+	for kind := range c.Discord.Channels {
+		if !isChatEventKind(kind) {
+			return fmt.Errorf("invalid discord.channels.%s: unknown kind: want one of %v", kind, ChatEventKinds)
+		}
+	}
+	if c.Email.Password != "" && !HasRefs(c.Email.Password) {
+		return fmt.Errorf("invalid email.password: must be a ${VAR} or secret:// reference, not the password itself")
+	}
+	if c.Email.SMTPPort < 0 || c.Email.SMTPPort > 65535 {
+		return fmt.Errorf("invalid email.smtp_port: %d", c.Email.SMTPPort)
+	}
+	if c.Email.SMTPHost != "" {
+		if _, err := mail.ParseAddress(c.Email.From); err != nil {
+			return fmt.Errorf("invalid email.from: want the sender's address: %w", err)
+		}
+	}
+	if err := validateEmailRouting(c.Email.Routing()); err != nil {
+		return err
+	}
+	if u := c.Jira.URL; u != "" && !HasRefs(u) && !strings.HasPrefix(u, "https://") && !strings.HasPrefix(u, "http://") {
+		return fmt.Errorf("invalid jira.url: %q must start with http:// or https://", u)
+	}
+	if c.Jira.Token != "" && !HasRefs(c.Jira.Token) {
+		return fmt.Errorf("invalid jira.token: must be a ${VAR} or secret:// reference, not the token itself")
+	}
+	for rig, jql := range c.Jira.Rigs {
+		if strings.TrimSpace(jql) == "" {
+			return fmt.Errorf("invalid jira.rigs.%s: empty JQL", rig)
+		}
+	}
+	for stage := range c.Jira.Transitions {
+		if !containsString(TrackerStages, stage) {
+			return fmt.Errorf("invalid jira.transitions.%s: unknown stage: want one of %v", stage, TrackerStages)
+		}
+	}
+	if c.Linear.APIKey != "" && !HasRefs(c.Linear.APIKey) {
+		return fmt.Errorf("invalid linear.api_key: must be a ${VAR} or secret:// reference, not the key itself")
+	}
+	if c.Linear.WebhookSecret != "" && !HasRefs(c.Linear.WebhookSecret) {
+		return fmt.Errorf("invalid linear.webhook_secret: must be a ${VAR} or secret:// reference, not the secret itself")
+	}
+	for rig, r := range c.Linear.Rigs {
+		if r.Team == "" {
+			return fmt.Errorf("invalid linear.rigs.%s: team is required", rig)
+		}
+	}
+	for stage := range c.Linear.States {
+		if !containsString(TrackerStages, stage) {
+			return fmt.Errorf("invalid linear.states.%s: unknown stage: want one of %v", stage, TrackerStages)
+		}
+	}
+	if err := validateAlertingConfig(c.Alerting); err != nil {
+		return err
+	}
+	if c.MergeQueue != nil {
+		if err := validateMergeQueueConfig(&MergeQueueConfig{Checks: c.MergeQueue.Checks}); err != nil {
+			return fmt.Errorf("invalid merge_queue: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateAlertingConfig checks an [alerting] section.
+func validateAlertingConfig(c AlertingConfig) error {
+	if c.PagerDuty.RoutingKey != "" && !HasRefs(c.PagerDuty.RoutingKey) {
+		return fmt.Errorf("invalid alerting.pagerduty.routing_key: must be a ${VAR} or secret:// reference, not the key itself")
+	}
+	if c.Opsgenie.APIKey != "" && !HasRefs(c.Opsgenie.APIKey) {
+		return fmt.Errorf("invalid alerting.opsgenie.api_key: must be a ${VAR} or secret:// reference, not the key itself")
+	}
+	if u := c.Opsgenie.URL; u != "" && !strings.HasPrefix(u, "https://") && !strings.HasPrefix(u, "http://") {
+		return fmt.Errorf("invalid alerting.opsgenie.url: %q must start with http:// or https://", u)
+	}
+	for name, s := range map[string]string{"pagerduty.min_severity": c.PagerDuty.MinSeverity, "opsgenie.min_severity": c.Opsgenie.MinSeverity} {
+		if s != "" && !IsValidSeverity(s) {
+			return fmt.Errorf("invalid alerting.%s: got '%s', want one of %v", name, s, ValidSeverities())
+		}
+	}
+	for condition, s := range c.Severities {
+		if _, ok := AlertConditions[condition]; !ok {
+			return fmt.Errorf("invalid alerting.severities.%s: unknown condition", condition)
+		}
+		if !IsValidSeverity(s) {
+			return fmt.Errorf("invalid alerting.severities.%s: got '%s', want one of %v", condition, s, ValidSeverities())
+		}
+	}
+	if d := c.WedgedAfter; d != "" {
+		if v, err := time.ParseDuration(d); err != nil || v <= 0 {
+			return fmt.Errorf("invalid alerting.wedged_after %q: want a positive duration", d)
+		}
+	}
+	return nil
+}
+
+// isRole reports whether role names an agent role.
+func isRole(role string) bool {
+	for _, r := range append(append([]string{constants.RoleReviewer}, townRoles...), rigRoles...) {
+		if role == r {
+			return true
+		}
+	}
+	return false
+}
+
+// validateBindAddress checks that bind is an IP address or host name
+// without a port. Empty means all interfaces.
+func validateBindAddress(bind string) error {
+	if bind == "" || net.ParseIP(bind) != nil {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(bind); err == nil {
+		return fmt.Errorf("invalid server.bind %q: must not include a port (use server.port)", bind)
+	}
+	if !hostnamePattern.MatchString(bind) {
+		return fmt.Errorf("invalid server.bind %q: want an IP address or host name", bind)
+	}
+	return nil
+}
+
+// hostnamePattern matches DNS host names such as "localhost" or
+// "build-box.internal".
+var hostnamePattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]*[A-Za-z0-9])?(\.[A-Za-z0-9]([A-Za-z0-9-]*[A-Za-z0-9])?)*$`)
+
+// MergedOver returns c layered over base: a non-zero MaxSessions replaces
+// base's, and role and rig caps are merged with c's taking precedence.
+// Either may be nil.
+func (c *ConcurrencyConfig) MergedOver(base *ConcurrencyConfig) *ConcurrencyConfig {
+	if c == nil {
+		return base
+	}
+	if base == nil {
+		return c
+	}
+	merged := &ConcurrencyConfig{MaxSessions: base.MaxSessions}
+	if c.MaxSessions != 0 {
+		merged.MaxSessions = c.MaxSessions
+	}
+	mergeCaps := func(base, over map[string]int) map[string]int {
+		if len(base) == 0 && len(over) == 0 {
+			return nil
+		}
+		m := make(map[string]int, len(base)+len(over))
+		for k, v := range base {
+			m[k] = v
+		}
+		for k, v := range over {
+			m[k] = v
+		}
+		return m
+	}
+	merged.Roles = mergeCaps(base.Roles, c.Roles)
+	merged.Rigs = mergeCaps(base.Rigs, c.Rigs)
+	return merged
+}
+
+// withGastownDefaults returns rc and env with the operator's model and token
+// limit for the rig (or town) applied for agentName. Agents without a model
+// flag or token env var are left unchanged, as is a runtime whose args
+// already choose a model.
+func withGastownDefaults(rc *RuntimeConfig, env map[string]string, agentName, townRoot, rigPath string) *RuntimeConfig {
+	cfg, err := LoadRigGastownConfig(townRoot, rigPath)
+	if err != nil || (cfg.Model == "" && cfg.MaxTokens == 0) {
+		return rc
+	}
+	resolved := *normalizeRuntimeConfig(rc)
+	info := GetAgentPresetByName(agentName)
+	if info == nil {
+		info = GetAgentPresetByName(filepath.Base(resolved.Command))
+	}
+	if info == nil {
+		return rc
+	}
+	if cfg.MaxTokens > 0 && info.MaxTokensEnv != "" {
+		if _, ok := env[info.MaxTokensEnv]; !ok {
+			env[info.MaxTokensEnv] = strconv.Itoa(cfg.MaxTokens)
+		}
+	}
+	if cfg.Model != "" && info.ModelFlag != "" && !hasArg(resolved.Args, info.ModelFlag) {
+		resolved.Args = append([]string{info.ModelFlag, quoteForShell(cfg.Model)}, resolved.Args...)
+	}
+	return &resolved
+}
+
+// hasArg reports whether args contain flag, alone or as flag=value.
+func hasArg(args []string, flag string) bool {
+	for _, a := range args {
+		if a == flag || strings.HasPrefix(a, flag+"=") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,352 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// setupGastownConfig points the user config at a temp dir and writes the
+// given user and town files (skipped if empty). Returns the town root.
+func setupGastownConfig(t *testing.T, userTOML, townTOML string) string {
+	t.Helper()
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	for _, env := range []string{EnvRuntime, EnvModel, EnvMaxTokens, EnvMaxSessions,
+		EnvServerBind, EnvServerPort, EnvBudgetDailyUSD, EnvBudgetWeeklyUSD, EnvLogLevel, EnvLogFormat} {
+		t.Setenv(env, "")
+	}
+
+	townRoot := t.TempDir()
+	if userTOML != "" {
+		path := UserGastownConfigPath()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(userTOML), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if townTOML != "" {
+		if err := os.WriteFile(GastownConfigPath(townRoot), []byte(townTOML), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return townRoot
+}
+
+func TestLoadGastownConfig_Defaults(t *testing.T) {
+	townRoot := setupGastownConfig(t, "", "")
+
+	cfg, err := LoadGastownConfig(townRoot)
+	if err != nil {
+		t.Fatalf("LoadGastownConfig: %v", err)
+	}
+	if cfg.Runtime != "" || cfg.Model != "" || cfg.MaxTokens != 0 {
+		t.Errorf("expected empty agent settings, got %+v", cfg)
+	}
+	if cfg.Server.Port != DefaultServerPort || cfg.Server.Addr() != ":8080" {
+		t.Errorf("Server = %+v, want default port", cfg.Server)
+	}
+	if cfg.Concurrency != nil || cfg.Budget != nil || len(cfg.Files) != 0 {
+		t.Errorf("expected no concurrency, budget, or files, got %+v", cfg)
+	}
+}
+
+func TestLoadGastownConfig_Precedence(t *testing.T) {
+	townRoot := setupGastownConfig(t, `
+runtime = "gemini"
+model = "user-model"
+max_tokens = 8000
+
+[concurrency]
+max_sessions = 4
+roles = { polecat = 3 }
+
+[server]
+bind = "127.0.0.1"
+`, `
+model = "town-model"
+
+[concurrency]
+max_sessions = 10
+
+[server]
+port = 9090
+
+[budget]
+daily_usd = 25
+`)
+	t.Setenv(EnvMaxTokens, "16000")
+	t.Setenv(EnvBudgetWeeklyUSD, "100")
+
+	cfg, err := LoadGastownConfig(townRoot)
+	if err != nil {
+		t.Fatalf("LoadGastownConfig: %v", err)
+	}
+
+	if cfg.Runtime != "gemini" {
+		t.Errorf("Runtime = %q, want user file's gemini", cfg.Runtime)
+	}
+	if cfg.Model != "town-model" {
+		t.Errorf("Model = %q, want town file to override user file", cfg.Model)
+	}
+	if cfg.MaxTokens != 16000 {
+		t.Errorf("MaxTokens = %d, want env to override file", cfg.MaxTokens)
+	}
+	if cfg.Concurrency.MaxSessions != 10 || cfg.Concurrency.Roles["polecat"] != 3 {
+		t.Errorf("Concurrency = %+v, want town max_sessions with user role caps", cfg.Concurrency)
+	}
+	if cfg.Server.Addr() != "127.0.0.1:9090" {
+		t.Errorf("Server.Addr() = %q, want 127.0.0.1:9090", cfg.Server.Addr())
+	}
+	if cfg.Budget.DailyUSD != 25 || cfg.Budget.WeeklyUSD != 100 {
+		t.Errorf("Budget = %+v, want daily from file and weekly from env", cfg.Budget)
+	}
+	if len(cfg.Files) != 2 || cfg.Files[1] != GastownConfigPath(townRoot) {
+		t.Errorf("Files = %v, want user then town file", cfg.Files)
+	}
+}
+
+func TestLoadGastownConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		toml string
+		env  map[string]string
+		want string
+	}{
+		{"bad toml", "model = ", nil, "loading"},
+		{"negative tokens", "max_tokens = -1", nil, "max_tokens"},
+		{"bad port", "[server]\nport = 70000", nil, "server.port"},
+		{"tls cert without key", "[server]\ntls_cert = \"cert.pem\"", nil, "tls_key"},
+		{"literal api key", "[[server.api_keys]]\nname = \"ci\"\nkey = \"abc\"\nscope = \"read\"", nil, "server.api_keys.ci.key"},
+		{"bad api key scope", "[[server.api_keys]]\nname = \"ci\"\nkey = \"${CI_KEY}\"\nscope = \"admin\"", nil, "server.api_keys.ci.scope"},
+		{"bad budget action", "[budget]\nactions = [\"explode\"]", nil, "budget action"},
+		{"bad env int", "", map[string]string{EnvMaxSessions: "many"}, EnvMaxSessions},
+		{"bad env float", "", map[string]string{EnvBudgetDailyUSD: "lots"}, EnvBudgetDailyUSD},
+		{"bad activity", "[activity]\nactive = \"10m\"\nstale = \"5m\"", nil, "stale"},
+		{"bad check", "[[merge_queue.checks]]\nname = \"lint\"", nil, "merge_queue"},
+		{"negative git retries", "[git]\nretries = -1", nil, "git.retries"},
+		{"bad git backoff", "[git]\nretry_backoff = \"soon\"", nil, "git.retry_backoff"},
+		{"bad log level", "[log]\nlevel = \"loud\"", nil, "log level"},
+		{"bad env log format", "", map[string]string{EnvLogFormat: "xml"}, "log format"},
+		{"bad tracing endpoint", "[tracing]\nendpoint = \"localhost:4318\"", nil, "tracing.endpoint"},
+		{"negative retention", "[events]\nretention_days = -1", nil, "events.retention_days"},
+		{"unknown policy role", "[policy.roles.janitor]\ndeny_commands = [\"sudo\"]", nil, "policy.roles.janitor"},
+		{"unknown policy profile", "[policy.roles.polecat]\nprofile = \"admin\"", nil, "policy.roles.polecat"},
+		{"bad redact pattern", "[redact]\npatterns = [\"acme_(\"]", nil, "redact.patterns"},
+		{"negative loop threshold", "[loops]\nrepeat_calls = -1", nil, "loops.repeat_calls"},
+		{"literal signing key", "[events]\nsigning_key = \"hunter2\"", nil, "events.signing_key"},
+		{"negative cost spike factor", "[cost_alerts]\nspike_factor = -2", nil, "cost_alerts.spike_factor"},
+		{"unknown outbound mode", "[outbound]\nmode = \"scrub\"", nil, "outbound.mode"},
+		{"literal slack token", "[slack]\nbot_token = \"xoxb-123\"", nil, "slack.bot_token"},
+		{"unknown slack event kind", "[slack]\nevents = [\"deploys\"]", nil, "slack.events"},
+		{"literal discord token", "[discord]\nbot_token = \"MTk4NjIyNDgzNDcxOTI1MjQ4.Cl2FMQ\"", nil, "discord.bot_token"},
+		{"bad discord public key", "[discord]\npublic_key = \"not-hex\"", nil, "discord.public_key"},
+		{"literal email password", "[email]\nsmtp_host = \"smtp.example.com\"\nfrom = \"gt@example.com\"\npassword = \"hunter2\"", nil, "email.password"},
+		{"bad email digest", "[email]\ndigest = \"weekly\"", nil, "email.digest"},
+		{"bad jira url", "[jira]\nurl = \"example.atlassian.net\"", nil, "jira.url"},
+		{"literal jira token", "[jira]\nurl = \"https://example.atlassian.net\"\ntoken = \"abc123\"", nil, "jira.token"},
+		{"empty jira jql", "[jira.rigs]\ngastown = \" \"", nil, "jira.rigs.gastown"},
+		{"unknown jira stage", "[jira.transitions]\nreviewed = \"Done\"", nil, "jira.transitions.reviewed"},
+		{"literal linear api key", "[linear]\napi_key = \"lin_api_abc\"", nil, "linear.api_key"},
+		{"literal linear webhook secret", "[linear]\nwebhook_secret = \"lin_wh_abc\"", nil, "linear.webhook_secret"},
+		{"linear rig without team", "[linear.rigs.gastown]\nproject = \"Backend\"", nil, "linear.rigs.gastown"},
+		{"unknown linear stage", "[linear.states]\nreviewed = \"Done\"", nil, "linear.states.reviewed"},
+		{"literal pagerduty key", "[alerting.pagerduty]\nrouting_key = \"R0UT1NG\"", nil, "alerting.pagerduty.routing_key"},
+		{"literal opsgenie key", "[alerting.opsgenie]\napi_key = \"abc\"", nil, "alerting.opsgenie.api_key"},
+		{"bad alerting min severity", "[alerting.pagerduty]\nmin_severity = \"urgent\"", nil, "alerting.pagerduty.min_severity"},
+		{"unknown alert condition", "[alerting.severities]\ndisk_full = \"high\"", nil, "alerting.severities.disk_full"},
+		{"bad alert severity", "[alerting.severities]\ndeacon_down = \"p1\"", nil, "alerting.severities.deacon_down"},
+		{"bad wedged_after", "[alerting]\nwedged_after = \"soon\"", nil, "alerting.wedged_after"},
+		{"bad outbound pattern", "[outbound]\npatterns = [\"(\"]", nil, "outbound.patterns"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			townRoot := setupGastownConfig(t, "", tt.toml)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, err := LoadGastownConfig(townRoot)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadGastownConfig() error = %v, want mention of %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestConcurrencyMergedOver(t *testing.T) {
+	base := &ConcurrencyConfig{MaxSessions: 8, Rigs: map[string]int{"gastown": 4}}
+	over := &ConcurrencyConfig{Rigs: map[string]int{"beads": 2}}
+
+	merged := over.MergedOver(base)
+	if merged.MaxSessions != 8 || merged.Rigs["gastown"] != 4 || merged.Rigs["beads"] != 2 {
+		t.Errorf("MergedOver = %+v", merged)
+	}
+	if (*ConcurrencyConfig)(nil).MergedOver(base) != base || over.MergedOver(nil) != over {
+		t.Error("MergedOver with nil should return the other config")
+	}
+}
+
+func TestPolicyForRole(t *testing.T) {
+	townRoot := setupGastownConfig(t, "", `
+[policy]
+paths = ["."]
+deny_commands = ["sudo"]
+
+[policy.roles.polecat]
+deny_commands = ["sudo", "git push --force"]
+allow_hosts = ["github.com"]
+`)
+	cfg, err := LoadGastownConfig(townRoot)
+	if err != nil {
+		t.Fatalf("LoadGastownConfig: %v", err)
+	}
+	polecat := cfg.Policy.ForRole(constants.RolePolecat)
+	if len(polecat.Paths) != 1 || len(polecat.DenyCommands) != 2 || len(polecat.AllowHosts) != 1 {
+		t.Errorf("ForRole(polecat) = %+v, want town paths with polecat commands and hosts", polecat)
+	}
+	if crew := cfg.Policy.ForRole(constants.RoleCrew); len(crew.DenyCommands) != 1 || len(crew.AllowHosts) != 0 {
+		t.Errorf("ForRole(crew) = %+v, want the town-wide policy", crew)
+	}
+
+	for role, want := range map[string]string{
+		constants.RoleWitness:  ProfileObserver,
+		constants.RolePolecat:  ProfileDeveloper,
+		constants.RoleRefinery: ProfileMerger,
+		constants.RoleMayor:    "",
+	} {
+		if got := cfg.Policy.ForRole(role).Profile; got != want {
+			t.Errorf("ForRole(%s).Profile = %q, want %q", role, got, want)
+		}
+	}
+	cfg.Policy.Roles[constants.RoleWitness] = ToolPolicy{Profile: ProfileNone}
+	if prof := cfg.Policy.ForRole(constants.RoleWitness).PermissionProfile(); prof != nil {
+		t.Errorf("profile none = %+v, want no profile", prof)
+	}
+}
+
+func TestBuildStartupCommand_GastownConfig(t *testing.T) {
+	townRoot := setupGastownConfig(t, "", `
+model = "sonnet"
+max_tokens = 32000
+`)
+	rigPath := filepath.Join(townRoot, "testrig")
+
+	cmd := BuildStartupCommand(map[string]string{"GT_ROLE": constants.RoleCrew}, rigPath, "")
+	if !strings.Contains(cmd, `claude --model "sonnet"`) {
+		t.Errorf("expected model flag, got: %q", cmd)
+	}
+	if !strings.Contains(cmd, "CLAUDE_CODE_MAX_OUTPUT_TOKENS=32000") {
+		t.Errorf("expected max tokens env, got: %q", cmd)
+	}
+
+	t.Setenv(EnvRuntime, "codex")
+	cmd = BuildStartupCommand(map[string]string{"GT_ROLE": constants.RoleCrew}, rigPath, "")
+	if !strings.Contains(cmd, `codex --model "sonnet"`) {
+		t.Errorf("expected GT_RUNTIME to select codex, got: %q", cmd)
+	}
+	if strings.Contains(cmd, "CLAUDE_CODE_MAX_OUTPUT_TOKENS") {
+		t.Errorf("expected no claude token env for codex, got: %q", cmd)
+	}
+}
+
+func TestLoadRigGastownConfig(t *testing.T) {
+	townRoot := setupGastownConfig(t, "", `
+runtime = "claude"
+model = "sonnet"
+
+[prompts]
+witness = "Town witness policy."
+polecat = "Town polecat policy."
+
+[activity]
+active = "5m"
+stale = "20m"
+`)
+	rigPath := filepath.Join(townRoot, "docs")
+	if err := os.MkdirAll(rigPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	rigTOML := `
+model = "haiku"
+
+[prompts]
+polecat = "Run make docs before gt done."
+
+[activity]
+stale = "1h"
+
+[server]
+port = 9999
+
+[[merge_queue.checks]]
+name = "links"
+command = "make linkcheck"
+`
+	if err := os.WriteFile(RigGastownConfigPath(rigPath), []byte(rigTOML), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadRigGastownConfig(townRoot, rigPath)
+	if err != nil {
+		t.Fatalf("LoadRigGastownConfig: %v", err)
+	}
+	if cfg.Runtime != "claude" || cfg.Model != "haiku" {
+		t.Errorf("Runtime, Model = %q, %q; want town runtime and rig model", cfg.Runtime, cfg.Model)
+	}
+	if cfg.Prompts["witness"] != "Town witness policy." || cfg.Prompts["polecat"] != "Run make docs before gt done." {
+		t.Errorf("Prompts = %v, want rig polecat prompt over town prompts", cfg.Prompts)
+	}
+	if active, stale := cfg.Activity.Durations(); active != 5*time.Minute || stale != time.Hour {
+		t.Errorf("Activity = %v/%v, want 5m/1h", active, stale)
+	}
+	if cfg.Server.Port != DefaultServerPort {
+		t.Errorf("Server.Port = %d, want rig file's server ignored", cfg.Server.Port)
+	}
+	if cfg.MergeQueue == nil || len(cfg.MergeQueue.Checks) != 1 || cfg.MergeQueue.Checks[0].Name != "links" {
+		t.Errorf("MergeQueue = %+v, want rig checks", cfg.MergeQueue)
+	}
+
+	// The rig's model reaches session startup
+	cmd := BuildStartupCommand(map[string]string{"GT_ROLE": constants.RolePolecat}, rigPath, "")
+	if !strings.Contains(cmd, `--model "haiku"`) {
+		t.Errorf("expected rig model in startup command, got: %q", cmd)
+	}
+
+	// Other rigs keep the town settings
+	other, err := LoadRigGastownConfig(townRoot, filepath.Join(townRoot, "other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Model != "sonnet" || other.MergeQueue != nil {
+		t.Errorf("other rig = %+v, want town settings", other)
+	}
+}
+
+func TestLinearRigMatches(t *testing.T) {
+	r := LinearRig{Team: "ENG", Project: "Backend", Labels: []string{"polecat"}}
+	tests := []struct {
+		name          string
+		team, project string
+		labels        []string
+		want          bool
+	}{
+		{"match", "eng", "backend", []string{"Bug", "Polecat"}, true},
+		{"other team", "OPS", "Backend", []string{"polecat"}, false},
+		{"other project", "ENG", "Frontend", []string{"polecat"}, false},
+		{"no label", "ENG", "Backend", []string{"Bug"}, false},
+	}
+	for _, tt := range tests {
+		if got := r.Matches(tt.team, tt.project, tt.labels); got != tt.want {
+			t.Errorf("%s: Matches = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if !(LinearRig{Team: "ENG"}).Matches("ENG", "", nil) {
+		t.Error("a rig with only a team should match all of the team's issues")
+	}
+}
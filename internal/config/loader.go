@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/mail"
+	"net/url"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -212,6 +215,268 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if c.PullRequests != nil {
+		if err := validatePullRequestConfig(c.PullRequests); err != nil {
+			return err
+		}
+	}
+	if c.Forge != nil {
+		if err := validateForgeConfig(c.Forge); err != nil {
+			return err
+		}
+	}
+	if c.Budget != nil {
+		if err := ValidateBudgetConfig(c.Budget); err != nil {
+			return err
+		}
+	}
+	if c.Email != nil {
+		if err := validateEmailRouting(*c.Email); err != nil {
+			return err
+		}
+	}
+	for _, dir := range c.SparseCheckout {
+		if err := validateSparsePath(dir); err != nil {
+			return err
+		}
+	}
+	for _, pattern := range c.ProtectedBranches {
+		if err := validateBranchPattern(pattern); err != nil {
+			return err
+		}
+	}
+	for key, p := range c.Prompts {
+		if err := validateWorkPromptConfig(key, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateSparsePath validates a sparse_checkout directory: it must be a
+// path inside the repo.
+func validateSparsePath(dir string) error {
+	clean := filepath.Clean(dir)
+	if strings.TrimSpace(dir) == "" || clean == "." {
+		return fmt.Errorf("%w: sparse_checkout entry", ErrMissingField)
+	}
+	if filepath.IsAbs(dir) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("sparse_checkout %q: must be a directory inside the repo", dir)
+	}
+	return nil
+}
+
+// validateBranchPattern validates a protected_branches entry: a branch name
+// or path.Match pattern such as "release/*".
+func validateBranchPattern(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("%w: protected_branches entry", ErrMissingField)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("protected_branches %q: %w", pattern, err)
+	}
+	return nil
+}
+
+// validateWorkPromptConfig validates a rig's prompt entry for a bead type.
+func validateWorkPromptConfig(key string, c *WorkPromptConfig) error {
+	if key == "" {
+		return fmt.Errorf("%w: prompts key (bead type)", ErrMissingField)
+	}
+	if c == nil {
+		return fmt.Errorf("%w: prompts[%s]", ErrMissingField, key)
+	}
+	if c.Template != "" && c.TemplateFile != "" {
+		return fmt.Errorf("prompts[%s]: template and template_file are mutually exclusive", key)
+	}
+	if filepath.IsAbs(c.TemplateFile) || strings.HasPrefix(filepath.Clean(c.TemplateFile), "..") {
+		return fmt.Errorf("prompts[%s]: template_file must be relative to the rig's settings directory", key)
+	}
+	return nil
+}
+
+// ValidateBudgetConfig validates a BudgetConfig.
+func ValidateBudgetConfig(c *BudgetConfig) error {
+	if c.DailyUSD < 0 || c.WeeklyUSD < 0 || c.DailyTokens < 0 || c.WeeklyTokens < 0 {
+		return fmt.Errorf("invalid budget: caps must not be negative")
+	}
+	for _, a := range c.Actions {
+		switch a {
+		case BudgetActionBlockSpawn, BudgetActionPauseSessions, BudgetActionAlert:
+		default:
+			return fmt.Errorf("invalid budget action: got '%s', want '%s', '%s', or '%s'",
+				a, BudgetActionBlockSpawn, BudgetActionPauseSessions, BudgetActionAlert)
+		}
+	}
+	return nil
+}
+
+// validateEmailRouting validates an EmailRouting.
+func validateEmailRouting(r EmailRouting) error {
+	for _, addr := range r.To {
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return fmt.Errorf("invalid email.to: %q: %w", addr, err)
+		}
+	}
+	for _, kind := range r.Events {
+		if !containsString(EmailEventKinds, kind) {
+			return fmt.Errorf("invalid email.events: unknown kind %q: want one of %v", kind, EmailEventKinds)
+		}
+	}
+	switch r.Digest {
+	case "", EmailDigestHourly, EmailDigestDaily:
+	default:
+		return fmt.Errorf("invalid email.digest: got '%s', want '%s' or '%s'", r.Digest, EmailDigestHourly, EmailDigestDaily)
+	}
+	return nil
+}
+
+// validateSessionNamingConfig validates a SessionNamingConfig.
+func validateSessionNamingConfig(c *SessionNamingConfig) error {
+	prefix, hqPrefix := c.Prefix, c.HQPrefix
+	if prefix == "" {
+		prefix = "gt-"
+	}
+	if hqPrefix == "" {
+		hqPrefix = "hq-"
+	}
+	for _, p := range []string{prefix, hqPrefix} {
+		if strings.ContainsAny(p, ".: \t") {
+			return fmt.Errorf("invalid sessions: prefix %q must not contain '.', ':' or whitespace", p)
+		}
+	}
+	// Names are classified by prefix, so neither may shadow the other
+	if strings.HasPrefix(prefix, hqPrefix) || strings.HasPrefix(hqPrefix, prefix) {
+		return fmt.Errorf("invalid sessions: prefix %q and hq_prefix %q must not overlap", prefix, hqPrefix)
+	}
+	return nil
+}
+
+// validateHibernationConfig validates a HibernationConfig.
+func validateHibernationConfig(c *HibernationConfig) error {
+	if c.IdleAfter != "" {
+		d, err := time.ParseDuration(c.IdleAfter)
+		if err != nil {
+			return fmt.Errorf("invalid hibernation idle_after: %w", err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("invalid hibernation idle_after: must be positive")
+		}
+	}
+	return nil
+}
+
+// validateFederationConfig validates a FederationConfig.
+func validateFederationConfig(c *FederationConfig) error {
+	for name, town := range c.Towns {
+		if town == nil || town.URL == "" {
+			return fmt.Errorf("invalid federation: town '%s' has no url", name)
+		}
+		if HasRefs(town.URL) {
+			continue // resolved where used (see ResolveRef)
+		}
+		u, err := url.Parse(town.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid federation: town '%s' url %q must be an http(s) URL", name, town.URL)
+		}
+	}
+	return nil
+}
+
+// validateSLAConfig validates an SLAConfig.
+func validateSLAConfig(c *SLAConfig) error {
+	for key, target := range c.Priorities {
+		switch key {
+		case "P0", "P1", "P2", "P3", "P4":
+		default:
+			return fmt.Errorf("invalid sla: priority '%s' must be P0 through P4", key)
+		}
+		if target == nil {
+			continue
+		}
+		start, err := parseSLADuration(key, "start_within", target.StartWithin)
+		if err != nil {
+			return err
+		}
+		finish, err := parseSLADuration(key, "finish_within", target.FinishWithin)
+		if err != nil {
+			return err
+		}
+		if start > 0 && finish > 0 && finish < start {
+			return fmt.Errorf("invalid sla %s: finish_within must not be shorter than start_within", key)
+		}
+	}
+	return nil
+}
+
+// validateToolsets checks that role toolset overrides name built-in toolsets.
+func validateToolsets(toolsets map[string][]string) error {
+	for role, names := range toolsets {
+		for _, name := range names {
+			if GetToolset(name) == nil {
+				return fmt.Errorf("invalid toolsets: role '%s' has unknown toolset '%s' (known: %s)",
+					role, name, strings.Join(ListToolsets(), ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// parseSLADuration parses one SLA deadline. Empty means no deadline.
+func parseSLADuration(key, field, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sla %s %s: %w", key, field, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid sla %s %s: must be positive", key, field)
+	}
+	return d, nil
+}
+
+// validateConcurrencyConfig validates a ConcurrencyConfig.
+func validateConcurrencyConfig(c *ConcurrencyConfig) error {
+	if c.MaxSessions < 0 {
+		return fmt.Errorf("invalid concurrency: max_sessions must not be negative")
+	}
+	for role, n := range c.Roles {
+		if n < 0 {
+			return fmt.Errorf("invalid concurrency: limit for role '%s' must not be negative", role)
+		}
+	}
+	for rig, n := range c.Rigs {
+		if n < 0 {
+			return fmt.Errorf("invalid concurrency: limit for rig '%s' must not be negative", rig)
+		}
+	}
+	return nil
+}
+
+// validatePullRequestConfig validates a PullRequestConfig.
+func validatePullRequestConfig(c *PullRequestConfig) error {
+	switch c.Provider {
+	case "", PRProviderGitHub, PRProviderGitLab, PRProviderGitea:
+		return nil
+	default:
+		return fmt.Errorf("invalid pull_requests provider: got '%s', want '%s', '%s', or '%s'",
+			c.Provider, PRProviderGitHub, PRProviderGitLab, PRProviderGitea)
+	}
+}
+
+// validateForgeConfig validates a ForgeConfig.
+func validateForgeConfig(c *ForgeConfig) error {
+	switch c.Type {
+	case "", ForgeGitHub, ForgeGitLab, ForgeGitea:
+	default:
+		return fmt.Errorf("invalid forge type: got '%s', want '%s', '%s', or '%s'",
+			c.Type, ForgeGitHub, ForgeGitLab, ForgeGitea)
+	}
+	if c.URL != "" && !HasRefs(c.URL) && !strings.HasPrefix(c.URL, "https://") && !strings.HasPrefix(c.URL, "http://") {
+		return fmt.Errorf("invalid forge url: %q must start with http:// or https://", c.URL)
+	}
 	return nil
 }
 
@@ -221,9 +486,25 @@ var ErrInvalidOnConflict = errors.New("invalid on_conflict strategy")
 // validateMergeQueueConfig validates a MergeQueueConfig.
 func validateMergeQueueConfig(c *MergeQueueConfig) error {
 	// Validate on_conflict strategy
-	if c.OnConflict != "" && c.OnConflict != OnConflictAssignBack && c.OnConflict != OnConflictAutoRebase {
-		return fmt.Errorf("%w: got '%s', want '%s' or '%s'",
-			ErrInvalidOnConflict, c.OnConflict, OnConflictAssignBack, OnConflictAutoRebase)
+	switch c.OnConflict {
+	case "", OnConflictAssignBack, OnConflictAutoRebase, OnConflictAgentResolve:
+	default:
+		return fmt.Errorf("%w: got '%s', want '%s', '%s', or '%s'",
+			ErrInvalidOnConflict, c.OnConflict, OnConflictAssignBack, OnConflictAutoRebase, OnConflictAgentResolve)
+	}
+
+	// Validate conflict_timeout if specified
+	if c.ConflictTimeout != "" {
+		if _, err := time.ParseDuration(c.ConflictTimeout); err != nil {
+			return fmt.Errorf("invalid conflict_timeout: %w", err)
+		}
+	}
+
+	// Validate review_timeout if specified
+	if c.ReviewTimeout != "" {
+		if _, err := time.ParseDuration(c.ReviewTimeout); err != nil {
+			return fmt.Errorf("invalid review_timeout: %w", err)
+		}
 	}
 
 	// Validate poll_interval if specified
@@ -240,6 +521,35 @@ func validateMergeQueueConfig(c *MergeQueueConfig) error {
 	if c.MaxConcurrent < 0 {
 		return fmt.Errorf("%w: max_concurrent must be non-negative", ErrMissingField)
 	}
+	if c.BatchSize < 0 {
+		return fmt.Errorf("%w: batch_size must be non-negative", ErrMissingField)
+	}
+
+	seen := make(map[string]bool)
+	for i, check := range c.Checks {
+		if check.Name == "" {
+			return fmt.Errorf("%w: checks[%d].name", ErrMissingField, i)
+		}
+		if seen[check.Name] {
+			return fmt.Errorf("duplicate check name %q", check.Name)
+		}
+		seen[check.Name] = true
+		switch check.Type {
+		case "", MergeCheckCommand:
+			if check.Command == "" {
+				return fmt.Errorf("%w: checks[%s].command", ErrMissingField, check.Name)
+			}
+		case MergeCheckForge, MergeCheckGitHub:
+		default:
+			return fmt.Errorf("invalid type %q for check %s: want '%s' or '%s'",
+				check.Type, check.Name, MergeCheckCommand, MergeCheckForge)
+		}
+		if check.Timeout != "" {
+			if _, err := time.ParseDuration(check.Timeout); err != nil {
+				return fmt.Errorf("invalid timeout for check %s: %w", check.Name, err)
+			}
+		}
+	}
 
 	return nil
 }
@@ -264,7 +574,8 @@ func NewRigSettings() *RigSettings {
 	}
 }
 
-// LoadRigSettings loads and validates a rig settings file.
+// LoadRigSettings loads and validates a rig settings file. References in
+// its values are kept as written (see ResolveRef).
 func LoadRigSettings(path string) (*RigSettings, error) {
 	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally, not from user input
 	if err != nil {
@@ -502,8 +813,30 @@ func validateAccountsConfig(c *AccountsConfig) error {
 	}
 	// Validate each account has required fields
 	for handle, acct := range c.Accounts {
-		if acct.ConfigDir == "" {
-			return fmt.Errorf("%w: config_dir for account '%s'", ErrMissingField, handle)
+		if acct.ConfigDir == "" && acct.APIKeyEnv == "" {
+			return fmt.Errorf("%w: config_dir or api_key_env for account '%s'", ErrMissingField, handle)
+		}
+	}
+	for role, handle := range c.Roles {
+		if _, ok := c.Accounts[handle]; !ok {
+			return fmt.Errorf("account '%s' assigned to role '%s' not found in accounts", handle, role)
+		}
+	}
+	for rig, handle := range c.Rigs {
+		if _, ok := c.Accounts[handle]; !ok {
+			return fmt.Errorf("account '%s' assigned to rig '%s' not found in accounts", handle, rig)
+		}
+	}
+	if r := c.Rotation; r != nil {
+		for _, handle := range r.Pool {
+			if _, ok := c.Accounts[handle]; !ok {
+				return fmt.Errorf("rotation pool account '%s' not found in accounts", handle)
+			}
+		}
+		if r.Cooldown != "" {
+			if d, err := time.ParseDuration(r.Cooldown); err != nil || d <= 0 {
+				return fmt.Errorf("invalid rotation cooldown %q: must be a positive duration", r.Cooldown)
+			}
 		}
 	}
 	return nil
@@ -525,6 +858,11 @@ func (c *AccountsConfig) GetAccount(handle string) *Account {
 	return nil
 }
 
+// ConfigDirPath returns the account's config_dir with ~ expanded.
+func (a *Account) ConfigDirPath() string {
+	return expandPath(a.ConfigDir)
+}
+
 // GetDefaultAccount returns the default account, or nil if not set.
 func (c *AccountsConfig) GetDefaultAccount() *Account {
 	if c.Default == "" {
@@ -741,6 +1079,7 @@ func RigSettingsPath(rigPath string) string {
 }
 
 // LoadOrCreateTownSettings loads town settings or creates defaults if missing.
+// References in its values are kept as written (see ResolveRef).
 func LoadOrCreateTownSettings(path string) (*TownSettings, error) {
 	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
 	if err != nil {
@@ -754,6 +1093,34 @@ func LoadOrCreateTownSettings(path string) (*TownSettings, error) {
 	if err := json.Unmarshal(data, &settings); err != nil {
 		return nil, err
 	}
+	if settings.Concurrency != nil {
+		if err := validateConcurrencyConfig(settings.Concurrency); err != nil {
+			return nil, err
+		}
+	}
+	if settings.Sessions != nil {
+		if err := validateSessionNamingConfig(settings.Sessions); err != nil {
+			return nil, err
+		}
+	}
+	if settings.Hibernation != nil {
+		if err := validateHibernationConfig(settings.Hibernation); err != nil {
+			return nil, err
+		}
+	}
+	if settings.Federation != nil {
+		if err := validateFederationConfig(settings.Federation); err != nil {
+			return nil, err
+		}
+	}
+	if settings.SLA != nil {
+		if err := validateSLAConfig(settings.SLA); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateToolsets(settings.Toolsets); err != nil {
+		return nil, err
+	}
 	return &settings, nil
 }
 
@@ -824,10 +1191,8 @@ func ResolveAgentConfig(townRoot, rigPath string) *RuntimeConfig {
 	agentName := ""
 	if rigSettings != nil && rigSettings.Agent != "" {
 		agentName = rigSettings.Agent
-	} else if townSettings.DefaultAgent != "" {
-		agentName = townSettings.DefaultAgent
 	} else {
-		agentName = "claude" // ultimate fallback
+		agentName = townDefaultAgent(townRoot, rigPath, townSettings)
 	}
 
 	return lookupAgentConfig(agentName, townSettings, rigSettings)
@@ -868,10 +1233,8 @@ func ResolveAgentConfigWithOverride(townRoot, rigPath, agentOverride string) (*R
 		agentName = agentOverride
 	} else if rigSettings != nil && rigSettings.Agent != "" {
 		agentName = rigSettings.Agent
-	} else if townSettings.DefaultAgent != "" {
-		agentName = townSettings.DefaultAgent
 	} else {
-		agentName = "claude" // ultimate fallback
+		agentName = townDefaultAgent(townRoot, rigPath, townSettings)
 	}
 
 	// If an override is requested, validate it exists
@@ -1042,10 +1405,20 @@ func ResolveRoleAgentName(role, townRoot, rigPath string) (agentName string, isR
 	if rigSettings != nil && rigSettings.Agent != "" {
 		return rigSettings.Agent, false
 	}
+	return townDefaultAgent(townRoot, rigPath, townSettings), false
+}
+
+// townDefaultAgent returns the default agent for a rig (or the town, if
+// rigPath is empty): the runtime from gastown.toml or GT_RUNTIME if set, then
+// default_agent from town settings, then "claude".
+func townDefaultAgent(townRoot, rigPath string, townSettings *TownSettings) string {
+	if cfg, err := LoadRigGastownConfig(townRoot, rigPath); err == nil && cfg.Runtime != "" {
+		return cfg.Runtime
+	}
 	if townSettings.DefaultAgent != "" {
-		return townSettings.DefaultAgent, false
+		return townSettings.DefaultAgent
 	}
-	return "claude", false
+	return "claude"
 }
 
 // lookupAgentConfig looks up an agent by name.
@@ -1240,6 +1613,16 @@ func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) stri
 		}
 	}
 
+	agentName := ""
+	if townRoot != "" {
+		agentName, _ = ResolveRoleAgentName(role, townRoot, rigPath)
+	}
+
+	// Grant the role's built-in toolsets
+	if role != "" && townRoot != "" {
+		rc = withRoleTools(rc, agentName, role, townRoot)
+	}
+
 	// Copy env vars to avoid mutating caller map
 	resolvedEnv := make(map[string]string, len(envVars)+2)
 	for k, v := range envVars {
@@ -1249,6 +1632,10 @@ func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) stri
 	if townRoot != "" {
 		resolvedEnv["GT_ROOT"] = townRoot
 	}
+	// Apply the operator's model and token limit from gastown.toml
+	if townRoot != "" {
+		rc = withGastownDefaults(rc, resolvedEnv, agentName, townRoot, rigPath)
+	}
 	if rc.Session != nil && rc.Session.SessionIDEnv != "" {
 		resolvedEnv["GT_SESSION_ID_ENV"] = rc.Session.SessionIDEnv
 	}
@@ -1341,6 +1728,16 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 		}
 	}
 
+	agentName := agentOverride
+	if agentName == "" && townRoot != "" {
+		agentName, _ = ResolveRoleAgentName(role, townRoot, rigPath)
+	}
+
+	// Grant the role's built-in toolsets
+	if role != "" && townRoot != "" {
+		rc = withRoleTools(rc, agentName, role, townRoot)
+	}
+
 	// Copy env vars to avoid mutating caller map
 	resolvedEnv := make(map[string]string, len(envVars)+2)
 	for k, v := range envVars {
@@ -1350,6 +1747,10 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 	if townRoot != "" {
 		resolvedEnv["GT_ROOT"] = townRoot
 	}
+	// Apply the operator's model and token limit from gastown.toml
+	if townRoot != "" {
+		rc = withGastownDefaults(rc, resolvedEnv, agentName, townRoot, rigPath)
+	}
 	if rc.Session != nil && rc.Session.SessionIDEnv != "" {
 		resolvedEnv["GT_SESSION_ID_ENV"] = rc.Session.SessionIDEnv
 	}
@@ -1517,6 +1918,7 @@ func EscalationConfigPath(townRoot string) string {
 }
 
 // LoadEscalationConfig loads and validates an escalation configuration file.
+// References in its contacts are kept as written (see ResolveRef).
 func LoadEscalationConfig(path string) (*EscalationConfig, error) {
 	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally, not from user input
 	if err != nil {
@@ -1639,3 +2041,118 @@ func (c *EscalationConfig) GetMaxReescalations() int {
 	}
 	return c.MaxReescalations
 }
+
+// SchedulesConfigPath returns the standard path for scheduled job config in a town.
+func SchedulesConfigPath(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "schedules.json")
+}
+
+// LoadSchedulesConfig loads and validates a scheduled job configuration file.
+func LoadSchedulesConfig(path string) (*SchedulesConfig, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally, not from user input
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		return nil, fmt.Errorf("reading schedules config: %w", err)
+	}
+
+	var config SchedulesConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parsing schedules config: %w", err)
+	}
+
+	if err := ValidateSchedulesConfig(&config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// LoadOrCreateSchedulesConfig loads the schedules config, returning an empty config if not found.
+func LoadOrCreateSchedulesConfig(path string) (*SchedulesConfig, error) {
+	config, err := LoadSchedulesConfig(path)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return NewSchedulesConfig(), nil
+		}
+		return nil, err
+	}
+	return config, nil
+}
+
+// SaveSchedulesConfig saves a scheduled job configuration to a file.
+func SaveSchedulesConfig(path string, config *SchedulesConfig) error {
+	if err := ValidateSchedulesConfig(config); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding schedules config: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: schedules config doesn't contain secrets
+		return fmt.Errorf("writing schedules config: %w", err)
+	}
+
+	return nil
+}
+
+// ValidateSchedulesConfig validates a SchedulesConfig.
+// Cron expressions are parsed by the scheduler package when jobs are loaded.
+func ValidateSchedulesConfig(c *SchedulesConfig) error {
+	if c.Type != "schedules" && c.Type != "" {
+		return fmt.Errorf("%w: expected type 'schedules', got '%s'", ErrInvalidType, c.Type)
+	}
+	if c.Version > CurrentSchedulesVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, c.Version, CurrentSchedulesVersion)
+	}
+
+	seen := make(map[string]bool)
+	for i, job := range c.Jobs {
+		if job.Name == "" {
+			return fmt.Errorf("%w: jobs[%d].name", ErrMissingField, i)
+		}
+		if seen[job.Name] {
+			return fmt.Errorf("duplicate job name %q", job.Name)
+		}
+		seen[job.Name] = true
+
+		if job.Cron == "" {
+			return fmt.Errorf("%w: job %q: cron", ErrMissingField, job.Name)
+		}
+		if job.Timeout != "" {
+			if _, err := time.ParseDuration(job.Timeout); err != nil {
+				return fmt.Errorf("job %q: invalid timeout: %w", job.Name, err)
+			}
+		}
+
+		switch job.Action {
+		case ScheduleActionNudge:
+			if job.Target == "" || job.Message == "" {
+				return fmt.Errorf("%w: job %q: nudge requires target and message", ErrMissingField, job.Name)
+			}
+		case ScheduleActionMail:
+			if job.Target == "" || job.Subject == "" {
+				return fmt.Errorf("%w: job %q: mail requires target and subject", ErrMissingField, job.Name)
+			}
+		case ScheduleActionSling:
+			if job.Bead == "" {
+				return fmt.Errorf("%w: job %q: sling requires bead", ErrMissingField, job.Name)
+			}
+		case ScheduleActionCommand:
+			if job.Command == "" {
+				return fmt.Errorf("%w: job %q: command requires command", ErrMissingField, job.Name)
+			}
+		default:
+			return fmt.Errorf("job %q: unknown action %q (valid: nudge, mail, sling, command)", job.Name, job.Action)
+		}
+	}
+
+	return nil
+}
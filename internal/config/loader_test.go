@@ -312,6 +312,51 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid sparse_checkout",
+			settings: &RigSettings{
+				Type:           "rig-settings",
+				Version:        1,
+				SparseCheckout: []string{"services/api", "libs/"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "sparse_checkout outside repo",
+			settings: &RigSettings{
+				Type:           "rig-settings",
+				Version:        1,
+				SparseCheckout: []string{"../other"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty sparse_checkout entry",
+			settings: &RigSettings{
+				Type:           "rig-settings",
+				Version:        1,
+				SparseCheckout: []string{""},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid protected_branches",
+			settings: &RigSettings{
+				Type:              "rig-settings",
+				Version:           1,
+				ProtectedBranches: []string{"develop", "release/*"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "malformed protected_branches pattern",
+			settings: &RigSettings{
+				Type:              "rig-settings",
+				Version:           1,
+				ProtectedBranches: []string{"release/["},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid on_conflict",
 			settings: &RigSettings{
@@ -323,6 +368,18 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid review_timeout",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					Review:        true,
+					ReviewTimeout: "soon",
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid poll_interval",
 			settings: &RigSettings{
@@ -334,6 +391,75 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid budget",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Budget: &BudgetConfig{
+					DailyUSD: 50,
+					Actions:  []string{BudgetActionBlockSpawn, BudgetActionAlert},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid budget action",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Budget: &BudgetConfig{
+					DailyUSD: 50,
+					Actions:  []string{"shutdown"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid email",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Email:   &EmailRouting{To: []string{"Ops <ops@example.com>"}, Events: []string{"budget"}, Digest: EmailDigestDaily},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid email address",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Email:   &EmailRouting{To: []string{"not an address"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid forge",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Forge:   &ForgeConfig{Type: ForgeGitea, URL: "https://git.example.com"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid forge type",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Forge:   &ForgeConfig{Type: "bitbucket"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid forge url",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Forge:   &ForgeConfig{Type: ForgeGitea, URL: "git.example.com"},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -536,6 +662,37 @@ func TestAccountsConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "api key account with assignments and rotation",
+			config: &AccountsConfig{
+				Version: 1,
+				Accounts: map[string]Account{
+					"team": {APIKeyEnv: "TEAM_API_KEY"},
+				},
+				Roles:    map[string]string{"polecat": "team"},
+				Rigs:     map[string]string{"docs": "team"},
+				Rotation: &AccountRotationConfig{Enabled: true, Pool: []string{"team"}, Cooldown: "30m"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "role assigned to nonexistent account",
+			config: &AccountsConfig{
+				Version:  1,
+				Accounts: map[string]Account{"team": {APIKeyEnv: "TEAM_API_KEY"}},
+				Roles:    map[string]string{"polecat": "nonexistent"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid rotation cooldown",
+			config: &AccountsConfig{
+				Version:  1,
+				Accounts: map[string]Account{"team": {APIKeyEnv: "TEAM_API_KEY"}},
+				Rotation: &AccountRotationConfig{Enabled: true, Cooldown: "soon"},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1822,6 +1979,58 @@ func TestSaveTownSettings(t *testing.T) {
 			t.Errorf("Agents count = %d, want %d", len(loaded.Agents), len(original.Agents))
 		}
 	})
+
+	t.Run("rejects negative concurrency limits", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		settingsPath := filepath.Join(tmpDir, "config.json")
+		data := `{"type": "town-settings", "version": 1, "concurrency": {"roles": {"polecat": -1}}}`
+		if err := os.WriteFile(settingsPath, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := LoadOrCreateTownSettings(settingsPath); err == nil {
+			t.Error("expected error for negative role limit")
+		}
+	})
+
+	t.Run("rejects overlapping session prefixes", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		settingsPath := filepath.Join(tmpDir, "config.json")
+		data := `{"type": "town-settings", "version": 1, "sessions": {"prefix": "gt-", "hq_prefix": "gt-hq-"}}`
+		if err := os.WriteFile(settingsPath, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := LoadOrCreateTownSettings(settingsPath); err == nil {
+			t.Error("expected error for overlapping prefixes")
+		}
+	})
+
+	t.Run("rejects invalid hibernation idle_after", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		settingsPath := filepath.Join(tmpDir, "config.json")
+		data := `{"type": "town-settings", "version": 1, "hibernation": {"enabled": true, "idle_after": "soon"}}`
+		if err := os.WriteFile(settingsPath, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := LoadOrCreateTownSettings(settingsPath); err == nil {
+			t.Error("expected error for invalid idle_after")
+		}
+	})
+
+	t.Run("rejects federated town without http url", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		settingsPath := filepath.Join(tmpDir, "config.json")
+		data := `{"type": "town-settings", "version": 1, "federation": {"towns": {"west": {"url": "west-box:8080"}}}}`
+		if err := os.WriteFile(settingsPath, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := LoadOrCreateTownSettings(settingsPath); err == nil {
+			t.Error("expected error for non-http federation url")
+		}
+	})
 }
 
 func TestGetDefaultFormula(t *testing.T) {
@@ -2596,3 +2805,276 @@ func TestBuildStartupCommandWithAgentOverride_IncludesGTRoot(t *testing.T) {
 		t.Errorf("expected GT_ROOT=%s in command, got: %q", townRoot, cmd)
 	}
 }
+
+func TestSchedulesConfigRoundTrip(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "settings", "schedules.json")
+
+	original := NewSchedulesConfig()
+	original.Jobs = []ScheduledJob{
+		{Name: "triage", Cron: "@hourly", Action: ScheduleActionNudge, Target: "gastown/witness", Message: "Triage new beads"},
+		{Name: "deps", Cron: "0 2 * * *", Action: ScheduleActionSling, Bead: "mol-deps-update", Target: "gastown", Timeout: "30m"},
+	}
+
+	if err := SaveSchedulesConfig(path, original); err != nil {
+		t.Fatalf("SaveSchedulesConfig: %v", err)
+	}
+
+	loaded, err := LoadSchedulesConfig(path)
+	if err != nil {
+		t.Fatalf("LoadSchedulesConfig: %v", err)
+	}
+
+	if loaded.Type != "schedules" {
+		t.Errorf("Type = %q, want 'schedules'", loaded.Type)
+	}
+	if len(loaded.Jobs) != 2 {
+		t.Fatalf("Jobs count = %d, want 2", len(loaded.Jobs))
+	}
+	if loaded.Jobs[1].GetTimeout() != 30*time.Minute {
+		t.Errorf("GetTimeout = %v, want 30m", loaded.Jobs[1].GetTimeout())
+	}
+	if loaded.Jobs[0].GetTimeout() != time.Hour {
+		t.Errorf("default GetTimeout = %v, want 1h", loaded.Jobs[0].GetTimeout())
+	}
+}
+
+func TestSchedulesConfigValidation(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		jobs    []ScheduledJob
+		wantErr bool
+	}{
+		{
+			name: "valid command job",
+			jobs: []ScheduledJob{{Name: "cleanup", Cron: "0 4 * * 0", Action: ScheduleActionCommand, Command: "gt doctor"}},
+		},
+		{
+			name:    "missing name",
+			jobs:    []ScheduledJob{{Cron: "@daily", Action: ScheduleActionCommand, Command: "true"}},
+			wantErr: true,
+		},
+		{
+			name:    "missing cron",
+			jobs:    []ScheduledJob{{Name: "x", Action: ScheduleActionCommand, Command: "true"}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate names",
+			jobs: []ScheduledJob{
+				{Name: "x", Cron: "@daily", Action: ScheduleActionCommand, Command: "true"},
+				{Name: "x", Cron: "@hourly", Action: ScheduleActionCommand, Command: "true"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "nudge without target",
+			jobs:    []ScheduledJob{{Name: "x", Cron: "@daily", Action: ScheduleActionNudge, Message: "hi"}},
+			wantErr: true,
+		},
+		{
+			name:    "sling without bead",
+			jobs:    []ScheduledJob{{Name: "x", Cron: "@daily", Action: ScheduleActionSling}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown action",
+			jobs:    []ScheduledJob{{Name: "x", Cron: "@daily", Action: "explode"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid timeout",
+			jobs:    []ScheduledJob{{Name: "x", Cron: "@daily", Action: ScheduleActionCommand, Command: "true", Timeout: "soon"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewSchedulesConfig()
+			cfg.Jobs = tt.jobs
+			err := ValidateSchedulesConfig(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSchedulesConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchedulesConfigPath(t *testing.T) {
+	t.Parallel()
+
+	path := SchedulesConfigPath("/home/user/gt")
+	expected := "/home/user/gt/settings/schedules.json"
+	if path != expected {
+		t.Errorf("SchedulesConfigPath = %q, want %q", path, expected)
+	}
+}
+
+func TestMergeQueueConfigChecksValidation(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		checks  []MergeCheckConfig
+		wantErr bool
+	}{
+		{
+			name: "valid command and forge checks",
+			checks: []MergeCheckConfig{
+				{Name: "lint", Command: "golangci-lint run", Timeout: "10m"},
+				{Name: "ci", Type: MergeCheckForge, Required: []string{"build"}},
+				{Name: "gh", Type: MergeCheckGitHub},
+			},
+		},
+		{name: "missing name", checks: []MergeCheckConfig{{Command: "make lint"}}, wantErr: true},
+		{name: "command check without command", checks: []MergeCheckConfig{{Name: "lint"}}, wantErr: true},
+		{name: "unknown type", checks: []MergeCheckConfig{{Name: "x", Type: "jenkins"}}, wantErr: true},
+		{name: "invalid timeout", checks: []MergeCheckConfig{{Name: "x", Command: "true", Timeout: "forever"}}, wantErr: true},
+		{
+			name:    "duplicate names",
+			checks:  []MergeCheckConfig{{Name: "x", Command: "true"}, {Name: "x", Command: "false"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultMergeQueueConfig()
+			cfg.Checks = tt.checks
+			err := validateMergeQueueConfig(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateMergeQueueConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMergeQueueConfigBatchSizeValidation(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultMergeQueueConfig()
+	cfg.BatchSize = 5
+	if err := validateMergeQueueConfig(cfg); err != nil {
+		t.Errorf("batch_size 5: unexpected error %v", err)
+	}
+	cfg.BatchSize = -1
+	if err := validateMergeQueueConfig(cfg); err == nil {
+		t.Error("batch_size -1: expected error")
+	}
+}
+
+func TestWorkPromptConfigValidation(t *testing.T) {
+	t.Parallel()
+
+	if err := validateWorkPromptConfig("bug", &WorkPromptConfig{TemplateFile: "prompts/bug.md.tmpl"}); err != nil {
+		t.Errorf("template_file: unexpected error %v", err)
+	}
+	for name, c := range map[string]*WorkPromptConfig{
+		"both":     {Template: "x", TemplateFile: "x.tmpl"},
+		"absolute": {TemplateFile: "/etc/prompt.tmpl"},
+		"escapes":  {TemplateFile: "../prompt.tmpl"},
+	} {
+		if err := validateWorkPromptConfig("bug", c); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestSLAConfigValidation(t *testing.T) {
+	t.Parallel()
+
+	valid := &SLAConfig{Priorities: map[string]*SLATarget{
+		"P0": {StartWithin: "15m", FinishWithin: "4h"},
+		"P2": {FinishWithin: "72h"},
+	}}
+	if err := validateSLAConfig(valid); err != nil {
+		t.Errorf("valid: unexpected error %v", err)
+	}
+	if start, finish := valid.Target(0).Durations(); start != 15*time.Minute || finish != 4*time.Hour {
+		t.Errorf("P0 durations = %v, %v", start, finish)
+	}
+	if valid.Target(1) != nil {
+		t.Error("P1: expected no target")
+	}
+
+	for name, target := range map[string]map[string]*SLATarget{
+		"bad key":      {"high": {StartWithin: "1h"}},
+		"bad duration": {"P1": {StartWithin: "soon"}},
+		"negative":     {"P1": {FinishWithin: "-1h"}},
+		"inverted":     {"P1": {StartWithin: "8h", FinishWithin: "1h"}},
+	} {
+		if err := validateSLAConfig(&SLAConfig{Priorities: target}); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestBuildStartupCommand_GrantsRoleToolsets(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+
+	townSettings := NewTownSettings()
+	townSettings.Toolsets = map[string][]string{constants.RoleWitness: {}}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), townSettings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+
+	t.Run("refinery gets merge-queue tools before its args", func(t *testing.T) {
+		cmd := BuildStartupCommand(map[string]string{"GT_ROLE": constants.RoleRefinery}, rigPath, "Process the queue")
+		if !strings.Contains(cmd, `--allowedTools "Bash(bd show:*)"`) || !strings.Contains(cmd, `"Bash(gt mq:*)"`) {
+			t.Fatalf("expected merge-queue grants, got: %q", cmd)
+		}
+		if strings.Index(cmd, "--allowedTools") > strings.Index(cmd, "--dangerously-skip-permissions") {
+			t.Errorf("expected grants before runtime args, got: %q", cmd)
+		}
+		if !strings.HasSuffix(cmd, `"Process the queue"`) {
+			t.Errorf("expected prompt last, got: %q", cmd)
+		}
+	})
+
+	t.Run("town setting disables witness tools", func(t *testing.T) {
+		cmd := BuildStartupCommand(map[string]string{"GT_ROLE": constants.RoleWitness}, rigPath, "")
+		if strings.Contains(cmd, "--allowedTools") {
+			t.Errorf("expected no grants for witness, got: %q", cmd)
+		}
+	})
+
+	t.Run("crew gets no tools", func(t *testing.T) {
+		cmd := BuildStartupCommand(map[string]string{"GT_ROLE": constants.RoleCrew}, rigPath, "")
+		if strings.Contains(cmd, "--allowedTools") {
+			t.Errorf("expected no grants for crew, got: %q", cmd)
+		}
+	})
+}
+
+func TestRoleTools(t *testing.T) {
+	t.Parallel()
+
+	tools := RoleTools(constants.RolePolecat, nil)
+	for _, want := range []string{"Bash(git commit:*)", "Bash(go test:*)", "Edit"} {
+		found := false
+		for _, tool := range tools {
+			found = found || tool == want
+		}
+		if !found {
+			t.Errorf("polecat tools missing %q: %v", want, tools)
+		}
+	}
+
+	// Overlapping toolsets grant each tool once
+	settings := &TownSettings{Toolsets: map[string][]string{constants.RoleRefinery: {ToolsetMergeQueue, ToolsetGit}}}
+	seen := make(map[string]bool)
+	for _, tool := range RoleTools(constants.RoleRefinery, settings) {
+		if seen[tool] {
+			t.Errorf("duplicate tool %q", tool)
+		}
+		seen[tool] = true
+	}
+
+	if err := validateToolsets(map[string][]string{constants.RolePolecat: {"git", "teleport"}}); err == nil {
+		t.Error("expected error for unknown toolset")
+	}
+}
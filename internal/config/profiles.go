@@ -0,0 +1,74 @@
+package config
+
+import "sort"
+
+// PermissionProfile is a named set of the actions a role's agents may take,
+// enforced by the tool policy (gt policy check) and the git push guard.
+type PermissionProfile struct {
+	Name        string
+	Description string
+
+	// ReadOnly denies file edits and commands that change the repo
+	// (git commit, push, merge, rebase, reset, ...).
+	ReadOnly bool
+
+	// Spawn permits starting and directing other agents (gt sling,
+	// gt polecat).
+	Spawn bool
+
+	// PushProtected permits pushing to the rig's protected branches, which
+	// merging requires.
+	PushProtected bool
+}
+
+// Built-in permission profile names. ProfileNone turns a role's default
+// profile off.
+const (
+	ProfileObserver  = "observer"
+	ProfileDeveloper = "developer"
+	ProfileMerger    = "merger"
+	ProfileNone      = "none"
+)
+
+// builtinProfiles are the permission profiles shipped with Gas Town.
+var builtinProfiles = map[string]*PermissionProfile{
+	ProfileObserver: {
+		Name:        ProfileObserver,
+		Description: "Read the repo and spawn polecats; no edits or pushes",
+		ReadOnly:    true,
+		Spawn:       true,
+	},
+	ProfileDeveloper: {
+		Name:        ProfileDeveloper,
+		Description: "Edit code and run tests; no pushes to protected branches",
+	},
+	ProfileMerger: {
+		Name:          ProfileMerger,
+		Description:   "Merge and push, including to protected branches",
+		PushProtected: true,
+	},
+}
+
+// defaultRoleProfiles maps roles to the profile their agents get unless the
+// policy names another. Roles not listed (mayor, deacon, crew) get none.
+var defaultRoleProfiles = map[string]string{
+	"witness":  ProfileObserver,
+	"polecat":  ProfileDeveloper,
+	"refinery": ProfileMerger,
+}
+
+// GetPermissionProfile returns the built-in profile with the given name, or
+// nil.
+func GetPermissionProfile(name string) *PermissionProfile {
+	return builtinProfiles[name]
+}
+
+// ListPermissionProfiles returns the names of all built-in profiles, sorted.
+func ListPermissionProfiles() []string {
+	names := make([]string, 0, len(builtinProfiles))
+	for name := range builtinProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
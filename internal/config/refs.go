@@ -0,0 +1,101 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/secret"
+)
+
+// ErrUnresolvedRef indicates a config value references a missing
+// environment variable or secret.
+var ErrUnresolvedRef = errors.New("unresolved config reference")
+
+var (
+	envRefPattern    = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+	secretRefPattern = regexp.MustCompile(`secret://([A-Za-z0-9_.-]+)`)
+)
+
+// lookupSecret resolves secret references. Tests replace it to avoid the
+// OS keyring.
+var lookupSecret = func(name string) (string, error) {
+	if v := os.Getenv(name); v != "" {
+		return v, nil
+	}
+	v, err := secret.Get(name)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(v, "\r\n"), nil
+}
+
+// HasRefs reports whether s contains ${VAR} or secret://name references.
+func HasRefs(s string) bool {
+	return envRefPattern.MatchString(s) || secretRefPattern.MatchString(s)
+}
+
+// SecretRefs returns the names of the secrets s references (secret://name),
+// sorted and without duplicates.
+func SecretRefs(s string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, m := range secretRefPattern.FindAllStringSubmatch(s, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			names = append(names, m[1])
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExpandRefs resolves the references in a config value. ${VAR} is replaced
+// by the environment variable VAR, which must be set (an empty value is
+// allowed). secret://name is replaced by the gt secret name: the environment
+// variable of that name if set, otherwise the OS keyring, as with
+// 'gt secret get'. Text without references is returned unchanged.
+func ExpandRefs(s string) (string, error) {
+	var errs []string
+	out := envRefPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		name := envRefPattern.FindStringSubmatch(ref)[1]
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			errs = append(errs, fmt.Sprintf("environment variable %s is not set", name))
+		}
+		return v
+	})
+	out = secretRefPattern.ReplaceAllStringFunc(out, func(ref string) string {
+		name := secretRefPattern.FindStringSubmatch(ref)[1]
+		v, err := lookupSecret(name)
+		switch {
+		case errors.Is(err, secret.ErrNotFound):
+			errs = append(errs, fmt.Sprintf("secret %q not found (store it with 'gt secret set %s')", name, name))
+		case err != nil:
+			errs = append(errs, fmt.Sprintf("secret %q: %v", name, err))
+		}
+		return v
+	})
+	if len(errs) > 0 {
+		return "", errors.New(strings.Join(errs, "; "))
+	}
+	return out, nil
+}
+
+// ResolveRef resolves the references in the config value of field (its
+// JSON path, for the error). Settings files keep references as written when
+// loaded, and each field is resolved where it's used, so an unset variable
+// fails only the feature that reads it.
+func ResolveRef(field, value string) (string, error) {
+	if !HasRefs(value) {
+		return value, nil
+	}
+	v, err := ExpandRefs(value)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %v", ErrUnresolvedRef, field, err)
+	}
+	return v, nil
+}
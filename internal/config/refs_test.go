@@ -0,0 +1,121 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/secret"
+)
+
+// stubSecrets replaces the keyring lookup with a fixed set of secrets.
+func stubSecrets(t *testing.T, secrets map[string]string) {
+	t.Helper()
+	orig := lookupSecret
+	lookupSecret = func(name string) (string, error) {
+		if v, ok := secrets[name]; ok {
+			return v, nil
+		}
+		return "", secret.ErrNotFound
+	}
+	t.Cleanup(func() { lookupSecret = orig })
+}
+
+func TestExpandRefs(t *testing.T) {
+	t.Setenv("GT_TEST_HOST", "git.example.com")
+	t.Setenv("GT_TEST_EMPTY", "")
+	stubSecrets(t, map[string]string{"slack-hook": "https://hooks.slack.com/services/T0/B0/xyz"})
+
+	tests := []struct {
+		in      string
+		want    string
+		wantErr string
+	}{
+		{"https://github.com", "https://github.com", ""},
+		{"https://${GT_TEST_HOST}/api", "https://git.example.com/api", ""},
+		{"x${GT_TEST_EMPTY}y", "xy", ""},
+		{"$HOME and $ are left alone", "$HOME and $ are left alone", ""},
+		{"secret://slack-hook", "https://hooks.slack.com/services/T0/B0/xyz", ""},
+		{"https://${GT_TEST_MISSING}/", "", "environment variable GT_TEST_MISSING is not set"},
+		{"secret://nope", "", `secret "nope" not found (store it with 'gt secret set nope')`},
+	}
+	for _, tt := range tests {
+		got, err := ExpandRefs(tt.in)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("ExpandRefs(%q) error = %v, want %q", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ExpandRefs(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestSecretRefs(t *testing.T) {
+	got := SecretRefs(`{"token": "secret://jira", "hook": "secret://slack-hook", "again": "secret://jira", "host": "${HOST}"}`)
+	if strings.Join(got, ",") != "jira,slack-hook" {
+		t.Errorf("SecretRefs = %v, want [jira slack-hook]", got)
+	}
+}
+
+func TestResolveRef(t *testing.T) {
+	t.Setenv("GT_TEST_FORGE", "https://git.example.com")
+	got, err := ResolveRef("forge.url", "${GT_TEST_FORGE}/api")
+	if err != nil || got != "https://git.example.com/api" {
+		t.Errorf("ResolveRef = %q, %v", got, err)
+	}
+	if got, err := ResolveRef("forge.url", "https://github.com"); err != nil || got != "https://github.com" {
+		t.Errorf("ResolveRef without refs = %q, %v", got, err)
+	}
+
+	_, err = ResolveRef("federation.towns.lab.url", "${GT_TEST_MISSING}")
+	if !errors.Is(err, ErrUnresolvedRef) || !strings.Contains(err.Error(), "federation.towns.lab.url: environment variable GT_TEST_MISSING is not set") {
+		t.Errorf("ResolveRef with unset var: err = %v", err)
+	}
+}
+
+func TestLoadSettings_KeepsRefs(t *testing.T) {
+	dir := t.TempDir()
+	rigPath := filepath.Join(dir, "rig.json")
+	raw := `{"type":"rig-settings","version":1,"forge":{"type":"gitea","url":"${GT_TEST_MISSING}"}}`
+	if err := os.WriteFile(rigPath, []byte(raw), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rig, err := LoadRigSettings(rigPath)
+	if err != nil {
+		t.Fatalf("LoadRigSettings with an unresolved ref: %v", err)
+	}
+	if err := SaveRigSettings(rigPath, rig); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(rigPath); !strings.Contains(string(data), "${GT_TEST_MISSING}") {
+		t.Errorf("saved settings lost the reference:\n%s", data)
+	}
+
+	townPath := filepath.Join(dir, "town.json")
+	raw = `{"type":"town-settings","version":1,"federation":{"towns":{"lab":{"url":"secret://lab-url"}}}}`
+	if err := os.WriteFile(townPath, []byte(raw), 0644); err != nil {
+		t.Fatal(err)
+	}
+	town, err := LoadOrCreateTownSettings(townPath)
+	if err != nil {
+		t.Fatalf("LoadOrCreateTownSettings with an unresolved ref: %v", err)
+	}
+	if got := town.Federation.Towns["lab"].URL; got != "secret://lab-url" {
+		t.Errorf("lab url = %q, want the reference", got)
+	}
+
+	escPath := filepath.Join(dir, "escalation.json")
+	cfg := NewEscalationConfig()
+	cfg.Contacts.SlackWebhook = "secret://slack-hook"
+	if err := SaveEscalationConfig(escPath, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, err := LoadEscalationConfig(escPath); err != nil || loaded.Contacts.SlackWebhook != "secret://slack-hook" {
+		t.Errorf("LoadEscalationConfig = %+v, %v; want the reference kept", loaded, err)
+	}
+}
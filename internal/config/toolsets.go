@@ -0,0 +1,164 @@
+package config
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// Toolset is a named bundle of agent tools granted together at session start.
+// Tools use the agent's permission syntax (for claude, e.g. "Bash(gt mq:*)").
+// Most tools are gt and bd subcommands, which front the internal packages
+// (beads, polecat, refinery) so agents act through the same code paths as
+// humans.
+type Toolset struct {
+	Name        string
+	Description string
+	Tools       []string
+}
+
+// Built-in toolset names.
+const (
+	ToolsetBeadQuery    = "bead-query"
+	ToolsetSpawnPolecat = "spawn-polecat"
+	ToolsetMergeQueue   = "merge-queue"
+	ToolsetGit          = "git"
+	ToolsetTest         = "test"
+	ToolsetFile         = "file"
+	ToolsetSandbox      = "sandbox"
+)
+
+// builtinToolsets are the toolsets shipped with Gas Town.
+var builtinToolsets = map[string]*Toolset{
+	ToolsetBeadQuery: {
+		Name:        ToolsetBeadQuery,
+		Description: "Read beads and their status",
+		Tools: []string{
+			"Bash(bd show:*)", "Bash(bd list:*)", "Bash(bd ready:*)", "Bash(bd blocked:*)",
+			"Bash(gt bead list:*)", "Bash(gt hook:*)", "Bash(gt convoy status:*)",
+		},
+	},
+	ToolsetSpawnPolecat: {
+		Name:        ToolsetSpawnPolecat,
+		Description: "Spawn, nudge, and inspect polecats",
+		Tools: []string{
+			"Bash(gt sling:*)", "Bash(gt polecat:*)", "Bash(gt nudge:*)", "Bash(gt peek:*)",
+		},
+	},
+	ToolsetMergeQueue: {
+		Name:        ToolsetMergeQueue,
+		Description: "Process the merge queue and record outcomes",
+		Tools: []string{
+			"Bash(gt mq:*)", "Bash(gt stats record:*)", "Bash(git fetch:*)", "Bash(git merge:*)",
+			"Bash(git rebase:*)", "Bash(git push:*)", "Bash(git log:*)", "Bash(git diff:*)",
+		},
+	},
+	ToolsetGit: {
+		Name:        ToolsetGit,
+		Description: "Work on the polecat's branch",
+		Tools: []string{
+			"Bash(git status:*)", "Bash(git diff:*)", "Bash(git log:*)", "Bash(git add:*)",
+			"Bash(git commit:*)", "Bash(git fetch:*)", "Bash(git rebase:*)", "Bash(gt done:*)",
+		},
+	},
+	ToolsetTest: {
+		Name:        ToolsetTest,
+		Description: "Build and run tests",
+		Tools: []string{
+			"Bash(go build:*)", "Bash(go test:*)", "Bash(go vet:*)", "Bash(make:*)",
+			"Bash(npm test:*)", "Bash(npm run:*)", "Bash(pytest:*)", "Bash(cargo test:*)",
+		},
+	},
+	ToolsetFile: {
+		Name:        ToolsetFile,
+		Description: "Read, search, and edit files",
+		Tools:       []string{"Read", "Edit", "Write", "Glob", "Grep"},
+	},
+	ToolsetSandbox: {
+		Name:        ToolsetSandbox,
+		Description: "Shell and file tools confined to the workspace (gt tool)",
+		Tools:       []string{"Bash(gt tool:*)"},
+	},
+}
+
+// defaultRoleToolsets maps roles to the toolsets their sessions get by default.
+// Roles not listed (mayor, deacon, crew) get none.
+var defaultRoleToolsets = map[string][]string{
+	"witness":  {ToolsetBeadQuery, ToolsetSpawnPolecat},
+	"refinery": {ToolsetBeadQuery, ToolsetMergeQueue},
+	"polecat":  {ToolsetGit, ToolsetTest, ToolsetFile},
+}
+
+// GetToolset returns the built-in toolset with the given name, or nil.
+func GetToolset(name string) *Toolset {
+	return builtinToolsets[name]
+}
+
+// ListToolsets returns the names of all built-in toolsets, sorted.
+func ListToolsets() []string {
+	names := make([]string, 0, len(builtinToolsets))
+	for name := range builtinToolsets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RoleToolsetNames returns the toolsets for a role. The town's "toolsets"
+// setting replaces the default for a role; an empty list disables them.
+func RoleToolsetNames(role string, townSettings *TownSettings) []string {
+	if townSettings != nil {
+		if names, ok := townSettings.Toolsets[role]; ok {
+			return names
+		}
+	}
+	return defaultRoleToolsets[role]
+}
+
+// RoleTools returns the tools granted to a role's sessions, in toolset order
+// and without duplicates.
+func RoleTools(role string, townSettings *TownSettings) []string {
+	var tools []string
+	seen := make(map[string]bool)
+	for _, name := range RoleToolsetNames(role, townSettings) {
+		ts := GetToolset(name)
+		if ts == nil {
+			continue
+		}
+		for _, tool := range ts.Tools {
+			if !seen[tool] {
+				seen[tool] = true
+				tools = append(tools, tool)
+			}
+		}
+	}
+	return tools
+}
+
+// withRoleTools returns rc with the role's toolsets granted. agentName picks
+// the grant flag; custom agents fall back to the preset named by their
+// command. The grant flags go before the runtime's own args so a variadic
+// tool flag cannot swallow the prompt. rc is returned unchanged if the role
+// has no tools or the agent has no tool flag.
+func withRoleTools(rc *RuntimeConfig, agentName, role, townRoot string) *RuntimeConfig {
+	if role == "" || townRoot == "" {
+		return rc
+	}
+	townSettings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil {
+		townSettings = nil
+	}
+	tools := RoleTools(role, townSettings)
+	if len(tools) == 0 {
+		return rc
+	}
+	resolved := *normalizeRuntimeConfig(rc)
+	if GetAgentPresetByName(agentName) == nil {
+		agentName = filepath.Base(resolved.Command)
+	}
+	flags := BuildToolGrantFlags(agentName, tools, nil)
+	if flags == "" {
+		return rc
+	}
+	resolved.Args = append([]string{flags}, resolved.Args...)
+	return &resolved
+}
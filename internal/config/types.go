@@ -2,6 +2,7 @@
 package config
 
 import (
+	"fmt"
 	"path/filepath"
 	"os"
 	"strings"
@@ -61,6 +62,155 @@ type TownSettings struct {
 	// Agent addresses like "gastown/crew/jack" become "gastown.crew.jack@{domain}".
 	// Default: "gastown.local"
 	AgentEmailDomain string `json:"agent_email_domain,omitempty"`
+
+	// Concurrency limits how many agent sessions may run at once.
+	Concurrency *ConcurrencyConfig `json:"concurrency,omitempty"`
+
+	// Sessions configures tmux session naming. Set distinct prefixes when
+	// several towns share one machine so their session names don't collide.
+	Sessions *SessionNamingConfig `json:"sessions,omitempty"`
+
+	// Hibernation lets idle polecats release their session and resume their
+	// conversation when new work arrives. Off by default.
+	Hibernation *HibernationConfig `json:"hibernation,omitempty"`
+
+	// Federation connects this town to remote towns so their sessions,
+	// health, and cost are aggregated here and work can be routed to them.
+	Federation *FederationConfig `json:"federation,omitempty"`
+
+	// SLA sets per-priority start and finish deadlines for beads. The daemon
+	// escalates breaches and 'gt bead list' shows how close beads are to them.
+	SLA *SLAConfig `json:"sla,omitempty"`
+
+	// Toolsets overrides which built-in toolsets each role's sessions are
+	// granted at startup. Keys are role names; an empty list grants none.
+	// Example: {"polecat": ["git", "test", "file", "bead-query"]}
+	// Default: witness gets bead-query and spawn-polecat, refinery gets
+	// bead-query and merge-queue, polecats get git, test, and file.
+	Toolsets map[string][]string `json:"toolsets,omitempty"`
+}
+
+// SLAConfig maps bead priorities to their service-level targets.
+//
+//	{"priorities": {"P1": {"start_within": "1h", "finish_within": "8h"}}}
+type SLAConfig struct {
+	// Priorities maps a priority ("P0" through "P4") to its targets.
+	// Priorities without an entry have no SLA.
+	Priorities map[string]*SLATarget `json:"priorities,omitempty"`
+}
+
+// SLATarget is how quickly a bead of one priority must be picked up and
+// closed. Both durations are measured from the bead's creation; empty means
+// no deadline.
+type SLATarget struct {
+	// StartWithin is the deadline for the bead to be hooked or in progress (e.g., "1h").
+	StartWithin string `json:"start_within,omitempty"`
+
+	// FinishWithin is the deadline for the bead to be closed (e.g., "8h").
+	FinishWithin string `json:"finish_within,omitempty"`
+}
+
+// Target returns the SLA target for a priority (0-4), or nil if none is set.
+func (c *SLAConfig) Target(priority int) *SLATarget {
+	if c == nil {
+		return nil
+	}
+	return c.Priorities[fmt.Sprintf("P%d", priority)]
+}
+
+// Durations returns the start and finish deadlines. Zero means no deadline.
+func (t *SLATarget) Durations() (start, finish time.Duration) {
+	if t == nil {
+		return 0, 0
+	}
+	start, _ = time.ParseDuration(t.StartWithin)
+	finish, _ = time.ParseDuration(t.FinishWithin)
+	return start, finish
+}
+
+// FederationConfig lists remote towns and secures this town's federation API.
+type FederationConfig struct {
+	// TokenEnv names the environment variable (or gt secret keyring entry)
+	// holding the bearer token remote towns must present to this town's
+	// dashboard. Without a token the status API is public and dispatch is
+	// refused.
+	TokenEnv string `json:"token_env,omitempty"`
+
+	// Towns maps remote town names to their connection settings.
+	Towns map[string]*RemoteTownConfig `json:"towns,omitempty"`
+}
+
+// RemoteTownConfig is how to reach a remote town.
+type RemoteTownConfig struct {
+	// URL is the remote town's dashboard URL (e.g., "http://build-box:8080").
+	URL string `json:"url"`
+
+	// TokenEnv names the environment variable (or gt secret keyring entry)
+	// holding the remote town's token.
+	TokenEnv string `json:"token_env,omitempty"`
+}
+
+// HibernationConfig controls idle polecat hibernation.
+//
+// A hibernated polecat keeps its worktree and runtime session ID but has no
+// tmux session, so it holds no process or concurrency slot. Slinging work to
+// it resumes the previous conversation instead of starting a fresh one.
+type HibernationConfig struct {
+	// Enabled allows 'gt polecat hibernate --idle' to hibernate idle polecats.
+	Enabled bool `json:"enabled"`
+
+	// IdleAfter is how long a polecat with no hooked work must be inactive
+	// before it is hibernated (e.g., "15m"). Default: 15m.
+	IdleAfter string `json:"idle_after,omitempty"`
+}
+
+// GetIdleAfter returns the idle threshold as a time.Duration.
+// Returns 15 minutes if not configured or invalid.
+func (c *HibernationConfig) GetIdleAfter() time.Duration {
+	if c.IdleAfter == "" {
+		return 15 * time.Minute
+	}
+	d, err := time.ParseDuration(c.IdleAfter)
+	if err != nil || d <= 0 {
+		return 15 * time.Minute
+	}
+	return d
+}
+
+// SessionNamingConfig sets the tmux session name prefixes for a town.
+// Either prefix may contain "{town}", which expands to the town name:
+//
+//	{"prefix": "gt-{town}-", "hq_prefix": "hq-{town}-"}
+//
+// names the mayor "hq-acme-mayor" and a witness "gt-acme-gastown-witness".
+// Empty prefixes default to "gt-" and "hq-".
+type SessionNamingConfig struct {
+	// Prefix is prepended to rig-level session names (witness, refinery,
+	// crew, polecats).
+	Prefix string `json:"prefix,omitempty"`
+
+	// HQPrefix is prepended to town-level session names (mayor, deacon).
+	HQPrefix string `json:"hq_prefix,omitempty"`
+}
+
+// ConcurrencyConfig limits concurrent agent sessions across the town.
+//
+// Worker sessions (polecats and crew) share the town-wide MaxSessions pool,
+// which is divided fairly between rigs: a rig at or above its fair share may
+// only take a slot while every other rig below its share still has one left.
+// Infrastructure sessions (mayor, deacon, witness, refinery) never count
+// against the worker pool or rig caps, so a polecat storm in one rig cannot
+// keep another rig's witness from starting. A zero or missing limit means
+// unlimited.
+type ConcurrencyConfig struct {
+	// MaxSessions caps worker sessions town-wide.
+	MaxSessions int `json:"max_sessions,omitempty" toml:"max_sessions"`
+
+	// Roles caps sessions per role town-wide, e.g. {"polecat": 12, "crew": 4}.
+	Roles map[string]int `json:"roles,omitempty" toml:"roles"`
+
+	// Rigs caps worker sessions per rig, e.g. {"gastown": 6}.
+	Rigs map[string]int `json:"rigs,omitempty" toml:"rigs"`
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -211,6 +361,37 @@ type RigSettings struct {
 	Workflow   *WorkflowConfig   `json:"workflow,omitempty"`    // workflow settings
 	Runtime    *RuntimeConfig    `json:"runtime,omitempty"`     // LLM runtime settings (deprecated: use Agent)
 
+	// PullRequests configures opening forge pull requests for polecat branches.
+	PullRequests *PullRequestConfig `json:"pull_requests,omitempty"`
+
+	// Forge selects and configures the code host (GitHub, GitLab, Gitea)
+	// used for clone auth, pull requests, status checks, and webhooks.
+	// If unset, the forge is detected from the rig's git URL.
+	Forge *ForgeConfig `json:"forge,omitempty"`
+
+	// Budget caps the rig's daily and weekly agent spend.
+	Budget *BudgetConfig `json:"budget,omitempty"`
+
+	// Email sends the rig's high-severity events to its own recipients,
+	// through the town's SMTP server ([email] in gastown.toml).
+	Email *EmailRouting `json:"email,omitempty"`
+
+	// SparseCheckout limits polecat worktrees and crew clones to these repo
+	// directories (e.g., "services/api"), for monorepos too large to check
+	// out whole. Top-level files are always included. Empty means the full repo.
+	SparseCheckout []string `json:"sparse_checkout,omitempty"`
+
+	// ProtectedBranches are branch patterns (e.g., "release/*") that agents
+	// may not push to; only the Refinery's merge path lands on them. The
+	// rig's default branch is always protected. Empty means main, master,
+	// and release/*.
+	ProtectedBranches []string `json:"protected_branches,omitempty"`
+
+	// Prompts turn a slung bead into the polecat's start prompt and tool
+	// grants. Keys are bead types ("bug", "feature", "chore", ...) or
+	// DefaultPromptKey for any type without its own entry.
+	Prompts map[string]*WorkPromptConfig `json:"prompts,omitempty"`
+
 	// Agent selects which agent preset to use for this rig.
 	// Can be a built-in preset ("claude", "gemini", "codex", "cursor", "auggie", "amp")
 	// or a custom agent defined in settings/agents.json.
@@ -641,9 +822,28 @@ type MergeQueueConfig struct {
 	// Default: "integration/{epic}"
 	IntegrationBranchTemplate string `json:"integration_branch_template,omitempty"`
 
-	// OnConflict specifies conflict resolution strategy: "assign_back" or "auto_rebase".
+	// OnConflict specifies conflict resolution strategy: "assign_back", "auto_rebase",
+	// or "agent_resolve" (a short-lived agent resolves conflicts before merging).
 	OnConflict string `json:"on_conflict"`
 
+	// ConflictAgent is the agent preset used by the "agent_resolve" strategy.
+	// Must support non-interactive mode. Default: "claude".
+	ConflictAgent string `json:"conflict_agent,omitempty"`
+
+	// ConflictTimeout bounds how long the conflict-resolution agent may run (e.g., "10m").
+	ConflictTimeout string `json:"conflict_timeout,omitempty"`
+
+	// Review enables the review stage: before merging, a reviewer agent
+	// critiques the diff and approves it or sends findings back to the polecat.
+	Review bool `json:"review,omitempty"`
+
+	// ReviewAgent is the agent preset used for review sessions.
+	// Must support non-interactive mode. Default: "claude".
+	ReviewAgent string `json:"review_agent,omitempty"`
+
+	// ReviewTimeout bounds how long a review session may run (e.g., "20m").
+	ReviewTimeout string `json:"review_timeout,omitempty"`
+
 	// RunTests controls whether to run tests before merging.
 	RunTests bool `json:"run_tests"`
 
@@ -661,12 +861,55 @@ type MergeQueueConfig struct {
 
 	// MaxConcurrent is the maximum number of concurrent merges.
 	MaxConcurrent int `json:"max_concurrent"`
+
+	// BatchSize is the maximum number of MRs merged and tested together as a
+	// merge train (gt mq train). A failing train is bisected to find the
+	// culprits. 0 or 1 merges MRs one at a time.
+	BatchSize int `json:"batch_size,omitempty"`
+
+	// Checks are pre-merge gates that must pass before the Refinery merges.
+	Checks []MergeCheckConfig `json:"checks,omitempty"`
+}
+
+// MergeCheckConfig configures a pre-merge check run by the Refinery.
+type MergeCheckConfig struct {
+	// Name identifies the check in failure reports (e.g., "lint").
+	Name string `json:"name"`
+
+	// Type is "command" (run in an isolated worktree of the merge result)
+	// or "forge" (poll the rig's forge for the branch's status checks).
+	// "github" is accepted as a forge check pinned to GitHub. Default: "command".
+	Type string `json:"type,omitempty"`
+
+	// Command is the shell command for command checks.
+	Command string `json:"command,omitempty"`
+
+	// Required lists forge check names that must pass (forge type).
+	// If empty, every reported check must pass.
+	Required []string `json:"required,omitempty"`
+
+	// Timeout bounds the check (e.g., "15m").
+	Timeout string `json:"timeout,omitempty"`
+
+	// Optional checks are reported but do not block the merge.
+	Optional bool `json:"optional,omitempty"`
 }
 
+// Merge check type constants.
+const (
+	MergeCheckCommand = "command"
+	MergeCheckForge   = "forge"
+	MergeCheckGitHub  = "github"
+)
+
 // OnConflict strategy constants.
 const (
 	OnConflictAssignBack = "assign_back"
 	OnConflictAutoRebase = "auto_rebase"
+
+	// OnConflictAgentResolve spawns a non-interactive agent scoped to the
+	// conflicted files; the merge proceeds only if the resolution passes tests.
+	OnConflictAgentResolve = "agent_resolve"
 )
 
 // DefaultMergeQueueConfig returns a MergeQueueConfig with sensible defaults.
@@ -685,6 +928,165 @@ func DefaultMergeQueueConfig() *MergeQueueConfig {
 	}
 }
 
+// DefaultPromptKey is the RigSettings.Prompts entry used for bead types
+// without their own entry.
+const DefaultPromptKey = "default"
+
+// WorkPromptConfig describes how a bead becomes a polecat's start prompt.
+// Templates are Go text/templates over the bead (.ID, .Title, .Description,
+// .Type, .Priority, .Labels, .Files) and the assignment (.Rig, .Polecat,
+// .Subject, .Args).
+type WorkPromptConfig struct {
+	// Template is an inline prompt template.
+	Template string `json:"template,omitempty"`
+
+	// TemplateFile is a prompt template file, relative to the rig's
+	// settings/ directory (e.g., "prompts/bug.md.tmpl").
+	TemplateFile string `json:"template_file,omitempty"`
+
+	// AllowedTools and DisallowedTools are passed to the agent at startup
+	// (e.g., "Bash(go test:*)", "WebFetch"). Only agents with tool flags
+	// (claude) honor them.
+	AllowedTools    []string `json:"allowed_tools,omitempty"`
+	DisallowedTools []string `json:"disallowed_tools,omitempty"`
+}
+
+// PullRequestConfig represents pull request automation for a rig.
+// When enabled, `gt done` opens a pull request on the rig's forge
+// for the polecat's branch after it is pushed, and links it to the MR bead.
+type PullRequestConfig struct {
+	// Enabled controls whether gt done opens pull requests.
+	Enabled bool `json:"enabled"`
+
+	// Provider selects the forge: "github", "gitlab", or "gitea".
+	// If empty, forge.type is used, then detection from the origin remote URL.
+	Provider string `json:"provider,omitempty"`
+
+	// Draft opens pull requests as drafts.
+	Draft bool `json:"draft,omitempty"`
+
+	// Labels are applied to every pull request.
+	Labels []string `json:"labels,omitempty"`
+
+	// Reviewers are requested on every pull request.
+	Reviewers []string `json:"reviewers,omitempty"`
+}
+
+// Pull request provider constants.
+const (
+	PRProviderGitHub = ForgeGitHub
+	PRProviderGitLab = ForgeGitLab
+	PRProviderGitea  = ForgeGitea
+)
+
+// ForgeConfig identifies the code host behind a rig.
+type ForgeConfig struct {
+	// Type is "github", "gitlab", or "gitea".
+	// If empty, it is detected from the rig's git URL.
+	Type string `json:"type,omitempty"`
+
+	// URL is the forge's base web URL, for self-hosted instances whose
+	// API cannot be derived from the git URL (e.g., "https://git.example.com").
+	URL string `json:"url,omitempty"`
+
+	// TokenEnv names the environment variable (or gt secret keyring entry)
+	// holding the API token. Defaults: GITHUB_TOKEN, GITLAB_TOKEN, GITEA_TOKEN.
+	TokenEnv string `json:"token_env,omitempty"`
+
+	// WebhookSecretEnv names the environment variable (or gt secret keyring
+	// entry) holding the shared secret used to verify webhook deliveries. If empty, webhooks for the
+	// rig are rejected.
+	WebhookSecretEnv string `json:"webhook_secret_env,omitempty"`
+}
+
+// Forge type constants.
+const (
+	ForgeGitHub = "github"
+	ForgeGitLab = "gitlab"
+	ForgeGitea  = "gitea"
+)
+
+// BudgetConfig caps a rig's agent spend. Spend is rolled up per rig from the
+// session cost records written by 'gt costs record'. When a cap is exceeded,
+// the configured actions are taken until the window resets or a human
+// overrides the budget with 'gt budget override'.
+type BudgetConfig struct {
+	// DailyUSD is the maximum spend per calendar day (0 = no daily cap).
+	DailyUSD float64 `json:"daily_usd,omitempty" toml:"daily_usd"`
+
+	// WeeklyUSD is the maximum spend over the last 7 days (0 = no weekly cap).
+	WeeklyUSD float64 `json:"weekly_usd,omitempty" toml:"weekly_usd"`
+
+	// DailyTokens is the maximum tokens used per calendar day (0 = no cap).
+	DailyTokens int `json:"daily_tokens,omitempty" toml:"daily_tokens"`
+
+	// WeeklyTokens is the maximum tokens used over the last 7 days (0 = no cap).
+	WeeklyTokens int `json:"weekly_tokens,omitempty" toml:"weekly_tokens"`
+
+	// Actions lists what happens when a cap is exceeded:
+	// "block_spawn", "pause_sessions", "alert". If empty, all actions apply.
+	Actions []string `json:"actions,omitempty" toml:"actions"`
+}
+
+// Budget enforcement actions.
+const (
+	// BudgetActionBlockSpawn stops new polecats from being spawned in the rig.
+	BudgetActionBlockSpawn = "block_spawn"
+
+	// BudgetActionPauseSessions stops the rig's polecat sessions that have no
+	// assigned work. Working polecats, crew, the witness, and the refinery keep
+	// running so in-flight work can land.
+	BudgetActionPauseSessions = "pause_sessions"
+
+	// BudgetActionAlert mails the mayor when the budget is first exceeded.
+	BudgetActionAlert = "alert"
+)
+
+// HasAction reports whether the budget enforces the given action.
+func (c *BudgetConfig) HasAction(action string) bool {
+	if len(c.Actions) == 0 {
+		return true
+	}
+	for _, a := range c.Actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// EmailEventKinds are the kinds of high-severity events emailed (gt
+// email): budgets exceeded, polecats crash looping, and high or critical
+// escalations.
+var EmailEventKinds = []string{"budget", "crash", "escalation"}
+
+// Email digest modes.
+const (
+	// EmailDigestHourly collects events into one email an hour.
+	EmailDigestHourly = "hourly"
+
+	// EmailDigestDaily collects events into one email a day.
+	EmailDigestDaily = "daily"
+)
+
+// EmailRouting says who is emailed which events, and how often.
+type EmailRouting struct {
+	// To are the recipients' addresses. Empty means nobody.
+	To []string `json:"to,omitempty" toml:"to"`
+
+	// Events are the kinds to send (see EmailEventKinds). Empty means all.
+	Events []string `json:"events,omitempty" toml:"events"`
+
+	// Digest collects events into a summary sent "hourly" or "daily".
+	// Empty sends each event as it happens.
+	Digest string `json:"digest,omitempty" toml:"digest"`
+}
+
+// Sends reports whether events of kind are emailed.
+func (r EmailRouting) Sends(kind string) bool {
+	return len(r.To) > 0 && postsKind(r.Events, kind)
+}
+
 // NamepoolConfig represents namepool settings for themed polecat names.
 type NamepoolConfig struct {
 	// Style picks from a built-in theme (e.g., "mad-max", "minerals", "wasteland").
@@ -714,13 +1116,55 @@ type AccountsConfig struct {
 	Version  int                `json:"version"`  // schema version
 	Accounts map[string]Account `json:"accounts"` // handle -> account details
 	Default  string             `json:"default"`  // default account handle
+
+	// Roles assigns accounts to roles (role -> handle), e.g. polecats on a
+	// high-volume account and the mayor on a personal one.
+	Roles map[string]string `json:"roles,omitempty"`
+
+	// Rigs assigns accounts to rigs (rig -> handle). A rig assignment wins
+	// over a role assignment.
+	Rigs map[string]string `json:"rigs,omitempty"`
+
+	// Rotation moves new sessions off accounts that hit rate limits.
+	Rotation *AccountRotationConfig `json:"rotation,omitempty"`
 }
 
 // Account represents a single Claude Code account.
 type Account struct {
 	Email       string `json:"email"`                 // account email
 	Description string `json:"description,omitempty"` // human description
-	ConfigDir   string `json:"config_dir"`            // path to CLAUDE_CONFIG_DIR
+	ConfigDir   string `json:"config_dir,omitempty"`  // path to CLAUDE_CONFIG_DIR
+
+	// APIKeyEnv names the environment variable (or gt secret keyring entry)
+	// holding the account's API key, exported to sessions as ANTHROPIC_API_KEY.
+	APIKeyEnv string `json:"api_key_env,omitempty"`
+}
+
+// AccountRotationConfig configures rotation away from rate-limited accounts.
+type AccountRotationConfig struct {
+	// Enabled turns rotation on.
+	Enabled bool `json:"enabled"`
+
+	// Pool lists the handles to rotate through, in order. Default: all
+	// accounts, sorted by handle.
+	Pool []string `json:"pool,omitempty"`
+
+	// Cooldown is how long an account stays out of rotation after a rate
+	// limit is detected (e.g., "1h"). Default: DefaultAccountCooldown.
+	Cooldown string `json:"cooldown,omitempty"`
+}
+
+// DefaultAccountCooldown is how long a rate-limited account is skipped.
+const DefaultAccountCooldown = time.Hour
+
+// CooldownDuration returns the rotation cooldown, falling back to the default.
+func (r *AccountRotationConfig) CooldownDuration() time.Duration {
+	if r != nil {
+		if d, err := time.ParseDuration(r.Cooldown); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultAccountCooldown
 }
 
 // CurrentAccountsVersion is the current schema version for AccountsConfig.
@@ -858,6 +1302,20 @@ func IsValidSeverity(severity string) bool {
 	}
 }
 
+// SeverityAtLeast reports whether severity is min or more severe. An empty
+// min is met by every severity.
+func SeverityAtLeast(severity, min string) bool {
+	rank := func(s string) int {
+		for i, v := range ValidSeverities() {
+			if v == s {
+				return i
+			}
+		}
+		return -1
+	}
+	return min == "" || rank(severity) >= rank(min)
+}
+
 // NextSeverity returns the next higher severity level for re-escalation.
 // Returns the same level if already at critical.
 func NextSeverity(severity string) string {
@@ -889,3 +1347,82 @@ func NewEscalationConfig() *EscalationConfig {
 		MaxReescalations: 2,
 	}
 }
+
+// SchedulesConfig represents recurring job configuration (settings/schedules.json).
+// The daemon evaluates these jobs with cron expressions and runs them
+// through the regular gt commands (nudge, mail, sling) or a shell command.
+type SchedulesConfig struct {
+	Type    string `json:"type"`    // "schedules"
+	Version int    `json:"version"` // schema version
+
+	// Jobs are the recurring jobs, run in definition order when due at the same time.
+	Jobs []ScheduledJob `json:"jobs,omitempty"`
+}
+
+// ScheduledJob represents a single recurring job.
+type ScheduledJob struct {
+	// Name uniquely identifies the job (used for history and overlap locks).
+	Name string `json:"name"`
+
+	// Cron is a 5-field cron expression ("min hour dom month dow") or a
+	// macro such as "@hourly", "@daily", "@weekly", "@monthly".
+	// Times are evaluated in the daemon's local time zone.
+	Cron string `json:"cron"`
+
+	// Action selects what the job does: "nudge", "mail", "sling", or "command".
+	Action string `json:"action"`
+
+	// Target is the recipient for nudge/mail (e.g., "gastown/witness")
+	// or the sling target (e.g., a rig name to spawn a fresh polecat).
+	Target string `json:"target,omitempty"`
+
+	// Subject is the mail subject (mail action only).
+	Subject string `json:"subject,omitempty"`
+
+	// Message is the prompt text for nudge/mail actions.
+	Message string `json:"message,omitempty"`
+
+	// Bead is the bead or formula to sling (sling action only).
+	Bead string `json:"bead,omitempty"`
+
+	// Command is the shell command to run from the town root (command action only).
+	Command string `json:"command,omitempty"`
+
+	// Timeout bounds a single run (e.g., "30m"). Default: "1h".
+	Timeout string `json:"timeout,omitempty"`
+
+	// Disabled skips the job without removing it from the config.
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// CurrentSchedulesVersion is the current schema version for SchedulesConfig.
+const CurrentSchedulesVersion = 1
+
+// Scheduled job action constants.
+const (
+	ScheduleActionNudge   = "nudge"
+	ScheduleActionMail    = "mail"
+	ScheduleActionSling   = "sling"
+	ScheduleActionCommand = "command"
+)
+
+// NewSchedulesConfig creates a new SchedulesConfig with no jobs.
+func NewSchedulesConfig() *SchedulesConfig {
+	return &SchedulesConfig{
+		Type:    "schedules",
+		Version: CurrentSchedulesVersion,
+	}
+}
+
+// GetTimeout returns the job timeout as a time.Duration.
+// Returns 1 hour if not configured or invalid.
+func (j *ScheduledJob) GetTimeout() time.Duration {
+	if j.Timeout == "" {
+		return time.Hour
+	}
+	d, err := time.ParseDuration(j.Timeout)
+	if err != nil || d <= 0 {
+		return time.Hour
+	}
+	return d
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// ValidationIssue is a problem found by ValidateTown.
+type ValidationIssue struct {
+	File    string `json:"file"`
+	Message string `json:"message"`
+
+	// Warning marks problems that don't stop the config from loading but
+	// may keep something from working (e.g., a missing account directory).
+	Warning bool `json:"warning,omitempty"`
+}
+
+// ValidateTown checks a town's configuration: every config file is loaded
+// with its usual validation (types, ranges, durations, addresses, and
+// ${VAR}/secret:// references), then referenced rig directories, account
+// directories, and agent binaries are checked. Missing optional files are
+// skipped. Issues are returned in file order; nil means the town is valid.
+func ValidateTown(townRoot string) []ValidationIssue {
+	var issues []ValidationIssue
+	fail := func(file string, err error) {
+		// Loaders that prefix errors with the path would repeat File
+		msg := strings.TrimPrefix(err.Error(), file+": ")
+		issues = append(issues, ValidationIssue{File: file, Message: msg})
+	}
+	warn := func(file, format string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{File: file, Message: fmt.Sprintf(format, args...), Warning: true})
+	}
+	// load runs a loader, reporting any error other than a missing file.
+	load := func(path string, fn func(string) error) bool {
+		if err := fn(path); err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				fail(path, err)
+			}
+			return false
+		}
+		return true
+	}
+
+	load(constants.MayorTownPath(townRoot), func(p string) error { _, err := LoadTownConfig(p); return err })
+	load(MessagingConfigPath(townRoot), func(p string) error { _, err := LoadMessagingConfig(p); return err })
+	load(EscalationConfigPath(townRoot), func(p string) error { _, err := LoadEscalationConfig(p); return err })
+	load(DaemonPatrolConfigPath(townRoot), func(p string) error { _, err := LoadDaemonPatrolConfig(p); return err })
+	load(SchedulesConfigPath(townRoot), func(p string) error { _, err := LoadSchedulesConfig(p); return err })
+
+	_, gastownErr := LoadGastownConfig(townRoot)
+	if gastownErr != nil {
+		fail(GastownConfigPath(townRoot), gastownErr)
+	}
+
+	townSettingsPath := TownSettingsPath(townRoot)
+	townSettings, err := LoadOrCreateTownSettings(townSettingsPath)
+	if err != nil {
+		fail(townSettingsPath, err)
+		townSettings = NewTownSettings()
+	}
+
+	accountsPath := constants.MayorAccountsPath(townRoot)
+	var accounts *AccountsConfig
+	load(accountsPath, func(p string) error { accounts, err = LoadAccountsConfig(p); return err })
+	if accounts != nil {
+		for _, handle := range sortedKeys(accounts.Accounts) {
+			acct := accounts.Accounts[handle]
+			if acct.ConfigDir == "" {
+				continue
+			}
+			if _, err := os.Stat(acct.ConfigDirPath()); err != nil {
+				warn(accountsPath, "account '%s' config_dir %s does not exist (run 'gt account add %s' or log in)", handle, acct.ConfigDirPath(), handle)
+			}
+		}
+	}
+
+	// Agents the town starts sessions with
+	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
+	checked := make(map[string]bool)
+	checkAgent := func(file, setting, name string, rigSettings *RigSettings) {
+		if name == "" || checked[file+"\x00"+name] {
+			return
+		}
+		checked[file+"\x00"+name] = true
+		if err := ValidateAgentConfig(name, townSettings, rigSettings); err != nil {
+			fail(file, fmt.Errorf("%s: %w", setting, err))
+		}
+	}
+	checkAgent(townSettingsPath, "default_agent", townDefaultAgent(townRoot, "", townSettings), nil)
+	for _, role := range sortedKeys(townSettings.RoleAgents) {
+		checkAgent(townSettingsPath, "role_agents."+role, townSettings.RoleAgents[role], nil)
+	}
+
+	rigsPath := constants.MayorRigsPath(townRoot)
+	var rigs *RigsConfig
+	load(rigsPath, func(p string) error { rigs, err = LoadRigsConfig(p); return err })
+	if rigs == nil {
+		return issues
+	}
+	for _, name := range sortedKeys(rigs.Rigs) {
+		rigPath := filepath.Join(townRoot, name)
+		if _, err := os.Stat(rigPath); err != nil {
+			fail(rigsPath, fmt.Errorf("rig '%s': directory %s does not exist", name, rigPath))
+			continue
+		}
+		if repo := rigs.Rigs[name].LocalRepo; repo != "" {
+			if _, err := os.Stat(expandPath(repo)); err != nil {
+				warn(rigsPath, "rig '%s': local_repo %s does not exist", name, repo)
+			}
+		}
+		_ = LoadRigAgentRegistry(RigAgentRegistryPath(rigPath))
+		if gastownErr == nil {
+			if _, err := LoadRigGastownConfig(townRoot, rigPath); err != nil {
+				fail(RigGastownConfigPath(rigPath), err)
+			}
+		}
+		settingsPath := RigSettingsPath(rigPath)
+		var rigSettings *RigSettings
+		if !load(settingsPath, func(p string) error { rigSettings, err = LoadRigSettings(p); return err }) {
+			continue
+		}
+		if rigSettings.Runtime == nil {
+			agent := rigSettings.Agent
+			if agent == "" {
+				agent = townDefaultAgent(townRoot, rigPath, townSettings)
+			}
+			checkAgent(settingsPath, "agent", agent, rigSettings)
+		}
+		for _, role := range sortedKeys(rigSettings.RoleAgents) {
+			checkAgent(settingsPath, "role_agents."+role, rigSettings.RoleAgents[role], rigSettings)
+		}
+	}
+	return issues
+}
+
+// sortedKeys returns m's keys in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// stubAgentBinary puts an executable named name on PATH.
+func stubAgentBinary(t *testing.T, name string) {
+	t.Helper()
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateTown_Valid(t *testing.T) {
+	townRoot := setupGastownConfig(t, "", "model = \"sonnet\"\n")
+	stubAgentBinary(t, "claude")
+	writeTestFile(t, constants.MayorRigsPath(townRoot), `{"version":1,"rigs":{"gastown":{"git_url":"x"}}}`)
+	writeTestFile(t, RigSettingsPath(filepath.Join(townRoot, "gastown")), `{"type":"rig-settings","version":1}`)
+
+	if issues := ValidateTown(townRoot); len(issues) != 0 {
+		t.Errorf("ValidateTown = %+v, want no issues", issues)
+	}
+}
+
+func TestValidateTown_Problems(t *testing.T) {
+	townRoot := setupGastownConfig(t, "", "[server]\nbind = \"0.0.0.0:80\"\n")
+	stubAgentBinary(t, "claude")
+	writeTestFile(t, TownSettingsPath(townRoot), `{"type":"town-settings","version":1,
+		"role_agents":{"polecat":"fast"},
+		"agents":{"fast":{"command":"/no/such/agent"}}}`)
+	writeTestFile(t, constants.MayorRigsPath(townRoot), `{"version":1,"rigs":{"ghost":{"git_url":"x"}}}`)
+	writeTestFile(t, constants.MayorAccountsPath(townRoot), `{"version":1,
+		"accounts":{"work":{"config_dir":"`+filepath.Join(townRoot, "no-such-dir")+`"}}}`)
+
+	issues := ValidateTown(townRoot)
+	want := []struct {
+		file, msg string
+		warning   bool
+	}{
+		{GastownConfigPath(townRoot), `invalid server.bind "0.0.0.0:80": must not include a port`, false},
+		{constants.MayorAccountsPath(townRoot), "account 'work' config_dir", true},
+		{TownSettingsPath(townRoot), `role_agents.polecat: agent "fast" binary "/no/such/agent" not found`, false},
+		{constants.MayorRigsPath(townRoot), "rig 'ghost': directory", false},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("ValidateTown = %+v, want %d issues", issues, len(want))
+	}
+	for i, w := range want {
+		got := issues[i]
+		if got.File != w.file || !strings.Contains(got.Message, w.msg) || got.Warning != w.warning {
+			t.Errorf("issue %d = %+v, want %s: %q (warning=%v)", i, got, w.file, w.msg, w.warning)
+		}
+	}
+}
+
+func TestValidateBindAddress(t *testing.T) {
+	for _, bind := range []string{"", "127.0.0.1", "::1", "localhost", "build-box.internal"} {
+		if err := validateBindAddress(bind); err != nil {
+			t.Errorf("validateBindAddress(%q) = %v, want nil", bind, err)
+		}
+	}
+	for _, bind := range []string{"127.0.0.1:8080", "localhost:80", "bad host", "-dash"} {
+		if err := validateBindAddress(bind); err == nil {
+			t.Errorf("validateBindAddress(%q) = nil, want error", bind)
+		}
+	}
+}
@@ -107,14 +107,15 @@ const (
 )
 
 // Tmux session names.
-// Mayor and Deacon use hq- prefix: hq-mayor, hq-deacon (town-level, one per machine).
+// Mayor and Deacon use hq- prefix: hq-mayor, hq-deacon (town-level, one per town).
 // Rig-level services use gt- prefix: gt-<rig>-witness, gt-<rig>-refinery, etc.
-// Use session.MayorSessionName() and session.DeaconSessionName().
+// These are defaults; towns may configure their own prefixes (settings "sessions").
+// Use the session package name functions rather than these constants.
 const (
-	// SessionPrefix is the prefix for rig-level Gas Town tmux sessions.
+	// SessionPrefix is the default prefix for rig-level Gas Town tmux sessions.
 	SessionPrefix = "gt-"
 
-	// HQSessionPrefix is the prefix for town-level services (Mayor, Deacon).
+	// HQSessionPrefix is the default prefix for town-level services (Mayor, Deacon).
 	HQSessionPrefix = "hq-"
 )
 
@@ -137,6 +138,9 @@ const (
 
 	// RoleDeacon is the deacon agent role.
 	RoleDeacon = "deacon"
+
+	// RoleReviewer is the code reviewer role, run by the Refinery's review stage.
+	RoleReviewer = "reviewer"
 )
 
 // Role emojis - centralized for easy customization.
@@ -159,6 +163,9 @@ const (
 
 	// EmojiPolecat is the polecat emoji (transient worker).
 	EmojiPolecat = "😺"
+
+	// EmojiReviewer is the reviewer emoji (inspector).
+	EmojiReviewer = "🔍"
 )
 
 // RoleEmoji returns the emoji for a given role name.
@@ -176,6 +183,8 @@ func RoleEmoji(role string) string {
 		return EmojiCrew
 	case RolePolecat:
 		return EmojiPolecat
+	case RoleReviewer:
+		return EmojiReviewer
 	default:
 		return "❓"
 	}
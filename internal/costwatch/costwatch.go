@@ -0,0 +1,222 @@
+// Package costwatch tracks each rig's hourly spend against its own baseline
+// and flags anomalies, so a misconfigured model or a looping polecat is
+// caught within minutes rather than on the monthly bill.
+//
+// The daemon's heartbeat (gt budget check) feeds in each rig's spend so far
+// today. The increase since the last observation is the rig's new spend;
+// it is summed into hourly buckets, and the spend over the trailing hour is
+// compared with:
+//
+//   - an absolute cap (cost_alerts.hourly_usd)
+//   - the rig's baseline, the mean of its past hours (cost_alerts.spike_factor)
+//
+// Each kind of anomaly alerts once per rig per clock hour. State lives in
+// <town>/.runtime/costs/<rig>.json.
+package costwatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Anomaly kinds.
+const (
+	KindHourlyCap = "hourly_cap" // the last hour cost more than cost_alerts.hourly_usd
+	KindSpike     = "spike"      // the last hour cost far more than the rig's baseline
+)
+
+// MaxHours is how many hourly buckets are kept per rig.
+const MaxHours = 7 * 24
+
+// minBaselineHours is how many past hours must be recorded before a rig's
+// baseline is trusted.
+const minBaselineHours = 3
+
+// Hour is a rig's spend within one clock hour.
+type Hour struct {
+	Start time.Time `json:"start"`
+	USD   float64   `json:"usd"`
+}
+
+// Sample is new spend observed at a point in time.
+type Sample struct {
+	At  time.Time `json:"at"`
+	USD float64   `json:"usd"`
+}
+
+// State is a rig's spend history.
+type State struct {
+	Rig     string            `json:"rig"`
+	Day     string            `json:"day,omitempty"`     // date of Total (YYYY-MM-DD, local)
+	Total   float64           `json:"total_usd"`         // highest spend seen so far on Day
+	Samples []Sample          `json:"samples,omitempty"` // new spend within the last hour
+	Hours   []Hour            `json:"hours,omitempty"`   // hourly buckets, oldest first
+	Alerted map[string]string `json:"alerted,omitempty"` // anomaly kind -> hour last alerted
+}
+
+// Anomaly is an alert about a rig's hourly spend.
+type Anomaly struct {
+	Rig         string    `json:"rig"`
+	Kind        string    `json:"kind"`
+	HourUSD     float64   `json:"hour_usd"`               // spend over the last hour
+	BaselineUSD float64   `json:"baseline_usd,omitempty"` // the rig's mean hourly spend
+	LimitUSD    float64   `json:"limit_usd"`              // the threshold crossed
+	At          time.Time `json:"at"`
+}
+
+// Summary describes the anomaly (e.g., "$12.40 in the last hour, 8.3x the
+// baseline of $1.50/hour").
+func (a *Anomaly) Summary() string {
+	if a.Kind == KindSpike {
+		return fmt.Sprintf("$%.2f in the last hour, %.1fx the baseline of $%.2f/hour",
+			a.HourUSD, a.HourUSD/a.BaselineUSD, a.BaselineUSD)
+	}
+	return fmt.Sprintf("$%.2f in the last hour, over the $%.2f/hour cap", a.HourUSD, a.LimitUSD)
+}
+
+// Dir returns the cost history directory for a town.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "costs")
+}
+
+// Path returns the state file path for a rig.
+func Path(townRoot, rig string) string {
+	return filepath.Join(Dir(townRoot), rig+".json")
+}
+
+// Load returns the history of rig, or an empty one if it has none.
+func Load(townRoot, rig string) (*State, error) {
+	data, err := os.ReadFile(Path(townRoot, rig)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &State{Rig: rig}, nil
+		}
+		return nil, err
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing cost history: %w", err)
+	}
+	s.Rig = rig
+	return &s, nil
+}
+
+// Save writes s to its state file.
+func (s *State) Save(townRoot string) error {
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(Path(townRoot, s.Rig), s)
+}
+
+// Record observes the rig's spend so far today. Only increases count as new
+// spend: a total that dips (a session's live cost vanishing before its cost
+// record lands) isn't counted again when it recovers. The first observation
+// of a day only sets the starting point, so spend already counted (or a
+// running session's cost carried over midnight) isn't mistaken for a spike.
+func (s *State) Record(now time.Time, todayUSD float64) {
+	day := now.Format("2006-01-02")
+	var spent float64
+	switch {
+	case s.Day != day:
+		s.Total = todayUSD
+	case todayUSD > s.Total:
+		spent = todayUSD - s.Total
+		s.Total = todayUSD
+	}
+	s.Day = day
+
+	if spent > 0 {
+		s.Samples = append(s.Samples, Sample{At: now, USD: spent})
+	}
+	cutoff := now.Add(-time.Hour)
+	for len(s.Samples) > 0 && !s.Samples[0].At.After(cutoff) {
+		s.Samples = s.Samples[1:]
+	}
+
+	// Every observed hour gets a bucket, even an idle one, so the
+	// baseline reflects quiet hours too
+	start := now.Truncate(time.Hour)
+	if n := len(s.Hours); n > 0 && s.Hours[n-1].Start.Equal(start) {
+		s.Hours[n-1].USD += spent
+	} else {
+		s.Hours = append(s.Hours, Hour{Start: start, USD: spent})
+	}
+	if len(s.Hours) > MaxHours {
+		s.Hours = s.Hours[len(s.Hours)-MaxHours:]
+	}
+}
+
+// LastHour returns the rig's spend over the trailing hour.
+func (s *State) LastHour() float64 {
+	var total float64
+	for _, sample := range s.Samples {
+		total += sample.USD
+	}
+	return total
+}
+
+// Baseline returns the mean spend of the rig's recorded hours within the
+// last hours before the current one, or false if too few are recorded to
+// trust.
+func (s *State) Baseline(now time.Time, hours int) (float64, bool) {
+	current := now.Truncate(time.Hour)
+	since := current.Add(-time.Duration(hours) * time.Hour)
+	var total float64
+	var n int
+	for _, h := range s.Hours {
+		if h.Start.Before(since) || !h.Start.Before(current) {
+			continue
+		}
+		total += h.USD
+		n++
+	}
+	if n < minBaselineHours {
+		return 0, false
+	}
+	return total / float64(n), true
+}
+
+// Check returns the anomalies in the rig's last hour under cfg's thresholds.
+// A rig that has spent nothing in its baseline hours can't spike; its first
+// work is only caught by the hourly cap.
+func (s *State) Check(now time.Time, cfg config.CostAlertsConfig) []*Anomaly {
+	hourlyUSD, spikeFactor, minSpikeUSD, baselineHours := cfg.Thresholds()
+	last := s.LastHour()
+	var found []*Anomaly
+	if hourlyUSD > 0 && last > hourlyUSD {
+		found = append(found, &Anomaly{Rig: s.Rig, Kind: KindHourlyCap, HourUSD: last, LimitUSD: hourlyUSD, At: now})
+	}
+	if spikeFactor > 0 && last >= minSpikeUSD {
+		if baseline, ok := s.Baseline(now, baselineHours); ok && baseline > 0 && last > baseline*spikeFactor {
+			found = append(found, &Anomaly{Rig: s.Rig, Kind: KindSpike, HourUSD: last, BaselineUSD: baseline,
+				LimitUSD: baseline * spikeFactor, At: now})
+		}
+	}
+	return found
+}
+
+// ShouldAlert reports whether a has not been alerted yet this clock hour.
+func (s *State) ShouldAlert(a *Anomaly) bool {
+	return s.Alerted[a.Kind] != hourKey(a.At)
+}
+
+// MarkAlerted records that a was alerted. The caller saves s.
+func (s *State) MarkAlerted(a *Anomaly) {
+	if s.Alerted == nil {
+		s.Alerted = make(map[string]string)
+	}
+	s.Alerted[a.Kind] = hourKey(a.At)
+}
+
+// hourKey identifies the clock hour of t.
+func hourKey(t time.Time) string {
+	return t.Format("2006-01-02T15")
+}
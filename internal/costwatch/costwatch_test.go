@@ -0,0 +1,135 @@
+package costwatch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestRecord(t *testing.T) {
+	start := time.Date(2026, 1, 2, 15, 0, 0, 0, time.Local)
+	s := &State{Rig: "gastown"}
+
+	// The first observation of a day only sets the starting point
+	s.Record(start, 40)
+	if got := s.LastHour(); got != 0 {
+		t.Errorf("after first observation: last hour = %v, want 0", got)
+	}
+	s.Record(start.Add(3*time.Minute), 42.5)
+	s.Record(start.Add(6*time.Minute), 41) // a dip isn't negative spend...
+	s.Record(start.Add(9*time.Minute), 43) // ...and its recovery isn't counted twice
+	if got := s.LastHour(); got != 3 {
+		t.Errorf("last hour = %v, want 3", got)
+	}
+
+	// Spend older than an hour drops out of the last hour but stays in its bucket
+	s.Record(start.Add(70*time.Minute), 44)
+	if got := s.LastHour(); got != 1 {
+		t.Errorf("an hour later: last hour = %v, want 1", got)
+	}
+	if len(s.Hours) != 2 || s.Hours[0].USD != 3 || s.Hours[1].USD != 1 {
+		t.Errorf("hours = %+v", s.Hours)
+	}
+
+	// A new day starts over without counting the new total as spend
+	s.Record(start.Add(10*time.Hour), 2)
+	if got := s.LastHour(); got != 0 {
+		t.Errorf("next day: last hour = %v, want 0", got)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 30, 0, 0, time.UTC)
+	s := &State{Rig: "gastown"}
+	for i := 6; i > 0; i-- {
+		s.Hours = append(s.Hours, Hour{Start: now.Truncate(time.Hour).Add(-time.Duration(i) * time.Hour), USD: 2})
+	}
+	s.Samples = []Sample{{At: now.Add(-10 * time.Minute), USD: 9}}
+
+	if baseline, ok := s.Baseline(now, 24); !ok || baseline != 2 {
+		t.Fatalf("baseline = %v, %v; want 2", baseline, ok)
+	}
+	found := s.Check(now, config.CostAlertsConfig{})
+	if len(found) != 1 || found[0].Kind != KindSpike {
+		t.Fatalf("$9 against a $2 baseline: anomalies = %+v, want a spike", found)
+	}
+
+	off := 0.0
+	found = s.Check(now, config.CostAlertsConfig{HourlyUSD: 5, SpikeFactor: &off})
+	if len(found) != 1 || found[0].Kind != KindHourlyCap || found[0].LimitUSD != 5 {
+		t.Fatalf("$9 against a $5 cap: anomalies = %+v, want hourly_cap", found)
+	}
+
+	// A spike below min_spike_usd is ignored
+	s.Samples = []Sample{{At: now, USD: 0.9}}
+	s.Hours = []Hour{{Start: now.Add(-3 * time.Hour)}, {Start: now.Add(-2 * time.Hour)}, {Start: now.Add(-time.Hour), USD: 0.1}}
+	if found := s.Check(now, config.CostAlertsConfig{}); len(found) != 0 {
+		t.Errorf("a 90-cent hour alerted: %+v", found)
+	}
+	if _, ok := (&State{}).Baseline(now, 24); ok {
+		t.Error("baseline from no history")
+	}
+}
+
+func TestAlertOncePerHour(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 30, 0, 0, time.UTC)
+	s := &State{Rig: "gastown"}
+	a := &Anomaly{Rig: "gastown", Kind: KindSpike, At: now}
+	if !s.ShouldAlert(a) {
+		t.Fatal("first anomaly not alerted")
+	}
+	s.MarkAlerted(a)
+	if s.ShouldAlert(&Anomaly{Kind: KindSpike, At: now.Add(20 * time.Minute)}) {
+		t.Error("alerted twice in the same hour")
+	}
+	if !s.ShouldAlert(&Anomaly{Kind: KindHourlyCap, At: now}) {
+		t.Error("a different kind was suppressed")
+	}
+	if !s.ShouldAlert(&Anomaly{Kind: KindSpike, At: now.Add(time.Hour)}) {
+		t.Error("not alerted again the next hour")
+	}
+
+	town := t.TempDir()
+	if err := s.Save(town); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(town, "gastown")
+	if err != nil || loaded.ShouldAlert(a) {
+		t.Errorf("alert state not persisted: %+v, %v", loaded, err)
+	}
+}
+
+func TestPost(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	a := &Anomaly{Rig: "gastown", Kind: KindHourlyCap, HourUSD: 25, LimitUSD: 20}
+	if err := PostWebhook(context.Background(), srv.URL, a); err != nil {
+		t.Fatal(err)
+	}
+	if got["event"] != "cost_anomaly" || got["rig"] != "gastown" || got["hour_usd"] != 25.0 {
+		t.Errorf("webhook payload = %v", got)
+	}
+	if err := PostSlack(context.Background(), srv.URL, a); err != nil {
+		t.Fatal(err)
+	}
+	if text, _ := got["text"].(string); text == "" {
+		t.Errorf("slack payload = %v", got)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no_team", http.StatusNotFound)
+	}))
+	defer failing.Close()
+	if err := PostSlack(context.Background(), failing.URL, a); err == nil {
+		t.Error("a failed post returned no error")
+	}
+}
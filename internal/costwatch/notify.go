@@ -0,0 +1,65 @@
+package costwatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// postTimeout bounds each webhook delivery, so an unreachable endpoint
+// doesn't stall the daemon's heartbeat.
+const postTimeout = 10 * time.Second
+
+// WebhookPayload is the JSON body posted to cost_alerts.webhook_url.
+type WebhookPayload struct {
+	Event string `json:"event"` // always "cost_anomaly"
+	Text  string `json:"text"`
+	*Anomaly
+}
+
+// Text returns the alert's one-line message.
+func (a *Anomaly) Text() string {
+	return fmt.Sprintf("Gas Town cost anomaly: rig %s spent %s", a.Rig, a.Summary())
+}
+
+// PostWebhook posts a to a generic JSON webhook.
+func PostWebhook(ctx context.Context, url string, a *Anomaly) error {
+	return post(ctx, url, WebhookPayload{Event: "cost_anomaly", Text: a.Text(), Anomaly: a})
+}
+
+// PostSlack posts a to a Slack incoming webhook.
+func PostSlack(ctx context.Context, url string, a *Anomaly) error {
+	return post(ctx, url, map[string]string{
+		"text": fmt.Sprintf(":rotating_light: %s\nFind the cause with `gt costs --by-rig` and `gt loop status`.", a.Text()),
+	})
+}
+
+func post(ctx context.Context, url string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encoding alert: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, postTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting alert: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("posting alert: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+// Package crashdump captures post-mortem bundles of agent sessions that
+// crashed or had to be killed.
+//
+// A bundle is a directory holding what's needed to work out what happened
+// after the session is gone:
+//
+//	crash.json      agent, session, exit status, reason, and capture time
+//	pane.txt        the last lines of the session's pane (the agent's
+//	                stdout and stderr, and the conversation it showed),
+//	                redacted like every capture
+//	events.jsonl    the agent's most recent event log entries
+//	git-status.txt  git status and recent commits of the agent's workspace
+//
+// Bundles live in <rig>/.runtime/crashes/<session>-<time>/, or
+// <town>/.runtime/crashes/ for town-level agents, and the oldest are pruned
+// beyond MaxBundles. Alerts about the crash include the bundle's path.
+package crashdump
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Capture limits.
+const (
+	DefaultLines  = 200 // pane lines captured
+	DefaultEvents = 50  // event log entries captured
+	MaxBundles    = 20  // bundles kept per rig (or town)
+)
+
+// timeFormat names bundles; it sorts chronologically.
+const timeFormat = "20060102-150405"
+
+// Pane reads an agent's tmux pane. *tmux.Tmux implements it.
+type Pane interface {
+	CapturePane(session string, lines int) (string, error)
+	GetPaneWorkDir(session string) (string, error)
+}
+
+// Options describe the session to capture.
+type Options struct {
+	TownRoot string
+	Rig      string // "" for town-level agents (mayor, deacon)
+	Agent    string // the agent's event log actor (e.g., "gastown/Toast")
+	Session  string // tmux session name
+	WorkDir  string // the agent's workspace; "" = the pane's working directory
+	ExitCode int    // the agent's exit status; -1 if unknown
+	Reason   string // why the bundle was captured (e.g., "exit code 1")
+	Lines    int    // pane lines to capture (default DefaultLines)
+}
+
+// Info is a bundle's crash.json.
+type Info struct {
+	Agent      string    `json:"agent"`
+	Rig        string    `json:"rig,omitempty"`
+	Session    string    `json:"session"`
+	WorkDir    string    `json:"work_dir,omitempty"`
+	ExitCode   int       `json:"exit_code"`
+	Reason     string    `json:"reason,omitempty"`
+	CapturedAt time.Time `json:"captured_at"`
+	Files      []string  `json:"files"`
+	Errors     []string  `json:"errors,omitempty"` // parts that couldn't be captured
+}
+
+// Dir returns the directory holding the bundles of rig, or the town's when
+// rig is "".
+func Dir(townRoot, rig string) string {
+	return filepath.Join(townRoot, rig, constants.DirRuntime, "crashes")
+}
+
+// Capture writes a bundle for the session described by opts and returns its
+// directory. pane may be nil, or the session already gone; the bundle then
+// has no pane.txt. Parts that can't be captured are listed in crash.json's
+// errors rather than failing the capture.
+func Capture(pane Pane, opts Options) (string, error) {
+	now := time.Now().UTC()
+	dir := filepath.Join(Dir(opts.TownRoot, opts.Rig), bundleName(opts.Session, now))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating crash dump directory: %w", err)
+	}
+	info := &Info{
+		Agent:      opts.Agent,
+		Rig:        opts.Rig,
+		Session:    opts.Session,
+		WorkDir:    opts.WorkDir,
+		ExitCode:   opts.ExitCode,
+		Reason:     opts.Reason,
+		CapturedAt: now,
+	}
+	write := func(name string, data []byte) {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			info.Errors = append(info.Errors, fmt.Sprintf("%s: %v", name, err))
+			return
+		}
+		info.Files = append(info.Files, name)
+	}
+
+	if pane != nil && opts.Session != "" {
+		lines := opts.Lines
+		if lines <= 0 {
+			lines = DefaultLines
+		}
+		if out, err := pane.CapturePane(opts.Session, lines); err != nil {
+			info.Errors = append(info.Errors, fmt.Sprintf("pane: %v", err))
+		} else {
+			write("pane.txt", []byte(out))
+		}
+		if info.WorkDir == "" {
+			info.WorkDir, _ = pane.GetPaneWorkDir(opts.Session)
+		}
+	}
+
+	if opts.Agent != "" {
+		evs, err := events.Query(opts.TownRoot, events.Filter{Actor: opts.Agent, Limit: DefaultEvents})
+		if err != nil {
+			info.Errors = append(info.Errors, fmt.Sprintf("events: %v", err))
+		} else {
+			var b strings.Builder
+			for _, e := range evs {
+				data, _ := json.Marshal(e)
+				b.Write(data)
+				b.WriteByte('\n')
+			}
+			write("events.jsonl", []byte(b.String()))
+		}
+	}
+
+	if info.WorkDir != "" {
+		if out, err := gitState(info.WorkDir); err != nil {
+			info.Errors = append(info.Errors, fmt.Sprintf("git: %v", err))
+		} else {
+			write("git-status.txt", []byte(out))
+		}
+	}
+
+	sort.Strings(info.Files)
+	if err := util.AtomicWriteJSON(filepath.Join(dir, "crash.json"), info); err != nil {
+		return "", fmt.Errorf("writing crash.json: %w", err)
+	}
+	prune(Dir(opts.TownRoot, opts.Rig), MaxBundles)
+	return dir, nil
+}
+
+// Latest returns the directory of the newest bundle of session captured
+// within the last window, or "" if there is none. The daemon uses it to
+// point at the bundle the pane-died hook already captured.
+func Latest(townRoot, rig, session string, window time.Duration) string {
+	entries, err := os.ReadDir(Dir(townRoot, rig))
+	if err != nil {
+		return ""
+	}
+	cutoff := time.Now().UTC().Add(-window)
+	for i := len(entries) - 1; i >= 0; i-- {
+		name := entries[i].Name()
+		stamp, ok := strings.CutPrefix(name, sanitize(session)+"-")
+		if !ok || !entries[i].IsDir() {
+			continue
+		}
+		t, err := time.Parse(timeFormat, stamp)
+		if err != nil || t.Before(cutoff) {
+			continue
+		}
+		return filepath.Join(Dir(townRoot, rig), name)
+	}
+	return ""
+}
+
+// Load reads the crash.json of the bundle in dir.
+func Load(dir string) (*Info, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "crash.json"))
+	if err != nil {
+		return nil, err
+	}
+	var info Info
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("parsing crash.json: %w", err)
+	}
+	return &info, nil
+}
+
+// gitState returns the git status and last few commits of workDir.
+func gitState(workDir string) (string, error) {
+	status, err := exec.Command("git", "-C", workDir, "status", "--branch", "--short").CombinedOutput() //nolint:gosec // G204: workDir is an agent workspace
+	if err != nil {
+		return "", fmt.Errorf("%v: %s", err, strings.TrimSpace(string(status)))
+	}
+	log, _ := exec.Command("git", "-C", workDir, "log", "--oneline", "-5").CombinedOutput() //nolint:gosec // G204: workDir is an agent workspace
+	return fmt.Sprintf("$ git status --branch --short\n%s\n$ git log --oneline -5\n%s", status, log), nil
+}
+
+// bundleName names a bundle of session captured at t.
+func bundleName(session string, t time.Time) string {
+	return sanitize(session) + "-" + t.Format(timeFormat)
+}
+
+// sanitize makes a session name safe as a path component.
+func sanitize(session string) string {
+	if session == "" {
+		return "unknown"
+	}
+	return strings.NewReplacer("/", "_", string(filepath.Separator), "_").Replace(session)
+}
+
+// prune removes the oldest bundles in dir beyond keep.
+func prune(dir string, keep int) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	var bundles []os.DirEntry
+	for _, e := range entries {
+		if e.IsDir() {
+			bundles = append(bundles, e)
+		}
+	}
+	if len(bundles) <= keep {
+		return
+	}
+	// Oldest first: names end in a sortable timestamp
+	sort.Slice(bundles, func(i, j int) bool { return stampOf(bundles[i].Name()) < stampOf(bundles[j].Name()) })
+	for _, e := range bundles[:len(bundles)-keep] {
+		_ = os.RemoveAll(filepath.Join(dir, e.Name()))
+	}
+}
+
+// stampOf returns the timestamp suffix of a bundle name.
+func stampOf(name string) string {
+	if len(name) < len(timeFormat) {
+		return name
+	}
+	return name[len(name)-len(timeFormat):]
+}
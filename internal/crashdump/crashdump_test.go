@@ -0,0 +1,130 @@
+package crashdump
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakePane struct {
+	out     string
+	workDir string
+	err     error
+}
+
+func (p fakePane) CapturePane(session string, lines int) (string, error) { return p.out, p.err }
+func (p fakePane) GetPaneWorkDir(session string) (string, error)         { return p.workDir, p.err }
+
+func TestCapture(t *testing.T) {
+	town := t.TempDir()
+	work := t.TempDir()
+	if err := exec.Command("git", "-C", work, "init", "-q").Run(); err != nil {
+		t.Skip("git not available")
+	}
+	if err := os.WriteFile(filepath.Join(work, "dirty.go"), []byte("package x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	events := `{"ts":"2026-01-02T15:04:05Z","source":"gt","type":"sling","actor":"gastown/Toast"}
+{"ts":"2026-01-02T15:04:06Z","source":"gt","type":"sling","actor":"gastown/Nux"}
+`
+	if err := os.WriteFile(filepath.Join(town, ".events.jsonl"), []byte(events), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := Capture(fakePane{out: "panic: boom\n", workDir: work}, Options{
+		TownRoot: town,
+		Rig:      "gastown",
+		Agent:    "gastown/Toast",
+		Session:  "gt-gastown-Toast",
+		ExitCode: 2,
+		Reason:   "exit code 2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(dir, Dir(town, "gastown")) {
+		t.Errorf("bundle %s not under the rig's crash directory", dir)
+	}
+
+	info, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ExitCode != 2 || info.WorkDir != work || len(info.Errors) != 0 {
+		t.Errorf("info = %+v", info)
+	}
+	if want := []string{"events.jsonl", "git-status.txt", "pane.txt"}; strings.Join(info.Files, ",") != strings.Join(want, ",") {
+		t.Errorf("files = %v, want %v", info.Files, want)
+	}
+	check := func(name, want string) {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), want) {
+			t.Errorf("%s = %q, want it to contain %q", name, data, want)
+		}
+	}
+	check("pane.txt", "panic: boom")
+	check("events.jsonl", "gastown/Toast")
+	check("git-status.txt", "dirty.go")
+	if data, _ := os.ReadFile(filepath.Join(dir, "events.jsonl")); strings.Contains(string(data), "gastown/Nux") {
+		t.Error("events.jsonl includes another agent's events")
+	}
+
+	if got := Latest(town, "gastown", "gt-gastown-Toast", time.Minute); got != dir {
+		t.Errorf("Latest = %q, want %q", got, dir)
+	}
+	if got := Latest(town, "gastown", "gt-gastown-Nux", time.Minute); got != "" {
+		t.Errorf("Latest for another session = %q, want none", got)
+	}
+}
+
+func TestCaptureDeadSession(t *testing.T) {
+	town := t.TempDir()
+	dir, err := Capture(fakePane{err: errors.New("can't find session")}, Options{
+		TownRoot: town,
+		Agent:    "deacon/",
+		Session:  "hq-deacon",
+		ExitCode: -1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(dir, Dir(town, "")) {
+		t.Errorf("town-level bundle %s not under the town's crash directory", dir)
+	}
+	info, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Errors) == 0 || !strings.HasPrefix(info.Errors[0], "pane:") {
+		t.Errorf("errors = %v, want the pane failure recorded", info.Errors)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		// Alternate sessions so name order differs from time order
+		session := []string{"gt-b", "gt-a"}[i%2]
+		if err := os.Mkdir(filepath.Join(dir, bundleName(session, base.Add(time.Duration(i)*time.Minute))), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	prune(dir, 2)
+	entries, _ := os.ReadDir(dir)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	want := []string{bundleName("gt-a", base.Add(3*time.Minute)), bundleName("gt-b", base.Add(4*time.Minute))}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("kept %v, want %v", names, want)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -12,10 +13,15 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/concurrency"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/logging"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
 )
@@ -39,6 +45,9 @@ type StartOptions struct {
 	// If set, this is injected as an environment variable.
 	ClaudeConfigDir string
 
+	// AccountEnv is extra startup environment for the account (its API key).
+	AccountEnv map[string]string
+
 	// KillExisting kills any existing session before starting (for restart operations).
 	// If false and a session is running, Start() returns ErrSessionRunning.
 	KillExisting bool
@@ -84,6 +93,11 @@ type Manager struct {
 	git *git.Git
 }
 
+// log returns the crew component's logger for this rig.
+func (m *Manager) log() *slog.Logger {
+	return logging.For(logging.ComponentCrew).With("rig", m.rig.Name)
+}
+
 // NewManager creates a new crew manager.
 func NewManager(r *rig.Rig, g *git.Git) *Manager {
 	return &Manager{
@@ -130,7 +144,11 @@ func (m *Manager) Add(name string, createBranch bool) (*CrewWorker, error) {
 		return nil, fmt.Errorf("creating crew dir: %w", err)
 	}
 
-	// Clone the rig repo
+	// Clone the rig repo, as shallow/partial as the rig's own clones,
+	// limited to the rig's sparse checkout paths, with its forge credentials
+	m.git.SetCloneOptions(m.rig.CloneOptions())
+	m.git.SetSparsePaths(m.rig.SparsePaths())
+	m.git.SetCredentialHelper(m.rig.CredentialHelper())
 	if m.rig.LocalRepo != "" {
 		if err := m.git.CloneWithReference(m.rig.GitURL, crewPath, m.rig.LocalRepo); err != nil {
 			fmt.Printf("Warning: could not clone with local repo reference: %v\n", err)
@@ -151,11 +169,11 @@ func (m *Manager) Add(name string, createBranch bool) (*CrewWorker, error) {
 	if createBranch {
 		branchName = fmt.Sprintf("crew/%s", name)
 		if err := crewGit.CreateBranch(branchName); err != nil {
-			_ = os.RemoveAll(crewPath) // best-effort cleanup
+			logging.WarnIf(m.log(), "removing partial crew workspace", os.RemoveAll(crewPath))
 			return nil, fmt.Errorf("creating branch: %w", err)
 		}
 		if err := crewGit.Checkout(branchName); err != nil {
-			_ = os.RemoveAll(crewPath) // best-effort cleanup
+			logging.WarnIf(m.log(), "removing partial crew workspace", os.RemoveAll(crewPath))
 			return nil, fmt.Errorf("checking out branch: %w", err)
 		}
 	}
@@ -163,7 +181,7 @@ func (m *Manager) Add(name string, createBranch bool) (*CrewWorker, error) {
 	// Create mail directory for mail delivery
 	mailPath := m.mailDir(name)
 	if err := os.MkdirAll(mailPath, 0755); err != nil {
-		_ = os.RemoveAll(crewPath) // best-effort cleanup
+		logging.WarnIf(m.log(), "removing partial crew workspace", os.RemoveAll(crewPath))
 		return nil, fmt.Errorf("creating mail dir: %w", err)
 	}
 
@@ -209,7 +227,7 @@ func (m *Manager) Add(name string, createBranch bool) (*CrewWorker, error) {
 
 	// Save state
 	if err := m.saveState(crew); err != nil {
-		_ = os.RemoveAll(crewPath) // best-effort cleanup
+		logging.WarnIf(m.log(), "removing partial crew workspace", os.RemoveAll(crewPath))
 		return nil, fmt.Errorf("saving state: %w", err)
 	}
 
@@ -350,7 +368,7 @@ func (m *Manager) Rename(oldName, newName string) error {
 	crew, err := m.loadState(newName)
 	if err != nil {
 		// Rollback on error (best-effort)
-		_ = os.Rename(newPath, oldPath)
+		logging.WarnIf(m.log(), "rolling back crew rename", os.Rename(newPath, oldPath))
 		return fmt.Errorf("loading state: %w", err)
 	}
 
@@ -360,7 +378,7 @@ func (m *Manager) Rename(oldName, newName string) error {
 
 	if err := m.saveState(crew); err != nil {
 		// Rollback on error (best-effort)
-		_ = os.Rename(newPath, oldPath)
+		logging.WarnIf(m.log(), "rolling back crew rename", os.Rename(newPath, oldPath))
 		return fmt.Errorf("saving state: %w", err)
 	}
 
@@ -379,6 +397,7 @@ func (m *Manager) Pristine(name string) (*PristineResult, error) {
 
 	crewPath := m.crewDir(name)
 	crewGit := git.NewGit(crewPath)
+	crewGit.SetCloneOptions(m.rig.CloneOptions())
 
 	result := &PristineResult{
 		Name: name,
@@ -391,8 +410,11 @@ func (m *Manager) Pristine(name string) (*PristineResult, error) {
 	}
 	result.HadChanges = hasChanges
 
-	// Pull latest (use origin and current branch)
-	if err := crewGit.Pull("origin", ""); err != nil {
+	// Pull latest (use origin and current branch), keeping uncommitted
+	// work stashed across the pull
+	if err := crewGit.WithStash("pristine", func() error {
+		return crewGit.Pull("origin", "")
+	}); err != nil {
 		result.PullError = err.Error()
 	} else {
 		result.Pulled = true
@@ -434,12 +456,12 @@ func (m *Manager) setupSharedBeads(crewPath string) error {
 
 // SessionName returns the tmux session name for a crew member.
 func (m *Manager) SessionName(name string) string {
-	return fmt.Sprintf("gt-%s-crew-%s", m.rig.Name, name)
+	return session.CrewSessionName(m.rig.Name, name)
 }
 
 // Start creates and starts a tmux session for a crew member.
 // If the crew member doesn't exist, it will be created first.
-func (m *Manager) Start(name string, opts StartOptions) error {
+func (m *Manager) Start(name string, opts StartOptions) (err error) {
 	if err := validateCrewName(name); err != nil {
 		return err
 	}
@@ -457,6 +479,14 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 
 	t := tmux.NewTmux()
 	sessionID := m.SessionName(name)
+	townRoot := filepath.Dir(m.rig.Path)
+
+	agent, model, maxTokens := config.RoleAgentModel(constants.RoleCrew, townRoot, m.rig.Path)
+	if opts.AgentOverride != "" {
+		agent = opts.AgentOverride
+	}
+	ctx, span := telemetry.StartSession(constants.RoleCrew, m.rig.Name, sessionID, agent, model, maxTokens)
+	defer func() { telemetry.End(span, err) }()
 
 	// Check if session already exists
 	running, err := t.HasSession(sessionID)
@@ -481,6 +511,20 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 		}
 	}
 
+	// Respect the town's concurrent session limits
+	if err := concurrency.Check(filepath.Dir(m.rig.Path), t, constants.RoleCrew, m.rig.Name); err != nil {
+		return err
+	}
+
+	// Refuse to start in a corrupted or mis-pointed clone. Crew workspaces
+	// are long-lived, so work in progress is expected.
+	if err := git.NewGit(worker.ClonePath).VerifyWorkspace(git.VerifyOptions{
+		RemoteURL: m.rig.GitURL,
+		Resuming:  true,
+	}); err != nil {
+		return fmt.Errorf("refusing to start %s: %w", sessionID, err)
+	}
+
 	// Ensure Claude settings exist in crew/ (not crew/<name>/) so we don't
 	// write into the source repo. Claude walks up the tree to find settings.
 	// All crew members share the same settings file.
@@ -513,16 +557,18 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 	if opts.Interactive {
 		claudeCmd = strings.Replace(claudeCmd, " --dangerously-skip-permissions", "", 1)
 	}
+	claudeCmd = config.PrependEnv(claudeCmd, opts.AccountEnv)
+	claudeCmd = config.PrependEnv(claudeCmd, telemetry.Env(ctx))
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
 	if err := t.NewSessionWithCommand(sessionID, worker.ClonePath, claudeCmd); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
+	_ = events.LogAudit(events.TypeAgentStarted, address, events.AgentPayload(m.rig.Name, constants.RoleCrew, sessionID, agent))
 
 	// Set environment variables (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
-	townRoot := filepath.Dir(m.rig.Path)
 	envVars := config.AgentEnv(config.AgentEnvConfig{
 		Role:             "crew",
 		Rig:              m.rig.Name,
@@ -531,16 +577,17 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 		RuntimeConfigDir: opts.ClaudeConfigDir,
 		BeadsNoDaemon:    true,
 	})
+	log := logging.WithSession(m.log(), sessionID)
 	for k, v := range envVars {
-		_ = t.SetEnvironment(sessionID, k, v)
+		logging.DebugIf(log, "SetEnvironment "+k, t.SetEnvironment(sessionID, k, v))
 	}
 
 	// Apply rig-based theming (non-fatal: theming failure doesn't affect operation)
 	theme := tmux.AssignTheme(m.rig.Name)
-	_ = t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, name, "crew")
+	logging.DebugIf(log, "ConfigureGasTownSession", t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, name, "crew"))
 
 	// Set up C-b n/p keybindings for crew session cycling (non-fatal)
-	_ = t.SetCrewCycleBindings(sessionID)
+	logging.DebugIf(log, "SetCrewCycleBindings", t.SetCrewCycleBindings(sessionID))
 
 	// Note: We intentionally don't wait for Claude to start here.
 	// The session is created in detached mode, and blocking for 60 seconds
@@ -572,6 +619,8 @@ func (m *Manager) Stop(name string) error {
 	if err := t.KillSession(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
+	_ = events.LogAudit(events.TypeAgentStopped, fmt.Sprintf("%s/crew/%s", m.rig.Name, name),
+		events.AgentPayload(m.rig.Name, constants.RoleCrew, sessionID, ""))
 
 	return nil
 }
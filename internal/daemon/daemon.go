@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -20,12 +21,15 @@ import (
 	"github.com/steveyegge/gastown/internal/boot"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/crashdump"
 	"github.com/steveyegge/gastown/internal/deacon"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/feed"
+	"github.com/steveyegge/gastown/internal/logging"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/scheduler"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/wisp"
@@ -44,10 +48,18 @@ type Daemon struct {
 	cancel        context.CancelFunc
 	curator       *feed.Curator
 	convoyWatcher *ConvoyWatcher
+	scheduler     *scheduler.Scheduler
 
 	// Mass death detection: track recent session deaths
 	deathsMu     sync.Mutex
 	recentDeaths []sessionDeath
+
+	// Crash loop detection: recent crashes per polecat session (guarded by
+	// deathsMu)
+	crashes map[string][]time.Time
+
+	// lastEventPrune is when the event log's retention was last applied
+	lastEventPrune time.Time
 }
 
 // sessionDeath records a detected session death for mass death analysis.
@@ -62,6 +74,15 @@ const (
 	massDeathThreshold = 3                // Number of deaths to trigger alert
 )
 
+// Crash loop detection parameters
+const (
+	crashLoopWindow    = time.Hour // Time window to detect a crash loop
+	crashLoopThreshold = 3         // Crashes of one session to trigger alert
+)
+
+// eventPruneInterval is how often the event log's retention is applied.
+const eventPruneInterval = 24 * time.Hour
+
 // New creates a new daemon instance.
 func New(config *Config) (*Daemon, error) {
 	// Ensure daemon directory exists
@@ -76,7 +97,8 @@ func New(config *Config) (*Daemon, error) {
 		return nil, fmt.Errorf("opening log file: %w", err)
 	}
 
-	logger := log.New(logFile, "", log.LstdFlags)
+	// Structured records (component=daemon) in the configured format
+	logger := slog.NewLogLogger(logging.New(logFile, logging.ComponentDaemon).Handler(), slog.LevelInfo)
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &Daemon{
@@ -152,6 +174,9 @@ func (d *Daemon) Run() error {
 		d.logger.Println("Convoy watcher started")
 	}
 
+	// Start scheduler for recurring jobs (settings/schedules.json)
+	d.startScheduler()
+
 	// Initial heartbeat
 	d.heartbeat(state)
 
@@ -180,6 +205,32 @@ func (d *Daemon) Run() error {
 	}
 }
 
+// startScheduler loads settings/schedules.json and starts the recurring job scheduler.
+// A missing config is not an error - the scheduler simply has no jobs.
+func (d *Daemon) startScheduler() {
+	cfg, err := config.LoadOrCreateSchedulesConfig(config.SchedulesConfigPath(d.config.TownRoot))
+	if err != nil {
+		d.logger.Printf("Warning: failed to load schedules config: %v", err)
+		return
+	}
+
+	s, err := scheduler.New(d.config.TownRoot, cfg, d.logger.Printf)
+	if err != nil {
+		d.logger.Printf("Warning: invalid schedules config: %v", err)
+		return
+	}
+	if len(s.Jobs()) == 0 {
+		return
+	}
+
+	if err := s.Start(); err != nil {
+		d.logger.Printf("Warning: failed to start scheduler: %v", err)
+		return
+	}
+	d.scheduler = s
+	d.logger.Printf("Scheduler started (%d jobs)", len(s.Jobs()))
+}
+
 // recoveryHeartbeatInterval is the fixed interval for recovery-focused daemon.
 // Normal wake is handled by feed subscription (bd activity --follow).
 // The daemon is a safety net for dead sessions, GUPP violations, and orphaned work.
@@ -207,11 +258,11 @@ func (d *Daemon) heartbeat(state *State) {
 	// Boot may not detect all stuck states; this provides a fallback
 	d.checkDeaconHeartbeat()
 
-	// 4. Ensure Witnesses are running for all rigs (restart if dead)
-	d.ensureWitnessesRunning()
+	// 4. Reconcile sessions with desired town state: start missing or dead
+	// Witnesses and Refineries, stop orphaned sessions, adopt hand-started ones
+	d.reconcile()
 
-	// 5. Ensure Refineries are running for all rigs (restart if dead)
-	d.ensureRefineriesRunning()
+	// 5. (Merged into 4) Refineries are started by the reconciler
 
 	// 6. Trigger pending polecat spawns (bootstrap mode - ZFC violation acceptable)
 	// This ensures polecats get nudged even when Deacon isn't in a patrol cycle.
@@ -233,6 +284,45 @@ func (d *Daemon) heartbeat(state *State) {
 	// This validates tmux sessions are still alive for polecats with work-on-hook
 	d.checkPolecatSessionHealth()
 
+	// 12. Expire work leases (return abandoned hooked beads to the ready queue)
+	d.expireWorkLeases()
+
+	// 13. Enforce per-rig budgets (pause sessions, alert mayor)
+	d.checkBudgets()
+
+	// 14. Escalate beads that breached their priority's SLA
+	d.checkSLAs()
+
+	// 15. Detect rate-limited accounts so new sessions rotate off them
+	d.checkAccounts()
+
+	// 16. Apply the event log's retention policy
+	d.pruneEvents()
+
+	// 17. Pause sessions burning tokens far faster than the rest
+	d.checkLoops()
+
+	// 18. Record the town's rolled-up health, after this heartbeat's repairs
+	d.checkHealth()
+
+	// 19. Post new session, escalation, and merge events to Slack
+	d.syncSlack()
+
+	// 20. And to Discord
+	d.syncDiscord()
+
+	// 21. Email budget, crash loop, and high-severity escalation events
+	d.syncEmail()
+
+	// 22. Import Jira issues and report work on them back to Jira
+	d.syncJira()
+
+	// 23. Import Linear issues and report work on them back to Linear
+	d.syncLinear()
+
+	// 24. Open and resolve PagerDuty/Opsgenie incidents for critical conditions
+	d.syncIncidents()
+
 	// Update state
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
@@ -386,15 +476,6 @@ func (d *Daemon) checkDeaconHeartbeat() {
 	}
 }
 
-// ensureWitnessesRunning ensures witnesses are running for all rigs.
-// Called on each heartbeat to maintain witness patrol loops.
-func (d *Daemon) ensureWitnessesRunning() {
-	rigs := d.getKnownRigs()
-	for _, rigName := range rigs {
-		d.ensureWitnessRunning(rigName)
-	}
-}
-
 // ensureWitnessRunning ensures the witness for a specific rig is running.
 // Discover, don't track: uses Manager.Start() which checks tmux directly (gt-zecmc).
 func (d *Daemon) ensureWitnessRunning(rigName string) {
@@ -425,15 +506,6 @@ func (d *Daemon) ensureWitnessRunning(rigName string) {
 	d.logger.Printf("Witness session for %s started successfully", rigName)
 }
 
-// ensureRefineriesRunning ensures refineries are running for all rigs.
-// Called on each heartbeat to maintain refinery merge queue processing.
-func (d *Daemon) ensureRefineriesRunning() {
-	rigs := d.getKnownRigs()
-	for _, rigName := range rigs {
-		d.ensureRefineryRunning(rigName)
-	}
-}
-
 // ensureRefineryRunning ensures the refinery for a specific rig is running.
 // Discover, don't track: uses Manager.Start() which checks tmux directly (gt-zecmc).
 func (d *Daemon) ensureRefineryRunning(rigName string) {
@@ -594,6 +666,12 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("Convoy watcher stopped")
 	}
 
+	// Stop scheduler (waits for in-flight job runs)
+	if d.scheduler != nil {
+		d.scheduler.Stop()
+		d.logger.Println("Scheduler stopped")
+	}
+
 	state.Running = false
 	if err := SaveState(d.config.TownRoot, state); err != nil {
 		d.logger.Printf("Warning: failed to save final state: %v", err)
@@ -736,7 +814,7 @@ func listPolecatWorktrees(polecatsDir string) ([]string, error) {
 // If the polecat has work-on-hook but the tmux session is dead, it's restarted.
 func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	// Build the expected tmux session name
-	sessionName := fmt.Sprintf("gt-%s-%s", rigName, polecatName)
+	sessionName := session.PolecatSessionName(rigName, polecatName)
 
 	// Check if tmux session exists
 	sessionAlive, err := d.tmux.HasSession(sessionName)
@@ -771,19 +849,57 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	d.logger.Printf("CRASH DETECTED: polecat %s/%s has hook_bead=%s but session %s is dead",
 		rigName, polecatName, info.HookBead, sessionName)
 
-	// Track this death for mass death detection
+	// Track this death for mass death and crash loop detection
 	d.recordSessionDeath(sessionName)
+	d.recordCrash(rigName, polecatName, sessionName)
+
+	dump := d.crashDump(rigName, polecatName, sessionName)
+	if dump != "" {
+		d.logger.Printf("Crash dump for %s: %s", sessionName, dump)
+	}
 
 	// Auto-restart the polecat
 	if err := d.restartPolecatSession(rigName, polecatName, sessionName); err != nil {
 		d.logger.Printf("Error restarting polecat %s/%s: %v", rigName, polecatName, err)
 		// Notify witness as fallback
-		d.notifyWitnessOfCrashedPolecat(rigName, polecatName, info.HookBead, err)
+		d.notifyWitnessOfCrashedPolecat(rigName, polecatName, info.HookBead, dump, err)
 	} else {
 		d.logger.Printf("Successfully restarted crashed polecat %s/%s", rigName, polecatName)
 	}
 }
 
+// crashDumpWindow is how recent a crash dump must be to belong to the crash
+// the daemon just detected.
+const crashDumpWindow = 10 * time.Minute
+
+// crashDump returns the post-mortem bundle of a crashed polecat session: the
+// one its pane-died hook captured, or a new one without the pane (the
+// session is gone) if the hook didn't run. Returns "" if none could be
+// written.
+func (d *Daemon) crashDump(rigName, polecatName, sessionName string) string {
+	if dir := crashdump.Latest(d.config.TownRoot, rigName, sessionName, crashDumpWindow); dir != "" {
+		return dir
+	}
+	workDir := filepath.Join(d.config.TownRoot, rigName, "polecats", polecatName, rigName)
+	if _, err := os.Stat(workDir); err != nil {
+		workDir = filepath.Join(d.config.TownRoot, rigName, "polecats", polecatName)
+	}
+	dir, err := crashdump.Capture(nil, crashdump.Options{
+		TownRoot: d.config.TownRoot,
+		Rig:      rigName,
+		Agent:    rigName + "/" + polecatName,
+		Session:  sessionName,
+		WorkDir:  workDir,
+		ExitCode: -1,
+		Reason:   "session died with work on hook",
+	})
+	if err != nil {
+		d.logger.Printf("Warning: capturing crash dump for %s: %v", sessionName, err)
+		return ""
+	}
+	return dir
+}
+
 // recordSessionDeath records a session death and checks for mass death pattern.
 func (d *Daemon) recordSessionDeath(sessionName string) {
 	d.deathsMu.Lock()
@@ -834,6 +950,38 @@ func (d *Daemon) emitMassDeathEvent() {
 	d.recentDeaths = nil
 }
 
+// recordCrash records a polecat session crash and logs a crash loop event
+// when the same session has crashed crashLoopThreshold times within
+// crashLoopWindow, even though each restart succeeded.
+func (d *Daemon) recordCrash(rigName, polecatName, sessionName string) {
+	d.deathsMu.Lock()
+	defer d.deathsMu.Unlock()
+
+	now := time.Now()
+	cutoff := now.Add(-crashLoopWindow)
+	var recent []time.Time
+	for _, t := range d.crashes[sessionName] {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	recent = append(recent, now)
+	if d.crashes == nil {
+		d.crashes = make(map[string][]time.Time)
+	}
+	d.crashes[sessionName] = recent
+
+	if len(recent) < crashLoopThreshold {
+		return
+	}
+	d.logger.Printf("CRASH LOOP DETECTED: %s crashed %d times in %s", sessionName, len(recent), crashLoopWindow)
+	_ = events.LogFeed(events.TypeCrashLoop, "daemon",
+		events.CrashLoopPayload(rigName, fmt.Sprintf("%s/polecats/%s", rigName, polecatName), sessionName, len(recent), crashLoopWindow.String()))
+
+	// Start counting again, to avoid repeated alerts
+	delete(d.crashes, sessionName)
+}
+
 // restartPolecatSession restarts a crashed polecat session.
 func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string) error {
 	// Check rig operational state before auto-restarting
@@ -907,16 +1055,21 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 }
 
 // notifyWitnessOfCrashedPolecat notifies the witness when a polecat restart fails.
-func (d *Daemon) notifyWitnessOfCrashedPolecat(rigName, polecatName, hookBead string, restartErr error) {
+// dump is the crash's post-mortem bundle, if one was captured.
+func (d *Daemon) notifyWitnessOfCrashedPolecat(rigName, polecatName, hookBead, dump string, restartErr error) {
 	witnessAddr := rigName + "/witness"
 	subject := fmt.Sprintf("CRASHED_POLECAT: %s/%s restart failed", rigName, polecatName)
+	if dump == "" {
+		dump = "(none captured)"
+	}
 	body := fmt.Sprintf(`Polecat %s crashed and automatic restart failed.
 
 hook_bead: %s
 restart_error: %v
+crash_dump: %s
 
 Manual intervention may be required.`,
-		polecatName, hookBead, restartErr)
+		polecatName, hookBead, restartErr, dump)
 
 	cmd := exec.Command("gt", "mail", "send", witnessAddr, "-s", subject, "-m", body) //nolint:gosec // G204: args are constructed internally
 	cmd.Dir = d.config.TownRoot
@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/deacon"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -321,12 +322,14 @@ func (d *Daemon) identityToSession(identity string) string {
 		return session.MayorSessionName()
 	case "deacon":
 		return session.DeaconSessionName()
-	case "witness", "refinery":
-		return fmt.Sprintf("gt-%s-%s", parsed.RigName, parsed.RoleType)
+	case "witness":
+		return session.WitnessSessionName(parsed.RigName)
+	case "refinery":
+		return session.RefinerySessionName(parsed.RigName)
 	case "crew":
-		return fmt.Sprintf("gt-%s-crew-%s", parsed.RigName, parsed.AgentName)
+		return session.CrewSessionName(parsed.RigName, parsed.AgentName)
 	case "polecat":
-		return fmt.Sprintf("gt-%s-%s", parsed.RigName, parsed.AgentName)
+		return session.PolecatSessionName(parsed.RigName, parsed.AgentName)
 	default:
 		return ""
 	}
@@ -836,7 +839,7 @@ func (d *Daemon) checkRigGUPPViolations(rigName string) {
 		// Per gt-zecmc: derive running state from tmux, not agent_state
 		// Extract polecat name from agent ID (<prefix>-<rig>-polecat-<name> -> <name>)
 		polecatName := strings.TrimPrefix(agent.ID, prefix)
-		sessionName := fmt.Sprintf("gt-%s-%s", rigName, polecatName)
+		sessionName := session.PolecatSessionName(rigName, polecatName)
 
 		// Check if tmux session exists and Claude is running
 		if d.tmux.IsClaudeRunning(sessionName) {
@@ -891,6 +894,254 @@ func (d *Daemon) checkOrphanedWork() {
 	}
 }
 
+// expireWorkLeases returns beads whose work lease expired without activity
+// to the ready queue, so work hooked by a stalled worker is picked up again.
+func (d *Daemon) expireWorkLeases() {
+	result, err := deacon.ExpireLeases(d.config.TownRoot, nil)
+	if err != nil {
+		d.logger.Printf("Warning: lease expiry failed: %v", err)
+		return
+	}
+	for _, r := range result.Results {
+		switch r.Action {
+		case "released":
+			d.logger.Printf("Lease on %s expired (%s, holder %s): returned to ready queue", r.BeadID, r.Reason, r.Holder)
+		case "error":
+			d.logger.Printf("Warning: could not release %s after lease expiry: %s", r.BeadID, r.Error)
+		}
+	}
+}
+
+// checkBudgets runs gt budget check to enforce per-rig spend caps.
+// Spawn blocking is enforced by gt sling; this handles pausing and alerts.
+func (d *Daemon) checkBudgets() {
+	cmd := exec.Command("gt", "budget", "check")
+	cmd.Dir = d.config.TownRoot
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Warning: gt budget check failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+		return
+	}
+	if output := strings.TrimSpace(stdout.String()); output != "" {
+		d.logger.Printf("Budget check: %s", output)
+	}
+}
+
+// checkAccounts runs gt account check to put rate-limited accounts in a
+// cooldown, so new sessions rotate to other accounts.
+func (d *Daemon) checkAccounts() {
+	cmd := exec.Command("gt", "account", "check")
+	cmd.Dir = d.config.TownRoot
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Warning: gt account check failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+		return
+	}
+	if output := strings.TrimSpace(stdout.String()); output != "" {
+		d.logger.Printf("Account check: %s", output)
+	}
+}
+
+// checkSLAs runs gt sla check to escalate beads past their SLA deadlines.
+// Each breach escalates once; alert state lives in the wisp layer.
+func (d *Daemon) checkSLAs() {
+	cmd := exec.Command("gt", "sla", "check")
+	cmd.Dir = d.config.TownRoot
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Warning: gt sla check failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+		return
+	}
+	if output := strings.TrimSpace(stdout.String()); output != "" {
+		d.logger.Printf("SLA check: %s", output)
+	}
+}
+
+// checkLoops runs gt loop check to sample sessions' token rates and pause
+// runaway ones. Repeated tool calls and prompts are caught by the sessions'
+// own hooks.
+func (d *Daemon) checkLoops() {
+	cmd := exec.Command("gt", "loop", "check")
+	cmd.Dir = d.config.TownRoot
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Warning: gt loop check failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+		return
+	}
+	if output := strings.TrimSpace(stdout.String()); output != "" {
+		d.logger.Printf("Loop check: %s", output)
+	}
+}
+
+// checkHealth runs gt health check to record the town's rolled-up health
+// in its history. Changes of state are logged.
+func (d *Daemon) checkHealth() {
+	cmd := exec.Command("gt", "health", "check")
+	cmd.Dir = d.config.TownRoot
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Warning: gt health check failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+		return
+	}
+	if output := strings.TrimSpace(stdout.String()); output != "" {
+		d.logger.Printf("Health check: %s", output)
+	}
+}
+
+// syncSlack runs gt slack sync to post the events logged since the last
+// heartbeat to the town's Slack channels. It does nothing unless [slack] is
+// configured.
+func (d *Daemon) syncSlack() {
+	cmd := exec.Command("gt", "slack", "sync")
+	cmd.Dir = d.config.TownRoot
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Warning: gt slack sync failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+		return
+	}
+	if output := strings.TrimSpace(stdout.String()); output != "" {
+		d.logger.Printf("Slack: %s", output)
+	}
+}
+
+// syncDiscord runs gt discord sync to post the events logged since the last
+// heartbeat to the town's Discord channels. It does nothing unless
+// [discord] is configured.
+func (d *Daemon) syncDiscord() {
+	cmd := exec.Command("gt", "discord", "sync")
+	cmd.Dir = d.config.TownRoot
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Warning: gt discord sync failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+		return
+	}
+	if output := strings.TrimSpace(stdout.String()); output != "" {
+		d.logger.Printf("Discord: %s", output)
+	}
+}
+
+// syncEmail runs gt email sync to email the high-severity events logged
+// since the last heartbeat, and the digests that are due. It does nothing
+// unless [email] is configured.
+func (d *Daemon) syncEmail() {
+	cmd := exec.Command("gt", "email", "sync")
+	cmd.Dir = d.config.TownRoot
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Warning: gt email sync failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+		return
+	}
+	if output := strings.TrimSpace(stdout.String()); output != "" {
+		d.logger.Printf("Email: %s", output)
+	}
+}
+
+// syncJira runs gt jira sync to import issues matching each rig's JQL and
+// transition and comment on the issues polecats have worked on. gt jira
+// sync does nothing if the town isn't connected to Jira.
+func (d *Daemon) syncJira() {
+	cmd := exec.Command("gt", "jira", "sync")
+	cmd.Dir = d.config.TownRoot
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Warning: gt jira sync failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+		return
+	}
+	if output := strings.TrimSpace(stdout.String()); output != "" {
+		d.logger.Printf("Jira: %s", output)
+	}
+}
+
+// syncLinear runs gt linear sync to import each rig's unstarted Linear
+// issues and update the states of the issues polecats have worked on. gt
+// linear sync does nothing if the town isn't connected to Linear.
+func (d *Daemon) syncLinear() {
+	cmd := exec.Command("gt", "linear", "sync")
+	cmd.Dir = d.config.TownRoot
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Warning: gt linear sync failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+		return
+	}
+	if output := strings.TrimSpace(stdout.String()); output != "" {
+		d.logger.Printf("Linear: %s", output)
+	}
+}
+
+// syncIncidents runs gt incidents sync to open an incident for each
+// critical condition that holds (deacon down, merge queue wedged, ...) and
+// resolve those that cleared. It does nothing unless [alerting] is
+// configured.
+func (d *Daemon) syncIncidents() {
+	cmd := exec.Command("gt", "incidents", "sync")
+	cmd.Dir = d.config.TownRoot
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Warning: gt incidents sync failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+		return
+	}
+	if output := strings.TrimSpace(stdout.String()); output != "" {
+		d.logger.Printf("Incidents: %s", output)
+	}
+}
+
+// pruneEvents runs gt events prune to drop events older than the town's
+// [events] retention_days. Retention is in days, so it runs once a day
+// rather than rewriting the log on every heartbeat.
+func (d *Daemon) pruneEvents() {
+	if time.Since(d.lastEventPrune) < eventPruneInterval {
+		return
+	}
+	d.lastEventPrune = time.Now()
+
+	cmd := exec.Command("gt", "events", "prune")
+	cmd.Dir = d.config.TownRoot
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Warning: gt events prune failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+		return
+	}
+	if output := strings.TrimSpace(stdout.String()); output != "" {
+		d.logger.Printf("Event log: %s", output)
+	}
+}
+
 // checkRigOrphanedWork checks polecats in a specific rig for orphaned work.
 func (d *Daemon) checkRigOrphanedWork(rigName string) {
 	cmd := exec.Command("bd", "list", "--type=agent", "--json")
@@ -928,7 +1179,7 @@ func (d *Daemon) checkRigOrphanedWork(rigName string) {
 
 		// Check if tmux session is alive (derive state from tmux, not bead)
 		polecatName := strings.TrimPrefix(agent.ID, prefix)
-		sessionName := fmt.Sprintf("gt-%s-%s", rigName, polecatName)
+		sessionName := session.PolecatSessionName(rigName, polecatName)
 
 		// Session running = not orphaned (work is being processed)
 		if d.tmux.IsClaudeRunning(sessionName) {
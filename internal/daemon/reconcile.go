@@ -0,0 +1,258 @@
+package daemon
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// ReconcileKind is the kind of change the reconciler makes to converge
+// running sessions with the town's desired state.
+type ReconcileKind string
+
+const (
+	// ReconcileStart starts a session that should be running but isn't
+	// (or whose agent has died).
+	ReconcileStart ReconcileKind = "start"
+
+	// ReconcileStop kills a gt-managed session whose rig or workspace no
+	// longer exists.
+	ReconcileStop ReconcileKind = "stop"
+
+	// ReconcileAdopt brings a session that was started outside gt (no
+	// GT_ROLE in its environment) under management: env vars and theming.
+	ReconcileAdopt ReconcileKind = "adopt"
+)
+
+// ReconcileAction is one change in a reconcile plan.
+type ReconcileAction struct {
+	Kind    ReconcileKind `json:"kind"`
+	Session string        `json:"session"`
+	Role    string        `json:"role"`
+	Rig     string        `json:"rig,omitempty"`
+	Name    string        `json:"name,omitempty"`
+	Reason  string        `json:"reason"`
+}
+
+// TownState is a snapshot of desired and actual state compared by the
+// reconciler. Desired state comes from the rig registry and the workspaces
+// on disk; actual state from tmux.
+type TownState struct {
+	// Rigs maps registered rig names to their state.
+	Rigs map[string]*RigState
+
+	// Sessions are the tmux sessions that match the town's naming scheme.
+	Sessions []SessionState
+}
+
+// RigState is the desired state of one registered rig.
+type RigState struct {
+	// Operational is false for parked/docked rigs or rigs with auto_restart
+	// disabled; their witness and refinery are not started.
+	Operational bool
+
+	// Polecats and Crew are the workspaces present on disk.
+	Polecats []string
+	Crew     []string
+}
+
+// SessionState is one observed tmux session.
+type SessionState struct {
+	Name     string
+	Identity *session.AgentIdentity
+
+	// Role and Root are GT_ROLE and GT_ROOT from the session environment.
+	// Role is empty for sessions not started by gt.
+	Role string
+	Root string
+
+	// Healthy is whether the agent process is running (witness and
+	// refinery sessions only).
+	Healthy bool
+}
+
+// planReconcile compares desired and actual state and returns the actions
+// needed to converge them. It is pure so the rules can be tested without tmux.
+//
+// Sessions managed by another town (GT_ROOT set to a different root) and
+// unmanaged sessions that don't correspond to a known agent are left alone.
+func planReconcile(townRoot string, state *TownState) []ReconcileAction {
+	var actions []ReconcileAction
+	running := make(map[string]SessionState)
+
+	for _, s := range state.Sessions {
+		running[s.Name] = s
+		id := s.Identity
+		managed := s.Role != ""
+		if managed && s.Root != "" && s.Root != townRoot {
+			continue
+		}
+
+		orphanReason := ""
+		switch id.Role {
+		case session.RoleWitness, session.RoleRefinery:
+			if state.Rigs[id.Rig] == nil {
+				orphanReason = "rig not registered"
+			}
+		case session.RolePolecat:
+			if r := state.Rigs[id.Rig]; r == nil {
+				orphanReason = "rig not registered"
+			} else if !contains(r.Polecats, id.Name) {
+				orphanReason = "polecat worktree missing"
+			}
+		case session.RoleCrew:
+			if r := state.Rigs[id.Rig]; r == nil {
+				orphanReason = "rig not registered"
+			} else if !contains(r.Crew, id.Name) {
+				orphanReason = "crew workspace missing"
+			}
+		}
+
+		action := ReconcileAction{Session: s.Name, Role: string(id.Role), Rig: id.Rig, Name: id.Name}
+		switch {
+		case orphanReason != "" && managed:
+			action.Kind, action.Reason = ReconcileStop, orphanReason
+		case orphanReason == "" && !managed:
+			action.Kind, action.Reason = ReconcileAdopt, "started outside gt"
+		default:
+			continue
+		}
+		actions = append(actions, action)
+	}
+
+	rigNames := make([]string, 0, len(state.Rigs))
+	for name := range state.Rigs {
+		rigNames = append(rigNames, name)
+	}
+	sort.Strings(rigNames)
+	for _, rigName := range rigNames {
+		if !state.Rigs[rigName].Operational {
+			continue
+		}
+		for _, role := range []session.Role{session.RoleWitness, session.RoleRefinery} {
+			id := session.AgentIdentity{Role: role, Rig: rigName}
+			name := id.SessionName()
+			reason := "not running"
+			if s, ok := running[name]; ok {
+				if s.Healthy {
+					continue
+				}
+				reason = "agent not running"
+			}
+			actions = append(actions, ReconcileAction{
+				Kind:    ReconcileStart,
+				Session: name,
+				Role:    string(role),
+				Rig:     rigName,
+				Reason:  reason,
+			})
+		}
+	}
+
+	return actions
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// observeTown snapshots the rig registry, workspaces, and tmux sessions.
+func (d *Daemon) observeTown() (*TownState, error) {
+	state := &TownState{Rigs: make(map[string]*RigState)}
+	for _, rigName := range d.getKnownRigs() {
+		rigPath := filepath.Join(d.config.TownRoot, rigName)
+		operational, _ := d.isRigOperational(rigName)
+		polecats, _ := listPolecatWorktrees(filepath.Join(rigPath, "polecats"))
+		crew, _ := listPolecatWorktrees(filepath.Join(rigPath, "crew"))
+		state.Rigs[rigName] = &RigState{Operational: operational, Polecats: polecats, Crew: crew}
+	}
+
+	sessions, err := d.tmux.ListSessions()
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+	sort.Strings(sessions)
+	for _, name := range sessions {
+		id, err := session.ParseSessionName(name)
+		if err != nil {
+			continue // not one of ours
+		}
+		s := SessionState{Name: name, Identity: id}
+		if env, err := d.tmux.GetAllEnvironment(name); err == nil {
+			s.Role = env["GT_ROLE"]
+			s.Root = env["GT_ROOT"]
+		}
+		if id.Role == session.RoleWitness || id.Role == session.RoleRefinery {
+			s.Healthy = d.tmux.IsClaudeRunning(name)
+		}
+		state.Sessions = append(state.Sessions, s)
+	}
+	return state, nil
+}
+
+// reconcile converges running sessions with the town's desired state:
+// starts missing or dead witnesses and refineries, stops gt-managed sessions
+// whose rig or workspace is gone, and adopts agent sessions started by hand.
+// Called on each heartbeat.
+func (d *Daemon) reconcile() {
+	if _, err := d.runReconcile(false); err != nil {
+		d.logger.Printf("Warning: reconcile failed: %v", err)
+	}
+}
+
+func (d *Daemon) runReconcile(dryRun bool) ([]ReconcileAction, error) {
+	state, err := d.observeTown()
+	if err != nil {
+		return nil, err
+	}
+	actions := planReconcile(d.config.TownRoot, state)
+	if dryRun {
+		return actions, nil
+	}
+
+	for _, a := range actions {
+		switch a.Kind {
+		case ReconcileStart:
+			if a.Role == string(session.RoleWitness) {
+				d.ensureWitnessRunning(a.Rig)
+			} else {
+				d.ensureRefineryRunning(a.Rig)
+			}
+		case ReconcileStop:
+			d.logger.Printf("Reconcile: stopping orphan session %s (%s)", a.Session, a.Reason)
+			if err := d.tmux.KillSessionWithProcesses(a.Session); err != nil {
+				d.logger.Printf("Warning: failed to stop %s: %v", a.Session, err)
+			}
+		case ReconcileAdopt:
+			d.logger.Printf("Reconcile: adopting session %s (%s)", a.Session, a.Reason)
+			parsed := &ParsedIdentity{RoleType: a.Role, RigName: a.Rig, AgentName: a.Name}
+			d.setSessionEnvironment(a.Session, nil, parsed)
+			d.applySessionTheme(a.Session, parsed)
+		}
+	}
+	return actions, nil
+}
+
+// Reconcile runs one reconcile pass outside the daemon loop, logging to
+// logger. With dryRun, returns the plan without changing anything.
+func Reconcile(townRoot string, dryRun bool, logger *log.Logger) ([]ReconcileAction, error) {
+	if logger == nil {
+		logger = log.New(os.Stderr, "", 0)
+	}
+	d := &Daemon{
+		config: DefaultConfig(townRoot),
+		tmux:   tmux.NewTmux(),
+		logger: logger,
+	}
+	return d.runReconcile(dryRun)
+}
@@ -0,0 +1,68 @@
+package daemon
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+func observed(name, role, root string, healthy bool) SessionState {
+	id, err := session.ParseSessionName(name)
+	if err != nil {
+		panic(err)
+	}
+	return SessionState{Name: name, Identity: id, Role: role, Root: root, Healthy: healthy}
+}
+
+func TestPlanReconcile(t *testing.T) {
+	const town = "/town"
+	state := &TownState{
+		Rigs: map[string]*RigState{
+			"gastown": {Operational: true, Polecats: []string{"Toast"}, Crew: []string{"max"}},
+			"parked":  {Operational: false},
+		},
+		Sessions: []SessionState{
+			observed("gt-gastown-witness", "witness", town, true),
+			observed("gt-gastown-refinery", "refinery", town, false), // zombie
+			observed("gt-gastown-Toast", "polecat", town, false),
+			observed("gt-gastown-Nux", "polecat", town, false),             // worktree gone
+			observed("gt-gastown-crew-max", "", "", false),                 // started by hand
+			observed("gt-gastown-crew-ghost", "", "", false),               // unknown, unmanaged
+			observed("gt-oldrig-witness", "witness", town, true),           // rig removed
+			observed("gt-otherrig-witness", "witness", "/elsewhere", true), // other town
+			observed("hq-mayor", "", "", false),
+		},
+	}
+
+	got := planReconcile(town, state)
+	want := []ReconcileAction{
+		{Kind: ReconcileStop, Session: "gt-gastown-Nux", Reason: "polecat worktree missing"},
+		{Kind: ReconcileAdopt, Session: "gt-gastown-crew-max", Reason: "started outside gt"},
+		{Kind: ReconcileStop, Session: "gt-oldrig-witness", Reason: "rig not registered"},
+		{Kind: ReconcileAdopt, Session: "hq-mayor", Reason: "started outside gt"},
+		{Kind: ReconcileStart, Session: "gt-gastown-refinery", Reason: "agent not running"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("planReconcile() = %+v, want %d actions", got, len(want))
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Kind != w.Kind || g.Session != w.Session || g.Reason != w.Reason {
+			t.Errorf("action %d = %s %s (%s), want %s %s (%s)", i, g.Kind, g.Session, g.Reason, w.Kind, w.Session, w.Reason)
+		}
+	}
+}
+
+func TestPlanReconcileStartsMissing(t *testing.T) {
+	state := &TownState{Rigs: map[string]*RigState{"gastown": {Operational: true}}}
+
+	got := planReconcile("/town", state)
+	if len(got) != 2 {
+		t.Fatalf("planReconcile() = %+v, want witness and refinery starts", got)
+	}
+	for i, role := range []string{"witness", "refinery"} {
+		if got[i].Kind != ReconcileStart || got[i].Role != role || got[i].Reason != "not running" {
+			t.Errorf("action %d = %+v, want start %s", i, got[i], role)
+		}
+	}
+}
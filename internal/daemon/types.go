@@ -4,6 +4,7 @@
 // 1. Pokes agents periodically (heartbeat)
 // 2. Processes lifecycle requests (cycle, restart, shutdown)
 // 3. Restarts sessions when agents request cycling
+// 4. Reconciles running sessions with the town's desired state
 //
 // The daemon is a "dumb scheduler" - all intelligence is in agents.
 package daemon
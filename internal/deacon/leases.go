@@ -0,0 +1,167 @@
+package deacon
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/lease"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// LeaseExpiryConfig holds configurable parameters for lease expiry.
+type LeaseExpiryConfig struct {
+	// DryRun if true, only reports what would be done without making changes.
+	DryRun bool `json:"dry_run"`
+}
+
+// LeaseResult represents the result of processing one expired lease.
+type LeaseResult struct {
+	BeadID   string `json:"bead_id"`
+	Holder   string `json:"holder"`
+	Expired  string `json:"expired"` // how long ago the lease expired
+	Action   string `json:"action"`  // renewed, released, discarded, would-release
+	Reason   string `json:"reason"`
+	Error    string `json:"error,omitempty"`
+	Released bool   `json:"released"`
+}
+
+// LeaseScanResult contains the full results of a lease expiry pass.
+type LeaseScanResult struct {
+	ScannedAt    time.Time      `json:"scanned_at"`
+	TotalLeases  int            `json:"total_leases"`
+	ExpiredCount int            `json:"expired_count"`
+	Released     int            `json:"released"`
+	Results      []*LeaseResult `json:"results"`
+}
+
+// ExpireLeases processes expired work leases. For each expired lease:
+//   - If the bead is no longer hooked by the holder, the lease is stale and discarded.
+//   - If the holder's session shows activity since the last renewal, that activity
+//     counts as a heartbeat and the lease is renewed.
+//   - Otherwise the holder has stopped making progress: the bead is returned to
+//     the ready queue (status=open, assignee cleared) and the lease is removed.
+func ExpireLeases(townRoot string, cfg *LeaseExpiryConfig) (*LeaseScanResult, error) {
+	if cfg == nil {
+		cfg = &LeaseExpiryConfig{}
+	}
+
+	all, err := lease.List(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing leases: %w", err)
+	}
+
+	now := time.Now().UTC()
+	result := &LeaseScanResult{
+		ScannedAt:   now,
+		TotalLeases: len(all),
+		Results:     make([]*LeaseResult, 0),
+	}
+
+	var expired []*lease.Lease
+	for _, l := range all {
+		if l.Expired(now) {
+			expired = append(expired, l)
+		}
+	}
+	result.ExpiredCount = len(expired)
+	if len(expired) == 0 {
+		return result, nil
+	}
+
+	// The bead store is authoritative for who holds the work
+	hookedBeads, err := listHookedBeads(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing hooked beads: %w", err)
+	}
+	assignees := make(map[string]string, len(hookedBeads))
+	for _, b := range hookedBeads {
+		assignees[b.ID] = b.Assignee
+	}
+
+	t := tmux.NewTmux()
+	for _, l := range expired {
+		r := &LeaseResult{
+			BeadID:  l.BeadID,
+			Holder:  l.Holder,
+			Expired: (-l.Remaining(now)).Round(time.Second).String(),
+		}
+		result.Results = append(result.Results, r)
+
+		if assignee, ok := assignees[l.BeadID]; !ok || assignee != l.Holder {
+			r.Action = "discarded"
+			r.Reason = "bead no longer hooked by holder"
+			if !cfg.DryRun {
+				_ = lease.Release(townRoot, l.BeadID)
+			}
+			continue
+		}
+
+		active, reason := sessionActiveSince(t, l.Holder, l.RenewedAt)
+		r.Reason = reason
+		if active {
+			r.Action = "renewed"
+			if !cfg.DryRun {
+				if _, err := lease.Renew(townRoot, l.BeadID); err != nil {
+					r.Error = err.Error()
+				}
+			}
+			continue
+		}
+
+		if cfg.DryRun {
+			r.Action = "would-release"
+			continue
+		}
+
+		if err := releaseBead(townRoot, l.BeadID); err != nil {
+			r.Action = "error"
+			r.Error = err.Error()
+			continue
+		}
+		_ = lease.Release(townRoot, l.BeadID)
+		r.Action = "released"
+		r.Released = true
+		result.Released++
+	}
+
+	return result, nil
+}
+
+// sessionActiveSince reports whether the holder's tmux session has shown
+// activity since the given time. The reason describes the decision.
+func sessionActiveSince(t *tmux.Tmux, holder string, since time.Time) (bool, string) {
+	sessionName := assigneeToSessionName(holder)
+	if sessionName == "" {
+		return false, "holder has no session"
+	}
+	alive, _ := t.HasSession(sessionName)
+	if !alive {
+		return false, "session dead"
+	}
+
+	info, err := t.GetSessionInfo(sessionName)
+	if err != nil || info.Activity == "" {
+		return false, "session idle"
+	}
+	ts, err := strconv.ParseInt(info.Activity, 10, 64)
+	if err != nil {
+		return false, "session idle"
+	}
+	if time.Unix(ts, 0).After(since) {
+		return true, "session active"
+	}
+	return false, "session idle"
+}
+
+// releaseBead returns a hooked bead to the ready queue.
+func releaseBead(townRoot, beadID string) error {
+	cmd := exec.Command("bd", "update", beadID, "--status=open", "--assignee=",
+		"--notes=Lease expired: returned to ready queue by Deacon")
+	cmd.Dir = townRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, out)
+	}
+	return nil
+}
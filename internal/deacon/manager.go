@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/account"
 	"github.com/steveyegge/gastown/internal/claude"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
@@ -85,6 +86,10 @@ func (m *Manager) Start(agentOverride string) error {
 	if err != nil {
 		return fmt.Errorf("building startup command: %w", err)
 	}
+	startupCmd, err = account.PrepareSession(m.townRoot, account.Selection{Role: constants.RoleDeacon}, sessionID, startupCmd)
+	if err != nil {
+		return fmt.Errorf("resolving account: %w", err)
+	}
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
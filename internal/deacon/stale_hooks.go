@@ -166,8 +166,10 @@ func assigneeToSessionName(assignee string) string {
 		// rig/role: "gastown/witness", "gastown/refinery"
 		rig, role := parts[0], parts[1]
 		switch role {
-		case "witness", "refinery":
-			return fmt.Sprintf("gt-%s-%s", rig, role)
+		case "witness":
+			return session.WitnessSessionName(rig)
+		case "refinery":
+			return session.RefinerySessionName(rig)
 		default:
 			return ""
 		}
@@ -176,9 +178,9 @@ func assigneeToSessionName(assignee string) string {
 		rig, agentType, name := parts[0], parts[1], parts[2]
 		switch agentType {
 		case "polecats":
-			return fmt.Sprintf("gt-%s-%s", rig, name)
+			return session.PolecatSessionName(rig, name)
 		case "crew":
-			return fmt.Sprintf("gt-%s-crew-%s", rig, name)
+			return session.CrewSessionName(rig, name)
 		default:
 			return ""
 		}
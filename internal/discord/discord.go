@@ -0,0 +1,135 @@
+// Package discord connects a town to a Discord application, so teams
+// that coordinate on Discord can supervise it from chat.
+//
+// Outbound, the daemon posts session lifecycle, escalation, and merge
+// events from the town's event log to channels (Sync), through the
+// application's bot or a channel webhook. Inbound, gt dashboard serves the
+// application's /gt command as its interactions endpoint: requests are
+// verified with the application's public key (Verify) and parsed into an
+// Interaction for the town to run.
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// postTimeout bounds each request, so an unreachable Discord doesn't stall
+// the daemon's heartbeat.
+const postTimeout = 10 * time.Second
+
+// maxContent is the longest message Discord accepts, in characters.
+const maxContent = 2000
+
+// DefaultAPIURL is Discord's REST API.
+const DefaultAPIURL = "https://discord.com/api/v10"
+
+// Poster posts messages to Discord.
+type Poster interface {
+	// Post posts text to a channel ID; a webhook ignores channel.
+	Post(ctx context.Context, channel, text string) error
+}
+
+// message is a message as Discord's API takes it. Mentions are never
+// parsed, so agent output can't ping @everyone.
+func message(text string) map[string]interface{} {
+	if r := []rune(text); len(r) > maxContent {
+		text = string(r[:maxContent-1]) + "…"
+	}
+	return map[string]interface{}{
+		"content":          text,
+		"allowed_mentions": map[string]interface{}{"parse": []string{}},
+	}
+}
+
+// Client posts as the application's bot.
+type Client struct {
+	Token  string
+	APIURL string // DefaultAPIURL if empty
+	HTTP   *http.Client
+}
+
+// NewClient returns a client for a bot token.
+func NewClient(token string) *Client {
+	return &Client{Token: token, HTTP: http.DefaultClient}
+}
+
+// api returns the client's API URL.
+func (c *Client) api() string {
+	if c.APIURL == "" {
+		return DefaultAPIURL
+	}
+	return c.APIURL
+}
+
+// Post implements Poster.
+func (c *Client) Post(ctx context.Context, channel, text string) error {
+	if channel == "" {
+		return fmt.Errorf("no Discord channel configured")
+	}
+	return send(ctx, c.HTTP, http.MethodPost, c.api()+"/channels/"+channel+"/messages", "Bot "+c.Token, message(text))
+}
+
+// RegisterCommands registers the /gt command (see Commands) for an
+// application, replacing its other commands. Discord can take up to an hour
+// to show changes in every server.
+func (c *Client) RegisterCommands(ctx context.Context, applicationID string) error {
+	if applicationID == "" {
+		return fmt.Errorf("no Discord application ID configured")
+	}
+	return send(ctx, c.HTTP, http.MethodPut, c.api()+"/applications/"+applicationID+"/commands", "Bot "+c.Token, Commands())
+}
+
+// Webhook posts to a channel webhook, which has its own channel.
+type Webhook struct {
+	URL  string
+	HTTP *http.Client
+}
+
+// NewWebhook returns a poster for a webhook URL.
+func NewWebhook(url string) *Webhook {
+	return &Webhook{URL: url, HTTP: http.DefaultClient}
+}
+
+// Post implements Poster.
+func (w *Webhook) Post(ctx context.Context, _, text string) error {
+	return send(ctx, w.HTTP, http.MethodPost, w.URL, "", message(text))
+}
+
+// send sends body as JSON.
+func send(ctx context.Context, client *http.Client, method, url, auth string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encoding request: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, postTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting to Discord: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return fmt.Errorf("posting to Discord: %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
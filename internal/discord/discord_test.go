@@ -0,0 +1,174 @@
+package discord
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// signed returns headers signing body with priv at ts, as Discord does.
+func signed(priv ed25519.PrivateKey, body []byte, ts time.Time) http.Header {
+	stamp := strconv.FormatInt(ts.Unix(), 10)
+	h := http.Header{}
+	h.Set("X-Signature-Timestamp", stamp)
+	h.Set("X-Signature-Ed25519", hex.EncodeToString(ed25519.Sign(priv, append([]byte(stamp), body...))))
+	return h
+}
+
+func TestVerify(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	_, other, _ := ed25519.GenerateKey(nil)
+	key := hex.EncodeToString(pub)
+	now := time.Now()
+	body := []byte(`{"type":1}`)
+	tests := []struct {
+		name   string
+		header http.Header
+		body   []byte
+		ok     bool
+	}{
+		{"valid", signed(priv, body, now), body, true},
+		{"wrong key", signed(other, body, now), body, false},
+		{"tampered body", signed(priv, body, now), []byte(`{"type":2}`), false},
+		{"replayed", signed(priv, body, now.Add(-10*time.Minute)), body, false},
+		{"unsigned", http.Header{}, body, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(key, tt.header, tt.body, now)
+			if (err == nil) != tt.ok {
+				t.Errorf("Verify = %v, want ok %v", err, tt.ok)
+			}
+			if err != nil && !errors.Is(err, ErrSignature) {
+				t.Errorf("error %v is not ErrSignature", err)
+			}
+		})
+	}
+}
+
+func TestParseInteraction(t *testing.T) {
+	body := `{"type":2,"channel_id":"42","member":{"user":{"id":"7","username":"ann"}},
+		"data":{"name":"gt","options":[{"name":"prompt","type":1,"options":[
+			{"name":"agent","type":3,"value":"mayor"},{"name":"text","type":3,"value":"check the queue"}]}]}}`
+	i, err := ParseInteraction([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	verb, opts := i.Subcommand()
+	if verb != "prompt" || opts["agent"] != "mayor" || opts["text"] != "check the queue" || i.Invoker().Username != "ann" {
+		t.Errorf("parsed verb %q opts %v invoker %+v", verb, opts, i.Invoker())
+	}
+	if _, err := ParseInteraction([]byte(`{"type":2,"data":{"name":"gt"}}`)); err == nil {
+		t.Error("command without a user parsed")
+	}
+}
+
+func TestResponseJSON(t *testing.T) {
+	for _, inChannel := range []bool{false, true} {
+		data, err := json.Marshal(Response{Text: "@everyone hi", InChannel: inChannel})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got struct {
+			Type int `json:"type"`
+			Data struct {
+				Content         string                 `json:"content"`
+				Flags           int                    `json:"flags"`
+				AllowedMentions map[string]interface{} `json:"allowed_mentions"`
+			} `json:"data"`
+		}
+		_ = json.Unmarshal(data, &got)
+		wantFlags := 64
+		if inChannel {
+			wantFlags = 0
+		}
+		if got.Type != 4 || got.Data.Content != "@everyone hi" || got.Data.Flags != wantFlags || got.Data.AllowedMentions == nil {
+			t.Errorf("in channel %v: %s", inChannel, data)
+		}
+	}
+}
+
+func TestClientPost(t *testing.T) {
+	var path, auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth = r.URL.Path, r.Header.Get("Authorization")
+		if strings.Contains(path, "/missing/") {
+			http.Error(w, `{"message":"Unknown Channel"}`, http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := &Client{Token: "tok", APIURL: srv.URL, HTTP: srv.Client()}
+	if err := c.Post(context.Background(), "42", "hi"); err != nil || path != "/channels/42/messages" || auth != "Bot tok" {
+		t.Errorf("Post = %v, to %s with %q", err, path, auth)
+	}
+	if err := c.Post(context.Background(), "missing", "hi"); err == nil || !strings.Contains(err.Error(), "Unknown Channel") {
+		t.Errorf("Post to a missing channel = %v", err)
+	}
+	if err := c.RegisterCommands(context.Background(), "99"); err != nil || path != "/applications/99/commands" {
+		t.Errorf("RegisterCommands = %v, to %s", err, path)
+	}
+}
+
+// recorder is a Poster that records posts.
+type recorder struct{ posts []string }
+
+func (r *recorder) Post(_ context.Context, channel, text string) error {
+	r.posts = append(r.posts, channel+" "+text)
+	return nil
+}
+
+func TestSyncRoutesByRig(t *testing.T) {
+	townRoot := t.TempDir()
+	cfg := config.DiscordConfig{
+		BotToken: "${T}",
+		Channel:  "town",
+		Rigs:     map[string]string{"gastown": "gastown-ch"},
+		Channels: map[string]string{"escalation": "oncall"},
+	}
+	r := &recorder{}
+	if n, err := Sync(context.Background(), townRoot, cfg, r); err != nil || n != 0 {
+		t.Fatalf("first sync = %d, %v", n, err)
+	}
+
+	ts := time.Now().UTC().Format(time.RFC3339)
+	f, err := os.Create(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range []events.Event{
+		{Timestamp: ts, Type: events.TypeMerged, Actor: "gastown/refinery", Payload: events.MergePayload("mr-1", "toast", "polecat/toast", "")},
+		{Timestamp: ts, Type: events.TypeMerged, Actor: "beads/refinery", Payload: events.MergePayload("mr-2", "nux", "polecat/nux", "")},
+		{Timestamp: ts, Type: events.TypeEscalationSent, Actor: "mayor", Payload: events.EscalationPayload("hq-7", "", "overseer", "stuck")},
+		{Timestamp: ts, Type: events.TypeSling, Actor: "mayor"},
+	} {
+		data, _ := json.Marshal(e)
+		_, _ = f.Write(append(data, '\n'))
+	}
+	f.Close()
+
+	if n, err := Sync(context.Background(), townRoot, cfg, r); err != nil || n != 3 {
+		t.Fatalf("sync = %d, %v", n, err)
+	}
+	want := []string{"gastown-ch 🔀 Merged `polecat/toast`", "town 🔀 Merged `polecat/nux`", "oncall 🆘 Escalation hq-7"}
+	for i, prefix := range want {
+		if !strings.HasPrefix(r.posts[i], prefix) {
+			t.Errorf("post %d = %q, want prefix %q", i, r.posts[i], prefix)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package discord
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxRequestAge is how old an interaction request may be, so a captured
+// request can't be replayed later.
+const maxRequestAge = 5 * time.Minute
+
+// ErrSignature is returned for a request Discord didn't sign.
+var ErrSignature = errors.New("invalid Discord signature")
+
+// Verify checks a request's X-Signature-Ed25519 against the application's
+// public key (hex): a signature of the X-Signature-Timestamp followed by
+// the body, made within maxRequestAge of now.
+func Verify(publicKey string, header http.Header, body []byte, now time.Time) error {
+	key, err := hex.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid Discord public key")
+	}
+	ts := header.Get("X-Signature-Timestamp")
+	sig, err := hex.DecodeString(header.Get("X-Signature-Ed25519"))
+	if ts == "" || err != nil || len(sig) != ed25519.SignatureSize {
+		return ErrSignature
+	}
+	secs, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrSignature
+	}
+	if age := now.Sub(time.Unix(secs, 0)); age > maxRequestAge || age < -maxRequestAge {
+		return fmt.Errorf("%w: request is too old", ErrSignature)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), append([]byte(ts), body...), sig) {
+		return ErrSignature
+	}
+	return nil
+}
+
+// Interaction types the town handles.
+const (
+	InteractionPing    = 1 // Discord checking the endpoint
+	InteractionCommand = 2 // a slash command
+)
+
+// User is a Discord user.
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+// Option is a slash command option, or a subcommand with its own options.
+type Option struct {
+	Name    string      `json:"name"`
+	Type    int         `json:"type"`
+	Value   interface{} `json:"value,omitempty"`
+	Options []Option    `json:"options,omitempty"`
+}
+
+// Interaction is an interaction request (e.g., "/gt status").
+type Interaction struct {
+	Type int `json:"type"`
+	Data struct {
+		Name    string   `json:"name"` // the command, e.g. "gt"
+		Options []Option `json:"options"`
+	} `json:"data"`
+	Member *struct {
+		User User `json:"user"`
+	} `json:"member"` // set in servers
+	User      *User  `json:"user"` // set in DMs
+	ChannelID string `json:"channel_id"`
+}
+
+// ParseInteraction parses an interaction request's body.
+func ParseInteraction(body []byte) (*Interaction, error) {
+	var i Interaction
+	if err := json.Unmarshal(body, &i); err != nil {
+		return nil, fmt.Errorf("parsing interaction: %w", err)
+	}
+	if i.Type == InteractionCommand && i.Invoker().ID == "" {
+		return nil, fmt.Errorf("parsing interaction: no user")
+	}
+	return &i, nil
+}
+
+// Invoker returns the user who ran the command.
+func (i *Interaction) Invoker() User {
+	if i.Member != nil {
+		return i.Member.User
+	}
+	if i.User != nil {
+		return *i.User
+	}
+	return User{}
+}
+
+// Subcommand returns the /gt subcommand run (e.g., "prompt") and its
+// options' values by name.
+func (i *Interaction) Subcommand() (string, map[string]string) {
+	if len(i.Data.Options) == 0 {
+		return "", nil
+	}
+	sub := i.Data.Options[0]
+	opts := make(map[string]string, len(sub.Options))
+	for _, o := range sub.Options {
+		opts[o.Name] = fmt.Sprint(o.Value)
+	}
+	return sub.Name, opts
+}
+
+// Command definition types (Discord's application command option types).
+const (
+	optionSubcommand = 1
+	optionString     = 3
+)
+
+// Commands returns the /gt command's definition for registration.
+func Commands() []map[string]interface{} {
+	str := func(name, desc string, required bool) map[string]interface{} {
+		return map[string]interface{}{"type": optionString, "name": name, "description": desc, "required": required}
+	}
+	sub := func(name, desc string, opts ...map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"type": optionSubcommand, "name": name, "description": desc, "options": opts}
+	}
+	return []map[string]interface{}{{
+		"name":        "gt",
+		"description": "Supervise the Gas Town",
+		"options": []map[string]interface{}{
+			sub("status", "Show the town's health"),
+			sub("prompt", "Nudge an agent (operators)",
+				str("agent", "mayor, gastown/witness, gastown/polecats/toast, or a session", true),
+				str("text", "What to tell it", true)),
+			sub("approve", "Approve an MR under review (operators)",
+				str("mr", "The MR's bead ID", true),
+				str("message", "Review summary", false)),
+			sub("stop", "Stop an agent's session (operators)",
+				str("agent", "mayor, gastown/witness, gastown/polecats/toast, or a session", true)),
+		},
+	}}
+}
+
+// Response is the response to a slash command. Ephemeral responses are
+// shown only to the user who ran it.
+type Response struct {
+	Text      string
+	InChannel bool
+}
+
+// MarshalJSON encodes the response as Discord expects: a message, never
+// parsing mentions.
+func (r Response) MarshalJSON() ([]byte, error) {
+	data := message(r.Text)
+	if !r.InChannel {
+		data["flags"] = 64 // EPHEMERAL
+	}
+	return json.Marshal(map[string]interface{}{
+		"type": 4, // CHANNEL_MESSAGE_WITH_SOURCE
+		"data": data,
+	})
+}
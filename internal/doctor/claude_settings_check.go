@@ -133,7 +133,7 @@ func (c *ClaudeSettingsCheck) findSettingsFiles(townRoot string) []staleSettings
 		files = append(files, staleSettingsInfo{
 			path:          staleTownRootSettings,
 			agentType:     "mayor",
-			sessionName:   "hq-mayor",
+			sessionName:   session.MayorSessionName(),
 			wrongLocation: true,
 			gitStatus:     c.getGitFileStatus(staleTownRootSettings),
 			missing:       []string{"should be at mayor/.claude/settings.json, not town root"},
@@ -149,7 +149,7 @@ func (c *ClaudeSettingsCheck) findSettingsFiles(townRoot string) []staleSettings
 		files = append(files, staleSettingsInfo{
 			path:          staleTownRootCLAUDEmd,
 			agentType:     "mayor",
-			sessionName:   "hq-mayor",
+			sessionName:   session.MayorSessionName(),
 			wrongLocation: true,
 			gitStatus:     c.getGitFileStatus(staleTownRootCLAUDEmd),
 			missing:       []string{"should be at mayor/CLAUDE.md, not town root"},
@@ -162,7 +162,7 @@ func (c *ClaudeSettingsCheck) findSettingsFiles(townRoot string) []staleSettings
 		files = append(files, staleSettingsInfo{
 			path:        mayorSettings,
 			agentType:   "mayor",
-			sessionName: "hq-mayor",
+			sessionName: session.MayorSessionName(),
 		})
 	}
 
@@ -172,7 +172,7 @@ func (c *ClaudeSettingsCheck) findSettingsFiles(townRoot string) []staleSettings
 		files = append(files, staleSettingsInfo{
 			path:        deaconSettings,
 			agentType:   "deacon",
-			sessionName: "hq-deacon",
+			sessionName: session.DeaconSessionName(),
 		})
 	}
 
@@ -204,7 +204,7 @@ func (c *ClaudeSettingsCheck) findSettingsFiles(townRoot string) []staleSettings
 				path:        witnessSettings,
 				agentType:   "witness",
 				rigName:     rigName,
-				sessionName: fmt.Sprintf("gt-%s-witness", rigName),
+				sessionName: session.WitnessSessionName(rigName),
 			})
 		}
 		witnessWrongSettings := filepath.Join(rigPath, "witness", "rig", ".claude", "settings.json")
@@ -213,7 +213,7 @@ func (c *ClaudeSettingsCheck) findSettingsFiles(townRoot string) []staleSettings
 				path:          witnessWrongSettings,
 				agentType:     "witness",
 				rigName:       rigName,
-				sessionName:   fmt.Sprintf("gt-%s-witness", rigName),
+				sessionName:   session.WitnessSessionName(rigName),
 				wrongLocation: true,
 			})
 		}
@@ -226,7 +226,7 @@ func (c *ClaudeSettingsCheck) findSettingsFiles(townRoot string) []staleSettings
 				path:        refinerySettings,
 				agentType:   "refinery",
 				rigName:     rigName,
-				sessionName: fmt.Sprintf("gt-%s-refinery", rigName),
+				sessionName: session.RefinerySessionName(rigName),
 			})
 		}
 		refineryWrongSettings := filepath.Join(rigPath, "refinery", "rig", ".claude", "settings.json")
@@ -235,7 +235,7 @@ func (c *ClaudeSettingsCheck) findSettingsFiles(townRoot string) []staleSettings
 				path:          refineryWrongSettings,
 				agentType:     "refinery",
 				rigName:       rigName,
-				sessionName:   fmt.Sprintf("gt-%s-refinery", rigName),
+				sessionName:   session.RefinerySessionName(rigName),
 				wrongLocation: true,
 			})
 		}
@@ -264,7 +264,7 @@ func (c *ClaudeSettingsCheck) findSettingsFiles(townRoot string) []staleSettings
 						path:          crewWrongSettings,
 						agentType:     "crew",
 						rigName:       rigName,
-						sessionName:   fmt.Sprintf("gt-%s-crew-%s", rigName, crewEntry.Name()),
+						sessionName:   session.CrewSessionName(rigName, crewEntry.Name()),
 						wrongLocation: true,
 					})
 				}
@@ -302,7 +302,7 @@ func (c *ClaudeSettingsCheck) findSettingsFiles(townRoot string) []staleSettings
 							path:          pcWrongSettings,
 							agentType:     "polecat",
 							rigName:       rigName,
-							sessionName:   fmt.Sprintf("gt-%s-%s", rigName, pcEntry.Name()),
+							sessionName:   session.PolecatSessionName(rigName, pcEntry.Name()),
 							wrongLocation: true,
 						})
 					}
@@ -337,6 +337,10 @@ func (c *ClaudeSettingsCheck) checkSettings(path, _ string) []string {
 	// 2. PATH export in hooks
 	// 3. Stop hook with gt costs record (for autonomous)
 	// 4. gt nudge deacon session-started in SessionStart
+	// 5. PostToolUse hook with gt events record-tool (audit log)
+	// 6. PreToolUse hook with gt policy check (tool policy)
+	// 7. UserPromptSubmit hook with gt loop check-prompt (runaway loops)
+	// 8. UserPromptSubmit hook with gt outbound check-prompt (outbound filter)
 
 	// Check enabledPlugins
 	if _, ok := actual["enabledPlugins"]; !ok {
@@ -364,6 +368,26 @@ func (c *ClaudeSettingsCheck) checkSettings(path, _ string) []string {
 		missing = append(missing, "Stop hook")
 	}
 
+	// Check PostToolUse hook records tool executions in the event log
+	if !c.hookHasPattern(hooks, "PostToolUse", "gt events record-tool") {
+		missing = append(missing, "tool audit hook")
+	}
+
+	// Check PreToolUse hook enforces the tool policy
+	if !c.hookHasPattern(hooks, "PreToolUse", "gt policy check") {
+		missing = append(missing, "tool policy hook")
+	}
+
+	// Check UserPromptSubmit hook feeds prompts to loop detection
+	if !c.hookHasPattern(hooks, "UserPromptSubmit", "gt loop check-prompt") {
+		missing = append(missing, "loop guard hook")
+	}
+
+	// Check UserPromptSubmit hook applies the outbound filter
+	if !c.hookHasPattern(hooks, "UserPromptSubmit", "gt outbound check-prompt") {
+		missing = append(missing, "outbound filter hook")
+	}
+
 	return missing
 }
 
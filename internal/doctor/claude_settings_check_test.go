@@ -56,6 +56,43 @@ func createValidSettings(t *testing.T, path string) {
 					},
 				},
 			},
+			"UserPromptSubmit": []any{
+				map[string]any{
+					"matcher": "",
+					"hooks": []any{
+						map[string]any{
+							"type":    "command",
+							"command": "gt loop check-prompt",
+						},
+						map[string]any{
+							"type":    "command",
+							"command": "gt outbound check-prompt",
+						},
+					},
+				},
+			},
+			"PreToolUse": []any{
+				map[string]any{
+					"matcher": "",
+					"hooks": []any{
+						map[string]any{
+							"type":    "command",
+							"command": "gt policy check",
+						},
+					},
+				},
+			},
+			"PostToolUse": []any{
+				map[string]any{
+					"matcher": "",
+					"hooks": []any{
+						map[string]any{
+							"type":    "command",
+							"command": "gt events record-tool",
+						},
+					},
+				},
+			},
 			"Stop": []any{
 				map[string]any{
 					"matcher": "**",
@@ -106,6 +143,43 @@ func createStaleSettings(t *testing.T, path string, missingElements ...string) {
 					},
 				},
 			},
+			"UserPromptSubmit": []any{
+				map[string]any{
+					"matcher": "",
+					"hooks": []any{
+						map[string]any{
+							"type":    "command",
+							"command": "gt loop check-prompt",
+						},
+						map[string]any{
+							"type":    "command",
+							"command": "gt outbound check-prompt",
+						},
+					},
+				},
+			},
+			"PreToolUse": []any{
+				map[string]any{
+					"matcher": "",
+					"hooks": []any{
+						map[string]any{
+							"type":    "command",
+							"command": "gt policy check",
+						},
+					},
+				},
+			},
+			"PostToolUse": []any{
+				map[string]any{
+					"matcher": "",
+					"hooks": []any{
+						map[string]any{
+							"type":    "command",
+							"command": "gt events record-tool",
+						},
+					},
+				},
+			},
 			"Stop": []any{
 				map[string]any{
 					"matcher": "**",
@@ -2,7 +2,6 @@ package doctor
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/session"
@@ -72,7 +71,7 @@ func (c *EnvVarsCheck) Run(ctx *CheckContext) *CheckResult {
 	// Filter to Gas Town sessions only (gt-* and hq-*)
 	var gtSessions []string
 	for _, sess := range sessions {
-		if strings.HasPrefix(sess, "gt-") || strings.HasPrefix(sess, "hq-") {
+		if session.HasRigPrefix(sess) || session.HasHQPrefix(sess) {
 			gtSessions = append(gtSessions, sess)
 		}
 	}
@@ -96,7 +96,7 @@ func (c *OrphanSessionCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 
 		// Only check gt-* sessions (Gas Town sessions)
-		if !strings.HasPrefix(sess, "gt-") {
+		if !session.HasRigPrefix(sess) {
 			continue
 		}
 
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/git"
 )
 
@@ -111,9 +112,19 @@ func (c *SparseCheckoutCheck) Run(ctx *CheckContext) *CheckResult {
 }
 
 // Fix configures sparse checkout for affected repos to exclude Claude context files.
+// Crew clones and polecat worktrees keep the rig's sparse_checkout paths.
 func (c *SparseCheckoutCheck) Fix(ctx *CheckContext) error {
+	var workerPaths []string
+	if settings, err := config.LoadRigSettings(config.RigSettingsPath(c.rigPath)); err == nil {
+		workerPaths = settings.SparseCheckout
+	}
 	for _, repoPath := range c.affectedRepos {
-		if err := git.ConfigureSparseCheckout(repoPath); err != nil {
+		var paths []string
+		if rel, _ := filepath.Rel(c.rigPath, repoPath); strings.HasPrefix(rel, "crew"+string(filepath.Separator)) ||
+			strings.HasPrefix(rel, "polecats"+string(filepath.Separator)) {
+			paths = workerPaths
+		}
+		if err := git.ConfigureSparseCheckoutPaths(repoPath, paths); err != nil {
 			relPath, _ := filepath.Rel(c.rigPath, repoPath)
 			return fmt.Errorf("failed to configure sparse checkout for %s: %w", relPath, err)
 		}
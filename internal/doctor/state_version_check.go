@@ -0,0 +1,63 @@
+package doctor
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/migrate"
+)
+
+// StateVersionCheck verifies the town's state files are in the current
+// formats.
+type StateVersionCheck struct {
+	FixableCheck
+}
+
+// NewStateVersionCheck creates a new state version check.
+func NewStateVersionCheck() *StateVersionCheck {
+	return &StateVersionCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "state-versions",
+				CheckDescription: "Check state files are in the current formats",
+				CheckCategory:    CategoryConfig,
+			},
+		},
+	}
+}
+
+// Run lists the migrations pending for the town's state files.
+func (c *StateVersionCheck) Run(ctx *CheckContext) *CheckResult {
+	steps, err := migrate.Town(ctx.TownRoot, true)
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Could not check state file versions",
+			Details: []string{err.Error()},
+		}
+	}
+	if len(steps) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "State files are current",
+		}
+	}
+	details := make([]string, len(steps))
+	for i, s := range steps {
+		details[i] = s.String()
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d state file upgrade(s) pending", len(steps)),
+		Details: details,
+		FixHint: "Run 'gt town migrate' or 'gt doctor --fix'",
+	}
+}
+
+// Fix upgrades the state files.
+func (c *StateVersionCheck) Fix(ctx *CheckContext) error {
+	_, err := migrate.Town(ctx.TownRoot, false)
+	return err
+}
@@ -45,9 +45,9 @@ func (c *LinkedPaneCheck) Run(ctx *CheckContext) *CheckResult {
 
 	// Filter to gt-* sessions only
 	var gtSessions []string
-	for _, session := range sessions {
-		if strings.HasPrefix(session, "gt-") {
-			gtSessions = append(gtSessions, session)
+	for _, sess := range sessions {
+		if session.HasRigPrefix(sess) {
+			gtSessions = append(gtSessions, sess)
 		}
 	}
 
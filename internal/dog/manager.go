@@ -147,7 +147,7 @@ func (m *Manager) createRigWorktree(dogPath, dogName, rigName string) (string, e
 	branchName := fmt.Sprintf("dog/%s-%s-%d", dogName, rigName, time.Now().UnixMilli())
 
 	// Create worktree with new branch from default branch
-	if err := repoGit.WorktreeAddFromRef(worktreePath, branchName, startPoint); err != nil {
+	if err := repoGit.AddWorktree(worktreePath, git.AddWorktreeOptions{Branch: branchName, NewBranch: true, StartPoint: startPoint}); err != nil {
 		return "", fmt.Errorf("creating worktree from %s: %w", startPoint, err)
 	}
 
@@ -198,13 +198,10 @@ func (m *Manager) Remove(name string) error {
 		}
 
 		// Try to remove worktree properly
-		if err := repoGit.WorktreeRemove(worktreePath, true); err != nil {
+		if err := repoGit.RemoveWorktree(worktreePath, true); err != nil {
 			// Log but continue - will remove directory below
 			fmt.Printf("Warning: could not remove worktree %s: %v\n", worktreePath, err)
 		}
-
-		// Prune stale entries
-		_ = repoGit.WorktreePrune()
 	}
 
 	// Remove dog directory
@@ -352,9 +349,8 @@ func (m *Manager) Refresh(name string) error {
 
 		// Remove old worktree if it exists
 		if oldWorktreePath != "" {
-			_ = repoGit.WorktreeRemove(oldWorktreePath, true)
+			_ = repoGit.RemoveWorktree(oldWorktreePath, true)
 			_ = os.RemoveAll(oldWorktreePath)
-			_ = repoGit.WorktreePrune()
 		}
 
 		// Fetch latest from origin
@@ -403,9 +399,8 @@ func (m *Manager) RefreshRig(name, rigName string) error {
 
 	// Remove old worktree if it exists
 	if oldWorktreePath != "" {
-		_ = repoGit.WorktreeRemove(oldWorktreePath, true)
+		_ = repoGit.RemoveWorktree(oldWorktreePath, true)
 		_ = os.RemoveAll(oldWorktreePath)
-		_ = repoGit.WorktreePrune()
 	}
 
 	// Fetch latest
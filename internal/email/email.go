@@ -0,0 +1,84 @@
+// Package email sends a town's high-severity events by email, for
+// operators who don't run a chat integration.
+//
+// The daemon reads budget, crash loop, and high or critical escalation
+// events from the town's event log (Notifier.Sync) and mails each to the
+// recipients of its rig, or of the town, through an SMTP server. Recipients
+// may take a digest instead: the events collected into one email an hour
+// or a day.
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Mailer sends email.
+type Mailer interface {
+	Send(to []string, subject, body string) error
+}
+
+// SMTP sends through an SMTP server, with STARTTLS when the server offers
+// it. Logging in requires TLS, except to localhost.
+type SMTP struct {
+	Host     string
+	Port     int
+	Username string // empty sends without logging in
+	Password string
+	From     string
+}
+
+// Send implements Mailer.
+func (s *SMTP) Send(to []string, subject, body string) error {
+	from, err := mail.ParseAddress(s.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", s.From, err)
+	}
+	var rcpts []string
+	for _, addr := range to {
+		a, err := mail.ParseAddress(addr)
+		if err != nil {
+			return fmt.Errorf("invalid recipient %q: %w", addr, err)
+		}
+		rcpts = append(rcpts, a.Address)
+	}
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+	if err := smtp.SendMail(addr, auth, from.Address, rcpts, Message(s.From, to, subject, body, time.Now())); err != nil {
+		return fmt.Errorf("sending email via %s: %w", addr, err)
+	}
+	return nil
+}
+
+// Message returns a plain text email.
+func Message(from string, to []string, subject, body string, date time.Time) []byte {
+	var buf bytes.Buffer
+	header := func(key, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+	header("From", from)
+	header("To", strings.Join(to, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	buf.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	_, _ = qp.Write([]byte(strings.ReplaceAll(body, "\n", "\r\n")))
+	_ = qp.Close()
+	return buf.Bytes()
+}
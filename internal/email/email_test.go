@@ -0,0 +1,121 @@
+package email
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestMessage(t *testing.T) {
+	msg := string(Message("Gas Town <gt@example.com>", []string{"a@example.com", "b@example.com"},
+		"Rig gastown is over budget: daily $52.10 of $50.00 — again", "line one\nline two\n", time.Unix(0, 0).UTC()))
+	for _, want := range []string{
+		"From: Gas Town <gt@example.com>\r\n",
+		"To: a@example.com, b@example.com\r\n",
+		"Subject: =?utf-8?q?",
+		"Content-Transfer-Encoding: quoted-printable\r\n",
+		"\r\n\r\nline one\r\nline two\r\n",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message lacks %q:\n%s", want, msg)
+		}
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		e    events.Event
+		want string
+	}{
+		{events.Event{Type: events.TypeBudgetExceeded}, "budget"},
+		{events.Event{Type: events.TypeCrashLoop}, "crash"},
+		{events.Event{Type: events.TypeEscalationSent, Payload: map[string]interface{}{"severity": "critical"}}, "escalation"},
+		{events.Event{Type: events.TypeEscalationSent, Payload: map[string]interface{}{"severity": "medium"}}, ""},
+		{events.Event{Type: events.TypeMerged}, ""},
+	}
+	for _, tt := range tests {
+		if got := KindOf(tt.e); got != tt.want {
+			t.Errorf("KindOf(%s %v) = %q, want %q", tt.e.Type, tt.e.Payload, got, tt.want)
+		}
+	}
+}
+
+// outbox is a Mailer that records what it sends, failing while err is set.
+type outbox struct {
+	sent []string
+	err  error
+}
+
+func (o *outbox) Send(to []string, subject, body string) error {
+	if o.err != nil {
+		return o.err
+	}
+	o.sent = append(o.sent, strings.Join(to, ",")+" | "+subject)
+	return nil
+}
+
+func logEvents(t *testing.T, townRoot string, evs ...events.Event) {
+	t.Helper()
+	f, err := os.OpenFile(filepath.Join(townRoot, events.EventsFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, e := range evs {
+		e.Timestamp = time.Now().UTC().Format(time.RFC3339)
+		data, _ := json.Marshal(e)
+		_, _ = f.Write(append(data, '\n'))
+	}
+}
+
+func TestNotifierSync(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+	box := &outbox{}
+	n := &Notifier{
+		Town:   config.EmailConfig{SMTPHost: "smtp.example.com", To: []string{"town@example.com"}},
+		Rigs:   map[string]config.EmailRouting{"beads": {To: []string{"beads@example.com"}, Digest: config.EmailDigestHourly}},
+		Mailer: box,
+		Prefix: "[gt hq]",
+		Now:    func() time.Time { return now },
+	}
+	if sent, err := n.Sync(townRoot, false); err != nil || sent != 0 {
+		t.Fatalf("first sync = %d, %v", sent, err)
+	}
+
+	logEvents(t, townRoot,
+		events.Event{Type: events.TypeBudgetExceeded, Actor: "daemon", Payload: events.BudgetExceededPayload("gastown", "daily $52.10 of $50.00", nil)},
+		events.Event{Type: events.TypeCrashLoop, Actor: "daemon", Payload: events.CrashLoopPayload("beads", "beads/polecats/nux", "gt-beads-nux", 3, "1h0m0s")},
+		events.Event{Type: events.TypeMerged, Actor: "gastown/refinery"},
+	)
+	if sent, err := n.Sync(townRoot, false); err != nil || sent != 1 {
+		t.Fatalf("sync = %d, %v (sent %q)", sent, err, box.sent)
+	}
+	if want := "town@example.com | [gt hq] Rig gastown is over budget: daily $52.10 of $50.00"; box.sent[0] != want {
+		t.Errorf("sent %q, want %q", box.sent[0], want)
+	}
+
+	// The beads digest goes out once due, and is retried if sending fails
+	now = now.Add(time.Hour)
+	box.err = errors.New("connection refused")
+	if _, err := n.Sync(townRoot, false); err == nil {
+		t.Fatal("failed digest reported no error")
+	}
+	box.err = nil
+	if sent, err := n.Sync(townRoot, false); err != nil || sent != 1 {
+		t.Fatalf("digest sync = %d, %v", sent, err)
+	}
+	if want := "beads@example.com | [gt hq] Hourly digest for beads: 1 event(s)"; box.sent[1] != want {
+		t.Errorf("sent %q, want %q", box.sent[1], want)
+	}
+	if sent, _ := n.Sync(townRoot, true); sent != 0 {
+		t.Errorf("flushed %d digests after sending them all", sent)
+	}
+}
@@ -0,0 +1,223 @@
+package email
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// MaxPerSync bounds how many events one Sync handles, so a burst doesn't
+// flood inboxes; the rest wait for the next sync.
+const MaxPerSync = 20
+
+// KindOf returns the kind (see config.EmailEventKinds) an event is emailed
+// as, or "" if it isn't emailed. Only high and critical escalations are.
+func KindOf(e events.Event) string {
+	switch e.Type {
+	case events.TypeBudgetExceeded:
+		return "budget"
+	case events.TypeCrashLoop, events.TypeMassDeath:
+		return "crash"
+	case events.TypeEscalationSent:
+		switch severity, _ := e.Payload["severity"].(string); severity {
+		case config.SeverityHigh, config.SeverityCritical:
+			return "escalation"
+		}
+	}
+	return ""
+}
+
+// Summary returns a one-line description of an event, used as its
+// subject and in digests.
+func Summary(e events.Event) string {
+	str := func(key string) string {
+		s, _ := e.Payload[key].(string)
+		return s
+	}
+	switch e.Type {
+	case events.TypeBudgetExceeded:
+		return fmt.Sprintf("Rig %s is over budget: %s", str("rig"), str("summary"))
+	case events.TypeCrashLoop:
+		return fmt.Sprintf("%s crashed %v times within %s", str("agent"), e.Payload["count"], str("window"))
+	case events.TypeMassDeath:
+		return fmt.Sprintf("%v sessions died within %s", e.Payload["count"], str("window"))
+	case events.TypeEscalationSent:
+		return fmt.Sprintf("[%s] Escalation %s from %s: %s", strings.ToUpper(str("severity")), str("rig"), e.Actor, str("reason"))
+	}
+	return fmt.Sprintf("%s by %s", e.Type, e.Actor)
+}
+
+// Body returns the email for an event: its summary, then its details.
+func Body(e events.Event) string {
+	lines := []string{Summary(e), "", "Event: " + e.Type, "Actor: " + e.Actor, "Time:  " + e.Timestamp}
+	keys := make([]string, 0, len(e.Payload))
+	for k := range e.Payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if len(keys) > 0 {
+		lines = append(lines, "")
+	}
+	for _, k := range keys {
+		lines = append(lines, fmt.Sprintf("%s: %v", k, e.Payload[k]))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// digest is the events collected for one set of recipients since its
+// last email.
+type digest struct {
+	To      []string  `json:"to"`
+	Mode    string    `json:"mode"` // hourly, daily
+	Started time.Time `json:"started"`
+	Items   []string  `json:"items"`
+}
+
+// due reports whether the digest's email should be sent at now.
+func (d *digest) due(now time.Time) bool {
+	period := time.Hour
+	if d.Mode == config.EmailDigestDaily {
+		period = 24 * time.Hour
+	}
+	return !now.Before(d.Started.Add(period))
+}
+
+// runtimeDir returns where a town's email state is kept.
+func runtimeDir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "email")
+}
+
+// loadDigests returns the town's pending digests, by rig ("" = the town's
+// recipients).
+func loadDigests(townRoot string) (map[string]*digest, error) {
+	digests := make(map[string]*digest)
+	data, err := os.ReadFile(filepath.Join(runtimeDir(townRoot), "digests.json")) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return digests, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &digests); err != nil {
+		return nil, fmt.Errorf("parsing email digests: %w", err)
+	}
+	return digests, nil
+}
+
+// saveDigests stores the town's pending digests.
+func saveDigests(townRoot string, digests map[string]*digest) error {
+	if err := os.MkdirAll(runtimeDir(townRoot), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(filepath.Join(runtimeDir(townRoot), "digests.json"), digests)
+}
+
+// Notifier emails a town's high-severity events.
+type Notifier struct {
+	Town   config.EmailConfig
+	Rigs   map[string]config.EmailRouting // rigs that route their own events
+	Mailer Mailer
+	Prefix string           // subject prefix (e.g., "[gt hq]")
+	Now    func() time.Time // time.Now if nil
+}
+
+// route returns who an event is emailed to, keyed by the rig whose
+// routing applies ("" = the town's), or false if it isn't emailed.
+func (n *Notifier) route(e events.Event) (string, config.EmailRouting, bool) {
+	kind := KindOf(e)
+	if kind == "" {
+		return "", config.EmailRouting{}, false
+	}
+	if rig := events.RigOf(e); rig != "" {
+		if r, ok := n.Rigs[rig]; ok {
+			return rig, r, r.Sends(kind)
+		}
+	}
+	r := n.Town.Routing()
+	return "", r, r.Sends(kind)
+}
+
+// subject returns an email subject with the notifier's prefix.
+func (n *Notifier) subject(s string) string {
+	return strings.TrimSpace(n.Prefix + " " + s)
+}
+
+// Sync emails the events logged since the last sync, up to MaxPerSync,
+// or adds them to their recipients' digests, then sends the digests that
+// are due (all of them with flush). It returns how many emails it sent.
+// The first sync only marks where the log ends, so history isn't mailed;
+// an event or digest that fails to send is retried on the next sync.
+func (n *Notifier) Sync(townRoot string, flush bool) (int, error) {
+	now := time.Now()
+	if n.Now != nil {
+		now = n.Now()
+	}
+	digests, err := loadDigests(townRoot)
+	if err != nil {
+		return 0, err
+	}
+
+	mailed := 0
+	_, syncErr := events.Forward(townRoot, filepath.Join(runtimeDir(townRoot), "cursor.json"), MaxPerSync,
+		func(e events.Event) bool {
+			_, _, ok := n.route(e)
+			return ok
+		},
+		func(e events.Event) error {
+			key, r, _ := n.route(e)
+			if r.Digest == "" {
+				if err := n.Mailer.Send(r.To, n.subject(Summary(e)), Body(e)); err != nil {
+					return err
+				}
+				mailed++
+				return nil
+			}
+			d := digests[key]
+			if d == nil {
+				d = &digest{Mode: r.Digest, Started: now}
+				digests[key] = d
+			}
+			d.To = r.To
+			d.Items = append(d.Items, fmt.Sprintf("%s  %s", e.Timestamp, Summary(e)))
+			return nil
+		})
+
+	keys := make([]string, 0, len(digests))
+	for key := range digests {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		d := digests[key]
+		if !flush && !d.due(now) {
+			continue
+		}
+		mode := strings.ToUpper(d.Mode[:1]) + d.Mode[1:]
+		subject := fmt.Sprintf("%s digest: %d event(s)", mode, len(d.Items))
+		if key != "" {
+			subject = fmt.Sprintf("%s digest for %s: %d event(s)", mode, key, len(d.Items))
+		}
+		if err := n.Mailer.Send(d.To, n.subject(subject), strings.Join(d.Items, "\n")+"\n"); err != nil {
+			if syncErr == nil {
+				syncErr = err
+			}
+			continue
+		}
+		delete(digests, key)
+		mailed++
+	}
+
+	if err := saveDigests(townRoot, digests); err != nil {
+		return mailed, err
+	}
+	return mailed, syncErr
+}
@@ -0,0 +1,251 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Tamper evidence. With [events] chain, each event records in "prev" the
+// SHA-256 of the line before it (GenesisHash for the first line), so editing,
+// inserting, or deleting an entry breaks the chain from there on. With
+// [events] signing_key, each event also carries in "sig" an HMAC-SHA256 of
+// its line without the sig, so the chain can't be rebuilt without the key.
+//
+// Pruning old events breaks the chain at the new first line; Prune records
+// an events_pruned event naming that line's prev as an anchor, which Verify
+// accepts. Truncating the newest events can't be detected from the log
+// alone: compare Verify's head hash with one recorded elsewhere.
+
+// GenesisHash is the prev of the first event in a chained log.
+const GenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// TypeEventsPruned records that Prune removed old events from a chained log.
+const TypeEventsPruned = "events_pruned"
+
+// chainSettings returns whether the town chains its events and the key
+// that signs them (nil = unsigned).
+func chainSettings(townRoot string) (bool, []byte) {
+	cfg, err := config.LoadGastownConfig(townRoot)
+	if err != nil || !cfg.Events.Chained() {
+		return false, nil
+	}
+	if cfg.Events.SigningKey == "" {
+		return true, nil
+	}
+	key, err := config.ExpandRefs(cfg.Events.SigningKey)
+	if err != nil || key == "" {
+		return true, nil // entries go unsigned, which Verify reports
+	}
+	return true, []byte(key)
+}
+
+// lineHash returns the chain hash of a log line (without its newline).
+func lineHash(line []byte) string {
+	sum := sha256.Sum256(line)
+	return hex.EncodeToString(sum[:])
+}
+
+// sign returns the HMAC-SHA256 of an unsigned line.
+func sign(key, line []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(line)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// sigSuffix is how a signature ends a signed line.
+func sigSuffix(sig string) string {
+	return `,"sig":"` + sig + `"}`
+}
+
+// seal marshals event as the next line after prevLine (nil = the first
+// line), chaining and optionally signing it.
+func seal(event Event, prevLine []byte, key []byte) ([]byte, error) {
+	event.Prev = GenesisHash
+	if prevLine != nil {
+		event.Prev = lineHash(prevLine)
+	}
+	event.Sig = ""
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	if key != nil {
+		// Appending the sig keeps the signed bytes recoverable exactly,
+		// without depending on how the line would re-marshal
+		data = append(data[:len(data)-1], sigSuffix(sign(key, data))...)
+	}
+	return data, nil
+}
+
+// lastLine returns the last non-empty line of the file at path, or nil if
+// it has none.
+func lastLine(path string) ([]byte, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is the town's events log
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	// Read backwards in chunks until a complete line is in hand
+	const chunk = 64 * 1024
+	end := info.Size()
+	var tail []byte
+	for end > 0 {
+		start := end - chunk
+		if start < 0 {
+			start = 0
+		}
+		buf := make([]byte, end-start)
+		if _, err := f.ReadAt(buf, start); err != nil && err != io.EOF {
+			return nil, err
+		}
+		tail = append(buf, tail...)
+		end = start
+		trimmed := bytes.TrimRight(tail, "\n")
+		if i := bytes.LastIndexByte(trimmed, '\n'); i >= 0 {
+			return trimmed[i+1:], nil
+		}
+	}
+	trimmed := bytes.TrimRight(tail, "\n")
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+	return trimmed, nil
+}
+
+// Problem is a break in the chain Verify found.
+type Problem struct {
+	Line    int    `json:"line"` // 1-based line number in the log
+	Problem string `json:"problem"`
+}
+
+// Verification is the result of checking a log's chain.
+type Verification struct {
+	Entries  int       `json:"entries"`
+	Chained  int       `json:"chained"`            // entries with a prev hash
+	Signed   int       `json:"signed"`             // entries whose sig was checked
+	Head     string    `json:"head,omitempty"`     // hash of the last line, to record elsewhere
+	Problems []Problem `json:"problems,omitempty"` // empty = intact
+}
+
+// OK reports whether the chain is intact.
+func (v *Verification) OK() bool {
+	return len(v.Problems) == 0
+}
+
+// Verify checks the chain of townRoot's event log. Entries before the chain
+// began (written before [events] chain was turned on) are counted but not
+// checked. With a key, every chained entry must carry a valid signature;
+// without one, signatures are not checked.
+func Verify(townRoot string, key []byte) (*Verification, error) {
+	data, err := os.ReadFile(filepath.Join(townRoot, EventsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Verification{}, nil
+		}
+		return nil, fmt.Errorf("reading events file: %w", err)
+	}
+
+	type line struct {
+		n     int
+		bytes []byte
+		event Event
+		ok    bool // parsed
+	}
+	var lines []line
+	anchors := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		raw := append([]byte(nil), scanner.Bytes()...)
+		if len(bytes.TrimSpace(raw)) == 0 {
+			continue
+		}
+		l := line{n: n, bytes: raw}
+		l.ok = json.Unmarshal(raw, &l.event) == nil
+		// A forged prune record is itself unsigned or mis-signed, which
+		// the signature check below reports
+		if l.ok && l.event.Type == TypeEventsPruned {
+			if anchor, _ := l.event.Payload["anchor"].(string); anchor != "" {
+				anchors[anchor] = true
+			}
+		}
+		lines = append(lines, l)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading events file: %w", err)
+	}
+
+	v := &Verification{Entries: len(lines)}
+	problem := func(l line, format string, args ...interface{}) {
+		v.Problems = append(v.Problems, Problem{Line: l.n, Problem: fmt.Sprintf(format, args...)})
+	}
+	started := false
+	for i, l := range lines {
+		if !l.ok {
+			if started {
+				problem(l, "not a valid event")
+			}
+			continue
+		}
+		if l.event.Prev == "" {
+			if started {
+				problem(l, "not chained (inserted, or chaining was turned off)")
+			}
+			continue
+		}
+		started = true
+		v.Chained++
+
+		want := GenesisHash
+		if i > 0 {
+			want = lineHash(lines[i-1].bytes)
+		}
+		switch {
+		case l.event.Prev == want:
+		case i == 0 && anchors[l.event.Prev]:
+			// The first line after a prune
+		case l.event.Prev == GenesisHash:
+			problem(l, "chain restarts here: earlier entries were replaced")
+		default:
+			problem(l, "previous entry was edited, inserted, or removed")
+		}
+
+		if key == nil {
+			continue
+		}
+		if l.event.Sig == "" {
+			problem(l, "not signed")
+			continue
+		}
+		suffix := sigSuffix(l.event.Sig)
+		unsigned, ok := strings.CutSuffix(string(l.bytes), suffix)
+		if !ok || !hmac.Equal([]byte(sign(key, []byte(unsigned+"}"))), []byte(l.event.Sig)) {
+			problem(l, "signature does not match: the entry was edited or signed with another key")
+			continue
+		}
+		v.Signed++
+	}
+	if len(lines) > 0 {
+		v.Head = lineHash(lines[len(lines)-1].bytes)
+	}
+	return v, nil
+}
@@ -0,0 +1,193 @@
+package events
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// chainedTown returns a temp town whose gastown.toml has the given [events]
+// settings, with n events appended through the chaining writer.
+func chainedTown(t *testing.T, settings string, n int) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	path := config.GastownConfigPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("[events]\n"+settings+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		appendEvent(t, townRoot, Event{Timestamp: at(time.Duration(n-i) * time.Hour), Type: TypeToolExec, Actor: "gastown/polecats/toast",
+			Payload: map[string]interface{}{"n": i}})
+	}
+	return townRoot
+}
+
+func appendEvent(t *testing.T, townRoot string, e Event) {
+	t.Helper()
+	if err := appendLocked(townRoot, filepath.Join(townRoot, EventsFile), e); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// editLines rewrites the town's events log through fn.
+func editLines(t *testing.T, townRoot string, fn func(lines [][]byte) [][]byte) {
+	t.Helper()
+	path := filepath.Join(townRoot, EventsFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := fn(bytes.Split(bytes.TrimRight(data, "\n"), []byte("\n")))
+	if err := os.WriteFile(path, append(bytes.Join(lines, []byte("\n")), '\n'), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// problemLines reports the lines Verify flags.
+func problemLines(t *testing.T, townRoot string, key []byte) []int {
+	t.Helper()
+	v, err := Verify(townRoot, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []int
+	for _, p := range v.Problems {
+		lines = append(lines, p.Line)
+	}
+	return lines
+}
+
+func TestVerifyChain(t *testing.T) {
+	townRoot := chainedTown(t, "chain = true", 4)
+	v, err := Verify(townRoot, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.OK() || v.Entries != 4 || v.Chained != 4 || len(v.Head) != 64 {
+		t.Fatalf("intact chain: %+v", v)
+	}
+
+	tests := []struct {
+		name string
+		edit func(lines [][]byte) [][]byte
+		want []int
+	}{
+		{"edited", func(l [][]byte) [][]byte {
+			l[1] = bytes.Replace(l[1], []byte(`"n":1`), []byte(`"n":9`), 1)
+			return l
+		}, []int{3}},
+		{"removed", func(l [][]byte) [][]byte { return append(l[:1], l[2:]...) }, []int{2}},
+		{"inserted", func(l [][]byte) [][]byte {
+			return append(l[:2], append([][]byte{[]byte(`{"ts":"2026-01-01T00:00:00Z","type":"tool_exec"}`)}, l[2:]...)...)
+		}, []int{3, 4}},
+		{"reordered", func(l [][]byte) [][]byte { l[1], l[2] = l[2], l[1]; return l }, []int{2, 3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			town := chainedTown(t, "chain = true", 4)
+			editLines(t, town, tt.edit)
+			if got := problemLines(t, town, nil); !equalInts(got, tt.want) {
+				t.Errorf("problems on lines %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyUnchainedPrefix(t *testing.T) {
+	// Events from before chaining was turned on aren't checked
+	townRoot := writeEvents(t, Event{Timestamp: at(time.Hour), Type: TypeToolExec})
+	editLines(t, townRoot, func(l [][]byte) [][]byte { return l[:1] })
+	path := config.GastownConfigPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("[events]\nchain = true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	appendEvent(t, townRoot, Event{Timestamp: at(time.Minute), Type: TypeToolExec})
+	if got := problemLines(t, townRoot, nil); len(got) != 0 {
+		t.Errorf("problems on lines %v, want none", got)
+	}
+}
+
+func TestVerifySigned(t *testing.T) {
+	t.Setenv("GT_TEST_EVENTS_KEY", "s3cret")
+	townRoot := chainedTown(t, `signing_key = "${GT_TEST_EVENTS_KEY}"`, 3)
+	v, err := Verify(townRoot, []byte("s3cret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.OK() || v.Signed != 3 {
+		t.Fatalf("signed chain: %+v", v)
+	}
+	if got := problemLines(t, townRoot, []byte("wrong")); !equalInts(got, []int{1, 2, 3}) {
+		t.Errorf("wrong key: problems on lines %v", got)
+	}
+
+	// An entry forged without the key fails its signature
+	editLines(t, townRoot, func(l [][]byte) [][]byte {
+		forged, err := seal(Event{Timestamp: at(time.Hour), Type: TypeToolExec}, nil, []byte("forged"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		l[0] = forged
+		return l
+	})
+	if got := problemLines(t, townRoot, []byte("s3cret")); !equalInts(got, []int{1, 2}) {
+		t.Errorf("forged entry: problems on lines %v, want [1 2]", got)
+	}
+
+	// An unsigned entry in a signed log is flagged
+	townRoot = chainedTown(t, "chain = true", 2)
+	if got := problemLines(t, townRoot, []byte("s3cret")); !equalInts(got, []int{1, 2}) {
+		t.Errorf("unsigned entries: problems on lines %v", got)
+	}
+}
+
+func TestPruneKeepsChain(t *testing.T) {
+	townRoot := chainedTown(t, "chain = true", 5)
+	removed, err := Prune(townRoot, 150*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 3 {
+		t.Fatalf("removed = %d, want 3", removed)
+	}
+	v, err := Verify(townRoot, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.OK() || v.Entries != 3 {
+		t.Errorf("after prune: %+v", v)
+	}
+	data, _ := os.ReadFile(filepath.Join(townRoot, EventsFile))
+	if !strings.Contains(string(data), `"type":"events_pruned"`) {
+		t.Errorf("prune not recorded:\n%s", data)
+	}
+
+	// Cutting the head off without Prune is caught
+	editLines(t, townRoot, func(l [][]byte) [][]byte { return l[1:] })
+	if got := problemLines(t, townRoot, nil); len(got) == 0 {
+		t.Error("truncated head not detected")
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -1,10 +1,15 @@
 // Package events provides event logging for the gt activity feed.
 //
 // Events are written to ~/gt/.events.jsonl (raw audit log) and later
-// curated by the feed daemon into ~/.feed.jsonl (user-facing).
+// curated by the feed daemon into ~/.feed.jsonl (user-facing). The raw log
+// is append-only: besides feed events it records agent session lifecycle,
+// prompts sent to agents, the tools agents run, merges, and config changes.
+// Query reads it back; Prune enforces the town's retention policy.
 package events
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -12,6 +17,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gofrs/flock"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/secret"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -23,6 +32,10 @@ type Event struct {
 	Actor      string                 `json:"actor"`
 	Payload    map[string]interface{} `json:"payload,omitempty"`
 	Visibility string                 `json:"visibility"`
+
+	// Set only when the town chains its log (see chain.go)
+	Prev string `json:"prev,omitempty"` // SHA-256 of the previous line
+	Sig  string `json:"sig,omitempty"`  // HMAC-SHA256 of this line without sig
 }
 
 // Visibility levels for events.
@@ -53,6 +66,7 @@ const (
 	// Session death events (for crash investigation)
 	TypeSessionDeath = "session_death" // Feed-visible session termination
 	TypeMassDeath    = "mass_death"    // Multiple sessions died in short window
+	TypeCrashLoop    = "crash_loop"    // One polecat's session keeps crashing
 
 	// Witness patrol events
 	TypePatrolStarted   = "patrol_started"
@@ -68,14 +82,51 @@ const (
 	TypeMerged       = "merged"
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
+
+	// Push guard events (emitted by git.Push)
+	TypePushBlocked  = "push_blocked"
+	TypePushOverride = "push_override"
+
+	// Audit trail of what agents are told and do
+	TypeAgentStarted   = "agent_started"   // tmux session started for an agent
+	TypeAgentStopped   = "agent_stopped"   // agent's tmux session stopped
+	TypePromptSent     = "prompt_sent"     // prompt nudged into an agent session
+	TypeToolExec       = "tool_exec"       // tool an agent ran (PostToolUse hook)
+	TypeConfigChange   = "config_change"   // town or rig settings changed by gt
+	TypePolicyDenied   = "policy_denied"   // tool call blocked by the town's tool policy
+	TypePromptFiltered = "prompt_filtered" // prompt content filtered or blocked before leaving for the API
+	TypeChatCommand    = "chat_command"    // control command run from Slack or Discord (gt slack, gt discord)
+
+	// Runaway-loop detection (gt loop)
+	TypeLoopDetected = "loop_detected" // session paused as a runaway loop
+	TypeLoopResumed  = "loop_resumed"  // paused session resumed by a human
+
+	// Spend alerting (gt budget check)
+	TypeCostAnomaly    = "cost_anomaly"    // a rig's hourly spend crossed its cap or spiked
+	TypeBudgetExceeded = "budget_exceeded" // a rig went over its daily or weekly budget
 )
 
 // EventsFile is the name of the raw events log.
 const EventsFile = ".events.jsonl"
 
-// mutex protects concurrent writes to the events file.
+// maxArgLen bounds each string recorded in a tool event's arguments, so
+// large file writes do not bloat the log.
+const maxArgLen = 2048
+
+// mutex protects concurrent writes to the events file within a process;
+// the file lock in lockFile serializes writers across processes.
 var mutex sync.Mutex
 
+// lockFile locks the events log at eventsPath against other writers and
+// Prune. The caller must Unlock it.
+func lockFile(eventsPath string) (*flock.Flock, error) {
+	lock := flock.New(eventsPath + ".lock")
+	if err := lock.Lock(); err != nil {
+		return nil, fmt.Errorf("locking events file: %w", err)
+	}
+	return lock, nil
+}
+
 // Log writes an event to the events log.
 // The event is appended to ~/gt/.events.jsonl.
 // Returns nil if logging fails (events are best-effort).
@@ -112,16 +163,41 @@ func write(event Event) error {
 
 	eventsPath := filepath.Join(townRoot, EventsFile)
 
-	// Marshal event to JSON
-	data, err := json.Marshal(event)
-	if err != nil {
-		return fmt.Errorf("marshaling event: %w", err)
+	// Keep credentials in payloads (tool arguments, prompts) out of the log
+	if event.Payload != nil {
+		event.Payload = secret.RedactValue(event.Payload).(map[string]interface{})
 	}
-	data = append(data, '\n')
 
 	// Append to file with proper locking
 	mutex.Lock()
 	defer mutex.Unlock()
+	lock, err := lockFile(eventsPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	return appendLocked(townRoot, eventsPath, event)
+}
+
+// appendLocked appends an event to the events file at eventsPath, chaining it
+// to the last line if the town chains its log. The caller holds the lock.
+func appendLocked(townRoot, eventsPath string, event Event) error {
+	var data []byte
+	var err error
+	if chained, key := chainSettings(townRoot); chained {
+		prev, lerr := lastLine(eventsPath)
+		if lerr != nil {
+			return fmt.Errorf("reading events file: %w", lerr)
+		}
+		data, err = seal(event, prev, key)
+	} else {
+		data, err = json.Marshal(event)
+	}
+	if err != nil {
+		return fmt.Errorf("marshaling event: %w", err)
+	}
+	data = append(data, '\n')
 
 	f, err := os.OpenFile(eventsPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
 	if err != nil {
@@ -136,6 +212,21 @@ func write(event Event) error {
 	return nil
 }
 
+// LogPrompt records a prompt sent to an agent's tmux session: its SHA-256
+// and length, and its text only if the town sets [events] record_prompts.
+// Best-effort, like Log.
+func LogPrompt(actor, session, prompt string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil
+	}
+	withBody := false
+	if cfg, err := config.LoadGastownConfig(townRoot); err == nil {
+		withBody = cfg.Events.RecordPrompts
+	}
+	return LogAudit(TypePromptSent, actor, PromptPayload(session, prompt, withBody))
+}
+
 // Payload helpers for common event structures.
 
 // SlingPayload creates a payload for sling events.
@@ -311,6 +402,22 @@ func MassDeathPayload(count int, window string, sessions []string, possibleCause
 	return p
 }
 
+// CrashLoopPayload creates a payload for crash loop events.
+// rig: the polecat's rig
+// agent: the polecat's address (e.g., "gastown/polecats/toast")
+// session: its tmux session
+// count: how many times it crashed within window
+// window: the detection window (e.g., "1h0m0s")
+func CrashLoopPayload(rig, agent, session string, count int, window string) map[string]interface{} {
+	return map[string]interface{}{
+		"rig":     rig,
+		"agent":   agent,
+		"session": session,
+		"count":   count,
+		"window":  window,
+	}
+}
+
 // SessionPayload creates a payload for session start/end events.
 // sessionID: Claude Code session UUID
 // role: Gas Town role (e.g., "gastown/crew/joe", "deacon")
@@ -330,3 +437,170 @@ func SessionPayload(sessionID, role, topic, cwd string) map[string]interface{} {
 	}
 	return p
 }
+
+// AgentPayload creates a payload for agent session start/stop events.
+// role: Gas Town role (e.g., "polecat", "crew", "refinery")
+// session: tmux session name
+// agent: agent the session runs (e.g., "claude"), empty for stop events
+func AgentPayload(rig, role, session, agent string) map[string]interface{} {
+	p := map[string]interface{}{
+		"rig":     rig,
+		"role":    role,
+		"session": session,
+	}
+	if agent != "" {
+		p["agent"] = agent
+	}
+	return p
+}
+
+// PromptPayload creates a payload for prompt events. The prompt is
+// identified by its SHA-256 hash; its text is included only if withBody.
+func PromptPayload(session, prompt string, withBody bool) map[string]interface{} {
+	sum := sha256.Sum256([]byte(prompt))
+	p := map[string]interface{}{
+		"session": session,
+		"sha256":  hex.EncodeToString(sum[:]),
+		"length":  len(prompt),
+	}
+	if withBody {
+		p["body"] = prompt
+	}
+	return p
+}
+
+// ToolPayload creates a payload for tool execution events.
+// tool: tool name reported by the runtime (e.g., "Bash", "Edit")
+// args: the tool's input; strings longer than maxArgLen are truncated
+// sessionID: runtime session UUID, if known
+func ToolPayload(tool string, args map[string]interface{}, sessionID string) map[string]interface{} {
+	p := map[string]interface{}{
+		"tool": tool,
+		"args": truncateArgs(args),
+	}
+	if sessionID != "" {
+		p["session_id"] = sessionID
+	}
+	return p
+}
+
+// PolicyPayload creates a payload for policy denial events.
+// tool: tool name reported by the runtime (e.g., "Bash", "Edit")
+// args: the tool's input, truncated like ToolPayload's
+// rule: the check that denied the call (path, command, or network)
+// reason: the explanation returned to the model
+// sessionID: runtime session UUID, if known
+func PolicyPayload(tool string, args map[string]interface{}, rule, reason, sessionID string) map[string]interface{} {
+	p := ToolPayload(tool, args, sessionID)
+	p["rule"] = rule
+	p["reason"] = reason
+	return p
+}
+
+// LoopPayload creates a payload for runaway-loop events.
+// session: tmux session paused or resumed
+// pattern: the detector that fired (repeat_calls, repeat_prompts, burn_rate)
+// detail: what it saw (e.g., "Bash called 10 times in a row with the same input")
+func LoopPayload(session, pattern, detail string) map[string]interface{} {
+	p := map[string]interface{}{
+		"session": session,
+		"pattern": pattern,
+	}
+	if detail != "" {
+		p["detail"] = detail
+	}
+	return p
+}
+
+// PromptFilteredPayload creates a payload for outbound filter events. It
+// never includes the matched content.
+// session: tmux session the prompt was for, if known
+// mode: what the filter did (redact, pseudonymize, block)
+// rules: the rules that matched (e.g., "email", "deny list")
+// matches: how many matches in all
+func PromptFilteredPayload(session, mode string, rules []string, matches int) map[string]interface{} {
+	p := map[string]interface{}{
+		"mode":    mode,
+		"rules":   rules,
+		"matches": matches,
+	}
+	if session != "" {
+		p["session"] = session
+	}
+	return p
+}
+
+// ChatCommandPayload creates a payload for chat control command events.
+// via: where it was run (slack, discord)
+// user: the chat user who ran it, as "name (ID)"
+// verb: the command (prompt, approve, stop)
+// target: what it acted on (a session, an MR)
+func ChatCommandPayload(via, user, verb, target string) map[string]interface{} {
+	return map[string]interface{}{
+		"via":    via,
+		"user":   user,
+		"verb":   verb,
+		"target": target,
+	}
+}
+
+// CostAnomalyPayload creates a payload for cost anomaly events.
+// rig: the rig whose spend is anomalous
+// kind: the threshold crossed (hourly_cap, spike)
+// hourUSD: the rig's spend over the last hour
+// detail: what was seen (e.g., "$12.40 in the last hour, 8.3x the baseline of $1.50/hour")
+func CostAnomalyPayload(rig, kind string, hourUSD float64, detail string) map[string]interface{} {
+	return map[string]interface{}{
+		"rig":      rig,
+		"kind":     kind,
+		"hour_usd": hourUSD,
+		"detail":   detail,
+	}
+}
+
+// BudgetExceededPayload creates a payload for budget exceeded events.
+// rig: the rig over budget
+// summary: which caps were exceeded (e.g., "daily $52.10 of $50.00")
+// actions: the enforcement applied (block_spawn, pause_sessions, alert)
+func BudgetExceededPayload(rig, summary string, actions []string) map[string]interface{} {
+	return map[string]interface{}{
+		"rig":     rig,
+		"summary": summary,
+		"actions": actions,
+	}
+}
+
+// truncateArgs returns args with long strings cut to maxArgLen, recursing
+// into nested objects.
+func truncateArgs(args map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		switch v := v.(type) {
+		case string:
+			if len(v) > maxArgLen {
+				v = fmt.Sprintf("%s… (%d bytes)", v[:maxArgLen], len(v))
+			}
+			out[k] = v
+		case map[string]interface{}:
+			out[k] = truncateArgs(v)
+		default:
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// ConfigChangePayload creates a payload for config change events.
+// file: settings file changed (e.g., "settings/config.json")
+// key: setting changed (e.g., "default_agent", "agents.fast")
+// value: new value, empty if the setting was removed
+func ConfigChangePayload(file, key, value string) map[string]interface{} {
+	p := map[string]interface{}{
+		"file": file,
+		"key":  key,
+	}
+	if value != "" {
+		p["value"] = value
+	}
+	return p
+}
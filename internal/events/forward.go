@@ -0,0 +1,173 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// ChatKind returns the kind (see config.ChatEventKinds) chat integrations
+// post an event type as, or "" if they don't post it.
+func ChatKind(eventType string) string {
+	switch eventType {
+	case TypeAgentStarted, TypeAgentStopped, TypeSessionDeath, TypeMassDeath,
+		TypeLoopDetected, TypeLoopResumed:
+		return "session"
+	case TypeEscalationSent, TypeEscalationAcked, TypeEscalationClosed:
+		return "escalation"
+	case TypeMerged, TypeMergeFailed:
+		return "merge"
+	}
+	return ""
+}
+
+// RigOf returns the rig an event came from, or "" for town-level events:
+// the rig of its actor (e.g., "gastown" for "gastown/witness"), or else the
+// rig in its payload. Escalation payloads put the escalation's ID there, so
+// theirs isn't used.
+func RigOf(e Event) string {
+	if rig, _, ok := strings.Cut(e.Actor, "/"); ok {
+		return rig
+	}
+	if e.Type == TypeEscalationSent {
+		return ""
+	}
+	rig, _ := e.Payload["rig"].(string)
+	return rig
+}
+
+// Cursor is how far into the event log a forwarder (see Forward) has
+// delivered.
+type Cursor struct {
+	Offset int64  `json:"offset"`  // bytes of the log already read
+	LastTS string `json:"last_ts"` // timestamp of the last event read
+}
+
+// LoadCursor returns the cursor stored at path, or nil if there is none.
+func LoadCursor(path string) (*Cursor, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed by the caller from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parsing cursor %s: %w", filepath.Base(path), err)
+	}
+	return &c, nil
+}
+
+// SaveCursor stores c at path.
+func SaveCursor(path string, c *Cursor) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, c)
+}
+
+// pendingEvent is an undelivered event and the offset just past it.
+type pendingEvent struct {
+	event Event
+	end   int64
+}
+
+// pending returns the events after c in the town's log, and the offset of
+// the end of what was read. A log shorter than c.Offset was pruned, so it's
+// read from the start, skipping what c.LastTS says was already read.
+func pending(townRoot string, c *Cursor) ([]pendingEvent, int64, error) {
+	f, err := os.Open(filepath.Join(townRoot, EventsFile)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, nil
+		}
+		return nil, 0, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+	start, skipThrough := c.Offset, ""
+	if info.Size() < c.Offset {
+		start, skipThrough = 0, c.LastTS
+	}
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		return nil, 0, err
+	}
+
+	var out []pendingEvent
+	r := bufio.NewReader(f)
+	offset := start
+	for {
+		line, _ := r.ReadBytes('\n')
+		if len(line) == 0 || line[len(line)-1] != '\n' {
+			break // end of log, or a line still being written
+		}
+		offset += int64(len(line))
+		var e Event
+		if json.Unmarshal(bytes.TrimSpace(line), &e) != nil {
+			continue
+		}
+		if skipThrough != "" && e.Timestamp <= skipThrough {
+			continue
+		}
+		out = append(out, pendingEvent{event: e, end: offset})
+	}
+	return out, offset, nil
+}
+
+// Forward delivers the events logged since the cursor at cursorPath that
+// want accepts, up to max, by calling send, and returns how many it sent.
+// The first call only marks where the log ends, so history isn't replayed.
+// An event send fails on is retried by the next call.
+func Forward(townRoot, cursorPath string, max int, want func(Event) bool, send func(Event) error) (int, error) {
+	c, err := LoadCursor(cursorPath)
+	if err != nil {
+		return 0, err
+	}
+	if c == nil {
+		c = &Cursor{}
+		if info, err := os.Stat(filepath.Join(townRoot, EventsFile)); err == nil {
+			c.Offset = info.Size()
+		}
+		return 0, SaveCursor(cursorPath, c)
+	}
+
+	evs, end, err := pending(townRoot, c)
+	if err != nil {
+		return 0, fmt.Errorf("reading events: %w", err)
+	}
+	sent := 0
+	var sendErr error
+	done := true
+	for _, pe := range evs {
+		if want(pe.event) {
+			if sent == max {
+				done = false
+				break
+			}
+			if err := send(pe.event); err != nil {
+				sendErr, done = err, false
+				break
+			}
+			sent++
+		}
+		c.Offset, c.LastTS = pe.end, pe.event.Timestamp
+	}
+	if done {
+		c.Offset = end // past any lines skipped after the last event
+	}
+	if err := SaveCursor(cursorPath, c); err != nil {
+		return sent, err
+	}
+	return sent, sendErr
+}
@@ -0,0 +1,166 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Filter selects events from the log. Zero fields match everything.
+type Filter struct {
+	Types []string  // event types to include
+	Actor string    // actor address prefix (e.g., "gastown/" or "gastown/polecats/toast")
+	Since time.Time // only events at or after this time
+	Until time.Time // only events before this time
+	Limit int       // only the most recent Limit matches (0 = all)
+}
+
+// Time returns when e happened, or the zero time if its timestamp is
+// malformed.
+func (e Event) Time() time.Time {
+	t, _ := time.Parse(time.RFC3339, e.Timestamp)
+	return t
+}
+
+// matches reports whether e passes f.
+func (f Filter) matches(e Event) bool {
+	if len(f.Types) > 0 {
+		found := false
+		for _, t := range f.Types {
+			if e.Type == t {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.Actor != "" && !strings.HasPrefix(e.Actor, f.Actor) {
+		return false
+	}
+	if !f.Since.IsZero() || !f.Until.IsZero() {
+		ts := e.Time()
+		if !f.Since.IsZero() && ts.Before(f.Since) {
+			return false
+		}
+		if !f.Until.IsZero() && !ts.Before(f.Until) {
+			return false
+		}
+	}
+	return true
+}
+
+// Query returns the events in townRoot's log that match f, oldest first.
+// A missing log has no events; malformed lines are skipped.
+func Query(townRoot string, f Filter) ([]Event, error) {
+	file, err := os.Open(filepath.Join(townRoot, EventsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening events file: %w", err)
+	}
+	defer file.Close()
+
+	var matched []Event
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if !f.matches(e) {
+			continue
+		}
+		matched = append(matched, e)
+		if f.Limit > 0 && len(matched) > 2*f.Limit {
+			matched = append(matched[:0], matched[len(matched)-f.Limit:]...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading events file: %w", err)
+	}
+	if f.Limit > 0 && len(matched) > f.Limit {
+		matched = matched[len(matched)-f.Limit:]
+	}
+	return matched, nil
+}
+
+// Prune removes events older than maxAge from townRoot's log and returns
+// how many it removed. Lines without a readable timestamp are kept. The log
+// is replaced atomically while holding the writers' lock, so no concurrent
+// event is lost.
+func Prune(townRoot string, maxAge time.Duration) (int, error) {
+	if maxAge <= 0 {
+		return 0, nil
+	}
+	eventsPath := filepath.Join(townRoot, EventsFile)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	lock, err := lockFile(eventsPath)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	data, err := os.ReadFile(eventsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("reading events file: %w", err)
+	}
+
+	cutoff := time.Now().Add(-maxAge)
+	var kept bytes.Buffer
+	var anchor string // prev of the first kept line, if chained
+	removed := 0
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(line, &e); err == nil {
+			if ts := e.Time(); !ts.IsZero() && ts.Before(cutoff) {
+				removed++
+				continue
+			}
+		}
+		if kept.Len() == 0 {
+			anchor = e.Prev
+		}
+		kept.Write(line)
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+	if err := util.AtomicWriteFile(eventsPath, kept.Bytes(), 0644); err != nil {
+		return 0, fmt.Errorf("writing events file: %w", err)
+	}
+
+	// Record the cut so Verify accepts the chain's new start
+	if chained, _ := chainSettings(townRoot); chained {
+		pruned := Event{
+			Timestamp:  time.Now().UTC().Format(time.RFC3339),
+			Source:     "gt",
+			Type:       TypeEventsPruned,
+			Actor:      "gt",
+			Payload:    map[string]interface{}{"removed": removed, "anchor": anchor},
+			Visibility: VisibilityAudit,
+		}
+		if err := appendLocked(townRoot, eventsPath, pruned); err != nil {
+			return removed, err
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,122 @@
+package events
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeEvents writes evs to a fresh events log in a temp town root.
+func writeEvents(t *testing.T, evs ...Event) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	var b strings.Builder
+	for _, e := range evs {
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	b.WriteString("not json\n")
+	if err := os.WriteFile(filepath.Join(townRoot, EventsFile), []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return townRoot
+}
+
+func at(ago time.Duration) string {
+	return time.Now().Add(-ago).UTC().Format(time.RFC3339)
+}
+
+func TestQuery(t *testing.T) {
+	townRoot := writeEvents(t,
+		Event{Timestamp: at(72 * time.Hour), Type: TypeToolExec, Actor: "gastown/polecats/toast"},
+		Event{Timestamp: at(2 * time.Hour), Type: TypePromptSent, Actor: "mayor"},
+		Event{Timestamp: at(time.Hour), Type: TypeToolExec, Actor: "gastown/polecats/toast"},
+		Event{Timestamp: at(time.Minute), Type: TypeToolExec, Actor: "gastown/crew/max"},
+	)
+
+	tests := []struct {
+		name   string
+		filter Filter
+		actors []string
+	}{
+		{"all", Filter{}, []string{"gastown/polecats/toast", "mayor", "gastown/polecats/toast", "gastown/crew/max"}},
+		{"type and actor prefix", Filter{Types: []string{TypeToolExec}, Actor: "gastown/polecats/"}, []string{"gastown/polecats/toast", "gastown/polecats/toast"}},
+		{"window", Filter{Since: time.Now().Add(-3 * time.Hour), Until: time.Now().Add(-30 * time.Minute)}, []string{"mayor", "gastown/polecats/toast"}},
+		{"most recent", Filter{Limit: 1}, []string{"gastown/crew/max"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Query(townRoot, tt.filter)
+			if err != nil {
+				t.Fatalf("Query: %v", err)
+			}
+			var actors []string
+			for _, e := range got {
+				actors = append(actors, e.Actor)
+			}
+			if strings.Join(actors, ",") != strings.Join(tt.actors, ",") {
+				t.Errorf("Query = %v, want %v", actors, tt.actors)
+			}
+		})
+	}
+
+	if got, err := Query(t.TempDir(), Filter{}); err != nil || got != nil {
+		t.Errorf("Query with no log = %v, %v; want nothing", got, err)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	townRoot := writeEvents(t,
+		Event{Timestamp: at(40 * 24 * time.Hour), Type: TypeSling},
+		Event{Timestamp: at(time.Hour), Type: TypeMerged},
+	)
+
+	removed, err := Prune(townRoot, 30*24*time.Hour)
+	if err != nil || removed != 1 {
+		t.Fatalf("Prune = %d, %v; want 1 removed", removed, err)
+	}
+	data, err := os.ReadFile(filepath.Join(townRoot, EventsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), TypeSling) || !strings.Contains(string(data), TypeMerged) || !strings.Contains(string(data), "not json") {
+		t.Errorf("pruned log = %q, want the recent event and the unreadable line", data)
+	}
+	if removed, err := Prune(townRoot, 0); err != nil || removed != 0 {
+		t.Errorf("Prune with no retention = %d, %v; want a no-op", removed, err)
+	}
+}
+
+func TestPromptAndToolPayloads(t *testing.T) {
+	p := PromptPayload("gt-gastown-toast", "hello", false)
+	if p["sha256"] != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" || p["length"] != 5 {
+		t.Errorf("PromptPayload = %v", p)
+	}
+	if _, ok := p["body"]; ok {
+		t.Error("PromptPayload without body should not record the text")
+	}
+	if PromptPayload("s", "hello", true)["body"] != "hello" {
+		t.Error("PromptPayload with body should record the text")
+	}
+
+	big := strings.Repeat("x", 2*maxArgLen)
+	tool := ToolPayload("Write", map[string]interface{}{
+		"file_path": "main.go",
+		"content":   big,
+		"nested":    map[string]interface{}{"content": big},
+	}, "")
+	args := tool["args"].(map[string]interface{})
+	if args["file_path"] != "main.go" || len(args["content"].(string)) >= len(big) {
+		t.Errorf("ToolPayload args = %v, want long content truncated", args)
+	}
+	if nested := args["nested"].(map[string]interface{}); len(nested["content"].(string)) >= len(big) {
+		t.Error("ToolPayload should truncate nested strings")
+	}
+}
@@ -0,0 +1,80 @@
+package events
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Summarize returns a summary of a session from its tool_exec events: how
+// many tools it ran in how long, and which files it edited. It returns ""
+// if there are no tool_exec events.
+func Summarize(evs []Event) string {
+	counts := make(map[string]int)
+	edited := make(map[string]bool)
+	var first, last time.Time
+	calls := 0
+	for _, e := range evs {
+		if e.Type != TypeToolExec {
+			continue
+		}
+		calls++
+		tool, _ := e.Payload["tool"].(string)
+		counts[tool]++
+		if t := e.Time(); !t.IsZero() {
+			if first.IsZero() || t.Before(first) {
+				first = t
+			}
+			if t.After(last) {
+				last = t
+			}
+		}
+		if args, ok := e.Payload["args"].(map[string]interface{}); ok {
+			for _, k := range []string{"file_path", "notebook_path"} {
+				if p, _ := args[k].(string); p != "" && tool != "Read" {
+					edited[path.Base(p)] = true
+				}
+			}
+		}
+	}
+	if calls == 0 {
+		return ""
+	}
+
+	tools := make([]string, 0, len(counts))
+	for tool := range counts {
+		tools = append(tools, tool)
+	}
+	sort.Slice(tools, func(i, j int) bool {
+		if counts[tools[i]] != counts[tools[j]] {
+			return counts[tools[i]] > counts[tools[j]]
+		}
+		return tools[i] < tools[j]
+	})
+	var parts []string
+	for _, tool := range tools {
+		parts = append(parts, fmt.Sprintf("%s %d", tool, counts[tool]))
+	}
+	took := strings.TrimSuffix(last.Sub(first).Round(time.Minute).String(), "0s")
+	if took == "" {
+		took = "under a minute"
+	}
+	s := fmt.Sprintf("Session: %d tool calls in %s (%s).", calls, took, strings.Join(parts, ", "))
+
+	if len(edited) > 0 {
+		files := make([]string, 0, len(edited))
+		for name := range edited {
+			files = append(files, name)
+		}
+		sort.Strings(files)
+		more := ""
+		if len(files) > 10 {
+			more = fmt.Sprintf(", and %d more", len(files)-10)
+			files = files[:10]
+		}
+		s += "\nFiles edited: " + strings.Join(files, ", ") + more + "."
+	}
+	return s
+}
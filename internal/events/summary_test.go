@@ -0,0 +1,31 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	if got := Summarize(nil); got != "" {
+		t.Errorf("Summarize(nil) = %q, want empty", got)
+	}
+
+	start := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	tool := func(at time.Duration, name string, args map[string]interface{}) Event {
+		return Event{
+			Timestamp: start.Add(at).Format(time.RFC3339),
+			Type:      TypeToolExec,
+			Payload:   map[string]interface{}{"tool": name, "args": args},
+		}
+	}
+	evs := []Event{
+		tool(0, "Read", map[string]interface{}{"file_path": "/w/main.go"}),
+		tool(5*time.Minute, "Edit", map[string]interface{}{"file_path": "/w/main.go"}),
+		tool(20*time.Minute, "Bash", map[string]interface{}{"command": "go test ./..."}),
+		tool(72*time.Minute, "Edit", map[string]interface{}{"file_path": "/w/util.go"}),
+	}
+	want := "Session: 4 tool calls in 1h12m (Edit 2, Bash 1, Read 1).\nFiles edited: main.go, util.go."
+	if got := Summarize(evs); got != want {
+		t.Errorf("Summarize = %q, want %q", got, want)
+	}
+}
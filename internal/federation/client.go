@@ -0,0 +1,98 @@
+package federation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/telemetry"
+)
+
+// DefaultTimeout bounds each request to a remote town.
+const DefaultTimeout = 10 * time.Second
+
+// Client talks to a remote town's federation API.
+type Client struct {
+	// Name is the remote town's name in this town's federation config.
+	Name string
+
+	// BaseURL is the remote dashboard URL (e.g., "http://build-box:8080").
+	BaseURL string
+
+	// Token is sent as a bearer token when non-empty.
+	Token string
+
+	// HTTP is the client used for requests.
+	HTTP *http.Client
+}
+
+// NewClient creates a client for a remote town.
+func NewClient(name, baseURL, token string) *Client {
+	return &Client{
+		Name:    name,
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Token:   token,
+		HTTP:    &http.Client{Timeout: DefaultTimeout},
+	}
+}
+
+// Status fetches the remote town's status.
+func (c *Client) Status(ctx context.Context) (*TownStatus, error) {
+	var s TownStatus
+	if err := c.do(ctx, http.MethodGet, StatusPath, nil, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Dispatch asks the remote town to create and sling a task.
+func (c *Client) Dispatch(ctx context.Context, req DispatchRequest) (*DispatchResult, error) {
+	var res DispatchResult
+	if err := c.do(ctx, http.MethodPost, DispatchPath, req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encoding request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	telemetry.InjectHTTP(ctx, req.Header)
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("contacting %s: %w", c.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: %s: %s", c.Name, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response from %s: %w", c.Name, err)
+	}
+	return nil
+}
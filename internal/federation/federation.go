@@ -0,0 +1,172 @@
+// Package federation connects a central town to remote towns over HTTP.
+//
+// Each town's dashboard server (gt dashboard) exposes a small JSON API under
+// /api/federation/. A central town polls remote towns for their sessions,
+// health, and cost with a Client, aggregates the results, and can dispatch
+// new work to the least-loaded town.
+package federation
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// API paths served by Handler.
+const (
+	StatusPath   = "/api/federation/status"
+	DispatchPath = "/api/federation/dispatch"
+)
+
+// ErrDispatchDisabled is returned by a server that has no federation token
+// configured; dispatch requires authentication.
+var ErrDispatchDisabled = errors.New("dispatch disabled: no federation token configured")
+
+// TownStatus is a town's snapshot of sessions, health, and cost.
+type TownStatus struct {
+	// Town is the town name.
+	Town string `json:"town"`
+
+	// Sessions are the agent sessions running in the town.
+	Sessions []Session `json:"sessions"`
+
+	// Rigs are the town's registered rigs with their load.
+	Rigs []RigStatus `json:"rigs"`
+
+	// Workers is the number of running worker (polecat and crew) sessions.
+	Workers int `json:"workers"`
+
+	// MaxWorkers is the town's worker pool size (concurrency.max_sessions).
+	// Zero means unlimited.
+	MaxWorkers int `json:"max_workers,omitempty"`
+
+	// DeaconRunning reports whether the town's Deacon session is up.
+	DeaconRunning bool `json:"deacon_running"`
+
+	// CostTodayUSD is the town's spend so far today.
+	CostTodayUSD float64 `json:"cost_today_usd"`
+
+	// CollectedAt is when the snapshot was taken.
+	CollectedAt time.Time `json:"collected_at"`
+
+	// URL and Error are set by Gather: where the status came from and why
+	// it could not be fetched. An errored status has no other fields.
+	URL   string `json:"url,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Session is one agent session in a town.
+type Session struct {
+	Name  string `json:"name"`
+	Role  string `json:"role"`
+	Rig   string `json:"rig,omitempty"`
+	Agent string `json:"agent,omitempty"` // polecat or crew member name
+}
+
+// RigStatus is a rig's load and health.
+type RigStatus struct {
+	Name            string  `json:"name"`
+	Workers         int     `json:"workers"`
+	WitnessRunning  bool    `json:"witness_running"`
+	RefineryRunning bool    `json:"refinery_running"`
+	CostTodayUSD    float64 `json:"cost_today_usd"`
+}
+
+// Healthy reports whether the town's patrol agents are all running.
+func (s *TownStatus) Healthy() bool {
+	if s.Error != "" || !s.DeaconRunning {
+		return false
+	}
+	for _, r := range s.Rigs {
+		if !r.WitnessRunning || !r.RefineryRunning {
+			return false
+		}
+	}
+	return true
+}
+
+// Load returns the town's worker load: the fraction of the worker pool in
+// use, or the raw worker count when the pool is unlimited.
+func (s *TownStatus) Load() float64 {
+	if s.MaxWorkers > 0 {
+		return float64(s.Workers) / float64(s.MaxWorkers)
+	}
+	return float64(s.Workers)
+}
+
+// DispatchRequest asks a town to create a task and sling it to a rig.
+type DispatchRequest struct {
+	Rig         string `json:"rig"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Priority    int    `json:"priority,omitempty"`
+	From        string `json:"from,omitempty"` // dispatching town
+}
+
+// DispatchResult reports where dispatched work landed.
+type DispatchResult struct {
+	Town   string `json:"town"`
+	Rig    string `json:"rig"`
+	BeadID string `json:"bead_id"`
+}
+
+// Gather fetches status from every client concurrently. Towns that cannot
+// be reached are returned with Error set, so the result has one entry per
+// client in the same order.
+func Gather(ctx context.Context, clients []*Client) []*TownStatus {
+	statuses := make([]*TownStatus, len(clients))
+	var wg sync.WaitGroup
+	for i, c := range clients {
+		wg.Add(1)
+		go func(i int, c *Client) {
+			defer wg.Done()
+			s, err := c.Status(ctx)
+			if err != nil {
+				s = &TownStatus{Town: c.Name, Error: err.Error()}
+			}
+			s.URL = c.BaseURL
+			statuses[i] = s
+		}(i, c)
+	}
+	wg.Wait()
+	return statuses
+}
+
+// LeastLoaded picks the reachable town with the lowest load that has at
+// least one rig, and its rig with the fewest workers. Unhealthy towns are
+// only chosen if no healthy town is available. Ties go to the lower name.
+// Returns nils if no town can take work.
+func LeastLoaded(statuses []*TownStatus) (*TownStatus, *RigStatus) {
+	var candidates []*TownStatus
+	for _, s := range statuses {
+		if s != nil && s.Error == "" && len(s.Rigs) > 0 {
+			candidates = append(candidates, s)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Healthy() != b.Healthy() {
+			return a.Healthy()
+		}
+		if a.Load() != b.Load() {
+			return a.Load() < b.Load()
+		}
+		return a.Town < b.Town
+	})
+	town := candidates[0]
+
+	rigs := append([]RigStatus(nil), town.Rigs...)
+	sort.SliceStable(rigs, func(i, j int) bool {
+		if rigs[i].Workers != rigs[j].Workers {
+			return rigs[i].Workers < rigs[j].Workers
+		}
+		return rigs[i].Name < rigs[j].Name
+	})
+	return town, &rigs[0]
+}
@@ -0,0 +1,116 @@
+package federation
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientHandlerRoundTrip(t *testing.T) {
+	var got DispatchRequest
+	h := NewHandler(
+		func() (*TownStatus, error) {
+			return &TownStatus{Town: "west", Workers: 2, Rigs: []RigStatus{{Name: "gastown", Workers: 2}}}, nil
+		},
+		func(_ context.Context, req DispatchRequest) (*DispatchResult, error) {
+			got = req
+			return &DispatchResult{Town: "west", Rig: req.Rig, BeadID: "gt-abc"}, nil
+		},
+		"s3cret",
+	)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	ctx := context.Background()
+
+	c := NewClient("west", srv.URL+"/", "s3cret")
+	s, err := c.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if s.Town != "west" || s.Workers != 2 {
+		t.Errorf("Status = %+v", s)
+	}
+
+	res, err := c.Dispatch(ctx, DispatchRequest{Rig: "gastown", Title: "Fix it", From: "hq"})
+	if err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+	if res.BeadID != "gt-abc" || got.Title != "Fix it" || got.From != "hq" {
+		t.Errorf("Dispatch = %+v, request = %+v", res, got)
+	}
+
+	if _, err := NewClient("west", srv.URL, "wrong").Status(ctx); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Status with bad token error = %v, want 401", err)
+	}
+	if _, err := c.Dispatch(ctx, DispatchRequest{Rig: "gastown"}); err == nil {
+		t.Error("Dispatch without title succeeded")
+	}
+}
+
+func TestHandlerDispatchDisabledWithoutToken(t *testing.T) {
+	h := NewHandler(
+		func() (*TownStatus, error) { return &TownStatus{Town: "west"}, nil },
+		func(context.Context, DispatchRequest) (*DispatchResult, error) { return nil, errors.New("unreachable") },
+		"",
+	)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	c := NewClient("west", srv.URL, "")
+	if _, err := c.Status(context.Background()); err != nil {
+		t.Errorf("Status without token: %v", err)
+	}
+	_, err := c.Dispatch(context.Background(), DispatchRequest{Rig: "gastown", Title: "x"})
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Dispatch error = %v, want 403", err)
+	}
+}
+
+func TestGather(t *testing.T) {
+	h := NewHandler(func() (*TownStatus, error) { return &TownStatus{Town: "west"}, nil }, nil, "")
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	statuses := Gather(context.Background(), []*Client{
+		NewClient("west", srv.URL, ""),
+		NewClient("gone", "http://127.0.0.1:1", ""),
+	})
+	if len(statuses) != 2 {
+		t.Fatalf("Gather returned %d statuses, want 2", len(statuses))
+	}
+	if statuses[0].Error != "" || statuses[0].URL != srv.URL {
+		t.Errorf("west = %+v", statuses[0])
+	}
+	if statuses[1].Town != "gone" || statuses[1].Error == "" {
+		t.Errorf("gone = %+v, want error", statuses[1])
+	}
+}
+
+func TestLeastLoaded(t *testing.T) {
+	healthyRig := func(name string, workers int) RigStatus {
+		return RigStatus{Name: name, Workers: workers, WitnessRunning: true, RefineryRunning: true}
+	}
+	statuses := []*TownStatus{
+		{Town: "busy", DeaconRunning: true, Workers: 8, MaxWorkers: 10, Rigs: []RigStatus{healthyRig("a", 8)}},
+		{Town: "quiet", DeaconRunning: true, Workers: 3, MaxWorkers: 10, Rigs: []RigStatus{healthyRig("b", 2), healthyRig("c", 1)}},
+		{Town: "sick", DeaconRunning: false, Workers: 0, Rigs: []RigStatus{healthyRig("d", 0)}},
+		{Town: "down", Error: "connection refused"},
+		{Town: "empty", DeaconRunning: true},
+	}
+
+	town, rig := LeastLoaded(statuses)
+	if town == nil || town.Town != "quiet" || rig.Name != "c" {
+		t.Fatalf("LeastLoaded = %v/%v, want quiet/c", town, rig)
+	}
+
+	town, rig = LeastLoaded(statuses[2:])
+	if town == nil || town.Town != "sick" || rig.Name != "d" {
+		t.Errorf("LeastLoaded without healthy towns = %v/%v, want sick/d", town, rig)
+	}
+
+	if town, _ := LeastLoaded(statuses[3:]); town != nil {
+		t.Errorf("LeastLoaded = %v, want nil", town)
+	}
+}
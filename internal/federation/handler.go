@@ -0,0 +1,97 @@
+package federation
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxDispatchBody bounds the size of a dispatch request.
+const maxDispatchBody = 64 << 10
+
+// StatusFunc reports the local town's status.
+type StatusFunc func() (*TownStatus, error)
+
+// DispatchFunc creates and slings a task in the local town. ctx carries the
+// request's trace.
+type DispatchFunc func(ctx context.Context, req DispatchRequest) (*DispatchResult, error)
+
+// Handler serves the federation API for the local town.
+//
+// GET StatusPath returns the town status. POST DispatchPath creates a task
+// and slings it to the requested rig. When a token is configured every
+// request must carry it as a bearer token; without one, status is public
+// and dispatch is refused.
+type Handler struct {
+	status   StatusFunc
+	dispatch DispatchFunc
+	token    string
+}
+
+// NewHandler creates a federation API handler.
+func NewHandler(status StatusFunc, dispatch DispatchFunc, token string) *Handler {
+	return &Handler{status: status, dispatch: dispatch, token: token}
+}
+
+// ServeHTTP routes federation API requests.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.token != "" && !h.authorized(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.URL.Path {
+	case StatusPath:
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s, err := h.status()
+		if err != nil {
+			http.Error(w, "Failed to collect status", http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, s)
+
+	case DispatchPath:
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if h.token == "" {
+			http.Error(w, ErrDispatchDisabled.Error(), http.StatusForbidden)
+			return
+		}
+		var req DispatchRequest
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxDispatchBody)).Decode(&req); err != nil {
+			http.Error(w, "Invalid request", http.StatusBadRequest)
+			return
+		}
+		if req.Rig == "" || strings.TrimSpace(req.Title) == "" {
+			http.Error(w, "rig and title are required", http.StatusBadRequest)
+			return
+		}
+		res, err := h.dispatch(r.Context(), req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, res)
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) authorized(r *http.Request) bool {
+	got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(got), []byte(h.token)) == 1
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
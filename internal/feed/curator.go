@@ -103,12 +103,13 @@ func (c *Curator) Stop() {
 // ZFC: No in-memory state to clean up - state is derived from the events file.
 func (c *Curator) run(file *os.File) {
 	defer c.wg.Done()
-	defer file.Close()
+	defer func() { _ = file.Close() }()
 
 	reader := bufio.NewReader(file)
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
 
+	var last string
 	for {
 		select {
 		case <-c.ctx.Done():
@@ -122,9 +123,50 @@ func (c *Curator) run(file *os.File) {
 					break // No more data available
 				}
 				c.processLine(line)
+				last = line
+			}
+			// Follow the log when retention pruning replaces it
+			if f, r := c.reopenIfPruned(file, last); f != nil {
+				_ = file.Close()
+				file, reader = f, r
+			}
+		}
+	}
+}
+
+// reopenIfPruned returns the events file reopened just after last (the last
+// line processed) if events.Prune has replaced the open file, or nil if it
+// has not. Pruning keeps recent lines in order, so only lines appended since
+// are read again; if last is gone, reading resumes at the end.
+func (c *Curator) reopenIfPruned(file *os.File, last string) (*os.File, *bufio.Reader) {
+	current, err := os.Stat(filepath.Join(c.townRoot, events.EventsFile))
+	if err != nil {
+		return nil, nil
+	}
+	if open, err := file.Stat(); err != nil || os.SameFile(open, current) {
+		return nil, nil
+	}
+	f, err := os.Open(filepath.Join(c.townRoot, events.EventsFile))
+	if err != nil {
+		return nil, nil
+	}
+	reader := bufio.NewReader(f)
+	if last != "" {
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				break
+			}
+			if line == last {
+				return f, reader
 			}
 		}
 	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		_ = f.Close()
+		return nil, nil
+	}
+	return f, bufio.NewReader(f)
 }
 
 // processLine processes a single line from the events file.
@@ -376,6 +418,22 @@ func (c *Curator) generateSummary(event *events.Event) string {
 		}
 		return "Session terminated"
 
+	case events.TypeLoopDetected:
+		session, _ := event.Payload["session"].(string)
+		detail, _ := event.Payload["detail"].(string)
+		if session != "" && detail != "" {
+			return fmt.Sprintf("RUNAWAY LOOP: paused %s - %s", session, detail)
+		}
+		return "Runaway loop: session paused"
+
+	case events.TypeCostAnomaly:
+		rig, _ := event.Payload["rig"].(string)
+		detail, _ := event.Payload["detail"].(string)
+		if rig != "" && detail != "" {
+			return fmt.Sprintf("COST ANOMALY: %s - %s", rig, detail)
+		}
+		return "Cost anomaly: hourly spend spiked"
+
 	case events.TypeMassDeath:
 		count, _ := event.Payload["count"].(float64) // JSON numbers are float64
 		possibleCause, _ := event.Payload["possible_cause"].(string)
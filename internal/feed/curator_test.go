@@ -194,3 +194,38 @@ func TestCurator_GeneratesSummary(t *testing.T) {
 		}
 	}
 }
+
+func TestCurator_ReopenIfPruned(t *testing.T) {
+	tmpDir := t.TempDir()
+	eventsPath := filepath.Join(tmpDir, events.EventsFile)
+	if err := os.WriteFile(eventsPath, []byte("old\nkept\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(eventsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	c := NewCurator(tmpDir)
+	if f, _ := c.reopenIfPruned(file, "kept\n"); f != nil {
+		t.Fatal("reopened a file that was not replaced")
+	}
+
+	// Prune replaces the log, keeping recent lines; a new event follows
+	tmp := eventsPath + ".tmp"
+	if err := os.WriteFile(tmp, []byte("kept\nnew\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, eventsPath); err != nil {
+		t.Fatal(err)
+	}
+	f, reader := c.reopenIfPruned(file, "kept\n")
+	if f == nil {
+		t.Fatal("did not reopen the replaced file")
+	}
+	defer f.Close()
+	if line, _ := reader.ReadString('\n'); line != "new\n" {
+		t.Errorf("next line = %q, want the event appended after the last one processed", line)
+	}
+}
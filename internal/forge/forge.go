@@ -0,0 +1,303 @@
+// Package forge abstracts the code hosting service ("forge") behind a rig.
+//
+// Gas Town merges through its own Refinery, but rigs whose upstream lives on
+// a forge also open pull requests, wait on forge status checks, authenticate
+// clones, and receive webhooks. Each forge implements these the way its
+// tooling expects: GitHub through the gh CLI, GitLab through the glab CLI,
+// and Gitea (which has no standard CLI) through its REST API.
+//
+// The forge for a rig is selected by its settings (forge.type or
+// pull_requests.provider) or detected from the rig's git URL.
+package forge
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/secret"
+)
+
+// Supported forges.
+const (
+	GitHub = config.ForgeGitHub
+	GitLab = config.ForgeGitLab
+	Gitea  = config.ForgeGitea
+)
+
+// ErrUnknownForge indicates the forge could not be determined.
+var ErrUnknownForge = errors.New("unknown forge")
+
+// ErrWebhookSignature indicates a webhook failed signature verification.
+var ErrWebhookSignature = errors.New("invalid webhook signature")
+
+// ErrUnsupportedEvent indicates a webhook event type that is not handled
+// (e.g., GitHub's ping). Callers should acknowledge and ignore it.
+var ErrUnsupportedEvent = errors.New("unsupported webhook event")
+
+// Check states, normalized across forges.
+const (
+	CheckPass    = "pass"
+	CheckFail    = "fail"
+	CheckPending = "pending"
+	CheckSkipped = "skipped"
+)
+
+// Webhook event types, normalized across forges.
+const (
+	EventPush        = "push"
+	EventPullRequest = "pull_request"
+	EventChecks      = "checks"
+)
+
+// Forge is a code hosting service.
+type Forge interface {
+	// Name returns the forge identifier (github, gitlab, gitea).
+	Name() string
+
+	// CreatePR opens a pull request from workDir (a clone with the forge
+	// remote). If one is already open for the branch, it is returned with
+	// Existing set instead of failing.
+	CreatePR(workDir string, req PRRequest) (*PullRequest, error)
+
+	// FindPR returns the URL of the open pull request for branch, or "".
+	FindPR(workDir, branch string) (string, error)
+
+	// Checks returns the status checks reported for branch.
+	Checks(ctx context.Context, workDir, branch string) ([]Check, error)
+
+	// CredentialHelper returns a git credential helper that supplies the
+	// forge token from the environment, or "" if no token is configured.
+	// The helper references the environment variable, never the token itself,
+	// so it is safe to persist in git config.
+	CredentialHelper() string
+
+	// ParseWebhook verifies and parses a webhook delivery.
+	// If secret is empty, signature verification is skipped.
+	ParseWebhook(header http.Header, body []byte, secret string) (*Event, error)
+}
+
+// PRRequest describes a pull request to open.
+type PRRequest struct {
+	Branch    string // source branch (already pushed)
+	Base      string // target branch
+	Title     string
+	Body      string
+	Draft     bool
+	Labels    []string
+	Reviewers []string
+}
+
+// PullRequest is an opened (or already existing) pull request.
+type PullRequest struct {
+	URL      string
+	Forge    string
+	Existing bool // true if the PR was already open for the branch
+}
+
+// Check is one status check on a branch.
+type Check struct {
+	Name  string
+	State string // CheckPass, CheckFail, CheckPending, CheckSkipped
+	Link  string
+}
+
+// Event is a normalized webhook event.
+type Event struct {
+	Forge  string `json:"forge"`
+	Type   string `json:"type"`             // EventPush, EventPullRequest, EventChecks
+	Repo   string `json:"repo"`             // owner/name
+	Branch string `json:"branch,omitempty"` // source branch
+	Action string `json:"action,omitempty"` // e.g., opened, closed, merged
+	State  string `json:"state,omitempty"`  // check state for EventChecks
+	URL    string `json:"url,omitempty"`    // pull request or check URL
+}
+
+// Options configure a forge client.
+type Options struct {
+	// BaseURL is the forge's web URL (e.g., https://git.example.com).
+	// If empty, it is derived from the remote URL.
+	BaseURL string
+
+	// TokenEnv names the environment variable holding the API token, which
+	// may instead be stored with gt secret set under the same name.
+	// If empty, the forge's conventional variable is used.
+	TokenEnv string
+
+	// RemoteURL is the rig's git URL, used to locate the repository.
+	RemoteURL string
+}
+
+// Detect infers the forge from a remote URL by host name.
+// Returns "" if the host is not recognized (self-hosted forges on other
+// host names must be configured with forge.type).
+func Detect(remoteURL string) string {
+	host := strings.ToLower(hostOf(remoteURL))
+	switch {
+	case strings.Contains(host, "github"):
+		return GitHub
+	case strings.Contains(host, "gitlab"):
+		return GitLab
+	case strings.Contains(host, "gitea"), strings.Contains(host, "codeberg"), strings.Contains(host, "forgejo"):
+		return Gitea
+	default:
+		return ""
+	}
+}
+
+// New returns a client for the named forge.
+func New(name string, opts Options) (Forge, error) {
+	switch name {
+	case GitHub:
+		return newGitHub(opts), nil
+	case GitLab:
+		return newGitLab(opts), nil
+	case Gitea:
+		return newGitea(opts)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownForge, name)
+	}
+}
+
+// Resolve returns the forge for a rig. The forge is chosen by forge.type,
+// then pull_requests.provider, then detection from remoteURL.
+func Resolve(settings *config.RigSettings, remoteURL string) (Forge, error) {
+	opts := Options{RemoteURL: remoteURL}
+	name := ""
+	if settings != nil {
+		if settings.Forge != nil {
+			name = settings.Forge.Type
+			baseURL, err := config.ResolveRef("forge.url", settings.Forge.URL)
+			if err != nil {
+				return nil, err
+			}
+			opts.BaseURL = baseURL
+			opts.TokenEnv = settings.Forge.TokenEnv
+		}
+		if name == "" && settings.PullRequests != nil {
+			name = settings.PullRequests.Provider
+		}
+	}
+	if name == "" {
+		name = Detect(remoteURL)
+	}
+	if name == "" {
+		return nil, fmt.Errorf("%w: cannot detect forge from %q; set forge.type in rig settings", ErrUnknownForge, remoteURL)
+	}
+	return New(name, opts)
+}
+
+// tokenEnv returns the configured token variable, or the default.
+func tokenEnv(opts Options, def string) string {
+	if opts.TokenEnv != "" {
+		return opts.TokenEnv
+	}
+	return def
+}
+
+// credentialHelper builds a git credential helper that answers "get" requests
+// with the given username and the token read from envVar at call time, falling
+// back to the keyring secret of the same name (via gt secret get).
+// Returns "" if the token is in neither place.
+func credentialHelper(envVar, username, password string) string {
+	if os.Getenv(envVar) == "" && !secret.InKeyring(envVar) {
+		return ""
+	}
+	return fmt.Sprintf(`!f() { test "$1" = get && %s="${%s:-$(gt secret get %s 2>/dev/null)}" && test -n "$%s" && echo "username=%s" && echo "password=%s"; }; f`,
+		envVar, envVar, envVar, envVar, username, password)
+}
+
+// createWithCLI opens a pull request by running a forge CLI, returning the
+// existing pull request for the branch if there is one.
+func createWithCLI(f Forge, workDir string, req PRRequest, args []string) (*PullRequest, error) {
+	if u, err := f.FindPR(workDir, req.Branch); err == nil && u != "" {
+		return &PullRequest{URL: u, Forge: f.Name(), Existing: true}, nil
+	}
+
+	out, err := runCLI(context.Background(), workDir, args)
+	if err != nil {
+		return nil, err
+	}
+
+	u := extractURL(out)
+	if u == "" {
+		return nil, fmt.Errorf("%s did not report a pull request URL: %s", args[0], strings.TrimSpace(out))
+	}
+	return &PullRequest{URL: u, Forge: f.Name()}, nil
+}
+
+// runCLI executes a forge CLI command and returns its stdout.
+func runCLI(ctx context.Context, workDir string, args []string) (string, error) {
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...) //nolint:gosec // G204: args are built from config and branch names
+	cmd.Dir = workDir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		name := strings.Join(args[:min(3, len(args))], " ")
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return stdout.String(), fmt.Errorf("%s: %s", name, msg)
+		}
+		return stdout.String(), fmt.Errorf("%s: %w", name, err)
+	}
+	return stdout.String(), nil
+}
+
+// extractURL returns the last http(s) URL found in CLI output.
+func extractURL(out string) string {
+	var u string
+	for _, field := range strings.Fields(out) {
+		if strings.HasPrefix(field, "https://") || strings.HasPrefix(field, "http://") {
+			u = field
+		}
+	}
+	return u
+}
+
+// hostOf returns the host of a git remote URL (https, ssh://, or scp-like).
+func hostOf(remoteURL string) string {
+	if u, err := url.Parse(remoteURL); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+	// scp-like: git@host:owner/repo.git
+	if at := strings.Index(remoteURL, "@"); at >= 0 {
+		rest := remoteURL[at+1:]
+		if colon := strings.Index(rest, ":"); colon >= 0 {
+			return rest[:colon]
+		}
+	}
+	return ""
+}
+
+// webBaseURL returns the forge web URL for a remote: the scheme and host of
+// an http(s) remote, or https://host for SSH remotes.
+func webBaseURL(remoteURL string) string {
+	if u, err := url.Parse(remoteURL); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		return u.Scheme + "://" + u.Host
+	}
+	if host := hostOf(remoteURL); host != "" {
+		return "https://" + host
+	}
+	return ""
+}
+
+// repoPath returns the owner/name path of a git remote URL.
+func repoPath(remoteURL string) string {
+	var p string
+	if u, err := url.Parse(remoteURL); err == nil && u.Host != "" {
+		p = u.Path
+	} else if colon := strings.Index(remoteURL, ":"); colon >= 0 {
+		p = remoteURL[colon+1:]
+	}
+	p = strings.Trim(p, "/")
+	return strings.TrimSuffix(p, ".git")
+}
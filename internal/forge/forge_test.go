@@ -0,0 +1,133 @@
+package forge
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"git@github.com:steveyegge/gastown.git", GitHub},
+		{"https://github.com/steveyegge/gastown", GitHub},
+		{"https://gitlab.example.com/team/repo.git", GitLab},
+		{"git@gitlab.com:team/repo.git", GitLab},
+		{"https://gitea.example.com/team/repo.git", Gitea},
+		{"ssh://git@codeberg.org/team/repo.git", Gitea},
+		{"https://git.example.com/team/repo.git", ""},
+		{"/tmp/bare.git", ""},
+	}
+	for _, tt := range tests {
+		if got := Detect(tt.url); got != tt.want {
+			t.Errorf("Detect(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestResolvePrecedence(t *testing.T) {
+	remote := "git@github.com:o/r.git"
+
+	f, err := Resolve(nil, remote)
+	if err != nil || f.Name() != GitHub {
+		t.Fatalf("Resolve(nil) = %v, %v; want github", f, err)
+	}
+
+	settings := &config.RigSettings{PullRequests: &config.PullRequestConfig{Provider: config.PRProviderGitLab}}
+	if f, _ := Resolve(settings, remote); f.Name() != GitLab {
+		t.Errorf("pull_requests.provider should override detection, got %s", f.Name())
+	}
+
+	settings.Forge = &config.ForgeConfig{Type: config.ForgeGitea}
+	if f, _ := Resolve(settings, remote); f.Name() != Gitea {
+		t.Errorf("forge.type should take precedence, got %s", f.Name())
+	}
+
+	if _, err := Resolve(nil, "https://git.example.com/o/r.git"); !errors.Is(err, ErrUnknownForge) {
+		t.Errorf("Resolve(unrecognized) error = %v, want ErrUnknownForge", err)
+	}
+
+	settings.Forge.URL = "${GT_TEST_UNSET_FORGE_URL}"
+	if _, err := Resolve(settings, remote); !errors.Is(err, config.ErrUnresolvedRef) {
+		t.Errorf("Resolve(unset forge.url) error = %v, want ErrUnresolvedRef", err)
+	}
+}
+
+func TestCreateArgs(t *testing.T) {
+	req := PRRequest{
+		Branch:    "polecat/Nux/gt-abc",
+		Base:      "main",
+		Title:     "Fix it",
+		Body:      "body",
+		Draft:     true,
+		Labels:    []string{"gastown", "bot"},
+		Reviewers: []string{"alice"},
+	}
+
+	args := newGitHub(Options{}).createArgs(req)
+	want := []string{"gh", "pr", "create", "--head", "polecat/Nux/gt-abc", "--base", "main",
+		"--title", "Fix it", "--body", "body", "--draft",
+		"--label", "gastown", "--label", "bot", "--reviewer", "alice"}
+	if !slices.Equal(args, want) {
+		t.Errorf("github args = %v, want %v", args, want)
+	}
+
+	args = newGitLab(Options{}).createArgs(req)
+	want = []string{"glab", "mr", "create", "--source-branch", "polecat/Nux/gt-abc", "--target-branch", "main",
+		"--title", "Fix it", "--description", "body", "--yes", "--draft",
+		"--label", "gastown,bot", "--reviewer", "alice"}
+	if !slices.Equal(args, want) {
+		t.Errorf("gitlab args = %v, want %v", args, want)
+	}
+}
+
+func TestExtractURL(t *testing.T) {
+	out := "Creating pull request for polecat/Nux/gt-abc into main\n\nhttps://github.com/o/r/pull/7\n"
+	if got := extractURL(out); got != "https://github.com/o/r/pull/7" {
+		t.Errorf("extractURL = %q", got)
+	}
+	if got := extractURL("no url here"); got != "" {
+		t.Errorf("extractURL = %q, want empty", got)
+	}
+}
+
+func TestRemoteParsing(t *testing.T) {
+	tests := []struct {
+		url, base, path string
+	}{
+		{"https://git.example.com/team/repo.git", "https://git.example.com", "team/repo"},
+		{"http://localhost:3000/team/repo", "http://localhost:3000", "team/repo"},
+		{"git@git.example.com:team/repo.git", "https://git.example.com", "team/repo"},
+		{"ssh://git@git.example.com/team/repo.git", "https://git.example.com", "team/repo"},
+	}
+	for _, tt := range tests {
+		if got := webBaseURL(tt.url); got != tt.base {
+			t.Errorf("webBaseURL(%q) = %q, want %q", tt.url, got, tt.base)
+		}
+		if got := repoPath(tt.url); got != tt.path {
+			t.Errorf("repoPath(%q) = %q, want %q", tt.url, got, tt.path)
+		}
+	}
+}
+
+func TestCredentialHelper(t *testing.T) {
+	t.Setenv("GT_TEST_FORGE_TOKEN", "")
+	f := newGitLab(Options{TokenEnv: "GT_TEST_FORGE_TOKEN"})
+	if h := f.CredentialHelper(); h != "" {
+		t.Errorf("CredentialHelper without token = %q, want empty", h)
+	}
+
+	t.Setenv("GT_TEST_FORGE_TOKEN", "s3cret")
+	h := f.CredentialHelper()
+	if !strings.Contains(h, "$GT_TEST_FORGE_TOKEN") || !strings.Contains(h, "username=oauth2") {
+		t.Errorf("CredentialHelper = %q, want oauth2 helper reading the env var", h)
+	}
+	if strings.Contains(h, "s3cret") {
+		t.Error("CredentialHelper must not embed the token")
+	}
+}
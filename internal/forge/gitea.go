@@ -0,0 +1,220 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/secret"
+)
+
+// giteaTimeout bounds a single Gitea API request.
+const giteaTimeout = 30 * time.Second
+
+// gitea talks to the Gitea (or Forgejo) REST API directly: there is no
+// standard CLI, so the repository is located from the remote URL.
+type gitea struct {
+	baseURL  string // e.g., https://git.example.com
+	owner    string
+	repo     string
+	tokenEnv string
+	client   *http.Client
+}
+
+func newGitea(opts Options) (*gitea, error) {
+	parts := strings.Split(repoPath(opts.RemoteURL), "/")
+	if len(parts) < 2 || parts[len(parts)-2] == "" || parts[len(parts)-1] == "" {
+		return nil, fmt.Errorf("cannot determine gitea repository from %q", opts.RemoteURL)
+	}
+
+	base := strings.TrimSuffix(opts.BaseURL, "/")
+	if base == "" {
+		base = webBaseURL(opts.RemoteURL)
+		if base == "" {
+			return nil, fmt.Errorf("cannot determine gitea host from %q; set forge.url in rig settings", opts.RemoteURL)
+		}
+	}
+
+	return &gitea{
+		baseURL:  base,
+		owner:    parts[len(parts)-2],
+		repo:     parts[len(parts)-1],
+		tokenEnv: tokenEnv(opts, "GITEA_TOKEN"),
+		client:   &http.Client{Timeout: giteaTimeout},
+	}, nil
+}
+
+func (g *gitea) Name() string { return Gitea }
+
+// api performs a request against the repository API and decodes the response.
+func (g *gitea) api(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v1/repos/%s/%s%s", g.baseURL,
+		url.PathEscape(g.owner), url.PathEscape(g.repo), path)
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token := secret.Lookup(g.tokenEnv); token != "" {
+		req.Header.Set("Authorization", "token "+token)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("gitea %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("gitea %s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("gitea %s %s: decoding response: %w", method, path, err)
+	}
+	return nil
+}
+
+// giteaPull is the subset of a Gitea pull request used here.
+type giteaPull struct {
+	Number  int64  `json:"number"`
+	HTMLURL string `json:"html_url"`
+	Head    struct {
+		Ref string `json:"ref"`
+	} `json:"head"`
+}
+
+func (g *gitea) CreatePR(workDir string, req PRRequest) (*PullRequest, error) {
+	if u, err := g.FindPR(workDir, req.Branch); err == nil && u != "" {
+		return &PullRequest{URL: u, Forge: Gitea, Existing: true}, nil
+	}
+
+	ctx := context.Background()
+
+	// Gitea has no draft flag; a "WIP:" title prefix marks work in progress.
+	title := req.Title
+	if req.Draft {
+		title = "WIP: " + title
+	}
+
+	labelIDs, err := g.labelIDs(ctx, req.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	in := map[string]interface{}{
+		"head":  req.Branch,
+		"base":  req.Base,
+		"title": title,
+		"body":  req.Body,
+	}
+	if len(labelIDs) > 0 {
+		in["labels"] = labelIDs
+	}
+
+	var pull giteaPull
+	if err := g.api(ctx, http.MethodPost, "/pulls", in, &pull); err != nil {
+		return nil, err
+	}
+
+	if len(req.Reviewers) > 0 {
+		path := fmt.Sprintf("/pulls/%d/requested_reviewers", pull.Number)
+		if err := g.api(ctx, http.MethodPost, path, map[string][]string{"reviewers": req.Reviewers}, nil); err != nil {
+			return &PullRequest{URL: pull.HTMLURL, Forge: Gitea}, fmt.Errorf("requesting reviewers: %w", err)
+		}
+	}
+	return &PullRequest{URL: pull.HTMLURL, Forge: Gitea}, nil
+}
+
+// labelIDs resolves label names to the repository's label IDs, which the
+// Gitea API requires. Unknown labels are skipped.
+func (g *gitea) labelIDs(ctx context.Context, names []string) ([]int64, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	var labels []struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := g.api(ctx, http.MethodGet, "/labels?limit=100", nil, &labels); err != nil {
+		return nil, fmt.Errorf("listing labels: %w", err)
+	}
+	byName := make(map[string]int64, len(labels))
+	for _, l := range labels {
+		byName[l.Name] = l.ID
+	}
+	var ids []int64
+	for _, n := range names {
+		if id, ok := byName[n]; ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (g *gitea) FindPR(_ string, branch string) (string, error) {
+	var pulls []giteaPull
+	if err := g.api(context.Background(), http.MethodGet, "/pulls?state=open&limit=50", nil, &pulls); err != nil {
+		return "", err
+	}
+	for _, p := range pulls {
+		if p.Head.Ref == branch {
+			return p.HTMLURL, nil
+		}
+	}
+	return "", nil
+}
+
+// Checks reports the commit statuses on the branch head.
+func (g *gitea) Checks(ctx context.Context, _ string, branch string) ([]Check, error) {
+	var combined struct {
+		Statuses []struct {
+			Context   string `json:"context"`
+			Status    string `json:"status"`
+			TargetURL string `json:"target_url"`
+		} `json:"statuses"`
+	}
+	if err := g.api(ctx, http.MethodGet, "/commits/"+url.PathEscape(branch)+"/status", nil, &combined); err != nil {
+		return nil, err
+	}
+
+	checks := make([]Check, 0, len(combined.Statuses))
+	for _, s := range combined.Statuses {
+		checks = append(checks, Check{Name: s.Context, State: commitStatusState(s.Status), Link: s.TargetURL})
+	}
+	return checks, nil
+}
+
+func (g *gitea) CredentialHelper() string {
+	return credentialHelper(g.tokenEnv, "$"+g.tokenEnv, "x-oauth-basic")
+}
+
+// ParseWebhook verifies X-Gitea-Signature and parses push, pull_request, and
+// status deliveries (Gitea uses GitHub's payload format).
+func (g *gitea) ParseWebhook(header http.Header, body []byte, secret string) (*Event, error) {
+	if secret != "" && !validHMAC(body, secret, header.Get("X-Gitea-Signature")) {
+		return nil, ErrWebhookSignature
+	}
+	return parseGitHubStyleEvent(Gitea, header.Get("X-Gitea-Event"), body)
+}
@@ -0,0 +1,118 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeGitea serves the subset of the Gitea API used by the client.
+func fakeGitea(t *testing.T, pulls *[]giteaPull) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/repos/team/repo/pulls", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "token tok" {
+			t.Errorf("Authorization = %q, want token header", got)
+		}
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(*pulls)
+		case http.MethodPost:
+			var in struct {
+				Head   string  `json:"head"`
+				Title  string  `json:"title"`
+				Labels []int64 `json:"labels"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&in)
+			if in.Title != "WIP: Fix it" {
+				t.Errorf("title = %q, want draft prefix", in.Title)
+			}
+			if len(in.Labels) != 1 || in.Labels[0] != 7 {
+				t.Errorf("labels = %v, want [7]", in.Labels)
+			}
+			p := giteaPull{Number: 3, HTMLURL: "https://gitea.test/team/repo/pulls/3"}
+			p.Head.Ref = in.Head
+			*pulls = append(*pulls, p)
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(p)
+		}
+	})
+	mux.HandleFunc("/api/v1/repos/team/repo/labels", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"id":7,"name":"gastown"},{"id":8,"name":"other"}]`))
+	})
+	mux.HandleFunc("/api/v1/repos/team/repo/pulls/3/requested_reviewers", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("/api/v1/repos/team/repo/commits/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v1/repos/team/repo/commits/polecat%2FNux%2Fgt-abc/status" {
+			t.Errorf("status path = %q", r.URL.EscapedPath())
+		}
+		_, _ = w.Write([]byte(`{"state":"pending","statuses":[
+			{"context":"build","status":"success","target_url":"https://ci/1"},
+			{"context":"lint","status":"pending"}]}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGiteaCreatePR(t *testing.T) {
+	t.Setenv("GITEA_TOKEN", "tok")
+	var pulls []giteaPull
+	srv := fakeGitea(t, &pulls)
+
+	f, err := New(Gitea, Options{BaseURL: srv.URL, RemoteURL: "git@gitea.test:team/repo.git"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	req := PRRequest{
+		Branch:    "polecat/Nux/gt-abc",
+		Base:      "main",
+		Title:     "Fix it",
+		Draft:     true,
+		Labels:    []string{"gastown", "missing"},
+		Reviewers: []string{"alice"},
+	}
+	pull, err := f.CreatePR("", req)
+	if err != nil {
+		t.Fatalf("CreatePR: %v", err)
+	}
+	if pull.Existing || pull.URL != "https://gitea.test/team/repo/pulls/3" {
+		t.Errorf("CreatePR = %+v, want new PR", pull)
+	}
+
+	again, err := f.CreatePR("", req)
+	if err != nil {
+		t.Fatalf("CreatePR again: %v", err)
+	}
+	if !again.Existing || again.URL != pull.URL {
+		t.Errorf("second CreatePR = %+v, want existing PR", again)
+	}
+}
+
+func TestGiteaChecks(t *testing.T) {
+	t.Setenv("GITEA_TOKEN", "tok")
+	var pulls []giteaPull
+	srv := fakeGitea(t, &pulls)
+
+	f, err := New(Gitea, Options{BaseURL: srv.URL, RemoteURL: "https://gitea.test/team/repo.git"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	checks, err := f.Checks(context.Background(), "", "polecat/Nux/gt-abc")
+	if err != nil {
+		t.Fatalf("Checks: %v", err)
+	}
+	if len(checks) != 2 || checks[0].State != CheckPass || checks[0].Link != "https://ci/1" || checks[1].State != CheckPending {
+		t.Errorf("Checks = %+v", checks)
+	}
+}
+
+func TestNewGiteaRequiresRepository(t *testing.T) {
+	if _, err := New(Gitea, Options{RemoteURL: "/tmp/bare.git"}); err == nil {
+		t.Error("New(gitea) with a local path should fail")
+	}
+}
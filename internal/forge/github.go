@@ -0,0 +1,108 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// gitHub talks to GitHub through the gh CLI, which handles authentication.
+type gitHub struct {
+	tokenEnv string
+}
+
+func newGitHub(opts Options) *gitHub {
+	return &gitHub{tokenEnv: tokenEnv(opts, "GITHUB_TOKEN")}
+}
+
+func (g *gitHub) Name() string { return GitHub }
+
+// createArgs returns the gh invocation that opens the pull request.
+func (g *gitHub) createArgs(req PRRequest) []string {
+	args := []string{"gh", "pr", "create",
+		"--head", req.Branch,
+		"--base", req.Base,
+		"--title", req.Title,
+		"--body", req.Body,
+	}
+	if req.Draft {
+		args = append(args, "--draft")
+	}
+	for _, l := range req.Labels {
+		args = append(args, "--label", l)
+	}
+	for _, r := range req.Reviewers {
+		args = append(args, "--reviewer", r)
+	}
+	return args
+}
+
+func (g *gitHub) CreatePR(workDir string, req PRRequest) (*PullRequest, error) {
+	return createWithCLI(g, workDir, req, g.createArgs(req))
+}
+
+func (g *gitHub) FindPR(workDir, branch string) (string, error) {
+	out, err := runCLI(context.Background(), workDir, []string{"gh", "pr", "view", branch, "--json", "url", "--jq", ".url"})
+	if err != nil {
+		return "", err
+	}
+	return extractURL(out), nil
+}
+
+// ghCheck is one entry of `gh pr checks --json name,bucket,link`.
+type ghCheck struct {
+	Name   string `json:"name"`
+	Bucket string `json:"bucket"` // pass, fail, pending, skipping, cancel
+	Link   string `json:"link"`
+}
+
+func (g *gitHub) Checks(ctx context.Context, workDir, branch string) ([]Check, error) {
+	// gh exits non-zero when checks are failing or pending; the JSON is still valid.
+	out, runErr := runCLI(ctx, workDir, []string{"gh", "pr", "checks", branch, "--json", "name,bucket,link"})
+	var raw []ghCheck
+	if err := json.Unmarshal(bytes.TrimSpace([]byte(out)), &raw); err != nil {
+		if runErr != nil {
+			return nil, runErr
+		}
+		return nil, fmt.Errorf("parsing gh pr checks: %w", err)
+	}
+
+	checks := make([]Check, 0, len(raw))
+	for _, c := range raw {
+		checks = append(checks, Check{Name: c.Name, State: ghBucketState(c.Bucket), Link: c.Link})
+	}
+	return checks, nil
+}
+
+// ghBucketState maps a gh check bucket to a normalized check state.
+func ghBucketState(bucket string) string {
+	switch bucket {
+	case "pass":
+		return CheckPass
+	case "fail", "cancel":
+		return CheckFail
+	case "skipping":
+		return CheckSkipped
+	default:
+		return CheckPending
+	}
+}
+
+func (g *gitHub) CredentialHelper() string {
+	return credentialHelper(g.tokenEnv, "x-access-token", "$"+g.tokenEnv)
+}
+
+// ParseWebhook verifies X-Hub-Signature-256 and parses push, pull_request,
+// check_suite, and check_run deliveries.
+func (g *gitHub) ParseWebhook(header http.Header, body []byte, secret string) (*Event, error) {
+	if secret != "" {
+		sig := strings.TrimPrefix(header.Get("X-Hub-Signature-256"), "sha256=")
+		if !validHMAC(body, secret, sig) {
+			return nil, ErrWebhookSignature
+		}
+	}
+	return parseGitHubStyleEvent(GitHub, header.Get("X-GitHub-Event"), body)
+}
@@ -0,0 +1,163 @@
+package forge
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// gitLab talks to GitLab through the glab CLI, which handles authentication
+// and resolves the project from the working directory's remote.
+type gitLab struct {
+	tokenEnv string
+}
+
+func newGitLab(opts Options) *gitLab {
+	return &gitLab{tokenEnv: tokenEnv(opts, "GITLAB_TOKEN")}
+}
+
+func (g *gitLab) Name() string { return GitLab }
+
+// createArgs returns the glab invocation that opens the merge request.
+func (g *gitLab) createArgs(req PRRequest) []string {
+	args := []string{"glab", "mr", "create",
+		"--source-branch", req.Branch,
+		"--target-branch", req.Base,
+		"--title", req.Title,
+		"--description", req.Body,
+		"--yes",
+	}
+	if req.Draft {
+		args = append(args, "--draft")
+	}
+	if len(req.Labels) > 0 {
+		args = append(args, "--label", strings.Join(req.Labels, ","))
+	}
+	if len(req.Reviewers) > 0 {
+		args = append(args, "--reviewer", strings.Join(req.Reviewers, ","))
+	}
+	return args
+}
+
+func (g *gitLab) CreatePR(workDir string, req PRRequest) (*PullRequest, error) {
+	return createWithCLI(g, workDir, req, g.createArgs(req))
+}
+
+func (g *gitLab) FindPR(workDir, branch string) (string, error) {
+	out, err := runCLI(context.Background(), workDir, []string{"glab", "mr", "view", branch})
+	if err != nil {
+		return "", err
+	}
+	return extractURL(out), nil
+}
+
+// glJob is one job of a GitLab pipeline.
+type glJob struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	WebURL string `json:"web_url"`
+}
+
+// Checks reports the jobs of the latest pipeline for branch.
+func (g *gitLab) Checks(ctx context.Context, workDir, branch string) ([]Check, error) {
+	out, err := runCLI(ctx, workDir, []string{"glab", "api",
+		"projects/:id/pipelines/latest?ref=" + url.QueryEscape(branch)})
+	if err != nil {
+		return nil, err
+	}
+	var pipeline struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(out), &pipeline); err != nil {
+		return nil, fmt.Errorf("parsing pipeline: %w", err)
+	}
+	if pipeline.ID == 0 {
+		return nil, nil // no pipeline yet
+	}
+
+	out, err = runCLI(ctx, workDir, []string{"glab", "api",
+		fmt.Sprintf("projects/:id/pipelines/%d/jobs?per_page=100", pipeline.ID)})
+	if err != nil {
+		return nil, err
+	}
+	var jobs []glJob
+	if err := json.Unmarshal([]byte(out), &jobs); err != nil {
+		return nil, fmt.Errorf("parsing pipeline jobs: %w", err)
+	}
+
+	checks := make([]Check, 0, len(jobs))
+	for _, j := range jobs {
+		checks = append(checks, Check{Name: j.Name, State: glStatusState(j.Status), Link: j.WebURL})
+	}
+	return checks, nil
+}
+
+// glStatusState maps a GitLab pipeline or job status to a check state.
+func glStatusState(status string) string {
+	switch status {
+	case "success":
+		return CheckPass
+	case "failed", "canceled":
+		return CheckFail
+	case "skipped", "manual":
+		return CheckSkipped
+	default:
+		return CheckPending
+	}
+}
+
+func (g *gitLab) CredentialHelper() string {
+	return credentialHelper(g.tokenEnv, "oauth2", "$"+g.tokenEnv)
+}
+
+// ParseWebhook verifies X-Gitlab-Token and parses push, merge request, and
+// pipeline hooks.
+func (g *gitLab) ParseWebhook(header http.Header, body []byte, secret string) (*Event, error) {
+	if secret != "" {
+		token := header.Get("X-Gitlab-Token")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			return nil, ErrWebhookSignature
+		}
+	}
+
+	var p struct {
+		Ref     string `json:"ref"`
+		Project struct {
+			PathWithNamespace string `json:"path_with_namespace"`
+		} `json:"project"`
+		ObjectAttributes struct {
+			Ref          string `json:"ref"`
+			SourceBranch string `json:"source_branch"`
+			Action       string `json:"action"`
+			Status       string `json:"status"`
+			URL          string `json:"url"`
+		} `json:"object_attributes"`
+	}
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("parsing gitlab webhook: %w", err)
+	}
+
+	ev := &Event{Forge: GitLab, Repo: p.Project.PathWithNamespace}
+	switch kind := header.Get("X-Gitlab-Event"); kind {
+	case "Push Hook":
+		ev.Type = EventPush
+		ev.Branch = strings.TrimPrefix(p.Ref, "refs/heads/")
+	case "Merge Request Hook":
+		ev.Type = EventPullRequest
+		ev.Branch = p.ObjectAttributes.SourceBranch
+		ev.Action = p.ObjectAttributes.Action
+		ev.URL = p.ObjectAttributes.URL
+	case "Pipeline Hook":
+		ev.Type = EventChecks
+		ev.Branch = p.ObjectAttributes.Ref
+		ev.State = glStatusState(p.ObjectAttributes.Status)
+		ev.URL = p.ObjectAttributes.URL
+	default:
+		return nil, fmt.Errorf("%w: gitlab %q", ErrUnsupportedEvent, kind)
+	}
+	return ev, nil
+}
@@ -0,0 +1,116 @@
+package forge
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// validHMAC reports whether sigHex is the hex HMAC-SHA256 of body under secret.
+func validHMAC(body []byte, secret, sigHex string) bool {
+	sig, err := hex.DecodeString(sigHex)
+	if err != nil || len(sig) == 0 {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(sig, mac.Sum(nil))
+}
+
+// githubStylePayload covers the webhook fields used by GitHub and Gitea,
+// which share a payload format.
+type githubStylePayload struct {
+	Ref        string `json:"ref"`
+	Action     string `json:"action"`
+	State      string `json:"state"` // status events
+	TargetURL  string `json:"target_url"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	PullRequest struct {
+		HTMLURL string `json:"html_url"`
+		Merged  bool   `json:"merged"`
+		Head    struct {
+			Ref string `json:"ref"`
+		} `json:"head"`
+	} `json:"pull_request"`
+	CheckSuite struct {
+		HeadBranch string `json:"head_branch"`
+		Status     string `json:"status"`
+		Conclusion string `json:"conclusion"`
+	} `json:"check_suite"`
+	Branches []struct {
+		Name string `json:"name"`
+	} `json:"branches"`
+}
+
+// parseGitHubStyleEvent parses a GitHub-format webhook payload.
+func parseGitHubStyleEvent(forgeName, kind string, body []byte) (*Event, error) {
+	var p githubStylePayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("parsing %s webhook: %w", forgeName, err)
+	}
+
+	ev := &Event{Forge: forgeName, Repo: p.Repository.FullName}
+	switch kind {
+	case "push":
+		ev.Type = EventPush
+		ev.Branch = strings.TrimPrefix(p.Ref, "refs/heads/")
+	case "pull_request":
+		ev.Type = EventPullRequest
+		ev.Branch = p.PullRequest.Head.Ref
+		ev.Action = p.Action
+		if p.Action == "closed" && p.PullRequest.Merged {
+			ev.Action = "merged"
+		}
+		ev.URL = p.PullRequest.HTMLURL
+	case "check_suite":
+		ev.Type = EventChecks
+		ev.Branch = p.CheckSuite.HeadBranch
+		ev.Action = p.Action
+		ev.State = CheckPending
+		if p.CheckSuite.Status == "completed" {
+			ev.State = conclusionState(p.CheckSuite.Conclusion)
+		}
+	case "status":
+		ev.Type = EventChecks
+		if len(p.Branches) > 0 {
+			ev.Branch = p.Branches[0].Name
+		}
+		ev.State = commitStatusState(p.State)
+		ev.URL = p.TargetURL
+	default:
+		return nil, fmt.Errorf("%w: %s %q", ErrUnsupportedEvent, forgeName, kind)
+	}
+	return ev, nil
+}
+
+// conclusionState maps a GitHub check conclusion to a check state.
+func conclusionState(conclusion string) string {
+	switch conclusion {
+	case "success", "neutral":
+		return CheckPass
+	case "skipped":
+		return CheckSkipped
+	case "":
+		return CheckPending
+	default: // failure, cancelled, timed_out, action_required, stale
+		return CheckFail
+	}
+}
+
+// commitStatusState maps a commit status state (GitHub or Gitea) to a
+// check state.
+func commitStatusState(state string) string {
+	switch state {
+	case "success", "warning":
+		return CheckPass
+	case "failure", "error":
+		return CheckFail
+	default:
+		return CheckPending
+	}
+}
@@ -0,0 +1,98 @@
+package forge
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func sign(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestGitHubWebhook(t *testing.T) {
+	body := []byte(`{"action":"closed","repository":{"full_name":"o/r"},
+		"pull_request":{"html_url":"https://github.com/o/r/pull/7","merged":true,"head":{"ref":"polecat/Nux/gt-abc"}}}`)
+	h := http.Header{}
+	h.Set("X-GitHub-Event", "pull_request")
+	h.Set("X-Hub-Signature-256", "sha256="+sign(body, "s3cret"))
+
+	ev, err := newGitHub(Options{}).ParseWebhook(h, body, "s3cret")
+	if err != nil {
+		t.Fatalf("ParseWebhook: %v", err)
+	}
+	if ev.Type != EventPullRequest || ev.Action != "merged" || ev.Branch != "polecat/Nux/gt-abc" || ev.Repo != "o/r" {
+		t.Errorf("event = %+v", ev)
+	}
+
+	h.Set("X-Hub-Signature-256", "sha256="+sign(body, "wrong"))
+	if _, err := newGitHub(Options{}).ParseWebhook(h, body, "s3cret"); !errors.Is(err, ErrWebhookSignature) {
+		t.Errorf("bad signature error = %v, want ErrWebhookSignature", err)
+	}
+}
+
+func TestGitHubCheckSuiteWebhook(t *testing.T) {
+	body := []byte(`{"action":"completed","repository":{"full_name":"o/r"},
+		"check_suite":{"head_branch":"polecat/Nux/gt-abc","status":"completed","conclusion":"failure"}}`)
+	h := http.Header{}
+	h.Set("X-GitHub-Event", "check_suite")
+
+	ev, err := newGitHub(Options{}).ParseWebhook(h, body, "")
+	if err != nil {
+		t.Fatalf("ParseWebhook: %v", err)
+	}
+	if ev.Type != EventChecks || ev.State != CheckFail {
+		t.Errorf("event = %+v, want failed checks", ev)
+	}
+}
+
+func TestGitLabWebhook(t *testing.T) {
+	body := []byte(`{"project":{"path_with_namespace":"team/repo"},
+		"object_attributes":{"ref":"polecat/Nux/gt-abc","status":"success","url":"https://gitlab.com/team/repo/-/pipelines/1"}}`)
+	h := http.Header{}
+	h.Set("X-Gitlab-Event", "Pipeline Hook")
+	h.Set("X-Gitlab-Token", "s3cret")
+
+	ev, err := newGitLab(Options{}).ParseWebhook(h, body, "s3cret")
+	if err != nil {
+		t.Fatalf("ParseWebhook: %v", err)
+	}
+	if ev.Type != EventChecks || ev.State != CheckPass || ev.Branch != "polecat/Nux/gt-abc" || ev.Repo != "team/repo" {
+		t.Errorf("event = %+v", ev)
+	}
+
+	h.Set("X-Gitlab-Token", "wrong")
+	if _, err := newGitLab(Options{}).ParseWebhook(h, body, "s3cret"); !errors.Is(err, ErrWebhookSignature) {
+		t.Errorf("bad token error = %v, want ErrWebhookSignature", err)
+	}
+}
+
+func TestGiteaWebhook(t *testing.T) {
+	f, err := newGitea(Options{RemoteURL: "https://gitea.test/team/repo.git"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := []byte(`{"ref":"refs/heads/main","repository":{"full_name":"team/repo"}}`)
+	h := http.Header{}
+	h.Set("X-Gitea-Event", "push")
+	h.Set("X-Gitea-Signature", sign(body, "s3cret"))
+
+	ev, err := f.ParseWebhook(h, body, "s3cret")
+	if err != nil {
+		t.Fatalf("ParseWebhook: %v", err)
+	}
+	if ev.Type != EventPush || ev.Branch != "main" || ev.Forge != Gitea {
+		t.Errorf("event = %+v", ev)
+	}
+
+	h.Set("X-Gitea-Event", "wiki")
+	h.Set("X-Gitea-Signature", sign(body, "s3cret"))
+	if _, err := f.ParseWebhook(h, body, "s3cret"); !errors.Is(err, ErrUnsupportedEvent) {
+		t.Errorf("unsupported event error = %v, want ErrUnsupportedEvent", err)
+	}
+}
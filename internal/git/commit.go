@@ -0,0 +1,178 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Commit and push errors.
+var (
+	ErrInvalidCommitMessage = errors.New("invalid commit message")
+	ErrNothingToCommit      = errors.New("nothing to commit")
+	ErrPushRejected         = errors.New("push rejected")
+)
+
+// CommitTypes are the conventional-commit types accepted by CommitMessage.
+var CommitTypes = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+// ActorTrailer is the commit trailer naming the agent that made a commit
+// (its BD_ACTOR address, e.g. "gastown/polecats/toast"), so history can be
+// audited by agent even when author names are shortened.
+const ActorTrailer = "Gt-Actor"
+
+// CommitMessage is a conventional-commit message:
+//
+//	type(scope)!: subject (issue)
+//
+//	body
+type CommitMessage struct {
+	Type     string // one of CommitTypes
+	Scope    string // optional area, e.g. "refinery"
+	Subject  string // imperative summary, no trailing period
+	Body     string // optional
+	Issue    string // bead ID, appended to the subject
+	Breaking bool   // marks the type with "!"
+}
+
+// Validate checks that m is a well-formed conventional commit.
+func (m CommitMessage) Validate() error {
+	valid := false
+	for _, t := range CommitTypes {
+		if m.Type == t {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("%w: type %q must be one of %s", ErrInvalidCommitMessage, m.Type, strings.Join(CommitTypes, ", "))
+	}
+	if strings.ContainsAny(m.Scope, "() \n") {
+		return fmt.Errorf("%w: scope %q must be a single word", ErrInvalidCommitMessage, m.Scope)
+	}
+	if strings.TrimSpace(m.Subject) == "" {
+		return fmt.Errorf("%w: subject is required", ErrInvalidCommitMessage)
+	}
+	if strings.Contains(m.Subject, "\n") {
+		return fmt.Errorf("%w: subject must be one line", ErrInvalidCommitMessage)
+	}
+	return nil
+}
+
+// String formats m as a commit message.
+func (m CommitMessage) String() string {
+	var b strings.Builder
+	b.WriteString(m.Type)
+	if m.Scope != "" {
+		fmt.Fprintf(&b, "(%s)", m.Scope)
+	}
+	if m.Breaking {
+		b.WriteString("!")
+	}
+	b.WriteString(": ")
+	b.WriteString(strings.TrimSuffix(strings.TrimSpace(m.Subject), "."))
+	if m.Issue != "" {
+		fmt.Fprintf(&b, " (%s)", m.Issue)
+	}
+	if body := strings.TrimSpace(m.Body); body != "" {
+		b.WriteString("\n\n")
+		b.WriteString(body)
+	}
+	return b.String()
+}
+
+// CommitAuthor identifies who makes a commit. Empty Name and Email leave
+// git's configured identity in place.
+type CommitAuthor struct {
+	Name  string // author and committer name
+	Email string // author and committer email
+	Actor string // agent address, recorded in the ActorTrailer
+}
+
+// env returns the git environment that sets a's identity as both author
+// and committer, overriding user.name/user.email and any GIT_AUTHOR_* the
+// session exported.
+func (a CommitAuthor) env() []string {
+	var env []string
+	if a.Name != "" {
+		env = append(env, "GIT_AUTHOR_NAME="+a.Name, "GIT_COMMITTER_NAME="+a.Name)
+	}
+	if a.Email != "" {
+		env = append(env, "GIT_AUTHOR_EMAIL="+a.Email, "GIT_COMMITTER_EMAIL="+a.Email)
+	}
+	return env
+}
+
+// CommitAll stages every change (including untracked files) and commits it
+// with msg, attributed to author. Returns the new commit's hash.
+// Returns ErrInvalidCommitMessage for a malformed msg and ErrNothingToCommit
+// if the work tree is clean; git failures (e.g., a rejecting hook) are
+// returned as *GitError.
+func (g *Git) CommitAll(msg CommitMessage, author CommitAuthor) (string, error) {
+	if err := msg.Validate(); err != nil {
+		return "", err
+	}
+	if _, err := g.run("add", "-A"); err != nil {
+		return "", err
+	}
+	status, err := g.Status()
+	if err != nil {
+		return "", err
+	}
+	if status.Clean {
+		return "", ErrNothingToCommit
+	}
+
+	message := msg.String()
+	if author.Actor != "" {
+		message += "\n\n" + ActorTrailer + ": " + author.Actor
+	}
+	if _, err := g.runWithEnv(author.env(), "commit", "-m", message); err != nil {
+		return "", err
+	}
+	return g.run("rev-parse", "HEAD")
+}
+
+// PushError is a push the remote refused, from git's porcelain push output.
+// It matches ErrPushRejected and unwraps to the underlying *GitError.
+type PushError struct {
+	Remote string
+	Branch string
+	// Reason is git's explanation, e.g. "non-fast-forward", "fetch first",
+	// or the remote's message such as "pre-receive hook declined".
+	Reason string
+	Err    error
+}
+
+func (e *PushError) Error() string {
+	return fmt.Sprintf("push %s to %s rejected: %s", e.Branch, e.Remote, e.Reason)
+}
+
+func (e *PushError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrPushRejected.
+func (e *PushError) Is(target error) bool {
+	return target == ErrPushRejected
+}
+
+// pushRejection returns the reason from a rejected ref in git push
+// --porcelain output ("!\t<from>:<to>\t[rejected] (non-fast-forward)").
+func pushRejection(stdout string) (string, bool) {
+	for _, line := range strings.Split(stdout, "\n") {
+		if !strings.HasPrefix(line, "!\t") {
+			continue
+		}
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) < 3 {
+			return "rejected", true
+		}
+		summary := fields[2]
+		if open := strings.Index(summary, "("); open >= 0 && strings.HasSuffix(summary, ")") {
+			return summary[open+1 : len(summary)-1], true
+		}
+		return strings.Trim(summary, "[]"), true
+	}
+	return "", false
+}
@@ -0,0 +1,115 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommitMessageString(t *testing.T) {
+	tests := []struct {
+		msg  CommitMessage
+		want string
+	}{
+		{CommitMessage{Type: "fix", Subject: "handle empty queue"}, "fix: handle empty queue"},
+		{CommitMessage{Type: "feat", Scope: "refinery", Subject: "Add merge trains.", Issue: "gt-abc"},
+			"feat(refinery): Add merge trains (gt-abc)"},
+		{CommitMessage{Type: "refactor", Subject: "drop v1 config", Breaking: true, Body: "Config v1 is gone.\n"},
+			"refactor!: drop v1 config\n\nConfig v1 is gone."},
+	}
+	for _, tt := range tests {
+		if got := tt.msg.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestCommitMessageValidate(t *testing.T) {
+	for _, msg := range []CommitMessage{
+		{Type: "feature", Subject: "x"},
+		{Type: "fix", Subject: " "},
+		{Type: "fix", Subject: "two\nlines"},
+		{Type: "fix", Scope: "a b", Subject: "x"},
+	} {
+		if err := msg.Validate(); !errors.Is(err, ErrInvalidCommitMessage) {
+			t.Errorf("Validate(%+v) = %v, want ErrInvalidCommitMessage", msg, err)
+		}
+	}
+	if err := (CommitMessage{Type: "chore", Scope: "deps", Subject: "bump"}).Validate(); err != nil {
+		t.Errorf("Validate = %v, want nil", err)
+	}
+}
+
+func TestCommitAll(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	author := CommitAuthor{Name: "gastown/polecats/toast", Email: "toast@gastown.local", Actor: "gastown/polecats/toast"}
+	msg := CommitMessage{Type: "fix", Scope: "mail", Subject: "deliver to crew", Issue: "gt-123"}
+
+	if _, err := g.CommitAll(msg, author); !errors.Is(err, ErrNothingToCommit) {
+		t.Fatalf("CommitAll on clean tree = %v, want ErrNothingToCommit", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "toast") // session env must not win
+	sha, err := g.CommitAll(msg, author)
+	if err != nil {
+		t.Fatalf("CommitAll: %v", err)
+	}
+	if head, _ := g.Rev("HEAD"); sha != head {
+		t.Errorf("CommitAll returned %s, HEAD is %s", sha, head)
+	}
+
+	out, err := g.run("log", "-1", "--format=%an|%ae|%cn|%ce|%s|%(trailers:key="+ActorTrailer+",valueonly)")
+	if err != nil {
+		t.Fatalf("log: %v", err)
+	}
+	want := "gastown/polecats/toast|toast@gastown.local|gastown/polecats/toast|toast@gastown.local|fix(mail): deliver to crew (gt-123)|gastown/polecats/toast"
+	if out != want {
+		t.Errorf("commit = %q, want %q", out, want)
+	}
+	if status, _ := g.Status(); !status.Clean {
+		t.Error("untracked file should have been committed")
+	}
+}
+
+func TestPushRejected(t *testing.T) {
+	src := initTestRepo(t)
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	if out, err := exec.Command("git", "clone", "--bare", src, remote).CombinedOutput(); err != nil {
+		t.Fatalf("clone --bare: %v: %s", err, out)
+	}
+	g := NewGit(src)
+	g.SetProtectedBranches([]string{})
+	t.Chdir(src) // keep the push audit event out of the source tree
+	if _, err := g.run("remote", "add", "origin", remote); err != nil {
+		t.Fatalf("remote add: %v", err)
+	}
+	branch, _ := g.CurrentBranch()
+
+	// Rewrite local history so the push is not a fast-forward
+	if _, err := g.run("commit", "--amend", "-m", "rewritten"); err != nil {
+		t.Fatalf("amend: %v", err)
+	}
+	err := g.Push("origin", branch, false)
+	var pushErr *PushError
+	if !errors.As(err, &pushErr) || !errors.Is(err, ErrPushRejected) {
+		t.Fatalf("Push = %v, want *PushError", err)
+	}
+	if pushErr.Branch != branch || !strings.Contains(pushErr.Reason, "non-fast-forward") {
+		t.Errorf("PushError = %+v, want non-fast-forward on %s", pushErr, branch)
+	}
+	var gitErr *GitError
+	if !errors.As(err, &gitErr) {
+		t.Error("PushError should unwrap to *GitError")
+	}
+
+	if err := g.PushWithOptions("origin", branch, PushOptions{Force: true, AllowForce: true}); err != nil {
+		t.Errorf("forced Push = %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FileStat is the change to one file in a DiffStat.
+type FileStat struct {
+	Path       string `json:"path"`
+	Insertions int    `json:"insertions"`
+	Deletions  int    `json:"deletions"`
+	Binary     bool   `json:"binary,omitempty"` // no line counts
+}
+
+// DiffStat summarizes how much a branch has changed relative to a base.
+type DiffStat struct {
+	Base       string     `json:"base"`
+	Insertions int        `json:"insertions"`
+	Deletions  int        `json:"deletions"`
+	Files      []FileStat `json:"files,omitempty"`
+}
+
+// FilesChanged returns the number of files changed.
+func (s *DiffStat) FilesChanged() int {
+	return len(s.Files)
+}
+
+// String summarizes s, e.g. "3 files, +120 -4", or "no changes".
+func (s *DiffStat) String() string {
+	switch n := len(s.Files); n {
+	case 0:
+		return "no changes"
+	case 1:
+		return fmt.Sprintf("1 file, +%d -%d", s.Insertions, s.Deletions)
+	default:
+		return fmt.Sprintf("%d files, +%d -%d", n, s.Insertions, s.Deletions)
+	}
+}
+
+// DiffStat returns what the current branch has changed since it diverged
+// from base (e.g., "origin/main"): its commits plus uncommitted changes to
+// tracked files, so a worker's progress shows before it commits. Untracked
+// files are not counted.
+func (g *Git) DiffStat(base string) (*DiffStat, error) {
+	mergeBase, err := g.run("merge-base", base, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	out, err := g.run("diff", "--numstat", "-z", "--no-renames", mergeBase)
+	if err != nil {
+		return nil, err
+	}
+	stat := parseNumstat(out)
+	stat.Base = base
+	return stat, nil
+}
+
+// parseNumstat parses git diff --numstat -z --no-renames output:
+// "<insertions>\t<deletions>\t<path>" records, with "-" counts for binary
+// files.
+func parseNumstat(out string) *DiffStat {
+	stat := &DiffStat{}
+	for _, record := range strings.Split(out, "\x00") {
+		fields := strings.SplitN(strings.TrimPrefix(record, "\n"), "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		file := FileStat{Path: fields[2]}
+		if fields[0] == "-" && fields[1] == "-" {
+			file.Binary = true
+		} else {
+			file.Insertions, _ = strconv.Atoi(fields[0])
+			file.Deletions, _ = strconv.Atoi(fields[1])
+		}
+		stat.Insertions += file.Insertions
+		stat.Deletions += file.Deletions
+		stat.Files = append(stat.Files, file)
+	}
+	return stat
+}
@@ -0,0 +1,71 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseNumstat(t *testing.T) {
+	stat := parseNumstat("10\t2\tmain.go\x00-\t-\tlogo.png\x000\t5\tdir/old file.txt\x00")
+	want := []FileStat{
+		{Path: "main.go", Insertions: 10, Deletions: 2},
+		{Path: "logo.png", Binary: true},
+		{Path: "dir/old file.txt", Deletions: 5},
+	}
+	if len(stat.Files) != len(want) {
+		t.Fatalf("Files = %+v, want %+v", stat.Files, want)
+	}
+	for i, f := range want {
+		if stat.Files[i] != f {
+			t.Errorf("Files[%d] = %+v, want %+v", i, stat.Files[i], f)
+		}
+	}
+	if stat.Insertions != 10 || stat.Deletions != 7 || stat.String() != "3 files, +10 -7" {
+		t.Errorf("totals = +%d -%d (%q)", stat.Insertions, stat.Deletions, stat.String())
+	}
+	if s := parseNumstat("").String(); s != "no changes" {
+		t.Errorf("empty String() = %q", s)
+	}
+}
+
+func TestDiffStat(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	base, _ := g.CurrentBranch()
+	if err := g.CreateBranch("feature"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := g.Checkout("feature"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "new.go"), []byte("a\nb\nc\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_ = g.Add("new.go")
+	_ = g.Commit("add new.go")
+
+	// The base branch moving on doesn't count; uncommitted edits do
+	_ = g.Checkout(base)
+	if err := os.WriteFile(filepath.Join(dir, "upstream.txt"), []byte("x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_ = g.Add("upstream.txt")
+	_ = g.Commit("upstream")
+	_ = g.Checkout("feature")
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stat, err := g.DiffStat(base)
+	if err != nil {
+		t.Fatalf("DiffStat: %v", err)
+	}
+	if stat.FilesChanged() != 2 || stat.Insertions != 4 || stat.Deletions != 1 {
+		t.Errorf("DiffStat = %+v, want 2 files, +4 -1", stat)
+	}
+	if stat.Base != base {
+		t.Errorf("Base = %q, want %q", stat.Base, base)
+	}
+}
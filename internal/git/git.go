@@ -3,10 +3,12 @@ package git
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -37,6 +39,74 @@ func (e *GitError) Unwrap() error {
 type Git struct {
 	workDir string
 	gitDir  string // Optional: explicit git directory (for bare repos)
+
+	// credentialHelper, if set, is configured on clones (credential.helper)
+	// so clone, fetch, and push authenticate against the forge.
+	credentialHelper string
+
+	// cloneOpts limits what clones fetch.
+	cloneOpts CloneOptions
+
+	// sparsePaths limits the directories checked out in clones and worktrees.
+	sparsePaths []string
+
+	// protectedBranches are the branch patterns Push guards (nil = defaults).
+	protectedBranches []string
+
+	// retry overrides the default retry policy for network operations.
+	retry *RetryPolicy
+}
+
+// CloneOptions limits what a clone fetches, so workspaces for very large
+// repositories don't pull their full history. The zero value is a full clone.
+type CloneOptions struct {
+	// Depth truncates history to this many commits (0 = full history).
+	// Use Unshallow to fetch the rest later.
+	Depth int `json:"depth,omitempty"`
+
+	// SingleBranch fetches only one branch: Branch, or the remote's
+	// default branch if Branch is empty.
+	SingleBranch bool   `json:"single_branch,omitempty"`
+	Branch       string `json:"branch,omitempty"`
+
+	// Filter makes a partial clone with a git object filter, e.g.
+	// "blob:none" (blobs fetched on demand) or "blob:limit=1m".
+	Filter string `json:"filter,omitempty"`
+
+	// NoSubmodules skips initializing and updating submodules in clones,
+	// worktrees, and pulls (they are updated recursively by default).
+	NoSubmodules bool `json:"no_submodules,omitempty"`
+
+	// SkipLFS leaves Git LFS files as pointer files in clones and
+	// worktrees, to save bandwidth (they are downloaded by default).
+	SkipLFS bool `json:"skip_lfs,omitempty"`
+}
+
+// IsZero reports whether o is a full clone with submodules and LFS files.
+func (o CloneOptions) IsZero() bool {
+	return o.Depth == 0 && !o.SingleBranch && o.Filter == "" && !o.NoSubmodules && !o.SkipLFS
+}
+
+// args returns the git clone flags for o.
+func (o CloneOptions) args() []string {
+	var args []string
+	if o.Depth > 0 {
+		args = append(args, fmt.Sprintf("--depth=%d", o.Depth))
+	}
+	switch {
+	case o.SingleBranch:
+		args = append(args, "--single-branch")
+		if o.Branch != "" {
+			args = append(args, "--branch="+o.Branch)
+		}
+	case o.Depth > 0:
+		// --depth implies --single-branch; keep the two independent
+		args = append(args, "--no-single-branch")
+	}
+	if o.Filter != "" {
+		args = append(args, "--filter="+o.Filter)
+	}
+	return args
 }
 
 // NewGit creates a new Git wrapper for the given directory.
@@ -51,6 +121,47 @@ func NewGitWithDir(gitDir, workDir string) *Git {
 	return &Git{gitDir: gitDir, workDir: workDir}
 }
 
+// SetCredentialHelper sets a git credential helper to configure on clones
+// and worktrees, so agents can fetch and push over HTTPS without globally
+// configured credentials. The helper is persisted in the repo's config, so it
+// must not embed secrets (forge helpers read the token from the environment
+// or keyring when invoked).
+func (g *Git) SetCredentialHelper(helper string) {
+	g.credentialHelper = helper
+}
+
+// SetCloneOptions sets the depth, branch, and filter limits for clones, and
+// whether clones, worktrees, and pulls update submodules.
+func (g *Git) SetCloneOptions(opts CloneOptions) {
+	g.cloneOpts = opts
+}
+
+// SetSparsePaths limits the clones and worktrees this Git creates to the
+// given repo directories (plus top-level files). Nil checks out everything.
+func (g *Git) SetSparsePaths(paths []string) {
+	g.sparsePaths = paths
+}
+
+// cloneArgs builds a git clone invocation, adding the credential helper and
+// clone options.
+func (g *Git) cloneArgs(args ...string) []string {
+	cmd := []string{"clone"}
+	if g.credentialHelper != "" {
+		cmd = append(cmd, "--config", "credential.helper="+g.credentialHelper)
+	}
+	cmd = append(cmd, g.cloneOpts.args()...)
+	return append(cmd, args...)
+}
+
+// sparseCloneArgs defers a non-bare clone's checkout until its sparse paths
+// are configured.
+func (g *Git) sparseCloneArgs(args ...string) []string {
+	if len(g.sparsePaths) == 0 {
+		return args
+	}
+	return append([]string{"--no-checkout"}, args...)
+}
+
 // WorkDir returns the working directory for this Git instance.
 func (g *Git) WorkDir() string {
 	return g.workDir
@@ -64,6 +175,11 @@ func (g *Git) IsRepo() bool {
 
 // run executes a git command and returns stdout.
 func (g *Git) run(args ...string) (string, error) {
+	return g.runWithEnv(nil, args...)
+}
+
+// runWithEnv is run with extra environment variables (e.g., GIT_AUTHOR_NAME).
+func (g *Git) runWithEnv(env []string, args ...string) (string, error) {
 	// If gitDir is set (bare repo), prepend --git-dir flag
 	if g.gitDir != "" {
 		args = append([]string{"--git-dir=" + g.gitDir}, args...)
@@ -73,6 +189,7 @@ func (g *Git) run(args ...string) (string, error) {
 	if g.workDir != "" {
 		cmd.Dir = g.workDir
 	}
+	cmd.Env = nonInteractiveEnv(env...)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -86,6 +203,13 @@ func (g *Git) run(args ...string) (string, error) {
 	return strings.TrimSpace(stdout.String()), nil
 }
 
+// nonInteractiveEnv returns the environment for git commands, plus env.
+// Agents have no terminal to answer credential prompts, so git fails
+// instead of hanging when no credential helper can authenticate.
+func nonInteractiveEnv(env ...string) []string {
+	return append(append(os.Environ(), "GIT_TERMINAL_PROMPT=0"), env...)
+}
+
 // wrapError wraps git errors with context.
 // ZFC: Returns GitError with raw output for agent observation.
 // Does not detect or interpret error types - agents should observe and decide.
@@ -116,51 +240,55 @@ func (g *Git) wrapError(err error, stdout, stderr string, args []string) error {
 
 // Clone clones a repository to the destination.
 func (g *Git) Clone(url, dest string) error {
-	cmd := exec.Command("git", "clone", url, dest)
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
-		return g.wrapError(err, stdout.String(), stderr.String(), []string{"clone", url})
+	if err := g.runClone([]string{"clone", url}, g.cloneArgs(g.sparseCloneArgs(url, dest)...)...); err != nil {
+		return err
 	}
 	// Configure hooks path for Gas Town clones
 	if err := configureHooksPath(dest); err != nil {
 		return err
 	}
 	// Configure sparse checkout to exclude .claude/ from source repo
-	return ConfigureSparseCheckout(dest)
+	return g.finishCheckout(dest)
 }
 
 // CloneWithReference clones a repository using a local repo as an object reference.
 // This saves disk by sharing objects without changing remotes.
 func (g *Git) CloneWithReference(url, dest, reference string) error {
-	cmd := exec.Command("git", "clone", "--reference-if-able", reference, url, dest)
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
-		return g.wrapError(err, stdout.String(), stderr.String(), []string{"clone", "--reference-if-able", url})
+	if err := g.runClone([]string{"clone", "--reference-if-able", url}, g.cloneArgs(g.sparseCloneArgs("--reference-if-able", reference, url, dest)...)...); err != nil {
+		return err
 	}
 	// Configure hooks path for Gas Town clones
 	if err := configureHooksPath(dest); err != nil {
 		return err
 	}
 	// Configure sparse checkout to exclude .claude/ from source repo
-	return ConfigureSparseCheckout(dest)
+	return g.finishCheckout(dest)
 }
 
 // CloneBare clones a repository as a bare repo (no working directory).
 // This is used for the shared repo architecture where all worktrees share a single git database.
 func (g *Git) CloneBare(url, dest string) error {
-	cmd := exec.Command("git", "clone", "--bare", url, dest)
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
-		return g.wrapError(err, stdout.String(), stderr.String(), []string{"clone", "--bare", url})
+	if err := g.runClone([]string{"clone", "--bare", url}, g.cloneArgs("--bare", url, dest)...); err != nil {
+		return err
 	}
 	// Configure refspec so worktrees can fetch and see origin/* refs
-	return configureRefspec(dest)
+	return configureRefspec(dest, g.cloneOpts)
+}
+
+// runClone runs a git clone invocation, retrying transient network
+// failures. errArgs is the command reported in errors (without paths).
+func (g *Git) runClone(errArgs []string, args ...string) error {
+	return g.withRetry("clone", func() error {
+		cmd := exec.Command("git", args...)
+		cmd.Env = nonInteractiveEnv(g.checkoutEnv()...)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return g.wrapError(err, stdout.String(), stderr.String(), errArgs)
+		}
+		return nil
+	})
 }
 
 // configureHooksPath sets core.hooksPath to use the repo's .githooks directory
@@ -187,15 +315,30 @@ func configureHooksPath(repoPath string) error {
 // fetch and see origin/* refs. Without this, `git fetch` only updates FETCH_HEAD
 // and origin/main never appears in refs/remotes/origin/main.
 // See: https://github.com/anthropics/gastown/issues/286
-func configureRefspec(repoPath string) error {
-	cmd := exec.Command("git", "-C", repoPath, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
+// A single-branch clone tracks only its branch, and a shallow clone's first
+// fetch keeps the same depth so other branches don't pull full history.
+func configureRefspec(repoPath string, opts CloneOptions) error {
+	refspec := "+refs/heads/*:refs/remotes/origin/*"
+	if opts.SingleBranch {
+		out, err := exec.Command("git", "-C", repoPath, "symbolic-ref", "--short", "HEAD").Output()
+		if err != nil {
+			return fmt.Errorf("reading cloned branch: %w", err)
+		}
+		branch := strings.TrimSpace(string(out))
+		refspec = "+refs/heads/" + branch + ":refs/remotes/origin/" + branch
+	}
+	cmd := exec.Command("git", "-C", repoPath, "config", "remote.origin.fetch", refspec)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("configuring refspec: %s", strings.TrimSpace(stderr.String()))
 	}
 	// Fetch to populate refs/remotes/origin/* so worktrees can use origin/main
-	fetchCmd := exec.Command("git", "-C", repoPath, "fetch", "origin")
+	fetchArgs := []string{"-C", repoPath, "fetch", "origin"}
+	if opts.Depth > 0 {
+		fetchArgs = append(fetchArgs, fmt.Sprintf("--depth=%d", opts.Depth))
+	}
+	fetchCmd := exec.Command("git", fetchArgs...)
 	fetchCmd.Stderr = &stderr
 	if err := fetchCmd.Run(); err != nil {
 		return fmt.Errorf("fetching origin: %s", strings.TrimSpace(stderr.String()))
@@ -205,15 +348,32 @@ func configureRefspec(repoPath string) error {
 
 // CloneBareWithReference clones a bare repository using a local repo as an object reference.
 func (g *Git) CloneBareWithReference(url, dest, reference string) error {
-	cmd := exec.Command("git", "clone", "--bare", "--reference-if-able", reference, url, dest)
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
-		return g.wrapError(err, stdout.String(), stderr.String(), []string{"clone", "--bare", "--reference-if-able", url})
+	if err := g.runClone([]string{"clone", "--bare", "--reference-if-able", url}, g.cloneArgs("--bare", "--reference-if-able", reference, url, dest)...); err != nil {
+		return err
 	}
 	// Configure refspec so worktrees can fetch and see origin/* refs
-	return configureRefspec(dest)
+	return configureRefspec(dest, g.cloneOpts)
+}
+
+// IsShallow reports whether the repository has truncated history.
+func (g *Git) IsShallow() (bool, error) {
+	out, err := g.run("rev-parse", "--is-shallow-repository")
+	if err != nil {
+		return false, err
+	}
+	return out == "true", nil
+}
+
+// Unshallow fetches the full history of a shallow clone from origin, for
+// agents that need it (blame, bisect, old merge bases). A single-branch
+// clone stays single-branch, and a partial clone still fetches blobs on
+// demand. It does nothing if the repository is not shallow.
+func (g *Git) Unshallow() error {
+	shallow, err := g.IsShallow()
+	if err != nil || !shallow {
+		return err
+	}
+	return g.runNetwork("fetch", "--unshallow", "origin")
 }
 
 // Checkout checks out the given ref.
@@ -222,32 +382,110 @@ func (g *Git) Checkout(ref string) error {
 	return err
 }
 
-// Fetch fetches from the remote.
+// Fetch fetches from the remote, retrying transient network failures.
 func (g *Git) Fetch(remote string) error {
-	_, err := g.run("fetch", remote)
-	return err
+	return g.runNetwork("fetch", remote)
 }
 
-// FetchBranch fetches a specific branch from the remote.
+// FetchBranch fetches a specific branch from the remote, retrying transient
+// network failures.
 func (g *Git) FetchBranch(remote, branch string) error {
-	_, err := g.run("fetch", remote, branch)
-	return err
+	return g.runNetwork("fetch", remote, branch)
 }
 
-// Pull pulls from the remote branch.
+// runNetwork runs a git command that talks to a remote under withRetry.
+func (g *Git) runNetwork(args ...string) error {
+	return g.withRetry(args[0], func() error {
+		_, err := g.run(args...)
+		return err
+	})
+}
+
+// Pull pulls from the remote branch (retrying transient network failures),
+// then updates submodules to the
+// commits it brought in (unless CloneOptions.NoSubmodules).
 func (g *Git) Pull(remote, branch string) error {
-	_, err := g.run("pull", remote, branch)
+	if err := g.runNetwork("pull", remote, branch); err != nil {
+		return err
+	}
+	if g.cloneOpts.NoSubmodules {
+		return nil
+	}
+	return g.UpdateSubmodules()
+}
+
+// UpdateSubmodules initializes and updates the repo's submodules,
+// recursively, to the commits the superproject records, so agents don't find
+// them empty. It does nothing in a repo without .gitmodules.
+func (g *Git) UpdateSubmodules() error {
+	if _, err := os.Stat(filepath.Join(g.workDir, ".gitmodules")); err != nil {
+		return nil
+	}
+	// Pick up submodule URL changes before fetching
+	if _, err := g.run("submodule", "sync", "--recursive"); err != nil {
+		return err
+	}
+	_, err := g.run("submodule", "update", "--init", "--recursive")
 	return err
 }
 
+// ConfigureCredentialHelper sets the repo's credential.helper, replacing
+// any set in its config (global helpers still apply as fallbacks). A
+// worktree shares its repo's config.
+func (g *Git) ConfigureCredentialHelper(helper string) error {
+	_, err := g.run("config", "--replace-all", "credential.helper", helper)
+	return err
+}
+
+// finishCheckout configures a new clone or worktree at path: credentials,
+// sparse checkout (which performs any deferred checkout), then submodules
+// and LFS files, which may need the credentials.
+func (g *Git) finishCheckout(path string) error {
+	if g.credentialHelper != "" {
+		if err := NewGit(path).ConfigureCredentialHelper(g.credentialHelper); err != nil {
+			return fmt.Errorf("configuring credential helper: %w", err)
+		}
+	}
+	if err := ConfigureSparseCheckoutPaths(path, g.sparsePaths); err != nil {
+		return err
+	}
+	if !g.cloneOpts.NoSubmodules {
+		if err := NewGit(path).UpdateSubmodules(); err != nil {
+			return err
+		}
+	}
+	return g.provisionLFS(path)
+}
+
 // Push pushes to the remote branch.
+// Force-pushes and pushes to protected branches are refused by the guard;
+// use PushWithOptions to override it.
+// A push the remote refuses is returned as a *PushError (ErrPushRejected).
 func (g *Git) Push(remote, branch string, force bool) error {
-	args := []string{"push", remote, branch}
-	if force {
+	return g.PushWithOptions(remote, branch, PushOptions{Force: force})
+}
+
+// PushWithOptions is Push with explicit guard overrides. A push the guard
+// refuses is returned as a *PushGuardError (ErrPushGuarded) without running
+// git.
+func (g *Git) PushWithOptions(remote, branch string, opts PushOptions) error {
+	if err := g.guardPush(remote, branch, opts); err != nil {
+		return err
+	}
+	args := []string{"push", "--porcelain", remote, branch}
+	if opts.Force {
 		args = append(args, "--force")
 	}
-	_, err := g.run(args...)
-	return err
+	return g.withRetry("push", func() error {
+		_, err := g.run(args...)
+		var gitErr *GitError
+		if errors.As(err, &gitErr) {
+			if reason, ok := pushRejection(gitErr.Stdout); ok {
+				return &PushError{Remote: remote, Branch: branch, Reason: reason, Err: err}
+			}
+		}
+		return err
+	})
 }
 
 // Add stages files for commit.
@@ -263,12 +501,6 @@ func (g *Git) Commit(message string) error {
 	return err
 }
 
-// CommitAll stages all changes and commits.
-func (g *Git) CommitAll(message string) error {
-	_, err := g.run("commit", "-am", message)
-	return err
-}
-
 // GitStatus represents the status of the working directory.
 type GitStatus struct {
 	Clean    bool
@@ -276,43 +508,179 @@ type GitStatus struct {
 	Added    []string
 	Deleted  []string
 	Untracked []string
+
+	// Branch is the checked-out branch ("" when Detached).
+	Branch   string
+	Detached bool
+
+	// Upstream is the tracking branch (e.g., "origin/main"), with the
+	// commits HEAD is Ahead of and Behind it. Empty if none is set.
+	Upstream string
+	Ahead    int
+	Behind   int
+
+	// Staged and Unstaged list changed files in the index and the work
+	// tree; a file changed in both appears in both. Conflicted lists
+	// unmerged files.
+	Staged     []string
+	Unstaged   []string
+	Conflicted []string
+
+	// Merging and Rebasing report an in-progress merge or rebase.
+	Merging  bool
+	Rebasing bool
 }
 
-// Status returns the current git status.
+// Status returns the current git status: branch and upstream tracking,
+// changed files, and any in-progress merge or rebase.
+// ZFC: parsed from porcelain v2 output, which is stable across git versions.
 func (g *Git) Status() (*GitStatus, error) {
-	out, err := g.run("status", "--porcelain")
+	out, err := g.run("status", "--porcelain=v2", "--branch", "-z")
 	if err != nil {
 		return nil, err
 	}
 
 	status := &GitStatus{Clean: true}
-	if out == "" {
-		return status, nil
-	}
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		line := entries[i]
+		if len(line) < 2 {
+			continue
+		}
+		if strings.HasPrefix(line, "# ") {
+			parseStatusHeader(status, line[2:])
+			continue
+		}
 
-	status.Clean = false
-	for _, line := range strings.Split(out, "\n") {
-		if len(line) < 3 {
+		var code, file string
+		switch line[0] {
+		case '1':
+			if f := strings.SplitN(line, " ", 9); len(f) == 9 {
+				code, file = f[1], f[8]
+			}
+		case '2':
+			if f := strings.SplitN(line, " ", 10); len(f) == 10 {
+				code, file = f[1], f[9]
+			}
+			i++ // the rename's original path follows
+		case 'u':
+			if f := strings.SplitN(line, " ", 11); len(f) == 11 {
+				status.Clean = false
+				status.Conflicted = append(status.Conflicted, f[10])
+			}
+			continue
+		case '?':
+			status.Clean = false
+			status.Untracked = append(status.Untracked, line[2:])
+			continue
+		default:
+			continue
+		}
+		if len(code) != 2 {
 			continue
 		}
-		code := line[:2]
-		file := line[3:]
 
+		status.Clean = false
+		if code[0] != '.' {
+			status.Staged = append(status.Staged, file)
+		}
+		if code[1] != '.' {
+			status.Unstaged = append(status.Unstaged, file)
+		}
 		switch {
-		case strings.Contains(code, "M"):
+		case strings.ContainsAny(code, "MR"):
 			status.Modified = append(status.Modified, file)
 		case strings.Contains(code, "A"):
 			status.Added = append(status.Added, file)
 		case strings.Contains(code, "D"):
 			status.Deleted = append(status.Deleted, file)
-		case strings.Contains(code, "?"):
-			status.Untracked = append(status.Untracked, file)
 		}
 	}
 
+	status.Merging, status.Rebasing = g.inProgressOps()
 	return status, nil
 }
 
+// parseStatusHeader applies a porcelain v2 "# branch.*" header to status.
+func parseStatusHeader(status *GitStatus, header string) {
+	key, value, _ := strings.Cut(header, " ")
+	switch key {
+	case "branch.head":
+		if value == "(detached)" {
+			status.Detached = true
+		} else {
+			status.Branch = value
+		}
+	case "branch.upstream":
+		status.Upstream = value
+	case "branch.ab":
+		_, _ = fmt.Sscanf(value, "+%d -%d", &status.Ahead, &status.Behind)
+	}
+}
+
+// inProgressOps reports whether a merge or rebase is in progress, from the
+// state files git leaves in the git dir (per worktree).
+func (g *Git) inProgressOps() (merging, rebasing bool) {
+	out, err := g.run("rev-parse", "--git-path", "MERGE_HEAD", "--git-path", "rebase-merge", "--git-path", "rebase-apply")
+	if err != nil {
+		return false, false
+	}
+	paths := strings.Split(out, "\n")
+	exists := func(i int) bool {
+		if i >= len(paths) {
+			return false
+		}
+		path := paths[i]
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(g.workDir, path)
+		}
+		_, err := os.Stat(path)
+		return err == nil
+	}
+	return exists(0), exists(1) || exists(2)
+}
+
+// ChangedFiles returns the modified, added, deleted, and conflicted files.
+func (s *GitStatus) ChangedFiles() []string {
+	var files []string
+	files = append(files, s.Modified...)
+	files = append(files, s.Added...)
+	files = append(files, s.Deleted...)
+	return append(files, s.Conflicted...)
+}
+
+// Summary describes the status in a few words for status displays,
+// e.g. "2 staged, 1 untracked, ahead 3, rebasing". Returns "clean" if there
+// is nothing to report.
+func (s *GitStatus) Summary() string {
+	var parts []string
+	count := func(n int, what string) {
+		if n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, what))
+		}
+	}
+	count(len(s.Conflicted), "conflicted")
+	count(len(s.Staged), "staged")
+	count(len(s.Unstaged), "unstaged")
+	count(len(s.Untracked), "untracked")
+	if s.Ahead > 0 {
+		parts = append(parts, fmt.Sprintf("ahead %d", s.Ahead))
+	}
+	if s.Behind > 0 {
+		parts = append(parts, fmt.Sprintf("behind %d", s.Behind))
+	}
+	if s.Merging {
+		parts = append(parts, "merging")
+	}
+	if s.Rebasing {
+		parts = append(parts, "rebasing")
+	}
+	if len(parts) == 0 {
+		return "clean"
+	}
+	return strings.Join(parts, ", ")
+}
+
 // CurrentBranch returns the current branch name.
 func (g *Git) CurrentBranch() (string, error) {
 	return g.run("rev-parse", "--abbrev-ref", "HEAD")
@@ -399,15 +767,23 @@ func (g *Git) MergeNoFF(branch, message string) error {
 	return err
 }
 
+// MergeNoCommit starts a --no-ff merge of branch but stops before committing,
+// leaving any conflicts in the working tree for resolution.
+// Returns an error if the merge stopped on conflicts; callers should inspect
+// GetConflictingFiles rather than the error text.
+func (g *Git) MergeNoCommit(branch string) error {
+	_, err := g.run("merge", "--no-ff", "--no-commit", branch)
+	return err
+}
+
 // DeleteRemoteBranch deletes a branch on the remote.
 func (g *Git) DeleteRemoteBranch(remote, branch string) error {
-	_, err := g.run("push", remote, "--delete", branch)
-	return err
+	return g.runNetwork("push", remote, "--delete", branch)
 }
 
-// Rebase rebases the current branch onto the given ref.
-func (g *Git) Rebase(onto string) error {
-	_, err := g.run("rebase", onto)
+// ResetHard resets the current branch, index, and working tree to ref.
+func (g *Git) ResetHard(ref string) error {
+	_, err := g.run("reset", "--hard", ref)
 	return err
 }
 
@@ -596,58 +972,29 @@ func (g *Git) IsAncestor(ancestor, descendant string) (bool, error) {
 	return true, nil
 }
 
-// WorktreeAdd creates a new worktree at the given path with a new branch.
-// The new branch is created from the current HEAD.
-// Sparse checkout is enabled to exclude .claude/ from source repos.
-func (g *Git) WorktreeAdd(path, branch string) error {
-	if _, err := g.run("worktree", "add", "-b", branch, path); err != nil {
-		return err
-	}
-	return ConfigureSparseCheckout(path)
-}
-
-// WorktreeAddFromRef creates a new worktree at the given path with a new branch
-// starting from the specified ref (e.g., "origin/main").
-// Sparse checkout is enabled to exclude .claude/ from source repos.
-func (g *Git) WorktreeAddFromRef(path, branch, startPoint string) error {
-	if _, err := g.run("worktree", "add", "-b", branch, path, startPoint); err != nil {
-		return err
-	}
-	return ConfigureSparseCheckout(path)
-}
-
-// WorktreeAddDetached creates a new worktree at the given path with a detached HEAD.
-// Sparse checkout is enabled to exclude .claude/ from source repos.
-func (g *Git) WorktreeAddDetached(path, ref string) error {
-	if _, err := g.run("worktree", "add", "--detach", path, ref); err != nil {
-		return err
-	}
-	return ConfigureSparseCheckout(path)
-}
-
-// WorktreeAddExisting creates a new worktree at the given path for an existing branch.
-// Sparse checkout is enabled to exclude .claude/ from source repos.
-func (g *Git) WorktreeAddExisting(path, branch string) error {
-	if _, err := g.run("worktree", "add", path, branch); err != nil {
-		return err
-	}
-	return ConfigureSparseCheckout(path)
-}
-
-// WorktreeAddExistingForce creates a new worktree even if the branch is already checked out elsewhere.
-// This is useful for cross-rig worktrees where multiple clones need to be on main.
-// Sparse checkout is enabled to exclude .claude/ from source repos.
-func (g *Git) WorktreeAddExistingForce(path, branch string) error {
-	if _, err := g.run("worktree", "add", "--force", path, branch); err != nil {
-		return err
+// worktreeAddArgs builds a git worktree add invocation. With sparse paths the
+// checkout is deferred until they are configured, so the rest of the repo is
+// never written to disk.
+func (g *Git) worktreeAddArgs(args ...string) []string {
+	cmd := []string{"worktree", "add"}
+	if len(g.sparsePaths) > 0 {
+		cmd = append(cmd, "--no-checkout")
 	}
-	return ConfigureSparseCheckout(path)
+	return append(cmd, args...)
 }
 
 // ConfigureSparseCheckout sets up sparse checkout for a clone or worktree to exclude .claude/.
 // This ensures source repo settings don't override Gas Town agent settings.
 // Exported for use by doctor checks.
 func ConfigureSparseCheckout(repoPath string) error {
+	return ConfigureSparseCheckoutPaths(repoPath, nil)
+}
+
+// ConfigureSparseCheckoutPaths is ConfigureSparseCheckout that also limits the
+// checkout to the given repo directories (e.g., "services/api"), so a worker
+// on one part of a monorepo doesn't materialize the rest. Top-level files are
+// always checked out. Nil paths check out the whole repo.
+func ConfigureSparseCheckoutPaths(repoPath string, paths []string) error {
 	// Enable sparse checkout
 	cmd := exec.Command("git", "-C", repoPath, "config", "core.sparseCheckout", "true")
 	var stderr bytes.Buffer
@@ -683,8 +1030,7 @@ func ConfigureSparseCheckout(repoPath string) error {
 		return fmt.Errorf("creating info dir: %w", err)
 	}
 	sparseFile := filepath.Join(infoDir, "sparse-checkout")
-	sparsePatterns := "/*\n!/.claude/\n!/CLAUDE.md\n!/CLAUDE.local.md\n!/.mcp.json\n"
-	if err := os.WriteFile(sparseFile, []byte(sparsePatterns), 0644); err != nil {
+	if err := os.WriteFile(sparseFile, []byte(sparsePatterns(paths)), 0644); err != nil {
 		return fmt.Errorf("writing sparse-checkout: %w", err)
 	}
 
@@ -706,6 +1052,44 @@ func ConfigureSparseCheckout(repoPath string) error {
 	return nil
 }
 
+// sparsePatterns returns the sparse-checkout file content for paths. Each
+// directory is included along with its parents' top-level files, the same
+// patterns git's cone mode writes.
+func sparsePatterns(paths []string) string {
+	var b strings.Builder
+	b.WriteString("/*\n")
+	if len(paths) > 0 {
+		b.WriteString("!/*/\n")
+	}
+	var dirs []string
+	for _, p := range paths {
+		p = strings.Trim(filepath.ToSlash(filepath.Clean(p)), "/")
+		if p != "" && p != "." {
+			dirs = append(dirs, p)
+		}
+	}
+	sort.Strings(dirs)
+	parents := make(map[string]bool)
+	var last string
+	for _, dir := range dirs {
+		if last != "" && strings.HasPrefix(dir+"/", last+"/") {
+			continue // already inside an included directory
+		}
+		last = dir
+		parts := strings.Split(dir, "/")
+		for i := 1; i < len(parts); i++ {
+			parent := strings.Join(parts[:i], "/")
+			if !parents[parent] {
+				parents[parent] = true
+				fmt.Fprintf(&b, "/%s/\n!/%s/*/\n", parent, parent)
+			}
+		}
+		fmt.Fprintf(&b, "/%s/\n", dir)
+	}
+	b.WriteString("!/.claude/\n!/CLAUDE.md\n!/CLAUDE.local.md\n!/.mcp.json\n")
+	return b.String()
+}
+
 // ExcludedContextFiles lists all Claude context files that should be excluded by sparse checkout.
 var ExcludedContextFiles = []string{
 	".claude",
@@ -775,8 +1159,182 @@ func IsSparseCheckoutConfigured(repoPath string) bool {
 	return true
 }
 
-// WorktreeRemove removes a worktree.
-func (g *Git) WorktreeRemove(path string, force bool) error {
+// Worktree safety errors. Callers can match them with errors.Is; the
+// returned errors carry details (the path, or what is uncommitted).
+var (
+	// ErrWorktreePathExists indicates the target path exists and is not empty.
+	ErrWorktreePathExists = errors.New("worktree path already exists")
+
+	// ErrBranchCheckedOut indicates the branch is checked out in another worktree.
+	ErrBranchCheckedOut = errors.New("branch is checked out in another worktree")
+
+	// ErrWorktreeDirty indicates a worktree has uncommitted or unpushed work.
+	ErrWorktreeDirty = errors.New("worktree has uncommitted work")
+
+	// ErrNotWorktree indicates a path is not a linked worktree of the repository.
+	ErrNotWorktree = errors.New("not a worktree of this repository")
+)
+
+// AddWorktreeOptions controls AddWorktree.
+type AddWorktreeOptions struct {
+	// Branch is the branch to check out. With NewBranch it is created from
+	// StartPoint (default HEAD). Ignored with Detach.
+	Branch    string
+	NewBranch bool
+
+	// StartPoint is the ref a new branch or detached HEAD starts from.
+	StartPoint string
+
+	// Detach checks out StartPoint (default HEAD) with a detached HEAD.
+	Detach bool
+
+	// Force allows checking out a branch that is already checked out in
+	// another worktree.
+	Force bool
+}
+
+// AddWorktree creates a worktree at path. It refuses a path that exists
+// and is not empty, and a branch checked out in another worktree (unless
+// opts.Force). Sparse checkout is enabled to exclude .claude/ from source
+// repos, and submodules are checked out.
+func (g *Git) AddWorktree(path string, opts AddWorktreeOptions) error {
+	if entries, err := os.ReadDir(path); err == nil && len(entries) > 0 {
+		return fmt.Errorf("%w: %s", ErrWorktreePathExists, path)
+	} else if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("%w: %s", ErrWorktreePathExists, path)
+	}
+
+	args := g.worktreeAddArgs()
+	switch {
+	case opts.Detach:
+		args = append(args, "--detach", path)
+		if opts.StartPoint != "" {
+			args = append(args, opts.StartPoint)
+		}
+	case opts.Branch == "":
+		return fmt.Errorf("adding worktree %s: branch required", path)
+	case opts.NewBranch:
+		args = append(args, "-b", opts.Branch, path)
+		if opts.StartPoint != "" {
+			args = append(args, opts.StartPoint)
+		}
+	default:
+		if !opts.Force {
+			worktrees, err := g.ListWorktrees()
+			if err != nil {
+				return err
+			}
+			for _, wt := range worktrees {
+				if wt.Branch == opts.Branch {
+					return fmt.Errorf("%w: %s is checked out at %s", ErrBranchCheckedOut, opts.Branch, wt.Path)
+				}
+			}
+		} else {
+			args = append(args, "--force")
+		}
+		args = append(args, path, opts.Branch)
+	}
+
+	if _, err := g.runWithEnv(g.checkoutEnv(), args...); err != nil {
+		return err
+	}
+	return g.finishCheckout(path)
+}
+
+// DirtyWorktreeError reports the uncommitted work that kept a worktree from
+// being removed. It matches ErrWorktreeDirty.
+type DirtyWorktreeError struct {
+	Path   string
+	Status *UncommittedWorkStatus
+}
+
+func (e *DirtyWorktreeError) Error() string {
+	return fmt.Sprintf("%v: %s has %s", ErrWorktreeDirty, e.Path, e.Status)
+}
+
+func (e *DirtyWorktreeError) Is(target error) bool {
+	return target == ErrWorktreeDirty
+}
+
+// WorktreeStatus reports the uncommitted work in a worktree: modified and
+// untracked files, and commits not pushed to its upstream. Stashes are shared
+// by all worktrees of a repository, so they are not counted.
+func WorktreeStatus(path string) (*UncommittedWorkStatus, error) {
+	wg := NewGit(path)
+	status := &UncommittedWorkStatus{}
+	gitStatus, err := wg.Status()
+	if err != nil {
+		return nil, fmt.Errorf("checking git status: %w", err)
+	}
+	status.HasUncommittedChanges = !gitStatus.Clean
+	status.ModifiedFiles = gitStatus.ChangedFiles()
+	status.UntrackedFiles = gitStatus.Untracked
+
+	unpushed, err := wg.UnpushedCommits()
+	if err != nil {
+		return nil, fmt.Errorf("checking unpushed commits: %w", err)
+	}
+	status.UnpushedCommits = unpushed
+	return status, nil
+}
+
+// RemoveWorktree removes a linked worktree of the repository. Unless force
+// is set, it refuses a worktree with uncommitted changes, untracked files,
+// or unpushed commits, returning a *DirtyWorktreeError. A worktree whose
+// directory is already gone has just its entry removed; other stale entries
+// are left for PruneWorktrees.
+func (g *Git) RemoveWorktree(path string, force bool) error {
+	wt, err := g.findWorktree(path)
+	if err != nil {
+		return err
+	}
+	if wt.Prunable != "" {
+		return g.removeWorktree(wt.Path, true)
+	}
+	if !force {
+		status, err := WorktreeStatus(wt.Path)
+		if err != nil {
+			return err
+		}
+		if !status.Clean() {
+			return &DirtyWorktreeError{Path: wt.Path, Status: status}
+		}
+	}
+	return g.removeWorktree(wt.Path, force)
+}
+
+// findWorktree returns the linked worktree at path.
+func (g *Git) findWorktree(path string) (*Worktree, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	worktrees, err := g.ListWorktrees()
+	if err != nil {
+		return nil, err
+	}
+	for i, wt := range worktrees {
+		wtPath := wt.Path
+		if resolved, err := filepath.EvalSymlinks(wtPath); err == nil {
+			wtPath = resolved
+		}
+		if wtPath == abs {
+			if i == 0 {
+				// The first entry is the main worktree (or bare repo)
+				break
+			}
+			return &worktrees[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotWorktree, path)
+}
+
+// removeWorktree runs git worktree remove without checking for uncommitted
+// work.
+func (g *Git) removeWorktree(path string, force bool) error {
 	args := []string{"worktree", "remove", path}
 	if force {
 		args = append(args, "--force")
@@ -785,21 +1343,46 @@ func (g *Git) WorktreeRemove(path string, force bool) error {
 	return err
 }
 
-// WorktreePrune removes worktree entries for deleted paths.
-func (g *Git) WorktreePrune() error {
-	_, err := g.run("worktree", "prune")
-	return err
+// PruneWorktrees removes the administrative entries of worktrees whose
+// directories were deleted, returning the paths they were at. With dryRun
+// nothing is removed. Locked worktrees are never pruned.
+// ZFC: Prunable worktrees come from porcelain output (worktree list), not
+// from parsing prune's messages.
+func (g *Git) PruneWorktrees(dryRun bool) ([]string, error) {
+	worktrees, err := g.ListWorktrees()
+	if err != nil {
+		return nil, err
+	}
+	var pruned []string
+	for _, wt := range worktrees {
+		if wt.Prunable != "" && wt.Locked == "" {
+			pruned = append(pruned, wt.Path)
+		}
+	}
+	if dryRun || len(pruned) == 0 {
+		return pruned, nil
+	}
+	if _, err := g.run("worktree", "prune"); err != nil {
+		return nil, err
+	}
+	return pruned, nil
 }
 
 // Worktree represents a git worktree.
 type Worktree struct {
 	Path   string
-	Branch string
+	Branch string // empty if detached or bare
 	Commit string
+
+	Bare     bool
+	Detached bool
+	Locked   string // lock reason ("locked" if none given); empty if unlocked
+	Prunable string // why git considers it prunable; empty if not
 }
 
-// WorktreeList returns all worktrees for this repository.
-func (g *Git) WorktreeList() ([]Worktree, error) {
+// ListWorktrees returns all worktrees for this repository, the main
+// worktree first.
+func (g *Git) ListWorktrees() ([]Worktree, error) {
 	out, err := g.run("worktree", "list", "--porcelain")
 	if err != nil {
 		return nil, err
@@ -824,6 +1407,18 @@ func (g *Git) WorktreeList() ([]Worktree, error) {
 			current.Commit = strings.TrimPrefix(line, "HEAD ")
 		case strings.HasPrefix(line, "branch "):
 			current.Branch = strings.TrimPrefix(line, "branch refs/heads/")
+		case line == "bare":
+			current.Bare = true
+		case line == "detached":
+			current.Detached = true
+		case line == "locked":
+			current.Locked = "locked"
+		case strings.HasPrefix(line, "locked "):
+			current.Locked = strings.TrimPrefix(line, "locked ")
+		case line == "prunable":
+			current.Prunable = "prunable"
+		case strings.HasPrefix(line, "prunable "):
+			current.Prunable = strings.TrimPrefix(line, "prunable ")
 		}
 	}
 
@@ -890,6 +1485,60 @@ func (g *Git) CommitsAhead(base, branch string) (int, error) {
 	return count, nil
 }
 
+// CommitSubjects returns the subject lines of commits on branch that are not on base,
+// oldest first.
+func (g *Git) CommitSubjects(base, branch string) ([]string, error) {
+	out, err := g.run("log", "--reverse", "--format=%s", base+".."+branch)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// CommitHashes returns the full hashes of commits on branch that are not on base,
+// oldest first.
+func (g *Git) CommitHashes(base, branch string) ([]string, error) {
+	out, err := g.run("rev-list", "--reverse", base+".."+branch)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// CommitMessages returns the full messages of commits on branch that are not on base.
+func (g *Git) CommitMessages(base, branch string) ([]string, error) {
+	out, err := g.run("log", "--format=%B%x00", base+".."+branch)
+	if err != nil {
+		return nil, err
+	}
+	var msgs []string
+	for _, msg := range strings.Split(out, "\x00") {
+		if msg = strings.TrimSpace(msg); msg != "" {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs, nil
+}
+
+// ChangedFiles returns the files added or modified on branch since it
+// diverged from base.
+func (g *Git) ChangedFiles(base, branch string) ([]string, error) {
+	out, err := g.run("diff", "--name-only", "--diff-filter=AM", base+"..."+branch)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
 // CountCommitsBehind returns the number of commits that HEAD is behind the given ref.
 // For example, CountCommitsBehind("origin/main") returns how many commits
 // are on origin/main that are not on the current HEAD.
@@ -1001,8 +1650,7 @@ func (g *Git) CheckUncommittedWork() (*UncommittedWorkStatus, error) {
 		return nil, fmt.Errorf("checking git status: %w", err)
 	}
 	status.HasUncommittedChanges = !gitStatus.Clean
-	status.ModifiedFiles = append(gitStatus.Modified, gitStatus.Added...)
-	status.ModifiedFiles = append(status.ModifiedFiles, gitStatus.Deleted...)
+	status.ModifiedFiles = gitStatus.ChangedFiles()
 	status.UntrackedFiles = gitStatus.Untracked
 
 	// Check stashes
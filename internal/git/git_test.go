@@ -1,9 +1,11 @@
 package git
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -88,6 +90,27 @@ func TestCloneWithReferenceCreatesAlternates(t *testing.T) {
 	}
 }
 
+func TestCloneBareConfiguresCredentialHelper(t *testing.T) {
+	tmp := t.TempDir()
+	src := initTestRepo(t)
+	dst := filepath.Join(tmp, "dst.git")
+
+	helper := `!f() { echo "password=$GITLAB_TOKEN"; }; f`
+	g := NewGit(tmp)
+	g.SetCredentialHelper(helper)
+	if err := g.CloneBare(src, dst); err != nil {
+		t.Fatalf("CloneBare: %v", err)
+	}
+
+	out, err := exec.Command("git", "--git-dir", dst, "config", "credential.helper").Output()
+	if err != nil {
+		t.Fatalf("reading credential.helper: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != helper {
+		t.Errorf("credential.helper = %q, want %q", got, helper)
+	}
+}
+
 func TestCurrentBranch(t *testing.T) {
 	dir := initTestRepo(t)
 	g := NewGit(dir)
@@ -134,6 +157,117 @@ func TestStatus(t *testing.T) {
 	}
 }
 
+func TestStatusBranchAndFiles(t *testing.T) {
+	src := initTestRepo(t)
+	tmp := t.TempDir()
+	dir := filepath.Join(tmp, "clone")
+	if err := NewGit(tmp).Clone(src, dir); err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	g := NewGit(dir)
+	_, _ = g.run("config", "user.email", "test@test.com")
+	_, _ = g.run("config", "user.name", "Test User")
+
+	// One local commit ahead of origin
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add("a.txt"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := g.Commit("add a"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	// Staged, unstaged, and untracked changes
+	if err := os.WriteFile(filepath.Join(dir, "b file.txt"), []byte("b"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add("b file.txt"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := g.Status()
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	branch, _ := g.CurrentBranch()
+	if status.Branch != branch || status.Detached {
+		t.Errorf("Branch = %q (detached=%v), want %q", status.Branch, status.Detached, branch)
+	}
+	if status.Upstream != "origin/"+branch || status.Ahead != 1 || status.Behind != 0 {
+		t.Errorf("upstream = %q +%d -%d, want origin/%s +1 -0", status.Upstream, status.Ahead, status.Behind, branch)
+	}
+	if strings.Join(status.Staged, ",") != "b file.txt" {
+		t.Errorf("Staged = %q, want [b file.txt]", status.Staged)
+	}
+	if strings.Join(status.Unstaged, ",") != "README.md" {
+		t.Errorf("Unstaged = %q, want [README.md]", status.Unstaged)
+	}
+	if strings.Join(status.Untracked, ",") != "new.txt" {
+		t.Errorf("Untracked = %q, want [new.txt]", status.Untracked)
+	}
+	if len(status.Modified) != 1 || len(status.Added) != 1 {
+		t.Errorf("Modified = %q, Added = %q, want one each", status.Modified, status.Added)
+	}
+	if status.Merging || status.Rebasing {
+		t.Error("no merge or rebase should be in progress")
+	}
+	if got, want := status.Summary(), "1 staged, 1 unstaged, 1 untracked, ahead 1"; got != want {
+		t.Errorf("Summary = %q, want %q", got, want)
+	}
+}
+
+func TestStatusMergeConflict(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	base, _ := g.CurrentBranch()
+
+	commit := func(content, msg string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.Add("README.md"); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+		if err := g.Commit(msg); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+	}
+	if err := g.CreateBranch("feature"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	commit("base change\n", "base")
+	if err := g.Checkout("feature"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	commit("feature change\n", "feature")
+	if err := g.Checkout(base); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	_ = g.Merge("feature") // conflicts
+
+	status, err := g.Status()
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if !status.Merging || status.Rebasing {
+		t.Errorf("Merging = %v, Rebasing = %v; want merging", status.Merging, status.Rebasing)
+	}
+	if strings.Join(status.Conflicted, ",") != "README.md" || status.Clean {
+		t.Errorf("Conflicted = %q (clean=%v), want [README.md]", status.Conflicted, status.Clean)
+	}
+	if strings.Join(status.ChangedFiles(), ",") != "README.md" {
+		t.Errorf("ChangedFiles = %q, want [README.md]", status.ChangedFiles())
+	}
+}
+
 func TestAddAndCommit(t *testing.T) {
 	dir := initTestRepo(t)
 	g := NewGit(dir)
@@ -242,6 +376,49 @@ func TestRev(t *testing.T) {
 	}
 }
 
+func TestCommitSubjects(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+
+	base, err := g.CurrentBranch()
+	if err != nil {
+		t.Fatalf("CurrentBranch: %v", err)
+	}
+	if err := g.CreateBranch("feature"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := g.Checkout("feature"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	for _, name := range []string{"first", "second"} {
+		if err := os.WriteFile(filepath.Join(dir, name+".txt"), []byte(name), 0644); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+		if err := g.Add(name + ".txt"); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+		if err := g.Commit("add " + name); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+	}
+
+	subjects, err := g.CommitSubjects(base, "feature")
+	if err != nil {
+		t.Fatalf("CommitSubjects: %v", err)
+	}
+	if len(subjects) != 2 || subjects[0] != "add first" || subjects[1] != "add second" {
+		t.Errorf("CommitSubjects = %v, want [add first add second]", subjects)
+	}
+
+	subjects, err = g.CommitSubjects("feature", base)
+	if err != nil {
+		t.Fatalf("CommitSubjects: %v", err)
+	}
+	if len(subjects) != 0 {
+		t.Errorf("CommitSubjects = %v, want empty", subjects)
+	}
+}
+
 func TestFetchBranch(t *testing.T) {
 	// Create a "remote" repo
 	remoteDir := t.TempDir()
@@ -399,7 +576,7 @@ func TestCheckConflicts_WithConflict(t *testing.T) {
 // TestCloneBareHasOriginRefs verifies that after CloneBare, origin/* refs
 // are available for worktree creation. This was broken before the fix:
 // bare clones had refspec configured but no fetch was run, so origin/main
-// didn't exist and WorktreeAddFromRef("origin/main") failed.
+// didn't exist and AddWorktree from origin/main failed.
 //
 // Related: GitHub issue #286
 func TestCloneBareHasOriginRefs(t *testing.T) {
@@ -466,11 +643,11 @@ func TestCloneBareHasOriginRefs(t *testing.T) {
 		t.Errorf("expected %q in remote branches, got: %s", originMain, out)
 	}
 
-	// Verify WorktreeAddFromRef succeeds with origin/main
+	// Verify AddWorktree succeeds with origin/main
 	// This is what polecat creation does
 	worktreePath := filepath.Join(tmp, "worktree")
-	if err := bareGit.WorktreeAddFromRef(worktreePath, "test-branch", originMain); err != nil {
-		t.Errorf("WorktreeAddFromRef(%q) failed: %v", originMain, err)
+	if err := bareGit.AddWorktree(worktreePath, AddWorktreeOptions{Branch: "test-branch", NewBranch: true, StartPoint: originMain}); err != nil {
+		t.Errorf("AddWorktree from %q failed: %v", originMain, err)
 	}
 
 	// Verify the worktree was created and has the expected file
@@ -488,3 +665,391 @@ func stringContains(s, substr string) bool {
 	}
 	return false
 }
+
+func TestWorktreeCredentialHelper(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	const helper = "!f() { echo username=x; echo password=$TOKEN; }; f"
+	g.SetCredentialHelper(helper)
+
+	wtPath := filepath.Join(t.TempDir(), "polecat")
+	if err := g.AddWorktree(wtPath, AddWorktreeOptions{Branch: "polecat/toast", NewBranch: true}); err != nil {
+		t.Fatalf("AddWorktree: %v", err)
+	}
+	// Configuring again replaces rather than stacks the helper
+	if err := NewGit(wtPath).ConfigureCredentialHelper(helper); err != nil {
+		t.Fatalf("ConfigureCredentialHelper: %v", err)
+	}
+	out, err := NewGit(wtPath).run("config", "--local", "--get-all", "credential.helper")
+	if err != nil || out != helper {
+		t.Errorf("credential.helper = %q (%v), want %q", out, err, helper)
+	}
+}
+
+func TestAddWorktree(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	main, _ := g.CurrentBranch()
+
+	wtPath := filepath.Join(t.TempDir(), "feature")
+	if err := g.AddWorktree(wtPath, AddWorktreeOptions{Branch: "feature", NewBranch: true}); err != nil {
+		t.Fatalf("AddWorktree: %v", err)
+	}
+	if branch, _ := NewGit(wtPath).CurrentBranch(); branch != "feature" {
+		t.Errorf("worktree branch = %q, want feature", branch)
+	}
+
+	// Non-empty path is refused
+	if err := g.AddWorktree(wtPath, AddWorktreeOptions{Branch: "other", NewBranch: true}); !errors.Is(err, ErrWorktreePathExists) {
+		t.Errorf("AddWorktree over existing path: err = %v, want ErrWorktreePathExists", err)
+	}
+
+	// A branch checked out elsewhere is refused unless forced
+	second := filepath.Join(t.TempDir(), "second")
+	err := g.AddWorktree(second, AddWorktreeOptions{Branch: main})
+	if !errors.Is(err, ErrBranchCheckedOut) {
+		t.Errorf("AddWorktree of checked-out branch: err = %v, want ErrBranchCheckedOut", err)
+	}
+	if err := g.AddWorktree(second, AddWorktreeOptions{Branch: main, Force: true}); err != nil {
+		t.Errorf("forced AddWorktree: %v", err)
+	}
+
+	detached := filepath.Join(t.TempDir(), "detached")
+	if err := g.AddWorktree(detached, AddWorktreeOptions{Detach: true}); err != nil {
+		t.Fatalf("detached AddWorktree: %v", err)
+	}
+
+	worktrees, err := g.ListWorktrees()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(worktrees) != 4 {
+		t.Fatalf("ListWorktrees = %+v, want 4 worktrees", worktrees)
+	}
+	if worktrees[1].Branch != "feature" || !worktrees[3].Detached || worktrees[3].Branch != "" {
+		t.Errorf("ListWorktrees = %+v", worktrees)
+	}
+}
+
+func TestRemoveWorktree(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	wtPath := filepath.Join(t.TempDir(), "feature")
+	if err := g.AddWorktree(wtPath, AddWorktreeOptions{Branch: "feature", NewBranch: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Untracked work blocks removal
+	if err := os.WriteFile(filepath.Join(wtPath, "wip.txt"), []byte("wip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := g.RemoveWorktree(wtPath, false)
+	var dirty *DirtyWorktreeError
+	if !errors.Is(err, ErrWorktreeDirty) || !errors.As(err, &dirty) || len(dirty.Status.UntrackedFiles) != 1 {
+		t.Fatalf("RemoveWorktree dirty: err = %v, want DirtyWorktreeError with 1 untracked file", err)
+	}
+	if _, err := os.Stat(wtPath); err != nil {
+		t.Fatal("dirty worktree was removed")
+	}
+
+	if err := g.RemoveWorktree(wtPath, true); err != nil {
+		t.Fatalf("forced RemoveWorktree: %v", err)
+	}
+	if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+		t.Error("worktree still exists after forced remove")
+	}
+
+	// The main worktree is not a removable worktree
+	if err := g.RemoveWorktree(dir, true); !errors.Is(err, ErrNotWorktree) {
+		t.Errorf("RemoveWorktree(main): err = %v, want ErrNotWorktree", err)
+	}
+}
+
+func TestRemoveWorktree_MissingDirectory(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	gone := filepath.Join(t.TempDir(), "gone")
+	other := filepath.Join(t.TempDir(), "other")
+	for _, wt := range []string{gone, other} {
+		if err := g.AddWorktree(wt, AddWorktreeOptions{Branch: filepath.Base(wt), NewBranch: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.RemoveAll(wt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := g.RemoveWorktree(gone, false); err != nil {
+		t.Fatalf("RemoveWorktree of missing directory: %v", err)
+	}
+
+	// Only the target entry goes; other stale entries are left alone
+	worktrees, err := g.ListWorktrees()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(worktrees) != 2 || filepath.Base(worktrees[1].Path) != "other" {
+		t.Errorf("ListWorktrees = %+v, want main and other", worktrees)
+	}
+}
+
+func TestPruneWorktrees(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	wtPath := filepath.Join(t.TempDir(), "gone")
+	if err := g.AddWorktree(wtPath, AddWorktreeOptions{Branch: "gone", NewBranch: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(wtPath); err != nil {
+		t.Fatal(err)
+	}
+
+	pruned, err := g.PruneWorktrees(true)
+	if err != nil || len(pruned) != 1 || filepath.Base(pruned[0]) != "gone" {
+		t.Fatalf("PruneWorktrees(dry run) = %v, %v; want [.../gone]", pruned, err)
+	}
+	if worktrees, _ := g.ListWorktrees(); len(worktrees) != 2 || worktrees[1].Prunable == "" {
+		t.Errorf("dry run pruned or lost prunable state: %+v", worktrees)
+	}
+
+	if _, err := g.PruneWorktrees(false); err != nil {
+		t.Fatal(err)
+	}
+	if worktrees, _ := g.ListWorktrees(); len(worktrees) != 1 {
+		t.Errorf("ListWorktrees after prune = %+v, want main only", worktrees)
+	}
+}
+
+func TestCloneShallowAndUnshallow(t *testing.T) {
+	src := initTestRepo(t)
+	srcGit := NewGit(src)
+	for _, name := range []string{"second", "third"} {
+		if err := os.WriteFile(filepath.Join(src, name+".txt"), []byte(name), 0644); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+		if err := srcGit.Add(name + ".txt"); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+		if err := srcGit.Commit("add " + name); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+	}
+
+	tmp := t.TempDir()
+	dst := filepath.Join(tmp, "dst")
+	g := NewGit(tmp)
+	g.SetCloneOptions(CloneOptions{Depth: 1})
+	// Local paths ignore --depth; a file:// URL goes through the transport
+	if err := g.Clone("file://"+src, dst); err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+
+	dstGit := NewGit(dst)
+	countCommits := func() string {
+		out, err := dstGit.run("rev-list", "--count", "HEAD")
+		if err != nil {
+			t.Fatalf("rev-list: %v", err)
+		}
+		return out
+	}
+	if shallow, err := dstGit.IsShallow(); err != nil || !shallow {
+		t.Fatalf("IsShallow = %v, %v; want true", shallow, err)
+	}
+	if got := countCommits(); got != "1" {
+		t.Errorf("shallow clone has %s commits, want 1", got)
+	}
+
+	if err := dstGit.Unshallow(); err != nil {
+		t.Fatalf("Unshallow: %v", err)
+	}
+	if shallow, _ := dstGit.IsShallow(); shallow {
+		t.Error("still shallow after Unshallow")
+	}
+	if got := countCommits(); got != "3" {
+		t.Errorf("unshallowed clone has %s commits, want 3", got)
+	}
+	// Unshallow on a full clone is a no-op
+	if err := dstGit.Unshallow(); err != nil {
+		t.Errorf("second Unshallow: %v", err)
+	}
+}
+
+func TestCloneBarePartial(t *testing.T) {
+	src := initTestRepo(t)
+	if _, err := NewGit(src).run("config", "uploadpack.allowfilter", "true"); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+
+	tmp := t.TempDir()
+	dst := filepath.Join(tmp, "dst.git")
+	g := NewGit(tmp)
+	g.SetCloneOptions(CloneOptions{Filter: "blob:none", SingleBranch: true})
+	if err := g.CloneBare("file://"+src, dst); err != nil {
+		t.Fatalf("CloneBare: %v", err)
+	}
+
+	out, err := exec.Command("git", "--git-dir", dst, "config", "remote.origin.partialclonefilter").Output()
+	if err != nil {
+		t.Fatalf("reading partialclonefilter: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "blob:none" {
+		t.Errorf("partialclonefilter = %q, want blob:none", got)
+	}
+	out, err = exec.Command("git", "--git-dir", dst, "config", "remote.origin.fetch").Output()
+	if err != nil {
+		t.Fatalf("reading remote.origin.fetch: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); strings.Contains(got, "*") {
+		t.Errorf("single-branch refspec = %q, want one branch", got)
+	}
+}
+
+func TestCloneSubmodules(t *testing.T) {
+	// Git refuses local-file submodule transport by default
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	lib := initTestRepo(t)
+	src := initTestRepo(t)
+	srcGit := NewGit(src)
+	if _, err := srcGit.run("submodule", "add", lib, "lib"); err != nil {
+		t.Fatalf("submodule add: %v", err)
+	}
+	if err := srcGit.Commit("add lib"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	tmp := t.TempDir()
+	g := NewGit(tmp)
+	if err := g.Clone(src, filepath.Join(tmp, "with")); err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "with", "lib", "README.md")); err != nil {
+		t.Errorf("submodule not checked out: %v", err)
+	}
+
+	g.SetCloneOptions(CloneOptions{NoSubmodules: true})
+	if err := g.Clone(src, filepath.Join(tmp, "without")); err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "without", "lib", "README.md")); !os.IsNotExist(err) {
+		t.Errorf("NoSubmodules clone checked out lib (err=%v)", err)
+	}
+
+	// A pull brings the submodule to the superproject's new commit
+	libGit := NewGit(lib)
+	if err := os.WriteFile(filepath.Join(lib, "v2.txt"), []byte("v2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_ = libGit.Add("v2.txt")
+	_ = libGit.Commit("v2")
+	if _, err := NewGit(filepath.Join(src, "lib")).run("pull", "origin"); err != nil {
+		t.Fatalf("pull in submodule: %v", err)
+	}
+	_ = srcGit.Add("lib")
+	_ = srcGit.Commit("bump lib")
+	branch, _ := srcGit.CurrentBranch()
+	if err := NewGit(filepath.Join(tmp, "with")).Pull("origin", branch); err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "with", "lib", "v2.txt")); err != nil {
+		t.Errorf("Pull did not update the submodule: %v", err)
+	}
+}
+
+func TestCloneOptionsArgs(t *testing.T) {
+	tests := []struct {
+		opts CloneOptions
+		want string
+	}{
+		{CloneOptions{}, ""},
+		{CloneOptions{Depth: 1}, "--depth=1 --no-single-branch"},
+		{CloneOptions{Depth: 5, SingleBranch: true, Branch: "dev"}, "--depth=5 --single-branch --branch=dev"},
+		{CloneOptions{Filter: "blob:none"}, "--filter=blob:none"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(tt.opts.args(), " "); got != tt.want {
+			t.Errorf("%+v args = %q, want %q", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestSparsePaths(t *testing.T) {
+	src := initTestRepo(t)
+	srcGit := NewGit(src)
+	for _, f := range []string{"services/api/main.go", "services/web/main.go", "services/README.md", "lib/util.go", "CLAUDE.md"} {
+		path := filepath.Join(src, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(f), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := srcGit.Add("."); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := srcGit.Commit("monorepo"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	check := func(t *testing.T, dir string) {
+		t.Helper()
+		for f, want := range map[string]bool{
+			"README.md":            true,
+			"services/README.md":   true,
+			"services/api/main.go": true,
+			"services/web/main.go": false,
+			"lib/util.go":          false,
+			"CLAUDE.md":            false,
+		} {
+			_, err := os.Stat(filepath.Join(dir, f))
+			if got := err == nil; got != want {
+				t.Errorf("%s exists = %v, want %v", f, got, want)
+			}
+		}
+	}
+
+	t.Run("clone", func(t *testing.T) {
+		tmp := t.TempDir()
+		dst := filepath.Join(tmp, "dst")
+		g := NewGit(tmp)
+		g.SetSparsePaths([]string{"services/api/"})
+		if err := g.Clone(src, dst); err != nil {
+			t.Fatalf("Clone: %v", err)
+		}
+		check(t, dst)
+	})
+
+	t.Run("worktree", func(t *testing.T) {
+		wt := filepath.Join(t.TempDir(), "wt")
+		g := NewGit(src)
+		g.SetSparsePaths([]string{"services/api"})
+		if err := g.AddWorktree(wt, AddWorktreeOptions{Branch: "sparse", NewBranch: true}); err != nil {
+			t.Fatalf("AddWorktree: %v", err)
+		}
+		check(t, wt)
+		if !IsSparseCheckoutConfigured(wt) {
+			t.Error("IsSparseCheckoutConfigured = false, want true")
+		}
+	})
+}
+
+func TestSparsePatterns(t *testing.T) {
+	exclusions := "!/.claude/\n!/CLAUDE.md\n!/CLAUDE.local.md\n!/.mcp.json\n"
+	tests := []struct {
+		paths []string
+		want  string
+	}{
+		{nil, "/*\n"},
+		{[]string{"lib"}, "/*\n!/*/\n/lib/\n"},
+		{[]string{"services/api", "services", "./docs/"}, "/*\n!/*/\n/docs/\n/services/\n"},
+		{[]string{"a/b/c", "a/d"}, "/*\n!/*/\n/a/\n!/a/*/\n/a/b/\n!/a/b/*/\n/a/b/c/\n/a/d/\n"},
+	}
+	for _, tt := range tests {
+		if got := sparsePatterns(tt.paths); got != tt.want+exclusions {
+			t.Errorf("sparsePatterns(%q) = %q, want %q", tt.paths, got, tt.want+exclusions)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// ErrPushGuarded is returned when Push refuses a force-push or a push to a
+// protected branch that was not explicitly allowed.
+var ErrPushGuarded = errors.New("push refused by guard")
+
+// DefaultProtectedBranches are the branches Push refuses to update unless
+// the push allows it, for repos without their own protected_branches.
+var DefaultProtectedBranches = []string{"main", "master", "release/*"}
+
+// PushOptions controls a push and its guard overrides. Agents push their own
+// branches; only the merge path (the Refinery, integration branch landing)
+// should set AllowProtected, and nothing should set AllowForce casually.
+type PushOptions struct {
+	Force bool // git push --force
+
+	// AllowForce permits Force, and AllowProtected permits pushing to a
+	// protected branch. Overridden pushes are recorded in the audit log.
+	AllowForce     bool
+	AllowProtected bool
+}
+
+// PushPermissions limit the pushes the guard allows for the calling agent's
+// role, whatever its PushOptions say. The zero value limits nothing.
+type PushPermissions struct {
+	NoPush      bool   // refuse every push (read-only roles)
+	NoProtected bool   // refuse pushes to protected branches, even if allowed
+	Profile     string // permission profile the limits come from, for messages
+}
+
+// rolePush is the calling agent's push permissions.
+var rolePush PushPermissions
+
+// SetPushPermissions limits every Git's pushes to p (from the role's
+// permission profile, applied at startup).
+func SetPushPermissions(p PushPermissions) {
+	rolePush = p
+}
+
+// PushGuardError is a push refused by the guard. It matches ErrPushGuarded.
+type PushGuardError struct {
+	Remote string
+	Branch string
+	Reason string // e.g., "force-push" or "protected branch (matches release/*)"
+}
+
+func (e *PushGuardError) Error() string {
+	return fmt.Sprintf("refusing to push %s to %s: %s", e.Branch, e.Remote, e.Reason)
+}
+
+// Is reports whether target is ErrPushGuarded.
+func (e *PushGuardError) Is(target error) bool {
+	return target == ErrPushGuarded
+}
+
+// SetProtectedBranches sets the branch patterns (path.Match syntax, e.g.
+// "release/*") Push guards. Nil uses DefaultProtectedBranches.
+func (g *Git) SetProtectedBranches(patterns []string) {
+	g.protectedBranches = patterns
+}
+
+// ProtectedBranchPattern returns the pattern protecting branch, or "" if the
+// branch is not protected.
+func (g *Git) ProtectedBranchPattern(branch string) string {
+	patterns := g.protectedBranches
+	if patterns == nil {
+		patterns = DefaultProtectedBranches
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return pattern
+		}
+	}
+	return ""
+}
+
+// guardPush checks a push against the guard, recording refusals and
+// overrides in the audit log.
+func (g *Git) guardPush(remote, branch string, opts PushOptions) error {
+	var reasons, overrides []string
+	if rolePush.NoPush {
+		reasons = append(reasons, fmt.Sprintf("the %s profile does not permit pushing", rolePush.Profile))
+	}
+	if opts.Force {
+		if opts.AllowForce {
+			overrides = append(overrides, "force-push")
+		} else {
+			reasons = append(reasons, "force-push")
+		}
+	}
+	if pattern := g.ProtectedBranchPattern(branch); pattern != "" {
+		what := "protected branch"
+		if pattern != branch {
+			what = fmt.Sprintf("protected branch (matches %s)", pattern)
+		}
+		switch {
+		case opts.AllowProtected && rolePush.NoProtected:
+			reasons = append(reasons, fmt.Sprintf("%s, not permitted by the %s profile", what, rolePush.Profile))
+		case opts.AllowProtected:
+			overrides = append(overrides, what)
+		default:
+			reasons = append(reasons, what)
+		}
+	}
+
+	payload := map[string]interface{}{
+		"remote": remote,
+		"branch": branch,
+		"force":  opts.Force,
+		"dir":    g.workDir,
+	}
+	actor := os.Getenv("BD_ACTOR")
+	if len(reasons) > 0 {
+		payload["reasons"] = reasons
+		_ = events.LogAudit(events.TypePushBlocked, actor, payload)
+		err := &PushGuardError{Remote: remote, Branch: branch, Reason: reasons[0]}
+		for _, r := range reasons[1:] {
+			err.Reason += ", " + r
+		}
+		return err
+	}
+	if len(overrides) > 0 {
+		payload["overrides"] = overrides
+		_ = events.LogAudit(events.TypePushOverride, actor, payload)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package git
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProtectedBranchPattern(t *testing.T) {
+	g := NewGit(t.TempDir())
+	tests := []struct {
+		branch, want string
+	}{
+		{"main", "main"},
+		{"master", "master"},
+		{"release/1.2", "release/*"},
+		{"release", ""},
+		{"polecat/toast-abc", ""},
+	}
+	for _, tt := range tests {
+		if got := g.ProtectedBranchPattern(tt.branch); got != tt.want {
+			t.Errorf("ProtectedBranchPattern(%q) = %q, want %q", tt.branch, got, tt.want)
+		}
+	}
+
+	g.SetProtectedBranches([]string{"develop"})
+	if g.ProtectedBranchPattern("main") != "" || g.ProtectedBranchPattern("develop") != "develop" {
+		t.Error("SetProtectedBranches should replace the defaults")
+	}
+}
+
+// setupGuardedRepo returns a repo inside a town (so the audit log lands in
+// townRoot/.events.jsonl) with a bare origin.
+func setupGuardedRepo(t *testing.T) (g *Git, branch, townRoot string) {
+	t.Helper()
+	townRoot = t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"type":"town","version":1,"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	src := initTestRepo(t)
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	if out, err := exec.Command("git", "clone", "--bare", src, remote).CombinedOutput(); err != nil {
+		t.Fatalf("clone --bare: %v: %s", err, out)
+	}
+	g = NewGit(src)
+	if _, err := g.run("remote", "add", "origin", remote); err != nil {
+		t.Fatalf("remote add: %v", err)
+	}
+	branch, _ = g.CurrentBranch()
+	g.SetProtectedBranches([]string{branch})
+
+	t.Chdir(townRoot)
+	t.Setenv("BD_ACTOR", "gastown/polecats/toast")
+	return g, branch, townRoot
+}
+
+func auditEvents(t *testing.T, townRoot string) []map[string]interface{} {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(townRoot, ".events.jsonl"))
+	if err != nil {
+		return nil
+	}
+	var events []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var e map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("parsing event %q: %v", line, err)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestPushGuardProtectedBranch(t *testing.T) {
+	g, branch, townRoot := setupGuardedRepo(t)
+
+	err := g.Push("origin", branch, false)
+	var guardErr *PushGuardError
+	if !errors.As(err, &guardErr) || !errors.Is(err, ErrPushGuarded) {
+		t.Fatalf("Push to protected %s = %v, want *PushGuardError", branch, err)
+	}
+	if guardErr.Reason != "protected branch" {
+		t.Errorf("Reason = %q, want %q", guardErr.Reason, "protected branch")
+	}
+
+	if err := g.PushWithOptions("origin", branch, PushOptions{AllowProtected: true}); err != nil {
+		t.Fatalf("Push with AllowProtected = %v", err)
+	}
+
+	events := auditEvents(t, townRoot)
+	if len(events) != 2 {
+		t.Fatalf("got %d audit events, want 2: %v", len(events), events)
+	}
+	if events[0]["type"] != "push_blocked" || events[0]["actor"] != "gastown/polecats/toast" {
+		t.Errorf("first event = %v, want push_blocked by gastown/polecats/toast", events[0])
+	}
+	if events[1]["type"] != "push_override" {
+		t.Errorf("second event = %v, want push_override", events[1])
+	}
+}
+
+func TestPushGuardForce(t *testing.T) {
+	g, _, townRoot := setupGuardedRepo(t)
+	if _, err := g.run("checkout", "-b", "polecat/toast"); err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+
+	if err := g.Push("origin", "polecat/toast", false); err != nil {
+		t.Fatalf("Push to unprotected branch = %v", err)
+	}
+	if err := g.Push("origin", "polecat/toast", true); !errors.Is(err, ErrPushGuarded) {
+		t.Fatalf("force Push = %v, want ErrPushGuarded", err)
+	}
+	if err := g.PushWithOptions("origin", "polecat/toast", PushOptions{Force: true, AllowForce: true}); err != nil {
+		t.Fatalf("force Push with AllowForce = %v", err)
+	}
+
+	events := auditEvents(t, townRoot)
+	if len(events) != 2 || events[0]["type"] != "push_blocked" || events[1]["type"] != "push_override" {
+		t.Errorf("audit events = %v, want push_blocked then push_override", events)
+	}
+}
+
+func TestPushGuardRolePermissions(t *testing.T) {
+	g, branch, _ := setupGuardedRepo(t)
+	t.Cleanup(func() { SetPushPermissions(PushPermissions{}) })
+
+	SetPushPermissions(PushPermissions{NoProtected: true, Profile: "developer"})
+	err := g.PushWithOptions("origin", branch, PushOptions{AllowProtected: true})
+	var guardErr *PushGuardError
+	if !errors.As(err, &guardErr) || !strings.Contains(guardErr.Reason, "developer profile") {
+		t.Fatalf("AllowProtected push under developer = %v, want refused by the profile", err)
+	}
+
+	SetPushPermissions(PushPermissions{NoPush: true, Profile: "observer"})
+	if _, err := g.run("checkout", "-b", "witness/notes"); err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+	if err := g.Push("origin", "witness/notes", false); !errors.Is(err, ErrPushGuarded) {
+		t.Errorf("Push under observer = %v, want ErrPushGuarded", err)
+	}
+}
@@ -0,0 +1,103 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ErrWorkspaceInvalid is returned when a workspace fails VerifyWorkspace.
+var ErrWorkspaceInvalid = errors.New("workspace failed integrity check")
+
+// VerifyOptions configures VerifyWorkspace.
+type VerifyOptions struct {
+	// RemoteURL is the URL origin must point at. Empty skips the check.
+	RemoteURL string
+
+	// Resuming allows the staged changes, unmerged files, and in-progress
+	// merge or rebase a previous session may have left behind.
+	Resuming bool
+}
+
+// IntegrityError lists the problems VerifyWorkspace found in a workspace.
+// It matches ErrWorkspaceInvalid.
+type IntegrityError struct {
+	Path     string
+	Problems []string
+}
+
+func (e *IntegrityError) Error() string {
+	return fmt.Sprintf("%v: %s: %s", ErrWorkspaceInvalid, e.Path, strings.Join(e.Problems, "; "))
+}
+
+// Is reports whether target is ErrWorkspaceInvalid.
+func (e *IntegrityError) Is(target error) bool {
+	return target == ErrWorkspaceInvalid
+}
+
+// VerifyWorkspace is a quick integrity check to run before starting an
+// agent in the workspace: HEAD must resolve to a readable commit, origin
+// must point at opts.RemoteURL, and the index must be readable and (unless
+// resuming) clean, with no merge or rebase in progress. It is far cheaper
+// than git fsck and catches the corrupted or mis-pointed clones an agent
+// would otherwise flounder in. Returns *IntegrityError listing every problem.
+func (g *Git) VerifyWorkspace(opts VerifyOptions) error {
+	if _, err := g.run("rev-parse", "--git-dir"); err != nil {
+		return &IntegrityError{Path: g.workDir, Problems: []string{"not a git repository"}}
+	}
+
+	var problems []string
+	if _, err := g.run("rev-parse", "--verify", "--quiet", "HEAD^{tree}"); err != nil {
+		problems = append(problems, "HEAD does not resolve to a valid commit")
+	}
+
+	if opts.RemoteURL != "" {
+		// The configured URL, not get-url's, which applies insteadOf rewrites
+		url, err := g.run("config", "--get", "remote.origin.url")
+		switch {
+		case err != nil || url == "":
+			problems = append(problems, "no origin remote")
+		case !sameRemoteURL(url, opts.RemoteURL):
+			problems = append(problems, fmt.Sprintf("origin is %s, want %s", url, opts.RemoteURL))
+		}
+	}
+
+	status, err := g.Status()
+	switch {
+	case err != nil:
+		problems = append(problems, "index is unreadable")
+	case !opts.Resuming:
+		if n := len(status.Conflicted); n > 0 {
+			problems = append(problems, fmt.Sprintf("%d unmerged file(s)", n))
+		}
+		if n := len(status.Staged); n > 0 {
+			problems = append(problems, fmt.Sprintf("%d staged change(s)", n))
+		}
+		if status.Merging {
+			problems = append(problems, "merge in progress")
+		}
+		if status.Rebasing {
+			problems = append(problems, "rebase in progress")
+		}
+	}
+
+	if len(problems) > 0 {
+		return &IntegrityError{Path: g.workDir, Problems: problems}
+	}
+	return nil
+}
+
+// sameRemoteURL reports whether two remote URLs name the same repository,
+// ignoring a trailing slash or ".git" suffix.
+func sameRemoteURL(a, b string) bool {
+	normalize := func(url string) string {
+		url = strings.TrimSuffix(strings.TrimSpace(url), "/")
+		url = strings.TrimSuffix(url, ".git")
+		if filepath.IsAbs(url) {
+			url = filepath.Clean(url)
+		}
+		return url
+	}
+	return normalize(a) == normalize(b)
+}
@@ -0,0 +1,61 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyWorkspace(t *testing.T) {
+	src := initTestRepo(t)
+	dir := filepath.Join(t.TempDir(), "clone")
+	if err := NewGit(t.TempDir()).Clone(src, dir); err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	g := NewGit(dir)
+
+	if err := g.VerifyWorkspace(VerifyOptions{RemoteURL: src + ".git/"}); err != nil {
+		t.Errorf("VerifyWorkspace on a fresh clone = %v", err)
+	}
+
+	err := g.VerifyWorkspace(VerifyOptions{RemoteURL: "https://example.com/other.git"})
+	var ie *IntegrityError
+	if !errors.As(err, &ie) || !errors.Is(err, ErrWorkspaceInvalid) {
+		t.Fatalf("VerifyWorkspace with wrong remote = %v, want *IntegrityError", err)
+	}
+	if len(ie.Problems) != 1 || !strings.HasPrefix(ie.Problems[0], "origin is ") {
+		t.Errorf("Problems = %q, want the origin mismatch", ie.Problems)
+	}
+
+	// Staged changes are allowed only when resuming
+	if err := os.WriteFile(filepath.Join(dir, "wip.txt"), []byte("wip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_ = g.Add("wip.txt")
+	if err := g.VerifyWorkspace(VerifyOptions{}); !errors.Is(err, ErrWorkspaceInvalid) {
+		t.Errorf("VerifyWorkspace with staged changes = %v, want ErrWorkspaceInvalid", err)
+	}
+	if err := g.VerifyWorkspace(VerifyOptions{Resuming: true}); err != nil {
+		t.Errorf("resuming VerifyWorkspace with staged changes = %v", err)
+	}
+}
+
+func TestVerifyWorkspaceCorrupt(t *testing.T) {
+	if err := NewGit(t.TempDir()).VerifyWorkspace(VerifyOptions{}); !errors.Is(err, ErrWorkspaceInvalid) {
+		t.Errorf("VerifyWorkspace outside a repo = %v, want ErrWorkspaceInvalid", err)
+	}
+
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	head, _ := g.Rev("HEAD")
+	if err := os.Remove(filepath.Join(dir, ".git", "objects", head[:2], head[2:])); err != nil {
+		t.Fatal(err)
+	}
+	err := g.VerifyWorkspace(VerifyOptions{Resuming: true})
+	var ie *IntegrityError
+	if !errors.As(err, &ie) || !strings.Contains(strings.Join(ie.Problems, "; "), "HEAD does not resolve") {
+		t.Errorf("VerifyWorkspace with missing HEAD commit = %v, want HEAD problem", err)
+	}
+}
@@ -0,0 +1,85 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ErrLFSNotInstalled is returned when a repo uses Git LFS but git-lfs is
+// not installed, so its LFS files would be left as pointer files.
+var ErrLFSNotInstalled = errors.New("repo uses Git LFS but git-lfs is not installed")
+
+// UsesLFS reports whether the repo tracks files with Git LFS: whether any
+// checked-in .gitattributes assigns filter=lfs.
+func (g *Git) UsesLFS() bool {
+	out, err := g.run("ls-files", "--", ".gitattributes", "*/.gitattributes")
+	if err != nil {
+		return false
+	}
+	for _, path := range strings.Split(out, "\n") {
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(g.workDir, path))
+		if err == nil && strings.Contains(string(data), "filter=lfs") {
+			return true
+		}
+	}
+	return false
+}
+
+// PullLFS installs the LFS filters in the repo's config (so later
+// checkouts and pulls fetch LFS files too) and downloads the LFS files for
+// the current checkout, limited to sparse paths if set. Transient network
+// failures are retried. Returns ErrLFSNotInstalled if git-lfs is missing.
+func (g *Git) PullLFS() error {
+	if _, err := exec.LookPath("git-lfs"); err != nil {
+		return ErrLFSNotInstalled
+	}
+	if _, err := g.run("lfs", "install", "--local"); err != nil {
+		return err
+	}
+	args := []string{"lfs", "pull"}
+	if len(g.sparsePaths) > 0 {
+		include := make([]string, len(g.sparsePaths))
+		for i, dir := range g.sparsePaths {
+			include[i] = strings.Trim(filepath.ToSlash(dir), "/") + "/**"
+		}
+		args = append(args, "--include="+strings.Join(include, ","))
+	}
+	return g.runNetwork(args...)
+}
+
+// checkoutEnv returns the environment for commands that check out files:
+// with CloneOptions.SkipLFS, LFS files stay pointer files.
+func (g *Git) checkoutEnv() []string {
+	if g.cloneOpts.SkipLFS {
+		return []string{"GIT_LFS_SKIP_SMUDGE=1"}
+	}
+	return nil
+}
+
+// provisionLFS fetches LFS files into a new clone or worktree at path,
+// unless the clone options skip LFS or the repo doesn't use it.
+func (g *Git) provisionLFS(path string) error {
+	if g.cloneOpts.SkipLFS {
+		return nil
+	}
+	wt := NewGit(path)
+	wt.sparsePaths = g.sparsePaths
+	wt.retry = g.retry
+	if !wt.UsesLFS() {
+		return nil
+	}
+	if err := wt.PullLFS(); err != nil {
+		if errors.Is(err, ErrLFSNotInstalled) {
+			return fmt.Errorf("%w: install git-lfs, or add the rig with --skip-lfs to work with pointer files", err)
+		}
+		return fmt.Errorf("pulling LFS files: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initLFSRepo returns a test repo whose .gitattributes tracks *.bin with LFS.
+func initLFSRepo(t *testing.T) string {
+	t.Helper()
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	if err := os.WriteFile(filepath.Join(dir, ".gitattributes"), []byte("*.bin filter=lfs diff=lfs merge=lfs -text\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_ = g.Add(".gitattributes")
+	if err := g.Commit("track bins with lfs"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	return dir
+}
+
+func TestUsesLFS(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	if g.UsesLFS() {
+		t.Error("UsesLFS = true for a repo without .gitattributes")
+	}
+
+	// Only checked-in attributes count, including nested ones
+	sub := filepath.Join(dir, "assets")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sub, ".gitattributes"), []byte("*.psd filter=lfs -text\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if g.UsesLFS() {
+		t.Error("UsesLFS = true for an untracked .gitattributes")
+	}
+	_ = g.Add("assets/.gitattributes")
+	if !g.UsesLFS() {
+		t.Error("UsesLFS = false with assets/.gitattributes tracking *.psd")
+	}
+
+	if !NewGit(initLFSRepo(t)).UsesLFS() {
+		t.Error("UsesLFS = false with .gitattributes tracking *.bin")
+	}
+}
+
+func TestCloneLFSNotInstalled(t *testing.T) {
+	if _, err := exec.LookPath("git-lfs"); err == nil {
+		t.Skip("git-lfs is installed")
+	}
+	src := initLFSRepo(t)
+	tmp := t.TempDir()
+	g := NewGit(tmp)
+
+	err := g.Clone(src, filepath.Join(tmp, "clone"))
+	if !errors.Is(err, ErrLFSNotInstalled) || !strings.Contains(err.Error(), "--skip-lfs") {
+		t.Fatalf("Clone = %v, want ErrLFSNotInstalled suggesting --skip-lfs", err)
+	}
+
+	g.SetCloneOptions(CloneOptions{SkipLFS: true})
+	if err := g.Clone(src, filepath.Join(tmp, "pointers")); err != nil {
+		t.Errorf("SkipLFS Clone = %v", err)
+	}
+}
+
+func TestAddWorktreeLFS(t *testing.T) {
+	// A stub git-lfs records how provisioning invokes it
+	bin := t.TempDir()
+	log := filepath.Join(bin, "calls.log")
+	script := "#!/bin/sh\necho \"$@\" >> " + log + "\n"
+	if err := os.WriteFile(filepath.Join(bin, "git-lfs"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	src := initLFSRepo(t)
+	g := NewGit(src)
+	g.SetSparsePaths([]string{"assets/"})
+	if err := g.AddWorktree(filepath.Join(t.TempDir(), "wt"), AddWorktreeOptions{Branch: "lfs-branch", NewBranch: true}); err != nil {
+		t.Fatalf("AddWorktree: %v", err)
+	}
+	data, _ := os.ReadFile(log)
+	if want := "install --local\npull --include=assets/**\n"; string(data) != want {
+		t.Errorf("git-lfs calls = %q, want %q", data, want)
+	}
+
+	_ = os.Remove(log)
+	g.SetCloneOptions(CloneOptions{SkipLFS: true})
+	if err := g.AddWorktree(filepath.Join(t.TempDir(), "wt2"), AddWorktreeOptions{Branch: "lfs-branch-2", NewBranch: true}); err != nil {
+		t.Fatalf("SkipLFS AddWorktree: %v", err)
+	}
+	if data, err := os.ReadFile(log); err == nil {
+		t.Errorf("SkipLFS AddWorktree ran git-lfs: %q", data)
+	}
+}
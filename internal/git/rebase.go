@@ -0,0 +1,190 @@
+package git
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrRebaseConflict is returned when a rebase stops on conflicts.
+var ErrRebaseConflict = errors.New("rebase conflict")
+
+// RebaseOptions controls Rebase.
+type RebaseOptions struct {
+	// AbortOnConflict aborts the rebase after collecting its conflicts,
+	// restoring the branch to where it was. Otherwise the rebase is left in
+	// progress for the caller to resolve or AbortRebase.
+	AbortOnConflict bool
+}
+
+// ConflictFile is a file left conflicted by a merge or rebase.
+type ConflictFile struct {
+	Path string
+	// Hunks are the file's conflict regions, in order. Empty for conflicts
+	// without markers (binary files, modify/delete, rename conflicts).
+	Hunks []ConflictHunk
+}
+
+// ConflictHunk is one conflict region (<<<<<<< ... >>>>>>>) in a file.
+// In a rebase, "ours" is the branch being rebased onto and "theirs" is the
+// commit being replayed.
+type ConflictHunk struct {
+	StartLine int // 1-based line of the <<<<<<< marker
+	EndLine   int // 1-based line of the >>>>>>> marker
+
+	OursLabel   string // label after <<<<<<<, e.g. "HEAD"
+	TheirsLabel string // label after >>>>>>>, e.g. "abc1234 (Add retry)"
+	Ours        string
+	Base        string // merge base, only with merge.conflictStyle=diff3/zdiff3
+	Theirs      string
+}
+
+// RebaseConflictError is a rebase stopped on conflicts. It matches
+// ErrRebaseConflict.
+type RebaseConflictError struct {
+	Onto    string
+	Commit  string // the commit that failed to apply
+	Files   []ConflictFile
+	Aborted bool // the rebase was aborted (RebaseOptions.AbortOnConflict)
+}
+
+func (e *RebaseConflictError) Error() string {
+	paths := make([]string, len(e.Files))
+	for i, f := range e.Files {
+		paths[i] = f.Path
+	}
+	if e.Commit == "" {
+		return fmt.Sprintf("rebase onto %s conflicts: %s", e.Onto, strings.Join(paths, ", "))
+	}
+	return fmt.Sprintf("rebase onto %s conflicts applying %s: %s", e.Onto, shortSHA(e.Commit), strings.Join(paths, ", "))
+}
+
+// Is reports whether target is ErrRebaseConflict.
+func (e *RebaseConflictError) Is(target error) bool {
+	return target == ErrRebaseConflict
+}
+
+// Rebase rebases the current branch onto the given ref.
+// A rebase that stops on conflicts is returned as a *RebaseConflictError
+// (ErrRebaseConflict) listing the conflicted files and their hunks; it is
+// left in progress unless opts.AbortOnConflict is set. A rebase that fails
+// for any other reason is aborted and its *GitError returned.
+func (g *Git) Rebase(onto string, opts RebaseOptions) error {
+	_, rebaseErr := g.run("rebase", onto)
+	if rebaseErr == nil {
+		return nil
+	}
+
+	// ZFC: detect conflicts from git's index, not the rebase output.
+	files, err := g.ConflictFiles()
+	if err != nil || len(files) == 0 {
+		if _, rebasing := g.inProgressOps(); rebasing {
+			_ = g.AbortRebase()
+		}
+		return rebaseErr
+	}
+
+	conflictErr := &RebaseConflictError{Onto: onto, Files: files}
+	if commit, err := g.run("rev-parse", "--verify", "--quiet", "REBASE_HEAD"); err == nil {
+		conflictErr.Commit = commit
+	}
+	if opts.AbortOnConflict {
+		if err := g.AbortRebase(); err != nil {
+			return fmt.Errorf("aborting rebase: %w (after %v)", err, conflictErr)
+		}
+		conflictErr.Aborted = true
+	}
+	return conflictErr
+}
+
+// ConflictFiles returns the files with merge or rebase conflicts, with the
+// conflict hunks parsed from each work tree file.
+func (g *Git) ConflictFiles() ([]ConflictFile, error) {
+	paths, err := g.GetConflictingFiles()
+	if err != nil {
+		return nil, err
+	}
+	files := make([]ConflictFile, 0, len(paths))
+	for _, path := range paths {
+		file := ConflictFile{Path: path}
+		// Best-effort: a deleted side leaves no file to read.
+		if data, err := os.ReadFile(filepath.Join(g.workDir, path)); err == nil {
+			file.Hunks = parseConflictHunks(string(data))
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// parseConflictHunks parses the conflict marker regions in content.
+func parseConflictHunks(content string) []ConflictHunk {
+	const (
+		outside = iota
+		inOurs
+		inBase
+		inTheirs
+	)
+	var (
+		hunks              []ConflictHunk
+		hunk               ConflictHunk
+		ours, base, theirs strings.Builder
+		state              = outside
+		lineNo             int
+	)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNo++
+		switch {
+		case state == outside && isConflictMarker(line, "<<<<<<<"):
+			hunk = ConflictHunk{StartLine: lineNo, OursLabel: markerLabel(line)}
+			ours.Reset()
+			base.Reset()
+			theirs.Reset()
+			state = inOurs
+		case state == inOurs && isConflictMarker(line, "|||||||"):
+			state = inBase
+		case (state == inOurs || state == inBase) && line == "=======":
+			state = inTheirs
+		case state == inTheirs && isConflictMarker(line, ">>>>>>>"):
+			hunk.EndLine = lineNo
+			hunk.TheirsLabel = markerLabel(line)
+			hunk.Ours, hunk.Base, hunk.Theirs = ours.String(), base.String(), theirs.String()
+			hunks = append(hunks, hunk)
+			state = outside
+		case state == inOurs:
+			ours.WriteString(line + "\n")
+		case state == inBase:
+			base.WriteString(line + "\n")
+		case state == inTheirs:
+			theirs.WriteString(line + "\n")
+		}
+	}
+	return hunks
+}
+
+// isConflictMarker reports whether line is the given 7-character conflict
+// marker, alone or followed by a label.
+func isConflictMarker(line, marker string) bool {
+	return line == marker || strings.HasPrefix(line, marker+" ")
+}
+
+// markerLabel returns the label after a conflict marker.
+func markerLabel(line string) string {
+	if len(line) <= 8 {
+		return ""
+	}
+	return line[8:]
+}
+
+// shortSHA abbreviates a commit hash for messages.
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
@@ -0,0 +1,138 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseConflictHunks(t *testing.T) {
+	content := `keep
+<<<<<<< HEAD
+ours 1
+ours 2
+||||||| parent of abc1234 (Add retry)
+base
+=======
+theirs
+>>>>>>> abc1234 (Add retry)
+middle
+<<<<<<< HEAD
+=======
+added
+>>>>>>> abc1234 (Add retry)
+`
+	hunks := parseConflictHunks(content)
+	if len(hunks) != 2 {
+		t.Fatalf("got %d hunks, want 2: %+v", len(hunks), hunks)
+	}
+	want := ConflictHunk{StartLine: 2, EndLine: 9, OursLabel: "HEAD", TheirsLabel: "abc1234 (Add retry)",
+		Ours: "ours 1\nours 2\n", Base: "base\n", Theirs: "theirs\n"}
+	if hunks[0] != want {
+		t.Errorf("hunk 0 = %+v, want %+v", hunks[0], want)
+	}
+	if h := hunks[1]; h.StartLine != 11 || h.EndLine != 14 || h.Ours != "" || h.Theirs != "added\n" {
+		t.Errorf("hunk 1 = %+v", h)
+	}
+
+	if hunks := parseConflictHunks("no conflicts\n=======\n"); len(hunks) != 0 {
+		t.Errorf("parseConflictHunks without markers = %+v, want none", hunks)
+	}
+}
+
+// setupRebaseConflict returns a repo on branch "feature" whose README.md
+// change conflicts with the base branch's.
+func setupRebaseConflict(t *testing.T) (g *Git, base string) {
+	t.Helper()
+	dir := initTestRepo(t)
+	g = NewGit(dir)
+	base, _ = g.CurrentBranch()
+
+	commit := func(content, msg string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.Add("README.md"); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+		if err := g.Commit(msg); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+	}
+	if err := g.CreateBranch("feature"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	commit("base change\n", "base")
+	if err := g.Checkout("feature"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	commit("feature change\n", "feature")
+	return g, base
+}
+
+func TestRebaseConflict(t *testing.T) {
+	g, base := setupRebaseConflict(t)
+	feature, _ := g.Rev("HEAD")
+
+	err := g.Rebase(base, RebaseOptions{})
+	var conflictErr *RebaseConflictError
+	if !errors.As(err, &conflictErr) || !errors.Is(err, ErrRebaseConflict) {
+		t.Fatalf("Rebase = %v, want *RebaseConflictError", err)
+	}
+	if conflictErr.Commit != feature || conflictErr.Aborted {
+		t.Errorf("Commit = %s (aborted=%v), want %s", conflictErr.Commit, conflictErr.Aborted, feature)
+	}
+	if len(conflictErr.Files) != 1 || conflictErr.Files[0].Path != "README.md" {
+		t.Fatalf("Files = %+v, want README.md", conflictErr.Files)
+	}
+	hunks := conflictErr.Files[0].Hunks
+	if len(hunks) != 1 || hunks[0].Ours != "base change\n" || hunks[0].Theirs != "feature change\n" {
+		t.Errorf("Hunks = %+v, want base vs feature change", hunks)
+	}
+	if status, _ := g.Status(); !status.Rebasing {
+		t.Error("rebase should be left in progress")
+	}
+}
+
+func TestRebaseAbortOnConflict(t *testing.T) {
+	g, base := setupRebaseConflict(t)
+	feature, _ := g.Rev("HEAD")
+
+	err := g.Rebase(base, RebaseOptions{AbortOnConflict: true})
+	var conflictErr *RebaseConflictError
+	if !errors.As(err, &conflictErr) || !conflictErr.Aborted || len(conflictErr.Files) != 1 {
+		t.Fatalf("Rebase = %v, want aborted *RebaseConflictError", err)
+	}
+	status, _ := g.Status()
+	if status.Rebasing || !status.Clean || status.Branch != "feature" {
+		t.Errorf("status after abort = %+v, want clean feature branch", status)
+	}
+	if head, _ := g.Rev("HEAD"); head != feature {
+		t.Errorf("HEAD = %s, want %s", head, feature)
+	}
+}
+
+func TestRebaseClean(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	base, _ := g.CurrentBranch()
+	if err := g.CreateBranch("feature"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "other.txt"), []byte("x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_ = g.Add("other.txt")
+	_ = g.Commit("other")
+	baseHead, _ := g.Rev("HEAD")
+	_ = g.Checkout("feature")
+
+	if err := g.Rebase(base, RebaseOptions{}); err != nil {
+		t.Fatalf("Rebase = %v", err)
+	}
+	if head, _ := g.Rev("HEAD"); head != baseHead {
+		t.Errorf("HEAD = %s, want %s (fast-forwarded)", head, baseHead)
+	}
+}
@@ -0,0 +1,145 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrAuthFailed is returned when the remote rejected the credentials (or
+// none were available). Such failures are not retried.
+var ErrAuthFailed = errors.New("git authentication failed")
+
+// RetryPolicy controls retries of network operations (clone, fetch, pull,
+// push) after transient failures: DNS, connection, timeout, and HTTP 5xx
+// errors. The wait starts at Backoff and doubles up to MaxBackoff.
+type RetryPolicy struct {
+	Retries    int // retries after the first attempt (0 = no retries)
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy retries three times over about 14 seconds.
+var DefaultRetryPolicy = RetryPolicy{Retries: 3, Backoff: 2 * time.Second, MaxBackoff: 30 * time.Second}
+
+// defaultRetry is the policy for Git instances without their own.
+var defaultRetry = DefaultRetryPolicy
+
+// sleep is replaced in tests.
+var sleep = time.Sleep
+
+// SetDefaultRetryPolicy sets the retry policy for every Git that has not
+// been given its own (from gastown.toml [git], applied at startup).
+func SetDefaultRetryPolicy(p RetryPolicy) {
+	defaultRetry = p
+}
+
+// SetRetryPolicy sets the retry policy for this Git's network operations.
+func (g *Git) SetRetryPolicy(p RetryPolicy) {
+	g.retry = &p
+}
+
+// withRetry runs a network operation, retrying it under the retry policy
+// while it fails transiently. Authentication failures are returned at once,
+// wrapped as ErrAuthFailed; other failures are returned unchanged.
+func (g *Git) withRetry(op string, fn func() error) error {
+	policy := defaultRetry
+	if g.retry != nil {
+		policy = *g.retry
+	}
+	wait := policy.Backoff
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		switch classifyNetworkError(err) {
+		case networkAuth:
+			return fmt.Errorf("%w: %w", ErrAuthFailed, err)
+		case networkTransient:
+			if attempt < policy.Retries {
+				fmt.Fprintf(os.Stderr, "git %s failed (%v); retrying in %s (%d/%d)\n", op, err, wait, attempt+1, policy.Retries)
+				sleep(wait)
+				if wait *= 2; policy.MaxBackoff > 0 && wait > policy.MaxBackoff {
+					wait = policy.MaxBackoff
+				}
+				continue
+			}
+		}
+		return err
+	}
+}
+
+type networkFailure int
+
+const (
+	networkOther networkFailure = iota
+	networkTransient
+	networkAuth
+)
+
+// Messages git and its transports print for authentication and transient
+// network failures, lowercased.
+var (
+	authFailureMessages = []string{
+		"authentication failed",
+		"could not read username",
+		"could not read password",
+		"terminal prompts disabled",
+		"invalid username or password",
+		"permission denied (publickey",
+		"the requested url returned error: 401",
+		"the requested url returned error: 403",
+	}
+	transientFailureMessages = []string{
+		"could not resolve host",
+		"temporary failure in name resolution",
+		"failed to connect",
+		"connection refused",
+		"connection reset",
+		"connection timed out",
+		"operation timed out",
+		"network is unreachable",
+		"the remote end hung up unexpectedly",
+		"early eof",
+		"rpc failed",
+		"unexpected disconnect",
+		"gnutls_handshake",
+		"ssl_read",
+		"the requested url returned error: 500",
+		"the requested url returned error: 502",
+		"the requested url returned error: 503",
+		"the requested url returned error: 504",
+	}
+)
+
+// classifyNetworkError decides whether a failed network operation is worth
+// retrying. This is the one place the package reads git's stderr (ZFC
+// exception): git reports transport failures only there, with exit status
+// 128 like every other fatal error. Refused pushes (*PushError) come from
+// porcelain output and are never retried. The raw *GitError is still what
+// callers observe.
+func classifyNetworkError(err error) networkFailure {
+	var pushErr *PushError
+	if errors.As(err, &pushErr) {
+		return networkOther
+	}
+	var gitErr *GitError
+	if !errors.As(err, &gitErr) {
+		return networkOther
+	}
+	stderr := strings.ToLower(gitErr.Stderr)
+	for _, msg := range authFailureMessages {
+		if strings.Contains(stderr, msg) {
+			return networkAuth
+		}
+	}
+	for _, msg := range transientFailureMessages {
+		if strings.Contains(stderr, msg) {
+			return networkTransient
+		}
+	}
+	return networkOther
+}
@@ -0,0 +1,110 @@
+package git
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// stubSleep records retry waits instead of sleeping.
+func stubSleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var waits []time.Duration
+	orig := sleep
+	sleep = func(d time.Duration) { waits = append(waits, d) }
+	t.Cleanup(func() { sleep = orig })
+	return &waits
+}
+
+func TestClassifyNetworkError(t *testing.T) {
+	tests := []struct {
+		stderr string
+		want   networkFailure
+	}{
+		{"fatal: unable to access 'https://github.com/x/y.git/': Could not resolve host: github.com", networkTransient},
+		{"error: RPC failed; HTTP 502 curl 22 The requested URL returned error: 502", networkTransient},
+		{"fatal: the remote end hung up unexpectedly", networkTransient},
+		{"remote: Invalid username or password.\nfatal: Authentication failed for 'https://github.com/x/y.git/'", networkAuth},
+		{"fatal: could not read Username for 'https://github.com': terminal prompts disabled", networkAuth},
+		{"git@github.com: Permission denied (publickey).\nfatal: Could not read from remote repository.", networkAuth},
+		{"fatal: couldn't find remote ref nope", networkOther},
+	}
+	for _, tt := range tests {
+		err := &GitError{Command: "fetch", Stderr: tt.stderr}
+		if got := classifyNetworkError(err); got != tt.want {
+			t.Errorf("classifyNetworkError(%q) = %v, want %v", tt.stderr, got, tt.want)
+		}
+	}
+
+	rejected := &PushError{Reason: "non-fast-forward", Err: &GitError{Stderr: "fatal: the remote end hung up unexpectedly"}}
+	if got := classifyNetworkError(rejected); got != networkOther {
+		t.Errorf("classifyNetworkError(PushError) = %v, want networkOther", got)
+	}
+}
+
+func TestWithRetryBackoff(t *testing.T) {
+	waits := stubSleep(t)
+	g := NewGit(t.TempDir())
+	g.SetRetryPolicy(RetryPolicy{Retries: 3, Backoff: time.Second, MaxBackoff: 3 * time.Second})
+
+	attempts := 0
+	err := g.withRetry("fetch", func() error {
+		attempts++
+		if attempts < 4 {
+			return &GitError{Command: "fetch", Stderr: "fatal: unable to access: Connection reset by peer"}
+		}
+		return nil
+	})
+	if err != nil || attempts != 4 {
+		t.Fatalf("withRetry = %v after %d attempts, want success on attempt 4", err, attempts)
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}
+	if len(*waits) != len(want) {
+		t.Fatalf("waits = %v, want %v", *waits, want)
+	}
+	for i := range want {
+		if (*waits)[i] != want[i] {
+			t.Errorf("waits = %v, want %v", *waits, want)
+			break
+		}
+	}
+}
+
+func TestWithRetryAuthNotRetried(t *testing.T) {
+	waits := stubSleep(t)
+	g := NewGit(t.TempDir())
+
+	attempts := 0
+	authErr := &GitError{Command: "push", Stderr: "fatal: Authentication failed for 'https://example.com/x.git/'"}
+	err := g.withRetry("push", func() error {
+		attempts++
+		return authErr
+	})
+	if attempts != 1 || len(*waits) != 0 {
+		t.Errorf("auth failure attempted %d times (waits %v), want once", attempts, *waits)
+	}
+	var gitErr *GitError
+	if !errors.Is(err, ErrAuthFailed) || !errors.As(err, &gitErr) {
+		t.Errorf("withRetry = %v, want ErrAuthFailed wrapping the *GitError", err)
+	}
+}
+
+func TestFetchRetriesUnreachableRemote(t *testing.T) {
+	waits := stubSleep(t)
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	g.SetRetryPolicy(RetryPolicy{Retries: 2, Backoff: time.Millisecond})
+	if _, err := g.run("remote", "add", "origin", "http://127.0.0.1:1/"+filepath.Base(dir)+".git"); err != nil {
+		t.Fatalf("remote add: %v", err)
+	}
+
+	err := g.Fetch("origin")
+	var gitErr *GitError
+	if !errors.As(err, &gitErr) {
+		t.Fatalf("Fetch = %v, want *GitError", err)
+	}
+	if len(*waits) != 2 {
+		t.Errorf("Fetch retried %d times, want 2", len(*waits))
+	}
+}
@@ -0,0 +1,115 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrStashConflict is returned when stashed changes cannot be restored
+// cleanly.
+var ErrStashConflict = errors.New("stash conflict")
+
+// StashConflictError is a stash that did not apply cleanly. The stash is
+// kept so no work is lost. It matches ErrStashConflict.
+type StashConflictError struct {
+	Ref     string   // e.g. "stash@{0}"
+	Message string   // the stash's message
+	Files   []string // files left conflicted in the work tree
+}
+
+func (e *StashConflictError) Error() string {
+	return fmt.Sprintf("uncommitted changes conflict with the updated branch in %s; they are saved in %s (%q): resolve the conflicts and run 'git stash drop %s'",
+		strings.Join(e.Files, ", "), e.Ref, e.Message, e.Ref)
+}
+
+// Is reports whether target is ErrStashConflict.
+func (e *StashConflictError) Is(target error) bool {
+	return target == ErrStashConflict
+}
+
+// StashPush stashes all uncommitted changes, including untracked files, with
+// message. Returns the stash commit, or "" if there was nothing to stash.
+func (g *Git) StashPush(message string) (string, error) {
+	before, _ := g.run("rev-parse", "--verify", "--quiet", "refs/stash")
+	if _, err := g.run("stash", "push", "--include-untracked", "-m", message); err != nil {
+		return "", err
+	}
+	// ZFC: "nothing to stash" leaves refs/stash unchanged.
+	after, _ := g.run("rev-parse", "--verify", "--quiet", "refs/stash")
+	if after == before {
+		return "", nil
+	}
+	return after, nil
+}
+
+// StashPop restores and drops a stash: the stash commit returned by
+// StashPush, or the latest stash if stash is "". A stash that conflicts is
+// kept and returned as a *StashConflictError (ErrStashConflict).
+func (g *Git) StashPop(stash string) error {
+	ref := "stash@{0}"
+	if stash != "" {
+		var err error
+		if ref, err = g.stashRef(stash); err != nil {
+			return err
+		}
+	}
+	_, popErr := g.run("stash", "pop", ref)
+	if popErr == nil {
+		return nil
+	}
+
+	// ZFC: detect conflicts from git's index, not the pop output.
+	files, err := g.GetConflictingFiles()
+	if err != nil || len(files) == 0 {
+		return popErr
+	}
+	message, _ := g.run("log", "-1", "--format=%s", ref)
+	return &StashConflictError{Ref: ref, Message: message, Files: files}
+}
+
+// stashRef returns the stash@{n} reflog entry for a stash commit.
+func (g *Git) stashRef(commit string) (string, error) {
+	out, err := g.run("stash", "list", "--format=%H %gd")
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if sha, ref, ok := strings.Cut(line, " "); ok && sha == commit {
+			return ref, nil
+		}
+	}
+	return "", fmt.Errorf("stash %s not found", shortSHA(commit))
+}
+
+// WithStash runs fn with uncommitted changes (including untracked files)
+// stashed under a message naming op, then restores them. Use it around
+// fetch/pull/rebase so crew work in progress survives a sync.
+//
+// If fn fails and leaves a merge or rebase in progress, or the changes
+// conflict when restored, the stash is kept (see StashConflictError) and
+// the error says where the changes are.
+func (g *Git) WithStash(op string, fn func() error) error {
+	stash, err := g.StashPush("gt " + op + ": auto-stash")
+	if err != nil {
+		return fmt.Errorf("stashing changes before %s: %w", op, err)
+	}
+	fnErr := fn()
+	if stash == "" {
+		return fnErr
+	}
+
+	if fnErr != nil {
+		if status, err := g.Status(); err == nil && (status.Merging || status.Rebasing) {
+			ref, _ := g.stashRef(stash)
+			return fmt.Errorf("%w (uncommitted changes are saved in %s; run 'git stash pop' once the %s is resolved)", fnErr, ref, op)
+		}
+	}
+	if err := g.StashPop(stash); err != nil {
+		if fnErr != nil {
+			return fmt.Errorf("%w; restoring changes: %v", fnErr, err)
+		}
+		return err
+	}
+	return fnErr
+}
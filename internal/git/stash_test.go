@@ -0,0 +1,106 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStashPushPop(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+
+	if stash, err := g.StashPush("nothing"); err != nil || stash != "" {
+		t.Fatalf("StashPush on clean tree = %q, %v; want no stash", stash, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stash, err := g.StashPush("work")
+	if err != nil || stash == "" {
+		t.Fatalf("StashPush = %q, %v", stash, err)
+	}
+	if status, _ := g.Status(); !status.Clean {
+		t.Fatalf("tree not clean after StashPush: %+v", status)
+	}
+
+	if err := g.StashPop(stash); err != nil {
+		t.Fatalf("StashPop: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "new.txt")); string(data) != "new\n" {
+		t.Errorf("untracked file not restored: %q", data)
+	}
+	if n, _ := g.StashCount(); n != 0 {
+		t.Errorf("StashCount = %d, want 0", n)
+	}
+}
+
+func TestWithStash(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("wip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ran := false
+	err := g.WithStash("sync", func() error {
+		ran = true
+		if status, _ := g.Status(); !status.Clean {
+			t.Error("changes should be stashed while fn runs")
+		}
+		return nil
+	})
+	if err != nil || !ran {
+		t.Fatalf("WithStash = %v (ran=%v)", err, ran)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "notes.txt")); string(data) != "wip\n" {
+		t.Errorf("changes not restored: %q", data)
+	}
+
+	fnErr := errors.New("fetch failed")
+	if err := g.WithStash("sync", func() error { return fnErr }); !errors.Is(err, fnErr) {
+		t.Errorf("WithStash = %v, want fn's error", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Error("changes should be restored when fn fails cleanly")
+	}
+}
+
+func TestWithStashConflict(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readme, []byte("local edit\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := g.WithStash("sync", func() error {
+		// Simulate the sync bringing in a conflicting upstream change
+		if err := os.WriteFile(readme, []byte("upstream edit\n"), 0644); err != nil {
+			return err
+		}
+		if err := g.Add("README.md"); err != nil {
+			return err
+		}
+		return g.Commit("upstream")
+	})
+	var conflictErr *StashConflictError
+	if !errors.As(err, &conflictErr) || !errors.Is(err, ErrStashConflict) {
+		t.Fatalf("WithStash = %v, want *StashConflictError", err)
+	}
+	if conflictErr.Ref != "stash@{0}" || !strings.Contains(conflictErr.Message, "gt sync") {
+		t.Errorf("StashConflictError = %+v, want named stash@{0}", conflictErr)
+	}
+	if strings.Join(conflictErr.Files, ",") != "README.md" {
+		t.Errorf("Files = %v, want [README.md]", conflictErr.Files)
+	}
+	if n, _ := g.StashCount(); n != 1 {
+		t.Errorf("StashCount = %d, want the stash kept", n)
+	}
+}
@@ -0,0 +1,168 @@
+// Package handoff packages a blocked polecat's work for a crew member.
+//
+// When a polecat escalates with `gt escalate --handoff`, its branch, a summary
+// of what it tried, and its bead are recorded as a handoff package and the
+// overseer is notified. A crew member picks the work up with
+// `gt handoff accept <bead>`, which checks the branch out in their workspace
+// and hooks the bead to them.
+//
+// Packages live in <town>/.runtime/handoffs/<bead-id>.json.
+package handoff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// ErrNotFound indicates no handoff package exists for the bead.
+var ErrNotFound = errors.New("handoff not found")
+
+// ErrAlreadyAccepted indicates the handoff was already picked up.
+var ErrAlreadyAccepted = errors.New("handoff already accepted")
+
+// Package is a polecat's work, packaged for a crew member to continue.
+type Package struct {
+	Bead       string     `json:"bead"`
+	Title      string     `json:"title,omitempty"`
+	Rig        string     `json:"rig"`
+	Polecat    string     `json:"polecat"`
+	Branch     string     `json:"branch"`
+	Escalation string     `json:"escalation,omitempty"` // escalation bead ID
+	Blocker    string     `json:"blocker"`              // what the polecat is blocked on
+	Summary    string     `json:"summary,omitempty"`    // what was tried, in the polecat's words
+	Commits    []string   `json:"commits,omitempty"`    // commit subjects on the branch, oldest first
+	SessionID  string     `json:"session_id,omitempty"` // polecat's agent session, for its transcript
+	CreatedAt  time.Time  `json:"created_at"`
+	AcceptedBy string     `json:"accepted_by,omitempty"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+}
+
+// From returns the polecat's address.
+func (p *Package) From() string {
+	return fmt.Sprintf("%s/polecats/%s", p.Rig, p.Polecat)
+}
+
+// Accepted reports whether a crew member has picked up the handoff.
+func (p *Package) Accepted() bool {
+	return p.AcceptedBy != ""
+}
+
+// Dir returns the handoff directory for a town.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "handoffs")
+}
+
+// Path returns the package file path for a bead.
+func Path(townRoot, bead string) string {
+	return filepath.Join(Dir(townRoot), strings.ReplaceAll(bead, "/", "_")+".json")
+}
+
+// Save writes a handoff package, replacing any previous package for the bead.
+func Save(townRoot string, p *Package) error {
+	if p.Bead == "" {
+		return fmt.Errorf("handoff package has no bead")
+	}
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = time.Now().UTC()
+	}
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating handoff dir: %w", err)
+	}
+	return util.AtomicWriteJSON(Path(townRoot, p.Bead), p)
+}
+
+// Load reads the handoff package for a bead.
+func Load(townRoot, bead string) (*Package, error) {
+	data, err := os.ReadFile(Path(townRoot, bead)) //nolint:gosec // G304: path is built from the bead ID
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, bead)
+		}
+		return nil, err
+	}
+	var p Package
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing handoff %s: %w", bead, err)
+	}
+	return &p, nil
+}
+
+// List returns all handoff packages, oldest first.
+func List(townRoot string) ([]*Package, error) {
+	entries, err := os.ReadDir(Dir(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var pkgs []*Package
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		p, err := Load(townRoot, strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			continue
+		}
+		pkgs = append(pkgs, p)
+	}
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].CreatedAt.Before(pkgs[j].CreatedAt) })
+	return pkgs, nil
+}
+
+// Accept records that crew member acceptedBy picked up the handoff.
+func Accept(townRoot, bead, acceptedBy string) (*Package, error) {
+	p, err := Load(townRoot, bead)
+	if err != nil {
+		return nil, err
+	}
+	if p.Accepted() {
+		return nil, fmt.Errorf("%w by %s", ErrAlreadyAccepted, p.AcceptedBy)
+	}
+	now := time.Now().UTC()
+	p.AcceptedBy = acceptedBy
+	p.AcceptedAt = &now
+	if err := util.AtomicWriteJSON(Path(townRoot, bead), p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// FormatAssignment formats the package as a crew assignment: the mail sent to
+// the overseer and the briefing shown on accept.
+func FormatAssignment(p *Package) string {
+	var b strings.Builder
+	title := p.Bead
+	if p.Title != "" {
+		title = fmt.Sprintf("%s: %s", p.Bead, p.Title)
+	}
+	fmt.Fprintf(&b, "Polecat %s is blocked on %s and handed the work off to crew.\n\n", p.From(), title)
+	fmt.Fprintf(&b, "Blocked on: %s\n", p.Blocker)
+	fmt.Fprintf(&b, "Branch: %s\n", p.Branch)
+	if p.Escalation != "" {
+		fmt.Fprintf(&b, "Escalation: %s\n", p.Escalation)
+	}
+	if p.SessionID != "" {
+		fmt.Fprintf(&b, "Polecat session: %s\n", p.SessionID)
+	}
+	if p.Summary != "" {
+		fmt.Fprintf(&b, "\n## Summary\n%s\n", p.Summary)
+	}
+	if len(p.Commits) > 0 {
+		fmt.Fprintf(&b, "\n## Commits\n")
+		for _, c := range p.Commits {
+			fmt.Fprintf(&b, "- %s\n", c)
+		}
+	}
+	fmt.Fprintf(&b, "\nPick it up from a crew workspace in %s with:\n  gt handoff accept %s\n", p.Rig, p.Bead)
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package handoff
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPackageLifecycle(t *testing.T) {
+	townRoot := t.TempDir()
+
+	if _, err := Load(townRoot, "gt-abc"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Load before save error = %v, want ErrNotFound", err)
+	}
+	if pkgs, err := List(townRoot); err != nil || len(pkgs) != 0 {
+		t.Fatalf("List empty = %v, %v", pkgs, err)
+	}
+
+	older := &Package{Bead: "gt-old", Rig: "gastown", Polecat: "Nux", Branch: "polecat/Nux/gt-old", Blocker: "x", CreatedAt: time.Now().Add(-time.Hour)}
+	p := &Package{
+		Bead:       "gt-abc",
+		Title:      "Add OAuth login",
+		Rig:        "gastown",
+		Polecat:    "Toast",
+		Branch:     "polecat/Toast/gt-abc",
+		Escalation: "hq-esc1",
+		Blocker:    "Need OAuth client credentials",
+		Summary:    "Implemented the callback; token exchange needs real credentials.",
+		Commits:    []string{"Add OAuth callback handler"},
+	}
+	for _, pkg := range []*Package{older, p} {
+		if err := Save(townRoot, pkg); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	pkgs, err := List(townRoot)
+	if err != nil || len(pkgs) != 2 || pkgs[0].Bead != "gt-old" {
+		t.Fatalf("List = %+v, %v; want oldest first", pkgs, err)
+	}
+
+	accepted, err := Accept(townRoot, "gt-abc", "gastown/crew/max")
+	if err != nil {
+		t.Fatalf("Accept: %v", err)
+	}
+	if !accepted.Accepted() || accepted.AcceptedAt == nil {
+		t.Errorf("accepted package = %+v", accepted)
+	}
+	if _, err := Accept(townRoot, "gt-abc", "gastown/crew/joe"); !errors.Is(err, ErrAlreadyAccepted) {
+		t.Errorf("second Accept error = %v, want ErrAlreadyAccepted", err)
+	}
+}
+
+func TestFormatAssignment(t *testing.T) {
+	out := FormatAssignment(&Package{
+		Bead:    "gt-abc",
+		Title:   "Add OAuth login",
+		Rig:     "gastown",
+		Polecat: "Toast",
+		Branch:  "polecat/Toast/gt-abc",
+		Blocker: "Need OAuth client credentials",
+		Summary: "Token exchange needs real credentials.",
+		Commits: []string{"Add OAuth callback handler"},
+	})
+	for _, want := range []string{
+		"gastown/polecats/Toast",
+		"gt-abc: Add OAuth login",
+		"Blocked on: Need OAuth client credentials",
+		"Branch: polecat/Toast/gt-abc",
+		"## Summary\nToken exchange needs real credentials.",
+		"- Add OAuth callback handler",
+		"gt handoff accept gt-abc",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("assignment missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Package health rolls up per-session health into rig-level and town-level
+// health states, with the reasons for each, and keeps a history of how the
+// town's health changed.
+//
+// Each session is ok, degraded, or down:
+//
+//   - down: a session that should be running (mayor, deacon, a rig's witness
+//     and refinery) isn't
+//   - degraded: the session is paused as a runaway loop, crashed recently,
+//     or (the deacon) its patrol heartbeat is stale
+//
+// A rig is down when none of the sessions it needs are running, and
+// degraded when any of its sessions isn't ok. The town is down when the
+// deacon is down or every rig is, and degraded when anything isn't ok.
+//
+// The caller observes the sessions (tmux, loop guard, crash dumps); this
+// package only decides. History lives in <town>/.runtime/health/.
+package health
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// State is a health state.
+type State string
+
+// Health states, best first.
+const (
+	StateOK       State = "ok"
+	StateDegraded State = "degraded"
+	StateDown     State = "down"
+)
+
+// rank orders states from best to worst.
+func (s State) rank() int {
+	switch s {
+	case StateDown:
+		return 2
+	case StateDegraded:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// worse returns the worse of two states.
+func worse(a, b State) State {
+	if b.rank() > a.rank() {
+		return b
+	}
+	return a
+}
+
+// Observation is what the caller saw of one agent session.
+type Observation struct {
+	Agent    string // agent address (e.g., "gastown/witness", "deacon/")
+	Session  string // tmux session name
+	Role     string // mayor, deacon, witness, refinery, crew, polecat
+	Required bool   // the session should be running
+	Running  bool
+
+	Paused       string        // why the loop guard paused it ("" = not paused)
+	CrashedAt    time.Time     // its most recent crash dump (zero = none recently)
+	HeartbeatAge time.Duration // age of a stale patrol heartbeat (0 = fresh or none)
+}
+
+// RigObservation is what the caller saw of one rig.
+type RigObservation struct {
+	Name     string
+	Parked   bool // parked rigs don't need their patrol agents
+	Sessions []Observation
+}
+
+// Session is one session's health.
+type Session struct {
+	Agent   string   `json:"agent"`
+	Session string   `json:"session"`
+	Role    string   `json:"role"`
+	State   State    `json:"state"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// Rig is a rig's health.
+type Rig struct {
+	Name     string    `json:"name"`
+	State    State     `json:"state"`
+	Reasons  []string  `json:"reasons,omitempty"`
+	Parked   bool      `json:"parked,omitempty"`
+	Sessions []Session `json:"sessions"`
+}
+
+// Town is the town's health.
+type Town struct {
+	State     State     `json:"state"`
+	Reasons   []string  `json:"reasons,omitempty"`
+	Agents    []Session `json:"agents"` // town-level agents (mayor, deacon)
+	Rigs      []Rig     `json:"rigs"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// OK reports whether the town is fully healthy.
+func (t *Town) OK() bool {
+	return t.State == StateOK
+}
+
+// Summary describes the town's state and why (e.g., "degraded: gastown:
+// witness not running").
+func (t *Town) Summary() string {
+	if len(t.Reasons) == 0 {
+		return string(t.State)
+	}
+	return fmt.Sprintf("%s: %s", t.State, strings.Join(t.Reasons, "; "))
+}
+
+// Evaluate returns the health of one session at now.
+func Evaluate(o Observation, now time.Time) Session {
+	s := Session{Agent: o.Agent, Session: o.Session, Role: o.Role, State: StateOK}
+	if !o.Running {
+		if o.Required {
+			s.State = StateDown
+			s.Reasons = append(s.Reasons, "not running")
+		}
+		return s
+	}
+	if o.Paused != "" {
+		s.State = StateDegraded
+		s.Reasons = append(s.Reasons, "paused as a runaway loop: "+o.Paused)
+	}
+	if !o.CrashedAt.IsZero() {
+		s.State = StateDegraded
+		s.Reasons = append(s.Reasons, fmt.Sprintf("crashed %s ago", now.Sub(o.CrashedAt).Round(time.Minute)))
+	}
+	if o.HeartbeatAge > 0 {
+		s.State = StateDegraded
+		s.Reasons = append(s.Reasons, fmt.Sprintf("heartbeat stale (%s old)", o.HeartbeatAge.Round(time.Minute)))
+	}
+	return s
+}
+
+// EvaluateRig returns the health of a rig from its sessions.
+func EvaluateRig(r RigObservation, now time.Time) Rig {
+	rig := Rig{Name: r.Name, State: StateOK, Parked: r.Parked, Sessions: []Session{}}
+	var required, running int
+	for _, o := range r.Sessions {
+		if r.Parked {
+			o.Required = false
+		}
+		s := Evaluate(o, now)
+		rig.Sessions = append(rig.Sessions, s)
+		if o.Required {
+			required++
+			if o.Running {
+				running++
+			}
+		}
+		if s.State != StateOK {
+			rig.State = StateDegraded
+			for _, reason := range s.Reasons {
+				rig.Reasons = append(rig.Reasons, sessionLabel(s)+" "+reason)
+			}
+		}
+	}
+	sort.Slice(rig.Sessions, func(i, j int) bool { return rig.Sessions[i].Session < rig.Sessions[j].Session })
+	if required > 0 && running == 0 {
+		rig.State = StateDown
+	}
+	return rig
+}
+
+// Aggregate returns the town's health from its town-level agents and rigs.
+func Aggregate(agents []Observation, rigs []RigObservation, now time.Time) *Town {
+	t := &Town{State: StateOK, Agents: []Session{}, Rigs: []Rig{}, CheckedAt: now.UTC()}
+	for _, o := range agents {
+		s := Evaluate(o, now)
+		t.Agents = append(t.Agents, s)
+		if s.State == StateDown && s.Role == "deacon" {
+			t.State = StateDown // nothing patrols the town
+		}
+		t.State = worse(t.State, atMost(s.State, StateDegraded))
+		for _, reason := range s.Reasons {
+			t.Reasons = append(t.Reasons, sessionLabel(s)+" "+reason)
+		}
+	}
+
+	down := 0
+	for _, ro := range rigs {
+		r := EvaluateRig(ro, now)
+		t.Rigs = append(t.Rigs, r)
+		if r.State == StateDown {
+			down++
+		}
+		t.State = worse(t.State, atMost(r.State, StateDegraded))
+		for _, reason := range r.Reasons {
+			t.Reasons = append(t.Reasons, r.Name+": "+reason)
+		}
+	}
+	sort.Slice(t.Rigs, func(i, j int) bool { return t.Rigs[i].Name < t.Rigs[j].Name })
+	if len(rigs) > 0 && down == len(rigs) {
+		t.State = StateDown
+	}
+	return t
+}
+
+// atMost returns s, or limit if s is worse: a single component going down
+// only degrades the whole.
+func atMost(s, limit State) State {
+	if s.rank() > limit.rank() {
+		return limit
+	}
+	return s
+}
+
+// sessionLabel names a session in a reason: its role, or its role and name
+// for workers (e.g., "witness", "polecat Toast").
+func sessionLabel(s Session) string {
+	if i := strings.LastIndex(strings.TrimSuffix(s.Agent, "/"), "/"); i >= 0 && (s.Role == "polecat" || s.Role == "crew") {
+		return s.Role + " " + s.Agent[i+1:]
+	}
+	return s.Role
+}
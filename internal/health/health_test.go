@@ -0,0 +1,139 @@
+package health
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+
+func patrol(rig string, witness, refinery bool) RigObservation {
+	return RigObservation{Name: rig, Sessions: []Observation{
+		{Agent: rig + "/witness", Session: "gt-" + rig + "-witness", Role: "witness", Required: true, Running: witness},
+		{Agent: rig + "/refinery", Session: "gt-" + rig + "-refinery", Role: "refinery", Required: true, Running: refinery},
+	}}
+}
+
+func townAgents(deacon bool) []Observation {
+	return []Observation{
+		{Agent: "mayor/", Session: "hq-mayor", Role: "mayor", Required: true, Running: true},
+		{Agent: "deacon/", Session: "hq-deacon", Role: "deacon", Required: true, Running: deacon},
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name   string
+		obs    Observation
+		want   State
+		reason string
+	}{
+		{"running", Observation{Role: "witness", Required: true, Running: true}, StateOK, ""},
+		{"required and stopped", Observation{Role: "witness", Required: true}, StateDown, "not running"},
+		{"idle worker", Observation{Role: "polecat"}, StateOK, ""},
+		{"paused", Observation{Role: "polecat", Running: true, Paused: "Bash called 10 times"}, StateDegraded, "runaway loop"},
+		{"crashed", Observation{Role: "witness", Running: true, CrashedAt: now.Add(-5 * time.Minute)}, StateDegraded, "crashed 5m0s ago"},
+		{"stale heartbeat", Observation{Role: "deacon", Running: true, HeartbeatAge: 20 * time.Minute}, StateDegraded, "heartbeat stale"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Evaluate(tt.obs, now)
+			if s.State != tt.want {
+				t.Errorf("state = %s, want %s", s.State, tt.want)
+			}
+			if got := strings.Join(s.Reasons, "; "); !strings.Contains(got, tt.reason) || (tt.reason == "" && got != "") {
+				t.Errorf("reasons = %q, want %q", got, tt.reason)
+			}
+		})
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	town := Aggregate(townAgents(true), []RigObservation{patrol("gastown", true, true), patrol("beads", true, true)}, now)
+	if !town.OK() || len(town.Reasons) != 0 {
+		t.Errorf("all running: %s", town.Summary())
+	}
+
+	// One patrol agent down degrades the rig and the town
+	town = Aggregate(townAgents(true), []RigObservation{patrol("gastown", true, false), patrol("beads", true, true)}, now)
+	if town.State != StateDegraded || town.Rigs[1].State != StateDegraded {
+		t.Errorf("refinery down: town %s, rigs %+v", town.State, town.Rigs)
+	}
+	if want := "gastown: refinery not running"; town.Summary() != "degraded: "+want {
+		t.Errorf("summary = %q, want the reason %q", town.Summary(), want)
+	}
+
+	// A whole rig down only degrades the town...
+	town = Aggregate(townAgents(true), []RigObservation{patrol("gastown", false, false), patrol("beads", true, true)}, now)
+	if town.State != StateDegraded || town.Rigs[1].State != StateDown {
+		t.Errorf("one rig down: town %s, rigs %+v", town.State, town.Rigs)
+	}
+	// ...every rig down, or the deacon, takes it down
+	town = Aggregate(townAgents(true), []RigObservation{patrol("gastown", false, false)}, now)
+	if town.State != StateDown {
+		t.Errorf("every rig down: town %s", town.State)
+	}
+	town = Aggregate(townAgents(false), []RigObservation{patrol("gastown", true, true)}, now)
+	if town.State != StateDown || !strings.Contains(town.Summary(), "deacon not running") {
+		t.Errorf("deacon down: %s", town.Summary())
+	}
+
+	// A parked rig needs no patrol agents
+	parked := patrol("gastown", false, false)
+	parked.Parked = true
+	town = Aggregate(townAgents(true), []RigObservation{parked}, now)
+	if !town.OK() {
+		t.Errorf("parked rig: %s", town.Summary())
+	}
+
+	// Workers are named in reasons
+	rig := patrol("gastown", true, true)
+	rig.Sessions = append(rig.Sessions, Observation{Agent: "gastown/polecats/Toast", Session: "gt-gastown-Toast", Role: "polecat", Running: true, Paused: "looping"})
+	town = Aggregate(townAgents(true), []RigObservation{rig}, now)
+	if !strings.Contains(town.Summary(), "gastown: polecat Toast paused as a runaway loop") {
+		t.Errorf("summary = %q", town.Summary())
+	}
+}
+
+func TestSaveHistory(t *testing.T) {
+	townRoot := t.TempDir()
+	if last, err := Last(townRoot); err != nil || last != nil {
+		t.Fatalf("Last with no checks = %v, %v", last, err)
+	}
+
+	check := func(at time.Time, refinery bool, wantChanged bool) {
+		t.Helper()
+		town := Aggregate(townAgents(true), []RigObservation{patrol("gastown", true, refinery)}, at)
+		changed, err := Save(townRoot, town)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changed != wantChanged {
+			t.Errorf("check at %s: changed = %v, want %v", at.Format(time.Kitchen), changed, wantChanged)
+		}
+	}
+	check(now, true, true) // the first check starts the history
+	check(now.Add(3*time.Minute), true, false)
+	check(now.Add(6*time.Minute), false, true)
+	check(now.Add(9*time.Minute), false, false)
+	check(now.Add(12*time.Minute), true, true)
+
+	history, err := History(townRoot, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var states []string
+	for _, r := range history {
+		states = append(states, string(r.State)+"/"+string(r.Rigs["gastown"]))
+	}
+	if got, want := strings.Join(states, ","), "ok/ok,degraded/degraded,ok/ok"; got != want {
+		t.Errorf("history = %s, want %s", got, want)
+	}
+	if recent, _ := History(townRoot, 1); len(recent) != 1 || !recent[0].At.Equal(now.Add(12*time.Minute)) {
+		t.Errorf("History(1) = %+v", recent)
+	}
+	if last, err := Last(townRoot); err != nil || last == nil || !last.OK() {
+		t.Errorf("Last = %+v, %v", last, err)
+	}
+}
@@ -0,0 +1,145 @@
+package health
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// MaxHistory is how many state changes the history keeps.
+const MaxHistory = 1000
+
+// Record is one entry in the health history: the town's health when it or
+// any rig changed state.
+type Record struct {
+	At      time.Time        `json:"at"`
+	State   State            `json:"state"`
+	Reasons []string         `json:"reasons,omitempty"`
+	Rigs    map[string]State `json:"rigs,omitempty"`
+}
+
+// Dir returns the health state directory for a town.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "health")
+}
+
+// currentPath holds the most recent check; historyPath the state changes.
+func currentPath(townRoot string) string { return filepath.Join(Dir(townRoot), "current.json") }
+func historyPath(townRoot string) string { return filepath.Join(Dir(townRoot), "history.jsonl") }
+
+// record returns the history entry for t.
+func (t *Town) record() Record {
+	r := Record{At: t.CheckedAt, State: t.State, Reasons: t.Reasons}
+	for _, rig := range t.Rigs {
+		if r.Rigs == nil {
+			r.Rigs = make(map[string]State)
+		}
+		r.Rigs[rig.Name] = rig.State
+	}
+	return r
+}
+
+// changed reports whether r differs in state from prev, for the town or any
+// rig. Changed reasons alone (e.g., a crash's age) aren't a change.
+func (r Record) changed(prev *Record) bool {
+	if prev == nil || prev.State != r.State || len(prev.Rigs) != len(r.Rigs) {
+		return true
+	}
+	for name, s := range r.Rigs {
+		if prev.Rigs[name] != s {
+			return true
+		}
+	}
+	return false
+}
+
+// Last returns the most recently saved check, or nil if there is none.
+func Last(townRoot string) (*Town, error) {
+	data, err := os.ReadFile(currentPath(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var t Town
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("parsing health state: %w", err)
+	}
+	return &t, nil
+}
+
+// Save stores t as the latest check and, if the town's or a rig's state
+// changed since the previous one, appends it to the history. Reports
+// whether it changed.
+func Save(townRoot string, t *Town) (bool, error) {
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return false, err
+	}
+	var prev *Record
+	if last, err := Last(townRoot); err == nil && last != nil {
+		r := last.record()
+		prev = &r
+	}
+	if err := util.AtomicWriteJSON(currentPath(townRoot), t); err != nil {
+		return false, err
+	}
+	rec := t.record()
+	if !rec.changed(prev) {
+		return false, nil
+	}
+
+	history, err := History(townRoot, 0)
+	if err != nil {
+		return true, err
+	}
+	history = append(history, rec)
+	if len(history) > MaxHistory {
+		history = history[len(history)-MaxHistory:]
+	}
+	var buf bytes.Buffer
+	for _, r := range history {
+		data, _ := json.Marshal(r)
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return true, util.AtomicWriteFile(historyPath(townRoot), buf.Bytes(), 0644)
+}
+
+// History returns the recorded state changes, oldest first; limit > 0
+// returns only the most recent limit.
+func History(townRoot string, limit int) ([]Record, error) {
+	f, err := os.Open(historyPath(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue // skip malformed lines
+		}
+		records = append(records, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+	return records, nil
+}
@@ -0,0 +1,64 @@
+package jira
+
+import (
+	"strings"
+)
+
+// LabelPrefix starts the label that ties a bead to its Jira issue (e.g.,
+// "jira:PROJ-123").
+const LabelPrefix = "jira:"
+
+// Label returns the label for an issue's bead.
+func Label(key string) string {
+	return LabelPrefix + key
+}
+
+// KeyOf returns the issue key in a bead's labels, or "" if it wasn't
+// imported from Jira.
+func KeyOf(labels []string) string {
+	for _, l := range labels {
+		if key, ok := strings.CutPrefix(l, LabelPrefix); ok && key != "" {
+			return key
+		}
+	}
+	return ""
+}
+
+// BeadType returns the bead type for a Jira issue type.
+func BeadType(issueType string) string {
+	switch strings.ToLower(issueType) {
+	case "bug":
+		return "bug"
+	case "epic":
+		return "epic"
+	case "story", "feature", "new feature", "improvement":
+		return "feature"
+	}
+	return "task"
+}
+
+// BeadPriority returns the bead priority (0-4) for a Jira priority,
+// including the older Blocker-Trivial scheme. Unknown priorities are 2.
+func BeadPriority(priority string) int {
+	switch strings.ToLower(priority) {
+	case "highest", "blocker":
+		return 0
+	case "high", "critical":
+		return 1
+	case "low", "minor":
+		return 3
+	case "lowest", "trivial":
+		return 4
+	}
+	return 2
+}
+
+// Description returns the description of an issue's bead: a link back to
+// the issue, then the issue's own description.
+func Description(issue Issue, browseURL string) string {
+	desc := "Jira: " + browseURL
+	if d := strings.TrimSpace(issue.Description); d != "" {
+		desc += "\n\n" + d
+	}
+	return desc
+}
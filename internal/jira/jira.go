@@ -0,0 +1,188 @@
+// Package jira connects a town to a Jira site, so teams can drive polecats
+// from an existing Jira backlog.
+//
+// Issues matching a rig's JQL are imported as beads labeled with their
+// issue key (see Label). As a polecat works on one of those beads, gt jira
+// sync moves its issue through the site's workflow (slung, submitted as a
+// PR, merged) and comments on it with a summary of the polecat's session.
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoTransition indicates an issue has no transition to the requested
+// status, usually because it's already there or past it.
+var ErrNoTransition = errors.New("no such transition")
+
+// Client calls a Jira site's REST API (version 2, whose descriptions and
+// comments are plain text rather than Atlassian Document Format).
+type Client struct {
+	BaseURL string // e.g., "https://example.atlassian.net"
+	User    string // Jira Cloud account for basic auth; empty sends Token as a bearer PAT
+	Token   string
+	HTTP    *http.Client // a client with a 30s timeout if nil
+}
+
+// Issue is the part of a Jira issue imported as a bead.
+type Issue struct {
+	Key         string
+	Summary     string
+	Description string
+	Type        string // e.g., "Bug", "Story"
+	Priority    string // e.g., "High"
+	Status      string // e.g., "To Do"
+}
+
+// BrowseURL returns the web page for an issue.
+func (c *Client) BrowseURL(key string) string {
+	return strings.TrimSuffix(c.BaseURL, "/") + "/browse/" + key
+}
+
+// do sends a request to the API and decodes its JSON response into out,
+// if out isn't nil.
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.BaseURL, "/")+path, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.User != "" {
+		req.SetBasicAuth(c.User, c.Token)
+	} else if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	hc := c.HTTP
+	if hc == nil {
+		hc = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("jira %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := strings.TrimSpace(string(data))
+		if len(msg) > 200 {
+			msg = msg[:200] + "..."
+		}
+		return fmt.Errorf("jira %s %s: %s: %s", method, path, resp.Status, msg)
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("jira %s %s: parsing response: %w", method, path, err)
+	}
+	return nil
+}
+
+// Search returns up to max issues matching jql. Jira Cloud (basic auth)
+// is searched with its /search/jql endpoint, which replaced /search there;
+// Server and Data Center only have /search.
+func (c *Client) Search(ctx context.Context, jql string, max int) ([]Issue, error) {
+	q := url.Values{}
+	q.Set("jql", jql)
+	q.Set("maxResults", strconv.Itoa(max))
+	q.Set("fields", "summary,description,issuetype,priority,status")
+	path := "/rest/api/2/search"
+	if c.User != "" {
+		path = "/rest/api/2/search/jql"
+	}
+
+	var resp struct {
+		Issues []struct {
+			Key    string `json:"key"`
+			Fields struct {
+				Summary     string `json:"summary"`
+				Description string `json:"description"`
+				IssueType   struct {
+					Name string `json:"name"`
+				} `json:"issuetype"`
+				Priority *struct {
+					Name string `json:"name"`
+				} `json:"priority"`
+				Status struct {
+					Name string `json:"name"`
+				} `json:"status"`
+			} `json:"fields"`
+		} `json:"issues"`
+	}
+	if err := c.do(ctx, http.MethodGet, path+"?"+q.Encode(), nil, &resp); err != nil {
+		return nil, err
+	}
+	issues := make([]Issue, 0, len(resp.Issues))
+	for _, i := range resp.Issues {
+		issue := Issue{
+			Key:         i.Key,
+			Summary:     i.Fields.Summary,
+			Description: i.Fields.Description,
+			Type:        i.Fields.IssueType.Name,
+			Status:      i.Fields.Status.Name,
+		}
+		if i.Fields.Priority != nil {
+			issue.Priority = i.Fields.Priority.Name
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// Transition applies the issue's transition named name, or the one that
+// moves it to the status named name (case-insensitively). It returns
+// ErrNoTransition if the issue has neither.
+func (c *Client) Transition(ctx context.Context, key, name string) error {
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "/transitions"
+	var resp struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			To   struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return err
+	}
+	id := ""
+	for _, t := range resp.Transitions {
+		if strings.EqualFold(t.Name, name) || strings.EqualFold(t.To.Name, name) {
+			id = t.ID
+			break
+		}
+	}
+	if id == "" {
+		return fmt.Errorf("%w to %q for %s", ErrNoTransition, name, key)
+	}
+	body := map[string]interface{}{"transition": map[string]string{"id": id}}
+	return c.do(ctx, http.MethodPost, path, body, nil)
+}
+
+// Comment adds a comment to an issue.
+func (c *Client) Comment(ctx context.Context, key, text string) error {
+	return c.do(ctx, http.MethodPost, "/rest/api/2/issue/"+url.PathEscape(key)+"/comment", map[string]string{"body": text}, nil)
+}
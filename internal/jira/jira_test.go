@@ -0,0 +1,170 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     string
+		wantPath string
+		wantAuth string
+	}{
+		{"cloud", "gt@example.com", "/rest/api/2/search/jql", "Basic "},
+		{"server PAT", "", "/rest/api/2/search", "Bearer tok"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.wantPath {
+					t.Errorf("path = %s, want %s", r.URL.Path, tt.wantPath)
+				}
+				if auth := r.Header.Get("Authorization"); !strings.HasPrefix(auth, tt.wantAuth) {
+					t.Errorf("Authorization = %q, want prefix %q", auth, tt.wantAuth)
+				}
+				if jql := r.URL.Query().Get("jql"); jql != "project = GT" {
+					t.Errorf("jql = %q", jql)
+				}
+				_, _ = io.WriteString(w, `{"issues":[
+					{"key":"GT-1","fields":{"summary":"Fix login","description":"It breaks.","issuetype":{"name":"Bug"},"priority":{"name":"High"},"status":{"name":"To Do"}}},
+					{"key":"GT-2","fields":{"summary":"Add export","issuetype":{"name":"Story"},"priority":null,"status":{"name":"To Do"}}}
+				]}`)
+			}))
+			defer srv.Close()
+
+			c := &Client{BaseURL: srv.URL, User: tt.user, Token: "tok"}
+			issues, err := c.Search(context.Background(), "project = GT", 10)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			want := []Issue{
+				{Key: "GT-1", Summary: "Fix login", Description: "It breaks.", Type: "Bug", Priority: "High", Status: "To Do"},
+				{Key: "GT-2", Summary: "Add export", Type: "Story", Status: "To Do"},
+			}
+			if len(issues) != len(want) {
+				t.Fatalf("got %d issues, want %d", len(issues), len(want))
+			}
+			for i := range want {
+				if issues[i] != want[i] {
+					t.Errorf("issue %d = %+v, want %+v", i, issues[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSearch_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"errorMessages":["bad JQL"]}`, http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	_, err := (&Client{BaseURL: srv.URL}).Search(context.Background(), "nonsense", 10)
+	if err == nil || !strings.Contains(err.Error(), "bad JQL") {
+		t.Errorf("Search error = %v, want the response's message", err)
+	}
+}
+
+func TestTransition(t *testing.T) {
+	var posted string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/issue/GT-1/transitions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if r.Method == http.MethodPost {
+			var body struct {
+				Transition struct {
+					ID string `json:"id"`
+				} `json:"transition"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			posted = body.Transition.ID
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_, _ = io.WriteString(w, `{"transitions":[
+			{"id":"11","name":"Start work","to":{"name":"In Progress"}},
+			{"id":"21","name":"Review","to":{"name":"In Review"}}
+		]}`)
+	}))
+	defer srv.Close()
+	c := &Client{BaseURL: srv.URL}
+
+	tests := []struct {
+		name   string
+		want   string
+		wantID string
+	}{
+		{"by transition name", "review", "21"},
+		{"by status", "In Progress", "11"},
+		{"unavailable", "Done", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posted = ""
+			err := c.Transition(context.Background(), "GT-1", tt.want)
+			if tt.wantID == "" {
+				if !errors.Is(err, ErrNoTransition) {
+					t.Errorf("Transition error = %v, want ErrNoTransition", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Transition: %v", err)
+			}
+			if posted != tt.wantID {
+				t.Errorf("posted transition %q, want %q", posted, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestComment(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/api/2/issue/GT-1/comment" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"id":"100"}`)
+	}))
+	defer srv.Close()
+
+	if err := (&Client{BaseURL: srv.URL}).Comment(context.Background(), "GT-1", "Merged."); err != nil {
+		t.Fatalf("Comment: %v", err)
+	}
+	if got["body"] != "Merged." {
+		t.Errorf("body = %q, want %q", got["body"], "Merged.")
+	}
+}
+
+func TestKeyOf(t *testing.T) {
+	if got := KeyOf([]string{"gt:task", Label("GT-7")}); got != "GT-7" {
+		t.Errorf("KeyOf = %q, want GT-7", got)
+	}
+	if got := KeyOf([]string{"gt:task", "jira:"}); got != "" {
+		t.Errorf("KeyOf without a key = %q, want empty", got)
+	}
+}
+
+func TestBeadTypeAndPriority(t *testing.T) {
+	for issueType, want := range map[string]string{"Bug": "bug", "Epic": "epic", "Story": "feature", "Sub-task": "task"} {
+		if got := BeadType(issueType); got != want {
+			t.Errorf("BeadType(%q) = %q, want %q", issueType, got, want)
+		}
+	}
+	for priority, want := range map[string]int{"Highest": 0, "Critical": 1, "Medium": 2, "Minor": 3, "Lowest": 4, "": 2} {
+		if got := BeadPriority(priority); got != want {
+			t.Errorf("BeadPriority(%q) = %d, want %d", priority, got, want)
+		}
+	}
+}
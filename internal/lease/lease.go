@@ -0,0 +1,216 @@
+// Package lease provides time-boxed claims on hooked beads.
+//
+// When work is hooked to an agent, the agent is granted a lease on the bead.
+// The lease is renewed by activity heartbeats (every gt command the agent runs,
+// plus the daemon's liveness check of the agent's session). A lease that
+// expires means the holder stopped making progress; the Deacon returns the
+// bead to the ready queue so the work is not stranded on a dead session.
+//
+// Leases live in <town>/.runtime/leases/<bead-id>.json, alongside other
+// runtime state. The bead store remains the source of truth for assignment:
+// a lease whose bead was re-hooked to someone else is simply discarded.
+package lease
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultTTL is how long a lease lasts without renewal.
+const DefaultTTL = 30 * time.Minute
+
+// ErrNotFound indicates no lease exists for the bead.
+var ErrNotFound = errors.New("lease not found")
+
+// Lease is a time-boxed claim on a hooked bead.
+type Lease struct {
+	BeadID     string    `json:"bead_id"`
+	Holder     string    `json:"holder"` // agent address (e.g., "gastown/polecats/nux")
+	AcquiredAt time.Time `json:"acquired_at"`
+	RenewedAt  time.Time `json:"renewed_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	TTL        string    `json:"ttl"`
+}
+
+// Expired reports whether the lease has expired at now.
+func (l *Lease) Expired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// Remaining returns the time left on the lease at now (negative if expired).
+func (l *Lease) Remaining(now time.Time) time.Duration {
+	return l.ExpiresAt.Sub(now)
+}
+
+// ttl returns the lease's TTL, falling back to DefaultTTL.
+func (l *Lease) ttl() time.Duration {
+	if d, err := time.ParseDuration(l.TTL); err == nil && d > 0 {
+		return d
+	}
+	return DefaultTTL
+}
+
+// Dir returns the lease directory for a town.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "leases")
+}
+
+// Path returns the lease file path for a bead.
+func Path(townRoot, beadID string) string {
+	// Bead IDs are filesystem-safe, but guard against path separators anyway
+	return filepath.Join(Dir(townRoot), strings.ReplaceAll(beadID, "/", "_")+".json")
+}
+
+// Acquire grants holder a lease on beadID, replacing any existing lease.
+// Hooking is authoritative, so acquisition never fails because of another holder.
+func Acquire(townRoot, beadID, holder string, ttl time.Duration) (*Lease, error) {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	now := time.Now().UTC()
+	l := &Lease{
+		BeadID:     beadID,
+		Holder:     holder,
+		AcquiredAt: now,
+		RenewedAt:  now,
+		ExpiresAt:  now.Add(ttl),
+		TTL:        ttl.String(),
+	}
+	if err := write(townRoot, l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Get returns the lease for beadID, or ErrNotFound.
+func Get(townRoot, beadID string) (*Lease, error) {
+	data, err := os.ReadFile(Path(townRoot, beadID)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	var l Lease
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("parsing lease %s: %w", beadID, err)
+	}
+	return &l, nil
+}
+
+// Renew extends a single lease by its TTL from now.
+func Renew(townRoot, beadID string) (*Lease, error) {
+	l, err := Get(townRoot, beadID)
+	if err != nil {
+		return nil, err
+	}
+	renew(l, time.Now().UTC())
+	if err := write(townRoot, l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// RenewHolder extends every lease held by holder. This is the activity
+// heartbeat: it is called whenever the holder runs a gt command.
+// Returns the number of leases renewed.
+func RenewHolder(townRoot, holder string) (int, error) {
+	if holder == "" {
+		return 0, nil
+	}
+	leases, err := List(townRoot)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now().UTC()
+	renewed := 0
+	for _, l := range leases {
+		if l.Holder != holder {
+			continue
+		}
+		renew(l, now)
+		if err := write(townRoot, l); err != nil {
+			return renewed, err
+		}
+		renewed++
+	}
+	return renewed, nil
+}
+
+// Release removes the lease on beadID. Releasing a missing lease is not an error.
+func Release(townRoot, beadID string) error {
+	if err := os.Remove(Path(townRoot, beadID)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// List returns all leases in the town, sorted by expiry (soonest first).
+// Unreadable lease files are skipped.
+func List(townRoot string) ([]*Lease, error) {
+	entries, err := os.ReadDir(Dir(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var leases []*Lease
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(Dir(townRoot), entry.Name())) //nolint:gosec // G304: path is constructed internally
+		if err != nil {
+			continue
+		}
+		var l Lease
+		if err := json.Unmarshal(data, &l); err != nil || l.BeadID == "" {
+			continue
+		}
+		leases = append(leases, &l)
+	}
+
+	sort.Slice(leases, func(i, j int) bool {
+		return leases[i].ExpiresAt.Before(leases[j].ExpiresAt)
+	})
+	return leases, nil
+}
+
+// Expired returns the leases that have expired at now.
+func Expired(townRoot string, now time.Time) ([]*Lease, error) {
+	leases, err := List(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var expired []*Lease
+	for _, l := range leases {
+		if l.Expired(now) {
+			expired = append(expired, l)
+		}
+	}
+	return expired, nil
+}
+
+// renew extends l by its TTL from now.
+func renew(l *Lease, now time.Time) {
+	l.RenewedAt = now
+	l.ExpiresAt = now.Add(l.ttl())
+}
+
+// write persists a lease atomically.
+func write(townRoot string, l *Lease) error {
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating lease directory: %w", err)
+	}
+	return util.AtomicWriteJSON(Path(townRoot, l.BeadID), l)
+}
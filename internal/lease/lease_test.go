@@ -0,0 +1,119 @@
+package lease
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAcquireAndGet(t *testing.T) {
+	townRoot := t.TempDir()
+
+	l, err := Acquire(townRoot, "gt-abc", "gastown/polecats/nux", 10*time.Minute)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	if l.Expired(time.Now()) {
+		t.Error("fresh lease should not be expired")
+	}
+	if !l.Expired(time.Now().Add(11 * time.Minute)) {
+		t.Error("lease should be expired after its TTL")
+	}
+
+	got, err := Get(townRoot, "gt-abc")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Holder != "gastown/polecats/nux" || got.TTL != "10m0s" {
+		t.Errorf("Get = %+v, want holder and TTL preserved", got)
+	}
+}
+
+func TestGetMissing(t *testing.T) {
+	if _, err := Get(t.TempDir(), "gt-none"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRenewHolder(t *testing.T) {
+	townRoot := t.TempDir()
+
+	if _, err := Acquire(townRoot, "gt-a", "gastown/polecats/nux", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Acquire(townRoot, "gt-b", "gastown/polecats/nux", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	other, err := Acquire(townRoot, "gt-c", "gastown/polecats/ace", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before, _ := Get(townRoot, "gt-a")
+	time.Sleep(10 * time.Millisecond)
+
+	n, err := RenewHolder(townRoot, "gastown/polecats/nux")
+	if err != nil {
+		t.Fatalf("RenewHolder: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("RenewHolder renewed %d, want 2", n)
+	}
+
+	after, _ := Get(townRoot, "gt-a")
+	if !after.ExpiresAt.After(before.ExpiresAt) {
+		t.Error("renewed lease should expire later")
+	}
+	if !after.AcquiredAt.Equal(before.AcquiredAt) {
+		t.Error("renewal should not change AcquiredAt")
+	}
+
+	unchanged, _ := Get(townRoot, "gt-c")
+	if !unchanged.ExpiresAt.Equal(other.ExpiresAt) {
+		t.Error("other holder's lease should not be renewed")
+	}
+}
+
+func TestListExpiredAndRelease(t *testing.T) {
+	townRoot := t.TempDir()
+
+	if _, err := Acquire(townRoot, "gt-long", "gastown/crew/joe", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Acquire(townRoot, "gt-short", "gastown/polecats/nux", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	leases, err := List(townRoot)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(leases) != 2 || leases[0].BeadID != "gt-short" {
+		t.Fatalf("List = %v, want 2 leases sorted by expiry", leases)
+	}
+
+	expired, err := Expired(townRoot, time.Now().Add(2*time.Minute))
+	if err != nil {
+		t.Fatalf("Expired: %v", err)
+	}
+	if len(expired) != 1 || expired[0].BeadID != "gt-short" {
+		t.Errorf("Expired = %v, want only gt-short", expired)
+	}
+
+	if err := Release(townRoot, "gt-short"); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if err := Release(townRoot, "gt-short"); err != nil {
+		t.Errorf("releasing a missing lease should not error: %v", err)
+	}
+	if leases, _ := List(townRoot); len(leases) != 1 {
+		t.Errorf("List after release = %d leases, want 1", len(leases))
+	}
+}
+
+func TestListNoDirectory(t *testing.T) {
+	leases, err := List(t.TempDir())
+	if err != nil || leases != nil {
+		t.Errorf("List = %v, %v; want nil, nil", leases, err)
+	}
+}
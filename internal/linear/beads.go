@@ -0,0 +1,58 @@
+package linear
+
+import (
+	"strings"
+)
+
+// LabelPrefix starts the label that ties a bead to its Linear issue
+// (e.g., "linear:ENG-123").
+const LabelPrefix = "linear:"
+
+// Label returns the label for an issue's bead.
+func Label(identifier string) string {
+	return LabelPrefix + identifier
+}
+
+// KeyOf returns the issue identifier in a bead's labels, or "" if it
+// wasn't imported from Linear.
+func KeyOf(labels []string) string {
+	for _, l := range labels {
+		if id, ok := strings.CutPrefix(l, LabelPrefix); ok && id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// BeadType returns the bead type for an issue. Linear has no issue types,
+// so it's read from the issue's labels ("Bug", "Feature").
+func BeadType(labels []string) string {
+	for _, l := range labels {
+		switch strings.ToLower(l) {
+		case "bug":
+			return "bug"
+		case "feature", "improvement":
+			return "feature"
+		}
+	}
+	return "task"
+}
+
+// BeadPriority returns the bead priority (0-4) for a Linear priority
+// (1 = urgent ... 4 = low). No priority is 2.
+func BeadPriority(priority int) int {
+	if priority < 1 || priority > 4 {
+		return 2
+	}
+	return priority - 1
+}
+
+// Description returns the description of an issue's bead: a link back to
+// the issue, then the issue's own description.
+func Description(issue Issue) string {
+	desc := "Linear: " + issue.URL
+	if d := strings.TrimSpace(issue.Description); d != "" {
+		desc += "\n\n" + d
+	}
+	return desc
+}
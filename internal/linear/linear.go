@@ -0,0 +1,241 @@
+// Package linear connects a town to a Linear workspace, so teams can drive
+// polecats from their Linear issues.
+//
+// Issues in a rig's team (and, optionally, project and labels) are imported
+// as beads labeled with their identifier (see Label). As a polecat works on
+// one of those beads, gt linear sync moves its issue through the team's
+// workflow states and attaches the polecat's PR. The workspace's webhook
+// (see Verify and ParseWebhook) reports issues as they're triaged, so they
+// can be slung at once.
+package linear
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultEndpoint is Linear's GraphQL API.
+const DefaultEndpoint = "https://api.linear.app/graphql"
+
+// ErrNoState indicates an issue's team has no workflow state with the
+// requested name.
+var ErrNoState = errors.New("no such workflow state")
+
+// Client calls Linear's GraphQL API.
+type Client struct {
+	APIKey   string       // a personal API key
+	Endpoint string       // DefaultEndpoint if empty
+	HTTP     *http.Client // a client with a 30s timeout if nil
+}
+
+// Issue is the part of a Linear issue imported as a bead.
+type Issue struct {
+	ID          string // UUID
+	Identifier  string // e.g., "ENG-123"
+	Title       string
+	Description string // Markdown
+	Priority    int    // 0 = none, 1 = urgent ... 4 = low
+	URL         string
+	State       string // e.g., "Todo"
+	StateType   string // triage, backlog, unstarted, started, completed, canceled
+	Team        string // team key, e.g. "ENG"
+	Project     string
+	Labels      []string
+}
+
+// issueFields selects an Issue's fields in a query.
+const issueFields = `id identifier title description priority url
+	state { name type } team { key } project { name } labels { nodes { name } }`
+
+// wireIssue is an Issue as the API returns it.
+type wireIssue struct {
+	ID          string `json:"id"`
+	Identifier  string `json:"identifier"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Priority    int    `json:"priority"`
+	URL         string `json:"url"`
+	State       *struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"state"`
+	Team *struct {
+		Key string `json:"key"`
+	} `json:"team"`
+	Project *struct {
+		Name string `json:"name"`
+	} `json:"project"`
+	Labels json.RawMessage `json:"labels"` // {"nodes": [...]} from the API, [...] in webhooks
+}
+
+// issue converts a wireIssue.
+func (w *wireIssue) issue() Issue {
+	i := Issue{
+		ID:          w.ID,
+		Identifier:  w.Identifier,
+		Title:       w.Title,
+		Description: w.Description,
+		Priority:    w.Priority,
+		URL:         w.URL,
+	}
+	if w.State != nil {
+		i.State, i.StateType = w.State.Name, w.State.Type
+	}
+	if w.Team != nil {
+		i.Team = w.Team.Key
+	}
+	if w.Project != nil {
+		i.Project = w.Project.Name
+	}
+	type label struct {
+		Name string `json:"name"`
+	}
+	var labels []label
+	var conn struct {
+		Nodes []label `json:"nodes"`
+	}
+	if json.Unmarshal(w.Labels, &conn) == nil && conn.Nodes != nil {
+		labels = conn.Nodes
+	} else {
+		_ = json.Unmarshal(w.Labels, &labels)
+	}
+	for _, l := range labels {
+		i.Labels = append(i.Labels, l.Name)
+	}
+	return i
+}
+
+// query runs a GraphQL query or mutation and decodes its data into out.
+func (c *Client) query(ctx context.Context, q string, vars map[string]interface{}, out interface{}) error {
+	data, err := json.Marshal(map[string]interface{}{"query": q, "variables": vars})
+	if err != nil {
+		return err
+	}
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", c.APIKey)
+
+	hc := c.HTTP
+	if hc == nil {
+		hc = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("linear: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("linear: %s", resp.Status)
+		}
+		return fmt.Errorf("linear: parsing response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		msgs := make([]string, len(result.Errors))
+		for i, e := range result.Errors {
+			msgs[i] = e.Message
+		}
+		return fmt.Errorf("linear: %s", strings.Join(msgs, "; "))
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("linear: %s", resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(result.Data, out)
+}
+
+// Issues returns up to max issues in team that haven't been started
+// (backlog or todo), limited to a project and to any of labels if they're
+// set.
+func (c *Client) Issues(ctx context.Context, team, project string, labels []string, max int) ([]Issue, error) {
+	filter := map[string]interface{}{
+		"team":  map[string]interface{}{"key": map[string]interface{}{"eqIgnoreCase": team}},
+		"state": map[string]interface{}{"type": map[string]interface{}{"in": []string{"backlog", "unstarted"}}},
+	}
+	if project != "" {
+		filter["project"] = map[string]interface{}{"name": map[string]interface{}{"eqIgnoreCase": project}}
+	}
+	if len(labels) > 0 {
+		filter["labels"] = map[string]interface{}{"some": map[string]interface{}{"name": map[string]interface{}{"in": labels}}}
+	}
+	var data struct {
+		Issues struct {
+			Nodes []wireIssue `json:"nodes"`
+		} `json:"issues"`
+	}
+	q := `query($filter: IssueFilter, $first: Int) { issues(filter: $filter, first: $first) { nodes { ` + issueFields + ` } } }`
+	if err := c.query(ctx, q, map[string]interface{}{"filter": filter, "first": max}, &data); err != nil {
+		return nil, err
+	}
+	issues := make([]Issue, 0, len(data.Issues.Nodes))
+	for i := range data.Issues.Nodes {
+		issues = append(issues, data.Issues.Nodes[i].issue())
+	}
+	return issues, nil
+}
+
+// SetState moves an issue (by UUID or identifier) to its team's workflow
+// state named state (case-insensitively). It returns ErrNoState if the
+// team has no such state.
+func (c *Client) SetState(ctx context.Context, issue, state string) error {
+	var data struct {
+		Issue struct {
+			ID   string `json:"id"`
+			Team struct {
+				States struct {
+					Nodes []struct {
+						ID   string `json:"id"`
+						Name string `json:"name"`
+					} `json:"nodes"`
+				} `json:"states"`
+			} `json:"team"`
+		} `json:"issue"`
+	}
+	q := `query($id: String!) { issue(id: $id) { id team { states { nodes { id name } } } } }`
+	if err := c.query(ctx, q, map[string]interface{}{"id": issue}, &data); err != nil {
+		return err
+	}
+	stateID := ""
+	for _, s := range data.Issue.Team.States.Nodes {
+		if strings.EqualFold(s.Name, state) {
+			stateID = s.ID
+			break
+		}
+	}
+	if stateID == "" {
+		return fmt.Errorf("%w %q for %s", ErrNoState, state, issue)
+	}
+	m := `mutation($id: String!, $stateId: String!) { issueUpdate(id: $id, input: {stateId: $stateId}) { success } }`
+	return c.query(ctx, m, map[string]interface{}{"id": data.Issue.ID, "stateId": stateID}, nil)
+}
+
+// AttachURL links a URL (e.g., a pull request) to an issue, by UUID or
+// identifier.
+func (c *Client) AttachURL(ctx context.Context, issue, url, title string) error {
+	m := `mutation($issueId: String!, $url: String!, $title: String) { attachmentLinkURL(issueId: $issueId, url: $url, title: $title) { success } }`
+	return c.query(ctx, m, map[string]interface{}{"issueId": issue, "url": url, "title": title}, nil)
+}
@@ -0,0 +1,205 @@
+package linear
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// graphQL serves Linear's API from respond, which gets each request's
+// query and variables.
+func graphQL(t *testing.T, respond func(query string, vars map[string]interface{}) string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "lin_api_test" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+		_, _ = io.WriteString(w, respond(req.Query, req.Variables))
+	}))
+}
+
+func TestIssues(t *testing.T) {
+	srv := graphQL(t, func(query string, vars map[string]interface{}) string {
+		filter, _ := json.Marshal(vars["filter"])
+		for _, want := range []string{`"eqIgnoreCase":"ENG"`, `"in":["backlog","unstarted"]`, `"some":{"name":{"in":["polecat"]}}`} {
+			if !strings.Contains(string(filter), want) {
+				t.Errorf("filter %s lacks %s", filter, want)
+			}
+		}
+		return `{"data":{"issues":{"nodes":[{"id":"u1","identifier":"ENG-1","title":"Fix login","description":"It breaks.",
+			"priority":2,"url":"https://linear.app/acme/issue/ENG-1","state":{"name":"Todo","type":"unstarted"},
+			"team":{"key":"ENG"},"project":null,"labels":{"nodes":[{"name":"Bug"},{"name":"polecat"}]}}]}}}`
+	})
+	defer srv.Close()
+
+	c := &Client{APIKey: "lin_api_test", Endpoint: srv.URL}
+	issues, err := c.Issues(context.Background(), "ENG", "", []string{"polecat"}, 10)
+	if err != nil {
+		t.Fatalf("Issues: %v", err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("got %d issues, want 1", len(issues))
+	}
+	i := issues[0]
+	if i.Identifier != "ENG-1" || i.StateType != "unstarted" || i.Team != "ENG" || i.Priority != 2 ||
+		strings.Join(i.Labels, ",") != "Bug,polecat" {
+		t.Errorf("issue = %+v", i)
+	}
+}
+
+func TestQuery_Errors(t *testing.T) {
+	srv := graphQL(t, func(string, map[string]interface{}) string {
+		return `{"errors":[{"message":"Authentication required"}]}`
+	})
+	defer srv.Close()
+
+	_, err := (&Client{APIKey: "lin_api_test", Endpoint: srv.URL}).Issues(context.Background(), "ENG", "", nil, 10)
+	if err == nil || !strings.Contains(err.Error(), "Authentication required") {
+		t.Errorf("Issues error = %v, want the API's message", err)
+	}
+}
+
+func TestSetState(t *testing.T) {
+	var updated string
+	srv := graphQL(t, func(query string, vars map[string]interface{}) string {
+		if strings.Contains(query, "issueUpdate") {
+			updated = fmt.Sprint(vars["id"], "=", vars["stateId"])
+			return `{"data":{"issueUpdate":{"success":true}}}`
+		}
+		return `{"data":{"issue":{"id":"u1","team":{"states":{"nodes":[{"id":"s1","name":"Todo"},{"id":"s2","name":"In Review"}]}}}}}`
+	})
+	defer srv.Close()
+	c := &Client{APIKey: "lin_api_test", Endpoint: srv.URL}
+
+	if err := c.SetState(context.Background(), "ENG-1", "in review"); err != nil {
+		t.Fatalf("SetState: %v", err)
+	}
+	if updated != "u1=s2" {
+		t.Errorf("updated %q, want u1=s2", updated)
+	}
+	if err := c.SetState(context.Background(), "ENG-1", "Shipped"); !errors.Is(err, ErrNoState) {
+		t.Errorf("SetState to a missing state = %v, want ErrNoState", err)
+	}
+}
+
+func TestAttachURL(t *testing.T) {
+	var got map[string]interface{}
+	srv := graphQL(t, func(query string, vars map[string]interface{}) string {
+		got = vars
+		return `{"data":{"attachmentLinkURL":{"success":true}}}`
+	})
+	defer srv.Close()
+
+	c := &Client{APIKey: "lin_api_test", Endpoint: srv.URL}
+	if err := c.AttachURL(context.Background(), "ENG-1", "https://github.com/acme/app/pull/7", "Pull request"); err != nil {
+		t.Fatalf("AttachURL: %v", err)
+	}
+	if got["issueId"] != "ENG-1" || got["url"] != "https://github.com/acme/app/pull/7" {
+		t.Errorf("variables = %v", got)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Now()
+	body := func(ts time.Time) []byte {
+		return []byte(fmt.Sprintf(`{"action":"create","type":"Issue","webhookTimestamp":%d}`, ts.UnixMilli()))
+	}
+	sign := func(secret string, b []byte) http.Header {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(b)
+		h := http.Header{}
+		h.Set("Linear-Signature", hex.EncodeToString(mac.Sum(nil)))
+		return h
+	}
+	fresh, stale := body(now), body(now.Add(-5*time.Minute))
+	tests := []struct {
+		name   string
+		header http.Header
+		body   []byte
+		ok     bool
+	}{
+		{"valid", sign("s3cret", fresh), fresh, true},
+		{"wrong secret", sign("other", fresh), fresh, false},
+		{"tampered body", sign("s3cret", fresh), stale, false},
+		{"replayed", sign("s3cret", stale), stale, false},
+		{"unsigned", http.Header{}, fresh, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify("s3cret", tt.header, tt.body, now)
+			if (err == nil) != tt.ok {
+				t.Errorf("Verify = %v, want ok %v", err, tt.ok)
+			}
+			if err != nil && !errors.Is(err, ErrSignature) {
+				t.Errorf("error %v is not ErrSignature", err)
+			}
+		})
+	}
+}
+
+func TestWebhookTriaged(t *testing.T) {
+	issue := func(stateType string) string {
+		return `"data":{"id":"u1","identifier":"ENG-1","title":"Fix login","state":{"name":"Todo","type":"` + stateType +
+			`"},"team":{"key":"ENG"},"labels":[{"name":"polecat"}]}`
+	}
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{"created in todo", `{"action":"create","type":"Issue",` + issue("unstarted") + `}`, true},
+		{"created in triage", `{"action":"create","type":"Issue",` + issue("triage") + `}`, false},
+		{"accepted from triage", `{"action":"update","type":"Issue",` + issue("backlog") + `,"updatedFrom":{"stateId":"s0"}}`, true},
+		{"retitled", `{"action":"update","type":"Issue",` + issue("backlog") + `,"updatedFrom":{"title":"Old"}}`, false},
+		{"started", `{"action":"update","type":"Issue",` + issue("started") + `,"updatedFrom":{"stateId":"s1"}}`, false},
+		{"comment", `{"action":"create","type":"Comment","data":{"body":"hi"}}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := ParseWebhook([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("ParseWebhook: %v", err)
+			}
+			if got := w.Triaged(); got != tt.want {
+				t.Errorf("Triaged = %v, want %v", got, tt.want)
+			}
+			if w.Issue != nil && strings.Join(w.Issue.Labels, ",") != "polecat" {
+				t.Errorf("labels = %v, want [polecat]", w.Issue.Labels)
+			}
+		})
+	}
+}
+
+func TestBeadMapping(t *testing.T) {
+	if got := KeyOf([]string{"gt:task", Label("ENG-7")}); got != "ENG-7" {
+		t.Errorf("KeyOf = %q, want ENG-7", got)
+	}
+	if got := BeadType([]string{"polecat", "Bug"}); got != "bug" {
+		t.Errorf("BeadType = %q, want bug", got)
+	}
+	if got := BeadType(nil); got != "task" {
+		t.Errorf("BeadType(nil) = %q, want task", got)
+	}
+	for priority, want := range map[int]int{0: 2, 1: 0, 2: 1, 3: 2, 4: 3} {
+		if got := BeadPriority(priority); got != want {
+			t.Errorf("BeadPriority(%d) = %d, want %d", priority, got, want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package linear
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrSignature indicates a webhook delivery that isn't from the workspace's
+// webhook, or is a replay.
+var ErrSignature = errors.New("invalid Linear signature")
+
+// maxDeliveryAge bounds how old a delivery may be, so a captured one can't
+// be replayed later. Linear recommends a minute.
+const maxDeliveryAge = time.Minute
+
+// Verify checks a webhook delivery's Linear-Signature header (the hex
+// HMAC-SHA256 of the body with the webhook's signing secret) and that its
+// webhookTimestamp is recent.
+func Verify(secret string, header http.Header, body []byte, now time.Time) error {
+	sig, err := hex.DecodeString(header.Get("Linear-Signature"))
+	if err != nil || len(sig) == 0 {
+		return ErrSignature
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(mac.Sum(nil), sig) {
+		return ErrSignature
+	}
+	var stamp struct {
+		Timestamp int64 `json:"webhookTimestamp"`
+	}
+	if json.Unmarshal(body, &stamp) != nil || stamp.Timestamp == 0 {
+		return ErrSignature
+	}
+	if age := now.Sub(time.UnixMilli(stamp.Timestamp)); age > maxDeliveryAge || age < -maxDeliveryAge {
+		return fmt.Errorf("%w: delivery is too old", ErrSignature)
+	}
+	return nil
+}
+
+// Webhook is a webhook delivery.
+type Webhook struct {
+	Action string // create, update, remove
+	Type   string // the entity, e.g. "Issue" or "Comment"
+	Issue  *Issue // the issue, for Issue deliveries
+
+	// UpdatedFrom holds the previous values of the fields an update
+	// changed (e.g., "stateId").
+	UpdatedFrom map[string]interface{}
+}
+
+// ParseWebhook parses a verified webhook delivery.
+func ParseWebhook(body []byte) (*Webhook, error) {
+	var raw struct {
+		Action      string                 `json:"action"`
+		Type        string                 `json:"type"`
+		Data        json.RawMessage        `json:"data"`
+		UpdatedFrom map[string]interface{} `json:"updatedFrom"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("parsing Linear webhook: %w", err)
+	}
+	w := &Webhook{Action: raw.Action, Type: raw.Type, UpdatedFrom: raw.UpdatedFrom}
+	if raw.Type == "Issue" {
+		var wi wireIssue
+		if err := json.Unmarshal(raw.Data, &wi); err != nil {
+			return nil, fmt.Errorf("parsing Linear webhook issue: %w", err)
+		}
+		issue := wi.issue()
+		w.Issue = &issue
+	}
+	return w, nil
+}
+
+// Triaged reports whether the delivery is an issue that was just triaged:
+// created in, or moved to, its team's backlog or todo.
+func (w *Webhook) Triaged() bool {
+	if w.Issue == nil {
+		return false
+	}
+	switch w.Action {
+	case "create":
+	case "update":
+		if _, ok := w.UpdatedFrom["stateId"]; !ok {
+			return false
+		}
+	default:
+		return false
+	}
+	return w.Issue.StateType == "backlog" || w.Issue.StateType == "unstarted"
+}
@@ -0,0 +1,212 @@
+// Package logging is gastown's structured logging layer on log/slog.
+//
+// The level and format are configured once per process from gastown.toml's
+// [log] section (see Configure). Packages get a logger tagged with their
+// component from For, add correlation IDs with WithSession and WithRequest,
+// and report best-effort failures they would otherwise discard with DebugIf
+// or WarnIf:
+//
+//	log := logging.WithSession(logging.For(logging.ComponentCrew), sessionID)
+//	logging.DebugIf(log, "applying theme", t.ConfigureGasTownSession(...))
+//
+// Credentials in messages and values are redacted before they are written
+// (see secret.Redact).
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/secret"
+)
+
+// Components name the subsystem a record comes from, in its component field.
+const (
+	ComponentAPI      = "api"
+	ComponentCrew     = "crew"
+	ComponentDaemon   = "daemon"
+	ComponentPolecat  = "polecat"
+	ComponentRefinery = "refinery"
+	ComponentRuntime  = "runtime"
+)
+
+// Record field keys.
+const (
+	KeyComponent = "component"
+	KeySession   = "session"
+	KeyRequest   = "request_id"
+	KeyError     = "err"
+)
+
+// Output formats.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Config is the process-wide logging configuration.
+type Config struct {
+	// Level is the minimum level logged: "debug", "info", "warn", or
+	// "error". Empty means "info".
+	Level string
+
+	// Format is FormatText (logfmt-style key=value) or FormatJSON (one
+	// object per line). Empty means text.
+	Format string
+}
+
+var (
+	mu      sync.RWMutex
+	current Config
+)
+
+// ParseLevel parses a Config.Level.
+func ParseLevel(level string) (slog.Level, error) {
+	var l slog.Level
+	if level == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := l.UnmarshalText([]byte(level)); err != nil || strings.ContainsAny(level, "+-") {
+		return 0, fmt.Errorf("unknown log level %q: want debug, info, warn, or error", level)
+	}
+	return l, nil
+}
+
+// Validate checks cfg's level and format.
+func (cfg Config) Validate() error {
+	if _, err := ParseLevel(cfg.Level); err != nil {
+		return err
+	}
+	switch cfg.Format {
+	case "", FormatText, FormatJSON:
+		return nil
+	}
+	return fmt.Errorf("unknown log format %q: want %s or %s", cfg.Format, FormatText, FormatJSON)
+}
+
+// NewHandler returns a handler writing records to w as cfg says.
+func NewHandler(w io.Writer, cfg Config) (slog.Handler, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	level, _ := ParseLevel(cfg.Level)
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}
+	if cfg.Format == FormatJSON {
+		return slog.NewJSONHandler(w, opts), nil
+	}
+	return slog.NewTextHandler(w, opts), nil
+}
+
+// redactAttr scrubs credentials from a record's message and its string and
+// error values before they are written.
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindString:
+		a.Value = slog.StringValue(secret.Redact(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			a.Value = slog.StringValue(secret.Redact(err.Error()))
+		}
+	}
+	return a
+}
+
+// Configure makes cfg the process's logging configuration and the slog
+// default logger write to stderr with it. Loggers from For that were
+// obtained earlier keep the previous configuration, so call this at startup.
+func Configure(cfg Config) error {
+	h, err := NewHandler(os.Stderr, cfg)
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	current = cfg
+	mu.Unlock()
+	slog.SetDefault(slog.New(h))
+	return nil
+}
+
+// New returns a logger for component writing to w (e.g., a daemon's log
+// file) with the configured level and format.
+func New(w io.Writer, component string) *slog.Logger {
+	mu.RLock()
+	cfg := current
+	mu.RUnlock()
+	h, err := NewHandler(w, cfg)
+	if err != nil {
+		h, _ = NewHandler(w, Config{})
+	}
+	return slog.New(h).With(KeyComponent, component)
+}
+
+// For returns the default logger tagged with component.
+func For(component string) *slog.Logger {
+	return slog.Default().With(KeyComponent, component)
+}
+
+// WithSession tags l's records with a tmux session name.
+func WithSession(l *slog.Logger, session string) *slog.Logger {
+	return orDefault(l).With(KeySession, session)
+}
+
+// WithRequest tags l's records with a request ID.
+func WithRequest(l *slog.Logger, id string) *slog.Logger {
+	return orDefault(l).With(KeyRequest, id)
+}
+
+// NewRequestID returns a random ID for correlating a request's records.
+func NewRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b) // never fails on supported platforms
+	return hex.EncodeToString(b)
+}
+
+type contextKey struct{}
+
+// NewContext returns ctx carrying l, so code handling a request logs with
+// its correlation IDs.
+func NewContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger carried by ctx, or For(component) if none.
+func FromContext(ctx context.Context, component string) *slog.Logger {
+	if l, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return For(component)
+}
+
+// DebugIf logs a non-nil err from a best-effort operation at debug level,
+// with any extra key-value args, for failures that don't affect the outcome
+// (theming, cosmetic env).
+func DebugIf(l *slog.Logger, msg string, err error, args ...any) {
+	if err != nil {
+		orDefault(l).Debug(msg, append([]any{KeyError, err}, args...)...)
+	}
+}
+
+// WarnIf logs a non-nil err from a best-effort operation at warn level,
+// with any extra key-value args, for failures that may leave something
+// behind (cleanup, recovery).
+func WarnIf(l *slog.Logger, msg string, err error, args ...any) {
+	if err != nil {
+		orDefault(l).Warn(msg, append([]any{KeyError, err}, args...)...)
+	}
+}
+
+// orDefault returns l, or the default logger if l is nil, so zero-valued
+// structs in tests can log.
+func orDefault(l *slog.Logger) *slog.Logger {
+	if l == nil {
+		return slog.Default()
+	}
+	return l
+}
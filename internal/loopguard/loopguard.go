@@ -0,0 +1,286 @@
+// Package loopguard detects agent sessions stuck in runaway loops and keeps
+// them paused until a human resumes them.
+//
+// Three patterns are detected:
+//
+//   - repeat_calls: the same tool call (same tool, same input), or the same
+//     pair of calls alternating, many times in a row
+//   - repeat_prompts: the same prompt, or the same pair of prompts
+//     alternating, many times in a row (e.g., two agents nudging each
+//     other back and forth)
+//   - burn_rate: a session's token rate far above the baseline
+//
+// Tool calls and prompts are fed in by the runtime's hooks, token counts by
+// the daemon's heartbeat (gt loop check). A paused session's tool calls and
+// prompts are refused by the same hooks until gt loop resume.
+//
+// State lives in <town>/.runtime/loops/<session>.json.
+package loopguard
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Detected patterns.
+const (
+	PatternRepeatCalls   = "repeat_calls"
+	PatternRepeatPrompts = "repeat_prompts"
+	PatternBurnRate      = "burn_rate"
+)
+
+// BurnWindow is the span of token samples a session's rate is measured
+// over.
+const BurnWindow = 15 * time.Minute
+
+// minBaselineSessions is how many sessions must have a rate before their
+// median is trusted as the baseline.
+const minBaselineSessions = 3
+
+// Pause records why a session was paused.
+type Pause struct {
+	Pattern  string    `json:"pattern"`
+	Detail   string    `json:"detail"`
+	PausedAt time.Time `json:"paused_at"`
+}
+
+// Sample is a session's cumulative token count at a point in time.
+type Sample struct {
+	At     time.Time `json:"at"`
+	Tokens int       `json:"tokens"`
+}
+
+// State is a session's loop-detection state.
+type State struct {
+	Session string   `json:"session"`
+	Calls   []string `json:"calls,omitempty"`   // fingerprints of recent tool calls, oldest first
+	Prompts []string `json:"prompts,omitempty"` // hashes of recent prompts, oldest first
+	Samples []Sample `json:"samples,omitempty"` // token samples within BurnWindow
+	Pause   *Pause   `json:"pause,omitempty"`
+}
+
+// Dir returns the loop state directory for a town.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "loops")
+}
+
+// Path returns the state file path for a session.
+func Path(townRoot, session string) string {
+	return filepath.Join(Dir(townRoot), strings.ReplaceAll(session, "/", "_")+".json")
+}
+
+// Load returns the state of session, or an empty state if it has none.
+func Load(townRoot, session string) (*State, error) {
+	data, err := os.ReadFile(Path(townRoot, session)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &State{Session: session}, nil
+		}
+		return nil, err
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing loop state: %w", err)
+	}
+	s.Session = session
+	return &s, nil
+}
+
+// Save writes s to its state file.
+func (s *State) Save(townRoot string) error {
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(Path(townRoot, s.Session), s)
+}
+
+// List returns the state of every session that has one, sorted by session.
+func List(townRoot string) ([]*State, error) {
+	entries, err := os.ReadDir(Dir(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var states []*State
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		s, err := Load(townRoot, name)
+		if err != nil {
+			continue // unreadable state is skipped
+		}
+		states = append(states, s)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Session < states[j].Session })
+	return states, nil
+}
+
+// Paused reports whether the session is paused.
+func (s *State) Paused() bool {
+	return s.Pause != nil
+}
+
+// pause pauses s unless it already is, and returns the new pause.
+func (s *State) pause(pattern, detail string) *Pause {
+	if s.Pause != nil {
+		return nil
+	}
+	s.Pause = &Pause{Pattern: pattern, Detail: detail, PausedAt: time.Now().UTC()}
+	return s.Pause
+}
+
+// Resume clears the pause and the history that triggered it, so the
+// session isn't paused again by the same calls.
+func (s *State) Resume() {
+	s.Pause = nil
+	s.Calls = nil
+	s.Prompts = nil
+	s.Samples = nil
+}
+
+// RecordCall adds a tool call to the history and pauses the session if it
+// completes a loop of n repetitions (0 = never). Returns the new pause, or
+// nil.
+func (s *State) RecordCall(tool string, input map[string]interface{}, n int) *Pause {
+	if n <= 0 {
+		return nil
+	}
+	data, _ := json.Marshal(input) // map keys marshal sorted, so equal inputs match
+	s.Calls = appendBounded(s.Calls, fingerprint(tool+"\x00"+string(data)), 2*n)
+	period := repeating(s.Calls, n)
+	if period == 0 {
+		return nil
+	}
+	detail := fmt.Sprintf("%s called %d times in a row with the same input", tool, n)
+	if period == 2 {
+		detail = fmt.Sprintf("the same two tool calls alternated %d times", n)
+	}
+	return s.pause(PatternRepeatCalls, detail)
+}
+
+// RecordPrompt adds a prompt to the history and pauses the session if it
+// completes a loop of n repetitions (0 = never). Returns the new pause, or
+// nil.
+func (s *State) RecordPrompt(prompt string, n int) *Pause {
+	if n <= 0 {
+		return nil
+	}
+	s.Prompts = appendBounded(s.Prompts, fingerprint(prompt), 2*n)
+	period := repeating(s.Prompts, n)
+	if period == 0 {
+		return nil
+	}
+	detail := fmt.Sprintf("the same prompt arrived %d times in a row", n)
+	if period == 2 {
+		detail = fmt.Sprintf("the same two prompts alternated %d times", n)
+	}
+	return s.pause(PatternRepeatPrompts, detail)
+}
+
+// RecordTokens adds a token count sample, dropping samples older than
+// BurnWindow. A count lower than the last one (a new session or a
+// compaction) restarts the samples.
+func (s *State) RecordTokens(at time.Time, tokens int) {
+	if n := len(s.Samples); n > 0 && tokens < s.Samples[n-1].Tokens {
+		s.Samples = nil
+	}
+	s.Samples = append(s.Samples, Sample{At: at, Tokens: tokens})
+	cutoff := at.Add(-BurnWindow)
+	for len(s.Samples) > 2 && s.Samples[0].At.Before(cutoff) {
+		s.Samples = s.Samples[1:]
+	}
+}
+
+// Rate returns the session's tokens per minute over its samples, or false
+// if they span too little time to tell.
+func (s *State) Rate() (float64, bool) {
+	if len(s.Samples) < 2 {
+		return 0, false
+	}
+	first, last := s.Samples[0], s.Samples[len(s.Samples)-1]
+	minutes := last.At.Sub(first.At).Minutes()
+	if minutes < 1 {
+		return 0, false
+	}
+	return float64(last.Tokens-first.Tokens) / minutes, true
+}
+
+// CheckBurn pauses the session if its rate exceeds factor times baseline
+// (factor 0 = never). Returns the new pause, or nil.
+func (s *State) CheckBurn(baseline float64, factor int) *Pause {
+	rate, ok := s.Rate()
+	if !ok || factor <= 0 || baseline <= 0 || rate <= baseline*float64(factor) {
+		return nil
+	}
+	return s.pause(PatternBurnRate, fmt.Sprintf("burning %.0f tokens/min, %.1fx the baseline of %.0f",
+		rate, rate/baseline, baseline))
+}
+
+// Baseline returns the median of rates, or false if there are too few to
+// trust.
+func Baseline(rates []float64) (float64, bool) {
+	if len(rates) < minBaselineSessions {
+		return 0, false
+	}
+	sorted := append([]float64(nil), rates...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2, true
+	}
+	return sorted[mid], true
+}
+
+// repeating returns the period (1 or 2) of the loop the end of history
+// forms when it repeats n times, or 0 if it doesn't.
+func repeating(history []string, n int) int {
+	for period := 1; period <= 2; period++ {
+		span := period * n
+		if len(history) < span {
+			continue
+		}
+		tail := history[len(history)-span:]
+		if period == 2 && tail[0] == tail[1] {
+			continue // a run of one call, which period 1 reports
+		}
+		loop := true
+		for i := period; i < span; i++ {
+			if tail[i] != tail[i-period] {
+				loop = false
+				break
+			}
+		}
+		if loop {
+			return period
+		}
+	}
+	return 0
+}
+
+// appendBounded appends v to s, keeping at most max entries.
+func appendBounded(s []string, v string, max int) []string {
+	s = append(s, v)
+	if len(s) > max {
+		s = append(s[:0], s[len(s)-max:]...)
+	}
+	return s
+}
+
+// fingerprint returns a short hash of s.
+func fingerprint(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:8])
+}
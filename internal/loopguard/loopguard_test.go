@@ -0,0 +1,131 @@
+package loopguard
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordCall(t *testing.T) {
+	same := map[string]interface{}{"command": "go test ./..."}
+
+	s := &State{Session: "gt-gastown-Toast"}
+	for i := 1; i < 4; i++ {
+		if p := s.RecordCall("Bash", same, 4); p != nil {
+			t.Fatalf("paused after %d identical calls: %+v", i, p)
+		}
+	}
+	if p := s.RecordCall("Bash", same, 4); p == nil || p.Pattern != PatternRepeatCalls {
+		t.Fatalf("4th identical call: pause = %+v, want %s", p, PatternRepeatCalls)
+	}
+	if p := s.RecordCall("Bash", same, 4); p != nil {
+		t.Error("an already paused session reported a new pause")
+	}
+
+	// A different call in between breaks the run
+	s = &State{}
+	for _, cmd := range []string{"go test", "go test", "go test", "vim x.go", "go test"} {
+		if p := s.RecordCall("Bash", map[string]interface{}{"command": cmd}, 4); p != nil {
+			t.Fatalf("paused on a broken run at %q", cmd)
+		}
+	}
+
+	// Two calls alternating
+	s = &State{}
+	var p *Pause
+	for i := 0; i < 8 && p == nil; i++ {
+		p = s.RecordCall("Read", map[string]interface{}{"file_path": []string{"a.go", "b.go"}[i%2]}, 4)
+	}
+	if p == nil || !strings.Contains(p.Detail, "alternated") {
+		t.Errorf("alternating calls: pause = %+v", p)
+	}
+
+	// 0 disables the check
+	s = &State{}
+	for i := 0; i < 20; i++ {
+		if s.RecordCall("Bash", same, 0) != nil {
+			t.Fatal("paused with the check off")
+		}
+	}
+	if len(s.Calls) != 0 {
+		t.Errorf("recorded %d calls with the check off", len(s.Calls))
+	}
+}
+
+func TestRecordPrompt(t *testing.T) {
+	s := &State{}
+	var p *Pause
+	prompts := []string{"Are you done?", "Not yet, still working"}
+	for i := 0; i < 6 && p == nil; i++ {
+		p = s.RecordPrompt(prompts[i%2], 3)
+	}
+	if p == nil || p.Pattern != PatternRepeatPrompts {
+		t.Fatalf("ping-pong prompts: pause = %+v", p)
+	}
+	if len(s.Prompts) > 6 {
+		t.Errorf("kept %d prompts, want at most 6", len(s.Prompts))
+	}
+}
+
+func TestCheckBurn(t *testing.T) {
+	start := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	s := &State{}
+	s.RecordTokens(start, 10_000)
+	if _, ok := s.Rate(); ok {
+		t.Error("rate known from a single sample")
+	}
+	s.RecordTokens(start.Add(5*time.Minute), 60_000)
+	if rate, _ := s.Rate(); rate != 10_000 {
+		t.Errorf("rate = %v, want 10000/min", rate)
+	}
+	if p := s.CheckBurn(5_000, 5); p != nil {
+		t.Errorf("2x the baseline paused: %+v", p)
+	}
+	if p := s.CheckBurn(1_000, 5); p == nil || p.Pattern != PatternBurnRate {
+		t.Errorf("10x the baseline: pause = %+v", p)
+	}
+
+	// A lower count starts over
+	s.RecordTokens(start.Add(6*time.Minute), 500)
+	if len(s.Samples) != 1 {
+		t.Errorf("samples after a reset = %d, want 1", len(s.Samples))
+	}
+
+	// Old samples age out
+	s = &State{}
+	for i := 0; i < 10; i++ {
+		s.RecordTokens(start.Add(time.Duration(i)*5*time.Minute), i*1000)
+	}
+	if got := s.Samples[len(s.Samples)-1].At.Sub(s.Samples[0].At); got > BurnWindow {
+		t.Errorf("samples span %s, want at most %s", got, BurnWindow)
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	if _, ok := Baseline([]float64{100, 200}); ok {
+		t.Error("baseline from two sessions")
+	}
+	if b, _ := Baseline([]float64{300, 100, 90000, 200}); b != 250 {
+		t.Errorf("baseline = %v, want the median 250", b)
+	}
+}
+
+func TestStatePersistence(t *testing.T) {
+	town := t.TempDir()
+	s, err := Load(town, "gt-gastown-Toast")
+	if err != nil || s.Paused() {
+		t.Fatalf("Load of a new session = %+v, %v", s, err)
+	}
+	s.pause(PatternRepeatCalls, "test")
+	if err := s.Save(town); err != nil {
+		t.Fatal(err)
+	}
+	states, err := List(town)
+	if err != nil || len(states) != 1 || !states[0].Paused() || states[0].Session != "gt-gastown-Toast" {
+		t.Fatalf("List = %+v, %v", states, err)
+	}
+	states[0].Resume()
+	if states[0].Paused() || states[0].Calls != nil {
+		t.Errorf("after Resume: %+v", states[0])
+	}
+}
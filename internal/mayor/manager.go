@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/account"
 	"github.com/steveyegge/gastown/internal/claude"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
@@ -92,6 +93,10 @@ func (m *Manager) Start(agentOverride string) error {
 	if err != nil {
 		return fmt.Errorf("building startup command: %w", err)
 	}
+	startupCmd, err = account.PrepareSession(m.townRoot, account.Selection{Role: constants.RoleMayor}, sessionID, startupCmd)
+	if err != nil {
+		return fmt.Errorf("resolving account: %w", err)
+	}
 
 	// Create session in townRoot (not mayorDir) to match gt handoff behavior
 	// This ensures Mayor works from the town root where all tools work correctly
@@ -0,0 +1,179 @@
+// Package migrate upgrades a town's JSON state files from older schema
+// versions, so a format can change without hand-editing the files of every
+// town on upgrade.
+//
+// Each state file carries its schema version in a top-level "version" key.
+// A Migration upgrades one kind of file from one version to the next; to
+// change a format, bump its Current*Version constant in the config package
+// and add a migration from the old version to Migrations. Town applies the
+// pending migrations to a town's files in place (gt town migrate, gt doctor
+// --fix), and Document applies them to a single file's contents, as gt town
+// import does for bundles exported by an older gt.
+package migrate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Migration upgrades a kind of state file from version From to From+1.
+type Migration struct {
+	// File is the file's path relative to the town root, as a
+	// filepath.Match pattern; "*" in it matches a rig's directory.
+	File string
+
+	// From is the version the migration upgrades.
+	From int
+
+	// Summary says what changes, e.g. "add public_name".
+	Summary string
+
+	// Up changes the file's decoded contents in place. It needn't set the
+	// version.
+	Up func(doc map[string]interface{}) error
+}
+
+// Step is a migration of a particular file.
+type Step struct {
+	Path string // relative to the town root
+	Migration
+}
+
+// String describes the step, e.g. "mayor/town.json v1 → v2: add public_name".
+func (s Step) String() string {
+	return fmt.Sprintf("%s v%d → v%d: %s", s.Path, s.From, s.From+1, s.Summary)
+}
+
+// Document upgrades doc, the contents of the file at path (relative to the
+// town root), through every migration pending for it, in place. It returns
+// the steps it applied.
+func Document(path string, doc map[string]interface{}) ([]Step, error) {
+	path = filepath.ToSlash(path)
+	var steps []Step
+	for {
+		m := pending(path, version(doc))
+		if m == nil {
+			return steps, nil
+		}
+		if err := m.Up(doc); err != nil {
+			return steps, fmt.Errorf("%s v%d: %w", path, m.From, err)
+		}
+		doc["version"] = m.From + 1
+		steps = append(steps, Step{Path: path, Migration: *m})
+	}
+}
+
+// Town applies the pending migrations to the town's state files, each
+// rewritten with its original kept beside it as <file>.v<N>.bak. With
+// dryRun, it only returns the steps that are pending.
+func Town(townRoot string, dryRun bool) ([]Step, error) {
+	files := make(map[string]bool)
+	for _, m := range Migrations {
+		matches, err := filepath.Glob(filepath.Join(townRoot, filepath.FromSlash(m.File)))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			files[match] = true
+		}
+	}
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var all []Step
+	for _, path := range paths {
+		rel, err := filepath.Rel(townRoot, path)
+		if err != nil {
+			return all, err
+		}
+		steps, err := migrateFile(path, rel, dryRun)
+		all = append(all, steps...)
+		if err != nil {
+			return all, err
+		}
+	}
+	return all, nil
+}
+
+// migrateFile applies the migrations pending for one file.
+func migrateFile(path, rel string, dryRun bool) ([]Step, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is a state file in the town
+	if err != nil {
+		return nil, err
+	}
+	doc, err := Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", rel, err)
+	}
+	from := version(doc)
+	steps, err := Document(rel, doc)
+	if err != nil || len(steps) == 0 || dryRun {
+		return steps, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	backup := fmt.Sprintf("%s.v%d.bak", path, from)
+	if err := os.WriteFile(backup, data, info.Mode().Perm()); err != nil {
+		return nil, fmt.Errorf("backing up %s: %w", rel, err)
+	}
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := util.AtomicWriteFile(path, out, info.Mode().Perm()); err != nil {
+		return nil, err
+	}
+	return steps, nil
+}
+
+// Decode decodes a JSON state file, keeping its numbers as written.
+func Decode(data []byte) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// version returns a document's schema version, or 0 if it has none.
+func version(doc map[string]interface{}) int {
+	switch v := doc["version"].(type) {
+	case json.Number:
+		n, _ := strconv.Atoi(v.String())
+		return n
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
+
+// pending returns the migration for the file at path from version, if any.
+func pending(path string, from int) *Migration {
+	for i := range Migrations {
+		m := &Migrations[i]
+		if m.From != from {
+			continue
+		}
+		if ok, _ := filepath.Match(m.File, path); ok {
+			return m
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// currentVersions are the schema versions the migrations must reach, by
+// file pattern.
+var currentVersions = map[string]int{
+	"mayor/town.json": config.CurrentTownVersion,
+}
+
+func TestMigrations_ReachCurrentVersions(t *testing.T) {
+	for _, m := range Migrations {
+		current, ok := currentVersions[m.File]
+		if !ok {
+			t.Errorf("migration for %s: add its current version to currentVersions", m.File)
+			continue
+		}
+		if m.From+1 > current {
+			t.Errorf("migration for %s v%d goes past the current version %d", m.File, m.From, current)
+		}
+	}
+	for file, current := range currentVersions {
+		doc := map[string]interface{}{"version": 1}
+		if _, err := Document(file, doc); err != nil {
+			t.Fatalf("Document(%s): %v", file, err)
+		}
+		if got := version(doc); got != current {
+			t.Errorf("%s migrates from v1 to v%d, want v%d (is a migration missing?)", file, got, current)
+		}
+	}
+}
+
+func TestDocument(t *testing.T) {
+	doc, err := Decode([]byte(`{"type":"town","version":1,"name":"west","created_at":"2025-01-02T00:00:00Z"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps, err := Document("mayor/town.json", doc)
+	if err != nil {
+		t.Fatalf("Document: %v", err)
+	}
+	if len(steps) != 1 || steps[0].String() != "mayor/town.json v1 → v2: add public_name for federation identity" {
+		t.Errorf("steps = %v", steps)
+	}
+	if doc["public_name"] != "west" || version(doc) != 2 {
+		t.Errorf("doc = %v", doc)
+	}
+
+	// Current files, and files no migration knows, are left alone
+	if steps, _ := Document("mayor/town.json", doc); len(steps) != 0 {
+		t.Errorf("re-migrated a current file: %v", steps)
+	}
+	if steps, _ := Document("settings/other.json", map[string]interface{}{"version": 1}); len(steps) != 0 {
+		t.Errorf("migrated an unknown file: %v", steps)
+	}
+}
+
+func TestTown(t *testing.T) {
+	townRoot := t.TempDir()
+	path := filepath.Join(townRoot, "mayor", "town.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	original := `{"type":"town","version":1,"name":"west","created_at":"2025-01-02T00:00:00Z"}`
+	if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	steps, err := Town(townRoot, true)
+	if err != nil || len(steps) != 1 {
+		t.Fatalf("dry run = %v, %v; want one step", steps, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Errorf("dry run changed town.json: %s", data)
+	}
+
+	if _, err := Town(townRoot, false); err != nil {
+		t.Fatalf("Town: %v", err)
+	}
+	cfg, err := config.LoadTownConfig(path)
+	if err != nil {
+		t.Fatalf("loading migrated town.json: %v", err)
+	}
+	if cfg.Version != 2 || cfg.PublicName != "west" || cfg.CreatedAt.IsZero() {
+		t.Errorf("migrated town = %+v", cfg)
+	}
+	if backup, err := os.ReadFile(path + ".v1.bak"); err != nil || string(backup) != original {
+		t.Errorf("backup = %q, %v; want the original", backup, err)
+	}
+	if steps, _ := Town(townRoot, true); len(steps) != 0 {
+		t.Errorf("still pending after migrating: %v", steps)
+	}
+}
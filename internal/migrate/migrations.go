@@ -0,0 +1,23 @@
+package migrate
+
+// Migrations are the registered migrations. Add one here with each bump of
+// a Current*Version constant in the config package.
+var Migrations = []Migration{
+	{
+		File:    "mayor/town.json",
+		From:    1,
+		Summary: "add public_name for federation identity",
+		Up:      townPublicName,
+	},
+}
+
+// townPublicName gives a version 1 town.json the public_name version 2
+// added, defaulting to the town's name as gt install does. owner is left
+// unset; gt install takes it from git config, which a migration shouldn't
+// guess at.
+func townPublicName(doc map[string]interface{}) error {
+	if name, ok := doc["name"].(string); ok && doc["public_name"] == nil {
+		doc["public_name"] = name
+	}
+	return nil
+}
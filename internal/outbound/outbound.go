@@ -0,0 +1,275 @@
+// Package outbound filters prompts before they leave for the model's API,
+// for teams with rules about what may be sent to a third party.
+//
+// A Filter matches built-in PII detectors (emails, phone numbers, US social
+// security numbers, payment card numbers), the town's own regular
+// expressions, and a deny list of terms, from gastown.toml's [outbound]
+// section. Each match is redacted, replaced by a pseudonym (a keyed hash
+// that is the same every time, so the model can still tell two values
+// apart), or, in block mode, stops the prompt from being sent at all.
+//
+// gt applies the filter to the prompts it nudges into sessions, where it
+// can rewrite them. Prompts typed or pasted into a session reach the
+// runtime's UserPromptSubmit hook, which can only refuse them, so there any
+// match blocks the prompt.
+package outbound
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Filtered replaces each match in redact mode.
+const Filtered = "[FILTERED]"
+
+// ErrBlocked is returned for a prompt the filter refuses to send.
+var ErrBlocked = errors.New("prompt blocked by the outbound filter")
+
+// rule is one thing the filter matches.
+type rule struct {
+	name  string // reported in matches (e.g., "email", "deny list")
+	label string // prefixes pseudonyms (e.g., "email", "term")
+	re    *regexp.Regexp
+	valid func(match string) bool // nil = every match counts
+}
+
+// piiRules are the built-in detectors, most specific first so a card
+// number isn't taken for a phone number.
+var piiRules = []rule{
+	{name: "email", label: "email", re: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)},
+	{name: "card", label: "card", re: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), valid: luhn},
+	{name: "ssn", label: "ssn", re: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)},
+	{name: "phone", label: "phone", re: regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?(?:\(\d{3}\)|\b\d{3})[ .-]?\d{3}[ .-]?\d{4}\b`)},
+}
+
+// wordStart and wordEnd match a term that starts or ends with a word
+// character, where a deny list match must be at a word boundary.
+var (
+	wordStart = regexp.MustCompile(`^\w`)
+	wordEnd   = regexp.MustCompile(`\w$`)
+)
+
+// luhn reports whether the digits in s pass the Luhn checksum, as payment
+// card numbers do.
+func luhn(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
+
+// Filter applies a town's outbound rules. A nil Filter passes everything.
+type Filter struct {
+	rules []rule
+	mode  string
+	key   []byte
+}
+
+// New returns the filter cfg describes, or nil if it matches nothing. key
+// keys pseudonyms; without one they are plain SHA-256, which a guess at a
+// short value (a phone number, say) can confirm.
+func New(cfg config.OutboundConfig, key []byte) (*Filter, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+	f := &Filter{mode: cfg.Action(), key: key}
+	if cfg.PII {
+		f.rules = append(f.rules, piiRules...)
+	}
+	for _, p := range cfg.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid outbound pattern %q: %w", p, err)
+		}
+		f.rules = append(f.rules, rule{name: "pattern " + p, label: "pii", re: re})
+	}
+	if len(cfg.Deny) > 0 {
+		terms := make([]string, 0, len(cfg.Deny))
+		for _, term := range cfg.Deny {
+			term = regexp.QuoteMeta(strings.TrimSpace(term))
+			// Whole words only, where the term starts or ends with one
+			if wordStart.MatchString(term) {
+				term = `\b` + term
+			}
+			if wordEnd.MatchString(term) {
+				term += `\b`
+			}
+			terms = append(terms, term)
+		}
+		f.rules = append(f.rules, rule{name: "deny list", label: "term", re: regexp.MustCompile(`(?i)` + strings.Join(terms, "|"))})
+	}
+	return f, nil
+}
+
+// Load returns the filter from townRoot's gastown.toml, or nil if it has
+// none.
+func Load(townRoot string) (*Filter, error) {
+	cfg, err := config.LoadGastownConfig(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var key []byte
+	if cfg.Outbound.HashKey != "" {
+		resolved, err := config.ExpandRefs(cfg.Outbound.HashKey)
+		if err != nil {
+			return nil, fmt.Errorf("resolving outbound.hash_key: %w", err)
+		}
+		key = []byte(resolved)
+	}
+	return New(cfg.Outbound, key)
+}
+
+// Mode returns what the filter does with a match.
+func (f *Filter) Mode() string {
+	if f == nil {
+		return ""
+	}
+	return f.mode
+}
+
+// Match counts one rule's matches in a text.
+type Match struct {
+	Rule  string `json:"rule"`
+	Count int    `json:"count"`
+}
+
+// Result is a text after filtering.
+type Result struct {
+	Text    string  `json:"text"`    // the text to send; unchanged if blocked
+	Matches []Match `json:"matches"` // rules that matched, in rule order
+	Blocked bool    `json:"blocked"` // in block mode, something matched
+}
+
+// Filtered reports whether anything matched.
+func (r Result) Filtered() bool {
+	return len(r.Matches) > 0
+}
+
+// Rules returns the names of the rules that matched.
+func (r Result) Rules() []string {
+	names := make([]string, 0, len(r.Matches))
+	for _, m := range r.Matches {
+		names = append(names, m.Rule)
+	}
+	return names
+}
+
+// Total returns how many matches there were in all.
+func (r Result) Total() int {
+	n := 0
+	for _, m := range r.Matches {
+		n += m.Count
+	}
+	return n
+}
+
+// Apply filters text.
+func (f *Filter) Apply(text string) Result {
+	res := Result{Text: text, Matches: []Match{}}
+	if f == nil || text == "" {
+		return res
+	}
+	out := text
+	for _, r := range f.rules {
+		count := 0
+		out = r.re.ReplaceAllStringFunc(out, func(m string) string {
+			if r.valid != nil && !r.valid(m) {
+				return m
+			}
+			count++
+			return f.replacement(r, m)
+		})
+		if count > 0 {
+			res.Matches = append(res.Matches, Match{Rule: r.name, Count: count})
+		}
+	}
+	if f.mode == config.OutboundBlock {
+		res.Blocked = res.Filtered()
+		return res
+	}
+	res.Text = out
+	return res
+}
+
+// replacement returns what replaces match m of rule r.
+func (f *Filter) replacement(r rule, m string) string {
+	if f.mode != config.OutboundPseudonymize {
+		return Filtered
+	}
+	return "[" + r.label + ":" + f.pseudonym(m) + "]"
+}
+
+// pseudonym returns the stable token for a matched value. Case is ignored
+// so "Jane@Example.com" and "jane@example.com" are the same person.
+func (f *Filter) pseudonym(m string) string {
+	value := []byte(strings.ToLower(m))
+	var sum []byte
+	if f.key != nil {
+		mac := hmac.New(sha256.New, f.key)
+		mac.Write(value)
+		sum = mac.Sum(nil)
+	} else {
+		s := sha256.Sum256(value)
+		sum = s[:]
+	}
+	return hex.EncodeToString(sum[:4])
+}
+
+// Prompt filters a prompt gt is about to send into session, for the town
+// the working directory is in, and records what was filtered in the event
+// log. It returns the prompt to send, or an error wrapping ErrBlocked.
+// Prompts out of a town pass unchanged; with a config that can't be loaded,
+// none are sent, since the filter can't be applied.
+func Prompt(session, prompt string) (string, error) {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return prompt, nil
+	}
+	f, err := Load(townRoot)
+	if err != nil {
+		return "", fmt.Errorf("outbound filter: %w", err)
+	}
+	if f == nil {
+		return prompt, nil
+	}
+	res := f.Apply(prompt)
+	if !res.Filtered() {
+		return prompt, nil
+	}
+	_ = events.LogAudit(events.TypePromptFiltered, actor(), events.PromptFilteredPayload(session, f.Mode(), res.Rules(), res.Total()))
+	if res.Blocked {
+		return "", fmt.Errorf("%w: matches %s", ErrBlocked, strings.Join(res.Rules(), ", "))
+	}
+	return res.Text, nil
+}
+
+// actor returns who is sending, for the event log.
+func actor() string {
+	if a := os.Getenv("BD_ACTOR"); a != "" {
+		return a
+	}
+	return "gt"
+}
@@ -0,0 +1,70 @@
+package outbound
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   config.OutboundConfig
+		text  string
+		want  string
+		rules string
+	}{
+		{"disabled", config.OutboundConfig{}, "mail jane@example.com", "mail jane@example.com", ""},
+		{"email", config.OutboundConfig{PII: true}, "mail jane@example.com today", "mail [FILTERED] today", "email"},
+		{"card passes luhn", config.OutboundConfig{PII: true}, "card 4111 1111 1111 1111", "card [FILTERED]", "card"},
+		{"not a card", config.OutboundConfig{PII: true}, "build 4111111111111112 failed", "build 4111111111111112 failed", ""},
+		{"ssn and phone", config.OutboundConfig{PII: true}, "ssn 123-45-6789, call (555) 123-4567", "ssn [FILTERED], call [FILTERED]", "ssn, phone"},
+		{"pattern", config.OutboundConfig{Patterns: []string{`ACME-[0-9]{6}`}}, "ticket ACME-123456", "ticket [FILTERED]", "pattern ACME-[0-9]{6}"},
+		{"deny ignores case", config.OutboundConfig{Deny: []string{"Project Falcon"}}, "ship project falcon", "ship [FILTERED]", "deny list"},
+		{"deny whole words", config.OutboundConfig{Deny: []string{"globex"}}, "globexcorp and Globex", "globexcorp and [FILTERED]", "deny list"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(tt.cfg, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res := f.Apply(tt.text)
+			if res.Text != tt.want {
+				t.Errorf("text = %q, want %q", res.Text, tt.want)
+			}
+			if got := strings.Join(res.Rules(), ", "); got != tt.rules {
+				t.Errorf("rules = %q, want %q", got, tt.rules)
+			}
+		})
+	}
+}
+
+func TestApplyPseudonymize(t *testing.T) {
+	cfg := config.OutboundConfig{PII: true, Mode: config.OutboundPseudonymize}
+	f, _ := New(cfg, []byte("k1"))
+	res := f.Apply("jane@example.com wrote to bob@example.com, cc Jane@Example.com")
+	tokens := strings.Fields(strings.NewReplacer(",", "").Replace(res.Text))
+	if !strings.HasPrefix(tokens[0], "[email:") || tokens[0] != tokens[5] || tokens[0] == tokens[3] {
+		t.Errorf("pseudonyms not stable per value: %q", res.Text)
+	}
+	if res.Total() != 3 {
+		t.Errorf("total = %d, want 3", res.Total())
+	}
+	other, _ := New(cfg, []byte("k2"))
+	if again := other.Apply("jane@example.com"); strings.Contains(res.Text, again.Text) {
+		t.Errorf("a different key gave the same pseudonym %q", again.Text)
+	}
+}
+
+func TestApplyBlock(t *testing.T) {
+	f, _ := New(config.OutboundConfig{Deny: []string{"Falcon"}, Mode: config.OutboundBlock}, nil)
+	res := f.Apply("about Falcon")
+	if !res.Blocked || res.Text != "about Falcon" {
+		t.Errorf("block mode: %+v", res)
+	}
+	if res := f.Apply("about nothing"); res.Blocked || res.Filtered() {
+		t.Errorf("clean text: %+v", res)
+	}
+}
@@ -0,0 +1,344 @@
+// Package outcome records what happened to each work assignment and
+// aggregates success rates per role, worker, rig, and agent.
+//
+// An assignment starts when a bead is slung to an agent and collects facts as
+// it progresses: gt done (PR opened, tests added, wall time), the Refinery
+// (merged, rejected, later reverted), and gt costs record (cost, tokens).
+// Facts are appended to <town>/.runtime/outcomes.jsonl as they happen and are
+// folded into one Outcome per assignment when read, so writers in different
+// processes never read-modify-write shared state.
+package outcome
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Update kinds.
+const (
+	KindAssigned    = "assigned"     // bead slung to an agent (starts an assignment)
+	KindDone        = "done"         // agent ran gt done
+	KindMerged      = "merged"       // Refinery merged the work
+	KindMergeFailed = "merge_failed" // Refinery rejected the work
+	KindReverted    = "reverted"     // merged work was later reverted
+	KindCost        = "cost"         // session cost/tokens snapshot
+)
+
+// File is the outcome log, relative to the town root.
+const File = ".runtime/outcomes.jsonl"
+
+// Update is one fact about an assignment, as appended to the log.
+type Update struct {
+	Time time.Time `json:"ts"`
+	Kind string    `json:"kind"`
+	Bead string    `json:"bead,omitempty"`
+
+	// Assignment (KindAssigned; Agent also attributes KindCost without a bead)
+	Agent  string `json:"agent,omitempty"` // address (e.g., "gastown/polecats/nux")
+	Rig    string `json:"rig,omitempty"`
+	Role   string `json:"role,omitempty"`
+	Worker string `json:"worker,omitempty"`
+	Model  string `json:"model,omitempty"` // agent preset (e.g., "claude", "codex")
+
+	// gt done
+	Exit       string   `json:"exit,omitempty"` // COMPLETED, ESCALATED, DEFERRED, ...
+	PRURL      string   `json:"pr_url,omitempty"`
+	TestsAdded int      `json:"tests_added,omitempty"` // test files added or changed
+	Commits    []string `json:"commits,omitempty"`     // branch commits submitted
+
+	// Refinery
+	Commit string `json:"commit,omitempty"` // merge commit, or the reverted commit
+	Reason string `json:"reason,omitempty"` // merge failure type
+
+	// Costs (cumulative per session; the latest snapshot wins)
+	Session string  `json:"session,omitempty"`
+	CostUSD float64 `json:"cost_usd,omitempty"`
+	Tokens  int     `json:"tokens,omitempty"`
+}
+
+// Outcome is the folded record of one assignment.
+type Outcome struct {
+	Bead   string `json:"bead"`
+	Agent  string `json:"agent,omitempty"`
+	Rig    string `json:"rig,omitempty"`
+	Role   string `json:"role,omitempty"`
+	Worker string `json:"worker,omitempty"`
+	Model  string `json:"model,omitempty"`
+
+	AssignedAt time.Time `json:"assigned_at,omitempty"`
+	DoneAt     time.Time `json:"done_at,omitempty"`
+	MergedAt   time.Time `json:"merged_at,omitempty"`
+
+	Exit          string   `json:"exit,omitempty"`
+	PRURL         string   `json:"pr_url,omitempty"`
+	TestsAdded    int      `json:"tests_added,omitempty"`
+	Commits       []string `json:"commits,omitempty"`
+	Merged        bool     `json:"merged"`
+	MergeCommit   string   `json:"merge_commit,omitempty"`
+	MergeFailures int      `json:"merge_failures,omitempty"`
+	LastFailure   string   `json:"last_failure,omitempty"`
+	Reverted      bool     `json:"reverted"`
+
+	CostUSD float64 `json:"cost_usd"`
+	Tokens  int     `json:"tokens,omitempty"`
+
+	sessions map[string]Update // latest cost snapshot per session
+}
+
+// WallTime is the time from assignment to gt done, or 0 if unknown.
+func (o *Outcome) WallTime() time.Duration {
+	if o.AssignedAt.IsZero() || o.DoneAt.IsZero() {
+		return 0
+	}
+	return o.DoneAt.Sub(o.AssignedAt)
+}
+
+// Succeeded reports whether the work was merged and has not been reverted.
+func (o *Outcome) Succeeded() bool {
+	return o.Merged && !o.Reverted
+}
+
+// hasCommit reports whether sha (full or abbreviated) is the assignment's
+// merge commit or one of its branch commits.
+func (o *Outcome) hasCommit(sha string) bool {
+	if len(sha) < 7 {
+		return false
+	}
+	matches := func(c string) bool {
+		return c != "" && (strings.HasPrefix(c, sha) || strings.HasPrefix(sha, c))
+	}
+	if matches(o.MergeCommit) {
+		return true
+	}
+	for _, c := range o.Commits {
+		if matches(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// Path returns the outcome log path for a town.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, File)
+}
+
+// mu serializes appends from this process; O_APPEND keeps lines whole across processes.
+var mu sync.Mutex
+
+// Record appends an update to the town's outcome log.
+func Record(townRoot string, u Update) error {
+	if u.Kind == "" {
+		return fmt.Errorf("outcome update has no kind")
+	}
+	if u.Time.IsZero() {
+		u.Time = time.Now().UTC()
+	}
+	data, err := json.Marshal(u)
+	if err != nil {
+		return fmt.Errorf("marshaling outcome: %w", err)
+	}
+	data = append(data, '\n')
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	path := Path(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: outcome log is non-sensitive operational data
+	if err != nil {
+		return fmt.Errorf("opening outcome log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("writing outcome: %w", err)
+	}
+	return nil
+}
+
+// ReadUpdates reads the town's outcome log. A missing log has no updates;
+// malformed lines are skipped.
+func ReadUpdates(townRoot string) ([]Update, error) {
+	f, err := os.Open(Path(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var updates []Update
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var u Update
+		if err := json.Unmarshal(scanner.Bytes(), &u); err != nil || u.Kind == "" {
+			continue
+		}
+		updates = append(updates, u)
+	}
+	return updates, scanner.Err()
+}
+
+// Load reads and folds the town's outcome log.
+func Load(townRoot string) ([]*Outcome, error) {
+	updates, err := ReadUpdates(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	return Fold(updates), nil
+}
+
+// Fold replays updates in time order into one Outcome per assignment,
+// oldest assignment first.
+//
+// Each assigned update starts a new assignment for its bead, so a re-slung
+// bead gets a fresh record. Other updates apply to the bead's latest
+// assignment (one is started implicitly if the bead was never assigned).
+// Cost updates without a bead apply to the agent's latest assignment;
+// reverts without a bead apply to the assignment that owns the commit.
+func Fold(updates []Update) []*Outcome {
+	sorted := append([]Update(nil), updates...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time.Before(sorted[j].Time)
+	})
+
+	var all []*Outcome
+	byBead := make(map[string]*Outcome)
+	byAgent := make(map[string]*Outcome)
+	start := func(u Update) *Outcome {
+		o := &Outcome{Bead: u.Bead}
+		all = append(all, o)
+		if u.Bead != "" {
+			byBead[u.Bead] = o
+		}
+		return o
+	}
+
+	for _, u := range sorted {
+		var o *Outcome
+		switch {
+		case u.Kind == KindAssigned:
+			o = start(u)
+			o.AssignedAt = u.Time
+		case u.Bead != "":
+			if o = byBead[u.Bead]; o == nil {
+				o = start(u)
+			}
+		case u.Kind == KindCost && u.Agent != "":
+			o = byAgent[u.Agent]
+		case u.Kind == KindReverted && u.Commit != "":
+			for i := len(all) - 1; i >= 0 && o == nil; i-- {
+				if all[i].hasCommit(u.Commit) {
+					o = all[i]
+				}
+			}
+		}
+		if o == nil {
+			continue
+		}
+		apply(o, u)
+		if o.Agent != "" && (u.Kind == KindAssigned || byAgent[o.Agent] == nil) {
+			byAgent[o.Agent] = o
+		}
+	}
+	return all
+}
+
+// apply folds one update into an outcome.
+func apply(o *Outcome, u Update) {
+	setIfEmpty := func(dst *string, v string) {
+		if *dst == "" {
+			*dst = v
+		}
+	}
+	setIfEmpty(&o.Agent, u.Agent)
+	setIfEmpty(&o.Rig, u.Rig)
+	setIfEmpty(&o.Role, u.Role)
+	setIfEmpty(&o.Worker, u.Worker)
+	setIfEmpty(&o.Model, u.Model)
+
+	switch u.Kind {
+	case KindDone:
+		o.DoneAt = u.Time
+		o.Exit = u.Exit
+		if u.PRURL != "" {
+			o.PRURL = u.PRURL
+		}
+		if u.TestsAdded > 0 {
+			o.TestsAdded = u.TestsAdded
+		}
+		if len(u.Commits) > 0 {
+			o.Commits = u.Commits
+		}
+	case KindMerged:
+		o.Merged = true
+		o.MergedAt = u.Time
+		if u.Commit != "" {
+			o.MergeCommit = u.Commit
+		}
+	case KindMergeFailed:
+		o.MergeFailures++
+		o.LastFailure = u.Reason
+	case KindReverted:
+		o.Reverted = true
+	case KindCost:
+		if o.sessions == nil {
+			o.sessions = make(map[string]Update)
+		}
+		o.sessions[u.Session] = u
+		o.CostUSD, o.Tokens = 0, 0
+		for _, s := range o.sessions {
+			o.CostUSD += s.CostUSD
+			o.Tokens += s.Tokens
+		}
+	}
+}
+
+// revertRegex matches the trailer git revert writes into commit messages.
+var revertRegex = regexp.MustCompile(`This reverts commit ([0-9a-f]{7,40})`)
+
+// RevertedCommits returns the commits that the given commit messages revert.
+func RevertedCommits(messages []string) []string {
+	var shas []string
+	for _, msg := range messages {
+		for _, m := range revertRegex.FindAllStringSubmatch(msg, -1) {
+			shas = append(shas, m[1])
+		}
+	}
+	return shas
+}
+
+// IsTestFile reports whether path looks like a test file in common
+// language conventions (Go, Python, JS/TS, Rust, Java, Ruby).
+func IsTestFile(path string) bool {
+	p := filepath.ToSlash(path)
+	base := filepath.Base(p)
+	switch {
+	case strings.HasSuffix(base, "_test.go"),
+		strings.HasPrefix(base, "test_") && strings.HasSuffix(base, ".py"),
+		strings.HasSuffix(base, "_test.py"),
+		strings.Contains(base, ".test."),
+		strings.Contains(base, ".spec."),
+		strings.HasSuffix(base, "_spec.rb"),
+		strings.HasSuffix(base, "Test.java"):
+		return true
+	}
+	for _, dir := range strings.Split(filepath.Dir(p), "/") {
+		if dir == "test" || dir == "tests" || dir == "__tests__" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package outcome
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestRecordAndLoad(t *testing.T) {
+	townRoot := t.TempDir()
+	t0 := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	for _, u := range []Update{
+		{Time: t0, Kind: KindAssigned, Bead: "gt-1", Agent: "gastown/polecats/nux", Rig: "gastown", Role: "polecat", Worker: "nux", Model: "claude"},
+		{Time: t0.Add(30 * time.Minute), Kind: KindDone, Bead: "gt-1", Exit: "COMPLETED", PRURL: "https://example.com/pr/1", TestsAdded: 2, Commits: []string{"abc1234def"}},
+		{Time: t0.Add(40 * time.Minute), Kind: KindMerged, Bead: "gt-1", Commit: "fff0000111"},
+	} {
+		if err := Record(townRoot, u); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	outcomes, err := Load(townRoot)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(outcomes) != 1 {
+		t.Fatalf("got %d outcomes, want 1", len(outcomes))
+	}
+	o := outcomes[0]
+	if !o.Succeeded() || o.PRURL == "" || o.TestsAdded != 2 || o.Model != "claude" {
+		t.Errorf("outcome = %+v", o)
+	}
+	if o.WallTime() != 30*time.Minute {
+		t.Errorf("WallTime = %v, want 30m", o.WallTime())
+	}
+
+	if got, err := Load(t.TempDir()); err != nil || got != nil {
+		t.Errorf("Load(empty town) = %v, %v", got, err)
+	}
+}
+
+func TestFold(t *testing.T) {
+	t0 := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	at := func(m int) time.Time { return t0.Add(time.Duration(m) * time.Minute) }
+
+	outcomes := Fold([]Update{
+		{Time: at(0), Kind: KindAssigned, Bead: "gt-1", Agent: "gastown/polecats/nux"},
+		// Cost snapshots are cumulative per session
+		{Time: at(5), Kind: KindCost, Agent: "gastown/polecats/nux", Session: "gt-gastown-nux", CostUSD: 0.50, Tokens: 1000},
+		{Time: at(10), Kind: KindCost, Agent: "gastown/polecats/nux", Session: "gt-gastown-nux", CostUSD: 1.25, Tokens: 3000},
+		{Time: at(12), Kind: KindDone, Bead: "gt-1", Exit: "COMPLETED", Commits: []string{"abc1234def"}},
+		{Time: at(15), Kind: KindMergeFailed, Bead: "gt-1", Reason: "tests"},
+		{Time: at(20), Kind: KindMerged, Bead: "gt-1"},
+		// Re-sling of another bead to the same polecat starts a new assignment
+		{Time: at(30), Kind: KindAssigned, Bead: "gt-2", Agent: "gastown/polecats/nux"},
+		{Time: at(35), Kind: KindCost, Agent: "gastown/polecats/nux", Session: "gt-gastown-nux-2", CostUSD: 2},
+		// Revert found by commit
+		{Time: at(60), Kind: KindReverted, Commit: "abc1234"},
+		// Unattributable cost is dropped
+		{Time: at(61), Kind: KindCost, Agent: "gastown/polecats/ghost", CostUSD: 9},
+	})
+	if len(outcomes) != 2 {
+		t.Fatalf("got %d outcomes, want 2", len(outcomes))
+	}
+	first, second := outcomes[0], outcomes[1]
+	if first.CostUSD != 1.25 || first.Tokens != 3000 {
+		t.Errorf("first cost = $%.2f / %d tokens, want $1.25 / 3000", first.CostUSD, first.Tokens)
+	}
+	if first.MergeFailures != 1 || first.LastFailure != "tests" || !first.Merged || !first.Reverted || first.Succeeded() {
+		t.Errorf("first = %+v, want merged after one failure, then reverted", first)
+	}
+	if second.Bead != "gt-2" || second.CostUSD != 2 {
+		t.Errorf("second = %+v, want gt-2 with $2", second)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	t0 := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	outcomes := []*Outcome{
+		{Bead: "a", Rig: "gastown", Role: "polecat", Model: "claude", Exit: "COMPLETED", Merged: true, CostUSD: 2, AssignedAt: t0, DoneAt: t0.Add(time.Hour), TestsAdded: 1},
+		{Bead: "b", Rig: "gastown", Role: "polecat", Model: "codex", Exit: "COMPLETED", Merged: true, Reverted: true, CostUSD: 1},
+		{Bead: "c", Rig: "beads", Role: "polecat", Model: "claude", Exit: "ESCALATED", CostUSD: 3, AssignedAt: t0, DoneAt: t0.Add(3 * time.Hour)},
+	}
+
+	total := Aggregate(outcomes, "")
+	if len(total) != 1 {
+		t.Fatalf("total groups = %d", len(total))
+	}
+	s := total[0]
+	if s.Assignments != 3 || s.Done != 2 || s.Merged != 2 || s.Reverted != 1 || s.WithTests != 1 {
+		t.Errorf("total = %+v", s)
+	}
+	if got := s.SuccessRate(); got < 0.33 || got > 0.34 {
+		t.Errorf("SuccessRate = %v, want 1/3", got)
+	}
+	if s.AvgWallTime() != 2*time.Hour {
+		t.Errorf("AvgWallTime = %v, want 2h", s.AvgWallTime())
+	}
+	if s.CostPerMerge() != 6 {
+		t.Errorf("CostPerMerge = %v, want 6", s.CostPerMerge())
+	}
+
+	byModel := Aggregate(outcomes, ByModel)
+	if len(byModel) != 2 || byModel[0].Key != "claude" || byModel[0].Assignments != 2 {
+		t.Errorf("by model = %+v", byModel)
+	}
+
+	if got := Since(outcomes, t0.Add(time.Minute)); len(got) != 0 {
+		t.Errorf("Since = %d outcomes, want 0", len(got))
+	}
+}
+
+func TestHelpers(t *testing.T) {
+	shas := RevertedCommits([]string{"Revert \"x\"\n\nThis reverts commit 0123456789abcdef.", "unrelated"})
+	if len(shas) != 1 || shas[0] != "0123456789abcdef" {
+		t.Errorf("RevertedCommits = %v", shas)
+	}
+
+	for path, want := range map[string]bool{
+		"internal/foo/foo_test.go": true,
+		"tests/test_api.py":        true,
+		"web/app.spec.ts":          true,
+		"internal/foo/foo.go":      false,
+		"docs/testing.md":          false,
+	} {
+		if got := IsTestFile(path); got != want {
+			t.Errorf("IsTestFile(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestStatsJSON(t *testing.T) {
+	s := Aggregate([]*Outcome{{Bead: "a", Merged: true, CostUSD: 2}}, "")[0]
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["key"] != "total" || got["merged"] != 1.0 || got["success_rate"] != 1.0 || got["cost_per_merge_usd"] != 2.0 {
+		t.Errorf("Stats JSON = %s", data)
+	}
+}
@@ -0,0 +1,160 @@
+package outcome
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// Grouping keys for Aggregate.
+const (
+	ByRole   = "role"
+	ByWorker = "worker"
+	ByRig    = "rig"
+	ByModel  = "model"
+)
+
+// Stats aggregates the outcomes of a group of assignments.
+type Stats struct {
+	Key           string        `json:"key"`
+	Assignments   int           `json:"assignments"`
+	Done          int           `json:"done"` // finished with gt done COMPLETED
+	PRsOpened     int           `json:"prs_opened"`
+	Merged        int           `json:"merged"`
+	Reverted      int           `json:"reverted"`
+	MergeFailures int           `json:"merge_failures"`
+	WithTests     int           `json:"with_tests"` // assignments that added or changed tests
+	CostUSD       float64       `json:"cost_usd"`
+	Tokens        int           `json:"tokens"`
+	WallTime      time.Duration `json:"wall_time_ns"` // total, over assignments with a known wall time
+	timed         int
+}
+
+// SuccessRate is the fraction of assignments merged and not reverted.
+func (s *Stats) SuccessRate() float64 {
+	if s.Assignments == 0 {
+		return 0
+	}
+	return float64(s.Merged-s.Reverted) / float64(s.Assignments)
+}
+
+// AvgWallTime is the mean time from assignment to gt done.
+func (s *Stats) AvgWallTime() time.Duration {
+	if s.timed == 0 {
+		return 0
+	}
+	return s.WallTime / time.Duration(s.timed)
+}
+
+// CostPerMerge is the total cost divided by the merged, unreverted assignments.
+func (s *Stats) CostPerMerge() float64 {
+	if n := s.Merged - s.Reverted; n > 0 {
+		return s.CostUSD / float64(n)
+	}
+	return 0
+}
+
+// MarshalJSON includes the derived rates alongside the counts.
+func (s *Stats) MarshalJSON() ([]byte, error) {
+	type counts Stats
+	return json.Marshal(struct {
+		*counts
+		SuccessRate  float64 `json:"success_rate"`
+		AvgWallSecs  float64 `json:"avg_wall_time_secs"`
+		CostPerMerge float64 `json:"cost_per_merge_usd"`
+	}{
+		counts:       (*counts)(s),
+		SuccessRate:  s.SuccessRate(),
+		AvgWallSecs:  s.AvgWallTime().Seconds(),
+		CostPerMerge: s.CostPerMerge(),
+	})
+}
+
+// add folds one outcome into the stats.
+func (s *Stats) add(o *Outcome) {
+	s.Assignments++
+	if o.Exit == "COMPLETED" {
+		s.Done++
+	}
+	if o.PRURL != "" {
+		s.PRsOpened++
+	}
+	if o.Merged {
+		s.Merged++
+	}
+	if o.Merged && o.Reverted {
+		s.Reverted++
+	}
+	s.MergeFailures += o.MergeFailures
+	if o.TestsAdded > 0 {
+		s.WithTests++
+	}
+	s.CostUSD += o.CostUSD
+	s.Tokens += o.Tokens
+	if d := o.WallTime(); d > 0 {
+		s.WallTime += d
+		s.timed++
+	}
+}
+
+// Key returns the grouping key of an outcome for a grouping (ByRole, ByWorker,
+// ByRig, or ByModel). Outcomes missing the field group under "unknown".
+func Key(o *Outcome, by string) string {
+	var k string
+	switch by {
+	case ByRole:
+		k = o.Role
+	case ByWorker:
+		k = o.Agent
+	case ByRig:
+		k = o.Rig
+	case ByModel:
+		k = o.Model
+	}
+	if k == "" {
+		return "unknown"
+	}
+	return k
+}
+
+// Aggregate groups outcomes and returns stats per group, sorted by key.
+// An empty grouping returns a single "total" group.
+func Aggregate(outcomes []*Outcome, by string) []*Stats {
+	groups := make(map[string]*Stats)
+	for _, o := range outcomes {
+		k := "total"
+		if by != "" {
+			k = Key(o, by)
+		}
+		s := groups[k]
+		if s == nil {
+			s = &Stats{Key: k}
+			groups[k] = s
+		}
+		s.add(o)
+	}
+	stats := make([]*Stats, 0, len(groups))
+	for _, s := range groups {
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Key < stats[j].Key
+	})
+	return stats
+}
+
+// Since returns the outcomes assigned (or, if never assigned, finished) at
+// or after t.
+func Since(outcomes []*Outcome, t time.Time) []*Outcome {
+	var out []*Outcome
+	for _, o := range outcomes {
+		at := o.AssignedAt
+		if at.IsZero() {
+			at = o.DoneAt
+		}
+		if !at.Before(t) {
+			out = append(out, o)
+		}
+	}
+	return out
+}
@@ -0,0 +1,193 @@
+package polecat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/account"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Hibernation errors
+var (
+	ErrNotHibernated     = errors.New("polecat is not hibernated")
+	ErrNoRuntimeSession  = errors.New("no runtime session ID recorded")
+	ErrResumeUnsupported = errors.New("agent does not support session resume")
+)
+
+// Hibernation records a polecat whose session was stopped while idle so it
+// can later resume the same conversation. The worktree is left untouched.
+type Hibernation struct {
+	// Polecat is the polecat name.
+	Polecat string `json:"polecat"`
+
+	// SessionID is the runtime (agent) session ID to resume.
+	SessionID string `json:"session_id"`
+
+	// Agent is the agent preset the session was running (e.g., "claude").
+	Agent string `json:"agent"`
+
+	// Account is the account the session was running on, if any. The
+	// conversation is stored under its config dir, so Wake resumes there.
+	Account string `json:"account,omitempty"`
+
+	// HibernatedAt is when the session was stopped.
+	HibernatedAt time.Time `json:"hibernated_at"`
+}
+
+// hibernationPath returns the hibernation record path for a polecat.
+// Stored in polecats/<name>/.runtime/, outside the git worktree.
+func hibernationPath(rigPath, polecat string) string {
+	return filepath.Join(rigPath, "polecats", polecat, ".runtime", "hibernation.json")
+}
+
+func (m *SessionManager) hibernationPath(polecat string) string {
+	return hibernationPath(m.rig.Path, polecat)
+}
+
+// LoadHibernation returns the hibernation record for a polecat.
+// Returns ErrNotHibernated if the polecat is not hibernated.
+func (m *SessionManager) LoadHibernation(polecat string) (*Hibernation, error) {
+	data, err := os.ReadFile(m.hibernationPath(polecat))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotHibernated, polecat)
+		}
+		return nil, fmt.Errorf("reading hibernation record: %w", err)
+	}
+	var h Hibernation
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("parsing hibernation record: %w", err)
+	}
+	return &h, nil
+}
+
+// IsHibernated reports whether a polecat has a hibernation record.
+func (m *SessionManager) IsHibernated(polecat string) bool {
+	_, err := os.Stat(m.hibernationPath(polecat))
+	return err == nil
+}
+
+// Hibernate stops an idle polecat's session so it releases its process and
+// concurrency slot, recording the runtime session ID so Wake can resume the
+// conversation. The agent must support session resume.
+func (m *SessionManager) Hibernate(polecat string) (*Hibernation, error) {
+	if !m.hasPolecat(polecat) {
+		return nil, fmt.Errorf("%w: %s", ErrPolecatNotFound, polecat)
+	}
+	running, err := m.IsRunning(polecat)
+	if err != nil {
+		return nil, fmt.Errorf("checking session: %w", err)
+	}
+	if !running {
+		return nil, ErrSessionNotFound
+	}
+
+	sessionID := readRuntimeSessionID(m.clonePath(polecat))
+	if sessionID == "" {
+		return nil, fmt.Errorf("%w: %s", ErrNoRuntimeSession, polecat)
+	}
+	agent, _ := config.ResolveRoleAgentName(constants.RolePolecat, filepath.Dir(m.rig.Path), m.rig.Path)
+	if !config.SupportsSessionResume(agent) {
+		return nil, fmt.Errorf("%w: %s", ErrResumeUnsupported, agent)
+	}
+
+	h := &Hibernation{
+		Polecat:      polecat,
+		SessionID:    sessionID,
+		Agent:        agent,
+		Account:      account.SessionAccount(filepath.Dir(m.rig.Path), m.SessionName(polecat)),
+		HibernatedAt: time.Now().UTC(),
+	}
+	path := m.hibernationPath(polecat)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating runtime directory: %w", err)
+	}
+	if err := util.AtomicWriteJSON(path, h); err != nil {
+		return nil, fmt.Errorf("writing hibernation record: %w", err)
+	}
+
+	if err := m.Stop(polecat, false); err != nil {
+		_ = os.Remove(path)
+		return nil, err
+	}
+	return h, nil
+}
+
+// Wake resumes a hibernated polecat's conversation in a new session. The
+// session gets the usual startup and propulsion nudges, so the polecat picks
+// up whatever is on its hook. Returns ErrNotHibernated if there is no record.
+func (m *SessionManager) Wake(polecat string) (*Hibernation, error) {
+	h, err := m.LoadHibernation(polecat)
+	if err != nil {
+		return nil, err
+	}
+	path := m.hibernationPath(polecat)
+
+	// Someone already started a fresh session; the record is stale.
+	if running, _ := m.IsRunning(polecat); running {
+		_ = os.Remove(path)
+		return h, nil
+	}
+
+	resume := config.BuildResumeCommand(h.Agent, h.SessionID)
+	if resume == "" {
+		return nil, fmt.Errorf("%w: %s", ErrResumeUnsupported, h.Agent)
+	}
+	townRoot := filepath.Dir(m.rig.Path)
+	env := config.AgentEnv(config.AgentEnvConfig{
+		Role:      constants.RolePolecat,
+		Rig:       m.rig.Name,
+		AgentName: polecat,
+		TownRoot:  townRoot,
+	})
+	env["GT_ROOT"] = townRoot
+
+	opts := SessionStartOptions{Command: config.PrependEnv(resume, env), Resume: true}
+	acct, err := m.ResumeAccount(polecat, h.Account, &opts)
+	if err != nil {
+		return nil, fmt.Errorf("resolving account: %w", err)
+	}
+	if err := m.Start(polecat, opts); err != nil {
+		return nil, err
+	}
+	_ = acct.Record(townRoot, m.SessionName(polecat)) // usage tracking is best-effort
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("removing hibernation record: %w", err)
+	}
+	return h, nil
+}
+
+// ResumeAccount resolves the account for restarting a polecat's session and
+// sets opts' account fields from it: handle if given, otherwise the account
+// the session last ran on (see account.ResolveResume). The caller records
+// the session against it once started. Returns nil if no accounts are
+// configured.
+func (m *SessionManager) ResumeAccount(polecat, handle string, opts *SessionStartOptions) (*account.Resolved, error) {
+	acct, err := account.ResolveResume(filepath.Dir(m.rig.Path),
+		account.Selection{Role: constants.RolePolecat, Rig: m.rig.Name}, m.SessionName(polecat), handle)
+	if err != nil {
+		return nil, err
+	}
+	opts.RuntimeConfigDir = acct.ConfigDirOrEmpty()
+	opts.AccountEnv = acct.Env()
+	return acct, nil
+}
+
+// readRuntimeSessionID reads the agent session ID persisted by 'gt prime'
+// in <workDir>/.runtime/session_id. The ID is on the first line.
+func readRuntimeSessionID(workDir string) string {
+	data, err := os.ReadFile(filepath.Join(workDir, ".runtime", "session_id"))
+	if err != nil {
+		return ""
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	return strings.TrimSpace(line)
+}
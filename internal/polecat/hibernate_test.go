@@ -0,0 +1,79 @@
+package polecat
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+func TestHibernationRecord(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "polecats", "Toast", "gastown"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	m := NewSessionManager(tmux.NewTmux(), &rig.Rig{Name: "gastown", Path: root})
+
+	if m.IsHibernated("Toast") {
+		t.Error("expected IsHibernated(Toast) = false before hibernating")
+	}
+	if _, err := m.LoadHibernation("Toast"); !errors.Is(err, ErrNotHibernated) {
+		t.Errorf("LoadHibernation error = %v, want ErrNotHibernated", err)
+	}
+	if _, err := m.Wake("Toast"); !errors.Is(err, ErrNotHibernated) {
+		t.Errorf("Wake error = %v, want ErrNotHibernated", err)
+	}
+
+	want := Hibernation{Polecat: "Toast", SessionID: "abc-123", Agent: "claude", Account: "work"}
+	path := m.hibernationPath("Toast")
+	if path != filepath.Join(root, "polecats", "Toast", ".runtime", "hibernation.json") {
+		t.Errorf("hibernationPath = %q, want it outside the worktree", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := util.AtomicWriteJSON(path, want); err != nil {
+		t.Fatal(err)
+	}
+
+	if !m.IsHibernated("Toast") {
+		t.Error("expected IsHibernated(Toast) = true")
+	}
+	got, err := m.LoadHibernation("Toast")
+	if err != nil {
+		t.Fatalf("LoadHibernation: %v", err)
+	}
+	if got.SessionID != want.SessionID || got.Agent != want.Agent || got.Account != want.Account {
+		t.Errorf("LoadHibernation = %+v, want %+v", got, want)
+	}
+}
+
+func TestHibernateNotFound(t *testing.T) {
+	m := NewSessionManager(tmux.NewTmux(), &rig.Rig{Name: "gastown", Path: t.TempDir()})
+
+	if _, err := m.Hibernate("Nobody"); !errors.Is(err, ErrPolecatNotFound) {
+		t.Errorf("Hibernate error = %v, want ErrPolecatNotFound", err)
+	}
+}
+
+func TestReadRuntimeSessionID(t *testing.T) {
+	dir := t.TempDir()
+	if id := readRuntimeSessionID(dir); id != "" {
+		t.Errorf("readRuntimeSessionID = %q, want empty", id)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, ".runtime"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "abc-123\n2026-01-02T03:04:05Z\n"
+	if err := os.WriteFile(filepath.Join(dir, ".runtime", "session_id"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if id := readRuntimeSessionID(dir); id != "abc-123" {
+		t.Errorf("readRuntimeSessionID = %q, want abc-123", id)
+	}
+}
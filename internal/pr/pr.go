@@ -0,0 +1,83 @@
+// Package pr generates forge pull request titles and descriptions for
+// polecat branches.
+//
+// Gas Town merges work through the Refinery's merge queue. Rigs whose
+// upstream requires review on the forge can additionally have `gt done`
+// open a pull request (see package forge) for each submitted branch. The
+// PR URL is recorded on the MR bead so the Refinery and humans can find it.
+package pr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxCommitLines caps the number of commit subjects listed in a PR body.
+const maxCommitLines = 50
+
+// BodyInput holds the work context used to generate a PR description.
+type BodyInput struct {
+	IssueID          string
+	IssueTitle       string
+	IssueDescription string
+	MRID             string
+	Rig              string
+	Worker           string
+	Commits          []string // commit subjects, oldest first
+
+	// Session summarizes the polecat's session transcript (tool calls and
+	// files edited), as recorded in the town's tool-call events.
+	Session string
+}
+
+// Title returns the PR title for a piece of work.
+func Title(in BodyInput) string {
+	if in.IssueTitle != "" {
+		return fmt.Sprintf("%s (%s)", in.IssueTitle, in.IssueID)
+	}
+	return fmt.Sprintf("Merge: %s", in.IssueID)
+}
+
+// BuildBody generates a PR description from the source bead, the polecat's
+// commit history, and its session summary.
+func BuildBody(in BodyInput) string {
+	var b strings.Builder
+
+	b.WriteString("## Summary\n\n")
+	if in.IssueTitle != "" {
+		fmt.Fprintf(&b, "%s\n", in.IssueTitle)
+	}
+	if desc := strings.TrimSpace(in.IssueDescription); desc != "" {
+		fmt.Fprintf(&b, "\n%s\n", desc)
+	}
+
+	if len(in.Commits) > 0 {
+		b.WriteString("\n## Commits\n\n")
+		commits := in.Commits
+		if len(commits) > maxCommitLines {
+			commits = commits[len(commits)-maxCommitLines:]
+			fmt.Fprintf(&b, "- … %d earlier commits\n", len(in.Commits)-maxCommitLines)
+		}
+		for _, c := range commits {
+			fmt.Fprintf(&b, "- %s\n", c)
+		}
+	}
+
+	if session := strings.TrimSpace(in.Session); session != "" {
+		fmt.Fprintf(&b, "\n## Session\n\n%s\n", session)
+	}
+
+	b.WriteString("\n## Gas Town\n\n")
+	fmt.Fprintf(&b, "- Issue: %s\n", in.IssueID)
+	if in.MRID != "" {
+		fmt.Fprintf(&b, "- MR bead: %s\n", in.MRID)
+	}
+	if in.Rig != "" {
+		fmt.Fprintf(&b, "- Rig: %s\n", in.Rig)
+	}
+	if in.Worker != "" {
+		fmt.Fprintf(&b, "- Worker: %s\n", in.Worker)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package pr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestBuildBody(t *testing.T) {
+	in := BodyInput{
+		IssueID:          "gt-abc",
+		IssueTitle:       "Fix the widget",
+		IssueDescription: "The widget is broken.",
+		MRID:             "gt-mr1",
+		Rig:              "gastown",
+		Worker:           "Nux",
+		Commits:          []string{"Fix widget", "Add widget test"},
+		Session:          "Session: 12 tool calls in 4m (Edit 5, Bash 7).",
+	}
+	body := BuildBody(in)
+	for _, want := range []string{"Fix the widget", "The widget is broken.", "- Fix widget", "- Add widget test",
+		"## Session\n\nSession: 12 tool calls", "Issue: gt-abc", "MR bead: gt-mr1", "Rig: gastown", "Worker: Nux"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+
+	if strings.Contains(BuildBody(BodyInput{IssueID: "gt-abc"}), "## Session") {
+		t.Error("body without a session summary should omit the Session section")
+	}
+
+	if got := Title(in); got != "Fix the widget (gt-abc)" {
+		t.Errorf("Title = %q", got)
+	}
+	if got := Title(BodyInput{IssueID: "gt-abc"}); got != "Merge: gt-abc" {
+		t.Errorf("Title = %q", got)
+	}
+}
+
+func TestBuildBodyTruncatesCommits(t *testing.T) {
+	var commits []string
+	for i := 0; i < maxCommitLines+5; i++ {
+		commits = append(commits, fmt.Sprintf("commit %d", i))
+	}
+	body := BuildBody(BodyInput{IssueID: "gt-abc", Commits: commits})
+	if strings.Contains(body, "- commit 0\n") {
+		t.Error("oldest commit should be truncated")
+	}
+	if !strings.Contains(body, "5 earlier commits") {
+		t.Errorf("body missing truncation note:\n%s", body)
+	}
+}
@@ -92,6 +92,12 @@ func formatMergedBody(p MergedPayload) string {
 // NewMergeFailedMessage creates a MERGE_FAILED protocol message.
 // Sent by Refinery to Witness when merge fails (tests, build, etc.).
 func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureType, errorMsg string) *mail.Message {
+	return NewMergeFailedMessageWithReport(rig, polecat, branch, issue, targetBranch, failureType, errorMsg, "")
+}
+
+// NewMergeFailedMessageWithReport creates a MERGE_FAILED message carrying a
+// structured failure report. The report follows a "Report:" line in the body.
+func NewMergeFailedMessageWithReport(rig, polecat, branch, issue, targetBranch, failureType, errorMsg, report string) *mail.Message {
 	payload := MergeFailedPayload{
 		Branch:       branch,
 		Issue:        issue,
@@ -101,6 +107,7 @@ func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureTyp
 		FailureType:  failureType,
 		Error:        errorMsg,
 		TargetBranch: targetBranch,
+		Report:       report,
 	}
 
 	body := formatMergeFailedBody(payload)
@@ -128,6 +135,10 @@ func formatMergeFailedBody(p MergeFailedPayload) string {
 	sb.WriteString(fmt.Sprintf("Failed-At: %s\n", p.FailedAt.Format(time.RFC3339)))
 	sb.WriteString(fmt.Sprintf("Failure-Type: %s\n", p.FailureType))
 	sb.WriteString(fmt.Sprintf("Error: %s\n", p.Error))
+	if p.Report != "" {
+		sb.WriteString("Report:\n")
+		sb.WriteString(p.Report)
+	}
 	return sb.String()
 }
 
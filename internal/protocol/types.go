@@ -135,6 +135,10 @@ type MergeFailedPayload struct {
 
 	// TargetBranch is the branch we tried to merge into.
 	TargetBranch string `json:"target_branch"`
+
+	// Report is an optional multi-line failure report (e.g., failed pre-merge
+	// checks with output) relayed to the polecat as instructions.
+	Report string `json:"report,omitempty"`
 }
 
 // ReworkRequestPayload contains the data for a REWORK_REQUEST message.
@@ -0,0 +1,323 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/forge"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/logging"
+)
+
+// Pre-merge check types.
+const (
+	// CheckTypeCommand runs a shell command in an isolated worktree
+	// containing the merge result.
+	CheckTypeCommand = "command"
+
+	// CheckTypeForge polls the rig's forge (GitHub, GitLab, or Gitea) for
+	// the branch's status checks until the required checks finish.
+	CheckTypeForge = "forge"
+
+	// CheckTypeGitHub is a forge check pinned to GitHub, kept for rigs
+	// configured before forge checks existed.
+	CheckTypeGitHub = "github"
+)
+
+// Defaults for pre-merge checks.
+const (
+	defaultCommandCheckTimeout = 30 * time.Minute
+	defaultForgeCheckTimeout   = 60 * time.Minute
+	checkOutputTailBytes       = 4000
+)
+
+// forgePollInterval is how often forge checks are polled (var for testing).
+var forgePollInterval = 30 * time.Second
+
+// CheckConfig configures one pre-merge check.
+type CheckConfig struct {
+	// Name identifies the check in reports (e.g., "lint").
+	Name string `json:"name"`
+
+	// Type is "command" (default), "forge", or "github".
+	Type string `json:"type"`
+
+	// Command is the shell command for command checks.
+	Command string `json:"command,omitempty"`
+
+	// Required lists the forge check names that must pass.
+	// If empty, every check reported for the branch must pass.
+	Required []string `json:"required,omitempty"`
+
+	// Timeout bounds the check (command run time, or forge polling time).
+	Timeout time.Duration `json:"timeout"`
+
+	// Optional checks are reported but do not block the merge.
+	Optional bool `json:"optional,omitempty"`
+}
+
+// CheckResult is the outcome of one pre-merge check.
+type CheckResult struct {
+	Name     string
+	Type     string
+	Passed   bool
+	Optional bool
+	Summary  string // one-line reason
+	Output   string // tail of command output, or failing forge checks
+	Duration time.Duration
+}
+
+// runChecks runs the configured pre-merge checks for branch.
+// Command checks run in a temporary detached worktree at baseRef; if merge is
+// set, branch is merged (without committing) into that worktree first so the
+// checks see the merge result. The refinery's own clone is never touched.
+func (e *Engineer) runChecks(ctx context.Context, branch, baseRef string, merge bool) []CheckResult {
+	var results []CheckResult
+
+	var worktree string
+	var cleanup func()
+	for _, check := range e.config.Checks {
+		checkType := check.Type
+		if checkType == "" {
+			checkType = CheckTypeCommand
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Running check: %s (%s)\n", check.Name, checkType)
+
+		start := time.Now()
+		var result CheckResult
+		switch checkType {
+		case CheckTypeCommand:
+			if worktree == "" {
+				var err error
+				worktree, cleanup, err = e.prepareCheckWorktree(branch, baseRef, merge)
+				if err != nil {
+					result = CheckResult{Summary: fmt.Sprintf("preparing check worktree: %v", err)}
+					break
+				}
+			}
+			result = runCommandCheck(ctx, worktree, check)
+		case CheckTypeForge, CheckTypeGitHub:
+			result = e.runForgeCheck(ctx, branch, check, checkType)
+		default:
+			result = CheckResult{Summary: fmt.Sprintf("unknown check type %q", checkType)}
+		}
+		result.Name = check.Name
+		result.Type = checkType
+		result.Optional = check.Optional
+		result.Duration = time.Since(start)
+
+		status := "passed"
+		if !result.Passed {
+			status = "FAILED"
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Check %s %s (%v)\n", check.Name, status, result.Duration.Round(time.Second))
+		results = append(results, result)
+	}
+
+	if cleanup != nil {
+		cleanup()
+	}
+	return results
+}
+
+// prepareCheckWorktree creates a detached worktree at baseRef, optionally with
+// branch merged in. Returns the worktree path and a cleanup function.
+func (e *Engineer) prepareCheckWorktree(branch, baseRef string, merge bool) (string, func(), error) {
+	tmpDir, err := os.MkdirTemp("", "gt-check-*")
+	if err != nil {
+		return "", nil, err
+	}
+	path := filepath.Join(tmpDir, "wt")
+
+	cleanup := func() {
+		logging.WarnIf(e.log(), "removing worktree", e.git.RemoveWorktree(path, true), "path", path)
+		logging.WarnIf(e.log(), "removing directory", os.RemoveAll(tmpDir), "path", tmpDir)
+	}
+
+	if err := e.git.AddWorktree(path, git.AddWorktreeOptions{Detach: true, StartPoint: baseRef}); err != nil {
+		logging.WarnIf(e.log(), "removing directory", os.RemoveAll(tmpDir), "path", tmpDir)
+		return "", nil, err
+	}
+	if merge {
+		if err := git.NewGit(path).MergeNoCommit(branch); err != nil {
+			cleanup()
+			return "", nil, fmt.Errorf("merging %s into %s: %w", branch, baseRef, err)
+		}
+	}
+	return path, cleanup, nil
+}
+
+// runCommandCheck runs a command check in dir.
+func runCommandCheck(ctx context.Context, dir string, check CheckConfig) CheckResult {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = defaultCommandCheckTimeout
+	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Note: Command comes from rig's config.json (trusted infrastructure config).
+	cmd := exec.CommandContext(runCtx, "sh", "-c", check.Command) //nolint:gosec // G204: Command is from trusted rig config
+	cmd.Dir = dir
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err := cmd.Run()
+	result := CheckResult{Output: tailString(out.String(), checkOutputTailBytes)}
+	switch {
+	case err == nil:
+		result.Passed = true
+		result.Summary = "ok"
+	case runCtx.Err() == context.DeadlineExceeded:
+		result.Summary = fmt.Sprintf("timed out after %v: %s", timeout, check.Command)
+	default:
+		result.Summary = fmt.Sprintf("%s: %v", check.Command, err)
+	}
+	return result
+}
+
+// runForgeCheck polls the rig's forge for branch's status checks until the
+// required checks leave the pending state or the timeout expires.
+func (e *Engineer) runForgeCheck(ctx context.Context, branch string, check CheckConfig, checkType string) CheckResult {
+	f, err := e.resolveForge(checkType)
+	if err != nil {
+		return CheckResult{Summary: err.Error()}
+	}
+
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = defaultForgeCheckTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		checks, err := f.Checks(ctx, e.workDir, branch)
+		if err == nil {
+			if result, done := evaluateForgeChecks(f.Name(), checks, check.Required); done {
+				return result
+			}
+		}
+
+		if time.Now().Add(forgePollInterval).After(deadline) {
+			summary := fmt.Sprintf("checks still pending after %v", timeout)
+			if err != nil {
+				summary = fmt.Sprintf("fetching checks: %v", err)
+			}
+			return CheckResult{Summary: summary}
+		}
+
+		select {
+		case <-ctx.Done():
+			return CheckResult{Summary: "canceled"}
+		case <-time.After(forgePollInterval):
+		}
+	}
+}
+
+// resolveForge returns the forge to poll. "github" checks are pinned to
+// GitHub; "forge" checks use the rig's configured or detected forge.
+func (e *Engineer) resolveForge(checkType string) (forge.Forge, error) {
+	if checkType == CheckTypeGitHub {
+		return forge.New(forge.GitHub, forge.Options{RemoteURL: e.rig.GitURL})
+	}
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(e.rig.Path))
+	if err != nil {
+		settings = nil
+	}
+	return forge.Resolve(settings, e.rig.GitURL)
+}
+
+// evaluateForgeChecks decides whether the required checks have finished.
+// Returns done=false while any required check is pending or not yet reported.
+func evaluateForgeChecks(forgeName string, checks []forge.Check, required []string) (CheckResult, bool) {
+	byName := make(map[string]forge.Check, len(checks))
+	for _, c := range checks {
+		byName[c.Name] = c
+	}
+
+	relevant := checks
+	if len(required) > 0 {
+		relevant = nil
+		for _, name := range required {
+			c, ok := byName[name]
+			if !ok {
+				return CheckResult{}, false // not reported yet
+			}
+			relevant = append(relevant, c)
+		}
+	}
+	if len(relevant) == 0 {
+		return CheckResult{}, false
+	}
+
+	var failed []string
+	for _, c := range relevant {
+		switch c.State {
+		case forge.CheckPending:
+			return CheckResult{}, false
+		case forge.CheckFail:
+			line := c.Name
+			if c.Link != "" {
+				line += " " + c.Link
+			}
+			failed = append(failed, line)
+		}
+	}
+
+	if len(failed) > 0 {
+		return CheckResult{
+			Summary: fmt.Sprintf("%d %s check(s) failed", len(failed), forgeName),
+			Output:  strings.Join(failed, "\n"),
+		}, true
+	}
+	return CheckResult{Passed: true, Summary: fmt.Sprintf("%d %s check(s) passed", len(relevant), forgeName)}, true
+}
+
+// blockingFailures returns the failed checks that are not optional.
+func blockingFailures(results []CheckResult) []CheckResult {
+	var failed []CheckResult
+	for _, r := range results {
+		if !r.Passed && !r.Optional {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// FormatCheckReport formats failed checks as instructions for the polecat.
+// The report is sent in MERGE_FAILED and relayed to the polecat as its prompt.
+func FormatCheckReport(branch string, results []CheckResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Pre-merge checks failed for %s.\n", branch)
+	b.WriteString("Fix the failures below, commit, and resubmit with 'gt done'.\n")
+	for _, r := range results {
+		if r.Passed {
+			continue
+		}
+		label := "FAILED"
+		if r.Optional {
+			label = "failed (optional)"
+		}
+		fmt.Fprintf(&b, "\n## %s [%s] %s\n%s\n", r.Name, r.Type, label, r.Summary)
+		if out := strings.TrimSpace(r.Output); out != "" {
+			fmt.Fprintf(&b, "```\n%s\n```\n", out)
+		}
+	}
+	return b.String()
+}
+
+// tailString returns at most the last n bytes of s.
+func tailString(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return "…" + s[len(s)-n:]
+}
@@ -0,0 +1,208 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/forge"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestEvaluateForgeChecks(t *testing.T) {
+	tests := []struct {
+		name       string
+		checks     []forge.Check
+		required   []string
+		wantDone   bool
+		wantPassed bool
+	}{
+		{
+			name:       "all pass",
+			checks:     []forge.Check{{Name: "build", State: forge.CheckPass}, {Name: "lint", State: forge.CheckSkipped}},
+			wantDone:   true,
+			wantPassed: true,
+		},
+		{
+			name:     "pending",
+			checks:   []forge.Check{{Name: "build", State: forge.CheckPass}, {Name: "lint", State: forge.CheckPending}},
+			wantDone: false,
+		},
+		{
+			name:     "failure",
+			checks:   []forge.Check{{Name: "build", State: forge.CheckFail, Link: "https://example.com/run/1"}},
+			wantDone: true,
+		},
+		{
+			name:       "required subset ignores other failures",
+			checks:     []forge.Check{{Name: "build", State: forge.CheckPass}, {Name: "flaky", State: forge.CheckFail}},
+			required:   []string{"build"},
+			wantDone:   true,
+			wantPassed: true,
+		},
+		{
+			name:     "required check not reported yet",
+			checks:   []forge.Check{{Name: "lint", State: forge.CheckPass}},
+			required: []string{"build"},
+			wantDone: false,
+		},
+		{
+			name:     "no checks reported",
+			wantDone: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, done := evaluateForgeChecks(forge.GitHub, tt.checks, tt.required)
+			if done != tt.wantDone {
+				t.Fatalf("done = %v, want %v", done, tt.wantDone)
+			}
+			if done && result.Passed != tt.wantPassed {
+				t.Errorf("Passed = %v, want %v (%s)", result.Passed, tt.wantPassed, result.Summary)
+			}
+		})
+	}
+}
+
+func TestFormatCheckReport(t *testing.T) {
+	report := FormatCheckReport("polecat/Nux/gt-abc", []CheckResult{
+		{Name: "unit", Type: CheckTypeCommand, Passed: true},
+		{Name: "lint", Type: CheckTypeCommand, Summary: "make lint: exit status 1", Output: "main.go:3: unused import"},
+		{Name: "docs", Type: CheckTypeCommand, Optional: true, Summary: "spelling"},
+	})
+
+	for _, want := range []string{"polecat/Nux/gt-abc", "## lint [command] FAILED", "main.go:3: unused import", "failed (optional)", "gt done"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "## unit") {
+		t.Error("report should not include passing checks")
+	}
+}
+
+func TestRunChecks_CommandInWorktree(t *testing.T) {
+	r, repo := setupConflictRig(t)
+
+	e := NewEngineer(r)
+	e.SetOutput(&bytes.Buffer{})
+	e.config.Checks = []CheckConfig{
+		{Name: "on-main", Command: "grep -q main conflict.txt"},
+		{Name: "missing", Command: "test -f nope.txt", Timeout: time.Minute},
+		{Name: "advisory", Command: "false", Optional: true},
+	}
+
+	results := e.runChecks(context.Background(), "feature", "main", false)
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	if !results[0].Passed {
+		t.Errorf("on-main check failed: %s", results[0].Summary)
+	}
+	if results[1].Passed {
+		t.Error("missing check should fail")
+	}
+
+	failed := blockingFailures(results)
+	if len(failed) != 1 || failed[0].Name != "missing" {
+		t.Errorf("blockingFailures = %+v, want only 'missing'", failed)
+	}
+
+	// The refinery clone must be untouched and the worktree cleaned up
+	data, _ := os.ReadFile(filepath.Join(repo, "conflict.txt"))
+	if string(data) != "main\n" {
+		t.Errorf("refinery clone modified: conflict.txt = %q", data)
+	}
+	worktrees, err := e.git.ListWorktrees()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(worktrees) != 1 {
+		t.Errorf("expected check worktree to be removed, got %d worktrees", len(worktrees))
+	}
+}
+
+func TestEngineer_LoadConfig_Checks(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := map[string]interface{}{
+		"type": "rig",
+		"name": "test-rig",
+		"merge_queue": map[string]interface{}{
+			"checks": []map[string]interface{}{
+				{"name": "lint", "command": "make lint", "timeout": "5m"},
+				{"name": "ci", "type": "github", "required": []string{"build"}, "optional": true},
+			},
+		},
+	}
+	data, _ := json.Marshal(config)
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	checks := e.Config().Checks
+	if len(checks) != 2 {
+		t.Fatalf("got %d checks, want 2", len(checks))
+	}
+	if checks[0].Timeout != 5*time.Minute || checks[0].Command != "make lint" {
+		t.Errorf("lint check = %+v", checks[0])
+	}
+	if checks[1].Type != CheckTypeGitHub || !checks[1].Optional || checks[1].Required[0] != "build" {
+		t.Errorf("ci check = %+v", checks[1])
+	}
+}
+
+func TestEngineer_LoadConfig_GastownChecks(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "test-rig")
+	if err := os.MkdirAll(rigPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(map[string]interface{}{
+		"type": "rig",
+		"name": "test-rig",
+		"merge_queue": map[string]interface{}{
+			"checks": []map[string]interface{}{{"name": "lint", "command": "make lint"}},
+		},
+	})
+	if err := os.WriteFile(filepath.Join(rigPath, "config.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Town file sets a default; the rig file overrides it
+	town := "[[merge_queue.checks]]\nname = \"town\"\ncommand = \"make check\"\n"
+	if err := os.WriteFile(filepath.Join(townRoot, "gastown.toml"), []byte(town), 0644); err != nil {
+		t.Fatal(err)
+	}
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: rigPath})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if checks := e.Config().Checks; len(checks) != 1 || checks[0].Name != "town" {
+		t.Errorf("checks = %+v, want town check to replace config.json checks", checks)
+	}
+
+	rigFile := "[[merge_queue.checks]]\nname = \"e2e\"\ncommand = \"make e2e\"\ntimeout = \"20m\"\n"
+	if err := os.WriteFile(filepath.Join(rigPath, "gastown.toml"), []byte(rigFile), 0644); err != nil {
+		t.Fatal(err)
+	}
+	e = NewEngineer(&rig.Rig{Name: "test-rig", Path: rigPath})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	checks := e.Config().Checks
+	if len(checks) != 1 || checks[0].Name != "e2e" || checks[0].Timeout != 20*time.Minute {
+		t.Errorf("checks = %+v, want rig check", checks)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -14,8 +15,12 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/logging"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/outcome"
 	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/rig"
 )
@@ -31,9 +36,16 @@ type MergeQueueConfig struct {
 	// IntegrationBranches enables per-epic integration branches.
 	IntegrationBranches bool `json:"integration_branches"`
 
-	// OnConflict is the strategy for handling conflicts: "assign_back" or "auto_rebase".
+	// OnConflict is the strategy for handling conflicts:
+	// "assign_back", "auto_rebase", or "agent_resolve".
 	OnConflict string `json:"on_conflict"`
 
+	// ConflictAgent is the agent preset used for "agent_resolve" (default: "claude").
+	ConflictAgent string `json:"conflict_agent"`
+
+	// ConflictTimeout bounds how long the conflict-resolution agent may run.
+	ConflictTimeout time.Duration `json:"conflict_timeout"`
+
 	// RunTests controls whether to run tests before merging.
 	RunTests bool `json:"run_tests"`
 
@@ -51,6 +63,23 @@ type MergeQueueConfig struct {
 
 	// MaxConcurrent is the maximum number of MRs to process concurrently.
 	MaxConcurrent int `json:"max_concurrent"`
+
+	// BatchSize is the maximum number of MRs merged and tested together as
+	// one merge train. 0 or 1 merges MRs one at a time.
+	BatchSize int `json:"batch_size"`
+
+	// Checks are pre-merge gates (commands or forge checks) that must pass.
+	Checks []CheckConfig `json:"checks"`
+
+	// Review enables the reviewer stage: a reviewer agent critiques each
+	// MR's diff and must approve it before the merge.
+	Review bool `json:"review"`
+
+	// ReviewAgent is the agent preset used for review (default: "claude").
+	ReviewAgent string `json:"review_agent"`
+
+	// ReviewTimeout bounds how long the reviewer agent may run.
+	ReviewTimeout time.Duration `json:"review_timeout"`
 }
 
 // DefaultMergeQueueConfig returns sensible defaults for merge queue configuration.
@@ -60,12 +89,16 @@ func DefaultMergeQueueConfig() *MergeQueueConfig {
 		TargetBranch:         "main",
 		IntegrationBranches:  true,
 		OnConflict:           "assign_back",
+		ConflictAgent:        "claude",
+		ConflictTimeout:      10 * time.Minute,
 		RunTests:             true,
 		TestCommand:          "",
 		DeleteMergedBranches: true,
 		RetryFlakyTests:      1,
 		PollInterval:         30 * time.Second,
 		MaxConcurrent:        1,
+		ReviewAgent:          "claude",
+		ReviewTimeout:        20 * time.Minute,
 	}
 }
 
@@ -103,6 +136,11 @@ type Engineer struct {
 	stopCh chan struct{}
 }
 
+// log returns the refinery component's logger for this rig.
+func (e *Engineer) log() *slog.Logger {
+	return logging.For(logging.ComponentRefinery).With("rig", e.rig.Name)
+}
+
 // NewEngineer creates a new Engineer for the given rig.
 func NewEngineer(r *rig.Rig) *Engineer {
 	cfg := DefaultMergeQueueConfig()
@@ -135,8 +173,17 @@ func (e *Engineer) SetOutput(w io.Writer) {
 	e.output = w
 }
 
-// LoadConfig loads merge queue configuration from the rig's config.json.
+// LoadConfig loads merge queue configuration from the rig's config.json,
+// then applies pre-merge checks from gastown.toml.
 func (e *Engineer) LoadConfig() error {
+	if err := e.loadRigConfig(); err != nil {
+		return err
+	}
+	return e.applyGastownChecks()
+}
+
+// loadRigConfig loads the merge_queue section of the rig's config.json.
+func (e *Engineer) loadRigConfig() error {
 	configPath := filepath.Join(e.rig.Path, "config.json")
 	data, err := os.ReadFile(configPath)
 	if err != nil {
@@ -167,12 +214,19 @@ func (e *Engineer) LoadConfig() error {
 		TargetBranch         *string `json:"target_branch"`
 		IntegrationBranches  *bool   `json:"integration_branches"`
 		OnConflict           *string `json:"on_conflict"`
+		ConflictAgent        *string `json:"conflict_agent"`
+		ConflictTimeout      *string `json:"conflict_timeout"`
 		RunTests             *bool   `json:"run_tests"`
 		TestCommand          *string `json:"test_command"`
 		DeleteMergedBranches *bool   `json:"delete_merged_branches"`
 		RetryFlakyTests      *int    `json:"retry_flaky_tests"`
 		PollInterval         *string `json:"poll_interval"`
 		MaxConcurrent        *int    `json:"max_concurrent"`
+		BatchSize            *int    `json:"batch_size"`
+		Review               *bool   `json:"review"`
+		ReviewAgent          *string `json:"review_agent"`
+		ReviewTimeout        *string `json:"review_timeout"`
+		Checks               []config.MergeCheckConfig `json:"checks"`
 	}
 
 	if err := json.Unmarshal(rawConfig.MergeQueue, &mqRaw); err != nil {
@@ -192,6 +246,16 @@ func (e *Engineer) LoadConfig() error {
 	if mqRaw.OnConflict != nil {
 		e.config.OnConflict = *mqRaw.OnConflict
 	}
+	if mqRaw.ConflictAgent != nil {
+		e.config.ConflictAgent = *mqRaw.ConflictAgent
+	}
+	if mqRaw.ConflictTimeout != nil {
+		dur, err := time.ParseDuration(*mqRaw.ConflictTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid conflict_timeout %q: %w", *mqRaw.ConflictTimeout, err)
+		}
+		e.config.ConflictTimeout = dur
+	}
 	if mqRaw.RunTests != nil {
 		e.config.RunTests = *mqRaw.RunTests
 	}
@@ -207,6 +271,9 @@ func (e *Engineer) LoadConfig() error {
 	if mqRaw.MaxConcurrent != nil {
 		e.config.MaxConcurrent = *mqRaw.MaxConcurrent
 	}
+	if mqRaw.BatchSize != nil {
+		e.config.BatchSize = *mqRaw.BatchSize
+	}
 	if mqRaw.PollInterval != nil {
 		dur, err := time.ParseDuration(*mqRaw.PollInterval)
 		if err != nil {
@@ -214,10 +281,72 @@ func (e *Engineer) LoadConfig() error {
 		}
 		e.config.PollInterval = dur
 	}
+	if mqRaw.Review != nil {
+		e.config.Review = *mqRaw.Review
+	}
+	if mqRaw.ReviewAgent != nil {
+		e.config.ReviewAgent = *mqRaw.ReviewAgent
+	}
+	if mqRaw.ReviewTimeout != nil {
+		dur, err := time.ParseDuration(*mqRaw.ReviewTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid review_timeout %q: %w", *mqRaw.ReviewTimeout, err)
+		}
+		e.config.ReviewTimeout = dur
+	}
+	for _, c := range mqRaw.Checks {
+		check, err := newCheckConfig(c)
+		if err != nil {
+			return err
+		}
+		e.config.Checks = append(e.config.Checks, check)
+	}
 
 	return nil
 }
 
+// applyGastownChecks replaces the pre-merge checks with [[merge_queue.checks]]
+// from gastown.toml, where the rig's file overrides the town's. Without any,
+// the checks from config.json are kept.
+func (e *Engineer) applyGastownChecks() error {
+	gtConfig, err := config.LoadRigGastownConfig(filepath.Dir(e.rig.Path), e.rig.Path)
+	if err != nil {
+		return err
+	}
+	if gtConfig.MergeQueue == nil {
+		return nil
+	}
+	checks := make([]CheckConfig, 0, len(gtConfig.MergeQueue.Checks))
+	for _, c := range gtConfig.MergeQueue.Checks {
+		check, err := newCheckConfig(c)
+		if err != nil {
+			return err
+		}
+		checks = append(checks, check)
+	}
+	e.config.Checks = checks
+	return nil
+}
+
+// newCheckConfig converts a configured check to a CheckConfig.
+func newCheckConfig(c config.MergeCheckConfig) (CheckConfig, error) {
+	check := CheckConfig{
+		Name:     c.Name,
+		Type:     c.Type,
+		Command:  c.Command,
+		Required: c.Required,
+		Optional: c.Optional,
+	}
+	if c.Timeout != "" {
+		dur, err := time.ParseDuration(c.Timeout)
+		if err != nil {
+			return check, fmt.Errorf("invalid timeout %q for check %s: %w", c.Timeout, c.Name, err)
+		}
+		check.Timeout = dur
+	}
+	return check, nil
+}
+
 // Config returns the current merge queue configuration.
 func (e *Engineer) Config() *MergeQueueConfig {
 	return e.config
@@ -230,6 +359,15 @@ type ProcessResult struct {
 	Error       string
 	Conflict    bool
 	TestsFailed bool
+
+	// ChecksFailed is set when a required pre-merge check failed.
+	// CheckReport holds the structured failure report for the polecat.
+	ChecksFailed bool
+	CheckReport  string
+
+	// ReviewRejected is set when the reviewer requested changes.
+	// The review findings are reported in CheckReport.
+	ReviewRejected bool
 }
 
 // ProcessMR processes a single merge request from a beads issue.
@@ -249,12 +387,12 @@ func (e *Engineer) ProcessMR(ctx context.Context, mr *beads.Issue) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Target: %s\n", mrFields.Target)
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mrFields.Worker)
 
-	return e.doMerge(ctx, mrFields.Branch, mrFields.Target, mrFields.SourceIssue)
+	return e.doMerge(ctx, mr.ID, mrFields.Branch, mrFields.Target, mrFields.SourceIssue)
 }
 
 // doMerge performs the actual git merge operation.
 // This is the core merge logic shared by ProcessMR and ProcessMRFromQueue.
-func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue string) ProcessResult {
+func (e *Engineer) doMerge(ctx context.Context, mrID, branch, target, sourceIssue string) ProcessResult {
 	// Step 1: Verify source branch exists locally (shared .repo.git with polecats)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking local branch %s...\n", branch)
 	exists, err := e.git.BranchExists(branch)
@@ -271,6 +409,19 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
+	// A merge or rebase left over from an interrupted run would block the
+	// checkout; abort it so the queue keeps moving
+	if status, err := e.git.Status(); err == nil {
+		if status.Merging {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Aborting merge left in progress\n")
+			logging.WarnIf(e.log(), "aborting merge", e.git.AbortMerge())
+		}
+		if status.Rebasing {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Aborting rebase left in progress\n")
+			logging.WarnIf(e.log(), "aborting rebase", e.git.AbortRebase())
+		}
+	}
+
 	// Step 2: Checkout the target branch
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking out target branch %s...\n", target)
 	if err := e.git.Checkout(target); err != nil {
@@ -296,12 +447,48 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 			Error:    fmt.Sprintf("conflict check failed: %v", err),
 		}
 	}
+	mergeMsg := fmt.Sprintf("Merge %s into %s", branch, target)
+	if sourceIssue != "" {
+		mergeMsg = fmt.Sprintf("Merge %s into %s (%s)", branch, target, sourceIssue)
+	}
 	if len(conflicts) > 0 {
-		return ProcessResult{
-			Success:  false,
-			Conflict: true,
-			Error:    fmt.Sprintf("merge conflicts in: %v", conflicts),
+		if e.config.OnConflict != config.OnConflictAgentResolve {
+			return ProcessResult{
+				Success:  false,
+				Conflict: true,
+				Error:    fmt.Sprintf("merge conflicts in: %v", conflicts),
+			}
 		}
+
+		// Step 3b: Let a short-lived agent resolve the conflicts. The resolver
+		// runs the queue's tests itself and commits the merge only if they pass.
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Conflicts in %v, spawning resolver agent...\n", conflicts)
+		preMerge, err := e.git.Rev("HEAD")
+		if err != nil {
+			return ProcessResult{
+				Success: false,
+				Error:   fmt.Sprintf("failed to get target SHA: %v", err),
+			}
+		}
+		if err := e.resolveConflicts(ctx, branch, mergeMsg, conflicts); err != nil {
+			return ProcessResult{
+				Success:  false,
+				Conflict: true,
+				Error:    fmt.Sprintf("merge conflicts in: %v (agent resolution failed: %v)", conflicts, err),
+			}
+		}
+		_, _ = fmt.Fprintln(e.output, "[Engineer] Resolver agent resolved conflicts")
+
+		// The resolution is committed locally; gate it before pushing
+		if result := e.checkGate(ctx, branch, "HEAD", false); result != nil {
+			logging.WarnIf(e.log(), "resetting work tree", e.git.ResetHard(preMerge), "ref", preMerge)
+			return *result
+		}
+		if result := e.reviewGate(ctx, mrID, branch, target, sourceIssue); result != nil {
+			logging.WarnIf(e.log(), "resetting work tree", e.git.ResetHard(preMerge), "ref", preMerge)
+			return *result
+		}
+		return e.pushMerge(target)
 	}
 
 	// Step 4: Run tests if configured
@@ -318,18 +505,24 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
 	}
 
-	// Step 5: Perform the actual merge
-	mergeMsg := fmt.Sprintf("Merge %s into %s", branch, target)
-	if sourceIssue != "" {
-		mergeMsg = fmt.Sprintf("Merge %s into %s (%s)", branch, target, sourceIssue)
+	// Step 4b: Run pre-merge checks against the merge result
+	if result := e.checkGate(ctx, branch, target, true); result != nil {
+		return *result
+	}
+
+	// Step 4c: Have a reviewer critique the diff
+	if result := e.reviewGate(ctx, mrID, branch, target, sourceIssue); result != nil {
+		return *result
 	}
+
+	// Step 5: Perform the actual merge
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Merging with message: %s\n", mergeMsg)
 	if err := e.git.MergeNoFF(branch, mergeMsg); err != nil {
 		// ZFC: Use git's porcelain output to detect conflicts instead of parsing stderr.
 		// GetConflictingFiles() uses `git diff --diff-filter=U` which is proper.
 		conflicts, conflictErr := e.git.GetConflictingFiles()
 		if conflictErr == nil && len(conflicts) > 0 {
-			_ = e.git.AbortMerge()
+			logging.WarnIf(e.log(), "aborting merge", e.git.AbortMerge())
 			return ProcessResult{
 				Success:  false,
 				Conflict: true,
@@ -342,6 +535,34 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
+	return e.pushMerge(target)
+}
+
+// checkGate runs the configured pre-merge checks and returns a failed
+// ProcessResult if any required check failed, or nil if the gate passed.
+func (e *Engineer) checkGate(ctx context.Context, branch, baseRef string, merge bool) *ProcessResult {
+	if len(e.config.Checks) == 0 {
+		return nil
+	}
+	results := e.runChecks(ctx, branch, baseRef, merge)
+	failed := blockingFailures(results)
+	if len(failed) == 0 {
+		return nil
+	}
+	names := make([]string, len(failed))
+	for i, r := range failed {
+		names[i] = r.Name
+	}
+	return &ProcessResult{
+		Success:      false,
+		ChecksFailed: true,
+		Error:        fmt.Sprintf("pre-merge checks failed: %s", strings.Join(names, ", ")),
+		CheckReport:  FormatCheckReport(branch, results),
+	}
+}
+
+// pushMerge records the merge commit at HEAD and pushes target to origin.
+func (e *Engineer) pushMerge(target string) ProcessResult {
 	// Step 6: Get the merge commit SHA
 	mergeCommit, err := e.git.Rev("HEAD")
 	if err != nil {
@@ -353,7 +574,9 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 
 	// Step 7: Push to origin
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing to origin/%s...\n", target)
-	if err := e.git.Push("origin", target, false); err != nil {
+	// Merging is the sanctioned way onto protected branches, so it
+	// overrides the guard (and the override is audited).
+	if err := e.git.PushWithOptions("origin", target, git.PushOptions{AllowProtected: true}); err != nil {
 		return ProcessResult{
 			Success: false,
 			Error:   fmt.Sprintf("failed to push to origin: %v", err),
@@ -503,7 +726,7 @@ func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Source: %s\n", mr.SourceIssue)
 
 	// Use the shared merge logic
-	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
+	return e.doMerge(ctx, mr.ID, mr.Branch, mr.Target, mr.SourceIssue)
 }
 
 // HandleMRInfoSuccess handles a successful merge from MRInfo.
@@ -576,10 +799,42 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 		}
 	}
 
-	// 3. Log success
+	// 3. Record the outcome for gt stats, and the merge in the event log
+	e.recordMergeOutcome(mr, result)
+	payload := events.MergePayload(mr.ID, mr.Worker, mr.Branch, "")
+	payload["commit"] = result.MergeCommit
+	_ = events.LogFeed(events.TypeMerged, e.rig.Name+"/refinery", payload)
+
+	// 4. Log success
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
 }
 
+// recordMergeOutcome records a merge in the town's outcome log, along with
+// any earlier commits the merged work reverts. Best effort.
+func (e *Engineer) recordMergeOutcome(mr *MRInfo, result ProcessResult) {
+	if mr.SourceIssue == "" {
+		return
+	}
+	townRoot := filepath.Dir(e.rig.Path)
+	logging.WarnIf(e.log(), "recording merge outcome", outcome.Record(townRoot, outcome.Update{
+		Kind:   outcome.KindMerged,
+		Bead:   mr.SourceIssue,
+		Rig:    e.rig.Name,
+		Commit: result.MergeCommit,
+	}), "bead", mr.SourceIssue)
+	if result.MergeCommit == "" {
+		return
+	}
+	msgs, err := e.git.CommitMessages(result.MergeCommit+"^1", result.MergeCommit)
+	if err != nil {
+		return
+	}
+	for _, sha := range outcome.RevertedCommits(msgs) {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] %s reverts %s; recording revert\n", mr.SourceIssue, sha)
+		logging.WarnIf(e.log(), "recording revert outcome", outcome.Record(townRoot, outcome.Update{Kind: outcome.KindReverted, Commit: sha}), "commit", sha)
+	}
+}
+
 // HandleMRInfoFailure handles a failed merge from MRInfo.
 // For conflicts, creates a resolution task and blocks the MR until resolved.
 // This enables non-blocking delegation: the queue continues to the next MR.
@@ -591,8 +846,21 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		failureType = "conflict"
 	} else if result.TestsFailed {
 		failureType = "tests"
+	} else if result.ChecksFailed {
+		failureType = "checks"
+	} else if result.ReviewRejected {
+		failureType = "review"
+	}
+	if mr.SourceIssue != "" {
+		logging.WarnIf(e.log(), "recording merge failure outcome", outcome.Record(filepath.Dir(e.rig.Path), outcome.Update{
+			Kind:   outcome.KindMergeFailed,
+			Bead:   mr.SourceIssue,
+			Rig:    e.rig.Name,
+			Reason: failureType,
+		}), "bead", mr.SourceIssue)
 	}
-	msg := protocol.NewMergeFailedMessage(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, failureType, result.Error)
+	_ = events.LogFeed(events.TypeMergeFailed, e.rig.Name+"/refinery", events.MergePayload(mr.ID, mr.Worker, mr.Branch, failureType))
+	msg := protocol.NewMergeFailedMessageWithReport(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, failureType, result.Error, result.CheckReport)
 	if err := e.router.Send(msg); err != nil {
 		fmt.Fprintf(e.output, "[Engineer] Warning: failed to send MERGE_FAILED to witness: %v\n", err)
 	} else {
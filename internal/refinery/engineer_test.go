@@ -74,8 +74,11 @@ func TestEngineer_LoadConfig_WithMergeQueue(t *testing.T) {
 			"target_branch":  "develop",
 			"poll_interval":  "10s",
 			"max_concurrent": 2,
+			"batch_size":     4,
 			"run_tests":      false,
 			"test_command":   "make test",
+			"review":         true,
+			"review_timeout": "5m",
 		},
 	}
 
@@ -105,6 +108,9 @@ func TestEngineer_LoadConfig_WithMergeQueue(t *testing.T) {
 	if e.config.MaxConcurrent != 2 {
 		t.Errorf("expected MaxConcurrent 2, got %d", e.config.MaxConcurrent)
 	}
+	if e.config.BatchSize != 4 {
+		t.Errorf("expected BatchSize 4, got %d", e.config.BatchSize)
+	}
 	if e.config.RunTests != false {
 		t.Errorf("expected RunTests false, got %v", e.config.RunTests)
 	}
@@ -112,7 +118,14 @@ func TestEngineer_LoadConfig_WithMergeQueue(t *testing.T) {
 		t.Errorf("expected TestCommand 'make test', got %q", e.config.TestCommand)
 	}
 
+	if !e.config.Review || e.config.ReviewTimeout != 5*time.Minute {
+		t.Errorf("expected review enabled with 5m timeout, got %v/%v", e.config.Review, e.config.ReviewTimeout)
+	}
+
 	// Check that defaults are preserved for unspecified fields
+	if e.config.ReviewAgent != "claude" {
+		t.Errorf("expected ReviewAgent default 'claude', got %q", e.config.ReviewAgent)
+	}
 	if e.config.OnConflict != "assign_back" {
 		t.Errorf("expected OnConflict default 'assign_back', got %q", e.config.OnConflict)
 	}
@@ -5,20 +5,25 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/account"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/concurrency"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/logging"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
 )
@@ -37,6 +42,11 @@ type Manager struct {
 	output  io.Writer // Output destination for user-facing messages
 }
 
+// log returns the refinery component's logger for this rig.
+func (m *Manager) log() *slog.Logger {
+	return logging.For(logging.ComponentRefinery).With("rig", m.rig.Name)
+}
+
 // NewManager creates a new refinery manager for a rig.
 func NewManager(r *rig.Rig) *Manager {
 	return &Manager{
@@ -59,7 +69,7 @@ func (m *Manager) stateFile() string {
 
 // SessionName returns the tmux session name for this refinery.
 func (m *Manager) SessionName() string {
-	return fmt.Sprintf("gt-%s-refinery", m.rig.Name)
+	return session.RefinerySessionName(m.rig.Name)
 }
 
 // loadState loads refinery state from disk.
@@ -104,7 +114,7 @@ func (m *Manager) Status() (*Refinery, error) {
 // If foreground is true, runs in the current process (blocking) using the Go-based polling loop.
 // Otherwise, spawns a Claude agent in a tmux session to process the merge queue.
 // The agentOverride parameter allows specifying an agent alias to use instead of the town default.
-func (m *Manager) Start(foreground bool, agentOverride string) error {
+func (m *Manager) Start(foreground bool, agentOverride string) (err error) {
 	ref, err := m.loadState()
 	if err != nil {
 		return err
@@ -135,11 +145,18 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 		return m.run(ref)
 	}
 
+	townRoot := filepath.Dir(m.rig.Path)
+	agent, model, maxTokens := config.RoleAgentModel(constants.RoleRefinery, townRoot, m.rig.Path)
+	if agentOverride != "" {
+		agent = agentOverride
+	}
+	ctx, span := telemetry.StartSession(constants.RoleRefinery, m.rig.Name, sessionID, agent, model, maxTokens)
+	defer func() { telemetry.End(span, err) }()
+
 	// Background mode: check if session already exists
 	running, _ := t.HasSession(sessionID)
 	if running {
 		// Session exists - check if agent is actually running (healthy vs zombie)
-		townRoot := filepath.Dir(m.rig.Path)
 		agentCfg := config.ResolveRoleAgentConfig(constants.RoleRefinery, townRoot, m.rig.Path)
 		if t.IsAgentRunning(sessionID, config.ExpectedPaneCommands(agentCfg)...) {
 			// Healthy - agent is running
@@ -154,6 +171,11 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 
 	// Note: No PID check per ZFC - tmux session is the source of truth
 
+	// Respect the town's concurrent session limits
+	if err := concurrency.Check(filepath.Dir(m.rig.Path), t, constants.RoleRefinery, m.rig.Name); err != nil {
+		return err
+	}
+
 	// Background mode: spawn a Claude agent in a tmux session
 	// The Claude agent handles MR processing using git commands and beads
 
@@ -174,10 +196,8 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 	}
 
 	// Build startup command first
-	townRoot := filepath.Dir(m.rig.Path)
 	var command string
 	if agentOverride != "" {
-		var err error
 		command, err = config.BuildAgentStartupCommandWithAgentOverride("refinery", m.rig.Name, townRoot, m.rig.Path, "", agentOverride)
 		if err != nil {
 			return fmt.Errorf("building startup command with agent override: %w", err)
@@ -185,12 +205,18 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 	} else {
 		command = config.BuildAgentStartupCommand("refinery", m.rig.Name, townRoot, m.rig.Path, "")
 	}
+	command, err = account.PrepareSession(townRoot, account.Selection{Role: constants.RoleRefinery, Rig: m.rig.Name}, sessionID, command)
+	if err != nil {
+		return fmt.Errorf("resolving account: %w", err)
+	}
+	command = config.PrependEnv(command, telemetry.Env(ctx))
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
 	if err := t.NewSessionWithCommand(sessionID, refineryRigDir, command); err != nil {
 		return fmt.Errorf("creating tmux session: %w", err)
 	}
+	_ = events.LogAudit(events.TypeAgentStarted, m.rig.Name+"/refinery", events.AgentPayload(m.rig.Name, constants.RoleRefinery, sessionID, agent))
 
 	// Set environment variables (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
@@ -205,13 +231,14 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 	envVars["GT_REFINERY"] = "1"
 
 	// Set all env vars in tmux session (for debugging) and they'll also be exported to Claude
+	log := logging.WithSession(m.log(), sessionID)
 	for k, v := range envVars {
-		_ = t.SetEnvironment(sessionID, k, v)
+		logging.DebugIf(log, "SetEnvironment "+k, t.SetEnvironment(sessionID, k, v))
 	}
 
 	// Apply theme (non-fatal: theming failure doesn't affect operation)
 	theme := tmux.AssignTheme(m.rig.Name)
-	_ = t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, "refinery", "refinery")
+	logging.DebugIf(log, "ConfigureGasTownSession", t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, "refinery", "refinery"))
 
 	// Update state to running
 	now := time.Now()
@@ -219,36 +246,35 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 	ref.StartedAt = &now
 	ref.PID = 0 // Claude agent doesn't have a PID we track
 	if err := m.saveState(ref); err != nil {
-		_ = t.KillSession(sessionID) // best-effort cleanup on state save failure
+		logging.WarnIf(log, "killing session after state save failure", t.KillSession(sessionID))
 		return fmt.Errorf("saving state: %w", err)
 	}
 
 	// Wait for Claude to start and show its prompt (non-fatal)
 	// WaitForRuntimeReady waits for the runtime to be ready
-	if err := t.WaitForRuntimeReady(sessionID, runtimeConfig, constants.ClaudeStartTimeout); err != nil {
-		// Non-fatal - try to continue anyway
-	}
+	// Non-fatal - try to continue anyway
+	logging.DebugIf(log, "WaitForRuntimeReady", t.WaitForRuntimeReady(sessionID, runtimeConfig, constants.ClaudeStartTimeout))
 
 	// Accept bypass permissions warning dialog if it appears.
-	_ = t.AcceptBypassPermissionsWarning(sessionID)
+	logging.DebugIf(log, "AcceptBypassPermissionsWarning", t.AcceptBypassPermissionsWarning(sessionID))
 
 	// Wait for runtime to be fully ready
 	runtime.SleepForReadyDelay(runtimeConfig)
-	_ = runtime.RunStartupFallback(t, sessionID, "refinery", runtimeConfig)
+	logging.DebugIf(log, "RunStartupFallback", runtime.RunStartupFallback(t, sessionID, "refinery", runtimeConfig))
 
 	// Inject startup nudge for predecessor discovery via /resume
 	address := fmt.Sprintf("%s/refinery", m.rig.Name)
-	_ = session.StartupNudge(t, sessionID, session.StartupNudgeConfig{
+	logging.DebugIf(log, "StartupNudge", session.StartupNudge(t, sessionID, session.StartupNudgeConfig{
 		Recipient: address,
 		Sender:    "deacon",
 		Topic:     "patrol",
-	}) // Non-fatal
+	})) // Non-fatal
 
 	// GUPP: Gas Town Universal Propulsion Principle
 	// Send the propulsion nudge to trigger autonomous patrol execution.
 	// Wait for beacon to be fully processed (needs to be separate prompt)
 	time.Sleep(2 * time.Second)
-	_ = t.NudgeSession(sessionID, session.PropulsionNudgeForRole("refinery", refineryRigDir)) // Non-fatal
+	logging.DebugIf(log, "NudgeSession PropulsionNudge", t.NudgeSession(sessionID, session.PropulsionNudgeForRole("refinery", refineryRigDir))) // Non-fatal
 
 	return nil
 }
@@ -272,7 +298,9 @@ func (m *Manager) Stop() error {
 
 	// Kill tmux session if it exists (best-effort: may already be dead)
 	if sessionRunning {
-		_ = t.KillSession(sessionID)
+		if err := t.KillSession(sessionID); err == nil {
+			_ = events.LogAudit(events.TypeAgentStopped, m.rig.Name+"/refinery", events.AgentPayload(m.rig.Name, constants.RoleRefinery, sessionID, ""))
+		}
 	}
 
 	// Note: No PID-based stop per ZFC - tmux session kill is sufficient
@@ -607,7 +635,7 @@ Then the Refinery will retry the merge.`,
 			mr.Branch, mr.TargetBranch, mr.TargetBranch),
 		Priority: mail.PriorityHigh,
 	}
-	_ = router.Send(msg) // best-effort notification
+	logging.WarnIf(m.log(), "sending mail", router.Send(msg), "to", msg.To)
 }
 
 // notifyWorkerMerged sends a success notification to a polecat.
@@ -623,7 +651,7 @@ Issue: %s
 Thank you for your contribution!`,
 			mr.Branch, mr.TargetBranch, mr.IssueID),
 	}
-	_ = router.Send(msg) // best-effort notification
+	logging.WarnIf(m.log(), "sending mail", router.Send(msg), "to", msg.To)
 }
 
 // Common errors for MR operations
@@ -790,7 +818,7 @@ Please review the feedback and address the issues before resubmitting.`,
 			mr.Branch, mr.IssueID, reason),
 		Priority: mail.PriorityNormal,
 	}
-	_ = router.Send(msg) // best-effort notification
+	logging.WarnIf(m.log(), "sending mail", router.Send(msg), "to", msg.To)
 }
 
 // findTownRoot walks up directories to find the town root.
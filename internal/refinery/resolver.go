@@ -0,0 +1,262 @@
+package refinery
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/logging"
+)
+
+// ErrUnresolvedConflicts indicates the resolver agent left conflicts behind.
+var ErrUnresolvedConflicts = errors.New("conflicts remain after resolution")
+
+// ErrResolverOutOfScope indicates the resolver agent changed the repository
+// beyond the conflicted files: it edited other paths or moved HEAD itself.
+var ErrResolverOutOfScope = errors.New("resolver agent changed more than the conflicted files")
+
+// conflictPromptTemplate instructs the resolver agent. It is deliberately
+// narrow: the agent may only touch the conflicted files, and the Refinery
+// (not the agent) owns staging, testing, and committing the merge.
+const conflictPromptTemplate = `You are the Gas Town Refinery's conflict resolver.
+
+A merge of branch %s is in progress in this repository and stopped on conflicts.
+Resolve the conflicts in ONLY these files:
+%s
+Rules:
+- Read each file, understand both sides, and edit it so it contains the correct
+  combined result with no conflict markers (<<<<<<<, =======, >>>>>>>).
+- Do not modify any other files.
+- Do not run git commands that change state (no add, commit, merge, rebase, reset, checkout).
+%s
+Exit when every listed file is resolved.`
+
+// buildConflictPrompt builds the resolver prompt for the given conflicted files.
+func buildConflictPrompt(branch string, files []string, testCommand string) string {
+	var list strings.Builder
+	for _, f := range files {
+		fmt.Fprintf(&list, "- %s\n", f)
+	}
+	tests := ""
+	if testCommand != "" {
+		tests = fmt.Sprintf("- Verify your resolution by running: %s\n", testCommand)
+	}
+	return fmt.Sprintf(conflictPromptTemplate, branch, list.String(), tests)
+}
+
+// resolveConflicts merges branch into the current (target) branch, runs a
+// non-interactive agent to resolve the conflicted files, and commits the merge
+// only if no conflicts remain and the queue's tests pass. On any failure the
+// merge is aborted, leaving the target branch untouched, so the caller can
+// bounce the MR back to the author. An agent that touches other files or
+// moves HEAD itself fails the resolution too.
+func (e *Engineer) resolveConflicts(ctx context.Context, branch, mergeMsg string, conflicts []string) error {
+	if err := e.git.MergeNoCommit(branch); err == nil {
+		// Target moved since the conflict check and the merge is now clean
+		return e.commitResolution(ctx, nil, mergeMsg)
+	}
+
+	files, err := e.git.GetConflictingFiles()
+	if err != nil || len(files) == 0 {
+		logging.WarnIf(e.log(), "aborting merge", e.git.AbortMerge())
+		if err != nil {
+			return fmt.Errorf("listing conflicted files: %w", err)
+		}
+		return fmt.Errorf("merge failed without conflicted files (expected %v)", conflicts)
+	}
+
+	agent := e.config.ConflictAgent
+	if agent == "" {
+		agent = string(config.AgentClaude)
+	}
+	prompt := buildConflictPrompt(branch, files, e.testCommandIfEnabled())
+	argv := config.BuildNonInteractiveArgs(agent, prompt)
+	if argv == nil {
+		logging.WarnIf(e.log(), "aborting merge", e.git.AbortMerge())
+		return fmt.Errorf("agent %q has no non-interactive mode", agent)
+	}
+
+	// Snapshot the merge state so anything the agent does beyond the
+	// listed files can be detected (and undone) after it exits
+	head, err := e.git.Rev("HEAD")
+	if err != nil {
+		logging.WarnIf(e.log(), "aborting merge", e.git.AbortMerge())
+		return fmt.Errorf("reading HEAD: %w", err)
+	}
+	before, err := e.git.Status()
+	if err != nil {
+		logging.WarnIf(e.log(), "aborting merge", e.git.AbortMerge())
+		return fmt.Errorf("reading status: %w", err)
+	}
+
+	runCtx := ctx
+	if e.config.ConflictTimeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, e.config.ConflictTimeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(runCtx, argv[0], argv[1:]...) //nolint:gosec // G204: agent command is from trusted preset config
+	cmd.Dir = e.workDir
+	cmd.Stdout = e.output
+	cmd.Stderr = e.output
+	if err := cmd.Run(); err != nil {
+		logging.WarnIf(e.log(), "aborting merge", e.git.AbortMerge())
+		if runCtx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("resolver agent timed out after %v", e.config.ConflictTimeout)
+		}
+		return fmt.Errorf("resolver agent failed: %w", err)
+	}
+
+	if err := e.checkResolverScope(head, before, files); err != nil {
+		return err
+	}
+	return e.commitResolution(ctx, files, mergeMsg)
+}
+
+// checkResolverScope verifies the resolver agent stayed within files: HEAD
+// must not have moved (the agent ran git commit, reset, or checkout itself)
+// and no other path may have changed since before. On a violation the work
+// tree is reset to head and stray untracked paths are removed, since merge
+// --abort cannot undo the agent's own commits or edits.
+func (e *Engineer) checkResolverScope(head string, before *git.GitStatus, files []string) error {
+	reset := func() {
+		logging.WarnIf(e.log(), "resetting work tree", e.git.ResetHard(head), "ref", head)
+	}
+
+	now, err := e.git.Rev("HEAD")
+	if err != nil {
+		reset()
+		return fmt.Errorf("reading HEAD: %w", err)
+	}
+	if now != head {
+		reset()
+		return fmt.Errorf("%w: HEAD moved from %s to %s", ErrResolverOutOfScope, head, now)
+	}
+
+	after, err := e.git.Status()
+	if err != nil {
+		reset()
+		return fmt.Errorf("reading status: %w", err)
+	}
+	stray := strayChanges(before, after, files)
+	if len(stray) == 0 {
+		return nil
+	}
+	reset()
+	for _, p := range difference(after.Untracked, before.Untracked) {
+		if !slices.Contains(files, p) {
+			logging.WarnIf(e.log(), "removing stray file", os.RemoveAll(filepath.Join(e.workDir, p)), "path", p)
+		}
+	}
+	return fmt.Errorf("%w: %v", ErrResolverOutOfScope, stray)
+}
+
+// strayChanges returns the paths whose status changed between before and
+// after, other than files.
+func strayChanges(before, after *git.GitStatus, files []string) []string {
+	var stray []string
+	for _, set := range [][2][]string{
+		{before.Staged, after.Staged},
+		{before.Unstaged, after.Unstaged},
+		{before.Untracked, after.Untracked},
+		{before.Conflicted, after.Conflicted},
+	} {
+		for _, p := range append(difference(set[1], set[0]), difference(set[0], set[1])...) {
+			if !slices.Contains(files, p) && !slices.Contains(stray, p) {
+				stray = append(stray, p)
+			}
+		}
+	}
+	return stray
+}
+
+// difference returns the elements of a not in b.
+func difference(a, b []string) []string {
+	var out []string
+	for _, s := range a {
+		if !slices.Contains(b, s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// commitResolution verifies the resolved files, runs tests, and commits the
+// in-progress merge. Aborts the merge on failure.
+func (e *Engineer) commitResolution(ctx context.Context, files []string, mergeMsg string) error {
+	for _, f := range files {
+		marked, err := hasConflictMarkers(filepath.Join(e.workDir, f))
+		if err != nil && !os.IsNotExist(err) {
+			logging.WarnIf(e.log(), "aborting merge", e.git.AbortMerge())
+			return fmt.Errorf("checking %s: %w", f, err)
+		}
+		if marked {
+			logging.WarnIf(e.log(), "aborting merge", e.git.AbortMerge())
+			return fmt.Errorf("%w: %s", ErrUnresolvedConflicts, f)
+		}
+	}
+
+	if len(files) > 0 {
+		// -A semantics: stage edits and deletions of the resolved paths
+		if err := e.git.Add(append([]string{"-A", "--"}, files...)...); err != nil {
+			logging.WarnIf(e.log(), "aborting merge", e.git.AbortMerge())
+			return fmt.Errorf("staging resolution: %w", err)
+		}
+	}
+
+	if remaining, err := e.git.GetConflictingFiles(); err == nil && len(remaining) > 0 {
+		logging.WarnIf(e.log(), "aborting merge", e.git.AbortMerge())
+		return fmt.Errorf("%w: %v", ErrUnresolvedConflicts, remaining)
+	}
+
+	if e.testCommandIfEnabled() != "" {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests on resolution: %s\n", e.config.TestCommand)
+		if result := e.runTests(ctx); !result.Success {
+			logging.WarnIf(e.log(), "aborting merge", e.git.AbortMerge())
+			return fmt.Errorf("tests failed on resolution: %s", result.Error)
+		}
+	}
+
+	if err := e.git.Commit(mergeMsg); err != nil {
+		logging.WarnIf(e.log(), "aborting merge", e.git.AbortMerge())
+		return fmt.Errorf("committing resolution: %w", err)
+	}
+	return nil
+}
+
+// testCommandIfEnabled returns the queue's test command, or "" if tests are disabled.
+func (e *Engineer) testCommandIfEnabled() string {
+	if !e.config.RunTests {
+		return ""
+	}
+	return e.config.TestCommand
+}
+
+// hasConflictMarkers reports whether a file still contains git conflict markers.
+func hasConflictMarkers(path string) (bool, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is a conflicted file in the refinery worktree
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// "=======" alone is not checked: it is valid content (e.g., Markdown headings)
+		if strings.HasPrefix(line, "<<<<<<< ") || strings.HasPrefix(line, ">>>>>>> ") {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
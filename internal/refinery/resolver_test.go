@@ -0,0 +1,246 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestBuildConflictPrompt(t *testing.T) {
+	prompt := buildConflictPrompt("polecat/Nux/gt-abc", []string{"a.go", "b.go"}, "go test ./...")
+	for _, want := range []string{"polecat/Nux/gt-abc", "- a.go\n", "- b.go\n", "go test ./..."} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+
+	if strings.Contains(buildConflictPrompt("b", []string{"a.go"}, ""), "Verify your resolution") {
+		t.Error("prompt should not mention tests when no test command is set")
+	}
+}
+
+func TestHasConflictMarkers(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"clean", "package main\n", false},
+		{"markers", "<<<<<<< HEAD\nours\n=======\ntheirs\n>>>>>>> feature\n", true},
+		{"markdown heading", "Title\n=======\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := hasConflictMarkers(path)
+			if err != nil {
+				t.Fatalf("hasConflictMarkers: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("hasConflictMarkers = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// setupConflictRig creates a rig whose refinery clone has a feature branch
+// that conflicts with main on conflict.txt.
+func setupConflictRig(t *testing.T) (*rig.Rig, string) {
+	t.Helper()
+	rigPath := t.TempDir()
+	repo := filepath.Join(rigPath, "refinery", "rig")
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	gitRun := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, "conflict.txt"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gitRun("init", "-b", "main")
+	gitRun("config", "user.email", "test@test.com")
+	gitRun("config", "user.name", "Test User")
+	write("base\n")
+	gitRun("add", ".")
+	gitRun("commit", "-m", "base")
+	gitRun("checkout", "-b", "feature")
+	write("feature\n")
+	gitRun("commit", "-am", "feature change")
+	gitRun("checkout", "main")
+	write("main\n")
+	gitRun("commit", "-am", "main change")
+
+	return &rig.Rig{Name: "test-rig", Path: rigPath}, repo
+}
+
+// registerFakeAgent registers a non-interactive agent that runs script via sh.
+func registerFakeAgent(t *testing.T, script string) {
+	t.Helper()
+	config.ResetRegistryForTesting()
+	t.Cleanup(config.ResetRegistryForTesting)
+
+	registry := map[string]interface{}{
+		"version": 1,
+		"agents": map[string]interface{}{
+			"fake-resolver": map[string]interface{}{
+				"name":            "fake-resolver",
+				"command":         "sh",
+				"args":            []string{"-c", script, "sh"},
+				"non_interactive": map[string]interface{}{},
+			},
+		},
+	}
+	data, _ := json.Marshal(registry)
+	path := filepath.Join(t.TempDir(), "agents.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.LoadAgentRegistry(path); err != nil {
+		t.Fatalf("LoadAgentRegistry: %v", err)
+	}
+}
+
+func TestResolveConflicts_AgentResolves(t *testing.T) {
+	registerFakeAgent(t, "printf 'merged\\n' > conflict.txt")
+	r, repo := setupConflictRig(t)
+
+	e := NewEngineer(r)
+	e.SetOutput(&bytes.Buffer{})
+	e.config.OnConflict = config.OnConflictAgentResolve
+	e.config.ConflictAgent = "fake-resolver"
+	e.config.RunTests = true
+	e.config.TestCommand = "grep -q merged conflict.txt"
+
+	if err := e.resolveConflicts(context.Background(), "feature", "Merge feature into main", []string{"conflict.txt"}); err != nil {
+		t.Fatalf("resolveConflicts: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(repo, "conflict.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "merged\n" {
+		t.Errorf("conflict.txt = %q, want resolved content", data)
+	}
+
+	// HEAD should be a merge commit with two parents
+	out, err := exec.Command("git", "-C", repo, "rev-list", "--parents", "-n", "1", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parents := strings.Fields(string(out)); len(parents) != 3 {
+		t.Errorf("HEAD parents = %v, want merge commit", parents)
+	}
+}
+
+func TestResolveConflicts_MarkersRemain(t *testing.T) {
+	registerFakeAgent(t, "true")
+	r, repo := setupConflictRig(t)
+
+	e := NewEngineer(r)
+	e.SetOutput(&bytes.Buffer{})
+	e.config.ConflictAgent = "fake-resolver"
+
+	err := e.resolveConflicts(context.Background(), "feature", "Merge feature into main", []string{"conflict.txt"})
+	if !errors.Is(err, ErrUnresolvedConflicts) {
+		t.Fatalf("resolveConflicts error = %v, want ErrUnresolvedConflicts", err)
+	}
+
+	// Merge must be aborted, leaving main untouched
+	data, _ := os.ReadFile(filepath.Join(repo, "conflict.txt"))
+	if string(data) != "main\n" {
+		t.Errorf("conflict.txt = %q, want main content after abort", data)
+	}
+}
+
+func TestResolveConflicts_TestsFail(t *testing.T) {
+	registerFakeAgent(t, "printf 'merged\\n' > conflict.txt")
+	r, repo := setupConflictRig(t)
+
+	e := NewEngineer(r)
+	e.SetOutput(&bytes.Buffer{})
+	e.config.ConflictAgent = "fake-resolver"
+	e.config.RunTests = true
+	e.config.TestCommand = "false"
+
+	err := e.resolveConflicts(context.Background(), "feature", "Merge feature into main", []string{"conflict.txt"})
+	if err == nil || !strings.Contains(err.Error(), "tests failed") {
+		t.Fatalf("resolveConflicts error = %v, want tests failed", err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(repo, "conflict.txt"))
+	if string(data) != "main\n" {
+		t.Errorf("conflict.txt = %q, want main content after abort", data)
+	}
+}
+
+func TestResolveConflicts_AgentCommits(t *testing.T) {
+	registerFakeAgent(t, "printf 'merged\\n' > conflict.txt && git add conflict.txt && git commit -qm 'agent merge'")
+	r, repo := setupConflictRig(t)
+	head, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+
+	e := NewEngineer(r)
+	e.SetOutput(&bytes.Buffer{})
+	e.config.ConflictAgent = "fake-resolver"
+
+	err := e.resolveConflicts(context.Background(), "feature", "Merge feature into main", []string{"conflict.txt"})
+	if !errors.Is(err, ErrResolverOutOfScope) {
+		t.Fatalf("resolveConflicts error = %v, want ErrResolverOutOfScope", err)
+	}
+
+	// The agent's commit must be discarded
+	now, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	if string(now) != string(head) {
+		t.Errorf("HEAD = %s, want %s after reset", now, head)
+	}
+	data, _ := os.ReadFile(filepath.Join(repo, "conflict.txt"))
+	if string(data) != "main\n" {
+		t.Errorf("conflict.txt = %q, want main content after reset", data)
+	}
+}
+
+func TestResolveConflicts_AgentTouchesOtherFiles(t *testing.T) {
+	registerFakeAgent(t, "printf 'merged\\n' > conflict.txt && echo stray > stray.txt")
+	r, repo := setupConflictRig(t)
+
+	e := NewEngineer(r)
+	e.SetOutput(&bytes.Buffer{})
+	e.config.ConflictAgent = "fake-resolver"
+
+	err := e.resolveConflicts(context.Background(), "feature", "Merge feature into main", []string{"conflict.txt"})
+	if !errors.Is(err, ErrResolverOutOfScope) || !strings.Contains(err.Error(), "stray.txt") {
+		t.Fatalf("resolveConflicts error = %v, want ErrResolverOutOfScope naming stray.txt", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(repo, "stray.txt")); !os.IsNotExist(err) {
+		t.Errorf("stray.txt should be removed, stat err = %v", err)
+	}
+	out, _ := exec.Command("git", "-C", repo, "status", "--porcelain").Output()
+	if len(out) != 0 {
+		t.Errorf("work tree not clean after reset:\n%s", out)
+	}
+}
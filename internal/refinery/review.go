@@ -0,0 +1,182 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/logging"
+	"github.com/steveyegge/gastown/internal/review"
+	"github.com/steveyegge/gastown/internal/templates"
+)
+
+// reviewAssignmentTemplate is appended to the reviewer role prompt so the
+// agent knows which MR it is judging and what the change claims to do.
+const reviewAssignmentTemplate = `
+
+## Assignment
+
+- MR: %s
+- Branch: %s
+- Target: %s
+- Source issue: %s
+
+Review this MR now and record a verdict before exiting.`
+
+// reviewGate runs a reviewer agent over the MR's diff and returns a failed
+// ProcessResult if the reviewer requested changes (or never reached a
+// verdict), or nil if the MR was approved or review is disabled. The verdict
+// is recorded on the MR bead either way.
+func (e *Engineer) reviewGate(ctx context.Context, mrID, branch, target, sourceIssue string) *ProcessResult {
+	if !e.config.Review || mrID == "" {
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Requesting review of %s...\n", branch)
+	r, err := e.runReview(ctx, mrID, branch, target, sourceIssue)
+	if err != nil {
+		return &ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("review failed: %v", err),
+		}
+	}
+	e.recordVerdict(mrID, r)
+	return reviewResult(r)
+}
+
+// reviewResult maps a completed review to a gate result.
+func reviewResult(r *review.Review) *ProcessResult {
+	if r.Verdict == review.VerdictApproved {
+		return nil
+	}
+	return &ProcessResult{
+		Success:        false,
+		ReviewRejected: true,
+		Error:          fmt.Sprintf("review requested changes (%d blockers)", len(r.Blockers())),
+		CheckReport:    review.FormatReport(r),
+	}
+}
+
+// runReview checks the branch out into a scratch worktree, runs the reviewer
+// agent there, and returns the review it recorded.
+func (e *Engineer) runReview(ctx context.Context, mrID, branch, target, sourceIssue string) (*review.Review, error) {
+	townRoot := filepath.Dir(e.rig.Path)
+	if _, err := review.Start(townRoot, review.Review{MRID: mrID, Rig: e.rig.Name, Branch: branch, Target: target}); err != nil {
+		return nil, fmt.Errorf("starting review: %w", err)
+	}
+	defer func() { _ = review.Remove(townRoot, mrID) }()
+
+	agent := e.config.ReviewAgent
+	if agent == "" {
+		agent = string(config.AgentClaude)
+	}
+
+	// The worktree lives inside the rig so cwd-based town detection works
+	// for the reviewer's gt commands.
+	reviewDir := filepath.Join(e.rig.Path, "refinery", "review", mrID)
+	// Clear out a review worktree left behind by a crash
+	logging.WarnIf(e.log(), "removing directory", os.RemoveAll(reviewDir), "path", reviewDir)
+	if err := e.git.RemoveWorktree(reviewDir, true); !errors.Is(err, git.ErrNotWorktree) {
+		logging.WarnIf(e.log(), "removing stale worktree", err, "path", reviewDir)
+	}
+	if err := e.git.AddWorktree(reviewDir, git.AddWorktreeOptions{Detach: true, StartPoint: branch}); err != nil {
+		return nil, fmt.Errorf("checking out %s for review: %w", branch, err)
+	}
+	defer func() {
+		logging.WarnIf(e.log(), "removing worktree", e.git.RemoveWorktree(reviewDir, true), "path", reviewDir)
+		logging.WarnIf(e.log(), "removing directory", os.RemoveAll(reviewDir), "path", reviewDir)
+	}()
+
+	prompt, err := e.buildReviewPrompt(townRoot, reviewDir, mrID, branch, target, sourceIssue)
+	if err != nil {
+		return nil, err
+	}
+	argv := config.BuildNonInteractiveArgs(agent, prompt)
+	if argv == nil {
+		return nil, fmt.Errorf("agent %q has no non-interactive mode", agent)
+	}
+
+	runCtx := ctx
+	if e.config.ReviewTimeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, e.config.ReviewTimeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(runCtx, argv[0], argv[1:]...) //nolint:gosec // G204: agent command is from trusted preset config
+	cmd.Dir = reviewDir
+	cmd.Env = append(os.Environ(),
+		"GT_ROLE="+e.rig.Name+"/"+constants.RoleReviewer,
+		"GT_RIG="+e.rig.Name,
+		"GT_TOWN_ROOT="+townRoot,
+		"GT_REVIEW_MR="+mrID,
+	)
+	cmd.Stdout = e.output
+	cmd.Stderr = e.output
+	if err := cmd.Run(); err != nil {
+		if runCtx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("reviewer agent timed out after %v", e.config.ReviewTimeout)
+		}
+		return nil, fmt.Errorf("reviewer agent failed: %w", err)
+	}
+
+	r, err := review.Load(townRoot, mrID)
+	if err != nil {
+		return nil, err
+	}
+	if r.Verdict == "" {
+		return nil, fmt.Errorf("reviewer exited without a verdict")
+	}
+	return r, nil
+}
+
+// buildReviewPrompt renders the reviewer role context plus the MR assignment.
+func (e *Engineer) buildReviewPrompt(townRoot, workDir, mrID, branch, target, sourceIssue string) (string, error) {
+	tmpl, err := templates.New()
+	if err != nil {
+		return "", fmt.Errorf("loading templates: %w", err)
+	}
+	prompt, err := tmpl.RenderRole(constants.RoleReviewer, templates.RoleData{
+		Role:          constants.RoleReviewer,
+		RigName:       e.rig.Name,
+		TownRoot:      townRoot,
+		TownName:      filepath.Base(townRoot),
+		WorkDir:       workDir,
+		DefaultBranch: e.rig.DefaultBranch(),
+	})
+	if err != nil {
+		return "", err
+	}
+	if sourceIssue == "" {
+		sourceIssue = "(none)"
+	}
+	return prompt + fmt.Sprintf(reviewAssignmentTemplate, mrID, branch, target, sourceIssue), nil
+}
+
+// recordVerdict stores the review verdict and reviewer on the MR bead.
+func (e *Engineer) recordVerdict(mrID string, r *review.Review) {
+	mrBead, err := e.beads.Show(mrID)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to fetch MR bead %s: %v\n", mrID, err)
+		return
+	}
+	mrFields := beads.ParseMRFields(mrBead)
+	if mrFields == nil {
+		mrFields = &beads.MRFields{}
+	}
+	mrFields.ReviewVerdict = r.Verdict
+	mrFields.Reviewer = r.Reviewer
+	newDesc := beads.SetMRFields(mrBead, mrFields)
+	if err := e.beads.Update(mrID, beads.UpdateOptions{Description: &newDesc}); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record review verdict on %s: %v\n", mrID, err)
+		return
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Review verdict for %s: %s\n", mrID, r.Verdict)
+}
@@ -0,0 +1,70 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/review"
+)
+
+func TestReviewResult(t *testing.T) {
+	if result := reviewResult(&review.Review{Verdict: review.VerdictApproved}); result != nil {
+		t.Errorf("approved review result = %+v, want nil", result)
+	}
+
+	result := reviewResult(&review.Review{
+		Branch:   "polecat/Nux/gt-abc",
+		Verdict:  review.VerdictChangesRequested,
+		Findings: []review.Finding{{File: "a.go", Line: 3, Severity: review.SeverityBlocker, Message: "nil deref"}},
+	})
+	if result == nil || !result.ReviewRejected || result.Success {
+		t.Fatalf("changes requested result = %+v, want rejection", result)
+	}
+	if !strings.Contains(result.CheckReport, "- a.go:3: nil deref") {
+		t.Errorf("report missing finding:\n%s", result.CheckReport)
+	}
+}
+
+func TestRunReview_ReadsVerdictFromCheckout(t *testing.T) {
+	// The fake reviewer checks it is running in the branch checkout, then
+	// records its verdict the way `gt review request-changes` would.
+	registerFakeAgent(t, `grep -q feature conflict.txt || exit 1
+printf '{"mr_id":"%s","verdict":"changes_requested","reviewer":"test-rig/reviewer","findings":[{"file":"conflict.txt","severity":"blocker","message":"wrong"}]}' "$GT_REVIEW_MR" > "$GT_TOWN_ROOT/.runtime/reviews/$GT_REVIEW_MR.json"`)
+	r, _ := setupConflictRig(t)
+
+	e := NewEngineer(r)
+	e.SetOutput(&bytes.Buffer{})
+	e.config.ReviewAgent = "fake-resolver"
+
+	got, err := e.runReview(context.Background(), "gt-mr1", "feature", "main", "gt-abc")
+	if err != nil {
+		t.Fatalf("runReview: %v", err)
+	}
+	if got.Verdict != review.VerdictChangesRequested || got.Reviewer != "test-rig/reviewer" || len(got.Blockers()) != 1 {
+		t.Errorf("review = %+v", got)
+	}
+
+	if _, err := os.Stat(filepath.Join(r.Path, "refinery", "review", "gt-mr1")); !os.IsNotExist(err) {
+		t.Errorf("review worktree not cleaned up: %v", err)
+	}
+	if _, err := review.Load(filepath.Dir(r.Path), "gt-mr1"); err == nil {
+		t.Error("review file not cleaned up")
+	}
+}
+
+func TestRunReview_NoVerdict(t *testing.T) {
+	registerFakeAgent(t, "true")
+	r, _ := setupConflictRig(t)
+
+	e := NewEngineer(r)
+	e.SetOutput(&bytes.Buffer{})
+	e.config.ReviewAgent = "fake-resolver"
+
+	if _, err := e.runReview(context.Background(), "gt-mr1", "feature", "main", ""); err == nil || !strings.Contains(err.Error(), "without a verdict") {
+		t.Errorf("runReview error = %v, want missing verdict", err)
+	}
+}
@@ -0,0 +1,275 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/logging"
+)
+
+// TrainResult is the outcome for one MR processed in a merge train.
+type TrainResult struct {
+	MR     *MRInfo
+	Result ProcessResult
+}
+
+// PlanTrains orders ready MRs by score (highest first) and groups them into
+// merge trains of at most batchSize MRs. Only MRs with the same target branch
+// ride together. A batchSize below 2 yields one train per MR (serial merging).
+func PlanTrains(mrs []*MRInfo, batchSize int, now time.Time) [][]*MRInfo {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	ordered := append([]*MRInfo(nil), mrs...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].ScoreAt(now) > ordered[j].ScoreAt(now)
+	})
+
+	// Trains are emitted in the order of their best MR, so the
+	// highest-scoring work still lands first.
+	var trains [][]*MRInfo
+	open := make(map[string]int) // target -> index of its train still filling
+	for _, mr := range ordered {
+		i, ok := open[mr.Target]
+		if !ok || len(trains[i]) >= batchSize {
+			trains = append(trains, nil)
+			i = len(trains) - 1
+			open[mr.Target] = i
+		}
+		trains[i] = append(trains[i], mr)
+	}
+	return trains
+}
+
+// bisectTrain splits a train into the MRs that pass together and the ones
+// that break the build. test reports whether the given MRs, merged in order
+// onto the target, pass. The base is assumed to pass, and the returned
+// passing MRs are always a set that test accepted.
+//
+// When the whole train fails, a binary search over prefixes finds the first
+// MR whose addition breaks the build; it is ejected and the MRs after it are
+// retried on top of the passing prefix. Each culprit costs about log2(n)
+// test runs instead of the n runs of serial testing.
+func bisectTrain(mrs []*MRInfo, test func([]*MRInfo) (bool, error)) (passed, failed []*MRInfo, err error) {
+	remaining := mrs
+	for len(remaining) > 0 {
+		ok, err := test(concatMRs(passed, remaining))
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			return append(passed, remaining...), failed, nil
+		}
+
+		// passed+remaining[:lo] passes, passed+remaining[:hi] fails
+		lo, hi := 0, len(remaining)
+		for hi-lo > 1 {
+			mid := (lo + hi) / 2
+			ok, err := test(concatMRs(passed, remaining[:mid]))
+			if err != nil {
+				return nil, nil, err
+			}
+			if ok {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		passed = append(passed, remaining[:lo]...)
+		failed = append(failed, remaining[hi-1])
+		remaining = remaining[hi:]
+	}
+	return passed, failed, nil
+}
+
+func concatMRs(a, b []*MRInfo) []*MRInfo {
+	return append(append([]*MRInfo(nil), a...), b...)
+}
+
+// ProcessTrain merges a train of MRs (all with the same target) in one pass.
+//
+// Each MR first goes through the per-MR gates (pre-merge checks and review)
+// and is merged onto the train. MRs that conflict with the target or with
+// earlier MRs in the train are ejected with a conflict result. The assembled
+// train is then tested once; if the tests fail, the train is bisected to find
+// the MRs that broke them. The passing MRs are pushed together.
+//
+// Results are returned in train order. Callers handle each result with
+// HandleMRInfoSuccess or HandleMRInfoFailure, as for ProcessMRInfo.
+func (e *Engineer) ProcessTrain(ctx context.Context, mrs []*MRInfo) []TrainResult {
+	results := make(map[*MRInfo]ProcessResult, len(mrs))
+	finish := func() []TrainResult {
+		out := make([]TrainResult, len(mrs))
+		for i, mr := range mrs {
+			out[i] = TrainResult{MR: mr, Result: results[mr]}
+		}
+		return out
+	}
+	failAll := func(pending []*MRInfo, result ProcessResult) []TrainResult {
+		for _, mr := range pending {
+			results[mr] = result
+		}
+		return finish()
+	}
+	if len(mrs) == 0 {
+		return nil
+	}
+
+	target := mrs[0].Target
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Assembling merge train of %d MRs into %s\n", len(mrs), target)
+	if err := e.git.Checkout(target); err != nil {
+		return failAll(mrs, ProcessResult{Error: fmt.Sprintf("failed to checkout target %s: %v", target, err)})
+	}
+	if err := e.git.Pull("origin", target); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: pull from origin/%s: %v (continuing)\n", target, err)
+	}
+	base, err := e.git.Rev("HEAD")
+	if err != nil {
+		return failAll(mrs, ProcessResult{Error: fmt.Sprintf("failed to get target SHA: %v", err)})
+	}
+
+	// Step 1: Admit MRs that pass their own gates and merge cleanly
+	var admitted []*MRInfo
+	for _, mr := range mrs {
+		if mr.Target != target {
+			results[mr] = ProcessResult{Error: fmt.Sprintf("target %s does not match train target %s", mr.Target, target)}
+			continue
+		}
+		if result := e.admitToTrain(ctx, mr); result != nil {
+			results[mr] = *result
+			continue
+		}
+		admitted = append(admitted, mr)
+	}
+	if len(admitted) == 0 {
+		logging.WarnIf(e.log(), "resetting work tree", e.git.ResetHard(base), "ref", base)
+		return finish()
+	}
+
+	// Step 2: Test the train, bisecting on failure
+	passed := admitted
+	if e.config.RunTests && e.config.TestCommand != "" {
+		var failed []*MRInfo
+		passed, failed, err = bisectTrain(admitted, func(train []*MRInfo) (bool, error) {
+			return e.testTrain(ctx, base, train)
+		})
+		if err != nil {
+			logging.WarnIf(e.log(), "resetting work tree", e.git.ResetHard(base), "ref", base)
+			return failAll(admitted, ProcessResult{Error: fmt.Sprintf("merge train aborted: %v", err)})
+		}
+		for _, mr := range failed {
+			results[mr] = ProcessResult{
+				TestsFailed: true,
+				Error:       fmt.Sprintf("tests failed with %s merged into %s (isolated by merge train bisection)", mr.Branch, target),
+			}
+		}
+		if len(passed) == 0 {
+			logging.WarnIf(e.log(), "resetting work tree", e.git.ResetHard(base), "ref", base)
+			return finish()
+		}
+	}
+
+	// Step 3: Rebuild the passing train and push it
+	commits, err := e.buildTrain(base, passed)
+	if err != nil {
+		logging.WarnIf(e.log(), "resetting work tree", e.git.ResetHard(base), "ref", base)
+		return failAll(passed, ProcessResult{Error: fmt.Sprintf("failed to rebuild merge train: %v", err)})
+	}
+	push := e.pushMerge(target)
+	for i, mr := range passed {
+		if push.Success {
+			results[mr] = ProcessResult{Success: true, MergeCommit: commits[i]}
+		} else {
+			results[mr] = push
+		}
+	}
+	if !push.Success {
+		logging.WarnIf(e.log(), "resetting work tree", e.git.ResetHard(base), "ref", base)
+	}
+	return finish()
+}
+
+// admitToTrain runs the per-MR gates and merges the MR onto the train at
+// HEAD. Returns a failed ProcessResult if the MR cannot ride the train.
+func (e *Engineer) admitToTrain(ctx context.Context, mr *MRInfo) *ProcessResult {
+	exists, err := e.git.BranchExists(mr.Branch)
+	if err != nil {
+		return &ProcessResult{Error: fmt.Sprintf("failed to check branch %s: %v", mr.Branch, err)}
+	}
+	if !exists {
+		return &ProcessResult{Error: fmt.Sprintf("branch %s not found locally", mr.Branch)}
+	}
+
+	// Checks see the MR merged onto the train so far
+	if result := e.checkGate(ctx, mr.Branch, "HEAD", true); result != nil {
+		return result
+	}
+	if result := e.reviewGate(ctx, mr.ID, mr.Branch, mr.Target, mr.SourceIssue); result != nil {
+		return result
+	}
+
+	if err := e.git.MergeNoFF(mr.Branch, trainMergeMessage(mr)); err != nil {
+		conflicts, conflictErr := e.git.GetConflictingFiles()
+		logging.WarnIf(e.log(), "aborting merge", e.git.AbortMerge())
+		if conflictErr == nil && len(conflicts) > 0 {
+			return &ProcessResult{
+				Conflict: true,
+				Error:    fmt.Sprintf("merge conflicts in merge train: %v", conflicts),
+			}
+		}
+		return &ProcessResult{Error: fmt.Sprintf("merge failed: %v", err)}
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Added %s to merge train\n", mr.Branch)
+	return nil
+}
+
+// testTrain builds the given MRs on base and runs the test command.
+func (e *Engineer) testTrain(ctx context.Context, base string, train []*MRInfo) (bool, error) {
+	if _, err := e.buildTrain(base, train); err != nil {
+		// Without an ejected MR a later one may no longer merge cleanly;
+		// count that as a failure so bisection ejects it too.
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Merge train does not build: %v\n", err)
+		return false, nil
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Testing merge train of %d MRs: %s\n", len(train), e.config.TestCommand)
+	result := e.runTests(ctx)
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+	status := "passed"
+	if !result.Success {
+		status = "FAILED"
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Merge train tests %s\n", status)
+	return result.Success, nil
+}
+
+// buildTrain resets the target to base and merges the MRs in order,
+// returning each MR's merge commit.
+func (e *Engineer) buildTrain(base string, train []*MRInfo) ([]string, error) {
+	if err := e.git.ResetHard(base); err != nil {
+		return nil, fmt.Errorf("resetting to %s: %w", base, err)
+	}
+	commits := make([]string, len(train))
+	for i, mr := range train {
+		if err := e.git.MergeNoFF(mr.Branch, trainMergeMessage(mr)); err != nil {
+			logging.WarnIf(e.log(), "aborting merge", e.git.AbortMerge())
+			return nil, fmt.Errorf("merging %s: %w", mr.Branch, err)
+		}
+		sha, err := e.git.Rev("HEAD")
+		if err != nil {
+			return nil, err
+		}
+		commits[i] = sha
+	}
+	return commits, nil
+}
+
+func trainMergeMessage(mr *MRInfo) string {
+	if mr.SourceIssue != "" {
+		return fmt.Sprintf("Merge %s into %s (%s)", mr.Branch, mr.Target, mr.SourceIssue)
+	}
+	return fmt.Sprintf("Merge %s into %s", mr.Branch, mr.Target)
+}
@@ -0,0 +1,179 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestPlanTrains(t *testing.T) {
+	now := time.Now()
+	mr := func(id, target string, priority int) *MRInfo {
+		return &MRInfo{ID: id, Target: target, Priority: priority, CreatedAt: now}
+	}
+	mrs := []*MRInfo{
+		mr("low", "main", 4),
+		mr("urgent", "main", 0),
+		mr("epic", "integration/gt-epic", 1),
+		mr("mid", "main", 2),
+		mr("high", "main", 1),
+	}
+
+	ids := func(trains [][]*MRInfo) [][]string {
+		var out [][]string
+		for _, train := range trains {
+			var t []string
+			for _, m := range train {
+				t = append(t, m.ID)
+			}
+			out = append(out, t)
+		}
+		return out
+	}
+
+	got := ids(PlanTrains(mrs, 3, now))
+	want := [][]string{{"urgent", "high", "mid"}, {"epic"}, {"low"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PlanTrains(3) = %v, want %v", got, want)
+	}
+
+	got = ids(PlanTrains(mrs, 0, now))
+	if len(got) != len(mrs) || got[0][0] != "urgent" {
+		t.Errorf("PlanTrains(0) = %v, want one MR per train, highest score first", got)
+	}
+}
+
+func TestBisectTrain(t *testing.T) {
+	mrs := make([]*MRInfo, 8)
+	for i := range mrs {
+		mrs[i] = &MRInfo{ID: string(rune('a' + i))}
+	}
+	bad := map[string]bool{"c": true, "g": true}
+
+	runs := 0
+	passed, failed, err := bisectTrain(mrs, func(train []*MRInfo) (bool, error) {
+		runs++
+		for _, mr := range train {
+			if bad[mr.ID] {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var passedIDs, failedIDs []string
+	for _, mr := range passed {
+		passedIDs = append(passedIDs, mr.ID)
+	}
+	for _, mr := range failed {
+		failedIDs = append(failedIDs, mr.ID)
+	}
+	if !reflect.DeepEqual(passedIDs, []string{"a", "b", "d", "e", "f", "h"}) {
+		t.Errorf("passed = %v", passedIDs)
+	}
+	if !reflect.DeepEqual(failedIDs, []string{"c", "g"}) {
+		t.Errorf("failed = %v", failedIDs)
+	}
+	if runs >= 2*len(mrs) {
+		t.Errorf("bisection took %d test runs for %d MRs", runs, len(mrs))
+	}
+}
+
+func TestProcessTrain(t *testing.T) {
+	rigPath := t.TempDir()
+	t.Chdir(rigPath) // keep the push audit event out of the source tree
+	origin := filepath.Join(rigPath, "origin.git")
+	repo := filepath.Join(rigPath, "refinery", "rig")
+	gitRun := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	branch := func(name, file, content string) {
+		t.Helper()
+		gitRun("checkout", "-q", "-b", name, "main")
+		write(file, content)
+		gitRun("add", ".")
+		gitRun("commit", "-q", "-m", name)
+	}
+
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("git", "init", "-q", "--bare", "-b", "main", origin).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v\n%s", err, out)
+	}
+	gitRun("init", "-q", "-b", "main")
+	gitRun("config", "user.email", "test@test.com")
+	gitRun("config", "user.name", "Test User")
+	gitRun("remote", "add", "origin", origin)
+	write("shared.txt", "base\n")
+	gitRun("add", ".")
+	gitRun("commit", "-q", "-m", "base")
+	gitRun("push", "-q", "origin", "main")
+
+	branch("polecat/a", "a.txt", "a\n")
+	branch("polecat/bad", "bad.txt", "breaks the build\n")
+	branch("polecat/c", "c.txt", "c\n")
+	branch("polecat/conflict-1", "shared.txt", "one\n")
+	branch("polecat/conflict-2", "shared.txt", "two\n")
+	gitRun("checkout", "-q", "main")
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: rigPath})
+	e.SetOutput(&bytes.Buffer{})
+	e.config.RunTests = true
+	e.config.TestCommand = "test ! -f bad.txt"
+
+	var mrs []*MRInfo
+	for _, b := range []string{"polecat/a", "polecat/bad", "polecat/conflict-1", "polecat/conflict-2", "polecat/c"} {
+		mrs = append(mrs, &MRInfo{Branch: b, Target: "main"})
+	}
+	results := e.ProcessTrain(context.Background(), mrs)
+	if len(results) != len(mrs) {
+		t.Fatalf("got %d results, want %d", len(results), len(mrs))
+	}
+
+	byBranch := make(map[string]ProcessResult)
+	for _, r := range results {
+		byBranch[r.MR.Branch] = r.Result
+	}
+	for _, b := range []string{"polecat/a", "polecat/conflict-1", "polecat/c"} {
+		if !byBranch[b].Success || byBranch[b].MergeCommit == "" {
+			t.Errorf("%s = %+v, want merged", b, byBranch[b])
+		}
+	}
+	if r := byBranch["polecat/bad"]; r.Success || !r.TestsFailed {
+		t.Errorf("polecat/bad = %+v, want tests failed", r)
+	}
+	if r := byBranch["polecat/conflict-2"]; r.Success || !r.Conflict {
+		t.Errorf("polecat/conflict-2 = %+v, want conflict", r)
+	}
+
+	// origin/main has the passing MRs and not the culprit
+	out, err := exec.Command("git", "--git-dir", origin, "ls-tree", "--name-only", "main").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(out); got != "a.txt\nc.txt\nshared.txt\n" {
+		t.Errorf("origin/main files = %q", got)
+	}
+}
@@ -0,0 +1,217 @@
+// Package review records code reviews of merge requests.
+//
+// When a rig enables the review stage, the Refinery starts a reviewer agent
+// for each MR before merging. The reviewer reads the diff and records
+// findings and a verdict with `gt review`; the Refinery then reads the review
+// back, records the verdict on the MR bead, and either merges or sends the
+// findings to the polecat.
+//
+// Reviews live in <town>/.runtime/reviews/<mr-id>.json while in progress.
+// The MR bead holds the durable verdict.
+package review
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Verdicts.
+const (
+	VerdictApproved         = "approved"
+	VerdictChangesRequested = "changes_requested"
+)
+
+// Finding severities.
+const (
+	// SeverityBlocker findings must be fixed before the MR can merge.
+	SeverityBlocker = "blocker"
+
+	// SeveritySuggestion findings are advisory.
+	SeveritySuggestion = "suggestion"
+)
+
+// ErrNotFound indicates no review exists for the MR.
+var ErrNotFound = errors.New("review not found")
+
+// ErrBlockersOutstanding indicates an approval was attempted with blocker findings.
+var ErrBlockersOutstanding = errors.New("cannot approve with blocker findings")
+
+// Finding is one review comment anchored to a file (and optionally a line).
+type Finding struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// Location returns "file:line" (or just "file" without a line).
+func (f Finding) Location() string {
+	if f.Line > 0 {
+		return fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	return f.File
+}
+
+// Review is the state of one MR's review.
+type Review struct {
+	MRID      string    `json:"mr_id"`
+	Rig       string    `json:"rig"`
+	Branch    string    `json:"branch"`
+	Target    string    `json:"target"`
+	Reviewer  string    `json:"reviewer,omitempty"`
+	Verdict   string    `json:"verdict,omitempty"`
+	Summary   string    `json:"summary,omitempty"`
+	Findings  []Finding `json:"findings,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Blockers returns the findings that block the merge.
+func (r *Review) Blockers() []Finding {
+	var blockers []Finding
+	for _, f := range r.Findings {
+		if f.Severity == SeverityBlocker {
+			blockers = append(blockers, f)
+		}
+	}
+	return blockers
+}
+
+// Dir returns the review directory for a town.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "reviews")
+}
+
+// Path returns the review file path for an MR.
+func Path(townRoot, mrID string) string {
+	return filepath.Join(Dir(townRoot), strings.ReplaceAll(mrID, "/", "_")+".json")
+}
+
+// Start begins a fresh review, discarding any previous review of the MR.
+func Start(townRoot string, r Review) (*Review, error) {
+	now := time.Now().UTC()
+	r.Reviewer, r.Verdict, r.Summary, r.Findings = "", "", "", nil
+	r.StartedAt = now
+	r.UpdatedAt = now
+	if err := save(townRoot, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Load reads the review for an MR.
+func Load(townRoot, mrID string) (*Review, error) {
+	data, err := os.ReadFile(Path(townRoot, mrID)) //nolint:gosec // G304: path is built from the MR ID
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, mrID)
+		}
+		return nil, err
+	}
+	var r Review
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing review %s: %w", mrID, err)
+	}
+	return &r, nil
+}
+
+// Annotate adds a finding to an in-progress review.
+func Annotate(townRoot, mrID, reviewer string, f Finding) error {
+	r, err := Load(townRoot, mrID)
+	if err != nil {
+		return err
+	}
+	if f.Severity == "" {
+		f.Severity = SeveritySuggestion
+	}
+	r.Reviewer = reviewer
+	r.Findings = append(r.Findings, f)
+	r.UpdatedAt = time.Now().UTC()
+	return save(townRoot, r)
+}
+
+// SetVerdict records the reviewer's verdict. Approving a review that still
+// has blocker findings fails with ErrBlockersOutstanding.
+func SetVerdict(townRoot, mrID, reviewer, verdict, summary string) error {
+	r, err := Load(townRoot, mrID)
+	if err != nil {
+		return err
+	}
+	switch verdict {
+	case VerdictApproved:
+		if n := len(r.Blockers()); n > 0 {
+			return fmt.Errorf("%w (%d recorded)", ErrBlockersOutstanding, n)
+		}
+	case VerdictChangesRequested:
+	default:
+		return fmt.Errorf("invalid verdict %q", verdict)
+	}
+	r.Reviewer = reviewer
+	r.Verdict = verdict
+	r.Summary = summary
+	r.UpdatedAt = time.Now().UTC()
+	return save(townRoot, r)
+}
+
+// Remove deletes the review for an MR. Removing a missing review is not an error.
+func Remove(townRoot, mrID string) error {
+	if err := os.Remove(Path(townRoot, mrID)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// ParseLocation splits "file:line" into its parts. The line is optional.
+func ParseLocation(loc string) (string, int, error) {
+	file, lineStr, found := strings.Cut(loc, ":")
+	if file == "" {
+		return "", 0, fmt.Errorf("invalid location %q: missing file", loc)
+	}
+	if !found {
+		return file, 0, nil
+	}
+	line, err := strconv.Atoi(lineStr)
+	if err != nil || line < 1 {
+		return "", 0, fmt.Errorf("invalid location %q: line must be a positive number", loc)
+	}
+	return file, line, nil
+}
+
+// FormatReport formats a review requesting changes as instructions for the
+// polecat. The report is sent in MERGE_FAILED and relayed to the polecat.
+func FormatReport(r *Review) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Code review requested changes for %s.\n", r.Branch)
+	b.WriteString("Address the findings below, commit, and resubmit with 'gt done'.\n")
+	if r.Summary != "" {
+		fmt.Fprintf(&b, "\n%s\n", r.Summary)
+	}
+	for _, severity := range []string{SeverityBlocker, SeveritySuggestion} {
+		var lines []string
+		for _, f := range r.Findings {
+			if f.Severity == severity {
+				lines = append(lines, fmt.Sprintf("- %s: %s", f.Location(), f.Message))
+			}
+		}
+		if len(lines) > 0 {
+			fmt.Fprintf(&b, "\n## %ss\n%s\n", severity, strings.Join(lines, "\n"))
+		}
+	}
+	return b.String()
+}
+
+// save writes a review atomically.
+func save(townRoot string, r *Review) error {
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating review dir: %w", err)
+	}
+	return util.AtomicWriteJSON(Path(townRoot, r.MRID), r)
+}
@@ -0,0 +1,91 @@
+package review
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReviewLifecycle(t *testing.T) {
+	townRoot := t.TempDir()
+
+	if _, err := Load(townRoot, "gt-mr1"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Load before start error = %v, want ErrNotFound", err)
+	}
+	if err := Annotate(townRoot, "gt-mr1", "gastown/reviewer", Finding{File: "a.go"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Annotate before start error = %v, want ErrNotFound", err)
+	}
+
+	if _, err := Start(townRoot, Review{MRID: "gt-mr1", Rig: "gastown", Branch: "polecat/Nux/gt-abc", Target: "main"}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := Annotate(townRoot, "gt-mr1", "gastown/reviewer", Finding{File: "a.go", Line: 12, Severity: SeverityBlocker, Message: "nil deref"}); err != nil {
+		t.Fatalf("Annotate: %v", err)
+	}
+	if err := Annotate(townRoot, "gt-mr1", "gastown/reviewer", Finding{File: "b.go", Message: "rename"}); err != nil {
+		t.Fatalf("Annotate: %v", err)
+	}
+
+	if err := SetVerdict(townRoot, "gt-mr1", "gastown/reviewer", VerdictApproved, ""); !errors.Is(err, ErrBlockersOutstanding) {
+		t.Errorf("approve with blockers error = %v, want ErrBlockersOutstanding", err)
+	}
+	if err := SetVerdict(townRoot, "gt-mr1", "gastown/reviewer", VerdictChangesRequested, "Fix the nil deref."); err != nil {
+		t.Fatalf("SetVerdict: %v", err)
+	}
+
+	r, err := Load(townRoot, "gt-mr1")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if r.Verdict != VerdictChangesRequested || len(r.Findings) != 2 || r.Findings[1].Severity != SeveritySuggestion {
+		t.Errorf("review = %+v", r)
+	}
+
+	report := FormatReport(r)
+	for _, want := range []string{"polecat/Nux/gt-abc", "Fix the nil deref.", "## blockers", "- a.go:12: nil deref", "## suggestions", "- b.go: rename", "gt done"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+
+	// A new attempt starts from a clean slate
+	r, err = Start(townRoot, Review{MRID: "gt-mr1", Branch: "polecat/Nux/gt-abc", Target: "main"})
+	if err != nil {
+		t.Fatalf("Start again: %v", err)
+	}
+	if r.Verdict != "" || len(r.Findings) != 0 {
+		t.Errorf("restarted review = %+v, want empty", r)
+	}
+
+	if err := Remove(townRoot, "gt-mr1"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := Remove(townRoot, "gt-mr1"); err != nil {
+		t.Errorf("removing a missing review should not error: %v", err)
+	}
+}
+
+func TestParseLocation(t *testing.T) {
+	tests := []struct {
+		loc     string
+		file    string
+		line    int
+		wantErr bool
+	}{
+		{"internal/cmd/done.go:42", "internal/cmd/done.go", 42, false},
+		{"README.md", "README.md", 0, false},
+		{"a.go:0", "", 0, true},
+		{"a.go:x", "", 0, true},
+		{":3", "", 0, true},
+	}
+	for _, tt := range tests {
+		file, line, err := ParseLocation(tt.loc)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLocation(%q) error = %v, wantErr %v", tt.loc, err, tt.wantErr)
+			continue
+		}
+		if file != tt.file || line != tt.line {
+			t.Errorf("ParseLocation(%q) = %q, %d; want %q, %d", tt.loc, file, line, tt.file, tt.line)
+		}
+	}
+}
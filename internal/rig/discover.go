@@ -0,0 +1,156 @@
+package rig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// townDirs are town-level directories that are never rigs.
+var townDirs = map[string]bool{
+	"mayor":    true,
+	"deacon":   true,
+	"daemon":   true,
+	"settings": true,
+	"plugins":  true,
+	"docs":     true,
+	"logs":     true,
+}
+
+// workspaceMarkers are rig subdirectories that, alongside .beads/, identify a
+// rig layout created by gt rig add.
+var workspaceMarkers = []string{"crew", "polecats", "witness", "refinery/rig", "mayor/rig"}
+
+// Discovered is a rig found on disk that is not in the registry.
+type Discovered struct {
+	Name    string
+	Path    string
+	Entry   config.RigEntry
+	Markers []string // layout markers that identified the directory as a rig
+}
+
+// Discover scans the town root for rig layouts that are not registered, e.g.
+// rigs added by hand or synced from another machine. A directory is a rig if
+// it has a rig config.json, or a .beads/ directory plus a rig workspace
+// (crew/, polecats/, witness/, refinery/rig, mayor/rig). Results are sorted
+// by name.
+func (m *Manager) Discover() ([]Discovered, error) {
+	entries, err := os.ReadDir(m.townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("reading town root: %w", err)
+	}
+
+	var found []Discovered
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() || townDirs[name] || strings.HasPrefix(name, ".") || m.RigExists(name) {
+			continue
+		}
+		// Names with agent ID delimiters can't be registered (see AddRig)
+		if strings.ContainsAny(name, "-. ") {
+			continue
+		}
+		rigPath := filepath.Join(m.townRoot, name)
+		markers := rigLayoutMarkers(rigPath)
+		if markers == nil {
+			continue
+		}
+		found = append(found, Discovered{
+			Name:    name,
+			Path:    rigPath,
+			Entry:   discoveredEntry(rigPath),
+			Markers: markers,
+		})
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].Name < found[j].Name })
+	return found, nil
+}
+
+// Register adds a discovered rig to the registry. The caller saves the
+// registry with config.SaveRigsConfig.
+func (m *Manager) Register(d Discovered) error {
+	if m.RigExists(d.Name) {
+		return ErrRigExists
+	}
+	if m.config.Rigs == nil {
+		m.config.Rigs = make(map[string]config.RigEntry)
+	}
+	m.config.Rigs[d.Name] = d.Entry
+	return nil
+}
+
+// rigLayoutMarkers returns the markers identifying rigPath as a rig,
+// or nil if it doesn't look like one.
+func rigLayoutMarkers(rigPath string) []string {
+	var markers []string
+	cfg, err := LoadRigConfig(rigPath)
+	hasConfig := err == nil && cfg.Type == "rig"
+	if hasConfig {
+		markers = append(markers, "config.json")
+	}
+	hasBeads := isDir(filepath.Join(rigPath, ".beads"))
+	if hasBeads {
+		markers = append(markers, ".beads/")
+	}
+	hasWorkspace := false
+	for _, w := range workspaceMarkers {
+		if isDir(filepath.Join(rigPath, w)) {
+			markers = append(markers, w+"/")
+			hasWorkspace = true
+		}
+	}
+	if !hasConfig && !(hasBeads && hasWorkspace) {
+		return nil
+	}
+	return markers
+}
+
+// discoveredEntry builds a registry entry from what is on disk: the rig
+// config if present, otherwise the origin of a rig clone and the beads prefix.
+func discoveredEntry(rigPath string) config.RigEntry {
+	entry := config.RigEntry{AddedAt: time.Now()}
+
+	if cfg, err := LoadRigConfig(rigPath); err == nil {
+		entry.GitURL = cfg.GitURL
+		entry.LocalRepo = cfg.LocalRepo
+		if cfg.Beads != nil && cfg.Beads.Prefix != "" {
+			entry.BeadsConfig = &config.BeadsConfig{Prefix: cfg.Beads.Prefix}
+		}
+	}
+
+	if entry.GitURL == "" {
+		for _, clone := range []string{"mayor/rig", "refinery/rig"} {
+			clonePath := filepath.Join(rigPath, clone)
+			if !isDir(clonePath) {
+				continue
+			}
+			if url, err := git.NewGit(clonePath).RemoteURL("origin"); err == nil && url != "" {
+				entry.GitURL = strings.TrimSpace(url)
+				break
+			}
+		}
+	}
+
+	if entry.BeadsConfig == nil {
+		for _, dir := range []string{".beads", "mayor/rig/.beads"} {
+			if prefix := detectBeadsPrefixFromConfig(filepath.Join(rigPath, dir, "config.yaml")); prefix != "" {
+				entry.BeadsConfig = &config.BeadsConfig{Prefix: prefix}
+				break
+			}
+		}
+	}
+
+	return entry
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
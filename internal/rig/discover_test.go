@@ -0,0 +1,69 @@
+package rig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func mkdirs(t *testing.T, root string, dirs ...string) {
+	t.Helper()
+	for _, d := range dirs {
+		if err := os.MkdirAll(filepath.Join(root, d), 0755); err != nil {
+			t.Fatalf("mkdir %s: %v", d, err)
+		}
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	root, rigsConfig := setupTestTown(t)
+	rigsConfig.Rigs["known"] = config.RigEntry{}
+	mkdirs(t, root,
+		"known/.beads", "known/crew",
+		"handmade/.beads", "handmade/crew/max", // .beads + workspace
+		"synced",                      // rig config only
+		"onlybeads/.beads",            // no workspace: not a rig
+		"mayor/rig", ".beads", "docs", // town-level
+		"bad-name/.beads", "bad-name/polecats", // unregistrable name
+	)
+	if err := os.WriteFile(filepath.Join(root, "handmade", ".beads", "config.yaml"), []byte("issue-prefix: hm\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "synced", "config.json"),
+		[]byte(`{"type":"rig","version":1,"name":"synced","git_url":"https://example.com/synced.git","beads":{"prefix":"sy"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewManager(root, rigsConfig, git.NewGit(root))
+	found, err := manager.Discover()
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	if len(found) != 2 || found[0].Name != "handmade" || found[1].Name != "synced" {
+		t.Fatalf("Discover() = %+v, want handmade and synced", found)
+	}
+	if found[0].Entry.BeadsConfig == nil || found[0].Entry.BeadsConfig.Prefix != "hm" {
+		t.Errorf("handmade beads config = %+v, want prefix hm", found[0].Entry.BeadsConfig)
+	}
+	if found[1].Entry.GitURL != "https://example.com/synced.git" || found[1].Entry.BeadsConfig.Prefix != "sy" {
+		t.Errorf("synced entry = %+v", found[1].Entry)
+	}
+
+	for _, d := range found {
+		if err := manager.Register(d); err != nil {
+			t.Fatalf("Register(%s): %v", d.Name, err)
+		}
+	}
+	if !manager.RigExists("handmade") || !manager.RigExists("synced") {
+		t.Error("discovered rigs not registered")
+	}
+	if err := manager.Register(found[0]); err != ErrRigExists {
+		t.Errorf("second Register error = %v, want ErrRigExists", err)
+	}
+	if again, _ := manager.Discover(); len(again) != 0 {
+		t.Errorf("Discover after register = %+v, want none", again)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/steveyegge/gastown/internal/claude"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/forge"
 	"github.com/steveyegge/gastown/internal/git"
 )
 
@@ -34,6 +35,10 @@ type RigConfig struct {
 	DefaultBranch string       `json:"default_branch,omitempty"` // main, master, etc.
 	CreatedAt     time.Time    `json:"created_at"`               // when rig was created
 	Beads         *BeadsConfig `json:"beads,omitempty"`
+
+	// Clone limits the history and objects fetched by the rig's clones
+	// (bare repo, mayor, crew). Nil means full clones.
+	Clone *git.CloneOptions `json:"clone,omitempty"`
 }
 
 // BeadsConfig represents beads configuration for the rig.
@@ -168,6 +173,10 @@ type AddRigOptions struct {
 	BeadsPrefix   string // Beads issue prefix (defaults to derived from name)
 	LocalRepo     string // Optional local repo for reference clones
 	DefaultBranch string // Default branch (defaults to auto-detected from remote)
+
+	// Clone makes shallow, single-branch, or partial clones for very large
+	// repositories. It is saved in the rig config for later clones.
+	Clone git.CloneOptions
 }
 
 func resolveLocalRepo(path, gitURL string) (string, string) {
@@ -271,6 +280,14 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 			Prefix: opts.BeadsPrefix,
 		},
 	}
+	if opts.Clone.SingleBranch && opts.Clone.Branch == "" {
+		// Fetch the configured branch, not the remote's HEAD
+		opts.Clone.Branch = opts.DefaultBranch
+	}
+	if !opts.Clone.IsZero() {
+		cloneOpts := opts.Clone
+		rigConfig.Clone = &cloneOpts
+	}
 	if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
 		return nil, fmt.Errorf("saving rig config: %w", err)
 	}
@@ -279,17 +296,18 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 	// This allows refinery to see polecat branches without pushing to remote.
 	// Mayor remains a separate clone (doesn't need branch visibility).
 	fmt.Printf("  Cloning repository (this may take a moment)...\n")
+	cloner := m.cloneGit(opts.GitURL, opts.Clone)
 	bareRepoPath := filepath.Join(rigPath, ".repo.git")
 	if localRepo != "" {
-		if err := m.git.CloneBareWithReference(opts.GitURL, bareRepoPath, localRepo); err != nil {
+		if err := cloner.CloneBareWithReference(opts.GitURL, bareRepoPath, localRepo); err != nil {
 			fmt.Printf("  Warning: could not use local repo reference: %v\n", err)
 			_ = os.RemoveAll(bareRepoPath)
-			if err := m.git.CloneBare(opts.GitURL, bareRepoPath); err != nil {
+			if err := cloner.CloneBare(opts.GitURL, bareRepoPath); err != nil {
 				return nil, fmt.Errorf("creating bare repo: %w", err)
 			}
 		}
 	} else {
-		if err := m.git.CloneBare(opts.GitURL, bareRepoPath); err != nil {
+		if err := cloner.CloneBare(opts.GitURL, bareRepoPath); err != nil {
 			return nil, fmt.Errorf("creating bare repo: %w", err)
 		}
 	}
@@ -322,15 +340,15 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		return nil, fmt.Errorf("creating mayor dir: %w", err)
 	}
 	if localRepo != "" {
-		if err := m.git.CloneWithReference(opts.GitURL, mayorRigPath, localRepo); err != nil {
+		if err := cloner.CloneWithReference(opts.GitURL, mayorRigPath, localRepo); err != nil {
 			fmt.Printf("  Warning: could not use local repo reference: %v\n", err)
 			_ = os.RemoveAll(mayorRigPath)
-			if err := m.git.Clone(opts.GitURL, mayorRigPath); err != nil {
+			if err := cloner.Clone(opts.GitURL, mayorRigPath); err != nil {
 				return nil, fmt.Errorf("cloning for mayor: %w", err)
 			}
 		}
 	} else {
-		if err := m.git.Clone(opts.GitURL, mayorRigPath); err != nil {
+		if err := cloner.Clone(opts.GitURL, mayorRigPath); err != nil {
 			return nil, fmt.Errorf("cloning for mayor: %w", err)
 		}
 	}
@@ -340,6 +358,11 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 	if err := mayorGit.Checkout(defaultBranch); err != nil {
 		return nil, fmt.Errorf("checking out default branch for mayor: %w", err)
 	}
+	if !opts.Clone.NoSubmodules {
+		if err := mayorGit.UpdateSubmodules(); err != nil {
+			return nil, fmt.Errorf("updating submodules for mayor: %w", err)
+		}
+	}
 	fmt.Printf("   ✓ Created mayor clone\n")
 
 	// Check if source repo has tracked .beads/ directory.
@@ -413,7 +436,8 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 	if err := os.MkdirAll(filepath.Dir(refineryRigPath), 0755); err != nil {
 		return nil, fmt.Errorf("creating refinery dir: %w", err)
 	}
-	if err := bareGit.WorktreeAddExisting(refineryRigPath, defaultBranch); err != nil {
+	bareGit.SetCloneOptions(opts.Clone)
+	if err := bareGit.AddWorktree(refineryRigPath, git.AddWorktreeOptions{Branch: defaultBranch}); err != nil {
 		return nil, fmt.Errorf("creating refinery worktree: %w", err)
 	}
 	fmt.Printf("   ✓ Created refinery worktree\n")
@@ -536,6 +560,22 @@ Use crew for your own workspace. Polecats are for batch work dispatch.
 	return m.loadRig(opts.Name, m.config.Rigs[opts.Name])
 }
 
+// cloneGit returns the Git used to clone a rig's repository. If the URL is on
+// a known forge and its token is in the environment (e.g., GITLAB_TOKEN), the
+// clones are configured with a credential helper that supplies it.
+func (m *Manager) cloneGit(gitURL string, opts git.CloneOptions) *git.Git {
+	g := git.NewGit(m.git.WorkDir())
+	g.SetCloneOptions(opts)
+	f, err := forge.Resolve(nil, gitURL)
+	if err != nil {
+		return g
+	}
+	if helper := f.CredentialHelper(); helper != "" {
+		g.SetCredentialHelper(helper)
+	}
+	return g
+}
+
 // saveRigConfig writes the rig configuration to config.json.
 func (m *Manager) saveRigConfig(rigPath string, cfg *RigConfig) error {
 	configPath := filepath.Join(rigPath, "config.json")
@@ -3,6 +3,8 @@ package rig
 
 import (
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/forge"
+	"github.com/steveyegge/gastown/internal/git"
 )
 
 // Rig represents a managed repository in the workspace.
@@ -92,3 +94,51 @@ func (r *Rig) DefaultBranch() string {
 	}
 	return cfg.DefaultBranch
 }
+
+// SparsePaths returns the repo directories the rig's polecat worktrees and
+// crew clones check out (settings/config.json sparse_checkout). Returns nil
+// (the full repo) if none are configured or settings cannot be loaded.
+func (r *Rig) SparsePaths() []string {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+	if err != nil {
+		return nil
+	}
+	return settings.SparseCheckout
+}
+
+// ProtectedBranches returns the branch patterns agents may not push to in
+// this rig: settings/config.json protected_branches (or
+// git.DefaultProtectedBranches) plus the rig's default branch.
+func (r *Rig) ProtectedBranches() []string {
+	patterns := git.DefaultProtectedBranches
+	if settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path)); err == nil && len(settings.ProtectedBranches) > 0 {
+		patterns = settings.ProtectedBranches
+	}
+	return append(append([]string{}, patterns...), r.DefaultBranch())
+}
+
+// CredentialHelper returns the git credential helper that supplies the rig's
+// forge token over HTTPS: the forge.token_env secret (or the forge's default,
+// e.g. GITHUB_TOKEN) from the environment or keyring. Returns "" if the rig's
+// forge is unknown or has no token.
+func (r *Rig) CredentialHelper() string {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+	if err != nil {
+		settings = nil
+	}
+	f, err := forge.Resolve(settings, r.GitURL)
+	if err != nil {
+		return ""
+	}
+	return f.CredentialHelper()
+}
+
+// CloneOptions returns the shallow/partial clone options the rig was added
+// with. Returns the zero value (full clones) if none are configured.
+func (r *Rig) CloneOptions() git.CloneOptions {
+	cfg, err := LoadRigConfig(r.Path)
+	if err != nil || cfg.Clone == nil {
+		return git.CloneOptions{}
+	}
+	return *cfg.Clone
+}
@@ -1,7 +1,10 @@
 package rig
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
 )
 
 func TestBeadsPath_AlwaysReturnsRigRoot(t *testing.T) {
@@ -144,3 +147,29 @@ func TestDefaultBranch_FallsBackToMain(t *testing.T) {
 		t.Errorf("DefaultBranch() = %q, want %q", got, "main")
 	}
 }
+
+func TestCredentialHelper(t *testing.T) {
+	rigPath := t.TempDir()
+	rig := Rig{Name: "testrig", Path: rigPath, GitURL: "https://github.com/example/repo.git"}
+	settings := &config.RigSettings{
+		Type:    "rig-settings",
+		Version: config.CurrentRigSettingsVersion,
+		Forge:   &config.ForgeConfig{TokenEnv: "GT_TEST_RIG_TOKEN"},
+	}
+	if err := config.SaveRigSettings(config.RigSettingsPath(rigPath), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := rig.CredentialHelper(); got != "" {
+		t.Errorf("CredentialHelper() without a token = %q, want empty", got)
+	}
+
+	t.Setenv("GT_TEST_RIG_TOKEN", "ghp_secret")
+	got := rig.CredentialHelper()
+	if !strings.Contains(got, "GT_TEST_RIG_TOKEN") {
+		t.Errorf("CredentialHelper() = %q, want a helper reading the rig's token_env", got)
+	}
+	if strings.Contains(got, "ghp_secret") {
+		t.Error("CredentialHelper() must not embed the token")
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/claude"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/logging"
 	"github.com/steveyegge/gastown/internal/opencode"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -76,7 +77,9 @@ func StartupFallbackCommands(role string, rc *config.RuntimeConfig) []string {
 // RunStartupFallback sends the startup fallback commands via tmux.
 func RunStartupFallback(t *tmux.Tmux, sessionID, role string, rc *config.RuntimeConfig) error {
 	commands := StartupFallbackCommands(role, rc)
+	log := logging.WithSession(logging.For(logging.ComponentRuntime), sessionID)
 	for _, cmd := range commands {
+		log.Debug("sending startup fallback", "role", role, "command", cmd)
 		if err := t.NudgeSession(sessionID, cmd); err != nil {
 			return err
 		}
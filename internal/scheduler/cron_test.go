@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"1,,2 * * * *",
+		"@sometimes",
+	}
+	for _, expr := range tests {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) expected error, got nil", expr)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// Wednesday, 2026-01-14 10:30:15 UTC
+	base := time.Date(2026, 1, 14, 10, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 1, 14, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 1, 14, 10, 45, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2026, 1, 14, 11, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 1, 14, 11, 0, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2026, 1, 15, 2, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 3 * * 0", time.Date(2026, 1, 18, 3, 0, 0, 0, time.UTC)}, // next Sunday
+		{"0 3 * * 7", time.Date(2026, 1, 18, 3, 0, 0, 0, time.UTC)}, // 7 = Sunday
+		{"@weekly", time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC)}, // strictly after
+		{"0 0 1 * *", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 29 2 *", time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC)}, // leap day
+		{"5,35 10 * * *", time.Date(2026, 1, 14, 10, 35, 0, 0, time.UTC)},
+		{"10/20 * * * *", time.Date(2026, 1, 14, 10, 50, 0, 0, time.UTC)},
+		// dom and dow both restricted: either matches (15th or Friday)
+		{"0 0 15 * 5", time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+			}
+			got := s.Next(base)
+			if !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", base, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleNext_NeverMatches(t *testing.T) {
+	s, err := ParseCron("0 0 31 2 *")
+	if err != nil {
+		t.Fatalf("ParseCron: %v", err)
+	}
+	if got := s.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Errorf("Next() = %v, want zero time", got)
+	}
+}
@@ -0,0 +1,443 @@
+package scheduler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// ErrJobRunning indicates a previous run of the job has not finished yet.
+var ErrJobRunning = errors.New("job already running")
+
+// ErrUnknownJob indicates the job name is not defined in the schedules config.
+var ErrUnknownJob = errors.New("unknown job")
+
+// Run status values recorded in history.
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped" // previous run still in progress
+)
+
+// tickInterval is how often the scheduler checks for due jobs.
+// Cron has minute resolution, so anything under a minute is sufficient.
+const tickInterval = 20 * time.Second
+
+// maxOutputBytes caps the command output kept in a history record.
+const maxOutputBytes = 2000
+
+// Runner executes a single job run and returns its combined output.
+type Runner func(ctx context.Context, townRoot string, job config.ScheduledJob) (string, error)
+
+// RunRecord is one entry in the scheduler run history.
+type RunRecord struct {
+	Job        string    `json:"job"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	Output     string    `json:"output,omitempty"`
+	Manual     bool      `json:"manual,omitempty"` // triggered by gt schedule run
+}
+
+// Duration returns how long the run took.
+func (r *RunRecord) Duration() time.Duration {
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// Job is a scheduled job with its parsed cron expression.
+type Job struct {
+	config.ScheduledJob
+	Schedule *Schedule
+}
+
+// Scheduler evaluates cron schedules and runs due jobs.
+// Each job is protected against overlapping runs, both within the process
+// and across processes (daemon vs. manual gt schedule run), via a file lock.
+type Scheduler struct {
+	townRoot string
+	jobs     []*Job
+	next     map[string]time.Time
+	runner   Runner
+	logger   func(format string, args ...interface{})
+	now      func() time.Time
+
+	mu      sync.Mutex
+	running map[string]bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a scheduler for the given jobs config.
+// Disabled jobs are skipped. Returns an error if any cron expression is invalid.
+func New(townRoot string, cfg *config.SchedulesConfig, logger func(format string, args ...interface{})) (*Scheduler, error) {
+	jobs, err := ParseJobs(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if logger == nil {
+		logger = func(string, ...interface{}) {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		townRoot: townRoot,
+		jobs:     jobs,
+		next:     make(map[string]time.Time),
+		runner:   DefaultRunner,
+		logger:   logger,
+		now:      time.Now,
+		running:  make(map[string]bool),
+		ctx:      ctx,
+		cancel:   cancel,
+	}, nil
+}
+
+// ParseJobs parses the cron expressions of all enabled jobs in the config.
+func ParseJobs(cfg *config.SchedulesConfig) ([]*Job, error) {
+	var jobs []*Job
+	if cfg == nil {
+		return jobs, nil
+	}
+	for _, jc := range cfg.Jobs {
+		if jc.Disabled {
+			continue
+		}
+		sched, err := ParseCron(jc.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("job %q: %w", jc.Name, err)
+		}
+		jobs = append(jobs, &Job{ScheduledJob: jc, Schedule: sched})
+	}
+	return jobs, nil
+}
+
+// SetRunner replaces the job runner. Used for testing.
+func (s *Scheduler) SetRunner(r Runner) {
+	s.runner = r
+}
+
+// Jobs returns the enabled jobs.
+func (s *Scheduler) Jobs() []*Job {
+	return s.jobs
+}
+
+// Start begins the scheduler goroutine.
+// Missed runs (while the daemon was down) are not caught up;
+// each job's first run is its next cron match after start.
+func (s *Scheduler) Start() error {
+	now := s.now()
+	s.mu.Lock()
+	for _, job := range s.jobs {
+		s.next[job.Name] = job.Schedule.Next(now)
+	}
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go s.run()
+	return nil
+}
+
+// Stop stops the scheduler and waits for in-flight runs to finish.
+func (s *Scheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// run is the main scheduler loop.
+func (s *Scheduler) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.Tick(s.now())
+		}
+	}
+}
+
+// Tick starts every job whose next run time is at or before now.
+// Jobs run in their own goroutines so a slow job doesn't delay the others.
+func (s *Scheduler) Tick(now time.Time) {
+	for _, job := range s.jobs {
+		s.mu.Lock()
+		next, ok := s.next[job.Name]
+		if !ok {
+			// Not started: schedule from now without running
+			s.next[job.Name] = job.Schedule.Next(now)
+			s.mu.Unlock()
+			continue
+		}
+		due := !next.IsZero() && !now.Before(next)
+		if due {
+			s.next[job.Name] = job.Schedule.Next(now)
+		}
+		s.mu.Unlock()
+
+		if !due {
+			continue
+		}
+
+		s.wg.Add(1)
+		go func(j *Job) {
+			defer s.wg.Done()
+			rec, err := s.runJob(s.ctx, j, false)
+			switch {
+			case errors.Is(err, ErrJobRunning):
+				s.logger("Scheduler: skipped %s (previous run still in progress)", j.Name)
+			case err != nil:
+				s.logger("Scheduler: %s failed: %v", j.Name, err)
+			default:
+				s.logger("Scheduler: %s completed in %v", j.Name, rec.Duration().Round(time.Second))
+			}
+		}(job)
+	}
+}
+
+// NextRun returns the next scheduled run time for a job, if the scheduler is started.
+func (s *Scheduler) NextRun(name string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.next[name]
+	return t, ok
+}
+
+// RunNow runs a job immediately and synchronously, regardless of its schedule.
+// Overlap protection still applies: returns ErrJobRunning if a run is in progress.
+func (s *Scheduler) RunNow(ctx context.Context, name string) (*RunRecord, error) {
+	for _, job := range s.jobs {
+		if job.Name == name {
+			return s.runJob(ctx, job, true)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownJob, name)
+}
+
+// runJob executes one run of a job with overlap protection and records it in history.
+func (s *Scheduler) runJob(ctx context.Context, job *Job, manual bool) (*RunRecord, error) {
+	rec := &RunRecord{
+		Job:       job.Name,
+		StartedAt: s.now(),
+		Manual:    manual,
+	}
+
+	// In-process overlap check
+	s.mu.Lock()
+	if s.running[job.Name] {
+		s.mu.Unlock()
+		return s.skip(rec)
+	}
+	s.running[job.Name] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, job.Name)
+		s.mu.Unlock()
+	}()
+
+	// Cross-process overlap check
+	if err := os.MkdirAll(stateDir(s.townRoot), 0755); err != nil {
+		return nil, fmt.Errorf("creating scheduler directory: %w", err)
+	}
+	lock := flock.New(lockPath(s.townRoot, job.Name))
+	locked, err := lock.TryLock()
+	if err != nil {
+		return nil, fmt.Errorf("acquiring job lock: %w", err)
+	}
+	if !locked {
+		return s.skip(rec)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	runCtx, cancel := context.WithTimeout(ctx, job.GetTimeout())
+	defer cancel()
+
+	output, runErr := s.runner(runCtx, s.townRoot, job.ScheduledJob)
+	rec.FinishedAt = s.now()
+	rec.Output = truncateOutput(output)
+	rec.Status = StatusSuccess
+	if runErr != nil {
+		rec.Status = StatusFailed
+		rec.Error = runErr.Error()
+	}
+
+	if err := AppendHistory(s.townRoot, rec); err != nil {
+		s.logger("Scheduler: warning: failed to record history for %s: %v", job.Name, err)
+	}
+	return rec, runErr
+}
+
+// skip records a skipped run and returns ErrJobRunning.
+func (s *Scheduler) skip(rec *RunRecord) (*RunRecord, error) {
+	rec.FinishedAt = rec.StartedAt
+	rec.Status = StatusSkipped
+	if err := AppendHistory(s.townRoot, rec); err != nil {
+		s.logger("Scheduler: warning: failed to record history for %s: %v", rec.Job, err)
+	}
+	return rec, ErrJobRunning
+}
+
+// JobCommand returns the argv that the default runner executes for a job.
+func JobCommand(job config.ScheduledJob) ([]string, error) {
+	switch job.Action {
+	case config.ScheduleActionNudge:
+		return []string{"gt", "nudge", job.Target, job.Message}, nil
+	case config.ScheduleActionMail:
+		return []string{"gt", "mail", "send", job.Target, "-s", job.Subject, "-m", job.Message}, nil
+	case config.ScheduleActionSling:
+		args := []string{"gt", "sling", job.Bead}
+		if job.Target != "" {
+			args = append(args, job.Target)
+		}
+		return args, nil
+	case config.ScheduleActionCommand:
+		return []string{"sh", "-c", job.Command}, nil
+	default:
+		return nil, fmt.Errorf("unknown action %q", job.Action)
+	}
+}
+
+// DefaultRunner runs a job by executing its command from the town root.
+func DefaultRunner(ctx context.Context, townRoot string, job config.ScheduledJob) (string, error) {
+	argv, err := JobCommand(job)
+	if err != nil {
+		return "", err
+	}
+
+	// Note: commands come from settings/schedules.json (trusted town config).
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...) //nolint:gosec // G204: job commands are from trusted town config
+	cmd.Dir = townRoot
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return out.String(), fmt.Errorf("timed out after %s", job.GetTimeout())
+		}
+		return out.String(), err
+	}
+	return out.String(), nil
+}
+
+// truncateOutput keeps the tail of long command output.
+func truncateOutput(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= maxOutputBytes {
+		return s
+	}
+	return "..." + s[len(s)-maxOutputBytes:]
+}
+
+// stateDir returns the directory holding scheduler history and locks.
+func stateDir(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "scheduler")
+}
+
+// lockPath returns the overlap lock file for a job.
+func lockPath(townRoot, name string) string {
+	safe := strings.NewReplacer("/", "_", string(filepath.Separator), "_").Replace(name)
+	return filepath.Join(stateDir(townRoot), safe+".lock")
+}
+
+// HistoryPath returns the path to the scheduler run history.
+func HistoryPath(townRoot string) string {
+	return filepath.Join(stateDir(townRoot), "history.jsonl")
+}
+
+// historyMu serializes history appends within the process.
+var historyMu sync.Mutex
+
+// AppendHistory appends a run record to the history file.
+func AppendHistory(townRoot string, rec *RunRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("marshaling run record: %w", err)
+	}
+	data = append(data, '\n')
+
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	if err := os.MkdirAll(stateDir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating scheduler directory: %w", err)
+	}
+	f, err := os.OpenFile(HistoryPath(townRoot), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: history is non-sensitive operational data
+	if err != nil {
+		return fmt.Errorf("opening history file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("writing run record: %w", err)
+	}
+	return nil
+}
+
+// LoadHistory returns recorded runs, oldest first.
+// If job is non-empty, only runs of that job are returned.
+// If limit > 0, only the most recent limit records are returned.
+func LoadHistory(townRoot, job string, limit int) ([]RunRecord, error) {
+	f, err := os.Open(HistoryPath(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening history file: %w", err)
+	}
+	defer f.Close()
+
+	var records []RunRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var rec RunRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue // Skip malformed lines
+		}
+		if job != "" && rec.Job != job {
+			continue
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading history file: %w", err)
+	}
+
+	if limit > 0 && len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+	return records, nil
+}
+
+// LastRuns returns the most recent run record for each job.
+func LastRuns(townRoot string) (map[string]RunRecord, error) {
+	records, err := LoadHistory(townRoot, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	last := make(map[string]RunRecord)
+	for _, rec := range records {
+		last[rec.Job] = rec
+	}
+	return last, nil
+}
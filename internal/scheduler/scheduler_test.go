@@ -0,0 +1,186 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func testConfig(jobs ...config.ScheduledJob) *config.SchedulesConfig {
+	cfg := config.NewSchedulesConfig()
+	cfg.Jobs = jobs
+	return cfg
+}
+
+func TestNew_InvalidCron(t *testing.T) {
+	cfg := testConfig(config.ScheduledJob{Name: "bad", Cron: "not a cron", Action: "command", Command: "true"})
+	if _, err := New(t.TempDir(), cfg, nil); err == nil {
+		t.Fatal("expected error for invalid cron expression")
+	}
+}
+
+func TestNew_SkipsDisabled(t *testing.T) {
+	cfg := testConfig(
+		config.ScheduledJob{Name: "on", Cron: "@hourly", Action: "command", Command: "true"},
+		config.ScheduledJob{Name: "off", Cron: "@hourly", Action: "command", Command: "true", Disabled: true},
+	)
+	s, err := New(t.TempDir(), cfg, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if len(s.Jobs()) != 1 || s.Jobs()[0].Name != "on" {
+		t.Errorf("Jobs() = %v, want only 'on'", s.Jobs())
+	}
+}
+
+func TestJobCommand(t *testing.T) {
+	tests := []struct {
+		job  config.ScheduledJob
+		want []string
+	}{
+		{
+			config.ScheduledJob{Action: "nudge", Target: "gastown/witness", Message: "triage"},
+			[]string{"gt", "nudge", "gastown/witness", "triage"},
+		},
+		{
+			config.ScheduledJob{Action: "mail", Target: "mayor/", Subject: "Weekly", Message: "cleanup"},
+			[]string{"gt", "mail", "send", "mayor/", "-s", "Weekly", "-m", "cleanup"},
+		},
+		{
+			config.ScheduledJob{Action: "sling", Bead: "mol-deps-update", Target: "gastown"},
+			[]string{"gt", "sling", "mol-deps-update", "gastown"},
+		},
+		{
+			config.ScheduledJob{Action: "command", Command: "gt doctor --fix"},
+			[]string{"sh", "-c", "gt doctor --fix"},
+		},
+	}
+	for _, tt := range tests {
+		got, err := JobCommand(tt.job)
+		if err != nil {
+			t.Fatalf("JobCommand(%+v): %v", tt.job, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("JobCommand(%+v) = %v, want %v", tt.job, got, tt.want)
+		}
+	}
+}
+
+func TestTick_RunsDueJobsAndRecordsHistory(t *testing.T) {
+	townRoot := t.TempDir()
+	cfg := testConfig(config.ScheduledJob{Name: "triage", Cron: "0 * * * *", Action: "command", Command: "true"})
+	s, err := New(townRoot, cfg, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	var runs int32
+	s.SetRunner(func(ctx context.Context, _ string, _ config.ScheduledJob) (string, error) {
+		atomic.AddInt32(&runs, 1)
+		return "ok", nil
+	})
+
+	start := time.Date(2026, 1, 14, 10, 30, 0, 0, time.UTC)
+	s.Tick(start) // initializes next run to 11:00
+	s.Tick(start.Add(10 * time.Minute))
+	s.wg.Wait()
+	if got := atomic.LoadInt32(&runs); got != 0 {
+		t.Fatalf("runs before due = %d, want 0", got)
+	}
+
+	s.Tick(time.Date(2026, 1, 14, 11, 0, 5, 0, time.UTC))
+	s.wg.Wait()
+	if got := atomic.LoadInt32(&runs); got != 1 {
+		t.Fatalf("runs after due = %d, want 1", got)
+	}
+
+	next, ok := s.NextRun("triage")
+	if !ok || !next.Equal(time.Date(2026, 1, 14, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("NextRun = %v, want 12:00", next)
+	}
+
+	history, err := LoadHistory(townRoot, "triage", 0)
+	if err != nil {
+		t.Fatalf("LoadHistory: %v", err)
+	}
+	if len(history) != 1 || history[0].Status != StatusSuccess || history[0].Output != "ok" {
+		t.Errorf("history = %+v, want one success record", history)
+	}
+}
+
+func TestRunNow_OverlapProtection(t *testing.T) {
+	townRoot := t.TempDir()
+	cfg := testConfig(config.ScheduledJob{Name: "cleanup", Cron: "@weekly", Action: "command", Command: "true"})
+	s, err := New(townRoot, cfg, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s.SetRunner(func(ctx context.Context, _ string, _ config.ScheduledJob) (string, error) {
+		close(started)
+		<-release
+		return "", nil
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.RunNow(context.Background(), "cleanup")
+		done <- err
+	}()
+	<-started
+
+	// Second scheduler instance simulates a concurrent manual run from another process
+	other, err := New(townRoot, cfg, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := other.RunNow(context.Background(), "cleanup"); !errors.Is(err, ErrJobRunning) {
+		t.Errorf("concurrent RunNow error = %v, want ErrJobRunning", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("RunNow: %v", err)
+	}
+
+	last, err := LastRuns(townRoot)
+	if err != nil {
+		t.Fatalf("LastRuns: %v", err)
+	}
+	if last["cleanup"].Status != StatusSuccess {
+		t.Errorf("last run status = %q, want %q", last["cleanup"].Status, StatusSuccess)
+	}
+
+	history, _ := LoadHistory(townRoot, "cleanup", 0)
+	if len(history) != 2 || history[0].Status != StatusSkipped {
+		t.Errorf("history = %+v, want skipped then success", history)
+	}
+}
+
+func TestRunNow_FailureRecorded(t *testing.T) {
+	townRoot := t.TempDir()
+	cfg := testConfig(config.ScheduledJob{Name: "fails", Cron: "@daily", Action: "command", Command: "exit 3"})
+	s, err := New(townRoot, cfg, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rec, err := s.RunNow(context.Background(), "fails")
+	if err == nil {
+		t.Fatal("expected error from failing command")
+	}
+	if rec.Status != StatusFailed || rec.Error == "" {
+		t.Errorf("record = %+v, want failed status with error", rec)
+	}
+
+	if _, err := s.RunNow(context.Background(), "missing"); !errors.Is(err, ErrUnknownJob) {
+		t.Errorf("RunNow(missing) error = %v, want ErrUnknownJob", err)
+	}
+}
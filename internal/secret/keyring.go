@@ -0,0 +1,187 @@
+package secret
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// keyring is a platform keyring reached through its command-line tool.
+// Secret values are always passed on stdin or through the environment, never
+// as arguments, so they do not show up in process listings.
+type keyring interface {
+	name() string
+	set(name, value string) error
+	get(name string) (string, error)
+	delete(name string) error
+}
+
+// platformKeyring returns the keyring for the running OS.
+func platformKeyring() keyring {
+	switch runtime.GOOS {
+	case "darwin":
+		return macKeychain{}
+	case "windows":
+		return winCredentials{}
+	default:
+		return secretService{}
+	}
+}
+
+// run executes a keyring tool and returns its stdout. A missing tool is
+// reported as ErrUnavailable.
+func run(stdin string, env []string, args ...string) (string, int, error) {
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return "", 0, fmt.Errorf("%w: %s not found on PATH", ErrUnavailable, args[0])
+	}
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec // G204: fixed keyring tools
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	if env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = exitErr.Error()
+		}
+		return stdout.String(), exitErr.ExitCode(), fmt.Errorf("%s: %s", args[0], msg)
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("%s: %w", args[0], err)
+	}
+	return stdout.String(), 0, nil
+}
+
+// macKeychain stores generic passwords in the login keychain with security(1).
+type macKeychain struct{}
+
+// macNotFound is security(1)'s exit status for a missing item.
+const macNotFound = 44
+
+func (macKeychain) name() string { return "macOS Keychain" }
+
+func (macKeychain) set(name, value string) error {
+	// Interactive mode reads the command from stdin, keeping the value out
+	// of the argument list.
+	line := fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+		shellQuote(Service), shellQuote(name), shellQuote(value))
+	_, _, err := run(line, nil, "security", "-i")
+	return err
+}
+
+func (macKeychain) get(name string) (string, error) {
+	out, code, err := run("", nil, "security", "find-generic-password", "-s", Service, "-a", name, "-w")
+	if code == macNotFound {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(out, "\n"), nil
+}
+
+func (macKeychain) delete(name string) error {
+	_, code, err := run("", nil, "security", "delete-generic-password", "-s", Service, "-a", name)
+	if code == macNotFound {
+		return ErrNotFound
+	}
+	return err
+}
+
+// secretService stores secrets through the freedesktop Secret Service
+// (GNOME Keyring, KWallet) with secret-tool(1).
+type secretService struct{}
+
+func (secretService) name() string { return "Secret Service (secret-tool)" }
+
+func (secretService) set(name, value string) error {
+	_, _, err := run(value, nil, "secret-tool", "store", "--label="+Service+" "+name,
+		"service", Service, "account", name)
+	return err
+}
+
+func (secretService) get(name string) (string, error) {
+	out, code, err := run("", nil, "secret-tool", "lookup", "service", Service, "account", name)
+	// lookup exits 1 with no output when nothing matches.
+	if code == 1 && out == "" {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	if out == "" {
+		return "", ErrNotFound
+	}
+	return strings.TrimSuffix(out, "\n"), nil
+}
+
+func (s secretService) delete(name string) error {
+	// clear succeeds even when nothing matches, so check first.
+	if _, err := s.get(name); err != nil {
+		return err
+	}
+	_, _, err := run("", nil, "secret-tool", "clear", "service", Service, "account", name)
+	return err
+}
+
+// winCredentials stores secrets in the Windows Credential Manager through the
+// WinRT PasswordVault. Names and values travel in the environment.
+type winCredentials struct{}
+
+// winNotFound is the exit status the scripts below use for a missing item.
+const winNotFound = 44
+
+const winVault = `$ErrorActionPreference = 'Stop'
+[void][Windows.Security.Credentials.PasswordVault,Windows.Security.Credentials,ContentType=WindowsRuntime]
+$v = New-Object Windows.Security.Credentials.PasswordVault
+`
+
+const winFind = `try { $c = $v.Retrieve($env:GT_SECRET_SERVICE, $env:GT_SECRET_NAME) } catch { exit 44 }
+`
+
+func (winCredentials) name() string { return "Windows Credential Manager" }
+
+func (winCredentials) powershell(script string, env ...string) (string, int, error) {
+	env = append(env, "GT_SECRET_SERVICE="+Service)
+	return run("", env, "powershell", "-NoProfile", "-NonInteractive", "-Command", winVault+script)
+}
+
+func (w winCredentials) set(name, value string) error {
+	_, _, err := w.powershell(`$v.Add((New-Object Windows.Security.Credentials.PasswordCredential($env:GT_SECRET_SERVICE, $env:GT_SECRET_NAME, $env:GT_SECRET_VALUE)))`,
+		"GT_SECRET_NAME="+name, "GT_SECRET_VALUE="+value)
+	return err
+}
+
+func (w winCredentials) get(name string) (string, error) {
+	out, code, err := w.powershell(winFind+`$c.RetrievePassword(); [Console]::Out.Write($c.Password)`,
+		"GT_SECRET_NAME="+name)
+	if code == winNotFound {
+		return "", ErrNotFound
+	}
+	return out, err
+}
+
+func (w winCredentials) delete(name string) error {
+	_, code, err := w.powershell(winFind+`$v.Remove($c)`, "GT_SECRET_NAME="+name)
+	if code == winNotFound {
+		return ErrNotFound
+	}
+	return err
+}
+
+// shellQuote single-quotes s for security(1)'s interactive command parser.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
@@ -0,0 +1,103 @@
+// Package secret stores API keys, forge tokens, and webhook secrets in the
+// OS keyring (macOS Keychain, Secret Service on Linux, Windows Credential
+// Manager) so they need not live in plaintext env files or configs on shared
+// build machines.
+//
+// Secrets are named like the environment variables that would otherwise hold
+// them (e.g., GITHUB_TOKEN). Settings that name a variable, such as
+// forge.token_env, resolve through Lookup: the environment wins, then the
+// keyring.
+//
+// Redact scrubs credentials from agent output (pane captures, logs, events)
+// using built-in patterns plus the town's [redact] patterns.
+package secret
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Service is the keyring service every gastown secret is stored under.
+const Service = "gastown"
+
+var (
+	// ErrNotFound indicates the keyring has no secret with the given name.
+	ErrNotFound = errors.New("secret not found")
+
+	// ErrUnavailable indicates no supported keyring is reachable on this host.
+	ErrUnavailable = errors.New("no OS keyring available")
+)
+
+// validName matches secret names: env-var style identifiers, plus '.' and '-'.
+var validName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// ValidateName reports whether name can be used as a secret name.
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q: use letters, digits, '_', '.', or '-'", name)
+	}
+	return nil
+}
+
+// Set stores value under name, replacing any existing secret.
+func Set(name, value string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if value == "" {
+		return errors.New("secret value is empty")
+	}
+	return platformKeyring().set(name, value)
+}
+
+// Get returns the secret stored under name.
+func Get(name string) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+	return platformKeyring().get(name)
+}
+
+// Delete removes the secret stored under name.
+func Delete(name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	return platformKeyring().delete(name)
+}
+
+// Lookup resolves a secret by name: the environment variable of that name if
+// set, otherwise the keyring. Returns "" if neither has it.
+func Lookup(name string) string {
+	if name == "" {
+		return ""
+	}
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	if ValidateName(name) != nil {
+		return ""
+	}
+	v, err := platformKeyring().get(name)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(v, "\r\n")
+}
+
+// InKeyring reports whether the keyring holds a secret under name.
+func InKeyring(name string) bool {
+	if ValidateName(name) != nil {
+		return false
+	}
+	v, err := platformKeyring().get(name)
+	return err == nil && v != ""
+}
+
+// Backend describes the keyring used on this platform (e.g., "secret-tool").
+func Backend() string {
+	return platformKeyring().name()
+}
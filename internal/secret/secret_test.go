@@ -0,0 +1,112 @@
+package secret
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// stubSecretTool installs a secret-tool stub that keeps secrets as files in
+// a temp dir, and returns that dir.
+func stubSecretTool(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS != "linux" {
+		t.Skip("secret-tool backend is linux-only")
+	}
+	binDir := t.TempDir()
+	store := t.TempDir()
+	script := `#!/bin/sh
+dir="` + store + `"
+case "$1" in
+  store)  cat > "$dir/$6" ;;
+  lookup) [ -f "$dir/$5" ] || exit 1; cat "$dir/$5" ;;
+  clear)  rm -f "$dir/$5" ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(binDir, "secret-tool"), []byte(script), 0755); err != nil {
+		t.Fatalf("write secret-tool stub: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return store
+}
+
+func TestSetGetDelete(t *testing.T) {
+	store := stubSecretTool(t)
+
+	if _, err := Get("GT_TEST_TOKEN"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get before Set = %v, want ErrNotFound", err)
+	}
+	if err := Set("GT_TEST_TOKEN", "s3cret"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if got, err := Get("GT_TEST_TOKEN"); err != nil || got != "s3cret" {
+		t.Errorf("Get = %q, %v; want s3cret", got, err)
+	}
+	if !InKeyring("GT_TEST_TOKEN") {
+		t.Error("InKeyring = false after Set")
+	}
+
+	if err := Delete("GT_TEST_TOKEN"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(store, "GT_TEST_TOKEN")); !os.IsNotExist(err) {
+		t.Errorf("secret still stored after Delete: %v", err)
+	}
+	if err := Delete("GT_TEST_TOKEN"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	stubSecretTool(t)
+	if err := Set("GT_TEST_TOKEN", "from-keyring"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("GT_TEST_TOKEN", "")
+	if got := Lookup("GT_TEST_TOKEN"); got != "from-keyring" {
+		t.Errorf("Lookup = %q, want keyring value", got)
+	}
+	t.Setenv("GT_TEST_TOKEN", "from-env")
+	if got := Lookup("GT_TEST_TOKEN"); got != "from-env" {
+		t.Errorf("Lookup = %q, want env to win", got)
+	}
+	if got := Lookup("GT_TEST_MISSING"); got != "" {
+		t.Errorf("Lookup missing = %q, want empty", got)
+	}
+}
+
+func TestUnavailable(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("secret-tool backend is linux-only")
+	}
+	t.Setenv("PATH", t.TempDir())
+	if _, err := Get("GT_TEST_TOKEN"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Get without secret-tool = %v, want ErrUnavailable", err)
+	}
+	if got := Lookup("GT_TEST_TOKEN"); got != "" {
+		t.Errorf("Lookup without keyring = %q, want empty", got)
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"GITHUB_TOKEN", "rig.webhook-secret"} {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("ValidateName(%q) = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "has space", "semi;colon", "$(x)"} {
+		if err := ValidateName(name); err == nil || !strings.Contains(err.Error(), "invalid secret name") {
+			t.Errorf("ValidateName(%q) = %v, want invalid", name, err)
+		}
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got := shellQuote(`it's`); got != `'it'"'"'s'` {
+		t.Errorf("shellQuote = %s", got)
+	}
+}
@@ -23,13 +23,20 @@ type AgentIdentity struct {
 	Role Role   // mayor, deacon, witness, refinery, crew, polecat
 	Rig  string // rig name (empty for mayor/deacon)
 	Name string // crew/polecat name (empty for mayor/deacon/witness/refinery)
+	Town string // town name from the naming scheme (empty if unknown)
 }
 
-// ParseSessionName parses a tmux session name into an AgentIdentity.
+// ParseSessionName parses a tmux session name into an AgentIdentity using the
+// current naming scheme. Sessions from other towns' schemes are rejected.
+func ParseSessionName(session string) (*AgentIdentity, error) {
+	return current.Parse(session)
+}
+
+// Parse parses a tmux session name into an AgentIdentity.
 //
-// Session name formats:
-//   - hq-mayor → Role: mayor (town-level, one per machine)
-//   - hq-deacon → Role: deacon (town-level, one per machine)
+// Session name formats (with the default prefixes):
+//   - hq-mayor → Role: mayor (town-level, one per town)
+//   - hq-deacon → Role: deacon (town-level, one per town)
 //   - gt-<rig>-witness → Role: witness, Rig: <rig>
 //   - gt-<rig>-refinery → Role: refinery, Rig: <rig>
 //   - gt-<rig>-crew-<name> → Role: crew, Rig: <rig>, Name: <name>
@@ -38,25 +45,34 @@ type AgentIdentity struct {
 // For polecat sessions without a crew marker, the last segment after the rig
 // is assumed to be the polecat name. This works for simple rig names but may
 // be ambiguous for rig names containing hyphens.
-func ParseSessionName(session string) (*AgentIdentity, error) {
+func (s Scheme) Parse(session string) (*AgentIdentity, error) {
+	id, err := s.parse(session)
+	if err != nil {
+		return nil, err
+	}
+	id.Town = s.Town
+	return id, nil
+}
+
+func (s Scheme) parse(session string) (*AgentIdentity, error) {
 	// Check for town-level roles (hq- prefix)
-	if strings.HasPrefix(session, HQPrefix) {
-		suffix := strings.TrimPrefix(session, HQPrefix)
+	if strings.HasPrefix(session, s.HQPrefix) {
+		suffix := strings.TrimPrefix(session, s.HQPrefix)
 		if suffix == "mayor" {
 			return &AgentIdentity{Role: RoleMayor}, nil
 		}
 		if suffix == "deacon" {
 			return &AgentIdentity{Role: RoleDeacon}, nil
 		}
-		return nil, fmt.Errorf("invalid session name %q: unknown hq- role", session)
+		return nil, fmt.Errorf("invalid session name %q: unknown %q role", session, s.HQPrefix)
 	}
 
 	// Rig-level roles use gt- prefix
-	if !strings.HasPrefix(session, Prefix) {
-		return nil, fmt.Errorf("invalid session name %q: missing %q or %q prefix", session, HQPrefix, Prefix)
+	if !strings.HasPrefix(session, s.Prefix) {
+		return nil, fmt.Errorf("invalid session name %q: missing %q or %q prefix", session, s.HQPrefix, s.Prefix)
 	}
 
-	suffix := strings.TrimPrefix(session, Prefix)
+	suffix := strings.TrimPrefix(session, s.Prefix)
 	if suffix == "" {
 		return nil, fmt.Errorf("invalid session name %q: empty after prefix", session)
 	}
@@ -97,19 +113,24 @@ func ParseSessionName(session string) (*AgentIdentity, error) {
 
 // SessionName returns the tmux session name for this identity.
 func (a *AgentIdentity) SessionName() string {
+	return current.SessionName(a)
+}
+
+// SessionName returns the tmux session name for an identity under this scheme.
+func (s Scheme) SessionName(a *AgentIdentity) string {
 	switch a.Role {
 	case RoleMayor:
-		return MayorSessionName()
+		return s.Mayor()
 	case RoleDeacon:
-		return DeaconSessionName()
+		return s.Deacon()
 	case RoleWitness:
-		return WitnessSessionName(a.Rig)
+		return s.Witness(a.Rig)
 	case RoleRefinery:
-		return RefinerySessionName(a.Rig)
+		return s.Refinery(a.Rig)
 	case RoleCrew:
-		return CrewSessionName(a.Rig, a.Name)
+		return s.Crew(a.Rig, a.Name)
 	case RolePolecat:
-		return PolecatSessionName(a.Rig, a.Name)
+		return s.Polecat(a.Rig, a.Name)
 	default:
 		return ""
 	}
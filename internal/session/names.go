@@ -6,44 +6,141 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/tmux"
 )
 
-// Prefix is the common prefix for rig-level Gas Town tmux sessions.
+// Prefix is the default prefix for rig-level Gas Town tmux sessions.
 const Prefix = "gt-"
 
-// HQPrefix is the prefix for town-level services (Mayor, Deacon).
+// HQPrefix is the default prefix for town-level services (Mayor, Deacon).
 const HQPrefix = "hq-"
 
+// TownPlaceholder is replaced with the town name in configured prefixes,
+// e.g. "gt-{town}-" names a witness "gt-acme-gastown-witness".
+const TownPlaceholder = "{town}"
+
+// Scheme is a town's tmux session naming scheme. Towns sharing a machine
+// (and tmux server) need distinct prefixes so their session names don't
+// collide.
+type Scheme struct {
+	Town     string // town name (empty if unknown)
+	Prefix   string // rig-level prefix, e.g. "gt-"
+	HQPrefix string // town-level prefix, e.g. "hq-"
+}
+
+// DefaultScheme returns the built-in naming scheme ("gt-" and "hq-").
+func DefaultScheme() Scheme {
+	return Scheme{Prefix: Prefix, HQPrefix: HQPrefix}
+}
+
+// NewScheme builds a naming scheme for a town. Empty prefixes fall back to
+// the defaults, and TownPlaceholder is expanded to the town name.
+func NewScheme(town, prefix, hqPrefix string) Scheme {
+	if prefix == "" {
+		prefix = Prefix
+	}
+	if hqPrefix == "" {
+		hqPrefix = HQPrefix
+	}
+	return Scheme{
+		Town:     town,
+		Prefix:   strings.ReplaceAll(prefix, TownPlaceholder, town),
+		HQPrefix: strings.ReplaceAll(hqPrefix, TownPlaceholder, town),
+	}
+}
+
+// current is the scheme used by the package-level name functions.
+var current = DefaultScheme()
+
+// SetScheme sets the naming scheme used by the package-level name functions
+// and ParseSessionName, and the prefixes the tmux key bindings match. It is
+// called once at startup from town settings.
+func SetScheme(s Scheme) {
+	current = s
+	tmux.SetSessionPrefixes(s.Prefix, s.HQPrefix)
+}
+
+// CurrentScheme returns the naming scheme in use.
+func CurrentScheme() Scheme {
+	return current
+}
+
+// HasRigPrefix reports whether name carries the current rig-level prefix.
+func HasRigPrefix(name string) bool {
+	return strings.HasPrefix(name, current.Prefix)
+}
+
+// HasHQPrefix reports whether name carries the current town-level prefix.
+func HasHQPrefix(name string) bool {
+	return strings.HasPrefix(name, current.HQPrefix)
+}
+
+// TrimRigPrefix removes the current rig-level prefix from name.
+func TrimRigPrefix(name string) string {
+	return strings.TrimPrefix(name, current.Prefix)
+}
+
+// Mayor returns the session name for the Mayor agent.
+func (s Scheme) Mayor() string {
+	return s.HQPrefix + "mayor"
+}
+
+// Deacon returns the session name for the Deacon agent.
+func (s Scheme) Deacon() string {
+	return s.HQPrefix + "deacon"
+}
+
+// Witness returns the session name for a rig's Witness agent.
+func (s Scheme) Witness(rig string) string {
+	return fmt.Sprintf("%s%s-witness", s.Prefix, rig)
+}
+
+// Refinery returns the session name for a rig's Refinery agent.
+func (s Scheme) Refinery(rig string) string {
+	return fmt.Sprintf("%s%s-refinery", s.Prefix, rig)
+}
+
+// Crew returns the session name for a crew worker in a rig.
+func (s Scheme) Crew(rig, name string) string {
+	return fmt.Sprintf("%s%s-crew-%s", s.Prefix, rig, name)
+}
+
+// Polecat returns the session name for a polecat in a rig.
+func (s Scheme) Polecat(rig, name string) string {
+	return fmt.Sprintf("%s%s-%s", s.Prefix, rig, name)
+}
+
 // MayorSessionName returns the session name for the Mayor agent.
-// One mayor per machine - multi-town requires containers/VMs for isolation.
+// One mayor per town; towns sharing a machine need distinct HQ prefixes.
 func MayorSessionName() string {
-	return HQPrefix + "mayor"
+	return current.Mayor()
 }
 
 // DeaconSessionName returns the session name for the Deacon agent.
-// One deacon per machine - multi-town requires containers/VMs for isolation.
+// One deacon per town; towns sharing a machine need distinct HQ prefixes.
 func DeaconSessionName() string {
-	return HQPrefix + "deacon"
+	return current.Deacon()
 }
 
 // WitnessSessionName returns the session name for a rig's Witness agent.
 func WitnessSessionName(rig string) string {
-	return fmt.Sprintf("%s%s-witness", Prefix, rig)
+	return current.Witness(rig)
 }
 
 // RefinerySessionName returns the session name for a rig's Refinery agent.
 func RefinerySessionName(rig string) string {
-	return fmt.Sprintf("%s%s-refinery", Prefix, rig)
+	return current.Refinery(rig)
 }
 
 // CrewSessionName returns the session name for a crew worker in a rig.
 func CrewSessionName(rig, name string) string {
-	return fmt.Sprintf("%s%s-crew-%s", Prefix, rig, name)
+	return current.Crew(rig, name)
 }
 
 // PolecatSessionName returns the session name for a polecat in a rig.
 func PolecatSessionName(rig, name string) string {
-	return fmt.Sprintf("%s%s-%s", Prefix, rig, name)
+	return current.Polecat(rig, name)
 }
 
 // PropulsionNudge generates the GUPP (Gas Town Universal Propulsion Principle) nudge.
@@ -169,3 +169,57 @@ func TestPropulsionNudgeForRole_AllRoles(t *testing.T) {
 		})
 	}
 }
+
+func TestSchemeWithTown(t *testing.T) {
+	s := NewScheme("acme", "gt-{town}-", "hq-{town}-")
+	if s.Prefix != "gt-acme-" || s.HQPrefix != "hq-acme-" {
+		t.Fatalf("NewScheme() = %+v", s)
+	}
+
+	names := []string{
+		s.Mayor(),
+		s.Deacon(),
+		s.Witness("gastown"),
+		s.Refinery("gastown"),
+		s.Crew("gastown", "max"),
+		s.Polecat("gastown", "Toast"),
+	}
+	if names[0] != "hq-acme-mayor" || names[2] != "gt-acme-gastown-witness" {
+		t.Errorf("scheme names = %v", names)
+	}
+	for _, name := range names {
+		id, err := s.Parse(name)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", name, err)
+		}
+		if id.Town != "acme" {
+			t.Errorf("Parse(%q).Town = %q, want acme", name, id.Town)
+		}
+		if got := s.SessionName(id); got != name {
+			t.Errorf("round trip %q -> %q", name, got)
+		}
+	}
+
+	// Sessions from a town using the default scheme are not ours
+	if _, err := s.Parse("gt-gastown-witness"); err == nil {
+		t.Error("Parse(default-scheme name) should fail")
+	}
+}
+
+func TestSetScheme(t *testing.T) {
+	defer SetScheme(DefaultScheme())
+
+	SetScheme(NewScheme("acme", "acme-gt-", "acme-hq-"))
+	if got := WitnessSessionName("gastown"); got != "acme-gt-gastown-witness" {
+		t.Errorf("WitnessSessionName() = %q", got)
+	}
+	if got := MayorSessionName(); got != "acme-hq-mayor" {
+		t.Errorf("MayorSessionName() = %q", got)
+	}
+	if id, err := ParseSessionName("acme-gt-gastown-crew-max"); err != nil || id.Role != RoleCrew || id.Town != "acme" {
+		t.Errorf("ParseSessionName() = %+v, %v", id, err)
+	}
+	if !HasRigPrefix("acme-gt-gastown-Toast") || HasRigPrefix("gt-gastown-Toast") {
+		t.Error("HasRigPrefix() should follow the current scheme")
+	}
+}
@@ -0,0 +1,154 @@
+// Package sla evaluates beads against per-priority start and finish deadlines.
+//
+// Deadlines come from the town's SLA config and are measured from bead
+// creation. The caller supplies each bead's timestamps (starts come from the
+// assignment outcome log); this package decides how far along each deadline
+// is and whether it has been breached. Alert state lives in the wisp layer,
+// like budget alerts, so each breach escalates once.
+package sla
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/wisp"
+)
+
+// States, from least to most urgent.
+const (
+	StateNone           = ""                // no SLA for the bead's priority, or bead closed
+	StateOK             = "ok"              // within all deadlines
+	StateWarning        = "warning"         // a pending deadline is WarnFraction elapsed
+	StateStartBreached  = "start_breached"  // not started within start_within
+	StateFinishBreached = "finish_breached" // not closed within finish_within
+)
+
+// WarnFraction is how much of a deadline must elapse before a bead is
+// flagged as aging.
+const WarnFraction = 0.75
+
+// alertedKeyPrefix prefixes the per-bead wisp key recording the last breach
+// that was escalated.
+const alertedKeyPrefix = "sla_alerted:"
+
+// Bead is the subset of a bead the SLA evaluation needs.
+type Bead struct {
+	ID       string
+	Rig      string // "" for town-level beads
+	Priority int
+	Status   string
+	Created  time.Time
+	Started  time.Time // when first assigned; zero if unknown
+}
+
+// started reports whether work on the bead has begun. A bead without an
+// assignment record still counts as started once it is hooked or in progress.
+func (b Bead) started() bool {
+	if !b.Started.IsZero() {
+		return true
+	}
+	switch b.Status {
+	case "hooked", "in_progress", "closed":
+		return true
+	}
+	return false
+}
+
+// Status is the SLA evaluation for one bead.
+type Status struct {
+	Bead         string        `json:"bead"`
+	Rig          string        `json:"rig,omitempty"`
+	Priority     int           `json:"priority"`
+	State        string        `json:"state,omitempty"`
+	Age          time.Duration `json:"-"`
+	StartWithin  time.Duration `json:"-"`
+	FinishWithin time.Duration `json:"-"`
+	StartDue     *time.Time    `json:"start_due,omitempty"`  // set while the bead is unstarted
+	FinishDue    *time.Time    `json:"finish_due,omitempty"` // set while the bead is open
+}
+
+// Breached reports whether a deadline has passed.
+func (s *Status) Breached() bool {
+	return s.State == StateStartBreached || s.State == StateFinishBreached
+}
+
+// Summary describes the breach (e.g., "P1 not started within 1h (open 2h30m)").
+func (s *Status) Summary() string {
+	switch s.State {
+	case StateStartBreached:
+		return fmt.Sprintf("P%d not started within %s (open %s)", s.Priority, shortDuration(s.StartWithin), shortDuration(s.Age))
+	case StateFinishBreached:
+		return fmt.Sprintf("P%d not finished within %s (open %s)", s.Priority, shortDuration(s.FinishWithin), shortDuration(s.Age))
+	}
+	return ""
+}
+
+// shortDuration formats a duration to the minute without zero units
+// (e.g., "1h", "2h30m", "45m").
+func shortDuration(d time.Duration) string {
+	str := d.Round(time.Minute).String()
+	str = strings.TrimSuffix(str, "0s")
+	if strings.HasSuffix(str, "h0m") {
+		str = strings.TrimSuffix(str, "0m")
+	}
+	return str
+}
+
+// Evaluate checks the bead against its priority's target. A finish breach
+// outranks a start breach, which outranks an aging warning.
+func Evaluate(cfg *config.SLAConfig, b Bead, now time.Time) *Status {
+	s := &Status{Bead: b.ID, Rig: b.Rig, Priority: b.Priority}
+	if !b.Created.IsZero() {
+		s.Age = now.Sub(b.Created)
+	}
+	startWithin, finishWithin := cfg.Target(b.Priority).Durations()
+	s.StartWithin, s.FinishWithin = startWithin, finishWithin
+	if b.Status == "closed" || b.Created.IsZero() || (startWithin == 0 && finishWithin == 0) {
+		return s
+	}
+
+	s.State = StateOK
+	if startWithin > 0 && !b.started() {
+		due := b.Created.Add(startWithin)
+		s.StartDue = &due
+		if s.Age >= startWithin {
+			s.State = StateStartBreached
+		} else if s.Age >= time.Duration(float64(startWithin)*WarnFraction) {
+			s.State = StateWarning
+		}
+	}
+	if finishWithin > 0 {
+		due := b.Created.Add(finishWithin)
+		s.FinishDue = &due
+		if s.Age >= finishWithin {
+			s.State = StateFinishBreached
+		} else if s.State == StateOK && s.Age >= time.Duration(float64(finishWithin)*WarnFraction) {
+			s.State = StateWarning
+		}
+	}
+	return s
+}
+
+// alertScope is the wisp config scope holding a bead's alert state.
+func alertScope(s *Status) string {
+	if s.Rig == "" {
+		return "hq"
+	}
+	return s.Rig
+}
+
+// ShouldAlert reports whether the bead's current breach has not been
+// escalated yet. A start breach that later becomes a finish breach alerts again.
+func ShouldAlert(townRoot string, s *Status) bool {
+	if !s.Breached() {
+		return false
+	}
+	return wisp.NewConfig(townRoot, alertScope(s)).GetString(alertedKeyPrefix+s.Bead) != s.State
+}
+
+// MarkAlerted records that the bead's current breach was escalated.
+func MarkAlerted(townRoot string, s *Status) error {
+	return wisp.NewConfig(townRoot, alertScope(s)).Set(alertedKeyPrefix+s.Bead, s.State)
+}
@@ -0,0 +1,88 @@
+package sla
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestEvaluate(t *testing.T) {
+	now := time.Now()
+	cfg := &config.SLAConfig{Priorities: map[string]*config.SLATarget{
+		"P1": {StartWithin: "1h", FinishWithin: "8h"},
+		"P2": {FinishWithin: "24h"},
+	}}
+	ago := func(d time.Duration) time.Time { return now.Add(-d) }
+
+	tests := []struct {
+		name string
+		bead Bead
+		want string
+	}{
+		{"no target", Bead{Priority: 3, Status: "open", Created: ago(100 * time.Hour)}, StateNone},
+		{"closed", Bead{Priority: 1, Status: "closed", Created: ago(100 * time.Hour)}, StateNone},
+		{"fresh", Bead{Priority: 1, Status: "open", Created: ago(10 * time.Minute)}, StateOK},
+		{"start aging", Bead{Priority: 1, Status: "open", Created: ago(50 * time.Minute)}, StateWarning},
+		{"start breached", Bead{Priority: 1, Status: "open", Created: ago(2 * time.Hour)}, StateStartBreached},
+		{"started by assignment", Bead{Priority: 1, Status: "open", Created: ago(2 * time.Hour), Started: ago(90 * time.Minute)}, StateOK},
+		{"started by status", Bead{Priority: 1, Status: "hooked", Created: ago(2 * time.Hour)}, StateOK},
+		{"finish aging", Bead{Priority: 1, Status: "in_progress", Created: ago(7 * time.Hour)}, StateWarning},
+		{"finish breached", Bead{Priority: 1, Status: "open", Created: ago(9 * time.Hour)}, StateFinishBreached},
+		{"finish only", Bead{Priority: 2, Status: "open", Created: ago(25 * time.Hour)}, StateFinishBreached},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Evaluate(cfg, tt.bead, now)
+			if s.State != tt.want {
+				t.Errorf("State = %q, want %q", s.State, tt.want)
+			}
+			if s.Breached() != (tt.want == StateStartBreached || tt.want == StateFinishBreached) {
+				t.Errorf("Breached() = %v for state %q", s.Breached(), s.State)
+			}
+		})
+	}
+}
+
+func TestEvaluateNilConfig(t *testing.T) {
+	s := Evaluate(nil, Bead{Priority: 0, Status: "open", Created: time.Now().Add(-time.Hour)}, time.Now())
+	if s.State != StateNone {
+		t.Errorf("State = %q, want none", s.State)
+	}
+}
+
+func TestAlertOncePerBreach(t *testing.T) {
+	townRoot := t.TempDir()
+	s := &Status{Bead: "gt-abc", Rig: "gastown", State: StateStartBreached}
+
+	if !ShouldAlert(townRoot, s) {
+		t.Fatal("expected first start breach to alert")
+	}
+	if err := MarkAlerted(townRoot, s); err != nil {
+		t.Fatal(err)
+	}
+	if ShouldAlert(townRoot, s) {
+		t.Error("expected start breach to alert only once")
+	}
+
+	s.State = StateFinishBreached
+	if !ShouldAlert(townRoot, s) {
+		t.Error("expected finish breach to alert after start breach")
+	}
+
+	if ShouldAlert(townRoot, &Status{Bead: "hq-1", State: StateWarning}) {
+		t.Error("warnings should not alert")
+	}
+}
+
+func TestSummary(t *testing.T) {
+	s := &Status{Priority: 1, State: StateStartBreached, StartWithin: time.Hour, Age: 150 * time.Minute}
+	if got, want := s.Summary(), "P1 not started within 1h (open 2h30m)"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+	s = &Status{Priority: 2, State: StateFinishBreached, FinishWithin: 45 * time.Minute, Age: 50 * time.Minute}
+	if got, want := s.Summary(), "P2 not finished within 45m (open 50m)"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
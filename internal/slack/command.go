@@ -0,0 +1,101 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRequestAge is how old a slash command request may be, so a captured
+// request can't be replayed later.
+const maxRequestAge = 5 * time.Minute
+
+// ErrSignature is returned for a request Slack didn't sign.
+var ErrSignature = errors.New("invalid Slack signature")
+
+// Verify checks a request's X-Slack-Signature against the app's signing
+// secret: an HMAC-SHA256 of "v0:<timestamp>:<body>", made within
+// maxRequestAge of now.
+func Verify(signingSecret string, header http.Header, body []byte, now time.Time) error {
+	ts := header.Get("X-Slack-Request-Timestamp")
+	sig := header.Get("X-Slack-Signature")
+	if ts == "" || !strings.HasPrefix(sig, "v0=") {
+		return ErrSignature
+	}
+	secs, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrSignature
+	}
+	if age := now.Sub(time.Unix(secs, 0)); age > maxRequestAge || age < -maxRequestAge {
+		return fmt.Errorf("%w: request is too old", ErrSignature)
+	}
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte("v0:" + ts + ":"))
+	mac.Write(body)
+	want := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(want), []byte(sig)) {
+		return ErrSignature
+	}
+	return nil
+}
+
+// Command is a slash command invocation (e.g., "/gt status").
+type Command struct {
+	Command   string // the slash command, e.g. "/gt"
+	Text      string // everything after it
+	UserID    string
+	UserName  string
+	ChannelID string
+}
+
+// ParseCommand parses a slash command request's form body.
+func ParseCommand(body []byte) (*Command, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("parsing command: %w", err)
+	}
+	c := &Command{
+		Command:   form.Get("command"),
+		Text:      strings.TrimSpace(form.Get("text")),
+		UserID:    form.Get("user_id"),
+		UserName:  form.Get("user_name"),
+		ChannelID: form.Get("channel_id"),
+	}
+	if c.UserID == "" {
+		return nil, fmt.Errorf("parsing command: no user_id")
+	}
+	return c, nil
+}
+
+// Verb returns the command's first word (e.g., "status") and the rest.
+func (c *Command) Verb() (string, string) {
+	verb, rest, _ := strings.Cut(c.Text, " ")
+	return strings.ToLower(verb), strings.TrimSpace(rest)
+}
+
+// Reply is the response to a slash command. Ephemeral replies are shown
+// only to the user who ran it.
+type Reply struct {
+	Text      string
+	InChannel bool
+}
+
+// MarshalJSON encodes the reply as Slack expects.
+func (r Reply) MarshalJSON() ([]byte, error) {
+	responseType := "ephemeral"
+	if r.InChannel {
+		responseType = "in_channel"
+	}
+	return json.Marshal(struct {
+		ResponseType string `json:"response_type"`
+		Text         string `json:"text"`
+	}{responseType, r.Text})
+}
@@ -0,0 +1,121 @@
+// Package slack connects a town to a Slack app, so on-call humans can
+// supervise it from chat.
+//
+// Outbound, the daemon posts session lifecycle, escalation, and merge
+// events from the town's event log to channels (Sync), through the app's
+// bot token or an incoming webhook. Inbound, gt dashboard serves the app's
+// /gt slash command: requests are verified with the app's signing secret
+// (Verify) and parsed into a Command for the town to run.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// postTimeout bounds each post, so an unreachable Slack doesn't stall the
+// daemon's heartbeat.
+const postTimeout = 10 * time.Second
+
+// DefaultAPIURL is Slack's Web API.
+const DefaultAPIURL = "https://slack.com/api"
+
+// Poster posts messages to Slack.
+type Poster interface {
+	// Post posts text to channel; a webhook ignores channel.
+	Post(ctx context.Context, channel, text string) error
+}
+
+// Client posts with a bot token through the Web API's chat.postMessage.
+type Client struct {
+	Token  string
+	APIURL string // DefaultAPIURL if empty
+	HTTP   *http.Client
+}
+
+// NewClient returns a client for a bot token.
+func NewClient(token string) *Client {
+	return &Client{Token: token, HTTP: http.DefaultClient}
+}
+
+// Post implements Poster.
+func (c *Client) Post(ctx context.Context, channel, text string) error {
+	if channel == "" {
+		return fmt.Errorf("no Slack channel configured")
+	}
+	api := c.APIURL
+	if api == "" {
+		api = DefaultAPIURL
+	}
+	body, err := post(ctx, c.HTTP, api+"/chat.postMessage", c.Token, map[string]string{"channel": channel, "text": text})
+	if err != nil {
+		return err
+	}
+	// The Web API reports failures in the body, with a 200
+	var resp struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("posting to Slack: invalid response: %w", err)
+	}
+	if !resp.OK {
+		return fmt.Errorf("posting to Slack %s: %s", channel, resp.Error)
+	}
+	return nil
+}
+
+// Webhook posts to an incoming webhook, which has its own channel.
+type Webhook struct {
+	URL  string
+	HTTP *http.Client
+}
+
+// NewWebhook returns a poster for an incoming webhook URL.
+func NewWebhook(url string) *Webhook {
+	return &Webhook{URL: url, HTTP: http.DefaultClient}
+}
+
+// Post implements Poster.
+func (w *Webhook) Post(ctx context.Context, _, text string) error {
+	_, err := post(ctx, w.HTTP, w.URL, "", map[string]string{"text": text})
+	return err
+}
+
+// post sends body as JSON and returns the response body.
+func post(ctx context.Context, client *http.Client, url, token string, body interface{}) ([]byte, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("encoding message: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, postTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("posting to Slack: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("posting to Slack: %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return respBody, nil
+}
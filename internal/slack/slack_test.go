@@ -0,0 +1,174 @@
+package slack
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// signed returns headers signing body with secret at ts, as Slack does.
+func signed(secret string, body []byte, ts time.Time) http.Header {
+	mac := hmac.New(sha256.New, []byte(secret))
+	stamp := strconv.FormatInt(ts.Unix(), 10)
+	mac.Write([]byte("v0:" + stamp + ":"))
+	mac.Write(body)
+	h := http.Header{}
+	h.Set("X-Slack-Request-Timestamp", stamp)
+	h.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return h
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Now()
+	body := []byte("command=%2Fgt&text=status&user_id=U1")
+	tests := []struct {
+		name   string
+		header http.Header
+		body   []byte
+		ok     bool
+	}{
+		{"valid", signed("s3cret", body, now), body, true},
+		{"wrong secret", signed("other", body, now), body, false},
+		{"tampered body", signed("s3cret", body, now), []byte("command=%2Fgt&text=prompt"), false},
+		{"replayed", signed("s3cret", body, now.Add(-10*time.Minute)), body, false},
+		{"unsigned", http.Header{}, body, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify("s3cret", tt.header, tt.body, now)
+			if (err == nil) != tt.ok {
+				t.Errorf("Verify = %v, want ok %v", err, tt.ok)
+			}
+			if err != nil && !errors.Is(err, ErrSignature) {
+				t.Errorf("error %v is not ErrSignature", err)
+			}
+		})
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	c, err := ParseCommand([]byte("command=%2Fgt&text=prompt+mayor+check+the+queue&user_id=U1&user_name=ann"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verb, rest := c.Verb(); verb != "prompt" || rest != "mayor check the queue" || c.UserName != "ann" {
+		t.Errorf("parsed %+v: verb %q rest %q", c, verb, rest)
+	}
+	if _, err := ParseCommand([]byte("command=%2Fgt")); err == nil {
+		t.Error("command without a user parsed")
+	}
+}
+
+func TestClientPost(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat.postMessage" || r.Header.Get("Authorization") != "Bearer xoxb-1" {
+			t.Errorf("request %s with auth %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		if got["channel"] == "#missing" {
+			_, _ = w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	c := &Client{Token: "xoxb-1", APIURL: srv.URL, HTTP: srv.Client()}
+	if err := c.Post(context.Background(), "#gastown", "hi"); err != nil || got["text"] != "hi" {
+		t.Errorf("Post = %v, sent %v", err, got)
+	}
+	if err := c.Post(context.Background(), "#missing", "hi"); err == nil || !strings.Contains(err.Error(), "channel_not_found") {
+		t.Errorf("Post to a missing channel = %v", err)
+	}
+}
+
+// recorder is a Poster that records posts, failing after failAfter.
+type recorder struct {
+	posts     []string
+	failAfter int
+}
+
+func (r *recorder) Post(_ context.Context, channel, text string) error {
+	if r.failAfter >= 0 && len(r.posts) == r.failAfter {
+		return errors.New("slack down")
+	}
+	r.posts = append(r.posts, channel+" "+text)
+	return nil
+}
+
+func appendEvents(t *testing.T, townRoot string, evs ...events.Event) {
+	t.Helper()
+	f, err := os.OpenFile(filepath.Join(townRoot, events.EventsFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, e := range evs {
+		data, _ := json.Marshal(e)
+		_, _ = f.Write(append(data, '\n'))
+	}
+}
+
+func TestSync(t *testing.T) {
+	townRoot := t.TempDir()
+	cfg := config.SlackConfig{BotToken: "${T}", Channel: "#gastown", Channels: map[string]string{"escalation": "#oncall"}}
+	ev := func(typ string, payload map[string]interface{}) events.Event {
+		return events.Event{Timestamp: time.Now().UTC().Format(time.RFC3339), Type: typ, Actor: "gastown/witness", Payload: payload}
+	}
+	appendEvents(t, townRoot, ev(events.TypeMerged, events.MergePayload("mr-0", "toast", "old", "")))
+
+	// The first sync skips history
+	r := &recorder{failAfter: -1}
+	if n, err := Sync(context.Background(), townRoot, cfg, r); err != nil || n != 0 {
+		t.Fatalf("first sync = %d, %v", n, err)
+	}
+
+	appendEvents(t, townRoot,
+		ev(events.TypeToolExec, events.ToolPayload("Bash", nil, "")),
+		ev(events.TypeMerged, events.MergePayload("mr-1", "toast", "polecat/toast", "")),
+		ev(events.TypeEscalationSent, events.EscalationPayload("hq-7", "", "mayor", "tests keep failing")),
+	)
+	if n, err := Sync(context.Background(), townRoot, cfg, r); err != nil || n != 2 {
+		t.Fatalf("sync = %d, %v", n, err)
+	}
+	if len(r.posts) != 2 || !strings.HasPrefix(r.posts[0], "#gastown :twisted_rightwards_arrows: Merged `polecat/toast`") ||
+		!strings.HasPrefix(r.posts[1], "#oncall :sos: Escalation hq-7") {
+		t.Errorf("posts = %q", r.posts)
+	}
+
+	// A failed post is retried next time; nothing is posted twice
+	appendEvents(t, townRoot,
+		ev(events.TypeMergeFailed, events.MergePayload("mr-2", "toast", "polecat/a", "conflict")),
+		ev(events.TypeMergeFailed, events.MergePayload("mr-3", "toast", "polecat/b", "conflict")),
+	)
+	r = &recorder{failAfter: 1}
+	if n, err := Sync(context.Background(), townRoot, cfg, r); err == nil || n != 1 {
+		t.Fatalf("failing sync = %d, %v", n, err)
+	}
+	r.failAfter = -1
+	if n, err := Sync(context.Background(), townRoot, cfg, r); err != nil || n != 1 || !strings.Contains(r.posts[1], "polecat/b") {
+		t.Errorf("retry = %d, %v, posts %q", n, err, r.posts)
+	}
+
+	// Kinds not in events aren't posted
+	cfg.Events = []string{"escalation"}
+	appendEvents(t, townRoot, ev(events.TypeMerged, events.MergePayload("mr-4", "toast", "polecat/c", "")))
+	if n, _ := Sync(context.Background(), townRoot, cfg, r); n != 0 {
+		t.Errorf("posted %d merges with events = [escalation]", n)
+	}
+}
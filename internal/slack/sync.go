@@ -0,0 +1,80 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// MaxPerSync bounds how many events one Sync posts, to stay within Slack's
+// rate limits; the rest wait for the next sync.
+const MaxPerSync = 20
+
+// Format returns the Slack message for an event.
+func Format(e events.Event) string {
+	str := func(key string) string {
+		s, _ := e.Payload[key].(string)
+		return s
+	}
+	var text string
+	switch e.Type {
+	case events.TypeAgentStarted:
+		text = fmt.Sprintf(":large_green_circle: %s started (`%s`)", e.Actor, str("session"))
+	case events.TypeAgentStopped:
+		text = fmt.Sprintf(":white_circle: %s stopped (`%s`)", e.Actor, str("session"))
+	case events.TypeSessionDeath:
+		text = fmt.Sprintf(":skull: `%s` died: %s", str("session"), str("reason"))
+	case events.TypeMassDeath:
+		text = fmt.Sprintf(":rotating_light: %v sessions died within %s", e.Payload["count"], str("window"))
+		if cause := str("possible_cause"); cause != "" {
+			text += " (possible cause: " + cause + ")"
+		}
+	case events.TypeLoopDetected:
+		text = fmt.Sprintf(":repeat: `%s` paused as a runaway loop: %s. Resume it with `gt loop resume %s`", str("session"), str("detail"), str("session"))
+	case events.TypeLoopResumed:
+		text = fmt.Sprintf(":arrow_forward: `%s` resumed by %s", str("session"), e.Actor)
+	case events.TypeEscalationSent:
+		text = fmt.Sprintf(":sos: Escalation %s from %s: %s", str("rig"), e.Actor, str("reason"))
+		if severity := str("severity"); severity != "" {
+			text = fmt.Sprintf(":sos: [%s] Escalation %s from %s: %s", severity, str("rig"), e.Actor, str("reason"))
+		}
+	case events.TypeEscalationAcked:
+		text = fmt.Sprintf(":eyes: Escalation %s acknowledged by %s", str("escalation_id"), e.Actor)
+	case events.TypeEscalationClosed:
+		text = fmt.Sprintf(":white_check_mark: Escalation %s closed by %s", str("escalation_id"), e.Actor)
+		if reason := str("reason"); reason != "" {
+			text += ": " + reason
+		}
+	case events.TypeMerged:
+		text = fmt.Sprintf(":twisted_rightwards_arrows: Merged `%s` (%s, from %s)", str("branch"), str("mr"), str("worker"))
+	case events.TypeMergeFailed:
+		text = fmt.Sprintf(":x: Merge of `%s` failed (%s): %s", str("branch"), str("mr"), str("reason"))
+	default:
+		text = fmt.Sprintf("%s by %s", e.Type, e.Actor)
+	}
+	return text
+}
+
+// cursorPath returns where a town's Slack cursor is kept.
+func cursorPath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "slack", "cursor.json")
+}
+
+// Sync posts the events logged since the last sync that cfg posts, up to
+// MaxPerSync, and returns how many it posted. The first sync only marks
+// where the log ends, so history isn't replayed into Slack. An event that
+// fails to post is retried on the next sync.
+func Sync(ctx context.Context, townRoot string, cfg config.SlackConfig, poster Poster) (int, error) {
+	return events.Forward(townRoot, cursorPath(townRoot), MaxPerSync,
+		func(e events.Event) bool {
+			kind := events.ChatKind(e.Type)
+			return kind != "" && cfg.Posts(kind)
+		},
+		func(e events.Event) error {
+			return poster.Post(ctx, cfg.ChannelFor(events.ChatKind(e.Type)), Format(e))
+		})
+}
@@ -0,0 +1,230 @@
+// Package telemetry traces gt with OpenTelemetry, exported over OTLP.
+//
+// Tracing is off unless an OTLP endpoint is configured (gastown.toml
+// [tracing] or the standard OTEL_EXPORTER_OTLP_* variables); until Setup
+// enables it every span is a no-op. A trace follows work across processes:
+// HTTP requests carry it in traceparent headers, and processes gt starts
+// (gt subcommands, agent sessions and the gt commands agents run as tools)
+// inherit it through the TRACEPARENT environment variable.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName is the service.name of gt's spans.
+const ServiceName = "gastown"
+
+// Environment variables that carry trace context into child processes.
+const (
+	EnvTraceParent = "TRACEPARENT"
+	EnvTraceState  = "TRACESTATE"
+)
+
+// Span attribute keys. Model and token attributes follow the OpenTelemetry
+// GenAI conventions.
+const (
+	AttrCommand   = attribute.Key("gt.command")
+	AttrActor     = attribute.Key("gt.actor")
+	AttrRole      = attribute.Key("gt.role")
+	AttrRig       = attribute.Key("gt.rig")
+	AttrSession   = attribute.Key("gt.session")
+	AttrPromptLen = attribute.Key("gt.prompt.length")
+	AttrAgent     = attribute.Key("gen_ai.system")
+	AttrModel     = attribute.Key("gen_ai.request.model")
+	AttrMaxTokens = attribute.Key("gen_ai.request.max_tokens")
+)
+
+// Config configures trace export.
+type Config struct {
+	// Endpoint is the OTLP/HTTP collector URL (e.g., "http://localhost:4318").
+	// Empty defers to OTEL_EXPORTER_OTLP_(TRACES_)ENDPOINT; with neither set,
+	// tracing stays off.
+	Endpoint string
+
+	// Version is reported as service.version.
+	Version string
+}
+
+// Enabled reports whether cfg or the environment names an OTLP endpoint.
+func (cfg Config) Enabled() bool {
+	return cfg.Endpoint != "" || os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+var (
+	mu         sync.RWMutex
+	processCtx = context.Background()
+)
+
+// Setup starts exporting spans as cfg says and returns a shutdown function
+// that flushes them, to call before the process exits. If tracing is not
+// enabled, Setup does nothing and shutdown is a no-op.
+func Setup(ctx context.Context, cfg Config) (shutdown func(context.Context) error, err error) {
+	noop := func(context.Context) error { return nil }
+	if !cfg.Enabled() {
+		return noop, nil
+	}
+	var opts []otlptracehttp.Option
+	if cfg.Endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return noop, fmt.Errorf("creating OTLP exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", ServiceName),
+		attribute.String("service.version", cfg.Version),
+	))
+	if err != nil {
+		return noop, fmt.Errorf("building trace resource: %w", err)
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return tp.Shutdown, nil
+}
+
+// Tracer returns gt's tracer.
+func Tracer() trace.Tracer {
+	return otel.Tracer("github.com/steveyegge/gastown")
+}
+
+// SetProcessContext sets the context spans started by ProcessContext
+// callers descend from: the span of the gt command being run.
+func SetProcessContext(ctx context.Context) {
+	mu.Lock()
+	processCtx = ctx
+	mu.Unlock()
+}
+
+// ProcessContext returns the process's trace context, for code without a
+// context of its own (session managers, tmux) to parent its spans with.
+func ProcessContext() context.Context {
+	mu.RLock()
+	defer mu.RUnlock()
+	return processCtx
+}
+
+// Start starts a span as a child of ProcessContext.
+func Start(name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ProcessContext(), name, trace.WithAttributes(attrs...))
+}
+
+// StartSession starts the span of starting an agent session: which role,
+// rig and tmux session, and the agent, model and token cap it runs with.
+// gt never sees the agent's own requests, so token usage is not recorded.
+func StartSession(role, rig, session, agent, model string, maxTokens int) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		AttrRole.String(role),
+		AttrRig.String(rig),
+		AttrSession.String(session),
+		AttrAgent.String(agent),
+	}
+	if model != "" {
+		attrs = append(attrs, AttrModel.String(model))
+	}
+	if maxTokens > 0 {
+		attrs = append(attrs, AttrMaxTokens.Int(maxTokens))
+	}
+	return Start("session.start", attrs...)
+}
+
+// End records err (if any) on span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// ContextFromEnv returns ctx with the trace context inherited from a parent
+// process through TRACEPARENT and TRACESTATE, if any.
+func ContextFromEnv(ctx context.Context) context.Context {
+	carrier := propagation.MapCarrier{}
+	if v := os.Getenv(EnvTraceParent); v != "" {
+		carrier["traceparent"] = v
+	}
+	if v := os.Getenv(EnvTraceState); v != "" {
+		carrier["tracestate"] = v
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, carrier)
+}
+
+// Env returns the environment that passes ctx's trace context to a child
+// process (TRACEPARENT, and TRACESTATE if set). It is empty when tracing is
+// off or ctx has no span.
+func Env(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	env := make(map[string]string)
+	for _, key := range []string{"traceparent", "tracestate"} {
+		if v := carrier.Get(key); v != "" {
+			env[strings.ToUpper(key)] = v
+		}
+	}
+	return env
+}
+
+// EnvList returns Env(ctx) as KEY=value entries for exec.Cmd.Env.
+func EnvList(ctx context.Context) []string {
+	var list []string
+	for k, v := range Env(ctx) {
+		list = append(list, k+"="+v)
+	}
+	return list
+}
+
+// InjectHTTP adds ctx's trace context to an outgoing request's headers.
+func InjectHTTP(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// Handler wraps h to run each request in a server span, continuing the
+// caller's trace from its traceparent header.
+func Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := Tracer().Start(ctx, r.Method+" "+r.URL.Path,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+			))
+		defer span.End()
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rec, r.WithContext(ctx))
+		span.SetAttributes(attribute.Int("http.response.status_code", rec.status))
+		if rec.status >= 500 {
+			span.SetStatus(codes.Error, http.StatusText(rec.status))
+		}
+	})
+}
+
+// statusRecorder records the status code a handler writes.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,102 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordSpans installs a tracer provider that records ended spans, restoring
+// the global provider and propagator when the test ends.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	rec := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+		SetProcessContext(context.Background())
+	})
+	return rec
+}
+
+func TestSetupDisabled(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	shutdown, err := Setup(context.Background(), Config{})
+	if err != nil {
+		t.Fatalf("Setup = %v, want nil", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown = %v, want nil", err)
+	}
+	if len(Env(context.Background())) != 0 {
+		t.Error("Env should be empty with no span")
+	}
+}
+
+func TestEnvRoundTrip(t *testing.T) {
+	rec := recordSpans(t)
+	ctx, span := Tracer().Start(context.Background(), "gt sling")
+	SetProcessContext(ctx)
+
+	env := Env(ctx)
+	if env[EnvTraceParent] == "" {
+		t.Fatalf("Env = %v, want %s", env, EnvTraceParent)
+	}
+	t.Setenv(EnvTraceParent, env[EnvTraceParent])
+	child := trace.SpanContextFromContext(ContextFromEnv(context.Background()))
+	if child.TraceID() != span.SpanContext().TraceID() || child.SpanID() != span.SpanContext().SpanID() {
+		t.Errorf("ContextFromEnv = %v, want parent %v", child, span.SpanContext())
+	}
+
+	_, session := StartSession("polecat", "gastown", "gt-gastown-toast", "claude", "sonnet", 8000)
+	End(session, nil)
+	span.End()
+	ended := rec.Ended()
+	if len(ended) != 2 || ended[0].Name() != "session.start" {
+		t.Fatalf("ended spans = %d, want session.start then gt sling", len(ended))
+	}
+	if ended[0].Parent().SpanID() != span.SpanContext().SpanID() {
+		t.Error("session.start should descend from the process context")
+	}
+	attrs := map[string]string{}
+	for _, kv := range ended[0].Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs[string(AttrModel)] != "sonnet" || attrs[string(AttrMaxTokens)] != "8000" || attrs[string(AttrRole)] != "polecat" {
+		t.Errorf("session.start attributes = %v", attrs)
+	}
+}
+
+func TestHandlerContinuesTrace(t *testing.T) {
+	rec := recordSpans(t)
+	var inner trace.SpanContext
+	h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inner = trace.SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+
+	ctx, caller := Tracer().Start(context.Background(), "gt federation dispatch")
+	req := httptest.NewRequest(http.MethodPost, "/api/federation/dispatch", nil)
+	InjectHTTP(ctx, req.Header)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	caller.End()
+
+	if inner.TraceID() != caller.SpanContext().TraceID() {
+		t.Errorf("handler trace = %v, want caller's %v", inner.TraceID(), caller.SpanContext().TraceID())
+	}
+	server := rec.Ended()[0]
+	if server.Name() != "POST /api/federation/dispatch" || server.Status().Code.String() != "Error" {
+		t.Errorf("server span = %q status %v, want error status", server.Name(), server.Status())
+	}
+}
@@ -122,6 +122,37 @@ func TestRenderRole_Deacon(t *testing.T) {
 	}
 }
 
+func TestRenderRole_Reviewer(t *testing.T) {
+	tmpl, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	data := RoleData{
+		Role:     "reviewer",
+		RigName:  "myrig",
+		TownRoot: "/test/town",
+		TownName: "town",
+	}
+
+	output, err := tmpl.RenderRole("reviewer", data)
+	if err != nil {
+		t.Fatalf("RenderRole() error = %v", err)
+	}
+
+	if !strings.Contains(output, "Reviewer Context") {
+		t.Error("output missing 'Reviewer Context'")
+	}
+	if !strings.Contains(output, "myrig") {
+		t.Error("output missing rig name")
+	}
+	for _, tool := range []string{"gt review diff", "gt review annotate", "gt review approve", "gt review request-changes"} {
+		if !strings.Contains(output, tool) {
+			t.Errorf("output missing tool %q", tool)
+		}
+	}
+}
+
 func TestRenderRole_Refinery_DefaultBranch(t *testing.T) {
 	tmpl, err := New()
 	if err != nil {
@@ -235,7 +266,7 @@ func TestRoleNames(t *testing.T) {
 	}
 
 	names := tmpl.RoleNames()
-	expected := []string{"mayor", "witness", "refinery", "polecat", "crew", "deacon"}
+	expected := []string{"mayor", "witness", "refinery", "polecat", "crew", "deacon", "reviewer"}
 
 	if len(names) != len(expected) {
 		t.Errorf("RoleNames() = %v, want %v", names, expected)
@@ -265,6 +296,7 @@ func TestGetAllRoleTemplates(t *testing.T) {
 		"mayor.md.tmpl",
 		"polecat.md.tmpl",
 		"crew.md.tmpl",
+		"reviewer.md.tmpl",
 	}
 
 	for _, file := range expectedFiles {
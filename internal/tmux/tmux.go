@@ -13,6 +13,10 @@ import (
 
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/outbound"
+	"github.com/steveyegge/gastown/internal/secret"
+	"github.com/steveyegge/gastown/internal/telemetry"
 )
 
 // versionPattern matches Claude Code version numbers like "2.0.76"
@@ -21,6 +25,28 @@ var versionPattern = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
 // validSessionNameRe validates session names to prevent shell injection
 var validSessionNameRe = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 
+// sessionPrefixes are the session name prefixes the key bindings treat as
+// Gas Town sessions. session.SetScheme keeps them in step with the town's
+// naming scheme.
+var sessionPrefixes = []string{"gt-", "hq-"}
+
+// SetSessionPrefixes sets the session name prefixes that mark Gas Town
+// sessions for SetCycleBindings and SetFeedBinding.
+func SetSessionPrefixes(prefixes ...string) {
+	sessionPrefixes = prefixes
+}
+
+// isGasTownSessionCmd is an if-shell test that succeeds when the session the
+// key was pressed in carries one of the Gas Town session prefixes.
+func isGasTownSessionCmd() string {
+	quoted := make([]string, len(sessionPrefixes))
+	for i, p := range sessionPrefixes {
+		quoted[i] = regexp.QuoteMeta(p)
+	}
+	re := strings.ReplaceAll("^("+strings.Join(quoted, "|")+")", "'", `'\''`)
+	return "echo '#{session_name}' | grep -Eq '" + re + "'"
+}
+
 // Common errors
 var (
 	ErrNoServer        = errors.New("no tmux server running")
@@ -424,7 +450,20 @@ func (t *Tmux) SendKeysDelayedDebounced(session, keys string, preDelayMs, deboun
 // This is the canonical way to send messages to Claude sessions.
 // Uses: literal mode + 500ms debounce + ESC (for vim mode) + separate Enter.
 // Verification is the Witness's job (AI), not this function.
-func (t *Tmux) NudgeSession(session, message string) error {
+// Each nudge is traced as a prompt.send span (its length, not its text)
+// and recorded in the town's event log. The town's outbound filter is
+// applied first, and may rewrite or refuse the message.
+func (t *Tmux) NudgeSession(session, message string) (err error) {
+	_, span := telemetry.Start("prompt.send",
+		telemetry.AttrSession.String(session),
+		telemetry.AttrPromptLen.Int(len(message)))
+	defer func() { telemetry.End(span, err) }()
+
+	// 0. Filter what leaves for the model's API ([outbound] in gastown.toml)
+	if message, err = outbound.Prompt(session, message); err != nil {
+		return err
+	}
+
 	// 1. Send text in literal mode (handles special characters)
 	if _, err := t.run("send-keys", "-t", session, "-l", message); err != nil {
 		return err
@@ -448,14 +487,29 @@ func (t *Tmux) NudgeSession(session, message string) error {
 			lastErr = err
 			continue
 		}
+		_ = events.LogPrompt(nudgeSender(), session, message)
 		return nil
 	}
 	return fmt.Errorf("failed to send Enter after 3 attempts: %w", lastErr)
 }
 
+// nudgeSender returns the agent sending a nudge, for the event log.
+func nudgeSender() string {
+	if actor := os.Getenv("BD_ACTOR"); actor != "" {
+		return actor
+	}
+	return "gt"
+}
+
 // NudgePane sends a message to a specific pane reliably.
 // Same pattern as NudgeSession but targets a pane ID (e.g., "%9") instead of session name.
 func (t *Tmux) NudgePane(pane, message string) error {
+	// 0. Filter what leaves for the model's API ([outbound] in gastown.toml)
+	message, err := outbound.Prompt("", message)
+	if err != nil {
+		return err
+	}
+
 	// 1. Send text in literal mode (handles special characters)
 	if _, err := t.run("send-keys", "-t", pane, "-l", message); err != nil {
 		return err
@@ -631,13 +685,17 @@ func (t *Tmux) FindSessionByWorkDir(targetDir string, processNames []string) ([]
 }
 
 // CapturePane captures the visible content of a pane.
+// Credentials in it are redacted (see secret.Redact), so captures shown on
+// dashboards or saved elsewhere don't spread them.
 func (t *Tmux) CapturePane(session string, lines int) (string, error) {
-	return t.run("capture-pane", "-p", "-t", session, "-S", fmt.Sprintf("-%d", lines))
+	out, err := t.run("capture-pane", "-p", "-t", session, "-S", fmt.Sprintf("-%d", lines))
+	return secret.Redact(out), err
 }
 
-// CapturePaneAll captures all scrollback history.
+// CapturePaneAll captures all scrollback history, redacted like CapturePane.
 func (t *Tmux) CapturePaneAll(session string) (string, error) {
-	return t.run("capture-pane", "-p", "-t", session, "-S", "-")
+	out, err := t.run("capture-pane", "-p", "-t", session, "-S", "-")
+	return secret.Redact(out), err
 }
 
 // CapturePaneLines captures the last N lines of a pane as a slice.
@@ -1157,7 +1215,8 @@ func (t *Tmux) SetTownCycleBindings(session string) error {
 // - Crew sessions: All crew members in the same rig
 //
 // IMPORTANT: These bindings are conditional - they only run gt cycle for
-// Gas Town sessions (those starting with "gt-" or "hq-"). For non-GT sessions,
+// Gas Town sessions (those carrying a session prefix, "gt-" or "hq-" by
+// default; see SetSessionPrefixes). For non-GT sessions,
 // the default tmux behavior (next-window/previous-window) is preserved.
 // See: https://github.com/steveyegge/gastown/issues/13
 //
@@ -1166,16 +1225,16 @@ func (t *Tmux) SetTownCycleBindings(session string) error {
 // resolution time (when the key is pressed), giving us the correct session.
 func (t *Tmux) SetCycleBindings(session string) error {
 	// C-b n → gt cycle next for GT sessions, next-window otherwise
-	// The if-shell checks if session name starts with "gt-" or "hq-"
+	// The if-shell checks if session name starts with a Gas Town prefix
 	if _, err := t.run("bind-key", "-T", "prefix", "n",
-		"if-shell", "echo '#{session_name}' | grep -Eq '^(gt|hq)-'",
+		"if-shell", isGasTownSessionCmd(),
 		"run-shell 'gt cycle next --session #{session_name}'",
 		"next-window"); err != nil {
 		return err
 	}
 	// C-b p → gt cycle prev for GT sessions, previous-window otherwise
 	if _, err := t.run("bind-key", "-T", "prefix", "p",
-		"if-shell", "echo '#{session_name}' | grep -Eq '^(gt|hq)-'",
+		"if-shell", isGasTownSessionCmd(),
 		"run-shell 'gt cycle prev --session #{session_name}'",
 		"previous-window"); err != nil {
 		return err
@@ -1188,12 +1247,12 @@ func (t *Tmux) SetCycleBindings(session string) error {
 // Uses `gt feed --window` which handles both creation and switching.
 //
 // IMPORTANT: This binding is conditional - it only runs for Gas Town sessions
-// (those starting with "gt-" or "hq-"). For non-GT sessions, a help message is shown.
+// (those carrying a session prefix). For non-GT sessions, a help message is shown.
 // See: https://github.com/steveyegge/gastown/issues/13
 func (t *Tmux) SetFeedBinding(session string) error {
 	// C-b a → gt feed --window for GT sessions, help message otherwise
 	_, err := t.run("bind-key", "-T", "prefix", "a",
-		"if-shell", "echo '#{session_name}' | grep -Eq '^(gt|hq)-'",
+		"if-shell", isGasTownSessionCmd(),
 		"run-shell 'gt feed --window'",
 		"display-message 'C-b a is for Gas Town sessions only'")
 	return err
@@ -605,3 +605,27 @@ func TestSessionSet(t *testing.T) {
 		t.Errorf("SessionSet.Names() doesn't contain %q", sessionName)
 	}
 }
+
+func TestIsGasTownSessionCmd(t *testing.T) {
+	defer SetSessionPrefixes(sessionPrefixes...)
+	SetSessionPrefixes("gt-acme.1-", "hq-acme.1-")
+
+	tests := []struct {
+		session string
+		want    bool
+	}{
+		{"gt-acme.1-gastown-witness", true},
+		{"hq-acme.1-mayor", true},
+		{"gt-acmex1-gastown-witness", false}, // "." is literal
+		{"gt-gastown-witness", false},        // another town
+		{"scratch", false},
+	}
+	for _, tt := range tests {
+		// tmux expands #{session_name} before running the test
+		cmd := strings.ReplaceAll(isGasTownSessionCmd(), "#{session_name}", tt.session)
+		got := exec.Command("sh", "-c", cmd).Run() == nil
+		if got != tt.want {
+			t.Errorf("%s on %q = %v, want %v", cmd, tt.session, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+// Package tools implements gt's own shell and file tools, confined to an
+// agent's workspace: bash with a timeout and output cap, read_file,
+// write_file, list_dir, and grep. Agents reach them through gt tool; the
+// "sandbox" toolset grants only those, so a session can edit code and run
+// tests without its runtime's unconfined built-ins.
+//
+// Every call is checked against the town's tool policy (see package
+// policy). File paths must lie within the workspace unless the policy's
+// paths allow more.
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/policy"
+)
+
+// Defaults for a Workspace's limits.
+const (
+	DefaultTimeout    = 2 * time.Minute
+	DefaultMaxOutput  = 64 * 1024
+	DefaultMaxMatches = 200
+)
+
+// ErrDenied wraps the policy's reason when a call is not allowed.
+var ErrDenied = errors.New("denied by tool policy")
+
+// Workspace runs tools for one agent session.
+type Workspace struct {
+	// Root is the session's working directory (its worktree or clone).
+	// Relative paths resolve against it.
+	Root string
+
+	// Policy restricts the calls. Without paths, file tools are confined
+	// to Root.
+	Policy config.ToolPolicy
+
+	// ProtectedBranches are the rig's protected branches, for the policy's
+	// permission profile. Nil means the defaults.
+	ProtectedBranches []string
+
+	// Timeout bounds a bash command (default DefaultTimeout).
+	Timeout time.Duration
+
+	// MaxOutput caps the bytes of output a tool returns (default
+	// DefaultMaxOutput). Longer bash output keeps its end.
+	MaxOutput int
+}
+
+// check evaluates a call against w's policy.
+func (w *Workspace) check(tool string, input map[string]interface{}) error {
+	p := w.Policy
+	if len(p.Paths) == 0 {
+		p.Paths = []string{"."}
+	}
+	if d := policy.Evaluate(p, policy.Call{Tool: tool, Input: input, WorkDir: w.Root, ProtectedBranches: w.ProtectedBranches}); !d.Allowed {
+		return fmt.Errorf("%w: %s", ErrDenied, d.Reason)
+	}
+	return nil
+}
+
+// path checks and resolves path for a file tool.
+func (w *Workspace) path(tool, path string) (string, error) {
+	if path == "" {
+		path = "."
+	}
+	if err := w.check(tool, map[string]interface{}{"file_path": path, "path": path}); err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(w.Root, path)
+	}
+	return filepath.Clean(path), nil
+}
+
+func (w *Workspace) maxOutput() int {
+	if w.MaxOutput > 0 {
+		return w.MaxOutput
+	}
+	return DefaultMaxOutput
+}
+
+// BashResult is the outcome of a bash command.
+type BashResult struct {
+	Output    string // combined stdout and stderr, capped
+	ExitCode  int
+	TimedOut  bool
+	Truncated bool // earlier output was dropped
+}
+
+// tailBuffer keeps the last max bytes written to it.
+type tailBuffer struct {
+	buf     []byte
+	max     int
+	dropped int
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - b.max; over > b.max {
+		b.dropped += over
+		b.buf = append(b.buf[:0], b.buf[over:]...)
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	if over := len(b.buf) - b.max; over > 0 {
+		b.dropped += over
+		b.buf = b.buf[over:]
+	}
+	if b.dropped == 0 {
+		return string(b.buf)
+	}
+	return fmt.Sprintf("[%d earlier bytes omitted]\n%s", b.dropped, b.buf)
+}
+
+// Bash runs command with bash in the workspace root. A non-zero exit or a
+// timeout is reported in the result, not as an error.
+func (w *Workspace) Bash(ctx context.Context, command string) (*BashResult, error) {
+	if err := w.check("Bash", map[string]interface{}{"command": command}); err != nil {
+		return nil, err
+	}
+	timeout := w.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	out := &tailBuffer{max: w.maxOutput()}
+	cmd := exec.CommandContext(ctx, "bash", "-c", command)
+	cmd.Dir = w.Root
+	cmd.Stdout = out
+	cmd.Stderr = out
+	cmd.WaitDelay = 5 * time.Second // don't wait forever on children holding the output open
+
+	err := cmd.Run()
+	res := &BashResult{}
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		res.TimedOut, res.ExitCode = true, -1
+	case errors.As(err, &exitErr):
+		res.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		return nil, fmt.Errorf("running bash: %w", err)
+	}
+	res.Output = out.String()
+	res.Truncated = out.dropped > 0
+	return res, nil
+}
+
+// ReadFile returns up to limit lines of path starting at line offset
+// (1-based; 0 means the start), capped at MaxOutput bytes.
+func (w *Workspace) ReadFile(path string, offset, limit int) (string, error) {
+	abs, err := w.path("Read", path)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(abs)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var b strings.Builder
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for n, shown := 1, 0; scanner.Scan(); n++ {
+		if n < offset {
+			continue
+		}
+		if limit > 0 && shown == limit {
+			break
+		}
+		if b.Len()+len(scanner.Text()) > w.maxOutput() {
+			fmt.Fprintf(&b, "[output capped at %d bytes; read from line %d]\n", w.maxOutput(), n)
+			break
+		}
+		b.WriteString(scanner.Text())
+		b.WriteByte('\n')
+		shown++
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("reading %s: %w", path, err)
+	}
+	return b.String(), nil
+}
+
+// WriteFile writes content to path, creating parent directories.
+func (w *Workspace) WriteFile(path, content string) error {
+	abs, err := w.path("Write", path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(abs), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(abs, []byte(content), 0644) //nolint:gosec // G306: workspace files are the agent's source files
+}
+
+// Entry is a directory listing entry.
+type Entry struct {
+	Name  string `json:"name"`
+	IsDir bool   `json:"is_dir"`
+	Size  int64  `json:"size"`
+}
+
+// ListDir lists the entries of the directory at path, sorted by name.
+func (w *Workspace) ListDir(path string) ([]Entry, error) {
+	abs, err := w.path("LS", path)
+	if err != nil {
+		return nil, err
+	}
+	dirEntries, err := os.ReadDir(abs)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(dirEntries))
+	for _, de := range dirEntries {
+		e := Entry{Name: de.Name(), IsDir: de.IsDir()}
+		if info, err := de.Info(); err == nil && !de.IsDir() {
+			e.Size = info.Size()
+		}
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+// Match is a line matching a grep.
+type Match struct {
+	Path string `json:"path"` // relative to the workspace root
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// Grep returns up to max lines (default DefaultMaxMatches) matching the
+// regular expression pattern in the files under path, skipping .git and
+// binary files.
+func (w *Workspace) Grep(pattern, path string, max int) ([]Match, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	abs, err := w.path("Grep", path)
+	if err != nil {
+		return nil, err
+	}
+	if max <= 0 {
+		max = DefaultMaxMatches
+	}
+
+	var matches []Match
+	errDone := errors.New("enough matches")
+	err = filepath.WalkDir(abs, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // unreadable entries are skipped
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil || bytes.IndexByte(data, 0) >= 0 {
+			return nil
+		}
+		rel, _ := filepath.Rel(w.Root, p)
+		for i, line := range strings.Split(string(data), "\n") {
+			if re.MatchString(line) {
+				matches = append(matches, Match{Path: rel, Line: i + 1, Text: line})
+				if len(matches) == max {
+					return errDone
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil && err != errDone {
+		return nil, err
+	}
+	return matches, nil
+}
@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func newWorkspace(t *testing.T) *Workspace {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return &Workspace{Root: root}
+}
+
+func TestBash(t *testing.T) {
+	ws := newWorkspace(t)
+	res, err := ws.Bash(context.Background(), "ls; exit 3")
+	if err != nil {
+		t.Fatalf("Bash: %v", err)
+	}
+	if res.ExitCode != 3 || !strings.Contains(res.Output, "main.go") {
+		t.Errorf("Bash = %+v, want exit 3 listing main.go", res)
+	}
+
+	ws.MaxOutput = 100
+	res, err = ws.Bash(context.Background(), "seq 1 1000")
+	if err != nil {
+		t.Fatalf("Bash: %v", err)
+	}
+	if !res.Truncated || !strings.HasSuffix(res.Output, "1000\n") || len(res.Output) > 200 {
+		t.Errorf("Bash output not capped to its end: %q", res.Output)
+	}
+
+	ws.Timeout = 100 * time.Millisecond
+	res, err = ws.Bash(context.Background(), "sleep 5")
+	if err != nil || !res.TimedOut {
+		t.Errorf("Bash(sleep) = %+v, %v; want a timeout", res, err)
+	}
+
+	ws.Policy = config.ToolPolicy{DenyCommands: []string{"rm"}}
+	if _, err := ws.Bash(context.Background(), "rm -rf ."); !errors.Is(err, ErrDenied) {
+		t.Errorf("Bash(rm) = %v, want ErrDenied", err)
+	}
+}
+
+func TestFileTools(t *testing.T) {
+	ws := newWorkspace(t)
+
+	if err := ws.WriteFile("pkg/util.go", "package pkg\n// TODO tidy\n"); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	text, err := ws.ReadFile("pkg/util.go", 2, 1)
+	if err != nil || text != "// TODO tidy\n" {
+		t.Errorf("ReadFile = %q, %v", text, err)
+	}
+
+	entries, err := ws.ListDir(".")
+	if err != nil || len(entries) != 2 || entries[0].Name != "main.go" || !entries[1].IsDir {
+		t.Errorf("ListDir = %+v, %v", entries, err)
+	}
+
+	matches, err := ws.Grep("TODO|func main", ".", 0)
+	if err != nil || len(matches) != 2 {
+		t.Fatalf("Grep = %+v, %v; want 2 matches", matches, err)
+	}
+	if matches[0].Path != "main.go" || matches[0].Line != 3 || matches[1].Path != filepath.Join("pkg", "util.go") {
+		t.Errorf("Grep = %+v", matches)
+	}
+}
+
+func TestConfinedToWorkspace(t *testing.T) {
+	ws := newWorkspace(t)
+	outside := filepath.Join(t.TempDir(), "x.txt")
+
+	if err := ws.WriteFile(outside, "x"); !errors.Is(err, ErrDenied) {
+		t.Errorf("WriteFile outside = %v, want ErrDenied", err)
+	}
+	if _, err := ws.ReadFile("../../etc/passwd", 0, 0); !errors.Is(err, ErrDenied) {
+		t.Errorf("ReadFile outside = %v, want ErrDenied", err)
+	}
+	if _, err := ws.Grep("root", "/etc", 0); !errors.Is(err, ErrDenied) {
+		t.Errorf("Grep outside = %v, want ErrDenied", err)
+	}
+
+	ws.Policy = config.ToolPolicy{Paths: []string{".", filepath.Dir(outside)}}
+	if err := ws.WriteFile(outside, "x"); err != nil {
+		t.Errorf("WriteFile to a policy path = %v", err)
+	}
+}
@@ -0,0 +1,306 @@
+// Package townbundle packs a town's configuration into a portable bundle
+// (gt town export) and unpacks it into another town (gt town import).
+//
+// A bundle is one JSON document holding the town's config files verbatim:
+// its identity, rig registrations, account profiles, settings, schedules,
+// escalation routes, gastown.toml (prompts, policies, integrations), and
+// each rig's settings and gastown.toml. Secrets stay out of it: config
+// refers to them as secret://name, and a bundle lists the names it needs
+// so they can be set on the new machine with gt secret set. Credentials
+// found written into a file are replaced with secret.Redacted on export.
+package townbundle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/migrate"
+	"github.com/steveyegge/gastown/internal/secret"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Format identifies a town bundle.
+const Format = "gastown-town-bundle"
+
+// CurrentVersion is the bundle format's version.
+const CurrentVersion = 1
+
+// rigsFile is the rig registry, whose rigs import adds with gt rig add
+// rather than by writing the file.
+const rigsFile = "mayor/rigs.json"
+
+// TownFiles are the town's files a bundle holds, relative to the town root.
+var TownFiles = []string{
+	"mayor/town.json",
+	rigsFile,
+	"mayor/accounts.json",
+	"mayor/config.json",
+	"mayor/daemon.json",
+	"mayor/overseer.json",
+	"settings/config.json",
+	"settings/agents.json",
+	"settings/escalation.json",
+	"settings/schedules.json",
+	"config/messaging.json",
+	config.GastownConfigFile,
+	"town.yaml",
+}
+
+// RigFiles are each rig's files a bundle holds, relative to the rig.
+var RigFiles = []string{
+	"settings/config.json",
+	config.GastownConfigFile,
+}
+
+// Bundle is a town's configuration.
+type Bundle struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	Town       string    `json:"town"`
+	GTVersion  string    `json:"gt_version,omitempty"`
+	ExportedAt time.Time `json:"exported_at"`
+
+	// Secrets are the names of the secrets the files refer to.
+	Secrets []string `json:"secrets,omitempty"`
+
+	// Files are the files' contents, by path relative to the town root.
+	Files map[string]string `json:"files"`
+}
+
+// Export bundles the town's configuration. It returns the bundle and the
+// files credentials were redacted from.
+func Export(townRoot string) (*Bundle, []string, error) {
+	town, err := config.LoadTownConfig(constants.MayorTownPath(townRoot))
+	if err != nil {
+		return nil, nil, err
+	}
+	b := &Bundle{
+		Format:     Format,
+		Version:    CurrentVersion,
+		Town:       town.Name,
+		ExportedAt: time.Now().UTC(),
+		Files:      make(map[string]string),
+	}
+
+	paths := append([]string(nil), TownFiles...)
+	rigs, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		return nil, nil, err
+	}
+	if rigs != nil {
+		for name := range rigs.Rigs {
+			for _, f := range RigFiles {
+				paths = append(paths, name+"/"+f)
+			}
+		}
+	}
+
+	secrets := make(map[string]bool)
+	var redacted []string
+	for _, rel := range paths {
+		data, err := os.ReadFile(filepath.Join(townRoot, filepath.FromSlash(rel))) //nolint:gosec // G304: path is a config file in the town
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, nil, err
+		}
+		content, scrubbed := scrub(string(data))
+		if scrubbed {
+			redacted = append(redacted, rel)
+		}
+		b.Files[rel] = content
+		for _, name := range config.SecretRefs(content) {
+			secrets[name] = true
+		}
+	}
+	for name := range secrets {
+		b.Secrets = append(b.Secrets, name)
+	}
+	sort.Strings(b.Secrets)
+	sort.Strings(redacted)
+	return b, redacted, nil
+}
+
+// scrub redacts credentials written into a file. Lines that refer to a
+// secret or variable hold no credential and are kept as they are, so a
+// reference such as token = "secret://jira" survives. It reports whether
+// anything was redacted.
+func scrub(content string) (string, bool) {
+	lines := strings.Split(content, "\n")
+	var scrubbed bool
+	for i, line := range lines {
+		if config.HasRefs(line) {
+			continue
+		}
+		if r := secret.Redact(line); r != line {
+			lines[i] = r
+			scrubbed = true
+		}
+	}
+	return strings.Join(lines, "\n"), scrubbed
+}
+
+// Write encodes the bundle as JSON.
+func (b *Bundle) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
+
+// Read decodes and checks a bundle.
+func Read(r io.Reader) (*Bundle, error) {
+	var b Bundle
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return nil, fmt.Errorf("reading bundle: %w", err)
+	}
+	if b.Format != Format {
+		return nil, fmt.Errorf("not a Gas Town town bundle (format %q)", b.Format)
+	}
+	if b.Version > CurrentVersion {
+		return nil, fmt.Errorf("bundle version %d is newer than this gt supports (%d); upgrade gt", b.Version, CurrentVersion)
+	}
+	for rel := range b.Files {
+		if !bundled(rel) {
+			return nil, fmt.Errorf("bundle holds %q, which isn't a town config file", rel)
+		}
+	}
+	return &b, nil
+}
+
+// bundled reports whether rel is a path a bundle may hold: one of
+// TownFiles, or one of RigFiles in a rig's directory.
+func bundled(rel string) bool {
+	if rel != path.Clean(rel) || path.IsAbs(rel) || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	for _, f := range TownFiles {
+		if rel == f {
+			return true
+		}
+	}
+	rig, f, ok := strings.Cut(rel, "/")
+	if !ok || rig == "" || strings.HasPrefix(rig, ".") {
+		return false
+	}
+	for _, rf := range RigFiles {
+		if f == rf {
+			return true
+		}
+	}
+	return false
+}
+
+// Migrate upgrades the bundle's JSON files from older schema versions (see
+// package migrate), returning the steps applied.
+func (b *Bundle) Migrate() ([]migrate.Step, error) {
+	var all []migrate.Step
+	for _, rel := range b.paths() {
+		if !strings.HasSuffix(rel, ".json") {
+			continue
+		}
+		doc, err := migrate.Decode([]byte(b.Files[rel]))
+		if err != nil {
+			return all, fmt.Errorf("%s: %w", rel, err)
+		}
+		steps, err := migrate.Document(rel, doc)
+		if err != nil {
+			return all, err
+		}
+		if len(steps) == 0 {
+			continue
+		}
+		out, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return all, err
+		}
+		b.Files[rel] = string(out)
+		all = append(all, steps...)
+	}
+	return all, nil
+}
+
+// Rigs returns the bundle's rig registrations.
+func (b *Bundle) Rigs() (map[string]config.RigEntry, error) {
+	content, ok := b.Files[rigsFile]
+	if !ok {
+		return nil, nil
+	}
+	var rigs config.RigsConfig
+	if err := json.Unmarshal([]byte(content), &rigs); err != nil {
+		return nil, fmt.Errorf("%s: %w", rigsFile, err)
+	}
+	return rigs.Rigs, nil
+}
+
+// InstallOptions control how Install writes a bundle's files.
+type InstallOptions struct {
+	// Force overwrites files that exist with different contents.
+	Force bool
+
+	// DryRun reports what would be written without writing it.
+	DryRun bool
+
+	// NewRigs are rigs just added for the import. Their files are
+	// overwritten, since they hold only gt rig add's defaults.
+	NewRigs map[string]bool
+}
+
+// Install writes the bundle's files into the town, except the rig registry
+// (rigs are added with gt rig add first). A file that exists with different
+// contents is skipped unless opts.Force is set. It returns the files
+// written and skipped.
+func (b *Bundle) Install(townRoot string, opts InstallOptions) (written, skipped []string, err error) {
+	for _, rel := range b.paths() {
+		if rel == rigsFile {
+			continue
+		}
+		content := b.Files[rel]
+		dest := filepath.Join(townRoot, filepath.FromSlash(rel))
+		rig, _, _ := strings.Cut(rel, "/")
+		if existing, err := os.ReadFile(dest); err == nil { //nolint:gosec // G304: dest is a bundled config path (see bundled)
+			if string(existing) == content {
+				continue
+			}
+			if !opts.Force && !opts.NewRigs[rig] {
+				skipped = append(skipped, rel)
+				continue
+			}
+		}
+		written = append(written, rel)
+		if opts.DryRun {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return written, skipped, err
+		}
+		perm := os.FileMode(0644)
+		if strings.HasPrefix(rel, constants.DirMayor+"/") {
+			perm = 0600
+		}
+		if err := util.AtomicWriteFile(dest, []byte(content), perm); err != nil {
+			return written, skipped, err
+		}
+	}
+	return written, skipped, nil
+}
+
+// paths returns the bundle's file paths in order.
+func (b *Bundle) paths() []string {
+	paths := make([]string, 0, len(b.Files))
+	for rel := range b.Files {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,266 @@
+package townspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// State is the town as it is, to compare with a spec.
+type State struct {
+	// Running records which agents' sessions are running, by role for the
+	// town's ("mayor") and by rig/role for the rigs' ("gastown/witness").
+	Running map[string]bool
+
+	// Rigs are the registered rigs, by name.
+	Rigs map[string]*RigState
+
+	// Schedules are the town's scheduled jobs.
+	Schedules []config.ScheduledJob
+
+	// Integrations are gastown.toml's tables, by name (see ReadGastownTOML).
+	Integrations map[string]interface{}
+}
+
+// RigState is a registered rig as it is.
+type RigState struct {
+	GitURL   string
+	Parked   bool
+	Polecats int                  // worker session cap, 0 = none
+	Budget   *config.BudgetConfig // nil = none
+}
+
+// Change ops, as gt plan shows them.
+const (
+	OpAdd      = "+" // added, or started
+	OpRemove   = "-" // removed, or stopped
+	OpUpdate   = "~" // changed
+	OpConflict = "!" // differs, but apply can't change it
+)
+
+// Change is one difference between a spec and the town.
+type Change struct {
+	Op      string
+	Subject string // what changes, e.g. "rig gastown", "gastown/witness"
+	Detail  string // how, e.g. "4 → 6"
+
+	// Command is the gt command that makes the change (without "gt"). It's
+	// nil for a settings change, which Write makes, and for a conflict.
+	Command []string
+}
+
+// Settings reports whether Write makes the change.
+func (c Change) Settings() bool {
+	return c.Command == nil && c.Op != OpConflict
+}
+
+// String formats the change as gt plan shows it.
+func (c Change) String() string {
+	if c.Detail == "" {
+		return c.Op + " " + c.Subject
+	}
+	return c.Op + " " + c.Subject + ": " + c.Detail
+}
+
+// Diff returns the changes that take the town from state to spec, in the
+// order they're made: conflicts first (they're only reported), then new
+// rigs, settings, parking, stopped sessions, and started sessions.
+func Diff(spec *Spec, state *State) []Change {
+	var conflicts, rigs, settings, parks, stops, starts []Change
+	session := func(key string, want bool, start, stop []string) {
+		switch running := state.Running[key]; {
+		case want && !running:
+			starts = append(starts, Change{Op: OpAdd, Subject: key, Detail: "start", Command: start})
+		case !want && running:
+			stops = append(stops, Change{Op: OpRemove, Subject: key, Detail: "stop", Command: stop})
+		}
+	}
+
+	if spec.Roles != nil {
+		for _, role := range TownRoles {
+			session(role, contains(spec.Roles, role), []string{role, "start"}, []string{role, "stop"})
+		}
+	}
+
+	for _, name := range sortedKeys(spec.Rigs) {
+		want := spec.Rigs[name]
+		have := state.Rigs[name]
+		if have == nil {
+			command := []string{"rig", "add", name, want.GitURL}
+			if want.Branch != "" {
+				command = append(command, "--branch", want.Branch)
+			}
+			if want.Prefix != "" {
+				command = append(command, "--prefix", want.Prefix)
+			}
+			rigs = append(rigs, Change{Op: OpAdd, Subject: "rig " + name, Detail: want.GitURL, Command: command})
+			have = &RigState{GitURL: want.GitURL}
+		}
+		if normalizeURL(have.GitURL) != normalizeURL(want.GitURL) {
+			conflicts = append(conflicts, Change{Op: OpConflict, Subject: "rig " + name,
+				Detail: fmt.Sprintf("git_url is %s, not %s (remove and re-add the rig to change it)", have.GitURL, want.GitURL)})
+		}
+
+		if want.Polecats != nil && *want.Polecats != have.Polecats {
+			settings = append(settings, Change{Op: OpUpdate, Subject: name + " polecats",
+				Detail: fmt.Sprintf("%s → %s", polecatCap(have.Polecats), polecatCap(*want.Polecats))})
+		}
+		if want.Budget != nil && !reflect.DeepEqual(normalizeBudget(have.Budget), normalizeBudget(want.Budget.Config())) {
+			settings = append(settings, Change{Op: OpUpdate, Subject: name + " budget",
+				Detail: fmt.Sprintf("%s → %s", budgetSummary(have.Budget), budgetSummary(want.Budget.Config()))})
+		}
+
+		switch {
+		case want.Parked && !have.Parked:
+			parks = append(parks, Change{Op: OpRemove, Subject: "rig " + name, Detail: "park", Command: []string{"rig", "park", name}})
+		case !want.Parked && have.Parked:
+			parks = append(parks, Change{Op: OpAdd, Subject: "rig " + name, Detail: "unpark", Command: []string{"rig", "unpark", name}})
+		}
+		// Parking stops a rig's agents, and they stay stopped while it's parked
+		if want.Roles != nil && !want.Parked {
+			for _, role := range RigRoles {
+				session(name+"/"+role, contains(want.Roles, role), []string{role, "start", name}, []string{role, "stop", name})
+			}
+		}
+	}
+
+	if spec.Schedules != nil {
+		settings = append(settings, diffSchedules(state.Schedules, spec.Schedules)...)
+	}
+
+	for _, name := range sortedKeys(spec.Integrations) {
+		have, ok := state.Integrations[name]
+		switch {
+		case !ok:
+			settings = append(settings, Change{Op: OpAdd, Subject: "integration " + name})
+		case !reflect.DeepEqual(normalize(have), normalize(spec.Integrations[name])):
+			settings = append(settings, Change{Op: OpUpdate, Subject: "integration " + name,
+				Detail: strings.Join(changedKeys(normalize(have), normalize(spec.Integrations[name])), ", ")})
+		}
+	}
+
+	var out []Change
+	for _, group := range [][]Change{conflicts, rigs, settings, parks, stops, starts} {
+		out = append(out, group...)
+	}
+	return out
+}
+
+// diffSchedules returns the changes that turn the jobs have into want.
+func diffSchedules(have, want []config.ScheduledJob) []Change {
+	current := make(map[string]config.ScheduledJob)
+	for _, job := range have {
+		current[job.Name] = job
+	}
+	var out []Change
+	declared := make(map[string]bool)
+	for _, job := range want {
+		declared[job.Name] = true
+		old, ok := current[job.Name]
+		switch {
+		case !ok:
+			out = append(out, Change{Op: OpAdd, Subject: "schedule " + job.Name, Detail: job.Cron + " " + job.Action})
+		case !reflect.DeepEqual(old, job):
+			out = append(out, Change{Op: OpUpdate, Subject: "schedule " + job.Name,
+				Detail: strings.Join(changedKeys(normalize(old), normalize(job)), ", ")})
+		}
+	}
+	for _, job := range have {
+		if !declared[job.Name] {
+			out = append(out, Change{Op: OpRemove, Subject: "schedule " + job.Name})
+		}
+	}
+	return out
+}
+
+// normalizeURL strips what doesn't change which repository a git URL names.
+func normalizeURL(u string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(u, "/"), ".git")
+}
+
+// polecatCap formats a worker session cap.
+func polecatCap(n int) string {
+	if n == 0 {
+		return "no cap"
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// normalizeBudget returns b with an empty budget as nil, so "none" and
+// "all zero" compare equal.
+func normalizeBudget(b *config.BudgetConfig) *config.BudgetConfig {
+	if b == nil || (b.DailyUSD == 0 && b.WeeklyUSD == 0 && b.DailyTokens == 0 && b.WeeklyTokens == 0 && len(b.Actions) == 0) {
+		return nil
+	}
+	c := *b
+	if len(c.Actions) == 0 {
+		c.Actions = nil
+	}
+	return &c
+}
+
+// budgetSummary formats a budget's caps.
+func budgetSummary(b *config.BudgetConfig) string {
+	b = normalizeBudget(b)
+	if b == nil {
+		return "none"
+	}
+	var parts []string
+	if b.DailyUSD > 0 {
+		parts = append(parts, fmt.Sprintf("$%.2f/day", b.DailyUSD))
+	}
+	if b.WeeklyUSD > 0 {
+		parts = append(parts, fmt.Sprintf("$%.2f/week", b.WeeklyUSD))
+	}
+	if b.DailyTokens > 0 {
+		parts = append(parts, fmt.Sprintf("%d tokens/day", b.DailyTokens))
+	}
+	if b.WeeklyTokens > 0 {
+		parts = append(parts, fmt.Sprintf("%d tokens/week", b.WeeklyTokens))
+	}
+	if len(b.Actions) > 0 {
+		parts = append(parts, strings.Join(b.Actions, "+"))
+	}
+	if len(parts) == 0 {
+		return "no caps"
+	}
+	return strings.Join(parts, " ")
+}
+
+// normalize returns v as JSON would decode it, so values parsed from YAML,
+// TOML, and Go structs compare equal when they hold the same settings.
+func normalize(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+// changedKeys returns the top-level keys that differ between two
+// normalized tables.
+func changedKeys(have, want interface{}) []string {
+	h, _ := have.(map[string]interface{})
+	w, _ := want.(map[string]interface{})
+	keys := make(map[string]bool)
+	for k := range h {
+		keys[k] = true
+	}
+	for k := range w {
+		keys[k] = true
+	}
+	var out []string
+	for _, k := range sortedKeys(keys) {
+		if !reflect.DeepEqual(h[k], w[k]) {
+			out = append(out, k)
+		}
+	}
+	return out
+}
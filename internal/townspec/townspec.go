@@ -0,0 +1,224 @@
+// Package townspec declares a whole town in one YAML file (town.yaml) and
+// reconciles the town with it.
+//
+// The spec names the rigs the town has, the agents to run in the town and
+// in each rig, each rig's polecat cap and budget, the town's scheduled jobs,
+// and its integrations (gastown.toml's [slack], [jira], ... tables). Diff
+// compares a spec with the town's State and returns the Changes that
+// converge them; gt plan prints them and gt apply makes them, running a gt
+// command for each (gt rig add, gt witness start, ...) and calling Write for
+// the settings. Whatever the spec leaves out is left as it is: rigs it
+// doesn't name, and the roles, caps, budgets, schedules, and integrations
+// it doesn't declare.
+package townspec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/scheduler"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is the spec's file in the town root.
+const DefaultFile = "town.yaml"
+
+// TownRoles are the town-level agents a spec can run.
+var TownRoles = []string{"deacon", "mayor"}
+
+// RigRoles are the rig-level agents a spec can run.
+var RigRoles = []string{"witness", "refinery"}
+
+// IntegrationSections are the gastown.toml tables a spec can set.
+var IntegrationSections = []string{"alerting", "discord", "email", "jira", "linear", "slack"}
+
+// Spec is a town as declared in town.yaml.
+type Spec struct {
+	// Roles are the town-level agents to run ("deacon", "mayor"); the
+	// others are stopped. If omitted, they're left as they are.
+	Roles []string `yaml:"roles"`
+
+	// Rigs are the rigs the town has, by name.
+	Rigs map[string]*Rig `yaml:"rigs"`
+
+	// Schedules are the town's scheduled jobs (settings/schedules.json).
+	// Jobs not listed are removed. If omitted, the jobs are left as they are.
+	Schedules []config.ScheduledJob `yaml:"schedules"`
+
+	// Integrations are gastown.toml tables, by name (see
+	// IntegrationSections). Each replaces its table in gastown.toml.
+	Integrations map[string]map[string]interface{} `yaml:"integrations"`
+}
+
+// Rig is a rig as declared in town.yaml.
+type Rig struct {
+	// GitURL is the repository the rig is added from.
+	GitURL string `yaml:"git_url"`
+
+	// Branch and Prefix are passed to gt rig add when the rig is added.
+	Branch string `yaml:"branch"`
+	Prefix string `yaml:"prefix"`
+
+	// Parked parks the rig (gt rig park), which stops its agents.
+	Parked bool `yaml:"parked"`
+
+	// Roles are the rig's agents to run ("witness", "refinery"); the
+	// others are stopped. If omitted, they're left as they are.
+	Roles []string `yaml:"roles"`
+
+	// Polecats caps the rig's worker sessions (0 = no cap). If omitted, the
+	// cap is left as it is.
+	Polecats *int `yaml:"polecats"`
+
+	// Budget is the rig's budget. If omitted, it's left as it is.
+	Budget *Budget `yaml:"budget"`
+}
+
+// Budget is a rig's budget (see config.BudgetConfig).
+type Budget struct {
+	DailyUSD     float64  `yaml:"daily_usd"`
+	WeeklyUSD    float64  `yaml:"weekly_usd"`
+	DailyTokens  int      `yaml:"daily_tokens"`
+	WeeklyTokens int      `yaml:"weekly_tokens"`
+	Actions      []string `yaml:"actions"`
+}
+
+// Config returns the budget as rig settings.
+func (b *Budget) Config() *config.BudgetConfig {
+	return &config.BudgetConfig{
+		DailyUSD:     b.DailyUSD,
+		WeeklyUSD:    b.WeeklyUSD,
+		DailyTokens:  b.DailyTokens,
+		WeeklyTokens: b.WeeklyTokens,
+		Actions:      b.Actions,
+	}
+}
+
+// Load reads and validates the spec at path.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the operator's spec file
+	if err != nil {
+		return nil, err
+	}
+	spec, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// Parse parses and validates a spec. Unknown keys are errors, so a typo
+// isn't silently ignored.
+func Parse(data []byte) (*Spec, error) {
+	var spec Spec
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&spec); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// validate checks the spec, including each setting as its own file would
+// be checked when loaded.
+func (s *Spec) validate() error {
+	if err := checkRoles(s.Roles, TownRoles); err != nil {
+		return fmt.Errorf("roles: %w", err)
+	}
+	for _, name := range sortedKeys(s.Rigs) {
+		r := s.Rigs[name]
+		if r == nil || r.GitURL == "" {
+			return fmt.Errorf("rig %q: git_url is required", name)
+		}
+		if strings.ContainsAny(name, "/ ") {
+			return fmt.Errorf("rig %q: invalid name", name)
+		}
+		if err := checkRoles(r.Roles, RigRoles); err != nil {
+			return fmt.Errorf("rig %q: roles: %w", name, err)
+		}
+		if r.Polecats != nil && *r.Polecats < 0 {
+			return fmt.Errorf("rig %q: polecats must be non-negative", name)
+		}
+		if r.Budget != nil {
+			if err := config.ValidateBudgetConfig(r.Budget.Config()); err != nil {
+				return fmt.Errorf("rig %q: budget: %w", name, err)
+			}
+		}
+	}
+	if s.Schedules != nil {
+		schedules := &config.SchedulesConfig{Jobs: s.Schedules}
+		if err := config.ValidateSchedulesConfig(schedules); err != nil {
+			return fmt.Errorf("schedules: %w", err)
+		}
+		if _, err := scheduler.ParseJobs(schedules); err != nil {
+			return fmt.Errorf("schedules: %w", err)
+		}
+	}
+	for _, name := range sortedKeys(s.Integrations) {
+		if !contains(IntegrationSections, name) {
+			return fmt.Errorf("integrations: unknown integration %q (valid: %s)", name, strings.Join(IntegrationSections, ", "))
+		}
+		if err := checkSection(name, s.Integrations[name]); err != nil {
+			return fmt.Errorf("integrations.%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// checkRoles checks that roles are all valid.
+func checkRoles(roles, valid []string) error {
+	for _, role := range roles {
+		if !contains(valid, role) {
+			return fmt.Errorf("unknown role %q (valid: %s)", role, strings.Join(valid, ", "))
+		}
+	}
+	return nil
+}
+
+// checkSection checks a gastown.toml table as gastown.toml would be checked,
+// rejecting keys gastown.toml doesn't have.
+func checkSection(name string, table map[string]interface{}) error {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(map[string]interface{}{name: table}); err != nil {
+		return err
+	}
+	var cfg config.GastownConfig
+	md, err := toml.Decode(buf.String(), &cfg)
+	if err != nil {
+		return err
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return fmt.Errorf("unknown key %q", undecoded[0].String())
+	}
+	return config.ValidateGastownConfig(&cfg)
+}
+
+// contains reports whether list contains s.
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns m's keys in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
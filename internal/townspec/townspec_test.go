@@ -0,0 +1,216 @@
+package townspec
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+const testSpec = `
+roles: [deacon]
+rigs:
+  gastown:
+    git_url: https://github.com/steveyegge/gastown.git
+    roles: [witness, refinery]
+    polecats: 6
+    budget: {daily_usd: 50}
+  beads:
+    git_url: https://github.com/steveyegge/beads
+    prefix: bd
+    roles: [witness]
+  old:
+    git_url: https://example.com/old.git
+    parked: true
+schedules:
+  - name: standup
+    cron: "0 9 * * 1-5"
+    action: mail
+    target: mayor/
+    subject: Standup
+integrations:
+  jira:
+    url: https://acme.atlassian.net
+    token: secret://jira-token
+`
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"unknown key", "rigz: {}", "rigz"},
+		{"town role", "roles: [witness]", `unknown role "witness"`},
+		{"rig role", "rigs:\n  a: {git_url: x, roles: [mayor]}", `rig "a": roles`},
+		{"no git url", "rigs:\n  a: {roles: [witness]}", "git_url is required"},
+		{"negative polecats", "rigs:\n  a: {git_url: x, polecats: -1}", "non-negative"},
+		{"budget action", "rigs:\n  a: {git_url: x, budget: {actions: [nap]}}", "budget"},
+		{"bad cron", "schedules:\n  - {name: j, cron: nope, action: command, command: ls}", "schedules"},
+		{"unknown integration", "integrations:\n  myspace: {}", `unknown integration "myspace"`},
+		{"unknown integration key", "integrations:\n  jira: {url: x, colour: red}", `unknown key "jira.colour"`},
+		{"plain secret", "integrations:\n  jira: {url: x, token: hunter2}", "integrations.jira"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Empty(t *testing.T) {
+	spec, err := Parse(nil)
+	if err != nil {
+		t.Fatalf("Parse(empty) = %v", err)
+	}
+	if changes := Diff(spec, &State{}); len(changes) != 0 {
+		t.Errorf("an empty spec changed %v", changes)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	spec, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	state := &State{
+		Running: map[string]bool{"mayor": true, "gastown/witness": true, "old/witness": true, "beads/refinery": true},
+		Rigs: map[string]*RigState{
+			"gastown": {GitURL: "https://github.com/steveyegge/gastown", Polecats: 4},
+			"old":     {GitURL: "https://example.com/old.git"},
+			"other":   {GitURL: "https://example.com/other.git"},
+		},
+		Schedules: []config.ScheduledJob{
+			{Name: "standup", Cron: "0 10 * * 1-5", Action: "mail", Target: "mayor/", Subject: "Standup"},
+			{Name: "nightly", Cron: "@daily", Action: "command", Command: "gt doctor"},
+		},
+		Integrations: map[string]interface{}{
+			"jira": map[string]interface{}{"url": "https://acme.atlassian.net", "token": "secret://old-token"},
+		},
+	}
+
+	var got []string
+	for _, c := range Diff(spec, state) {
+		got = append(got, c.String())
+	}
+	want := []string{
+		"+ rig beads: https://github.com/steveyegge/beads",
+		"~ gastown polecats: 4 → 6",
+		"~ gastown budget: none → $50.00/day",
+		"~ schedule standup: cron",
+		"- schedule nightly",
+		"~ integration jira: token",
+		"- rig old: park",
+		"- mayor: stop",
+		"- beads/refinery: stop",
+		"+ deacon: start",
+		"+ beads/witness: start",
+		"+ gastown/refinery: start",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Diff =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestDiff_Commands(t *testing.T) {
+	spec, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	commands := make(map[string]string)
+	for _, c := range Diff(spec, &State{}) {
+		if !c.Settings() {
+			commands[c.Subject+" "+c.Detail] = strings.Join(c.Command, " ")
+		}
+	}
+	for change, want := range map[string]string{
+		"rig beads https://github.com/steveyegge/beads": "rig add beads https://github.com/steveyegge/beads --prefix bd",
+		"rig old park":           "rig park old",
+		"gastown/refinery start": "refinery start gastown",
+		"deacon start":           "deacon start",
+	} {
+		if commands[change] != want {
+			t.Errorf("%s runs %q, want %q", change, commands[change], want)
+		}
+	}
+	if _, ok := commands["old/witness start"]; ok {
+		t.Error("started an agent in a parked rig")
+	}
+}
+
+func TestDiff_Conflict(t *testing.T) {
+	spec := &Spec{Rigs: map[string]*Rig{"gastown": {GitURL: "https://example.com/fork.git"}}}
+	state := &State{Rigs: map[string]*RigState{"gastown": {GitURL: "https://github.com/steveyegge/gastown"}}}
+	changes := Diff(spec, state)
+	if len(changes) != 1 || changes[0].Op != OpConflict || changes[0].Settings() {
+		t.Errorf("Diff = %v, want one conflict", changes)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.WriteFile(config.GastownConfigPath(townRoot), []byte("model = \"opus\"\n\n[jira]\nurl = \"https://old\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if err := Write(townRoot, spec); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.Concurrency == nil || settings.Concurrency.Rigs["gastown"] != 6 {
+		t.Errorf("concurrency = %+v, want gastown capped at 6", settings.Concurrency)
+	}
+	rig, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, "gastown")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rig.Budget == nil || rig.Budget.DailyUSD != 50 {
+		t.Errorf("budget = %+v, want $50/day", rig.Budget)
+	}
+	schedules, err := config.LoadSchedulesConfig(config.SchedulesConfigPath(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedules.Jobs) != 1 || schedules.Jobs[0].Name != "standup" {
+		t.Errorf("jobs = %+v, want standup", schedules.Jobs)
+	}
+	tables, err := ReadGastownTOML(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jira, _ := tables["jira"].(map[string]interface{})
+	if tables["model"] != "opus" || jira["url"] != "https://acme.atlassian.net" || jira["token"] != "secret://jira-token" {
+		t.Errorf("gastown.toml = %v", tables)
+	}
+	info, err := os.Stat(config.GastownConfigPath(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("gastown.toml mode = %v, want it kept at 0600", info.Mode())
+	}
+
+	// Applied, the settings no longer differ
+	state := &State{
+		Rigs:         map[string]*RigState{"gastown": {GitURL: "https://github.com/steveyegge/gastown", Polecats: 6, Budget: rig.Budget}},
+		Schedules:    schedules.Jobs,
+		Integrations: tables,
+	}
+	for _, c := range Diff(spec, state) {
+		if c.Settings() {
+			t.Errorf("after Write, still changes %s", c)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package townspec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/BurntSushi/toml"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// ReadGastownTOML returns the town's gastown.toml as tables, by name. A
+// missing file has none.
+func ReadGastownTOML(townRoot string) (map[string]interface{}, error) {
+	tables := make(map[string]interface{})
+	if _, err := toml.DecodeFile(config.GastownConfigPath(townRoot), &tables); err != nil {
+		if os.IsNotExist(err) {
+			return tables, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", config.GastownConfigFile, err)
+	}
+	return tables, nil
+}
+
+// Write makes the spec's settings changes: each rig's polecat cap (the
+// town's settings/config.json) and budget (the rig's settings/config.json),
+// the schedules (settings/schedules.json), and the integrations
+// (gastown.toml, which is only rewritten if one differs; comments in it are
+// lost when it is). Rigs must already be added.
+func Write(townRoot string, spec *Spec) error {
+	if err := writePolecats(townRoot, spec); err != nil {
+		return err
+	}
+	for _, name := range sortedKeys(spec.Rigs) {
+		if b := spec.Rigs[name].Budget; b != nil {
+			if err := writeBudget(filepath.Join(townRoot, name), b); err != nil {
+				return fmt.Errorf("rig %s: %w", name, err)
+			}
+		}
+	}
+	if spec.Schedules != nil {
+		path := config.SchedulesConfigPath(townRoot)
+		schedules, err := config.LoadOrCreateSchedulesConfig(path)
+		if err != nil {
+			return err
+		}
+		schedules.Jobs = spec.Schedules
+		if err := config.SaveSchedulesConfig(path, schedules); err != nil {
+			return err
+		}
+	}
+	return writeIntegrations(townRoot, spec.Integrations)
+}
+
+// writePolecats sets the worker session caps of the rigs that declare one.
+func writePolecats(townRoot string, spec *Spec) error {
+	var declared bool
+	for _, r := range spec.Rigs {
+		declared = declared || r.Polecats != nil
+	}
+	if !declared {
+		return nil
+	}
+	path := config.TownSettingsPath(townRoot)
+	settings, err := config.LoadOrCreateTownSettings(path)
+	if err != nil {
+		return err
+	}
+	if settings.Concurrency == nil {
+		settings.Concurrency = &config.ConcurrencyConfig{}
+	}
+	for name, r := range spec.Rigs {
+		switch {
+		case r.Polecats == nil:
+		case *r.Polecats == 0:
+			delete(settings.Concurrency.Rigs, name)
+		default:
+			if settings.Concurrency.Rigs == nil {
+				settings.Concurrency.Rigs = make(map[string]int)
+			}
+			settings.Concurrency.Rigs[name] = *r.Polecats
+		}
+	}
+	return config.SaveTownSettings(path, settings)
+}
+
+// writeBudget sets a rig's budget.
+func writeBudget(rigPath string, b *Budget) error {
+	path := config.RigSettingsPath(rigPath)
+	settings, err := config.LoadRigSettings(path)
+	if errors.Is(err, config.ErrNotFound) {
+		settings, err = config.NewRigSettings(), nil
+	}
+	if err != nil {
+		return err
+	}
+	settings.Budget = normalizeBudget(b.Config())
+	return config.SaveRigSettings(path, settings)
+}
+
+// writeIntegrations replaces gastown.toml's tables with the spec's, if any
+// differs.
+func writeIntegrations(townRoot string, integrations map[string]map[string]interface{}) error {
+	tables, err := ReadGastownTOML(townRoot)
+	if err != nil {
+		return err
+	}
+	var changed bool
+	for name, table := range integrations {
+		if !reflect.DeepEqual(normalize(tables[name]), normalize(table)) {
+			tables[name] = table
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(tables); err != nil {
+		return fmt.Errorf("encoding %s: %w", config.GastownConfigFile, err)
+	}
+	var cfg config.GastownConfig
+	if _, err := toml.Decode(buf.String(), &cfg); err != nil {
+		return fmt.Errorf("encoding %s: %w", config.GastownConfigFile, err)
+	}
+	if err := config.ValidateGastownConfig(&cfg); err != nil {
+		return fmt.Errorf("%s: %w", config.GastownConfigFile, err)
+	}
+	path := config.GastownConfigPath(townRoot)
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	return util.AtomicWriteFile(path, buf.Bytes(), perm)
+}
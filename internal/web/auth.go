@@ -0,0 +1,90 @@
+package web
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/logging"
+)
+
+// APIKey is a resolved bearer token and the scope it grants
+// (config.APIScopeRead or config.APIScopeOperator).
+type APIKey struct {
+	Name  string
+	Token string
+	Scope string
+}
+
+// Auth checks bearer tokens against the server's API keys.
+//
+// Read routes are public when publicRead is set (no API keys are
+// configured), so the dashboard keeps working in a browser. Operator routes
+// always need an operator key, and are refused when there is none.
+type Auth struct {
+	keys       []APIKey
+	publicRead bool
+}
+
+// NewAuth creates an Auth over keys.
+func NewAuth(keys []APIKey, publicRead bool) *Auth {
+	return &Auth{keys: keys, publicRead: publicRead}
+}
+
+// Require wraps h so it is served only to requests whose bearer token
+// grants scope: 401 without a valid token, 403 when the key's scope is too
+// narrow or no key could grant it.
+func (a *Auth) Require(scope string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if scope == config.APIScopeRead && a.publicRead {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if !a.grantable(scope) {
+			http.Error(w, "Forbidden: no API key with "+scope+" scope is configured (server.api_keys)", http.StatusForbidden)
+			return
+		}
+		key := a.match(r)
+		if key == nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="gastown"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if !grants(key.Scope, scope) {
+			http.Error(w, "Forbidden: API key "+key.Name+" lacks "+scope+" scope", http.StatusForbidden)
+			return
+		}
+		logging.FromContext(r.Context(), logging.ComponentAPI).Debug("api key accepted", "key", key.Name, "scope", scope)
+		h.ServeHTTP(w, r)
+	})
+}
+
+// match returns the key the request's bearer token matches, or nil.
+func (a *Auth) match(r *http.Request) *APIKey {
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || got == "" {
+		return nil
+	}
+	for i := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(got), []byte(a.keys[i].Token)) == 1 {
+			return &a.keys[i]
+		}
+	}
+	return nil
+}
+
+// grantable reports whether any key grants scope.
+func (a *Auth) grantable(scope string) bool {
+	for _, k := range a.keys {
+		if grants(k.Scope, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// grants reports whether a key scoped have may use a route that needs want.
+func grants(have, want string) bool {
+	return have == want || have == config.APIScopeOperator
+}
@@ -0,0 +1,47 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestAuthRequire(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	keys := []APIKey{
+		{Name: "ci", Token: "read-key", Scope: config.APIScopeRead},
+		{Name: "ops", Token: "op-key", Scope: config.APIScopeOperator},
+	}
+
+	tests := []struct {
+		name   string
+		auth   *Auth
+		scope  string
+		header string
+		want   int
+	}{
+		{"read without key", NewAuth(keys, false), config.APIScopeRead, "", http.StatusUnauthorized},
+		{"read with wrong key", NewAuth(keys, false), config.APIScopeRead, "Bearer nope", http.StatusUnauthorized},
+		{"read with read key", NewAuth(keys, false), config.APIScopeRead, "Bearer read-key", http.StatusOK},
+		{"read with operator key", NewAuth(keys, false), config.APIScopeRead, "Bearer op-key", http.StatusOK},
+		{"public read", NewAuth(nil, true), config.APIScopeRead, "", http.StatusOK},
+		{"operator with read key", NewAuth(keys, false), config.APIScopeOperator, "Bearer read-key", http.StatusForbidden},
+		{"operator without key", NewAuth(keys, true), config.APIScopeOperator, "", http.StatusUnauthorized},
+		{"operator with operator key", NewAuth(keys, false), config.APIScopeOperator, "Bearer op-key", http.StatusOK},
+		{"operator with no operator keys", NewAuth(keys[:1], false), config.APIScopeOperator, "Bearer read-key", http.StatusForbidden},
+		{"operator with no keys", NewAuth(nil, true), config.APIScopeOperator, "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/api/events", nil)
+		if tt.header != "" {
+			req.Header.Set("Authorization", tt.header)
+		}
+		w := httptest.NewRecorder()
+		tt.auth.Require(tt.scope, ok).ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package web
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/discord"
+	"github.com/steveyegge/gastown/internal/logging"
+)
+
+// maxDiscordBody bounds the size of an interaction request.
+const maxDiscordBody = 64 * 1024
+
+// DiscordKey returns the Discord application's public key ("" = commands
+// aren't configured).
+type DiscordKey func() (string, error)
+
+// DiscordCommandRunner runs a verified slash command and returns the
+// response.
+type DiscordCommandRunner func(i *discord.Interaction) discord.Response
+
+// DiscordInteractionHandler serves the Discord application's interactions
+// endpoint at POST /discord/interactions. Requests are verified with the
+// application's public key before the command runs; Discord's pings are
+// answered directly.
+type DiscordInteractionHandler struct {
+	key DiscordKey
+	run DiscordCommandRunner
+	now func() time.Time
+}
+
+// NewDiscordInteractionHandler creates an interactions handler.
+func NewDiscordInteractionHandler(key DiscordKey, run DiscordCommandRunner) *DiscordInteractionHandler {
+	return &DiscordInteractionHandler{key: key, run: run, now: time.Now}
+}
+
+// ServeHTTP handles an interaction request.
+func (h *DiscordInteractionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	key, err := h.key()
+	if err != nil {
+		logging.FromContext(r.Context(), logging.ComponentAPI).Error("loading Discord public key", logging.KeyError, err)
+		http.Error(w, "Discord not configured", http.StatusInternalServerError)
+		return
+	}
+	if key == "" {
+		http.Error(w, "Discord commands not configured", http.StatusForbidden)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxDiscordBody))
+	if err != nil {
+		http.Error(w, "Failed to read body", http.StatusBadRequest)
+		return
+	}
+	if err := discord.Verify(key, r.Header, body, h.now()); err != nil {
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+	i, err := discord.ParseInteraction(body)
+	if err != nil {
+		http.Error(w, "Invalid interaction", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	switch i.Type {
+	case discord.InteractionPing:
+		_, _ = w.Write([]byte(`{"type":1}`))
+	case discord.InteractionCommand:
+		_ = json.NewEncoder(w).Encode(h.run(i))
+	default:
+		http.Error(w, "Unsupported interaction", http.StatusBadRequest)
+	}
+}
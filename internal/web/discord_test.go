@@ -0,0 +1,73 @@
+package web
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/discord"
+)
+
+func TestDiscordInteractionHandler(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	_, other, _ := ed25519.GenerateKey(nil)
+	const ping = `{"type":1}`
+	const command = `{"type":2,"user":{"id":"7","username":"ann"},"data":{"name":"gt","options":[{"name":"status","type":1}]}}`
+	sign := func(key ed25519.PrivateKey, body string) http.Header {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		h := http.Header{}
+		h.Set("X-Signature-Timestamp", ts)
+		h.Set("X-Signature-Ed25519", hex.EncodeToString(ed25519.Sign(key, []byte(ts+body))))
+		return h
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		key    string
+		keyErr error
+		body   string
+		header http.Header
+		want   int
+		reply  string
+	}{
+		{"ping", "POST", hex.EncodeToString(pub), nil, ping, sign(priv, ping), http.StatusOK, `{"type":1}`},
+		{"command", "POST", hex.EncodeToString(pub), nil, command, sign(priv, command), http.StatusOK, `"content":"ran status"`},
+		{"bad signature", "POST", hex.EncodeToString(pub), nil, command, sign(other, command), http.StatusUnauthorized, ""},
+		{"not configured", "POST", "", nil, command, sign(priv, command), http.StatusForbidden, ""},
+		{"config error", "POST", "", errors.New("bad toml"), command, sign(priv, command), http.StatusInternalServerError, ""},
+		{"wrong method", "GET", hex.EncodeToString(pub), nil, command, sign(priv, command), http.StatusMethodNotAllowed, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran := false
+			handler := NewDiscordInteractionHandler(
+				func() (string, error) { return tt.key, tt.keyErr },
+				func(i *discord.Interaction) discord.Response {
+					ran = true
+					verb, _ := i.Subcommand()
+					return discord.Response{Text: "ran " + verb}
+				},
+			)
+			req := httptest.NewRequest(tt.method, "/discord/interactions", strings.NewReader(tt.body))
+			req.Header = tt.header
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			if tt.reply != "" && !strings.Contains(w.Body.String(), tt.reply) {
+				t.Errorf("body = %s, want %s", w.Body.String(), tt.reply)
+			}
+			if ran != (tt.name == "command") {
+				t.Errorf("command ran = %v", ran)
+			}
+		})
+	}
+}
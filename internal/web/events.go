@@ -0,0 +1,88 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Limits on the number of events /api/events returns.
+const (
+	defaultEventsLimit = 100
+	maxEventsLimit     = 1000
+)
+
+// EventQuerier returns the events in the town's event log that match f.
+type EventQuerier func(f events.Filter) ([]events.Event, error)
+
+// EventsResponse is the JSON body served at /api/events.
+type EventsResponse struct {
+	Events []events.Event `json:"events"`
+}
+
+// EventsHandler serves the town's event/audit log at GET /api/events,
+// oldest first.
+//
+// Query parameters:
+//
+//	type   only these event types (comma-separated)
+//	actor  only actors with this address prefix
+//	since  only events within this window (e.g., 1h, 7d)
+//	limit  the most recent events to return (default 100, at most 1000)
+//
+// The log holds tool arguments and possibly prompt bodies, so the server
+// must serve it behind Auth.Require(config.APIScopeOperator, ...).
+type EventsHandler struct {
+	query EventQuerier
+}
+
+// NewEventsHandler creates an events handler.
+func NewEventsHandler(query EventQuerier) *EventsHandler {
+	return &EventsHandler{query: query}
+}
+
+// ServeHTTP handles an events request.
+func (h *EventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	filter := events.Filter{Actor: q.Get("actor"), Limit: defaultEventsLimit}
+	if t := q.Get("type"); t != "" {
+		filter.Types = strings.Split(t, ",")
+	}
+	if s := q.Get("since"); s != "" {
+		d, err := parseWindow(s)
+		if err != nil {
+			http.Error(w, "Invalid since: want a duration like 24h or 7d", http.StatusBadRequest)
+			return
+		}
+		filter.Since = time.Now().Add(-d)
+	}
+	if l := q.Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n <= 0 || n > maxEventsLimit {
+			http.Error(w, "Invalid limit: want 1 to 1000", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = n
+	}
+
+	found, err := h.query(filter)
+	if err != nil {
+		http.Error(w, "Failed to query events", http.StatusInternalServerError)
+		return
+	}
+	if found == nil {
+		found = []events.Event{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(EventsResponse{Events: found})
+}
@@ -0,0 +1,61 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestEventsHandler(t *testing.T) {
+	var got events.Filter
+	handler := NewEventsHandler(func(f events.Filter) ([]events.Event, error) {
+		got = f
+		return []events.Event{{Type: events.TypeToolExec, Actor: "gastown/polecats/toast"}}, nil
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/api/events?type=tool_exec,prompt_sent&actor=gastown/&since=1d&limit=10", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if len(got.Types) != 2 || got.Actor != "gastown/" || got.Since.IsZero() || got.Limit != 10 {
+		t.Errorf("filter = %+v", got)
+	}
+	var resp EventsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Events) != 1 || resp.Events[0].Type != events.TypeToolExec {
+		t.Errorf("events = %+v", resp.Events)
+	}
+
+	for _, tt := range []struct {
+		method, query string
+		want          int
+	}{
+		{"GET", "?since=soon", http.StatusBadRequest},
+		{"GET", "?limit=5000", http.StatusBadRequest},
+		{"POST", "", http.StatusMethodNotAllowed},
+		{"GET", "", http.StatusOK},
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(tt.method, "/api/events"+tt.query, nil))
+		if w.Code != tt.want {
+			t.Errorf("%s /api/events%s = %d, want %d", tt.method, tt.query, w.Code, tt.want)
+		}
+	}
+	if got.Limit != defaultEventsLimit {
+		t.Errorf("default limit = %d, want %d", got.Limit, defaultEventsLimit)
+	}
+
+	failing := NewEventsHandler(func(events.Filter) ([]events.Event, error) { return nil, errors.New("disk") })
+	w = httptest.NewRecorder()
+	failing.ServeHTTP(w, httptest.NewRequest("GET", "/api/events", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("failing query = %d, want 500", w.Code)
+	}
+}
@@ -4,17 +4,23 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/activity"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/secret"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // LiveConvoyFetcher fetches convoy data from beads.
 type LiveConvoyFetcher struct {
+	townRoot  string
 	townBeads string
 }
 
@@ -26,10 +32,26 @@ func NewLiveConvoyFetcher() (*LiveConvoyFetcher, error) {
 	}
 
 	return &LiveConvoyFetcher{
+		townRoot:  townRoot,
 		townBeads: filepath.Join(townRoot, ".beads"),
 	}, nil
 }
 
+// activityThresholds returns the activity thresholds for a rig from
+// gastown.toml, with the rig's file overriding the town's. rig "" returns
+// the town's thresholds.
+func (f *LiveConvoyFetcher) activityThresholds(rig string) activity.Thresholds {
+	rigPath := ""
+	if rig != "" {
+		rigPath = filepath.Join(f.townRoot, rig)
+	}
+	var t activity.Thresholds // zero values fall back to the defaults
+	if cfg, err := config.LoadRigGastownConfig(f.townRoot, rigPath); err == nil {
+		t.Active, t.Stale = cfg.Activity.Durations()
+	}
+	return t
+}
+
 
 // FetchConvoys fetches all open convoys with their activity data.
 func (f *LiveConvoyFetcher) FetchConvoys() ([]ConvoyRow, error) {
@@ -93,17 +115,17 @@ func (f *LiveConvoyFetcher) FetchConvoys() ([]ConvoyRow, error) {
 		// Calculate activity info from most recent worker activity
 		if !mostRecentActivity.IsZero() {
 			// Have active tmux session activity from assigned workers
-			row.LastActivity = activity.Calculate(mostRecentActivity)
+			row.LastActivity = activity.CalculateWith(mostRecentActivity, f.activityThresholds(""))
 		} else if !hasAssignee {
 			// No assignees found in beads - try fallback to any running polecat activity
 			// This handles cases where bd update --assignee didn't persist or wasn't returned
 			if polecatActivity := f.getAllPolecatActivity(); polecatActivity != nil {
-				info := activity.Calculate(*polecatActivity)
+				info := activity.CalculateWith(*polecatActivity, f.activityThresholds(""))
 				info.FormattedAge = info.FormattedAge + " (polecat active)"
 				row.LastActivity = info
 			} else if !mostRecentUpdated.IsZero() {
 				// Fall back to issue updated_at if no polecats running
-				info := activity.Calculate(mostRecentUpdated)
+				info := activity.CalculateWith(mostRecentUpdated, f.activityThresholds(""))
 				info.FormattedAge = info.FormattedAge + " (unassigned)"
 				row.LastActivity = info
 			} else {
@@ -331,7 +353,7 @@ func (f *LiveConvoyFetcher) getSessionActivityForAssignee(assignee string) *time
 	polecat := parts[2]
 
 	// Construct session name
-	sessionName := fmt.Sprintf("gt-%s-%s", rig, polecat)
+	sessionName := session.PolecatSessionName(rig, polecat)
 
 	// Query tmux for session activity
 	// Format: session_activity returns unix timestamp
@@ -622,7 +644,7 @@ func (f *LiveConvoyFetcher) FetchPolecats() ([]PolecatRow, error) {
 		sessionName := parts[0]
 
 		// Filter for gt-<rig>-<polecat> pattern
-		if !strings.HasPrefix(sessionName, "gt-") {
+		if !session.HasRigPrefix(sessionName) {
 			continue
 		}
 
@@ -655,18 +677,59 @@ func (f *LiveConvoyFetcher) FetchPolecats() ([]PolecatRow, error) {
 			statusHint = f.getPolecatStatusHint(sessionName)
 		}
 
-		polecats = append(polecats, PolecatRow{
+		row := PolecatRow{
 			Name:         polecat,
 			Rig:          rig,
 			SessionID:    sessionName,
-			LastActivity: activity.Calculate(activityTime),
+			LastActivity: activity.CalculateWith(activityTime, f.activityThresholds(rig)),
 			StatusHint:   statusHint,
-		})
+		}
+		if wt := f.workerWorktree(rig, polecat); wt != "" {
+			row.GitStatus, row.Changes = getWorktreeGitStatus(wt, polecat != "refinery")
+		}
+		polecats = append(polecats, row)
 	}
 
 	return polecats, nil
 }
 
+// workerWorktree returns a polecat's worktree (or the refinery's clone), or
+// "" if it can't be found.
+func (f *LiveConvoyFetcher) workerWorktree(rig, polecat string) string {
+	var paths []string
+	if polecat == "refinery" {
+		paths = []string{filepath.Join(f.townRoot, rig, "refinery", "rig")}
+	} else {
+		// polecats/<name>/<rig>/, or polecats/<name>/ for older polecats
+		paths = []string{
+			filepath.Join(f.townRoot, rig, "polecats", polecat, rig),
+			filepath.Join(f.townRoot, rig, "polecats", polecat),
+		}
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// getWorktreeGitStatus summarizes a worktree's git status and, if withDiff,
+// what its branch has changed since the default branch. Either is "" if
+// git can't report it.
+func getWorktreeGitStatus(path string, withDiff bool) (status, changes string) {
+	g := git.NewGit(path)
+	if s, err := g.Status(); err == nil {
+		status = s.Summary()
+	}
+	if withDiff {
+		if d, err := g.DiffStat("origin/" + g.RemoteDefaultBranch()); err == nil {
+			changes = d.String()
+		}
+	}
+	return status, changes
+}
+
 // getPolecatStatusHint captures the last non-empty line from a polecat's pane.
 func (f *LiveConvoyFetcher) getPolecatStatusHint(sessionName string) string {
 	cmd := exec.Command("tmux", "capture-pane", "-t", sessionName, "-p", "-J")
@@ -677,7 +740,7 @@ func (f *LiveConvoyFetcher) getPolecatStatusHint(sessionName string) string {
 	}
 
 	// Get last non-empty line
-	lines := strings.Split(stdout.String(), "\n")
+	lines := strings.Split(secret.Redact(stdout.String()), "\n")
 	for i := len(lines) - 1; i >= 0; i-- {
 		line := strings.TrimSpace(lines[i])
 		if line != "" {
@@ -723,7 +786,7 @@ func truncateStatusHint(line string) string {
 // Format: gt-<rig>-<polecat> -> (rig, polecat, true)
 // Returns ("", "", false) if the format is invalid.
 func parsePolecatSessionName(sessionName string) (rig, polecat string, ok bool) {
-	if !strings.HasPrefix(sessionName, "gt-") {
+	if !session.HasRigPrefix(sessionName) {
 		return "", "", false
 	}
 	parts := strings.SplitN(sessionName, "-", 3)
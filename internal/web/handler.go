@@ -3,6 +3,8 @@ package web
 import (
 	"html/template"
 	"net/http"
+
+	"github.com/steveyegge/gastown/internal/logging"
 )
 
 // ConvoyFetcher defines the interface for fetching convoy data.
@@ -15,9 +17,13 @@ type ConvoyFetcher interface {
 // ConvoyHandler handles HTTP requests for the convoy dashboard.
 type ConvoyHandler struct {
 	fetcher  ConvoyFetcher
+	towns    TownFetcher
 	template *template.Template
 }
 
+// TownFetcher returns the federated towns shown on the dashboard.
+type TownFetcher func() ([]TownRow, error)
+
 // NewConvoyHandler creates a new convoy handler with the given fetcher.
 func NewConvoyHandler(fetcher ConvoyFetcher) (*ConvoyHandler, error) {
 	tmpl, err := LoadTemplates()
@@ -31,10 +37,17 @@ func NewConvoyHandler(fetcher ConvoyFetcher) (*ConvoyHandler, error) {
 	}, nil
 }
 
+// SetTownFetcher enables the federated towns section of the dashboard.
+func (h *ConvoyHandler) SetTownFetcher(towns TownFetcher) {
+	h.towns = towns
+}
+
 // ServeHTTP handles GET / requests and renders the convoy dashboard.
 func (h *ConvoyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	log := logging.FromContext(r.Context(), logging.ComponentAPI)
 	convoys, err := h.fetcher.FetchConvoys()
 	if err != nil {
+		log.Error("fetching convoys", logging.KeyError, err)
 		http.Error(w, "Failed to fetch convoys", http.StatusInternalServerError)
 		return
 	}
@@ -42,24 +55,35 @@ func (h *ConvoyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	mergeQueue, err := h.fetcher.FetchMergeQueue()
 	if err != nil {
 		// Non-fatal: show convoys even if merge queue fails
+		logging.WarnIf(log, "fetching merge queue", err)
 		mergeQueue = nil
 	}
 
 	polecats, err := h.fetcher.FetchPolecats()
 	if err != nil {
 		// Non-fatal: show convoys even if polecats fail
+		logging.WarnIf(log, "fetching polecats", err)
 		polecats = nil
 	}
 
+	var towns []TownRow
+	if h.towns != nil {
+		// Non-fatal: show convoys even if federation fails
+		towns, err = h.towns()
+		logging.WarnIf(log, "fetching federated towns", err)
+	}
+
 	data := ConvoyData{
 		Convoys:    convoys,
 		MergeQueue: mergeQueue,
 		Polecats:   polecats,
+		Towns:      towns,
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 
 	if err := h.template.ExecuteTemplate(w, "convoy.html", data); err != nil {
+		log.Error("rendering dashboard", logging.KeyError, err)
 		http.Error(w, "Failed to render template", http.StatusInternalServerError)
 		return
 	}
@@ -328,6 +328,8 @@ func TestConvoyHandler_PolecatWorkersRendering(t *testing.T) {
 				SessionID:    "gt-roxas-dag",
 				LastActivity: activity.Calculate(time.Now().Add(-30 * time.Second)),
 				StatusHint:   "Running tests...",
+				GitStatus:    "2 unstaged, ahead 1",
+				Changes:      "3 files, +120 -4",
 			},
 			{
 				Name:         "nux",
@@ -377,6 +379,12 @@ func TestConvoyHandler_PolecatWorkersRendering(t *testing.T) {
 	if !strings.Contains(body, "Running tests...") {
 		t.Error("Response should contain status hint")
 	}
+	if !strings.Contains(body, "2 unstaged, ahead 1") {
+		t.Error("Response should contain worktree git status")
+	}
+	if !strings.Contains(body, "3 files,") {
+		t.Error("Response should contain worker diff stats")
+	}
 
 	// Check activity colors (dag should be green, nux should be yellow/red)
 	if !strings.Contains(body, "activity-green") {
@@ -384,6 +392,37 @@ func TestConvoyHandler_PolecatWorkersRendering(t *testing.T) {
 	}
 }
 
+func TestConvoyHandler_FederatedTownsRendering(t *testing.T) {
+	handler, err := NewConvoyHandler(&MockConvoyFetcher{})
+	if err != nil {
+		t.Fatalf("NewConvoyHandler() error = %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if strings.Contains(w.Body.String(), "Federated Towns") {
+		t.Error("Response should not contain federated towns section without a town fetcher")
+	}
+
+	handler.SetTownFetcher(func() ([]TownRow, error) {
+		return []TownRow{
+			{Name: "hq", Workers: 3, MaxWorkers: 8, Sessions: 6, CostToday: 4.5, ColorClass: "mq-green"},
+			{Name: "west", URL: "http://west:8080", Error: "connection refused", ColorClass: "mq-red"},
+		}, nil
+	})
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	body := w.Body.String()
+
+	for _, want := range []string{"Federated Towns", "hq", "3/8", "$4.50", "healthy", "west", "connection refused"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Response should contain %q", want)
+		}
+	}
+}
+
 // Integration tests for work status rendering
 
 func TestConvoyHandler_WorkStatusRendering(t *testing.T) {
@@ -0,0 +1,44 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/steveyegge/gastown/internal/health"
+)
+
+// HealthChecker returns the town's current health.
+type HealthChecker func() (*health.Town, error)
+
+// HealthHandler serves the town's rolled-up health at GET /health/town:
+// the town's state, every rig's, and every session's, with reasons. The
+// status is 200 while the town is ok or degraded and 503 when it is down,
+// so uptime probes can watch it without parsing the body.
+type HealthHandler struct {
+	check HealthChecker
+}
+
+// NewHealthHandler creates a health handler.
+func NewHealthHandler(check HealthChecker) *HealthHandler {
+	return &HealthHandler{check: check}
+}
+
+// ServeHTTP handles a health request.
+func (h *HealthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	town, err := h.check()
+	if err != nil {
+		http.Error(w, "Failed to check health", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if town.State == health.StateDown {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(town)
+}
@@ -0,0 +1,52 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/health"
+)
+
+func TestHealthHandler(t *testing.T) {
+	now := time.Now()
+	agents := func(deacon bool) []health.Observation {
+		return []health.Observation{{Agent: "deacon/", Session: "hq-deacon", Role: "deacon", Required: true, Running: deacon}}
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		town   *health.Town
+		err    error
+		want   int
+	}{
+		{"ok", "GET", health.Aggregate(agents(true), nil, now), nil, http.StatusOK},
+		{"down", "GET", health.Aggregate(agents(false), nil, now), nil, http.StatusServiceUnavailable},
+		{"check fails", "GET", nil, errors.New("boom"), http.StatusInternalServerError},
+		{"wrong method", "POST", nil, nil, http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHealthHandler(func() (*health.Town, error) { return tt.town, tt.err })
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, "/health/town", nil))
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			if tt.town == nil {
+				return
+			}
+			var got health.Town
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if got.State != tt.town.State {
+				t.Errorf("state = %s, want %s", got.State, tt.town.State)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package web
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/linear"
+	"github.com/steveyegge/gastown/internal/logging"
+)
+
+// LinearSecret returns the Linear webhook's signing secret ("" = webhooks
+// aren't configured).
+type LinearSecret func() (string, error)
+
+// LinearWebhookNotifier handles a verified Linear webhook delivery.
+type LinearWebhookNotifier func(w *linear.Webhook) error
+
+// LinearWebhookHandler receives the Linear workspace's webhook at POST
+// /linear/webhook. Deliveries are verified with the webhook's signing
+// secret before being passed on.
+type LinearWebhookHandler struct {
+	secret LinearSecret
+	notify LinearWebhookNotifier
+	now    func() time.Time
+}
+
+// NewLinearWebhookHandler creates a Linear webhook handler.
+func NewLinearWebhookHandler(secret LinearSecret, notify LinearWebhookNotifier) *LinearWebhookHandler {
+	return &LinearWebhookHandler{secret: secret, notify: notify, now: time.Now}
+}
+
+// ServeHTTP handles a webhook delivery.
+func (h *LinearWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	secret, err := h.secret()
+	if err != nil {
+		logging.FromContext(r.Context(), logging.ComponentAPI).Error("loading Linear webhook secret", logging.KeyError, err)
+		http.Error(w, "Linear not configured", http.StatusInternalServerError)
+		return
+	}
+	if secret == "" {
+		http.Error(w, "Linear webhooks not configured", http.StatusForbidden)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "Failed to read body", http.StatusBadRequest)
+		return
+	}
+	if err := linear.Verify(secret, r.Header, body, h.now()); err != nil {
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+	delivery, err := linear.ParseWebhook(body)
+	if err != nil {
+		logging.FromContext(r.Context(), logging.ComponentAPI).Info("invalid Linear webhook payload", logging.KeyError, err)
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.notify(delivery); err != nil {
+		logging.FromContext(r.Context(), logging.ComponentAPI).Error("handling Linear webhook", logging.KeyError, err)
+		http.Error(w, "Failed to handle delivery", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
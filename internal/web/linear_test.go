@@ -0,0 +1,70 @@
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/linear"
+)
+
+func TestLinearWebhookHandler(t *testing.T) {
+	body := fmt.Sprintf(`{"action":"create","type":"Issue","data":{"identifier":"ENG-1","state":{"type":"unstarted"}},"webhookTimestamp":%d}`, time.Now().UnixMilli())
+	sign := func(secret string) http.Header {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		h := http.Header{}
+		h.Set("Linear-Signature", hex.EncodeToString(mac.Sum(nil)))
+		return h
+	}
+
+	tests := []struct {
+		name      string
+		method    string
+		secret    string
+		secretErr error
+		notifyErr error
+		header    http.Header
+		want      int
+		notified  bool
+	}{
+		{"delivered", "POST", "s3cret", nil, nil, sign("s3cret"), http.StatusAccepted, true},
+		{"bad signature", "POST", "s3cret", nil, nil, sign("other"), http.StatusUnauthorized, false},
+		{"not configured", "POST", "", nil, nil, sign("s3cret"), http.StatusForbidden, false},
+		{"config error", "POST", "", errors.New("bad toml"), nil, sign("s3cret"), http.StatusInternalServerError, false},
+		{"handler error", "POST", "s3cret", nil, errors.New("bd failed"), sign("s3cret"), http.StatusInternalServerError, true},
+		{"wrong method", "GET", "s3cret", nil, nil, sign("s3cret"), http.StatusMethodNotAllowed, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notified := false
+			handler := NewLinearWebhookHandler(
+				func() (string, error) { return tt.secret, tt.secretErr },
+				func(w *linear.Webhook) error {
+					notified = true
+					if w.Issue == nil || w.Issue.Identifier != "ENG-1" {
+						t.Errorf("delivered issue = %+v", w.Issue)
+					}
+					return tt.notifyErr
+				},
+			)
+			req := httptest.NewRequest(tt.method, "/linear/webhook", strings.NewReader(body))
+			req.Header = tt.header
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			if notified != tt.notified {
+				t.Errorf("notified = %v, want %v", notified, tt.notified)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package web
+
+import (
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/logging"
+)
+
+// RequestIDHeader carries a request's correlation ID. An ID sent by the
+// client (e.g., a federated town) is kept so records line up across towns.
+const RequestIDHeader = "X-Request-ID"
+
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// WithRequestLogging wraps h to log every request (component=api) under a
+// request ID, returned in the X-Request-ID header. Handlers log with the
+// request's ID via logging.FromContext(r.Context(), logging.ComponentAPI).
+func WithRequestLogging(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = logging.NewRequestID()
+		}
+		log := logging.WithRequest(logging.For(logging.ComponentAPI), id)
+		w.Header().Set(RequestIDHeader, id)
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		h.ServeHTTP(rec, r.WithContext(logging.NewContext(r.Context(), log)))
+
+		// Routine requests (the dashboard polls) only show at debug level
+		level := slog.LevelDebug
+		switch {
+		case rec.status >= 500:
+			level = slog.LevelError
+		case rec.status >= 400:
+			level = slog.LevelInfo
+		}
+		log.Log(r.Context(), level, "request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"duration", time.Since(start).Round(time.Millisecond))
+	})
+}
+
+// statusRecorder records the status code a handler writes.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,48 @@
+package web
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/logging"
+)
+
+func TestWithRequestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	h, _ := logging.NewHandler(&buf, logging.Config{Level: "debug"})
+	slog.SetDefault(slog.New(h))
+
+	handler := WithRequestLogging(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context(), logging.ComponentAPI).Info("handling")
+		http.Error(w, "nope", http.StatusTeapot)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/stats", nil)
+	req.Header.Set(RequestIDHeader, "town-a.42")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(RequestIDHeader); got != "town-a.42" {
+		t.Errorf("%s = %q, want the client's ID", RequestIDHeader, got)
+	}
+	out := buf.String()
+	if strings.Count(out, "request_id=town-a.42") != 2 || !strings.Contains(out, "component=api") ||
+		!strings.Contains(out, "status=418") || !strings.Contains(out, "path=/api/stats") {
+		t.Errorf("log = %q, want handler and request records with the ID", out)
+	}
+
+	// An unusable client ID is replaced
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "bad id\n")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if got := rec.Header().Get(RequestIDHeader); got == "" || got == "bad id\n" {
+		t.Errorf("%s = %q, want a generated ID", RequestIDHeader, got)
+	}
+}
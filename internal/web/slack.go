@@ -0,0 +1,72 @@
+package web
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/logging"
+	"github.com/steveyegge/gastown/internal/slack"
+)
+
+// maxSlackBody bounds the size of a slash command request.
+const maxSlackBody = 64 * 1024
+
+// SlackSecret returns the Slack app's signing secret ("" = slash commands
+// aren't configured).
+type SlackSecret func() (string, error)
+
+// SlackCommandRunner runs a verified slash command and returns the reply.
+type SlackCommandRunner func(c *slack.Command) slack.Reply
+
+// SlackCommandHandler serves the Slack app's slash command at POST
+// /slack/commands. Requests are verified with the app's signing secret
+// before the command runs.
+type SlackCommandHandler struct {
+	secret SlackSecret
+	run    SlackCommandRunner
+	now    func() time.Time
+}
+
+// NewSlackCommandHandler creates a slash command handler.
+func NewSlackCommandHandler(secret SlackSecret, run SlackCommandRunner) *SlackCommandHandler {
+	return &SlackCommandHandler{secret: secret, run: run, now: time.Now}
+}
+
+// ServeHTTP handles a slash command request.
+func (h *SlackCommandHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	secret, err := h.secret()
+	if err != nil {
+		logging.FromContext(r.Context(), logging.ComponentAPI).Error("resolving Slack signing secret", logging.KeyError, err)
+		http.Error(w, "Slack not configured", http.StatusInternalServerError)
+		return
+	}
+	if secret == "" {
+		http.Error(w, "Slack commands not configured", http.StatusForbidden)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSlackBody))
+	if err != nil {
+		http.Error(w, "Failed to read body", http.StatusBadRequest)
+		return
+	}
+	if err := slack.Verify(secret, r.Header, body, h.now()); err != nil {
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+	c, err := slack.ParseCommand(body)
+	if err != nil {
+		http.Error(w, "Invalid command", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.run(c))
+}
@@ -0,0 +1,74 @@
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/slack"
+)
+
+func TestSlackCommandHandler(t *testing.T) {
+	const body = "command=%2Fgt&text=status&user_id=U1&user_name=ann"
+	sign := func(secret string) http.Header {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte("v0:" + ts + ":" + body))
+		h := http.Header{}
+		h.Set("X-Slack-Request-Timestamp", ts)
+		h.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+		return h
+	}
+
+	tests := []struct {
+		name      string
+		method    string
+		secret    string
+		secretErr error
+		header    http.Header
+		want      int
+	}{
+		{"valid", "POST", "s3cret", nil, sign("s3cret"), http.StatusOK},
+		{"bad signature", "POST", "s3cret", nil, sign("other"), http.StatusUnauthorized},
+		{"not configured", "POST", "", nil, sign(""), http.StatusForbidden},
+		{"secret unresolvable", "POST", "", errors.New("no such secret"), sign(""), http.StatusInternalServerError},
+		{"wrong method", "GET", "s3cret", nil, sign("s3cret"), http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ran *slack.Command
+			handler := NewSlackCommandHandler(
+				func() (string, error) { return tt.secret, tt.secretErr },
+				func(c *slack.Command) slack.Reply { ran = c; return slack.Reply{Text: "ok", InChannel: true} },
+			)
+			req := httptest.NewRequest(tt.method, "/slack/commands", strings.NewReader(body))
+			req.Header = tt.header
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			if tt.want != http.StatusOK {
+				if ran != nil {
+					t.Error("command ran for a rejected request")
+				}
+				return
+			}
+			var reply map[string]string
+			if err := json.NewDecoder(w.Body).Decode(&reply); err != nil {
+				t.Fatal(err)
+			}
+			if ran == nil || ran.Text != "status" || reply["response_type"] != "in_channel" || reply["text"] != "ok" {
+				t.Errorf("ran %+v, replied %v", ran, reply)
+			}
+		})
+	}
+}
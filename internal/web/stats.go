@@ -0,0 +1,104 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/outcome"
+)
+
+// OutcomeLoader returns the town's assignment outcomes.
+type OutcomeLoader func() ([]*outcome.Outcome, error)
+
+// StatsResponse is the JSON body served at /api/stats.
+type StatsResponse struct {
+	By     string           `json:"by,omitempty"`
+	Rig    string           `json:"rig,omitempty"`
+	Since  *time.Time       `json:"since,omitempty"`
+	Groups []*outcome.Stats `json:"groups"`
+}
+
+// StatsHandler serves aggregated outcome stats at GET /api/stats.
+//
+// Query parameters:
+//
+//	by     group by role, worker, rig, or model (default: one total)
+//	rig    only assignments in this rig
+//	since  only assignments started within this window (e.g., 24h, 7d)
+type StatsHandler struct {
+	load OutcomeLoader
+}
+
+// NewStatsHandler creates a stats handler.
+func NewStatsHandler(load OutcomeLoader) *StatsHandler {
+	return &StatsHandler{load: load}
+}
+
+// ServeHTTP handles a stats request.
+func (h *StatsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	resp := StatsResponse{By: q.Get("by"), Rig: q.Get("rig")}
+	switch resp.By {
+	case "", outcome.ByRole, outcome.ByWorker, outcome.ByRig, outcome.ByModel:
+	default:
+		http.Error(w, "Invalid by: want role, worker, rig, or model", http.StatusBadRequest)
+		return
+	}
+	var window time.Duration
+	if s := q.Get("since"); s != "" {
+		d, err := parseWindow(s)
+		if err != nil {
+			http.Error(w, "Invalid since: want a duration like 24h or 7d", http.StatusBadRequest)
+			return
+		}
+		window = d
+	}
+
+	outcomes, err := h.load()
+	if err != nil {
+		http.Error(w, "Failed to load outcomes", http.StatusInternalServerError)
+		return
+	}
+	if resp.Rig != "" {
+		var filtered []*outcome.Outcome
+		for _, o := range outcomes {
+			if o.Rig == resp.Rig {
+				filtered = append(filtered, o)
+			}
+		}
+		outcomes = filtered
+	}
+	if window > 0 {
+		since := time.Now().Add(-window).UTC()
+		resp.Since = &since
+		outcomes = outcome.Since(outcomes, since)
+	}
+	resp.Groups = outcome.Aggregate(outcomes, resp.By)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// parseWindow parses a Go duration, also accepting whole days ("7d").
+func parseWindow(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, strconv.ErrSyntax
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, strconv.ErrSyntax
+	}
+	return d, nil
+}
@@ -0,0 +1,70 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/outcome"
+)
+
+func TestStatsHandler(t *testing.T) {
+	now := time.Now()
+	handler := NewStatsHandler(func() ([]*outcome.Outcome, error) {
+		return []*outcome.Outcome{
+			{Bead: "a", Rig: "gastown", Model: "claude", Merged: true, AssignedAt: now.Add(-time.Hour)},
+			{Bead: "b", Rig: "gastown", Model: "codex", AssignedAt: now.Add(-time.Hour)},
+			{Bead: "c", Rig: "beads", Model: "claude", Merged: true, AssignedAt: now.Add(-72 * time.Hour)},
+		}, nil
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		query  string
+		want   int
+		groups map[string]int // key -> assignments
+	}{
+		{"total", "GET", "", http.StatusOK, map[string]int{"total": 3}},
+		{"by model", "GET", "?by=model", http.StatusOK, map[string]int{"claude": 2, "codex": 1}},
+		{"rig and since", "GET", "?by=rig&rig=gastown&since=1d", http.StatusOK, map[string]int{"gastown": 2}},
+		{"bad by", "GET", "?by=color", http.StatusBadRequest, nil},
+		{"bad since", "GET", "?since=soon", http.StatusBadRequest, nil},
+		{"wrong method", "POST", "", http.StatusMethodNotAllowed, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, "/api/stats"+tt.query, nil))
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			if tt.groups == nil {
+				return
+			}
+			var resp struct {
+				Groups []struct {
+					Key         string `json:"key"`
+					Assignments int    `json:"assignments"`
+				} `json:"groups"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string]int)
+			for _, g := range resp.Groups {
+				got[g.Key] = g.Assignments
+			}
+			if len(got) != len(tt.groups) {
+				t.Fatalf("groups = %v, want %v", got, tt.groups)
+			}
+			for k, n := range tt.groups {
+				if got[k] != n {
+					t.Errorf("groups = %v, want %v", got, tt.groups)
+				}
+			}
+		})
+	}
+}
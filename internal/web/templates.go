@@ -17,6 +17,19 @@ type ConvoyData struct {
 	Convoys    []ConvoyRow
 	MergeQueue []MergeQueueRow
 	Polecats   []PolecatRow
+	Towns      []TownRow
+}
+
+// TownRow represents a federated town in the dashboard.
+type TownRow struct {
+	Name       string // Town name
+	URL        string // Remote dashboard URL (empty for this town)
+	Workers    int
+	MaxWorkers int // 0 means unlimited
+	Sessions   int
+	CostToday  float64
+	Error      string // Why the town could not be reached
+	ColorClass string // "mq-green" (healthy), "mq-yellow" (degraded), "mq-red" (unreachable)
 }
 
 // PolecatRow represents a polecat worker in the dashboard.
@@ -26,6 +39,8 @@ type PolecatRow struct {
 	SessionID    string        // e.g., "gt-roxas-dag"
 	LastActivity activity.Info // Colored activity display
 	StatusHint   string        // Last line from pane (optional)
+	GitStatus    string        // Worktree summary, e.g. "2 unstaged, ahead 1" (optional)
+	Changes      string        // Diff vs the default branch, e.g. "3 files, +120 -4" (optional)
 }
 
 // MergeQueueRow represents a PR in the merge queue.
@@ -0,0 +1,93 @@
+package web
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/forge"
+	"github.com/steveyegge/gastown/internal/logging"
+	"github.com/steveyegge/gastown/internal/secret"
+)
+
+// maxWebhookBody bounds the size of a webhook delivery.
+const maxWebhookBody = 1 << 20
+
+// WebhookResolver returns the forge and webhook secret for a rig.
+// An empty secret means the rig does not accept webhooks.
+type WebhookResolver func(rig string) (forge.Forge, string, error)
+
+// WebhookNotifier delivers a verified forge event for a rig.
+type WebhookNotifier func(rig string, ev *forge.Event) error
+
+// WebhookHandler receives forge webhooks at POST /webhooks/<rig>.
+// Deliveries are verified with the rig's secret before being passed on.
+type WebhookHandler struct {
+	resolve WebhookResolver
+	notify  WebhookNotifier
+}
+
+// NewWebhookHandler creates a webhook handler.
+func NewWebhookHandler(resolve WebhookResolver, notify WebhookNotifier) *WebhookHandler {
+	return &WebhookHandler{resolve: resolve, notify: notify}
+}
+
+// ServeHTTP handles a webhook delivery.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	rigName := strings.Trim(strings.TrimPrefix(r.URL.Path, "/webhooks/"), "/")
+	if rigName == "" || strings.Contains(rigName, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	f, secret, err := h.resolve(rigName)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	if secret == "" {
+		http.Error(w, "Webhooks not configured for rig", http.StatusForbidden)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "Failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	ev, err := f.ParseWebhook(r.Header, body, secret)
+	switch {
+	case errors.Is(err, forge.ErrWebhookSignature):
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	case errors.Is(err, forge.ErrUnsupportedEvent):
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case err != nil:
+		logging.FromContext(r.Context(), logging.ComponentAPI).Info("invalid webhook payload", "rig", rigName, logging.KeyError, err)
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	redactEvent(ev)
+	if err := h.notify(rigName, ev); err != nil {
+		logging.FromContext(r.Context(), logging.ComponentAPI).Error("delivering webhook event", "rig", rigName, logging.KeyError, err)
+		http.Error(w, "Failed to deliver event", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// redactEvent scrubs credentials from a webhook event's free-form fields
+// (e.g., a token embedded in a URL) before it reaches mail and the feed.
+func redactEvent(ev *forge.Event) {
+	ev.Branch = secret.Redact(ev.Branch)
+	ev.URL = secret.Redact(ev.URL)
+}
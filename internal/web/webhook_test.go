@@ -0,0 +1,66 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/forge"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	gitlab, err := forge.New(forge.GitLab, forge.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolve := func(rig string) (forge.Forge, string, error) {
+		switch rig {
+		case "gastown":
+			return gitlab, "s3cret", nil
+		case "nosecret":
+			return gitlab, "", nil
+		default:
+			return nil, "", errors.New("rig not found")
+		}
+	}
+	var got []*forge.Event
+	handler := NewWebhookHandler(resolve, func(rig string, ev *forge.Event) error {
+		got = append(got, ev)
+		return nil
+	})
+
+	push := `{"ref":"refs/heads/main","project":{"path_with_namespace":"team/repo"}}`
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		event  string
+		token  string
+		want   int
+	}{
+		{"delivered", "POST", "/webhooks/gastown", "Push Hook", "s3cret", http.StatusAccepted},
+		{"bad token", "POST", "/webhooks/gastown", "Push Hook", "wrong", http.StatusUnauthorized},
+		{"unsupported event", "POST", "/webhooks/gastown", "Wiki Page Hook", "s3cret", http.StatusNoContent},
+		{"no secret configured", "POST", "/webhooks/nosecret", "Push Hook", "", http.StatusForbidden},
+		{"unknown rig", "POST", "/webhooks/nope", "Push Hook", "s3cret", http.StatusNotFound},
+		{"wrong method", "GET", "/webhooks/gastown", "", "", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(push))
+			req.Header.Set("X-Gitlab-Event", tt.event)
+			req.Header.Set("X-Gitlab-Token", tt.token)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+
+	if len(got) != 1 || got[0].Type != forge.EventPush || got[0].Branch != "main" {
+		t.Errorf("delivered events = %+v, want one push to main", got)
+	}
+}
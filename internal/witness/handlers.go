@@ -12,6 +12,7 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -322,13 +323,7 @@ func HandleMergeFailed(workDir, rigName string, msg *mail.Message, router *mail.
 
 	// Notify the polecat about the failure
 	polecatAddr := fmt.Sprintf("%s/polecats/%s", rigName, payload.PolecatName)
-	notification := &mail.Message{
-		From:     fmt.Sprintf("%s/witness", rigName),
-		To:       polecatAddr,
-		Subject:  fmt.Sprintf("Merge failed: %s", payload.FailureType),
-		Priority: mail.PriorityHigh,
-		Type:     mail.TypeTask,
-		Body: fmt.Sprintf(`Your merge request was rejected.
+	body := fmt.Sprintf(`Your merge request was rejected.
 
 Branch: %s
 Issue: %s
@@ -336,11 +331,21 @@ Failure: %s
 Error: %s
 
 Please fix the issue and resubmit with 'gt done'.`,
-			payload.Branch,
-			payload.IssueID,
-			payload.FailureType,
-			payload.Error,
-		),
+		payload.Branch,
+		payload.IssueID,
+		payload.FailureType,
+		payload.Error,
+	)
+	if payload.Report != "" {
+		body += "\n\n" + payload.Report
+	}
+	notification := &mail.Message{
+		From:     fmt.Sprintf("%s/witness", rigName),
+		To:       polecatAddr,
+		Subject:  fmt.Sprintf("Merge failed: %s", payload.FailureType),
+		Priority: mail.PriorityHigh,
+		Type:     mail.TypeTask,
+		Body:     body,
 	}
 
 	if err := router.Send(notification); err != nil {
@@ -652,7 +657,7 @@ func NukePolecat(workDir, rigName, polecatName string) error {
 	// We do this explicitly here because gt polecat nuke may fail to kill the
 	// session due to rig loading issues or race conditions with IsRunning checks.
 	// See: gt-g9ft5 - sessions were piling up because nuke wasn't killing them.
-	sessionName := fmt.Sprintf("gt-%s-%s", rigName, polecatName)
+	sessionName := session.PolecatSessionName(rigName, polecatName)
 	t := tmux.NewTmux()
 
 	// Check if session exists and kill it
@@ -8,9 +8,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/account"
 	"github.com/steveyegge/gastown/internal/agent"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/concurrency"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -63,7 +65,7 @@ func (m *Manager) saveState(w *Witness) error {
 
 // SessionName returns the tmux session name for this witness.
 func (m *Manager) SessionName() string {
-	return fmt.Sprintf("gt-%s-witness", m.rig.Name)
+	return session.WitnessSessionName(m.rig.Name)
 }
 
 // Status returns the current witness status.
@@ -143,6 +145,11 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 
 	// Note: No PID check per ZFC - tmux session is the source of truth
 
+	// Respect the town's concurrent session limits
+	if err := concurrency.Check(filepath.Dir(m.rig.Path), t, constants.RoleWitness, m.rig.Name); err != nil {
+		return err
+	}
+
 	// Working directory
 	witnessDir := m.witnessDir()
 
@@ -168,6 +175,10 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 	if err != nil {
 		return err
 	}
+	command, err = account.PrepareSession(townRoot, account.Selection{Role: constants.RoleWitness, Rig: m.rig.Name}, sessionID, command)
+	if err != nil {
+		return fmt.Errorf("resolving account: %w", err)
+	}
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
@@ -81,6 +81,7 @@ type MergeFailedPayload struct {
 	IssueID     string
 	FailureType string // "build", "test", "lint", etc.
 	Error       string
+	Report      string // Optional multi-line failure report (e.g., failed checks)
 	FailedAt    time.Time
 }
 
@@ -242,9 +243,14 @@ func ParseMergeFailed(subject, body string) (*MergeFailedPayload, error) {
 		FailedAt:    time.Now(),
 	}
 
-	// Parse body for structured fields
-	for _, line := range strings.Split(body, "\n") {
+	// Parse body for structured fields; everything after "Report:" is the report
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
 		line = strings.TrimSpace(line)
+		if line == "Report:" {
+			payload.Report = strings.TrimSpace(strings.Join(lines[i+1:], "\n"))
+			break
+		}
 		switch {
 		case strings.HasPrefix(line, "Branch:"):
 			payload.Branch = strings.TrimSpace(strings.TrimPrefix(line, "Branch:"))
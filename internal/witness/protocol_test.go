@@ -1,6 +1,7 @@
 package witness
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -191,6 +192,30 @@ Error: unit tests failed with 3 errors`
 	}
 }
 
+func TestParseMergeFailed_WithReport(t *testing.T) {
+	subject := "MERGE_FAILED nux"
+	body := `Branch: feature-nux
+Issue: gt-abc123
+Error: pre-merge checks failed: lint
+Report:
+Pre-merge checks failed for feature-nux.
+
+## lint [command] FAILED
+Error: unused import`
+
+	payload, err := ParseMergeFailed(subject, body)
+	if err != nil {
+		t.Fatalf("ParseMergeFailed() error = %v", err)
+	}
+
+	if payload.Error != "pre-merge checks failed: lint" {
+		t.Errorf("Error = %q, want header error line", payload.Error)
+	}
+	if !strings.HasPrefix(payload.Report, "Pre-merge checks failed") || !strings.Contains(payload.Report, "Error: unused import") {
+		t.Errorf("Report = %q, want full report", payload.Report)
+	}
+}
+
 func TestParseMergeFailed_MinimalBody(t *testing.T) {
 	subject := "MERGE_FAILED ace"
 	body := "FailureType: build"
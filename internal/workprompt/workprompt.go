@@ -0,0 +1,152 @@
+// Package workprompt renders a bead into a polecat's start prompt.
+//
+// Rigs configure prompt templates and tool grants per bead type in
+// settings/config.json ("prompts"). When work is slung to a polecat, the
+// matching template is rendered with the bead's fields and sent as the start
+// prompt, and the tool grants are passed to the agent at startup. Without a
+// template the default start prompt is used.
+package workprompt
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Work is the data available to prompt templates.
+type Work struct {
+	// Bead fields
+	ID          string
+	Title       string
+	Description string
+	Type        string
+	Priority    int
+	Labels      []string
+	Files       []string // linked files (see LinkedFiles)
+
+	// Assignment
+	Rig     string
+	Polecat string
+	Subject string // gt sling --subject
+	Args    string // gt sling --args
+}
+
+// Prompt is a rendered start prompt with the tool grants that go with it.
+type Prompt struct {
+	// Text is the rendered prompt. Empty if the entry only grants tools,
+	// in which case the default start prompt is used.
+	Text string
+
+	AllowedTools    []string
+	DisallowedTools []string
+}
+
+// Select returns the prompt entry for a bead type: the type's own entry, or
+// the default entry, or nil if neither is configured.
+func Select(prompts map[string]*config.WorkPromptConfig, beadType string) *config.WorkPromptConfig {
+	if p := prompts[beadType]; p != nil && beadType != "" {
+		return p
+	}
+	return prompts[config.DefaultPromptKey]
+}
+
+// Build renders the rig's prompt for work. settingsDir is the rig's
+// settings/ directory, which template_file paths are relative to. Returns
+// nil if the rig has no prompt entry for the bead's type.
+func Build(settings *config.RigSettings, settingsDir string, work Work) (*Prompt, error) {
+	if settings == nil {
+		return nil, nil
+	}
+	entry := Select(settings.Prompts, work.Type)
+	if entry == nil {
+		return nil, nil
+	}
+
+	text := entry.Template
+	if entry.TemplateFile != "" {
+		data, err := os.ReadFile(filepath.Join(settingsDir, entry.TemplateFile))
+		if err != nil {
+			return nil, fmt.Errorf("reading prompt template: %w", err)
+		}
+		text = string(data)
+	}
+
+	p := &Prompt{
+		AllowedTools:    entry.AllowedTools,
+		DisallowedTools: entry.DisallowedTools,
+	}
+	if text != "" {
+		rendered, err := Render(text, work)
+		if err != nil {
+			return nil, err
+		}
+		p.Text = rendered
+	}
+	return p, nil
+}
+
+// Render executes a prompt template over work. Besides the Work fields,
+// templates can use {{join .Labels ", "}} and {{bullets .Files}}.
+func Render(text string, work Work) (string, error) {
+	tmpl, err := template.New("prompt").Funcs(template.FuncMap{
+		"join":    strings.Join,
+		"bullets": bullets,
+	}).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing prompt template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, work); err != nil {
+		return "", fmt.Errorf("rendering prompt template: %w", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// LinkedFiles returns the files linked to a bead: labels of the form
+// "file:<path>" and comma-separated "Files:" lines in the description.
+func LinkedFiles(description string, labels []string) []string {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(f string) {
+		f = strings.Trim(strings.TrimSpace(f), "`")
+		if f != "" && !seen[f] {
+			seen[f] = true
+			files = append(files, f)
+		}
+	}
+	for _, label := range labels {
+		if f, ok := strings.CutPrefix(label, "file:"); ok {
+			add(f)
+		}
+	}
+	for _, line := range strings.Split(description, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "file", "files":
+			for _, f := range strings.Split(value, ",") {
+				add(f)
+			}
+		}
+	}
+	return files
+}
+
+// bullets formats items as a markdown list.
+func bullets(items []string) string {
+	var b strings.Builder
+	for i, item := range items {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("- " + item)
+	}
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package workprompt
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestBuild(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "prompts"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	bugTmpl := "Fix bug {{.ID}}: {{.Title}}\n\nFiles:\n{{bullets .Files}}\n"
+	if err := os.WriteFile(filepath.Join(dir, "prompts", "bug.md.tmpl"), []byte(bugTmpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	settings := &config.RigSettings{Prompts: map[string]*config.WorkPromptConfig{
+		"bug":     {TemplateFile: "prompts/bug.md.tmpl", DisallowedTools: []string{"WebFetch"}},
+		"chore":   {AllowedTools: []string{"Bash(make:*)"}},
+		"default": {Template: "Work on {{.ID}} in {{.Rig}} as {{.Polecat}} ({{join .Labels \", \"}})."},
+	}}
+	work := Work{ID: "gt-1", Title: "Crash on start", Type: "bug", Rig: "gastown", Polecat: "nux", Files: []string{"main.go", "cmd/root.go"}}
+
+	p, err := Build(settings, dir, work)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Fix bug gt-1: Crash on start\n\nFiles:\n- main.go\n- cmd/root.go"
+	if p.Text != want {
+		t.Errorf("bug prompt = %q, want %q", p.Text, want)
+	}
+	if !reflect.DeepEqual(p.DisallowedTools, []string{"WebFetch"}) {
+		t.Errorf("DisallowedTools = %v", p.DisallowedTools)
+	}
+
+	work.Type = "feature"
+	work.Labels = []string{"ui", "p1"}
+	if p, err = Build(settings, dir, work); err != nil || p.Text != "Work on gt-1 in gastown as nux (ui, p1)." {
+		t.Errorf("default prompt = %+v, %v", p, err)
+	}
+
+	// A tools-only entry keeps the default start prompt
+	work.Type = "chore"
+	if p, err = Build(settings, dir, work); err != nil || p.Text != "" || len(p.AllowedTools) != 1 {
+		t.Errorf("chore prompt = %+v, %v", p, err)
+	}
+
+	if p, err = Build(&config.RigSettings{}, dir, work); err != nil || p != nil {
+		t.Errorf("Build without prompts = %+v, %v, want nil", p, err)
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	if _, err := Render("{{.Nope}}", Work{}); err == nil {
+		t.Error("expected error for unknown field")
+	}
+	if _, err := Render("{{.ID", Work{}); err == nil {
+		t.Error("expected error for malformed template")
+	}
+}
+
+func TestLinkedFiles(t *testing.T) {
+	desc := "The parser panics.\n\nFiles: internal/parse.go, `internal/lex.go`\nfile: internal/parse.go\nSee: docs"
+	got := LinkedFiles(desc, []string{"bug", "file:docs/grammar.md"})
+	want := []string{"docs/grammar.md", "internal/parse.go", "internal/lex.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LinkedFiles = %v, want %v", got, want)
+	}
+}